@@ -16,9 +16,11 @@ package api
 import (
 	"net/http/pprof"
 
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/node"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pingcap/ticdc/api/middleware"
 	v2 "github.com/pingcap/ticdc/api/v2"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -34,8 +36,9 @@ func RegisterRoutes(
 	// Open API V2
 	v2.RegisterOpenAPIV2Routes(router, v2.NewOpenAPIV2(server))
 
-	// pprof debug API
+	// pprof debug API, only the admins can access it if the open api requires authentication
 	pprofGroup := router.Group("/debug/pprof/")
+	pprofGroup.Use(middleware.AuthenticateMiddleware(server), middleware.AuthorizeMiddleware(config.APIRoleAdmin))
 	pprofGroup.GET("", gin.WrapF(pprof.Index))
 	pprofGroup.GET("/:any", gin.WrapF(pprof.Index))
 	pprofGroup.GET("/cmdline", gin.WrapF(pprof.Cmdline))
@@ -44,7 +47,7 @@ func RegisterRoutes(
 	pprofGroup.GET("/trace", gin.WrapF(pprof.Trace))
 	pprofGroup.GET("/threadcreate", gin.WrapF(pprof.Handler("threadcreate").ServeHTTP))
 
-	// Promtheus metrics API
+	// Promtheus metrics API, it's not authenticated since it's scraped by the monitoring system
 	prometheus.DefaultGatherer = registry
 	router.Any("/metrics", gin.WrapH(promhttp.Handler()))
 }
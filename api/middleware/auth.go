@@ -0,0 +1,213 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/logservice/upstream"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/node"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"go.uber.org/zap"
+)
+
+const (
+	// forwardRole is a header to carry the role of the authenticated identity when
+	// forwarding requests, it's trusted only if the request comes from another node.
+	forwardRole = "TiCDC-Forward-Role"
+
+	// identityKey and roleKey are the keys of the authenticated identity and
+	// its role in the gin context.
+	identityKey = "ticdc-api-identity"
+	roleKey     = "ticdc-api-role"
+
+	bearerPrefix = "Bearer "
+)
+
+// AuthenticateMiddleware authenticates the requests if the open api requires authentication,
+// the identity can be a static bearer token, a TiDB user verified by basic auth,
+// or the common name of a client certificate verified by mtls.
+// The role of the identity is stored in the context and checked by AuthorizeMiddleware.
+func AuthenticateMiddleware(server node.Server) gin.HandlerFunc {
+	selfCN, err := getSelfCommonName(config.GetGlobalServerConfig().Security)
+	if err != nil {
+		log.Warn("failed to get the common name of the server certificate, "+
+			"requests forwarded by other nodes are authenticated again", zap.Error(err))
+	}
+	return func(ctx *gin.Context) {
+		serverCfg := config.GetGlobalServerConfig()
+		if !authRequired(serverCfg) {
+			ctx.Set(roleKey, config.APIRoleAdmin)
+			ctx.Next()
+			return
+		}
+
+		identity, role, err := authenticate(ctx, server, serverCfg, selfCN)
+		if err != nil {
+			ctx.IndentedJSON(http.StatusUnauthorized, model.NewHTTPError(err))
+			ctx.Abort()
+			return
+		}
+		ctx.Set(identityKey, identity)
+		ctx.Set(roleKey, role)
+		// overwrite the header sent by the client, the node which receives the
+		// forwarded request trusts the role set by this node.
+		ctx.Request.Header.Set(forwardRole, role)
+		ctx.Next()
+	}
+}
+
+// AuthorizeMiddleware rejects the requests whose identity does not have the given role,
+// it should be used after AuthenticateMiddleware.
+func AuthorizeMiddleware(role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetString(roleKey) != role {
+			identity := ctx.GetString(identityKey)
+			err := errors.ErrUnauthorized.GenWithStackByArgs(identity,
+				"the role "+role+" is required to call this api")
+			ctx.IndentedJSON(http.StatusForbidden, model.NewHTTPError(err))
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// authRequired returns true if any authentication method is configured.
+func authRequired(serverCfg *config.ServerConfig) bool {
+	if serverCfg.Security != nil && serverCfg.Security.ClientUserRequired {
+		return true
+	}
+	return serverCfg.APIAuth != nil && serverCfg.APIAuth.Enabled()
+}
+
+// authenticate returns the identity of the request and its role.
+func authenticate(
+	ctx *gin.Context, server node.Server, serverCfg *config.ServerConfig, selfCN string,
+) (string, string, error) {
+	req := ctx.Request
+	cn := peerCommonName(req)
+	// The request is forwarded by another node which has authenticated it.
+	if role := req.Header.Get(forwardRole); role != "" && selfCN != "" && cn == selfCN {
+		if role != config.APIRoleReadOnly && role != config.APIRoleAdmin {
+			return "", "", errors.ErrUnauthorized.GenWithStackByArgs(cn, "unknown forwarded role "+role)
+		}
+		return req.Header.Get(forwardFrom), role, nil
+	}
+
+	authCfg := serverCfg.APIAuth
+	if authCfg == nil {
+		authCfg = config.NewDefaultAPIAuthConfig()
+	}
+	if auth := req.Header.Get("Authorization"); strings.HasPrefix(auth, bearerPrefix) {
+		token := strings.TrimPrefix(auth, bearerPrefix)
+		for _, t := range authCfg.Tokens {
+			if subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+				return "token:" + t.Name, t.Role, nil
+			}
+		}
+		return "", "", errors.ErrUnauthorized.GenWithStackByArgs("", "invalid bearer token")
+	}
+
+	if username, password, ok := req.BasicAuth(); ok && username != "" &&
+		serverCfg.Security != nil && serverCfg.Security.ClientUserRequired {
+		if err := verifyUser(ctx, server, serverCfg.Security, username, password); err != nil {
+			return "", "", err
+		}
+		return "user:" + username, userRole(authCfg, username), nil
+	}
+
+	if cn != "" {
+		if role, ok := authCfg.CertRoles[cn]; ok {
+			return "cert:" + cn, role, nil
+		}
+		return "", "", errors.ErrUnauthorized.GenWithStackByArgs(cn, "the certificate is not allowed")
+	}
+
+	errMsg := "please specify the user and password via authorization header"
+	if authCfg.Enabled() {
+		errMsg = "please specify a bearer token, a user and password or a client certificate"
+	}
+	return "", "", errors.ErrCredentialNotFound.GenWithStackByArgs(errMsg)
+}
+
+// userRole returns the role of the user authenticated by basic auth,
+// the users not listed in the user roles are read-only.
+func userRole(authCfg *config.APIAuthConfig, username string) string {
+	if role, ok := authCfg.UserRoles[username]; ok {
+		return role
+	}
+	return config.APIRoleReadOnly
+}
+
+// verifyUser checks the user is allowed and verifies the password by the upstream TiDB.
+func verifyUser(
+	ctx *gin.Context, server node.Server, credential *security.Credential, username, password string,
+) error {
+	allowed := false
+	for _, user := range credential.ClientAllowedUser {
+		if user == username {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errors.ErrUnauthorized.GenWithStackByArgs(username, "The user is not allowed.")
+	}
+	etcdCli := server.GetEtcdClient().GetEtcdClient().Unwrap()
+	if err := upstream.VerifyTiDBUser(ctx.Request.Context(), etcdCli, username, password); err != nil {
+		return errors.ErrUnauthorized.GenWithStackByArgs(username, err.Error())
+	}
+	return nil
+}
+
+// peerCommonName returns the common name of the client certificate verified by mtls.
+func peerCommonName(req *http.Request) string {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+	return req.TLS.PeerCertificates[0].Subject.CommonName
+}
+
+// getSelfCommonName returns the common name of the server certificate,
+// which is also used as the client certificate when forwarding requests.
+func getSelfCommonName(credential *security.Credential) (string, error) {
+	if credential == nil || credential.CertPath == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(credential.CertPath)
+	if err != nil {
+		return "", errors.WrapError(errors.ErrToTLSConfigFailed, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", errors.ErrToTLSConfigFailed.GenWithStack("failed to decode PEM block to certificate")
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", errors.WrapError(errors.ErrToTLSConfigFailed, err)
+	}
+	return certificate.Subject.CommonName, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateWithToken(t *testing.T) {
+	origin := config.GetGlobalServerConfig()
+	defer config.StoreGlobalServerConfig(origin)
+
+	gin.SetMode(gin.TestMode)
+	newRouter := func() *gin.Engine {
+		router := gin.New()
+		group := router.Group("/api/v2", AuthenticateMiddleware(nil))
+		ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+		group.GET("/changefeeds", ok)
+		group.POST("/changefeeds", AuthorizeMiddleware(config.APIRoleAdmin), ok)
+		return router
+	}
+	do := func(router *gin.Engine, method, token string, header map[string]string) int {
+		req := httptest.NewRequest(method, "/api/v2/changefeeds", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// no authentication is required by default
+	router := newRouter()
+	require.Equal(t, http.StatusOK, do(router, http.MethodPost, "", nil))
+
+	cfg := config.GetDefaultServerConfig()
+	cfg.APIAuth.Tokens = []*config.APITokenConfig{
+		{Name: "viewer", Token: "read-token", Role: config.APIRoleReadOnly},
+		{Name: "operator", Token: "admin-token", Role: config.APIRoleAdmin},
+	}
+	require.NoError(t, cfg.ValidateAndAdjust())
+	config.StoreGlobalServerConfig(cfg)
+	router = newRouter()
+
+	require.Equal(t, http.StatusUnauthorized, do(router, http.MethodGet, "", nil))
+	require.Equal(t, http.StatusUnauthorized, do(router, http.MethodGet, "invalid", nil))
+	require.Equal(t, http.StatusOK, do(router, http.MethodGet, "read-token", nil))
+	require.Equal(t, http.StatusForbidden, do(router, http.MethodPost, "read-token", nil))
+	require.Equal(t, http.StatusOK, do(router, http.MethodGet, "admin-token", nil))
+	require.Equal(t, http.StatusOK, do(router, http.MethodPost, "admin-token", nil))
+	// the forwarded role is ignored if the request does not come from another node
+	require.Equal(t, http.StatusUnauthorized,
+		do(router, http.MethodPost, "", map[string]string{forwardRole: config.APIRoleAdmin}))
+	require.Equal(t, http.StatusForbidden,
+		do(router, http.MethodPost, "read-token", map[string]string{forwardRole: config.APIRoleAdmin}))
+
+	// the role of the token must be valid
+	cfg.APIAuth.Tokens[0].Role = "root"
+	require.Error(t, cfg.ValidateAndAdjust())
+}
+
+func TestUserRole(t *testing.T) {
+	authCfg := config.NewDefaultAPIAuthConfig()
+	// the users not listed in the user roles are read-only
+	require.Equal(t, config.APIRoleReadOnly, userRole(authCfg, "ticdc"))
+
+	authCfg.UserRoles = map[string]string{"ticdc": config.APIRoleAdmin}
+	require.Equal(t, config.APIRoleAdmin, userRole(authCfg, "ticdc"))
+	require.Equal(t, config.APIRoleReadOnly, userRole(authCfg, "other"))
+}
@@ -16,6 +16,7 @@ package v2
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/ticdc/api/middleware"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/node"
 )
 
@@ -36,33 +37,53 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	v2.Use(middleware.LogMiddleware())
 	v2.Use(middleware.ErrorHandleMiddleware())
 
+	coordinatorMiddleware := middleware.ForwardToCoordinatorMiddleware(api.server)
+	// authenticateMiddleware must be placed before coordinatorMiddleware, so the
+	// request is authenticated by the node which receives it from the client.
+	authenticateMiddleware := middleware.AuthenticateMiddleware(api.server)
+	// adminMiddleware protects the apis which mutate changefeeds.
+	adminMiddleware := middleware.AuthorizeMiddleware(config.APIRoleAdmin)
+
+	// The status apis are not authenticated, since they are used by the health checks.
 	v2.GET("status", api.serverStatus)
 	// For compatibility with the old API.
 	// TiDB Operator relies on this API to determine whether the TiCDC node is healthy.
 	router.GET("/status", api.serverStatus)
 	// Intergration test relies on this API to determine whether the TiCDC node is healthy.
-	router.GET("/debug/info", gin.WrapF(api.handleDebugInfo))
-
-	coordinatorMiddleware := middleware.ForwardToCoordinatorMiddleware(api.server)
+	router.GET("/debug/info", authenticateMiddleware, gin.WrapF(api.handleDebugInfo))
 
 	// changefeed apis
 	changefeedGroup := v2.Group("/changefeeds")
+	changefeedGroup.Use(authenticateMiddleware)
 	changefeedGroup.GET("/:changefeed_id", coordinatorMiddleware, api.getChangeFeed)
-	changefeedGroup.POST("", coordinatorMiddleware, api.createChangefeed)
+	changefeedGroup.POST("", adminMiddleware, coordinatorMiddleware, api.createChangefeed)
 	changefeedGroup.GET("", coordinatorMiddleware, api.listChangeFeeds)
-	changefeedGroup.PUT("/:changefeed_id", coordinatorMiddleware, api.updateChangefeed)
-	changefeedGroup.POST("/:changefeed_id/resume", coordinatorMiddleware, api.resumeChangefeed)
-	changefeedGroup.POST("/:changefeed_id/pause", coordinatorMiddleware, api.pauseChangefeed)
-	changefeedGroup.DELETE("/:changefeed_id", coordinatorMiddleware, api.deleteChangefeed)
+	changefeedGroup.PUT("/:changefeed_id", adminMiddleware, coordinatorMiddleware, api.updateChangefeed)
+	changefeedGroup.POST("/:changefeed_id/resume", adminMiddleware, coordinatorMiddleware, api.resumeChangefeed)
+	changefeedGroup.POST("/:changefeed_id/pause", adminMiddleware, coordinatorMiddleware, api.pauseChangefeed)
+	changefeedGroup.DELETE("/:changefeed_id", adminMiddleware, coordinatorMiddleware, api.deleteChangefeed)
+	changefeedGroup.GET("/:changefeed_id/tables", coordinatorMiddleware, api.listTables)
+	changefeedGroup.POST("/:changefeed_id/tables/:table_id/move", adminMiddleware, coordinatorMiddleware, api.moveTable)
+	changefeedGroup.POST("/:changefeed_id/tables/:table_id/split", adminMiddleware, coordinatorMiddleware, api.splitTable)
+	changefeedGroup.GET("/:changefeed_id/pending_ddl", coordinatorMiddleware, api.listPendingDDLs)
+	changefeedGroup.POST("/:changefeed_id/pending_ddl/:block_ts/approve", adminMiddleware, coordinatorMiddleware, api.approvePendingDDL)
+	changefeedGroup.POST("/:changefeed_id/pending_ddl/:block_ts/skip", adminMiddleware, coordinatorMiddleware, api.skipPendingDDL)
 
 	// capture apis
 	captureGroup := v2.Group("/captures")
+	captureGroup.Use(authenticateMiddleware)
 	captureGroup.Use(coordinatorMiddleware)
 	captureGroup.GET("", api.listCaptures)
 
 	verifyTableGroup := v2.Group("/verify_table")
+	verifyTableGroup.Use(authenticateMiddleware)
 	verifyTableGroup.POST("", api.verifyTable)
 
+	// sink apis
+	sinkGroup := v2.Group("/sinks")
+	sinkGroup.Use(authenticateMiddleware)
+	sinkGroup.GET("/schemes", api.listSinkSchemes)
+
 	// common APIs
-	v2.POST("/tso", api.QueryTso)
+	v2.POST("/tso", authenticateMiddleware, api.QueryTso)
 }
@@ -271,6 +271,7 @@ func (h *OpenAPIV2) getChangeFeed(c *gin.Context) {
 	taskStatus := make([]model.CaptureTaskStatus, 0)
 	detail := toAPIModel(cfInfo, status.CheckpointTs,
 		status.CheckpointTs, taskStatus)
+	detail.DeadLetterRows = status.DeadLetterRows
 	c.JSON(http.StatusOK, detail)
 }
 
@@ -82,6 +82,40 @@ type ResumeChangefeedConfig struct {
 	OverwriteCheckpointTs uint64 `json:"overwrite_checkpoint_ts"`
 }
 
+// TableSpanInfo holds the replication status of a table span in a changefeed
+type TableSpanInfo struct {
+	TableID      int64  `json:"table_id"`
+	SchemaID     int64  `json:"schema_id"`
+	StartKey     string `json:"start_key"`
+	EndKey       string `json:"end_key"`
+	DispatcherID string `json:"dispatcher_id"`
+	NodeID       string `json:"node_id"`
+	// State is the scheduling state of the span, absent, scheduling or replicating
+	State        string `json:"state"`
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	ResolvedTs   uint64 `json:"resolved_ts"`
+	// CheckpointLag is the lag of the checkpoint ts in seconds
+	CheckpointLag float64 `json:"checkpoint_lag"`
+	// ResolvedLag is the lag of the resolved ts in seconds, it's 0 if the resolved ts is unknown
+	ResolvedLag float64 `json:"resolved_lag"`
+	// Operator is the type of the pending operator of the span, empty if there is none
+	Operator string `json:"operator,omitempty"`
+}
+
+// MoveTableConfig is used by move table api
+type MoveTableConfig struct {
+	TargetNodeID string `json:"target_node_id"`
+}
+
+// PendingDDL is a ddl blocked by the block ddl config of a changefeed,
+// it's not executed until it's approved or skipped
+type PendingDDL struct {
+	BlockTs uint64 `json:"block_ts"`
+	Query   string `json:"query"`
+	// Ready is true if all the influenced tables have reached the ddl
+	Ready bool `json:"ready"`
+}
+
 // PDConfig is a configuration used to connect to pd
 type PDConfig struct {
 	PDAddrs       []string `json:"pd_addrs,omitempty"`
@@ -111,6 +145,29 @@ type SyncedStatusConfig struct {
 	CheckpointInterval int64 `json:"checkpoint_interval"`
 }
 
+// TransformRule represents the row level transform rule of the tables
+type TransformRule struct {
+	Matcher       []string          `json:"matcher"`
+	RenameColumns map[string]string `json:"rename_columns,omitempty"`
+	MaskColumns   []*MaskColumnRule `json:"mask_columns,omitempty"`
+	AddColumns    []*AddColumnRule  `json:"add_columns,omitempty"`
+}
+
+// MaskColumnRule represents how the value of a column is masked
+type MaskColumnRule struct {
+	Column     string `json:"column"`
+	Method     string `json:"method"`
+	KeepPrefix int    `json:"keep_prefix,omitempty"`
+	KeepSuffix int    `json:"keep_suffix,omitempty"`
+}
+
+// AddColumnRule represents a static or computed column appended to the rows
+type AddColumnRule struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	Expr  string `json:"expr,omitempty"`
+}
+
 // MarshalJSON marshal changefeed common info to json
 // we need to set feed state to normal if it is uninitialized and pending to warning
 // to hide the detail of uninitialized and pending state from user
@@ -204,6 +261,7 @@ type ReplicaConfig struct {
 	Integrity                    *IntegrityConfig           `json:"integrity"`
 	ChangefeedErrorStuckDuration *JSONDuration              `json:"changefeed_error_stuck_duration,omitempty"`
 	SyncedStatus                 *SyncedStatusConfig        `json:"synced_status,omitempty"`
+	Transforms                   []*TransformRule           `json:"transforms,omitempty"`
 
 	// Deprecated: we don't use this field since v8.0.0.
 	SQLMode string `json:"sql_mode,omitempty"`
@@ -246,6 +304,12 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			IgnoreTxnStartTs: c.Filter.IgnoreTxnStartTs,
 			EventFilters:     efs,
 		}
+		if len(c.Filter.BlockDDL) != 0 {
+			res.Filter.BlockDDL = make([]bf.EventType, len(c.Filter.BlockDDL))
+			for i, et := range c.Filter.BlockDDL {
+				res.Filter.BlockDDL[i] = bf.EventType(et)
+			}
+		}
 	}
 	if c.Consistent != nil {
 		res.Consistent = &config.ConsistentConfig{
@@ -426,6 +490,13 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				EnableMultiStatement:         c.Sink.MySQLConfig.EnableMultiStatement,
 				EnableCachePreparedStatement: c.Sink.MySQLConfig.EnableCachePreparedStatement,
 			}
+			if c.Sink.MySQLConfig.DeadLetter != nil {
+				mysqlConfig.DeadLetter = &config.DeadLetterConfig{
+					Enable:     c.Sink.MySQLConfig.DeadLetter.Enable,
+					Table:      c.Sink.MySQLConfig.DeadLetter.Table,
+					StorageURI: c.Sink.MySQLConfig.DeadLetter.StorageURI,
+				}
+			}
 		}
 		var cloudStorageConfig *config.CloudStorageConfig
 		if c.Sink.CloudStorageConfig != nil {
@@ -513,6 +584,8 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			EnableTableAcrossNodes: c.Scheduler.EnableTableAcrossNodes,
 			RegionThreshold:        c.Scheduler.RegionThreshold,
 			WriteKeyThreshold:      c.Scheduler.WriteKeyThreshold,
+			MergeRegionThreshold:   c.Scheduler.MergeRegionThreshold,
+			MergeWriteKeyThreshold: c.Scheduler.MergeWriteKeyThreshold,
 		}
 	}
 	if c.Integrity != nil {
@@ -530,6 +603,28 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			CheckpointInterval:  c.SyncedStatus.CheckpointInterval,
 		}
 	}
+	for _, rule := range c.Transforms {
+		transform := &config.TransformRule{
+			Matcher:       rule.Matcher,
+			RenameColumns: rule.RenameColumns,
+		}
+		for _, mask := range rule.MaskColumns {
+			transform.MaskColumns = append(transform.MaskColumns, &config.MaskColumnRule{
+				Column:     mask.Column,
+				Method:     mask.Method,
+				KeepPrefix: mask.KeepPrefix,
+				KeepSuffix: mask.KeepSuffix,
+			})
+		}
+		for _, add := range rule.AddColumns {
+			transform.AddColumns = append(transform.AddColumns, &config.AddColumnRule{
+				Name:  add.Name,
+				Value: add.Value,
+				Expr:  add.Expr,
+			})
+		}
+		res.Transforms = append(res.Transforms, transform)
+	}
 	return res
 }
 
@@ -570,6 +665,12 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			IgnoreTxnStartTs: cloned.Filter.IgnoreTxnStartTs,
 			EventFilters:     efs,
 		}
+		if len(cloned.Filter.BlockDDL) != 0 {
+			res.Filter.BlockDDL = make([]string, len(cloned.Filter.BlockDDL))
+			for i, et := range cloned.Filter.BlockDDL {
+				res.Filter.BlockDDL[i] = string(et)
+			}
+		}
 	}
 	if cloned.Sink != nil {
 		var dispatchRules []*DispatchRule
@@ -697,6 +798,13 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				EnableMultiStatement:         cloned.Sink.MySQLConfig.EnableMultiStatement,
 				EnableCachePreparedStatement: cloned.Sink.MySQLConfig.EnableCachePreparedStatement,
 			}
+			if cloned.Sink.MySQLConfig.DeadLetter != nil {
+				mysqlConfig.DeadLetter = &DeadLetterConfig{
+					Enable:     cloned.Sink.MySQLConfig.DeadLetter.Enable,
+					Table:      cloned.Sink.MySQLConfig.DeadLetter.Table,
+					StorageURI: cloned.Sink.MySQLConfig.DeadLetter.StorageURI,
+				}
+			}
 		}
 		var pulsarConfig *PulsarConfig
 		if cloned.Sink.PulsarConfig != nil {
@@ -836,6 +944,8 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			EnableTableAcrossNodes: cloned.Scheduler.EnableTableAcrossNodes,
 			RegionThreshold:        cloned.Scheduler.RegionThreshold,
 			WriteKeyThreshold:      cloned.Scheduler.WriteKeyThreshold,
+			MergeRegionThreshold:   cloned.Scheduler.MergeRegionThreshold,
+			MergeWriteKeyThreshold: cloned.Scheduler.MergeWriteKeyThreshold,
 		}
 	}
 
@@ -854,6 +964,28 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			CheckpointInterval:  cloned.SyncedStatus.CheckpointInterval,
 		}
 	}
+	for _, rule := range cloned.Transforms {
+		transform := &TransformRule{
+			Matcher:       rule.Matcher,
+			RenameColumns: rule.RenameColumns,
+		}
+		for _, mask := range rule.MaskColumns {
+			transform.MaskColumns = append(transform.MaskColumns, &MaskColumnRule{
+				Column:     mask.Column,
+				Method:     mask.Method,
+				KeepPrefix: mask.KeepPrefix,
+				KeepSuffix: mask.KeepSuffix,
+			})
+		}
+		for _, add := range rule.AddColumns {
+			transform.AddColumns = append(transform.AddColumns, &AddColumnRule{
+				Name:  add.Name,
+				Value: add.Value,
+				Expr:  add.Expr,
+			})
+		}
+		res.Transforms = append(res.Transforms, transform)
+	}
 	return res
 }
 
@@ -868,6 +1000,7 @@ type FilterConfig struct {
 	Rules            []string          `json:"rules,omitempty"`
 	IgnoreTxnStartTs []uint64          `json:"ignore_txn_start_ts,omitempty"`
 	EventFilters     []EventFilterRule `json:"event_filters,omitempty"`
+	BlockDDL         []string          `json:"block_ddl,omitempty"`
 }
 
 // MounterConfig represents mounter config for a changefeed
@@ -1043,6 +1176,10 @@ type ChangefeedSchedulerConfig struct {
 	RegionThreshold int `toml:"region_threshold" json:"region_threshold"`
 	// WriteKeyThreshold is the written keys threshold of splitting a table.
 	WriteKeyThreshold int `toml:"write_key_threshold" json:"write_key_threshold"`
+	// MergeRegionThreshold is the region count threshold of merging the spans of a table.
+	MergeRegionThreshold int `toml:"merge_region_threshold" json:"merge_region_threshold"`
+	// MergeWriteKeyThreshold is the written keys threshold of merging the spans of a table.
+	MergeWriteKeyThreshold int `toml:"merge_write_key_threshold" json:"merge_write_key_threshold"`
 }
 
 // IntegrityConfig is the config for integrity check
@@ -1087,6 +1224,9 @@ type ChangeFeedInfo struct {
 	CheckpointTs   uint64                    `json:"checkpoint_ts"`
 	CheckpointTime model.JSONTime            `json:"checkpoint_time"`
 	TaskStatus     []model.CaptureTaskStatus `json:"task_status,omitempty"`
+	// DeadLetterRows is the number of rows written to the dead letter destination
+	// of the mysql sink since the changefeed is scheduled
+	DeadLetterRows uint64 `json:"dead_letter_rows,omitempty"`
 }
 
 // SyncedStatus describes the detail of a changefeed's synced status
@@ -1282,6 +1422,15 @@ type MySQLConfig struct {
 	EnableBatchDML               *bool   `json:"enable_batch_dml,omitempty"`
 	EnableMultiStatement         *bool   `json:"enable_multi_statement,omitempty"`
 	EnableCachePreparedStatement *bool   `json:"enable_cache_prepared_statement,omitempty"`
+
+	DeadLetter *DeadLetterConfig `json:"dead_letter,omitempty"`
+}
+
+// DeadLetterConfig represents the dead letter configuration of a MySQL sink
+type DeadLetterConfig struct {
+	Enable     *bool   `json:"enable,omitempty"`
+	Table      *string `json:"table,omitempty"`
+	StorageURI *string `json:"storage_uri,omitempty"`
 }
 
 // CloudStorageConfig represents a cloud storage sink configuration
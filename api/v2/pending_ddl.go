@@ -0,0 +1,145 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/tiflow/cdc/api"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/errors"
+)
+
+const apiOpVarBlockTs = "block_ts"
+
+// listPendingDDLs handles list pending ddls request
+// ListPendingDDLs lists the ddls of a changefeed which are waiting to be approved or skipped
+// @Summary List the pending ddls of a changefeed
+// @Description list the ddls blocked by the block ddl config, only the tables influenced by them are blocked
+// @Tags changefeed,v2
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "default"
+// @Success 200 {object} ListResponse[PendingDDL]
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/pending_ddl [get]
+func (h *OpenAPIV2) listPendingDDLs(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedDisplayName := common.NewChangeFeedDisplayName(c.Param(api.APIOpVarChangefeedID), model.DefaultNamespace)
+	if err := model.ValidateChangefeedID(changefeedDisplayName.Name); err != nil {
+		_ = c.Error(errors.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedDisplayName.Name))
+		return
+	}
+
+	coordinator, err := h.server.GetCoordinator()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	cfInfo, _, err := coordinator.GetChangefeed(c, changefeedDisplayName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	ddls, err := coordinator.ListPendingDDLs(ctx, cfInfo.ChangefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	items := make([]PendingDDL, 0, len(ddls))
+	for _, ddl := range ddls {
+		items = append(items, PendingDDL{
+			BlockTs: ddl.BlockTs,
+			Query:   ddl.Query,
+			Ready:   ddl.Ready,
+		})
+	}
+	c.JSON(http.StatusOK, &ListResponse[PendingDDL]{
+		Total: len(items),
+		Items: items,
+	})
+}
+
+// approvePendingDDL handles approve pending ddl request
+// ApprovePendingDDL approves a pending ddl, the ddl is executed in the downstream
+// @Summary Approve a pending ddl
+// @Description Approve a ddl which is blocked by the block ddl config of the changefeed
+// @Tags changefeed,v2
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param block_ts  path  integer  true  "block_ts"
+// @Param namespace query string false "default"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/pending_ddl/{block_ts}/approve [post]
+func (h *OpenAPIV2) approvePendingDDL(c *gin.Context) {
+	h.decidePendingDDL(c, true)
+}
+
+// skipPendingDDL handles skip pending ddl request
+// SkipPendingDDL skips a pending ddl, the ddl is not executed in the downstream
+// @Summary Skip a pending ddl
+// @Description Skip a ddl which is blocked by the block ddl config of the changefeed
+// @Tags changefeed,v2
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param block_ts  path  integer  true  "block_ts"
+// @Param namespace query string false "default"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/pending_ddl/{block_ts}/skip [post]
+func (h *OpenAPIV2) skipPendingDDL(c *gin.Context) {
+	h.decidePendingDDL(c, false)
+}
+
+func (h *OpenAPIV2) decidePendingDDL(c *gin.Context, approve bool) {
+	ctx := c.Request.Context()
+	changefeedDisplayName := common.NewChangeFeedDisplayName(c.Param(api.APIOpVarChangefeedID), model.DefaultNamespace)
+	if err := model.ValidateChangefeedID(changefeedDisplayName.Name); err != nil {
+		_ = c.Error(errors.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedDisplayName.Name))
+		return
+	}
+	blockTs, err := strconv.ParseUint(c.Param(apiOpVarBlockTs), 10, 64)
+	if err != nil || blockTs == 0 {
+		_ = c.Error(errors.ErrAPIInvalidParam.GenWithStack("invalid block_ts: %s",
+			c.Param(apiOpVarBlockTs)))
+		return
+	}
+
+	coordinator, err := h.server.GetCoordinator()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	cfInfo, _, err := coordinator.GetChangefeed(c, changefeedDisplayName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if approve {
+		err = coordinator.ApprovePendingDDL(ctx, cfInfo.ChangefeedID, blockTs)
+	} else {
+		err = coordinator.SkipPendingDDL(ctx, cfInfo.ChangefeedID, blockTs)
+	}
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
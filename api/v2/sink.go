@@ -0,0 +1,37 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/ticdc/pkg/config"
+)
+
+// listSinkSchemes lists all the supported sink schemes
+// @Summary List sink schemes
+// @Description list all the sink schemes supported by the cdc server, including the registered sinks
+// @Tags sink,v2
+// @Produce json
+// @Success 200 {array} string
+// @Router	/api/v2/sinks/schemes [get]
+func (h *OpenAPIV2) listSinkSchemes(c *gin.Context) {
+	schemes := config.SupportedSinkSchemes()
+	resp := &ListResponse[string]{
+		Total: len(schemes),
+		Items: schemes,
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/node"
+	"github.com/pingcap/tiflow/cdc/api"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+const apiOpVarTableID = "table_id"
+
+// listTables handles list tables request
+// ListTables lists the replication status of all the table spans of a changefeed
+// @Summary List the tables of a changefeed
+// @Description list the replication status of all the table spans of a changefeed, the lagging spans are in the front
+// @Tags changefeed,v2
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "default"
+// @Success 200 {object} ListResponse[TableSpanInfo]
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/tables [get]
+func (h *OpenAPIV2) listTables(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedDisplayName := common.NewChangeFeedDisplayName(c.Param(api.APIOpVarChangefeedID), model.DefaultNamespace)
+	if err := model.ValidateChangefeedID(changefeedDisplayName.Name); err != nil {
+		_ = c.Error(errors.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedDisplayName.Name))
+		return
+	}
+
+	coordinator, err := h.server.GetCoordinator()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	cfInfo, _, err := coordinator.GetChangefeed(c, changefeedDisplayName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	spans, err := coordinator.GetTableSpanStatus(ctx, cfInfo.ChangefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.writeTableSpanInfos(c, spans)
+}
+
+// writeTableSpanInfos writes the replication status of the spans with the lags to the response.
+func (h *OpenAPIV2) writeTableSpanInfos(c *gin.Context, spans []*config.TableSpanReplicationStatus) {
+	ts, _, err := h.server.GetPdClient().GetTS(c.Request.Context())
+	if err != nil {
+		_ = c.Error(errors.ErrPDEtcdAPIError.GenWithStackByArgs("fail to get ts from pd client"))
+		return
+	}
+
+	infos := make([]TableSpanInfo, 0, len(spans))
+	for _, span := range spans {
+		info := TableSpanInfo{
+			TableID:       span.TableID,
+			SchemaID:      span.SchemaID,
+			StartKey:      hex.EncodeToString(span.StartKey),
+			EndKey:        hex.EncodeToString(span.EndKey),
+			DispatcherID:  span.DispatcherID,
+			NodeID:        span.NodeID,
+			State:         span.State,
+			CheckpointTs:  span.CheckpointTs,
+			ResolvedTs:    span.ResolvedTs,
+			CheckpointLag: lagInSeconds(ts, span.CheckpointTs),
+			Operator:      span.Operator,
+		}
+		if span.ResolvedTs != 0 {
+			info.ResolvedLag = lagInSeconds(ts, span.ResolvedTs)
+		}
+		infos = append(infos, info)
+	}
+	c.JSON(http.StatusOK, &ListResponse[TableSpanInfo]{
+		Total: len(infos),
+		Items: infos,
+	})
+}
+
+// moveTable handles move table request
+// MoveTable moves all the spans of a table to the target node
+// @Summary Move a table
+// @Description Move all the spans of a table of the changefeed to the target node
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param table_id  path  integer  true  "table_id"
+// @Param namespace query string false "default"
+// @Param moveTableConfig body MoveTableConfig true "move table config"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/tables/{table_id}/move [post]
+func (h *OpenAPIV2) moveTable(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedDisplayName, tableID, ok := getTableScheduleParams(c)
+	if !ok {
+		return
+	}
+	cfg := new(MoveTableConfig)
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(errors.WrapError(errors.ErrAPIInvalidParam, err))
+		return
+	}
+	if cfg.TargetNodeID == "" {
+		_ = c.Error(errors.ErrAPIInvalidParam.GenWithStack("target_node_id is required"))
+		return
+	}
+
+	coordinator, err := h.server.GetCoordinator()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	cfInfo, _, err := coordinator.GetChangefeed(c, changefeedDisplayName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err = coordinator.MoveTable(ctx, cfInfo.ChangefeedID, tableID, node.ID(cfg.TargetNodeID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+// splitTable handles split table request
+// SplitTable splits the spans of a table
+// @Summary Split a table
+// @Description Split the spans of a table of the changefeed by the region count and the write traffic, and return the spans being split
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param table_id  path  integer  true  "table_id"
+// @Param namespace query string false "default"
+// @Success 200 {object} ListResponse[TableSpanInfo]
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/tables/{table_id}/split [post]
+func (h *OpenAPIV2) splitTable(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedDisplayName, tableID, ok := getTableScheduleParams(c)
+	if !ok {
+		return
+	}
+
+	coordinator, err := h.server.GetCoordinator()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	cfInfo, _, err := coordinator.GetChangefeed(c, changefeedDisplayName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	spans, err := coordinator.SplitTable(ctx, cfInfo.ChangefeedID, tableID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.writeTableSpanInfos(c, spans)
+}
+
+// getTableScheduleParams parses the changefeed id and the table id in the path,
+// the error is set to the context if any of them is invalid.
+func getTableScheduleParams(c *gin.Context) (common.ChangeFeedDisplayName, int64, bool) {
+	changefeedDisplayName := common.NewChangeFeedDisplayName(c.Param(api.APIOpVarChangefeedID), model.DefaultNamespace)
+	if err := model.ValidateChangefeedID(changefeedDisplayName.Name); err != nil {
+		_ = c.Error(errors.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedDisplayName.Name))
+		return changefeedDisplayName, 0, false
+	}
+	tableID, err := strconv.ParseInt(c.Param(apiOpVarTableID), 10, 64)
+	if err != nil || tableID <= 0 {
+		_ = c.Error(errors.ErrAPIInvalidParam.GenWithStack("invalid table_id: %s",
+			c.Param(apiOpVarTableID)))
+		return changefeedDisplayName, 0, false
+	}
+	return changefeedDisplayName, tableID, true
+}
+
+// lagInSeconds returns the lag between the physical time of the pd and the ts in seconds.
+func lagInSeconds(pdPhysical int64, ts uint64) float64 {
+	return float64(pdPhysical-oracle.ExtractPhysical(ts)) / 1e3
+}
@@ -34,6 +34,9 @@ func newCmdChangefeed(f factory.Factory) *cobra.Command {
 	cmds.AddCommand(newCmdQueryChangefeed(f))
 	cmds.AddCommand(newCmdRemoveChangefeed(f))
 	cmds.AddCommand(newCmdResumeChangefeed(f))
+	cmds.AddCommand(newCmdMoveTableChangefeed(f))
+	cmds.AddCommand(newCmdSplitTableChangefeed(f))
+	cmds.AddCommand(newCmdPendingDDLChangefeed(f))
 
 	return cmds
 }
@@ -24,12 +24,15 @@ import (
 	"github.com/pingcap/log"
 	v2 "github.com/pingcap/ticdc/api/v2"
 	"github.com/pingcap/ticdc/cmd/factory"
+	// register the sink schemes to validate the sink uri
+	_ "github.com/pingcap/ticdc/downstreamadapter/sink"
 	apiv2client "github.com/pingcap/ticdc/pkg/api/v2"
 	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/filter"
 	"github.com/pingcap/tiflow/cdc/model"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	putil "github.com/pingcap/tiflow/pkg/util"
 	"github.com/spf13/cobra"
 	"github.com/tikv/client-go/v2/oracle"
@@ -249,8 +252,39 @@ func (o *createChangefeedOptions) getUpstreamConfig() *v2.UpstreamConfig {
 	}
 }
 
+// validateSinkScheme checks whether the scheme of the sink uri is supported by the server,
+// the server may support more schemes than the cli if it registers sinks which are not built in.
+func (o *createChangefeedOptions) validateSinkScheme(ctx context.Context) error {
+	uri, err := url.Parse(o.commonChangefeedOptions.sinkURI)
+	if err != nil {
+		return err
+	}
+	schemes, err := o.apiClient.Sinks().ListSchemes(ctx)
+	if err != nil {
+		if errors.Cause(err) == apiv2client.ErrListSchemesNotSupported {
+			// the server is an old version, leave the validation to the server.
+			log.Warn("skip checking the sink scheme", zap.Error(err))
+			return nil
+		}
+		return err
+	}
+	scheme := strings.ToLower(uri.Scheme)
+	for _, s := range schemes {
+		if s == scheme {
+			return nil
+		}
+	}
+	return cerror.ErrSinkURIInvalid.GenWithStackByArgs(fmt.Sprintf(
+		"the sink scheme (%s) is not supported by the server, supported schemes: %s",
+		uri.Scheme, strings.Join(schemes, ", ")))
+}
+
 // run the `cli changefeed create` command.
 func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) error {
+	if err := o.validateSinkScheme(ctx); err != nil {
+		return err
+	}
+
 	tso, err := o.apiClient.Tso().Query(ctx, o.getUpstreamConfig())
 	if err != nil {
 		return err
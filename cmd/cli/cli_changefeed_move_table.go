@@ -0,0 +1,85 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/ticdc/cmd/factory"
+	apiv2client "github.com/pingcap/ticdc/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// moveTableChangefeedOptions defines flags for the `cli changefeed move-table` command.
+type moveTableChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	changefeedID string
+	namespace    string
+	tableID      int64
+	targetNodeID string
+}
+
+// newMoveTableChangefeedOptions creates new options for the `cli changefeed move-table` command.
+func newMoveTableChangefeedOptions() *moveTableChangefeedOptions {
+	return &moveTableChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *moveTableChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().Int64VarP(&o.tableID, "table-id", "t", 0, "the id of the table to move")
+	cmd.PersistentFlags().StringVarP(&o.targetNodeID, "target-node-id", "d", "", "the id of the destination node")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	_ = cmd.MarkPersistentFlagRequired("table-id")
+	_ = cmd.MarkPersistentFlagRequired("target-node-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *moveTableChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed move-table` command.
+func (o *moveTableChangefeedOptions) run() error {
+	ctx := context.GetDefaultContext()
+	return o.apiClient.Changefeeds().MoveTable(ctx, o.namespace, o.changefeedID, o.tableID, o.targetNodeID)
+}
+
+// newCmdMoveTableChangefeed creates the `cli changefeed move-table` command.
+func newCmdMoveTableChangefeed(f factory.Factory) *cobra.Command {
+	o := newMoveTableChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "move-table",
+		Short: "Move a table of the replication task (changefeed) to another node",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run())
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
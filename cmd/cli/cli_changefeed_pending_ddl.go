@@ -0,0 +1,99 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/ticdc/cmd/factory"
+	apiv2client "github.com/pingcap/ticdc/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// pendingDDLChangefeedOptions defines flags for the `cli changefeed pending-ddl` command.
+type pendingDDLChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	changefeedID string
+	namespace    string
+	approveTs    uint64
+	skipTs       uint64
+}
+
+// newPendingDDLChangefeedOptions creates new options for the `cli changefeed pending-ddl` command.
+func newPendingDDLChangefeedOptions() *pendingDDLChangefeedOptions {
+	return &pendingDDLChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *pendingDDLChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().Uint64Var(&o.approveTs, "approve", 0, "the block ts of the pending ddl to execute in the downstream")
+	cmd.PersistentFlags().Uint64Var(&o.skipTs, "skip", 0, "the block ts of the pending ddl to skip")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *pendingDDLChangefeedOptions) complete(f factory.Factory) error {
+	if o.approveTs != 0 && o.skipTs != 0 {
+		return errors.New("only one of --approve and --skip can be specified")
+	}
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed pending-ddl` command.
+func (o *pendingDDLChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.Background()
+	switch {
+	case o.approveTs != 0:
+		return o.apiClient.Changefeeds().ApprovePendingDDL(ctx, o.namespace, o.changefeedID, o.approveTs)
+	case o.skipTs != 0:
+		return o.apiClient.Changefeeds().SkipPendingDDL(ctx, o.namespace, o.changefeedID, o.skipTs)
+	default:
+	}
+	ddls, err := o.apiClient.Changefeeds().ListPendingDDLs(ctx, o.namespace, o.changefeedID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return util.JSONPrint(cmd, ddls)
+}
+
+// newCmdPendingDDLChangefeed creates the `cli changefeed pending-ddl` command.
+func newCmdPendingDDLChangefeed(f factory.Factory) *cobra.Command {
+	o := newPendingDDLChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "pending-ddl",
+		Short: "List, approve or skip the ddls blocked by the block-ddl config of a replication task (changefeed)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
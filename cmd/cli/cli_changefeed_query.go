@@ -52,6 +52,7 @@ type queryChangefeedOptions struct {
 	apiClientV2  apiv2client.APIV2Interface
 	changefeedID string
 	simplified   bool
+	tables       bool
 	namespace    string
 }
 
@@ -65,6 +66,7 @@ func newQueryChangefeedOptions() *queryChangefeedOptions {
 func (o *queryChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
 	cmd.PersistentFlags().BoolVarP(&o.simplified, "simple", "s", false, "Output simplified replication status")
+	cmd.PersistentFlags().BoolVar(&o.tables, "tables", false, "Output the replication status of all the table spans, the lagging spans are in the front")
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
 	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
@@ -82,6 +84,13 @@ func (o *queryChangefeedOptions) complete(f factory.Factory) error {
 // run the `cli changefeed query` command.
 func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := context.Background()
+	if o.tables {
+		tables, err := o.apiClientV2.Changefeeds().ListTables(ctx, o.namespace, o.changefeedID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return util.JSONPrint(cmd, tables)
+	}
 	if o.simplified {
 		infos, err := o.apiClientV2.Changefeeds().List(ctx, o.namespace, "all")
 		if err != nil {
@@ -0,0 +1,91 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/ticdc/pkg/applier"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// applyRedoOptions defines flags for the `cli redo apply` command.
+type applyRedoOptions struct {
+	storage string
+	sinkURI string
+	tmpDir  string
+}
+
+// newApplyRedoOptions creates new applyRedoOptions for the `cli redo apply` command.
+func newApplyRedoOptions() *applyRedoOptions {
+	return &applyRedoOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *applyRedoOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.storage, "storage", "", "storage of the redo logs, e.g. s3://bucket/prefix or file:///path")
+	cmd.Flags().StringVar(&o.sinkURI, "sink-uri", "", "uri of the mysql compatible downstream to apply the redo logs")
+	cmd.Flags().StringVar(&o.tmpDir, "tmp-dir", "", "temporary directory to sort the redo logs, the system temporary directory is used if it's empty")
+	_ = cmd.MarkFlagRequired("storage")
+	_ = cmd.MarkFlagRequired("sink-uri")
+}
+
+// validate checks that the provided options are valid.
+func (o *applyRedoOptions) validate() error {
+	if o.storage == "" {
+		return errors.New("storage of the redo logs must be specified")
+	}
+	if o.sinkURI == "" {
+		return errors.New("sink-uri must be specified")
+	}
+	return nil
+}
+
+// run runs the `cli redo apply` command.
+func (o *applyRedoOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	ra := applier.NewRedoApplier(&applier.RedoApplierConfig{
+		Storage: o.storage,
+		SinkURI: o.sinkURI,
+		Dir:     o.tmpDir,
+	})
+	if err := ra.Apply(ctx); err != nil {
+		return err
+	}
+
+	cmd.Println("Apply redo log successfully")
+	return nil
+}
+
+// newCmdApplyRedo creates the `cli redo apply` command.
+func newCmdApplyRedo() *cobra.Command {
+	o := newApplyRedoOptions()
+
+	command := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply the redo logs in the storage to the downstream up to the recorded resolved ts",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
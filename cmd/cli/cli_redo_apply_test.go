@@ -0,0 +1,37 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"testing"
+
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRedoCli(t *testing.T) {
+	o := newApplyRedoOptions()
+	require.NotNil(t, o.validate())
+	o.storage = "file:///tmp/redo"
+	require.NotNil(t, o.validate())
+	o.sinkURI = "mysql://root@127.0.0.1:3306/"
+	require.Nil(t, o.validate())
+
+	// the redo meta doesn't exist in the storage.
+	cmdcontext.SetDefaultContext(context.Background())
+	o.storage = "file://" + t.TempDir()
+	cmd := newCmdApplyRedo()
+	require.NotNil(t, o.run(cmd))
+}
@@ -38,6 +38,8 @@ const (
 	// User Credential Environment Variables
 	envVarTiCDCUser     = "TICDC_USER"
 	envVarTiCDCPassword = "TICDC_PASSWORD"
+	// Bearer Token Environment Variable
+	envVarTiCDCToken = "TICDC_TOKEN"
 	// TLS Client Certificate Environment Variables
 	envVarTiCDCCAPath   = "TICDC_CA_PATH"
 	envVarTiCDCCertPath = "TICDC_CERT_PATH"
@@ -69,6 +71,9 @@ type ClientAuth struct {
 	User     string `toml:"ticdc_user,omitempty"`
 	Password string `toml:"ticdc_password,omitempty"`
 
+	// Bearer Token
+	Token string `toml:"ticdc_token,omitempty"`
+
 	// TLS Client Certificate
 	CaPath   string `toml:"ca_path,omitempty"`
 	CertPath string `toml:"cert_path,omitempty"`
@@ -201,6 +206,9 @@ func (c *ClientFlags) AddFlags(cmd *cobra.Command) {
 		"You can sqpecify it via environment variable TICDC_USER")
 	cmd.PersistentFlags().StringVar(&c.Password, "password", "", "Password for authentication. "+
 		"You can specify it via environment variable TICDC_PASSWORD")
+	cmd.PersistentFlags().StringVar(&c.Token, "token", "", "Bearer token for authentication, "+
+		"it takes precedence over the user and password. "+
+		"You can specify it via environment variable TICDC_TOKEN")
 }
 
 // GetCredential returns credential.
@@ -218,9 +226,38 @@ func (c *ClientFlags) GetCredential() *security.Credential {
 // CompleteClientAuthParameters completes the authentication parameters.
 func (c *ClientFlags) CompleteClientAuthParameters(cmd *cobra.Command) error {
 	c.completeTLSClientCertificate(cmd)
+	if err := c.completeToken(); err != nil {
+		return err
+	}
+	// The user credential is not required if the token is specified.
+	if c.Token != "" {
+		log.Info("cli authentication type: token")
+		return nil
+	}
 	return c.completeUserCredential(cmd)
 }
 
+func (c *ClientFlags) completeToken() error {
+	if c.Token != "" {
+		return nil
+	}
+	// If token is not specified via command line, try to get it from environment variable.
+	c.Token = os.Getenv(envVarTiCDCToken)
+	if c.Token != "" {
+		return nil
+	}
+
+	// If token is not specified via command line or environment variable, try to get it from credential file.
+	res, err := ReadFromDefaultPath()
+	if err != nil {
+		return errors.WrapError(errors.ErrCredentialNotFound, err)
+	}
+	if res != nil {
+		c.Token = res.Token
+	}
+	return nil
+}
+
 func (c *ClientFlags) completeUserCredential(cmd *cobra.Command) (err error) {
 	authType := "command line"
 	defer func() {
@@ -303,6 +340,11 @@ func (c *ClientFlags) completeTLSClientCertificate(cmd *cobra.Command) {
 
 // GetAuthParameters returns the authentication parameters.
 func (c *ClientFlags) GetAuthParameters() url.Values {
+	if c.Token != "" {
+		return url.Values{
+			"token": {c.Token},
+		}
+	}
 	if c.User == "" {
 		return nil
 	}
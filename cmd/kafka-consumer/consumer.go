@@ -0,0 +1,155 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+func newSaramaConfig(o *option) (*sarama.Config, error) {
+	config := sarama.NewConfig()
+	version, err := sarama.ParseKafkaVersion(o.version)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrKafkaInvalidVersion, err)
+	}
+	config.Version = version
+	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	if o.ca != "" {
+		credential := &security.Credential{
+			CAPath:   o.ca,
+			CertPath: o.cert,
+			KeyPath:  o.key,
+		}
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config, err = credential.ToTLSConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return config, nil
+}
+
+// consumer reads the messages of all the partitions of the topic from the oldest offset,
+// and writes them to the downstream by the writer one by one.
+type consumer struct {
+	option *option
+	client sarama.Consumer
+	writer *writer
+}
+
+// newConsumer creates a consumer by the kafka client, the writer is created
+// for all the partitions of the topic.
+func newConsumer(ctx context.Context, o *option, client sarama.Consumer) (*consumer, error) {
+	partitions, err := client.Partitions(o.topic)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	partitionNum := int32(len(partitions))
+	if o.partitionNum != 0 && o.partitionNum != partitionNum {
+		return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"partition-num %d does not match the partition number %d of topic %s",
+			o.partitionNum, partitionNum, o.topic)
+	}
+	log.Info("get partition number of topic",
+		zap.String("topic", o.topic),
+		zap.Int32("partitionNum", partitionNum))
+
+	w, err := newWriter(ctx, o, partitionNum)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &consumer{
+		option: o,
+		client: client,
+		writer: w,
+	}, nil
+}
+
+// Run consumes the messages until the context is canceled or an error occurs.
+func (c *consumer) Run(ctx context.Context) error {
+	partitionConsumers := make([]sarama.PartitionConsumer, 0, len(c.writer.progresses))
+	for partition := range c.writer.progresses {
+		pc, err := c.client.ConsumePartition(c.option.topic, int32(partition), sarama.OffsetOldest)
+		if err != nil {
+			for _, pc := range partitionConsumers {
+				pc.AsyncClose()
+			}
+			return errors.Trace(err)
+		}
+		partitionConsumers = append(partitionConsumers, pc)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	messageCh := make(chan *sarama.ConsumerMessage, 1024)
+	for _, pc := range partitionConsumers {
+		g.Go(func() error {
+			defer pc.AsyncClose()
+			for {
+				select {
+				case <-ctx.Done():
+					return errors.Trace(ctx.Err())
+				case message, ok := <-pc.Messages():
+					if !ok {
+						return nil
+					}
+					select {
+					case <-ctx.Done():
+						return errors.Trace(ctx.Err())
+					case messageCh <- message:
+					}
+				case err, ok := <-pc.Errors():
+					if !ok {
+						return nil
+					}
+					return errors.Trace(err)
+				}
+			}
+		})
+	}
+	// the messages are written by a single goroutine, since a DDL event
+	// depends on the progress of all the partitions.
+	g.Go(func() error {
+		for {
+			select {
+			case <-ctx.Done():
+				return errors.Trace(ctx.Err())
+			case message := <-messageCh:
+				if err := c.writer.WriteMessage(message); err != nil {
+					log.Error("write message failed",
+						zap.Int32("partition", message.Partition),
+						zap.Int64("offset", message.Offset),
+						zap.Error(err))
+					return errors.Trace(err)
+				}
+			}
+		}
+	})
+	return g.Wait()
+}
+
+func (c *consumer) Close() {
+	c.writer.Close()
+	if err := c.client.Close(); err != nil {
+		log.Warn("close kafka consumer failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/sink/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/stretchr/testify/require"
+)
+
+const testTopic = "test-topic"
+
+// newOpenProtocolMessage encodes a key and a value to an open protocol message of batch version 1.
+func newOpenProtocolMessage(partition int32, offset int64, key, value string) *sarama.ConsumerMessage {
+	keyBytes := binary.BigEndian.AppendUint64(nil, 1)
+	keyBytes = binary.BigEndian.AppendUint64(keyBytes, uint64(len(key)))
+	keyBytes = append(keyBytes, key...)
+	valueBytes := binary.BigEndian.AppendUint64(nil, uint64(len(value)))
+	valueBytes = append(valueBytes, value...)
+	return &sarama.ConsumerMessage{
+		Topic:     testTopic,
+		Partition: partition,
+		Offset:    offset,
+		Key:       keyBytes,
+		Value:     valueBytes,
+	}
+}
+
+func newTestOption(t *testing.T) *option {
+	o := newOption()
+	o.downstreamURI = "mysql://127.0.0.1:3306/"
+	upstreamURI, err := url.Parse("kafka://127.0.0.1:9092/" + testTopic + "?protocol=open-protocol")
+	require.NoError(t, err)
+	require.NoError(t, o.Adjust(upstreamURI, ""))
+	return o
+}
+
+func TestConsumeOpenProtocolMessages(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	original := newMysqlConfigAndDB
+	newMysqlConfigAndDB = func(
+		_ context.Context, _ common.ChangeFeedID, _ *url.URL,
+	) (*mysql.MysqlConfig, *sql.DB, error) {
+		return &mysql.MysqlConfig{
+			MaxAllowedPacket: int64(variable.DefMaxAllowedPacket),
+			MaxTxnRow:        mysql.DefaultMaxTxnRow,
+		}, db, nil
+	}
+	defer func() { newMysqlConfigAndDB = original }()
+
+	// the DDL is sent to all the partitions, and the row is sent to partition 0.
+	ddlKey := `{"ts":10,"scm":"test","tbl":"t","t":2}`
+	ddlValue := `{"q":"CREATE TABLE t (id INT PRIMARY KEY, name VARCHAR(32))","t":3}`
+	rowKey := `{"ts":20,"scm":"test","tbl":"t","t":1}`
+	rowValue := `{"u":{"id":{"t":3,"h":true,"f":11,"v":1},"name":{"t":15,"f":64,"v":"a"}}}`
+	resolvedKey := `{"ts":30,"t":3}`
+
+	client := mocks.NewConsumer(t, nil)
+	client.SetTopicMetadata(map[string][]int32{testTopic: {0, 1}})
+	client.ExpectConsumePartition(testTopic, 0, sarama.OffsetOldest).
+		YieldMessage(newOpenProtocolMessage(0, 0, ddlKey, ddlValue)).
+		YieldMessage(newOpenProtocolMessage(0, 1, rowKey, rowValue)).
+		YieldMessage(newOpenProtocolMessage(0, 2, resolvedKey, ""))
+	client.ExpectConsumePartition(testTopic, 1, sarama.OffsetOldest).
+		YieldMessage(newOpenProtocolMessage(1, 0, ddlKey, ddlValue)).
+		YieldMessage(newOpenProtocolMessage(1, 1, resolvedKey, ""))
+
+	// the DDL is executed after both the partitions are resolved.
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("CREATE TABLE t (id INT PRIMARY KEY, name VARCHAR(32))").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE DATABASE IF NOT EXISTS tidb_cdc").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("USE tidb_cdc").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS ddl_ts_v1
+		(
+			ticdc_cluster_id varchar (255),
+			changefeed varchar(255),
+			ddl_ts varchar(18),
+			table_id bigint(21),
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			INDEX (ticdc_cluster_id, changefeed, table_id),
+			PRIMARY KEY (ticdc_cluster_id, changefeed, table_id)
+		);`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO tidb_cdc.ddl_ts_v1 (ticdc_cluster_id, changefeed, ddl_ts, table_id) " +
+		"VALUES ('default', 'default/kafka-consumer', '10', 0) " +
+		"ON DUPLICATE KEY UPDATE ddl_ts=VALUES(ddl_ts), created_at=CURRENT_TIMESTAMP;").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// the rows are written in safe mode.
+	mock.ExpectBegin()
+	mock.ExpectExec("REPLACE INTO `test`.`t` (`id`,`name`) VALUES (?,?)").
+		WithArgs(1, "a").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, err := newConsumer(ctx, newTestOption(t), client)
+	require.NoError(t, err)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.Run(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		return mock.ExpectationsWereMet() == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	for _, progress := range c.writer.progresses {
+		require.Equal(t, uint64(30), progress.watermark)
+	}
+
+	cancel()
+	require.Equal(t, context.Canceled, errors.Cause(<-errCh))
+	mock.ExpectClose()
+	c.writer.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdjustOption(t *testing.T) {
+	o := newTestOption(t)
+	require.Equal(t, testTopic, o.topic)
+	require.Equal(t, []string{"127.0.0.1:9092"}, o.address)
+	require.Equal(t, defaultKafkaVersion, o.version)
+
+	for _, uri := range []string{
+		// invalid scheme
+		"pulsar://127.0.0.1:6650/" + testTopic + "?protocol=open-protocol",
+		// no topic
+		"kafka://127.0.0.1:9092/?protocol=open-protocol",
+		// no protocol
+		"kafka://127.0.0.1:9092/" + testTopic,
+		// unsupported protocol
+		"kafka://127.0.0.1:9092/" + testTopic + "?protocol=craft",
+	} {
+		upstreamURI, err := url.Parse(uri)
+		require.NoError(t, err)
+		require.Error(t, newOption().Adjust(upstreamURI, ""), uri)
+	}
+}
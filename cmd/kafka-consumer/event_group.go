@@ -0,0 +1,52 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sort"
+
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+)
+
+// eventsGroup caches the DML events of a table received from a partition,
+// until the resolved ts of the partition is larger than their commitTs.
+type eventsGroup struct {
+	events []*commonEvent.DMLEvent
+}
+
+func newEventsGroup() *eventsGroup {
+	return &eventsGroup{
+		events: make([]*commonEvent.DMLEvent, 0),
+	}
+}
+
+// Append appends an event to the group.
+func (g *eventsGroup) Append(e *commonEvent.DMLEvent) {
+	g.events = append(g.events, e)
+}
+
+// Resolve removes and returns the events whose commitTs is not larger than
+// the resolvedTs, in commitTs order. The events of the same transaction keep
+// the order they are received.
+func (g *eventsGroup) Resolve(resolvedTs uint64) []*commonEvent.DMLEvent {
+	sort.SliceStable(g.events, func(i, j int) bool {
+		return g.events[i].CommitTs < g.events[j].CommitTs
+	})
+	i := sort.Search(len(g.events), func(i int) bool {
+		return g.events[i].CommitTs > resolvedTs
+	})
+	result := g.events[:i]
+	g.events = g.events[i:]
+	return result
+}
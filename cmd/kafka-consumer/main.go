@@ -0,0 +1,103 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/IBM/sarama"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/logger"
+	"github.com/pingcap/ticdc/version"
+	"go.uber.org/zap"
+)
+
+func main() {
+	var (
+		upstreamURIStr string
+		configFile     string
+	)
+	o := newOption()
+	flag.StringVar(&configFile, "config", "", "config file of the changefeed which produces the messages")
+	flag.StringVar(&upstreamURIStr, "upstream-uri", "", "kafka uri, e.g. kafka://127.0.0.1:9092/topic?protocol=open-protocol")
+	flag.StringVar(&o.downstreamURI, "downstream-uri", "", "mysql compatible downstream sink uri")
+	flag.StringVar(&o.upstreamTiDBDSN, "upstream-tidb-dsn", "", "upstream TiDB DSN, required by the handle-key-only messages")
+	flag.StringVar(&o.logPath, "log-file", "cdc_kafka_consumer.log", "log file path")
+	flag.StringVar(&o.logLevel, "log-level", "info", "log level (etc: debug|info|warn|error)")
+	flag.StringVar(&o.timezone, "tz", "System", "time zone of the kafka consumer")
+	flag.StringVar(&o.ca, "ca", "", "CA certificate path for Kafka SSL connection")
+	flag.StringVar(&o.cert, "cert", "", "Certificate path for Kafka SSL connection")
+	flag.StringVar(&o.key, "key", "", "Private key path for Kafka SSL connection")
+	flag.Parse()
+
+	err := logger.InitLogger(&logger.Config{
+		Level: o.logLevel,
+		File:  o.logPath,
+	})
+	if err != nil {
+		log.Panic("init logger failed", zap.Error(err))
+	}
+	version.LogVersionInfo("kafka consumer")
+
+	upstreamURI, err := url.Parse(upstreamURIStr)
+	if err != nil {
+		log.Panic("invalid upstream-uri", zap.Error(err))
+	}
+	if err = o.Adjust(upstreamURI, configFile); err != nil {
+		log.Panic("adjust consumer option failed", zap.Error(err))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		select {
+		case <-ctx.Done():
+		case s := <-sig:
+			log.Info("kafka consumer receives signal, exiting", zap.Stringer("signal", s))
+			cancel()
+		}
+	}()
+
+	if err = run(ctx, o); err != nil && errors.Cause(err) != context.Canceled {
+		log.Error("kafka consumer exits with error", zap.Error(err))
+		os.Exit(1)
+	}
+	log.Info("kafka consumer exits")
+}
+
+func run(ctx context.Context, o *option) error {
+	config, err := newSaramaConfig(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := sarama.NewConsumer(o.address, config)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c, err := newConsumer(ctx, o, client)
+	if err != nil {
+		_ = client.Close()
+		return errors.Trace(err)
+	}
+	defer c.Close()
+	return c.Run(ctx)
+}
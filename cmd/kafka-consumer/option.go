@@ -0,0 +1,155 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/filter"
+	"github.com/pingcap/ticdc/pkg/sink/codec/common"
+	cmdUtil "github.com/pingcap/tiflow/pkg/cmd/util"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const defaultKafkaVersion = "2.4.0"
+
+type option struct {
+	address      []string
+	version      string
+	topic        string
+	partitionNum int32
+
+	maxMessageBytes int
+	maxBatchSize    int
+
+	protocol    config.Protocol
+	codecConfig *common.Config
+	// replicaConfig is the config of the changefeed which produces the messages to the topic.
+	replicaConfig *config.ReplicaConfig
+
+	logPath       string
+	logLevel      string
+	timezone      string
+	ca, cert, key string
+
+	downstreamURI string
+	// upstreamTiDBDSN is used to query the whole row of the handle-key-only messages.
+	upstreamTiDBDSN string
+}
+
+func newOption() *option {
+	return &option{
+		version:         defaultKafkaVersion,
+		maxMessageBytes: math.MaxInt64,
+		maxBatchSize:    math.MaxInt64,
+	}
+}
+
+// Adjust adjusts the option by the upstream kafka uri and the changefeed config file.
+func (o *option) Adjust(upstreamURI *url.URL, configFile string) error {
+	if strings.ToLower(upstreamURI.Scheme) != "kafka" {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"the scheme of upstream-uri must be kafka, but got %s", upstreamURI.Scheme)
+	}
+	o.topic = strings.Trim(upstreamURI.Path, "/")
+	if o.topic == "" {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack("no topic is provided in upstream-uri")
+	}
+	o.address = strings.Split(upstreamURI.Host, ",")
+
+	query := upstreamURI.Query()
+	if s := query.Get("version"); s != "" {
+		o.version = s
+	}
+	if s := query.Get("partition-num"); s != "" {
+		c, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+		o.partitionNum = int32(c)
+	}
+	if s := query.Get("max-message-bytes"); s != "" {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+		o.maxMessageBytes = c
+	}
+	if s := query.Get("max-batch-size"); s != "" {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+		o.maxBatchSize = c
+	}
+
+	s := query.Get("protocol")
+	if s == "" {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack("no protocol is provided in upstream-uri")
+	}
+	protocol, err := config.ParseSinkProtocolFromString(s)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch protocol {
+	case config.ProtocolOpen, config.ProtocolDefault, config.ProtocolCanalJSON,
+		config.ProtocolSimple, config.ProtocolAvro:
+	default:
+		// the debezium messages carry neither the DDL events nor the watermarks,
+		// the events can not be applied to the downstream in order.
+		return cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(protocol)
+	}
+	o.protocol = protocol
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.Protocol = util.AddressOf(protocol.String())
+	if configFile != "" {
+		if err = cmdUtil.StrictDecodeFile(configFile, "kafka consumer", replicaConfig); err != nil {
+			return errors.Trace(err)
+		}
+		if _, err = filter.VerifyTableRules(replicaConfig.Filter); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	o.replicaConfig = replicaConfig
+
+	o.codecConfig = common.NewConfig(protocol)
+	if err = o.codecConfig.Apply(upstreamURI, replicaConfig.Sink); err != nil {
+		return errors.Trace(err)
+	}
+	tz, err := util.GetTimezone(o.timezone)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	o.codecConfig.TimeZone = tz
+
+	log.Info("consumer option adjusted",
+		zap.String("configFile", configFile),
+		zap.Strings("address", o.address),
+		zap.String("version", o.version),
+		zap.String("topic", o.topic),
+		zap.Int32("partitionNum", o.partitionNum),
+		zap.String("protocol", protocol.String()),
+		zap.Int("maxMessageBytes", o.maxMessageBytes),
+		zap.Int("maxBatchSize", o.maxBatchSize))
+	return nil
+}
@@ -0,0 +1,390 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+
+	"github.com/IBM/sarama"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/ticdc/pkg/sink/codec"
+	"github.com/pingcap/ticdc/pkg/sink/codec/decoder"
+	"github.com/pingcap/ticdc/pkg/sink/codec/simple"
+	"github.com/pingcap/ticdc/pkg/sink/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const consumerChangefeed = "kafka-consumer"
+
+// newMysqlConfigAndDB is used to create the downstream connection,
+// it can be replaced in tests to use a mock database.
+var newMysqlConfigAndDB = mysql.NewMysqlConfigAndDB
+
+// partitionProgress is the consuming progress of a kafka partition.
+type partitionProgress struct {
+	partition int32
+	// watermark is the max resolved ts received from the partition,
+	// all the events whose commitTs is not larger than it are received.
+	watermark       uint64
+	watermarkOffset int64
+
+	// eventGroups is the DML events received but not resolved yet, by table.
+	eventGroups map[int64]*eventsGroup
+	decoder     decoder.RowEventDecoder
+}
+
+// writer decodes the kafka messages, and writes the events to the mysql downstream
+// in commitTs order. The DML events of each partition are cached until the partition
+// is resolved, and a DDL event is executed only after all the partitions are resolved
+// to its commitTs and the DML events before it are written.
+type writer struct {
+	option *option
+
+	ddlList            []*commonEvent.DDLEvent
+	ddlWithMaxCommitTs *commonEvent.DDLEvent
+	progresses         []*partitionProgress
+
+	fakeTableIDGenerator *fakeTableIDGenerator
+
+	upstreamTiDB *sql.DB
+	db           *sql.DB
+	statistics   *metrics.Statistics
+	mysqlWriter  *mysql.MysqlWriter
+	maxTxnRow    int
+}
+
+func newWriter(ctx context.Context, o *option, partitionNum int32) (*writer, error) {
+	sinkURI, err := url.Parse(o.downstreamURI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if !sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
+		return nil, cerror.ErrSinkURIInvalid.GenWithStack(
+			"kafka consumer only supports mysql compatible downstream, but got %s", sinkURI.Scheme)
+	}
+
+	var upstreamTiDB *sql.DB
+	if o.upstreamTiDBDSN != "" {
+		upstreamTiDB, err = sql.Open("mysql", o.upstreamTiDBDSN)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	w := &writer{
+		option:       o,
+		upstreamTiDB: upstreamTiDB,
+		progresses:   make([]*partitionProgress, partitionNum),
+		fakeTableIDGenerator: &fakeTableIDGenerator{
+			tableIDs: make(map[string]int64),
+		},
+	}
+	for i := range w.progresses {
+		rowDecoder, err := codec.NewEventDecoder(ctx, o.codecConfig, o.topic, upstreamTiDB)
+		if err != nil {
+			w.Close()
+			return nil, errors.Trace(err)
+		}
+		w.progresses[i] = &partitionProgress{
+			partition:   int32(i),
+			eventGroups: make(map[int64]*eventsGroup),
+			decoder:     rowDecoder,
+		}
+	}
+
+	changefeedID := common.NewChangeFeedIDWithName(consumerChangefeed)
+	cfg, db, err := newMysqlConfigAndDB(ctx, changefeedID, sinkURI)
+	if err != nil {
+		w.Close()
+		return nil, errors.Trace(err)
+	}
+	// The offsets are not committed, the messages are consumed from the oldest
+	// offset after restarting, so the safe mode is always enabled to make the
+	// replay idempotent.
+	cfg.SafeMode = true
+	w.db = db
+	w.maxTxnRow = cfg.MaxTxnRow
+	w.statistics = metrics.NewStatistics(changefeedID, "KafkaConsumer")
+	w.mysqlWriter = mysql.NewMysqlWriter(ctx, db, cfg, changefeedID, w.statistics)
+	return w, nil
+}
+
+// WriteMessage decodes the kafka message, and writes the resolved events
+// to the downstream if a DDL event or a resolved event is received.
+func (w *writer) WriteMessage(message *sarama.ConsumerMessage) error {
+	if int(message.Partition) >= len(w.progresses) {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"message received from partition %d, but the partition num is %d",
+			message.Partition, len(w.progresses))
+	}
+	progress := w.progresses[message.Partition]
+	if err := progress.decoder.AddKeyValue(message.Key, message.Value); err != nil {
+		return errors.Trace(err)
+	}
+
+	var (
+		counter   int
+		needFlush bool
+	)
+	for {
+		tp, hasNext, err := progress.decoder.HasNext()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !hasNext {
+			break
+		}
+		counter++
+		// a message which contains only one event is allowed to exceed the limit.
+		if counter > 1 && len(message.Key)+len(message.Value) > w.option.maxMessageBytes {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"the message of partition %d offset %d exceeds max-message-bytes %d",
+				message.Partition, message.Offset, w.option.maxMessageBytes)
+		}
+		switch tp {
+		case model.MessageTypeDDL:
+			ddl, err := progress.decoder.NextDDLEvent()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			// the simple protocol decoder caches the rows whose table schema is
+			// not received yet, they can be decoded after the DDL event.
+			if simpleDecoder, ok := progress.decoder.(*simple.Decoder); ok {
+				for _, event := range simpleDecoder.GetCachedEvents() {
+					if err := w.appendDML(progress, message.Offset, event); err != nil {
+						return err
+					}
+				}
+			}
+			// the DDL events are sent to all the partitions,
+			// only the ones received from the first partition are handled.
+			// The bootstrap messages of the simple protocol carry no query.
+			if message.Partition == 0 && ddl.Query != "" {
+				// the table ids are lost in the message, the ddl ts is recorded for the ddl span.
+				ddl.Version = commonEvent.DDLEventVersion
+				ddl.BlockedTables = &commonEvent.InfluencedTables{
+					InfluenceType: commonEvent.InfluenceTypeNormal,
+					TableIDs:      []int64{heartbeatpb.DDLSpan.TableID},
+				}
+				w.appendDDL(ddl)
+				needFlush = true
+			}
+		case model.MessageTypeRow:
+			event, err := progress.decoder.NextDMLEvent()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			// the row is cached by the decoder until its table schema is received.
+			if event == nil {
+				continue
+			}
+			if err := w.appendDML(progress, message.Offset, event); err != nil {
+				return err
+			}
+		case model.MessageTypeResolved:
+			ts, err := progress.decoder.NextResolvedEvent()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if ts < progress.watermark {
+				if message.Offset > progress.watermarkOffset {
+					return cerror.ErrKafkaInvalidConfig.GenWithStack(
+						"resolved ts %d of partition %d fallback, the watermark is %d",
+						ts, message.Partition, progress.watermark)
+				}
+				// the old messages may be consumed again, just ignore them.
+				continue
+			}
+			progress.watermark = ts
+			progress.watermarkOffset = message.Offset
+			needFlush = true
+		default:
+			log.Warn("unknown message type, ignore it",
+				zap.Any("messageType", tp),
+				zap.Int32("partition", message.Partition),
+				zap.Int64("offset", message.Offset))
+		}
+	}
+	if counter > w.option.maxBatchSize {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"the message of partition %d offset %d contains %d events, exceeds max-batch-size %d",
+			message.Partition, message.Offset, counter, w.option.maxBatchSize)
+	}
+	if !needFlush {
+		return nil
+	}
+	return w.flush()
+}
+
+// appendDDL appends the DDL event to the pending list, the DDL events are
+// expected to be received in commitTs order.
+func (w *writer) appendDDL(ddl *commonEvent.DDLEvent) {
+	if w.ddlWithMaxCommitTs != nil {
+		maxCommitTs := w.ddlWithMaxCommitTs.GetCommitTs()
+		// A rename tables DDL job contains multiple DDL events with the same commitTs,
+		// so the DDL is redundant only if the query is the same.
+		if ddl.GetCommitTs() < maxCommitTs ||
+			(ddl.GetCommitTs() == maxCommitTs && ddl.Query == w.ddlWithMaxCommitTs.Query) {
+			log.Warn("ignore the DDL event which is already received",
+				zap.Uint64("commitTs", ddl.GetCommitTs()),
+				zap.Uint64("maxCommitTs", maxCommitTs),
+				zap.String("query", ddl.Query))
+			return
+		}
+	}
+	log.Info("DDL event received",
+		zap.Uint64("commitTs", ddl.GetCommitTs()),
+		zap.String("query", ddl.Query))
+	w.ddlList = append(w.ddlList, ddl)
+	w.ddlWithMaxCommitTs = ddl
+}
+
+func (w *writer) appendDML(progress *partitionProgress, offset int64, event *commonEvent.DMLEvent) error {
+	// the table id is not in the message, a fake one is generated for each table or partition.
+	tableID := w.fakeTableIDGenerator.generateFakeTableID(
+		event.TableInfo.GetSchemaName(), event.TableInfo.GetTableName(), event.PhysicalTableID)
+	event.PhysicalTableID = tableID
+	if event.CommitTs < progress.watermark {
+		if offset > progress.watermarkOffset {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"commitTs %d of the DML event in partition %d fallback, the watermark is %d",
+				event.CommitTs, progress.partition, progress.watermark)
+		}
+		// the old messages may be consumed again, just ignore them.
+		log.Warn("DML event fallback, ignore it",
+			zap.Uint64("commitTs", event.CommitTs),
+			zap.Uint64("watermark", progress.watermark),
+			zap.Int32("partition", progress.partition),
+			zap.Int64("offset", offset),
+			zap.String("table", event.TableInfo.TableName.String()))
+		return nil
+	}
+	group, ok := progress.eventGroups[tableID]
+	if !ok {
+		group = newEventsGroup()
+		progress.eventGroups[tableID] = group
+	}
+	group.Append(event)
+	return nil
+}
+
+// flush executes the DDL events whose commitTs is not larger than the min watermark,
+// and then writes the DML events resolved by the min watermark.
+func (w *writer) flush() error {
+	watermark := w.getMinWatermark()
+	for len(w.ddlList) > 0 {
+		ddl := w.ddlList[0]
+		if ddl.GetCommitTs() > watermark {
+			break
+		}
+		if err := w.flushDMLs(ddl.GetCommitTs()); err != nil {
+			return err
+		}
+		log.Info("execute DDL event",
+			zap.Uint64("commitTs", ddl.GetCommitTs()),
+			zap.String("query", ddl.Query))
+		if err := w.mysqlWriter.FlushDDLEvent(ddl); err != nil {
+			return errors.Trace(err)
+		}
+		w.ddlList = w.ddlList[1:]
+	}
+	return w.flushDMLs(watermark)
+}
+
+// flushDMLs writes the DML events whose commitTs is not larger than the resolvedTs,
+// in batches of at most maxTxnRow rows.
+func (w *writer) flushDMLs(resolvedTs uint64) error {
+	for _, progress := range w.progresses {
+		tableIDs := make([]int64, 0, len(progress.eventGroups))
+		for tableID := range progress.eventGroups {
+			tableIDs = append(tableIDs, tableID)
+		}
+		sort.Slice(tableIDs, func(i, j int) bool { return tableIDs[i] < tableIDs[j] })
+		for _, tableID := range tableIDs {
+			events := progress.eventGroups[tableID].Resolve(resolvedTs)
+			for len(events) > 0 {
+				rows, batch := 0, 0
+				for batch < len(events) && (batch == 0 || rows+int(events[batch].Len()) <= w.maxTxnRow) {
+					rows += int(events[batch].Len())
+					batch++
+				}
+				if err := w.mysqlWriter.Flush(events[:batch], 0); err != nil {
+					return errors.Trace(err)
+				}
+				events = events[batch:]
+			}
+		}
+	}
+	return nil
+}
+
+func (w *writer) getMinWatermark() uint64 {
+	result := uint64(math.MaxUint64)
+	for _, progress := range w.progresses {
+		if progress.watermark < result {
+			result = progress.watermark
+		}
+	}
+	return result
+}
+
+func (w *writer) Close() {
+	if w.mysqlWriter != nil {
+		w.mysqlWriter.Close()
+	}
+	if w.statistics != nil {
+		w.statistics.Close()
+	}
+	if w.db != nil {
+		if err := w.db.Close(); err != nil {
+			log.Warn("close downstream db failed", zap.Error(err))
+		}
+	}
+	if w.upstreamTiDB != nil {
+		if err := w.upstreamTiDB.Close(); err != nil {
+			log.Warn("close upstream TiDB failed", zap.Error(err))
+		}
+	}
+}
+
+type fakeTableIDGenerator struct {
+	tableIDs       map[string]int64
+	currentTableID int64
+}
+
+func (g *fakeTableIDGenerator) generateFakeTableID(schema, table string, partition int64) int64 {
+	key := quotes.QuoteSchema(schema, table)
+	if partition != 0 {
+		key = fmt.Sprintf("%s.`%d`", key, partition)
+	}
+	if tableID, ok := g.tableIDs[key]; ok {
+		return tableID
+	}
+	g.currentTableID++
+	g.tableIDs[key] = g.currentTableID
+	return g.currentTableID
+}
@@ -31,11 +31,10 @@ import (
 
 // Changefeed is a memory present for changefeed info and status
 type Changefeed struct {
-	ID          common.ChangeFeedID
-	info        *atomic.Pointer[config.ChangeFeedInfo]
-	isMQSink    bool
-	nodeID      node.ID
-	configBytes []byte
+	ID       common.ChangeFeedID
+	info     *atomic.Pointer[config.ChangeFeedInfo]
+	isMQSink bool
+	nodeID   node.ID
 	// it's saved to the backend db
 	lastSavedCheckpointTs *atomic.Uint64
 	// the heartbeatpb.MaintainerStatus is read only
@@ -53,11 +52,6 @@ func NewChangefeed(cfID common.ChangeFeedID,
 		log.Panic("unable to marshal changefeed config",
 			zap.Error(err))
 	}
-	bytes, err := json.Marshal(info)
-	if err != nil {
-		log.Panic("unable to marshal changefeed config",
-			zap.Error(err))
-	}
 	log.Info("changefeed instance created",
 		zap.String("id", cfID.String()),
 		zap.Uint64("checkpointTs", checkpointTs),
@@ -65,7 +59,6 @@ func NewChangefeed(cfID common.ChangeFeedID,
 	return &Changefeed{
 		ID:                    cfID,
 		info:                  atomic.NewPointer(info),
-		configBytes:           bytes,
 		lastSavedCheckpointTs: atomic.NewUint64(checkpointTs),
 		isMQSink:              sink.IsMQScheme(uri.Scheme),
 		// init the first Status
@@ -130,12 +123,19 @@ func (c *Changefeed) GetLastSavedCheckPointTs() uint64 {
 }
 
 func (c *Changefeed) NewAddMaintainerMessage(server node.ID) *messaging.TargetMessage {
+	// marshal the latest info, it may be updated after the changefeed is created,
+	// e.g. the pending ddl decisions.
+	bytes, err := json.Marshal(c.GetInfo())
+	if err != nil {
+		log.Panic("unable to marshal changefeed config",
+			zap.Error(err))
+	}
 	return messaging.NewSingleTargetMessage(server,
 		messaging.MaintainerManagerTopic,
 		&heartbeatpb.AddMaintainerRequest{
 			Id:           c.ID.ToPB(),
 			CheckpointTs: c.GetStatus().CheckpointTs,
-			Config:       c.configBytes,
+			Config:       bytes,
 		})
 }
 
@@ -0,0 +1,314 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changefeed
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	sqlBackendVersionTable    = "ticdc_meta_version"
+	sqlBackendChangefeedTable = "ticdc_changefeed"
+	// sqlBackendCheckpointBatchSize is the max number of changefeeds whose
+	// checkpoint ts are updated in one transaction
+	sqlBackendCheckpointBatchSize = 128
+)
+
+// sqlBackendMigrations are the statements to migrate the schema of the meta tables,
+// the schema version is the number of the applied migrations, so the migrations
+// can only be appended, and each of them must be idempotent.
+var sqlBackendMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS ` + sqlBackendChangefeedTable + ` (
+	cluster_id VARCHAR(128) NOT NULL,
+	namespace VARCHAR(128) NOT NULL,
+	name VARCHAR(128) NOT NULL,
+	info LONGTEXT NOT NULL,
+	checkpoint_ts BIGINT UNSIGNED NOT NULL,
+	progress INT NOT NULL,
+	update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	PRIMARY KEY (cluster_id, namespace, name)
+)`,
+}
+
+// SQLBackend is the changefeed meta store using a MySQL compatible database as the storage,
+// the info and the status of a changefeed are stored in one row, so all the operations
+// of a changefeed are transactional.
+type SQLBackend struct {
+	db        *sql.DB
+	clusterID string
+}
+
+// NewSQLBackend creates a SQLBackend, and migrates the schema of the meta tables to the latest version
+func NewSQLBackend(ctx context.Context, db *sql.DB, clusterID string) (*SQLBackend, error) {
+	b := &SQLBackend{
+		db:        db,
+		clusterID: clusterID,
+	}
+	if err := b.migrate(ctx); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return b, nil
+}
+
+// migrate applies the migrations which are not applied yet in order.
+func (b *SQLBackend) migrate(ctx context.Context) error {
+	_, err := b.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+sqlBackendVersionTable+` (
+	id INT NOT NULL PRIMARY KEY,
+	version INT NOT NULL
+)`)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = b.db.ExecContext(ctx, "INSERT IGNORE INTO "+sqlBackendVersionTable+" (id, version) VALUES (1, 0)")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var version int
+	err = b.db.QueryRowContext(ctx, "SELECT version FROM "+sqlBackendVersionTable+" WHERE id = 1").Scan(&version)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if version > len(sqlBackendMigrations) {
+		return errors.ErrMetaOpFailed.GenWithStackByArgs(
+			fmt.Sprintf("the meta schema version %d is newer than the supported version %d",
+				version, len(sqlBackendMigrations)))
+	}
+	for ; version < len(sqlBackendMigrations); version++ {
+		log.Info("migrate the schema of the changefeed meta tables",
+			zap.Int("from", version), zap.Int("to", version+1))
+		if _, err = b.db.ExecContext(ctx, sqlBackendMigrations[version]); err != nil {
+			return errors.Trace(err)
+		}
+		res, err := b.db.ExecContext(ctx, "UPDATE "+sqlBackendVersionTable+" SET version = ? WHERE id = 1 AND version = ?",
+			version+1, version)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// the version is changed by another server which migrates the schema concurrently
+		if affected == 0 {
+			return errors.ErrMetaOpFailed.GenWithStackByArgs(
+				fmt.Sprintf("the meta schema version %d is changed by others during migration", version))
+		}
+	}
+	return nil
+}
+
+func (b *SQLBackend) GetAllChangefeeds(ctx context.Context) (map[common.ChangeFeedID]*ChangefeedMetaWrapper, error) {
+	rows, err := b.db.QueryContext(ctx,
+		"SELECT namespace, name, info, checkpoint_ts, progress FROM "+sqlBackendChangefeedTable+" WHERE cluster_id = ?",
+		b.clusterID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer rows.Close()
+
+	cfMap := make(map[common.ChangeFeedID]*ChangefeedMetaWrapper)
+	for rows.Next() {
+		var (
+			ns, name, infoValue string
+			checkpointTs        uint64
+			progress            int
+		)
+		if err = rows.Scan(&ns, &name, &infoValue, &checkpointTs, &progress); err != nil {
+			return nil, errors.Trace(err)
+		}
+		info := &config.ChangeFeedInfo{}
+		if err = info.Unmarshal([]byte(infoValue)); err != nil {
+			log.Warn("failed to unmarshal change feed Info, ignore",
+				zap.String("namespace", ns), zap.String("name", name), zap.Error(err))
+			continue
+		}
+		if info.ChangefeedID.Name() == "" {
+			info.ChangefeedID = common.NewChangeFeedIDWithDisplayName(common.NewChangeFeedDisplayName(name, ns))
+		}
+		cfMap[info.ChangefeedID] = &ChangefeedMetaWrapper{
+			Info: info,
+			Status: &config.ChangeFeedStatus{
+				CheckpointTs: checkpointTs,
+				Progress:     config.Progress(progress),
+			},
+		}
+	}
+	return cfMap, errors.Trace(rows.Err())
+}
+
+func (b *SQLBackend) CreateChangefeed(ctx context.Context, info *config.ChangeFeedInfo) error {
+	infoValue, err := info.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	res, err := b.db.ExecContext(ctx, "INSERT IGNORE INTO "+sqlBackendChangefeedTable+
+		" (cluster_id, namespace, name, info, checkpoint_ts, progress) VALUES (?, ?, ?, ?, ?, ?)",
+		b.clusterID, info.ChangefeedID.Namespace(), info.ChangefeedID.Name(),
+		infoValue, info.StartTs, int(config.ProgressNone))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if affected == 0 {
+		err = errors.ErrMetaOpFailed.GenWithStackByArgs(fmt.Sprintf("create changefeed %s", info.ChangefeedID.Name()))
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+func (b *SQLBackend) UpdateChangefeed(ctx context.Context, info *config.ChangeFeedInfo, checkpointTs uint64, progress config.Progress) error {
+	infoValue, err := info.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = b.db.ExecContext(ctx, "UPDATE "+sqlBackendChangefeedTable+
+		" SET info = ?, checkpoint_ts = ?, progress = ? WHERE cluster_id = ? AND namespace = ? AND name = ?",
+		infoValue, checkpointTs, int(progress),
+		b.clusterID, info.ChangefeedID.Namespace(), info.ChangefeedID.Name())
+	return errors.Trace(err)
+}
+
+func (b *SQLBackend) PauseChangefeed(ctx context.Context, id common.ChangeFeedID) error {
+	return b.updateInTxn(ctx, id, func(info *config.ChangeFeedInfo, status *config.ChangeFeedStatus) {
+		info.State = model.StateStopped
+		status.Progress = config.ProgressStopping
+	})
+}
+
+func (b *SQLBackend) DeleteChangefeed(ctx context.Context, id common.ChangeFeedID) error {
+	_, err := b.db.ExecContext(ctx, "DELETE FROM "+sqlBackendChangefeedTable+
+		" WHERE cluster_id = ? AND namespace = ? AND name = ?",
+		b.clusterID, id.Namespace(), id.Name())
+	return errors.Trace(err)
+}
+
+func (b *SQLBackend) ResumeChangefeed(ctx context.Context, id common.ChangeFeedID, newCheckpointTs uint64) error {
+	return b.updateInTxn(ctx, id, func(info *config.ChangeFeedInfo, status *config.ChangeFeedStatus) {
+		info.State = model.StateNormal
+		if newCheckpointTs > 0 {
+			status.CheckpointTs = newCheckpointTs
+		}
+	})
+}
+
+func (b *SQLBackend) SetChangefeedProgress(ctx context.Context, id common.ChangeFeedID, progress config.Progress) error {
+	res, err := b.db.ExecContext(ctx, "UPDATE "+sqlBackendChangefeedTable+
+		" SET progress = ? WHERE cluster_id = ? AND namespace = ? AND name = ?",
+		int(progress), b.clusterID, id.Namespace(), id.Name())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// the affected rows is 0 if the progress is not changed, so only the error is checked
+	_, err = res.RowsAffected()
+	return errors.Trace(err)
+}
+
+func (b *SQLBackend) UpdateChangefeedCheckpointTs(ctx context.Context, cps map[common.ChangeFeedID]uint64) error {
+	ids := make([]common.ChangeFeedID, 0, len(cps))
+	for id := range cps {
+		ids = append(ids, id)
+	}
+	for start := 0; start < len(ids); start += sqlBackendCheckpointBatchSize {
+		end := start + sqlBackendCheckpointBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := b.updateCheckpointTs(ctx, ids[start:end], cps); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// updateCheckpointTs updates the checkpoint ts of a batch of changefeeds in one transaction.
+func (b *SQLBackend) updateCheckpointTs(ctx context.Context, ids []common.ChangeFeedID, cps map[common.ChangeFeedID]uint64) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, id := range ids {
+		_, err = tx.ExecContext(ctx, "UPDATE "+sqlBackendChangefeedTable+
+			" SET checkpoint_ts = ?, progress = ? WHERE cluster_id = ? AND namespace = ? AND name = ?",
+			cps[id], int(config.ProgressNone), b.clusterID, id.Namespace(), id.Name())
+		if err != nil {
+			rollbackTxn(tx)
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(tx.Commit())
+}
+
+// updateInTxn reads the info and the status of the changefeed with a row lock,
+// and writes them back after they are updated by the update function.
+func (b *SQLBackend) updateInTxn(ctx context.Context, id common.ChangeFeedID,
+	update func(info *config.ChangeFeedInfo, status *config.ChangeFeedStatus)) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var (
+		infoValue string
+		status    = &config.ChangeFeedStatus{}
+		progress  int
+	)
+	err = tx.QueryRowContext(ctx, "SELECT info, checkpoint_ts, progress FROM "+sqlBackendChangefeedTable+
+		" WHERE cluster_id = ? AND namespace = ? AND name = ? FOR UPDATE",
+		b.clusterID, id.Namespace(), id.Name()).Scan(&infoValue, &status.CheckpointTs, &progress)
+	if err != nil {
+		rollbackTxn(tx)
+		if err == sql.ErrNoRows {
+			return errors.ErrChangeFeedNotExists.GenWithStackByArgs(id.Name())
+		}
+		return errors.Trace(err)
+	}
+	status.Progress = config.Progress(progress)
+	info := &config.ChangeFeedInfo{}
+	if err = info.Unmarshal([]byte(infoValue)); err != nil {
+		rollbackTxn(tx)
+		return errors.Trace(err)
+	}
+
+	update(info, status)
+	newInfoValue, err := info.Marshal()
+	if err != nil {
+		rollbackTxn(tx)
+		return errors.Trace(err)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE "+sqlBackendChangefeedTable+
+		" SET info = ?, checkpoint_ts = ?, progress = ? WHERE cluster_id = ? AND namespace = ? AND name = ?",
+		newInfoValue, status.CheckpointTs, int(status.Progress), b.clusterID, id.Namespace(), id.Name())
+	if err != nil {
+		rollbackTxn(tx)
+		return errors.Trace(err)
+	}
+	return errors.Trace(tx.Commit())
+}
+
+func rollbackTxn(tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil {
+		log.Warn("failed to rollback the transaction", zap.Error(err))
+	}
+}
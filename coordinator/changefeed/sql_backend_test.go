@@ -0,0 +1,201 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changefeed
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// infoStateMatcher matches the changefeed info json with the expected state
+type infoStateMatcher struct {
+	state model.FeedState
+}
+
+func (m infoStateMatcher) Match(v driver.Value) bool {
+	value, ok := v.(string)
+	if !ok {
+		return false
+	}
+	info := &config.ChangeFeedInfo{}
+	if err := info.Unmarshal([]byte(value)); err != nil {
+		return false
+	}
+	return info.State == m.state
+}
+
+func expectMigrate(mock sqlmock.Sqlmock, version int) {
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS " + sqlBackendVersionTable).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO " + sqlBackendVersionTable)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version FROM " + sqlBackendVersionTable)).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version))
+	for ; version < len(sqlBackendMigrations); version++ {
+		mock.ExpectExec(regexp.QuoteMeta(sqlBackendMigrations[version])).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE "+sqlBackendVersionTable)).
+			WithArgs(version+1, version).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+}
+
+func newTestSQLBackend(t *testing.T) (*SQLBackend, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	expectMigrate(mock, 0)
+	backend, err := NewSQLBackend(context.Background(), db, "default")
+	require.NoError(t, err)
+	return backend, mock
+}
+
+func TestSQLBackendMigrate(t *testing.T) {
+	_, mock := newTestSQLBackend(t)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// the schema is already the latest version
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	expectMigrate(mock, len(sqlBackendMigrations))
+	_, err = NewSQLBackend(context.Background(), db, "default")
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// the schema is newer than the supported version
+	db, mock, err = sqlmock.New()
+	require.NoError(t, err)
+	expectMigrate(mock, len(sqlBackendMigrations)+1)
+	_, err = NewSQLBackend(context.Background(), db, "default")
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// the schema is migrated by another server concurrently
+	db, mock, err = sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS " + sqlBackendVersionTable).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO " + sqlBackendVersionTable)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version FROM " + sqlBackendVersionTable)).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta(sqlBackendMigrations[0])).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE "+sqlBackendVersionTable)).
+		WithArgs(1, 0).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	_, err = NewSQLBackend(context.Background(), db, "default")
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLBackendChangefeed(t *testing.T) {
+	ctx := context.Background()
+	backend, mock := newTestSQLBackend(t)
+	cfID := common.NewChangeFeedIDWithName("test")
+	info := &config.ChangeFeedInfo{
+		ChangefeedID: cfID,
+		SinkURI:      "blackhole://",
+		StartTs:      10,
+		State:        model.StateNormal,
+	}
+	infoValue, err := info.Marshal()
+	require.NoError(t, err)
+
+	// create
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO "+sqlBackendChangefeedTable)).
+		WithArgs("default", "default", "test", infoValue, uint64(10), int(config.ProgressNone)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, backend.CreateChangefeed(ctx, info))
+	// the changefeed already exists
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO " + sqlBackendChangefeedTable)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	require.True(t, cerror.ErrMetaOpFailed.Equal(backend.CreateChangefeed(ctx, info)))
+
+	// pause
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT info, checkpoint_ts, progress FROM "+sqlBackendChangefeedTable)).
+		WithArgs("default", "default", "test").
+		WillReturnRows(sqlmock.NewRows([]string{"info", "checkpoint_ts", "progress"}).
+			AddRow(infoValue, 20, int(config.ProgressNone)))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE "+sqlBackendChangefeedTable+" SET info = ?, checkpoint_ts = ?, progress = ?")).
+		WithArgs(infoStateMatcher{state: model.StateStopped}, uint64(20), int(config.ProgressStopping),
+			"default", "default", "test").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, backend.PauseChangefeed(ctx, cfID))
+
+	// resume with a new checkpoint ts
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT info, checkpoint_ts, progress FROM " + sqlBackendChangefeedTable)).
+		WillReturnRows(sqlmock.NewRows([]string{"info", "checkpoint_ts", "progress"}).
+			AddRow(infoValue, 20, int(config.ProgressStopping)))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE "+sqlBackendChangefeedTable+" SET info = ?, checkpoint_ts = ?, progress = ?")).
+		WithArgs(infoStateMatcher{state: model.StateNormal}, uint64(30), int(config.ProgressStopping),
+			"default", "default", "test").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, backend.ResumeChangefeed(ctx, cfID, 30))
+
+	// the changefeed is not found, the transaction is rolled back
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT info, checkpoint_ts, progress FROM " + sqlBackendChangefeedTable)).
+		WillReturnRows(sqlmock.NewRows([]string{"info", "checkpoint_ts", "progress"}))
+	mock.ExpectRollback()
+	err = backend.PauseChangefeed(ctx, common.NewChangeFeedIDWithName("not-exist"))
+	require.True(t, cerror.ErrChangeFeedNotExists.Equal(err))
+
+	// update checkpoint ts in batch
+	cps := make(map[common.ChangeFeedID]uint64)
+	for i := 0; i < sqlBackendCheckpointBatchSize+1; i++ {
+		cps[common.NewChangeFeedIDWithName(string(rune('a'+i%26))+string(rune('a'+i/26)))] = uint64(i)
+	}
+	for _, size := range []int{sqlBackendCheckpointBatchSize, 1} {
+		mock.ExpectBegin()
+		for i := 0; i < size; i++ {
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE " + sqlBackendChangefeedTable + " SET checkpoint_ts = ?, progress = ?")).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectCommit()
+	}
+	require.NoError(t, backend.UpdateChangefeedCheckpointTs(ctx, cps))
+
+	// load all changefeeds
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT namespace, name, info, checkpoint_ts, progress FROM " + sqlBackendChangefeedTable)).
+		WithArgs("default").
+		WillReturnRows(sqlmock.NewRows([]string{"namespace", "name", "info", "checkpoint_ts", "progress"}).
+			AddRow("default", "test", infoValue, 40, int(config.ProgressNone)).
+			AddRow("default", "broken", "{", 40, int(config.ProgressNone)))
+	cfs, err := backend.GetAllChangefeeds(ctx)
+	require.NoError(t, err)
+	require.Len(t, cfs, 1)
+	require.Equal(t, "test", cfs[cfID].Info.ChangefeedID.Name())
+	require.Equal(t, uint64(40), cfs[cfID].Status.CheckpointTs)
+	require.Equal(t, config.ProgressNone, cfs[cfID].Status.Progress)
+
+	// delete
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM "+sqlBackendChangefeedTable)).
+		WithArgs("default", "default", "test").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, backend.DeleteChangefeed(ctx, cfID))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -15,6 +15,7 @@ package coordinator
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -67,9 +68,17 @@ type Controller struct {
 
 	lastPrintStatusTime time.Time
 
+	// maintainerRequestID is used to generate the id of the requests sent to the maintainers,
+	// pendingRequests maps the request id to the channel waiting for the response.
+	maintainerRequestID *atomic.Uint64
+	pendingRequests     sync.Map
+
 	apiLock sync.RWMutex
 }
 
+// maintainerRequestTimeout is the max time to wait for the response of a request sent to the maintainer.
+const maintainerRequestTimeout = 10 * time.Second
+
 type ChangefeedStateChangeEvent struct {
 	ChangefeedID common.ChangeFeedID
 	State        model.FeedState
@@ -106,6 +115,8 @@ func NewController(
 		updatedChangefeedCh: updatedChangefeedCh,
 		stateChangedCh:      stateChangedCh,
 		lastPrintStatusTime: time.Now(),
+
+		maintainerRequestID: atomic.NewUint64(0),
 	}
 	c.bootstrapper = bootstrap.NewBootstrapper[heartbeatpb.CoordinatorBootstrapResponse]("coordinator", c.newBootstrapMessage)
 	// init bootstrapper nodes
@@ -168,6 +179,15 @@ func (c *Controller) onMessage(msg *messaging.TargetMessage) {
 			req := msg.Message[0].(*heartbeatpb.MaintainerHeartbeat)
 			c.HandleStatus(msg.From, req.Statuses)
 		}
+	case messaging.TypeTableScheduleResponse:
+		resp := msg.Message[0].(*messaging.TableScheduleResponse)
+		c.onMaintainerResponse(resp.RequestID, resp)
+	case messaging.TypeTableStatusResponse:
+		resp := msg.Message[0].(*messaging.TableStatusResponse)
+		c.onMaintainerResponse(resp.RequestID, resp)
+	case messaging.TypePendingDDLResponse:
+		resp := msg.Message[0].(*messaging.PendingDDLResponse)
+		c.onMaintainerResponse(resp.RequestID, resp)
 	default:
 		log.Panic("unexpected message type",
 			zap.String("type", msg.Type.String()))
@@ -462,6 +482,202 @@ func (c *Controller) UpdateChangefeed(ctx context.Context, change *config.Change
 	return nil
 }
 
+// MoveTable moves all the spans of the table to the target node,
+// the request is sent to the maintainer of the changefeed.
+func (c *Controller) MoveTable(ctx context.Context, id common.ChangeFeedID, tableID int64, targetNode node.ID) error {
+	if _, ok := c.nodeManager.GetAliveNodes()[targetNode]; !ok {
+		return cerror.ErrCaptureNotExist.GenWithStackByArgs(targetNode)
+	}
+	_, err := c.scheduleTable(ctx, id, &messaging.TableScheduleRequest{
+		Action:     messaging.TableScheduleActionMove,
+		TableID:    tableID,
+		TargetNode: targetNode.String(),
+	})
+	return err
+}
+
+// SplitTable splits the spans of the table, the request is sent to the maintainer
+// of the changefeed, and the spans which are being split are returned.
+func (c *Controller) SplitTable(ctx context.Context, id common.ChangeFeedID, tableID int64) ([]*config.TableSpanReplicationStatus, error) {
+	resp, err := c.scheduleTable(ctx, id, &messaging.TableScheduleRequest{
+		Action:  messaging.TableScheduleActionSplit,
+		TableID: tableID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Spans, nil
+}
+
+// scheduleTable sends the table schedule request to the maintainer of the changefeed,
+// and waits for the response.
+func (c *Controller) scheduleTable(
+	ctx context.Context, id common.ChangeFeedID, req *messaging.TableScheduleRequest,
+) (*messaging.TableScheduleResponse, error) {
+	req.RequestID = c.maintainerRequestID.Inc()
+	req.ChangefeedID = id.ToPB()
+	log.Info("send table schedule request to maintainer",
+		zap.String("changefeed", id.Name()),
+		zap.Stringer("action", req.Action),
+		zap.Int64("table", req.TableID),
+		zap.String("targetNode", req.TargetNode))
+	resp, err := c.requestMaintainer(ctx, id, req.RequestID, req)
+	if err != nil {
+		return nil, err
+	}
+	scheduleResp := resp.(*messaging.TableScheduleResponse)
+	if scheduleResp.Error != "" {
+		return nil, cerror.ErrSchedulerRequestFailed.GenWithStackByArgs(scheduleResp.Error)
+	}
+	return scheduleResp, nil
+}
+
+// GetTableSpanStatus queries the replication status of all the table spans from the maintainer of the changefeed.
+func (c *Controller) GetTableSpanStatus(ctx context.Context, id common.ChangeFeedID) ([]*config.TableSpanReplicationStatus, error) {
+	req := &messaging.TableStatusRequest{
+		RequestID:    c.maintainerRequestID.Inc(),
+		ChangefeedID: id.ToPB(),
+	}
+	resp, err := c.requestMaintainer(ctx, id, req.RequestID, req)
+	if err != nil {
+		return nil, err
+	}
+	statusResp := resp.(*messaging.TableStatusResponse)
+	if statusResp.Error != "" {
+		return nil, cerror.ErrSchedulerRequestFailed.GenWithStackByArgs(statusResp.Error)
+	}
+	return statusResp.Spans, nil
+}
+
+// ListPendingDDLs returns the ddls of the changefeed which are waiting to be approved or skipped.
+func (c *Controller) ListPendingDDLs(ctx context.Context, id common.ChangeFeedID) ([]*config.PendingDDL, error) {
+	return c.pendingDDLRequest(ctx, id, messaging.PendingDDLActionList, 0)
+}
+
+// ApprovePendingDDL approves the pending ddl with the block ts, the ddl is executed in the downstream.
+func (c *Controller) ApprovePendingDDL(ctx context.Context, id common.ChangeFeedID, blockTs uint64) error {
+	if _, err := c.pendingDDLRequest(ctx, id, messaging.PendingDDLActionApprove, blockTs); err != nil {
+		return err
+	}
+	return c.savePendingDDLDecision(ctx, id, &config.PendingDDLDecision{BlockTs: blockTs, Approved: true})
+}
+
+// SkipPendingDDL skips the pending ddl with the block ts, the ddl is not executed in the downstream.
+func (c *Controller) SkipPendingDDL(ctx context.Context, id common.ChangeFeedID, blockTs uint64) error {
+	if _, err := c.pendingDDLRequest(ctx, id, messaging.PendingDDLActionSkip, blockTs); err != nil {
+		return err
+	}
+	return c.savePendingDDLDecision(ctx, id, &config.PendingDDLDecision{BlockTs: blockTs, Approved: false})
+}
+
+// savePendingDDLDecision persists the decision with the changefeed info, so the new maintainer
+// applies it again if the changefeed is rescheduled before the ddl is finished.
+// The decisions of the finished ddls are removed at the same time.
+func (c *Controller) savePendingDDLDecision(ctx context.Context, id common.ChangeFeedID, decision *config.PendingDDLDecision) error {
+	c.apiLock.Lock()
+	defer c.apiLock.Unlock()
+
+	cf := c.changefeedDB.GetByID(id)
+	if cf == nil {
+		return errors.New("changefeed not found")
+	}
+	clone, err := cf.GetInfo().Clone()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	checkpointTs := cf.GetStatus().CheckpointTs
+	decisions := make([]*config.PendingDDLDecision, 0, len(clone.PendingDDLDecisions)+1)
+	for _, d := range clone.PendingDDLDecisions {
+		if d.BlockTs > checkpointTs && d.BlockTs != decision.BlockTs {
+			decisions = append(decisions, d)
+		}
+	}
+	clone.PendingDDLDecisions = append(decisions, decision)
+	if err := c.backend.UpdateChangefeed(ctx, clone, checkpointTs, config.ProgressNone); err != nil {
+		return errors.Trace(err)
+	}
+	cf.SetInfo(clone)
+	return nil
+}
+
+func (c *Controller) pendingDDLRequest(
+	ctx context.Context, id common.ChangeFeedID, action messaging.PendingDDLAction, blockTs uint64,
+) ([]*config.PendingDDL, error) {
+	req := &messaging.PendingDDLRequest{
+		RequestID:    c.maintainerRequestID.Inc(),
+		ChangefeedID: id.ToPB(),
+		Action:       action,
+		BlockTs:      blockTs,
+	}
+	if action != messaging.PendingDDLActionList {
+		log.Info("send pending ddl request to maintainer",
+			zap.String("changefeed", id.Name()),
+			zap.Stringer("action", action),
+			zap.Uint64("blockTs", blockTs))
+	}
+	resp, err := c.requestMaintainer(ctx, id, req.RequestID, req)
+	if err != nil {
+		return nil, err
+	}
+	ddlResp := resp.(*messaging.PendingDDLResponse)
+	if ddlResp.Error != "" {
+		return nil, cerror.ErrSchedulerRequestFailed.GenWithStackByArgs(ddlResp.Error)
+	}
+	return ddlResp.DDLs, nil
+}
+
+// requestMaintainer sends the request to the maintainer of the changefeed,
+// and waits for the response with the same request id.
+func (c *Controller) requestMaintainer(
+	ctx context.Context, id common.ChangeFeedID, requestID uint64, req messaging.IOTypeT,
+) (messaging.IOTypeT, error) {
+	c.apiLock.RLock()
+	cf := c.changefeedDB.GetByID(id)
+	c.apiLock.RUnlock()
+	if cf == nil {
+		return nil, cerror.ErrChangeFeedNotExists.GenWithStackByArgs(id.Name())
+	}
+	nodeID := cf.GetNodeID()
+	if nodeID == "" {
+		return nil, cerror.ErrSchedulerRequestFailed.GenWithStackByArgs(
+			fmt.Sprintf("the maintainer of changefeed %s is not running", id.Name()))
+	}
+
+	respCh := make(chan messaging.IOTypeT, 1)
+	c.pendingRequests.Store(requestID, respCh)
+	defer c.pendingRequests.Delete(requestID)
+
+	if err := c.messageCenter.SendCommand(
+		messaging.NewSingleTargetMessage(nodeID, messaging.MaintainerManagerTopic, req)); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	timer := time.NewTimer(maintainerRequestTimeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, errors.Trace(ctx.Err())
+	case <-timer.C:
+		return nil, cerror.ErrSchedulerRequestFailed.GenWithStackByArgs(
+			fmt.Sprintf("wait for the response of maintainer %s timeout, changefeed %s", nodeID, id.Name()))
+	case resp := <-respCh:
+		return resp, nil
+	}
+}
+
+func (c *Controller) onMaintainerResponse(requestID uint64, resp messaging.IOTypeT) {
+	ch, ok := c.pendingRequests.Load(requestID)
+	if !ok {
+		log.Warn("maintainer request not found, ignore the response",
+			zap.Uint64("requestID", requestID))
+		return
+	}
+	select {
+	case ch.(chan messaging.IOTypeT) <- resp:
+	default:
+	}
+}
+
 func (c *Controller) ListChangefeeds(_ context.Context) ([]*config.ChangeFeedInfo, []*config.ChangeFeedStatus, error) {
 	c.apiLock.RLock()
 	defer c.apiLock.RUnlock()
@@ -484,7 +700,11 @@ func (c *Controller) GetChangefeed(_ context.Context, changefeedDisplayName comm
 	if cf == nil {
 		return nil, nil, cerror.ErrChangeFeedNotExists.GenWithStackByArgs(changefeedDisplayName.Name)
 	}
-	return cf.GetInfo(), &config.ChangeFeedStatus{CheckpointTs: cf.GetStatus().CheckpointTs}, nil
+	status := cf.GetStatus()
+	return cf.GetInfo(), &config.ChangeFeedStatus{
+		CheckpointTs:   status.CheckpointTs,
+		DeadLetterRows: status.DeadLetterRows,
+	}, nil
 }
 
 // GetTask queries a task by channgefeed ID, return nil if not found
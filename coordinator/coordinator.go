@@ -218,6 +218,30 @@ func (c *coordinator) UpdateChangefeed(ctx context.Context, change *config.Chang
 	return c.controller.UpdateChangefeed(ctx, change)
 }
 
+func (c *coordinator) MoveTable(ctx context.Context, id common.ChangeFeedID, tableID int64, targetNode node.ID) error {
+	return c.controller.MoveTable(ctx, id, tableID, targetNode)
+}
+
+func (c *coordinator) SplitTable(ctx context.Context, id common.ChangeFeedID, tableID int64) ([]*config.TableSpanReplicationStatus, error) {
+	return c.controller.SplitTable(ctx, id, tableID)
+}
+
+func (c *coordinator) GetTableSpanStatus(ctx context.Context, id common.ChangeFeedID) ([]*config.TableSpanReplicationStatus, error) {
+	return c.controller.GetTableSpanStatus(ctx, id)
+}
+
+func (c *coordinator) ListPendingDDLs(ctx context.Context, id common.ChangeFeedID) ([]*config.PendingDDL, error) {
+	return c.controller.ListPendingDDLs(ctx, id)
+}
+
+func (c *coordinator) ApprovePendingDDL(ctx context.Context, id common.ChangeFeedID, blockTs uint64) error {
+	return c.controller.ApprovePendingDDL(ctx, id, blockTs)
+}
+
+func (c *coordinator) SkipPendingDDL(ctx context.Context, id common.ChangeFeedID, blockTs uint64) error {
+	return c.controller.SkipPendingDDL(ctx, id, blockTs)
+}
+
 func (c *coordinator) ListChangefeeds(ctx context.Context) ([]*config.ChangeFeedInfo, []*config.ChangeFeedStatus, error) {
 	return c.controller.ListChangefeeds(ctx)
 }
@@ -26,7 +26,12 @@ import (
 	"github.com/pingcap/ticdc/pkg/common"
 	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
 	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/filter"
+	"github.com/pingcap/ticdc/pkg/redo"
 	"github.com/pingcap/ticdc/pkg/sink/util"
+	"github.com/pingcap/ticdc/pkg/transformer"
+	timodel "github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
@@ -82,6 +87,11 @@ type Dispatcher struct {
 	componentStatus *ComponentStateWithMutex
 	// the config of filter
 	filterConfig *config.FilterConfig
+	// transformer transforms the rows before they are sent to the sink, nil if there is no transform rule.
+	transformer *transformer.Transformer
+	// bdrMode is true if the changefeed is in BDR mode, the events written by TiCDC are
+	// filtered out by the event service, and only the DDLs of the primary cluster are written.
+	bdrMode bool
 
 	// tableInfo is the latest table info of the dispatcher
 	tableInfo atomic.Pointer[common.TableInfo]
@@ -89,6 +99,11 @@ type Dispatcher struct {
 	// shared by the event dispatcher manager
 	sink sink.Sink
 
+	// redoManager writes the events to the redo log before sending them to the sink,
+	// it's nil if the redo log is disabled.
+	// shared by the event dispatcher manager
+	redoManager *redo.Manager
+
 	// blockStatusesChan use to collector block status of ddl/sync point event to Maintainer
 	// shared by the event dispatcher manager
 	blockStatusesChan chan *heartbeatpb.TableSpanBlockStatus
@@ -110,6 +125,10 @@ type Dispatcher struct {
 
 	// tableProgress is used to calculate the checkpointTs of the dispatcher
 	tableProgress *types.TableProgress
+	// redoProgress tracks the dml events which are sent to the redo log but not flushed to downstream yet.
+	// Events are added to tableProgress only after they are written to the redo log,
+	// so we need redoProgress to make sure the checkpointTs won't exceed these events.
+	redoProgress *types.TableProgress
 
 	// resendTaskMap is store all the resend task of ddl/sync point event current.
 	// When we meet a block event that need to report to maintainer, we will create a resend task and store it in the map(avoid message lost)
@@ -134,12 +153,15 @@ func NewDispatcher(
 	id common.DispatcherID,
 	tableSpan *heartbeatpb.TableSpan,
 	sink sink.Sink,
+	redoManager *redo.Manager,
 	startTs uint64,
 	blockStatusesChan chan *heartbeatpb.TableSpanBlockStatus,
 	schemaID int64,
 	schemaIDToDispatchers *SchemaIDToDispatchers,
 	syncPointConfig *syncpoint.SyncPointConfig,
 	filterConfig *config.FilterConfig,
+	transformer *transformer.Transformer,
+	bdrMode bool,
 	currentPdTs uint64,
 	errCh chan error) *Dispatcher {
 	dispatcher := &Dispatcher{
@@ -147,15 +169,19 @@ func NewDispatcher(
 		id:                    id,
 		tableSpan:             tableSpan,
 		sink:                  sink,
+		redoManager:           redoManager,
 		startTs:               startTs,
 		blockStatusesChan:     blockStatusesChan,
 		syncPointConfig:       syncPointConfig,
 		componentStatus:       newComponentStateWithMutex(heartbeatpb.ComponentState_Working),
 		resolvedTs:            newTsWithMutex(startTs),
 		filterConfig:          filterConfig,
+		transformer:           transformer,
+		bdrMode:               bdrMode,
 		isRemoving:            atomic.Bool{},
 		blockEventStatus:      BlockEventStatus{blockPendingEvent: nil},
 		tableProgress:         types.NewTableProgress(),
+		redoProgress:          types.NewTableProgress(),
 		schemaID:              schemaID,
 		schemaIDToDispatchers: schemaIDToDispatchers,
 		resendTaskMap:         newResendTaskMap(),
@@ -200,7 +226,7 @@ func (d *Dispatcher) HandleDispatcherStatus(dispatcherStatus *heartbeatpb.Dispat
 		if pendingEvent != nil && action.CommitTs == pendingEvent.GetCommitTs() && blockStatus == heartbeatpb.BlockStage_WAITING {
 			d.blockEventStatus.updateBlockStage(heartbeatpb.BlockStage_WRITING)
 			if action.Action == heartbeatpb.Action_Write {
-				err := d.sink.WriteBlockEvent(pendingEvent, d.tableProgress)
+				err := d.writeBlockEvent(pendingEvent)
 				if err != nil {
 					select {
 					case d.errCh <- err:
@@ -257,21 +283,40 @@ func (d *Dispatcher) HandleEvents(dispatcherEvents []DispatcherEvent, wakeCallba
 
 		switch event.GetType() {
 		case commonEvent.TypeResolvedEvent:
-			d.resolvedTs.Set(event.(commonEvent.ResolvedEvent).ResolvedTs)
+			resolvedTs := event.(commonEvent.ResolvedEvent).ResolvedTs
+			d.resolvedTs.Set(resolvedTs)
+			if d.redoManager != nil {
+				d.redoManager.UpdateResolvedTs(d.id, resolvedTs)
+			}
 		case commonEvent.TypeDMLEvent:
 			block = true
 			dml := event.(*commonEvent.DMLEvent)
 			dml.ReplicatingTs = d.creatationPDTs
 			dml.AssembleRows(d.tableInfo.Load())
+			if d.transformer != nil {
+				if err := d.transformer.Transform(dml); err != nil {
+					select {
+					case d.errCh <- err:
+					default:
+						log.Error("error channel is full, discard error",
+							zap.Any("ChangefeedID", d.changefeedID.String()),
+							zap.Any("DispatcherID", d.id.String()),
+							zap.Error(err))
+					}
+					// block the dispatcher, the rows can't be skipped, and
+					// the changefeed is restarted by the error.
+					return true
+				}
+			}
 			dml.AddPostFlushFunc(func() {
 				// Considering dml event in sink may be write to downstream not in order,
 				// thus, we use tableProgress.Empty() to ensure these events are flushed to downstream completely
 				// and wake dynamic stream to handle the next events.
-				if d.tableProgress.Empty() {
+				if d.tableProgress.Empty() && d.redoProgress.Empty() {
 					wakeCallback()
 				}
 			})
-			d.sink.AddDMLEvent(dml, d.tableProgress)
+			d.addDMLEvent(dml)
 		case commonEvent.TypeDDLEvent:
 			if len(dispatcherEvents) != 1 {
 				log.Panic("ddl event should only be singly handled", zap.Any("dispatcherID", d.id))
@@ -305,6 +350,19 @@ func (d *Dispatcher) HandleEvents(dispatcherEvents []DispatcherEvent, wakeCallba
 			d.dealWithBlockEvent(event)
 		case commonEvent.TypeHandshakeEvent:
 			log.Warn("Receive handshake event unexpectedly", zap.Any("event", event), zap.Stringer("dispatcher", d.id))
+		case commonEvent.TypeErrorEvent:
+			err := event.(*commonEvent.ErrorEvent).GetError()
+			select {
+			case d.errCh <- err:
+			default:
+				log.Error("error channel is full, discard error",
+					zap.Any("ChangefeedID", d.changefeedID.String()),
+					zap.Any("DispatcherID", d.id.String()),
+					zap.Error(err))
+			}
+			// block the dispatcher, the event service stops sending events to it,
+			// and the changefeed is restarted by the error.
+			return true
 		default:
 			log.Panic("Unexpected event type", zap.Any("event Type", event.GetType()), zap.Stringer("dispatcher", d.id), zap.Uint64("commitTs", event.GetCommitTs()))
 		}
@@ -328,12 +386,16 @@ func isCompleteSpan(tableSpan *heartbeatpb.TableSpan) bool {
 // shouldBlock check whether the event should be blocked(to wait maintainer response)
 // For the ddl event with more than one blockedTable, it should block.
 // For the ddl event with only one blockedTable, it should block only if the table is not complete span.
+// The ddl event which needs approval should always block.
 // Sync point event should always block.
 func (d *Dispatcher) shouldBlock(event commonEvent.BlockEvent) bool {
 	switch event.GetType() {
 	case commonEvent.TypeDDLEvent:
 		ddlEvent := event.(*commonEvent.DDLEvent)
 		if ddlEvent.BlockedTables != nil {
+			if d.needApproval(event) {
+				return true
+			}
 			switch ddlEvent.GetBlockedTables().InfluenceType {
 			case commonEvent.InfluenceTypeNormal:
 				if len(ddlEvent.GetBlockedTables().TableIDs) > 1 {
@@ -356,12 +418,64 @@ func (d *Dispatcher) shouldBlock(event commonEvent.BlockEvent) bool {
 	return false
 }
 
+// needApproval returns true if the type of the ddl event is in the block ddl config
+// of the changefeed, the maintainer holds the event until it's approved or skipped manually.
+func (d *Dispatcher) needApproval(event commonEvent.BlockEvent) bool {
+	if d.filterConfig == nil || event.GetType() != commonEvent.TypeDDLEvent {
+		return false
+	}
+	ddlEvent := event.(*commonEvent.DDLEvent)
+	return filter.IsBlockedDDL(d.filterConfig.BlockDDL, timodel.ActionType(ddlEvent.Type))
+}
+
+// blockEventQuery returns the query of the ddl event which needs approval.
+func blockEventQuery(event commonEvent.BlockEvent) string {
+	if ddlEvent, ok := event.(*commonEvent.DDLEvent); ok {
+		return ddlEvent.Query
+	}
+	return ""
+}
+
+// addDMLEvent sends the dml event to the sink.
+// If the redo log is enabled, the event is written to the redo log first,
+// and it's sent to the sink after it's flushed to the redo log storage.
+func (d *Dispatcher) addDMLEvent(dml *commonEvent.DMLEvent) {
+	if d.redoManager == nil {
+		d.sink.AddDMLEvent(dml, d.tableProgress)
+		return
+	}
+	d.redoProgress.Add(dml)
+	d.redoManager.AddDMLEvent(d.id, dml, func() {
+		d.sink.AddDMLEvent(dml, d.tableProgress)
+	})
+}
+
+// writeBlockEvent writes the block event to the sink.
+// If the redo log is enabled, the ddl event is written to the redo log first.
+func (d *Dispatcher) writeBlockEvent(event commonEvent.BlockEvent) error {
+	if d.shouldSkipDDL(event) {
+		log.Info("changefeed is in BDR mode and the DDL is not executed by the primary cluster, skip it",
+			zap.Stringer("dispatcher", d.id),
+			zap.String("query", event.(*commonEvent.DDLEvent).Query),
+			zap.String("bdrRole", event.(*commonEvent.DDLEvent).BDRRole),
+			zap.Uint64("commitTs", event.GetCommitTs()))
+		d.sink.PassBlockEvent(event, d.tableProgress)
+		return nil
+	}
+	if d.redoManager != nil && event.GetType() == commonEvent.TypeDDLEvent {
+		if err := d.redoManager.WriteDDLEvent(event.(*commonEvent.DDLEvent)); err != nil {
+			return err
+		}
+	}
+	return d.sink.WriteBlockEvent(event, d.tableProgress)
+}
+
 // 1.If the event is a single table DDL, it will be added to the sink for writing to downstream.
 // If the ddl leads to add new tables or drop tables, it should send heartbeat to maintainer
 // 2. If the event is a multi-table DDL / sync point Event, it will generate a TableSpanBlockStatus message with ddl info to send to maintainer.
 func (d *Dispatcher) dealWithBlockEvent(event commonEvent.BlockEvent) {
 	if !d.shouldBlock(event) {
-		err := d.sink.WriteBlockEvent(event, d.tableProgress)
+		err := d.writeBlockEvent(event)
 		if err != nil {
 			select {
 			case d.errCh <- err:
@@ -407,6 +521,10 @@ func (d *Dispatcher) dealWithBlockEvent(event commonEvent.BlockEvent) {
 				Stage:             heartbeatpb.BlockStage_WAITING,
 			},
 		}
+		if d.needApproval(event) {
+			message.State.NeedApproval = true
+			message.State.Query = blockEventQuery(event)
+		}
 		identifier := BlockEventIdentifier{
 			CommitTs:    event.GetCommitTs(),
 			IsSyncPoint: event.GetType() == commonEvent.TypeSyncPointEvent,
@@ -454,18 +572,39 @@ func (d *Dispatcher) GetResolvedTs() uint64 {
 
 func (d *Dispatcher) GetCheckpointTs() uint64 {
 	checkpointTs, isEmpty := d.tableProgress.GetCheckpointTs()
+	// The events waiting for writing to the redo log are not in tableProgress yet.
+	if redoCheckpointTs, redoEmpty := d.redoProgress.GetCheckpointTs(); !redoEmpty {
+		if isEmpty || redoCheckpointTs < checkpointTs {
+			checkpointTs = redoCheckpointTs
+		}
+		isEmpty = false
+	}
 	if checkpointTs == 0 {
 		// This means the dispatcher has never send events to the sink,
 		// so we use resolvedTs as checkpointTs
-		return d.GetResolvedTs()
+		return d.getFlushedResolvedTs()
 	}
 
 	if isEmpty {
-		return max(checkpointTs, d.GetResolvedTs())
+		return max(checkpointTs, d.getFlushedResolvedTs())
 	}
 	return checkpointTs
 }
 
+// getFlushedResolvedTs returns the resolvedTs which can be used as the checkpointTs
+// when there is no pending event. If the redo log is enabled, it's capped by the
+// resolvedTs flushed to the redo log, otherwise the checkpointTs may exceed the
+// resolvedTs of the redo meta, and the events in between can't be recovered.
+func (d *Dispatcher) getFlushedResolvedTs() uint64 {
+	resolvedTs := d.GetResolvedTs()
+	if d.redoManager != nil {
+		if redoResolvedTs, ok := d.redoManager.GetDispatcherResolvedTs(d.id); ok {
+			resolvedTs = min(resolvedTs, redoResolvedTs)
+		}
+	}
+	return resolvedTs
+}
+
 func (d *Dispatcher) GetId() common.DispatcherID {
 	return d.id
 }
@@ -489,6 +628,20 @@ func (d *Dispatcher) GetSchemaID() int64 {
 	return d.schemaID
 }
 
+// shouldSkipDDL returns true if the event is a DDL which should not be written to the downstream.
+// In BDR mode, the DDLs are received from all the clusters, but only the DDLs executed
+// by the primary cluster are written, the DDLs of the secondary clusters are skipped.
+func (d *Dispatcher) shouldSkipDDL(event commonEvent.BlockEvent) bool {
+	if !d.bdrMode || event.GetType() != commonEvent.TypeDDLEvent {
+		return false
+	}
+	return event.(*commonEvent.DDLEvent).BDRRole != string(ast.BDRRolePrimary)
+}
+
+func (d *Dispatcher) IsBDRMode() bool {
+	return d.bdrMode
+}
+
 func (d *Dispatcher) EnableSyncPoint() bool {
 	return d.syncPointConfig != nil
 }
@@ -554,7 +707,9 @@ func (d *Dispatcher) addToStatusDynamicStream() {
 func (d *Dispatcher) TryClose() (w heartbeatpb.Watermark, ok bool) {
 	// If sink is normal(not meet error), we need to wait all the events in sink to flushed downstream successfully.
 	// If sink is not normal, we can close the dispatcher immediately.
-	if (d.sink.IsNormal() && d.tableProgress.Empty()) || !d.sink.IsNormal() {
+	// If redo manager is not normal, the events waiting for writing to the redo log can't be flushed anymore.
+	if (d.sink.IsNormal() && d.tableProgress.Empty() && d.redoProgress.Empty()) || !d.sink.IsNormal() ||
+		(d.redoManager != nil && !d.redoManager.IsNormal()) {
 		w.CheckpointTs = d.GetCheckpointTs()
 		w.ResolvedTs = d.GetResolvedTs()
 
@@ -587,7 +742,7 @@ func (d *Dispatcher) GetBlockEventStatus() *heartbeatpb.State {
 	// 2. maintainer can get current available tables based on table trigger event dispatcher's startTs,
 	//    so don't need to do extra add and drop actions.
 
-	return &heartbeatpb.State{
+	state := &heartbeatpb.State{
 		IsBlocked:         true,
 		BlockTs:           pendingEvent.GetCommitTs(),
 		BlockTables:       pendingEvent.GetBlockedTables().ToPB(),
@@ -597,6 +752,11 @@ func (d *Dispatcher) GetBlockEventStatus() *heartbeatpb.State {
 		IsSyncPoint:       pendingEvent.GetType() == commonEvent.TypeSyncPointEvent,         // sync point event must should block
 		Stage:             blockStage,
 	}
+	if d.needApproval(pendingEvent) {
+		state.NeedApproval = true
+		state.Query = blockEventQuery(pendingEvent)
+	}
+	return state
 }
 
 func (d *Dispatcher) GetHeartBeatInfo(h *HeartBeatInfo) {
@@ -611,4 +771,7 @@ func (d *Dispatcher) GetHeartBeatInfo(h *HeartBeatInfo) {
 
 func (d *Dispatcher) HandleCheckpointTs(checkpointTs uint64) {
 	d.sink.AddCheckpointTs(checkpointTs)
+	if d.redoManager != nil {
+		d.redoManager.UpdateCheckpointTs(checkpointTs)
+	}
 }
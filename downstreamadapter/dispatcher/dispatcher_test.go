@@ -10,6 +10,7 @@ import (
 	"github.com/pingcap/ticdc/heartbeatpb"
 	"github.com/pingcap/ticdc/pkg/common"
 	sinkutil "github.com/pingcap/ticdc/pkg/sink/util"
+	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tiflow/pkg/spanz"
 
 	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
@@ -21,6 +22,10 @@ import (
 type mockSink struct {
 	dmls     []*commonEvent.DMLEvent
 	isNormal bool
+	// writtenBlockEvents and passedBlockEvents are the block events
+	// written to and passed by the sink.
+	writtenBlockEvents []commonEvent.BlockEvent
+	passedBlockEvents  []commonEvent.BlockEvent
 }
 
 func (s *mockSink) AddDMLEvent(event *commonEvent.DMLEvent, tableProgress *types.TableProgress) {
@@ -30,12 +35,14 @@ func (s *mockSink) AddDMLEvent(event *commonEvent.DMLEvent, tableProgress *types
 
 func (s *mockSink) WriteBlockEvent(event commonEvent.BlockEvent, tableProgress *types.TableProgress) error {
 	tableProgress.Add(event)
+	s.writtenBlockEvents = append(s.writtenBlockEvents, event)
 	event.PostFlush()
 	return nil
 }
 
 func (s *mockSink) PassBlockEvent(event commonEvent.BlockEvent, tableProgress *types.TableProgress) {
 	tableProgress.Pass(event)
+	s.passedBlockEvents = append(s.passedBlockEvents, event)
 	event.PostFlush()
 }
 
@@ -101,6 +108,7 @@ func newDispatcherForTest(sink sink.Sink, tableSpan *heartbeatpb.TableSpan) *Dis
 		common.NewDispatcherID(),
 		tableSpan,
 		sink,
+		nil,          // redoManager
 		common.Ts(0), // startTs
 		make(chan *heartbeatpb.TableSpanBlockStatus, 128),
 		1, // schemaID
@@ -110,6 +118,8 @@ func newDispatcherForTest(sink sink.Sink, tableSpan *heartbeatpb.TableSpan) *Dis
 			SyncPointRetention: time.Duration(10 * time.Minute),
 		}, // syncPointConfig
 		nil,          //filterConfig
+		nil,          //transformer
+		false,        //bdrMode
 		common.Ts(0), //pdTs
 		make(chan error, 1),
 	)
@@ -561,3 +571,33 @@ func TestDispatcherClose(t *testing.T) {
 		require.Equal(t, uint64(0), watermark.ResolvedTs)
 	}
 }
+
+// test the ddls of the secondary clusters are skipped in BDR mode
+func TestDispatcherSkipDDLInBDRMode(t *testing.T) {
+	sink := newMockSink()
+	dispatcher := newDispatcherForTest(sink, getCompleteTableSpan())
+
+	primaryDDL := &commonEvent.DDLEvent{FinishedTs: 2, BDRRole: string(ast.BDRRolePrimary)}
+	secondaryDDL := &commonEvent.DDLEvent{FinishedTs: 3, BDRRole: string(ast.BDRRoleSecondary)}
+	noRoleDDL := &commonEvent.DDLEvent{FinishedTs: 4}
+	syncPoint := &commonEvent.SyncPointEvent{CommitTs: 5}
+
+	// Case 1: all the block events are written if it's not in BDR mode
+	for _, event := range []commonEvent.BlockEvent{primaryDDL, secondaryDDL, noRoleDDL, syncPoint} {
+		require.False(t, dispatcher.shouldSkipDDL(event))
+	}
+
+	// Case 2: only the ddls executed by the primary cluster are written in BDR mode
+	dispatcher.bdrMode = true
+	require.False(t, dispatcher.shouldSkipDDL(primaryDDL))
+	require.True(t, dispatcher.shouldSkipDDL(secondaryDDL))
+	require.True(t, dispatcher.shouldSkipDDL(noRoleDDL))
+	require.False(t, dispatcher.shouldSkipDDL(syncPoint))
+
+	// Case 3: the skipped ddl is passed by the sink instead of being written
+	require.NoError(t, dispatcher.writeBlockEvent(secondaryDDL))
+	require.Equal(t, []commonEvent.BlockEvent{secondaryDDL}, sink.passedBlockEvents)
+	require.Empty(t, sink.writtenBlockEvents)
+	require.NoError(t, dispatcher.writeBlockEvent(primaryDDL))
+	require.Equal(t, []commonEvent.BlockEvent{primaryDDL}, sink.writtenBlockEvents)
+}
@@ -37,6 +37,8 @@ import (
 	appcontext "github.com/pingcap/ticdc/pkg/common/context"
 	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/ticdc/pkg/redo"
+	"github.com/pingcap/ticdc/pkg/transformer"
 	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 )
@@ -65,6 +67,10 @@ type EventDispatcherManager struct {
 	sink         sink.Sink
 	maintainerID node.ID
 
+	// redoManager writes the events of all the dispatchers to the redo log,
+	// only not nil when the redo log is enabled.
+	redoManager *redo.Manager
+
 	// statusesChan will fetch the tableSpan status that need to contains in the heartbeat info.
 	statusesChan chan *heartbeatpb.TableSpanStatus
 	// blockStatusesChan will fetch the tableSpan block status about ddl event and sync point event
@@ -73,6 +79,10 @@ type EventDispatcherManager struct {
 
 	filter filter.Filter
 
+	// transformer transforms the rows of all the dispatchers before they are sent to the sink,
+	// only not nil when the changefeed has transform rules.
+	transformer *transformer.Transformer
+
 	closing bool
 	closed  atomic.Bool
 
@@ -142,11 +152,21 @@ func NewEventDispatcherManager(changefeedID common.ChangeFeedID,
 	}
 	manager.filter = filter
 
+	manager.transformer, err = transformer.NewTransformer(cfConfig.Transforms, replicaConfig.CaseSensitive)
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+
 	err = manager.initSink(ctx)
 	if err != nil {
 		return nil, 0, errors.Trace(err)
 	}
 
+	err = manager.initRedoManager()
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+
 	// Register Event Dispatcher Manager in HeartBeatCollector,
 	// which is responsible for communication with the maintainer.
 	err = appcontext.GetService[*HeartBeatCollector](appcontext.HeartbeatCollector).RegisterEventDispatcherManager(manager)
@@ -192,6 +212,35 @@ func (e *EventDispatcherManager) initSink(ctx context.Context) error {
 	return nil
 }
 
+// initRedoManager creates the redo manager if the redo log is enabled.
+// The redo manager is not bound to the context of the event dispatcher manager,
+// because the dispatchers need it to flush the pending events when closing.
+// It's closed after all the dispatchers are closed.
+func (e *EventDispatcherManager) initRedoManager() error {
+	if !redo.IsConsistentEnabled(e.config.Consistent) {
+		return nil
+	}
+	redoManager, err := redo.NewManager(context.Background(), e.changefeedID, appcontext.GetID(), e.config.Consistent)
+	if err != nil {
+		return err
+	}
+	e.redoManager = redoManager
+
+	go func() {
+		err := redoManager.Run()
+		if err != nil && errors.Cause(err) != context.Canceled {
+			select {
+			case e.errCh <- err:
+			default:
+				log.Error("error channel is full, discard error",
+					zap.Stringer("changefeedID", e.changefeedID),
+					zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
 func (e *EventDispatcherManager) TryClose(remove bool) bool {
 	if !e.closing {
 		e.closing = true
@@ -236,6 +285,10 @@ func (e *EventDispatcherManager) close(remove bool) {
 		return
 	}
 
+	if e.redoManager != nil {
+		e.redoManager.Close(remove)
+	}
+
 	metrics.CreateDispatcherDuration.DeleteLabelValues(e.changefeedID.Namespace(), e.changefeedID.Name())
 	metrics.EventDispatcherManagerCheckpointTsGauge.DeleteLabelValues(e.changefeedID.Namespace(), e.changefeedID.Name())
 	metrics.EventDispatcherManagerResolvedTsGauge.DeleteLabelValues(e.changefeedID.Namespace(), e.changefeedID.Name())
@@ -326,15 +379,22 @@ func (e *EventDispatcherManager) newDispatchers(infos []dispatcherCreateInfo) er
 		d := dispatcher.NewDispatcher(
 			e.changefeedID,
 			id, tableSpans[idx], e.sink,
+			e.redoManager,
 			uint64(newStartTsList[idx]),
 			e.blockStatusesChan,
 			schemaIds[idx],
 			e.schemaIDToDispatchers,
 			e.syncPointConfig,
 			e.config.Filter,
+			e.transformer,
+			e.config.BDRMode,
 			pdTsList[idx],
 			e.errCh)
 
+		if e.redoManager != nil {
+			e.redoManager.AddDispatcher(id, uint64(newStartTsList[idx]))
+		}
+
 		if e.heartBeatTask == nil {
 			e.heartBeatTask = newHeartBeatTask(e)
 		}
@@ -503,6 +563,7 @@ func (e *EventDispatcherManager) aggregateDispatcherHeartbeats(needCompleteStatu
 
 	toRemoveDispatcherIDs := make([]common.DispatcherID, 0)
 	removedDispatcherSchemaIDs := make([]int64, 0)
+	removedDispatcherTableIDs := make([]int64, 0)
 	heartBeatInfo := &dispatcher.HeartBeatInfo{}
 
 	e.dispatcherMap.ForEach(func(id common.DispatcherID, dispatcherItem *dispatcher.Dispatcher) {
@@ -523,6 +584,7 @@ func (e *EventDispatcherManager) aggregateDispatcherHeartbeats(needCompleteStatu
 				})
 				toRemoveDispatcherIDs = append(toRemoveDispatcherIDs, id)
 				removedDispatcherSchemaIDs = append(removedDispatcherSchemaIDs, dispatcherItem.GetSchemaID())
+				removedDispatcherTableIDs = append(removedDispatcherTableIDs, dispatcherItem.GetTableSpan().TableID)
 			}
 		}
 
@@ -532,13 +594,17 @@ func (e *EventDispatcherManager) aggregateDispatcherHeartbeats(needCompleteStatu
 				ID:                 id.ToPB(),
 				ComponentStatus:    heartBeatInfo.ComponentStatus,
 				CheckpointTs:       heartBeatInfo.Watermark.CheckpointTs,
+				ResolvedTs:         heartBeatInfo.Watermark.ResolvedTs,
 				EventSizePerSecond: heartBeatInfo.EventSizePerSecond,
 			})
 		}
 	})
 
 	for idx, id := range toRemoveDispatcherIDs {
-		e.cleanTableEventDispatcher(id, removedDispatcherSchemaIDs[idx])
+		e.cleanTableEventDispatcher(id, removedDispatcherSchemaIDs[idx], removedDispatcherTableIDs[idx])
+	}
+	if deadLetterSink, ok := e.sink.(sink.DeadLetterSink); ok {
+		message.DeadLetterRows = deadLetterSink.DeadLetterRows()
 	}
 
 	e.metricCheckpointTs.Set(float64(message.Watermark.CheckpointTs))
@@ -569,9 +635,16 @@ func (e *EventDispatcherManager) removeDispatcher(id common.DispatcherID) {
 }
 
 // cleanTableEventDispatcher is called when the dispatcher is removed successfully.
-func (e *EventDispatcherManager) cleanTableEventDispatcher(id common.DispatcherID, schemaID int64) {
+func (e *EventDispatcherManager) cleanTableEventDispatcher(id common.DispatcherID, schemaID int64, tableID int64) {
 	e.dispatcherMap.Delete(id)
 	e.schemaIDToDispatchers.Delete(schemaID, id)
+	if e.redoManager != nil {
+		e.redoManager.RemoveDispatcher(id)
+	}
+	// the other dispatchers of the same table rebuild the transformation when they need it.
+	if e.transformer != nil {
+		e.transformer.RemoveTable(tableID)
+	}
 	if e.tableTriggerEventDispatcher != nil && e.tableTriggerEventDispatcher.GetId() == id {
 		e.tableTriggerEventDispatcher = nil
 	}
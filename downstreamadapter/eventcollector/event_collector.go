@@ -226,6 +226,7 @@ func (c *EventCollector) mustSendDispatcherRequest(req DispatcherRequest) error
 	if req.ActionType == eventpb.ActionType_ACTION_TYPE_REGISTER ||
 		req.ActionType == eventpb.ActionType_ACTION_TYPE_RESET {
 		message.RegisterDispatcherRequest.FilterConfig = req.Dispatcher.GetFilterConfig()
+		message.RegisterDispatcherRequest.BdrMode = req.Dispatcher.IsBDRMode()
 		message.RegisterDispatcherRequest.EnableSyncPoint = req.Dispatcher.EnableSyncPoint()
 		message.RegisterDispatcherRequest.SyncPointInterval = uint64(req.Dispatcher.GetSyncPointInterval().Seconds())
 		message.RegisterDispatcherRequest.SyncPointTs = syncpoint.CalculateStartSyncPointTs(req.StartTs, req.Dispatcher.GetSyncPointInterval())
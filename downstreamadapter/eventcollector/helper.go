@@ -98,6 +98,7 @@ const (
 	DataGroupDDL             = 2
 	DataGroupSyncPoint       = 3
 	DataGroupHandshake       = 4
+	DataGroupError           = 5
 )
 
 func (h *EventsHandler) GetType(event dispatcher.DispatcherEvent) dynstream.EventType {
@@ -112,6 +113,8 @@ func (h *EventsHandler) GetType(event dispatcher.DispatcherEvent) dynstream.Even
 		return dynstream.EventType{DataGroup: DataGroupSyncPoint, Property: dynstream.NonBatchable}
 	case commonEvent.TypeHandshakeEvent:
 		return dynstream.EventType{DataGroup: DataGroupHandshake, Property: dynstream.NonBatchable}
+	case commonEvent.TypeErrorEvent:
+		return dynstream.EventType{DataGroup: DataGroupError, Property: dynstream.NonBatchable}
 	default:
 		log.Panic("unknown event type", zap.Int("type", int(event.GetType())))
 	}
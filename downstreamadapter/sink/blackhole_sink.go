@@ -0,0 +1,77 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/types"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/sink/util"
+	"go.uber.org/zap"
+)
+
+// BlackHoleSink drops all the events, it's used to test the performance of
+// the upstream part of the changefeed without the cost of the downstream.
+type BlackHoleSink struct {
+	changefeedID common.ChangeFeedID
+}
+
+func NewBlackHoleSink(changefeedID common.ChangeFeedID) *BlackHoleSink {
+	return &BlackHoleSink{changefeedID: changefeedID}
+}
+
+func (s *BlackHoleSink) SinkType() SinkType {
+	return BlackHoleSinkType
+}
+
+func (s *BlackHoleSink) IsNormal() bool {
+	return true
+}
+
+func (s *BlackHoleSink) AddDMLEvent(event *commonEvent.DMLEvent, tableProgress *types.TableProgress) {
+	if event.Len() == 0 {
+		return
+	}
+	tableProgress.Add(event)
+	event.PostFlush()
+}
+
+func (s *BlackHoleSink) PassBlockEvent(event commonEvent.BlockEvent, tableProgress *types.TableProgress) {
+	tableProgress.Pass(event)
+	event.PostFlush()
+}
+
+func (s *BlackHoleSink) WriteBlockEvent(event commonEvent.BlockEvent, tableProgress *types.TableProgress) error {
+	tableProgress.Add(event)
+	log.Debug("black hole sink drops the block event",
+		zap.String("namespace", s.changefeedID.Namespace()),
+		zap.String("changefeed", s.changefeedID.Name()),
+		zap.Any("eventType", event.GetType()),
+		zap.Uint64("commitTs", event.GetCommitTs()))
+	event.PostFlush()
+	return nil
+}
+
+func (s *BlackHoleSink) AddCheckpointTs(_ uint64) {}
+
+func (s *BlackHoleSink) SetTableSchemaStore(_ *util.TableSchemaStore) {}
+
+func (s *BlackHoleSink) CheckStartTsList(_ []int64, startTsList []int64) ([]int64, error) {
+	return startTsList, nil
+}
+
+func (s *BlackHoleSink) Close(_ bool) error {
+	return nil
+}
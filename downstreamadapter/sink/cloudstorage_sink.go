@@ -0,0 +1,208 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/helper"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/types"
+	"github.com/pingcap/ticdc/downstreamadapter/worker"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	ticonfig "github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/ticdc/pkg/sink/cloudstorage"
+	"github.com/pingcap/ticdc/pkg/sink/util"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	putil "github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// CloudStorageSink is responsible for writing data to the external storage,
+// such as S3, GCS, Azure Blob Storage and local file system.
+// The DML events are written as data files, the DDL events are written as
+// schema files and the checkpoint ts is written to the metadata file.
+type CloudStorageSink struct {
+	changefeedID common.ChangeFeedID
+
+	dmlWorker *worker.CloudStorageDMLWorker
+	ddlWorker *worker.CloudStorageDDLWorker
+
+	storage    storage.ExternalStorage
+	statistics *metrics.Statistics
+
+	errgroup *errgroup.Group
+	errCh    chan error
+	isNormal uint32 // if sink is normal, isNormal is 1, otherwise is 0
+}
+
+func (s *CloudStorageSink) SinkType() SinkType {
+	return CloudStorageSinkType
+}
+
+func NewCloudStorageSink(ctx context.Context, changefeedID common.ChangeFeedID, sinkURI *url.URL, sinkConfig *ticonfig.SinkConfig, errCh chan error) (*CloudStorageSink, error) {
+	// create cloud storage config and then apply the params of sinkURI to it.
+	cfg := cloudstorage.NewConfig()
+	err := cfg.Apply(ctx, sinkURI, sinkConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// fetch protocol from replicaConfig defined by changefeed config file.
+	protocol, err := helper.GetProtocol(
+		putil.GetOrZero(sinkConfig.Protocol),
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// get cloud storage file extension according to the specific protocol.
+	ext := helper.GetFileExtension(protocol)
+	// the last param maxMsgBytes is mainly to limit the size of a single message for
+	// batch protocols in mq scenario. In cloud storage sink, we just set it to max int.
+	encoderConfig, err := util.GetEncoderConfig(changefeedID, sinkURI, protocol, sinkConfig, math.MaxInt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// create an external storage.
+	storage, err := putil.GetExternalStorageFromURI(ctx, sinkURI.String())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	errGroup, ctx := errgroup.WithContext(ctx)
+	statistics := metrics.NewStatistics(changefeedID, "CloudStorageSink")
+	dmlWorker, err := worker.NewCloudStorageDMLWorker(ctx, changefeedID, storage, cfg, encoderConfig, ext, statistics, errGroup)
+	if err != nil {
+		storage.Close()
+		return nil, cerror.WrapError(cerror.ErrStorageSinkInvalidConfig, err)
+	}
+	ddlWorker := worker.NewCloudStorageDDLWorker(ctx, changefeedID, sinkURI, cfg, storage, statistics, errGroup)
+
+	sink := &CloudStorageSink{
+		changefeedID: changefeedID,
+		dmlWorker:    dmlWorker,
+		ddlWorker:    ddlWorker,
+		storage:      storage,
+		statistics:   statistics,
+		errgroup:     errGroup,
+		errCh:        errCh,
+		isNormal:     1,
+	}
+	if err := ddlWorker.Run(); err != nil {
+		sink.Close(false)
+		return nil, errors.Trace(err)
+	}
+	go sink.run()
+	return sink, nil
+}
+
+func (s *CloudStorageSink) run() {
+	s.dmlWorker.Run()
+
+	err := s.errgroup.Wait()
+	if errors.Cause(err) != context.Canceled {
+		atomic.StoreUint32(&s.isNormal, 0)
+		select {
+		case s.errCh <- err:
+		default:
+			log.Error("error channel is full, discard error",
+				zap.Any("ChangefeedID", s.changefeedID.String()),
+				zap.Error(err))
+		}
+	}
+}
+
+func (s *CloudStorageSink) IsNormal() bool {
+	return atomic.LoadUint32(&s.isNormal) == 1
+}
+
+func (s *CloudStorageSink) AddDMLEvent(event *commonEvent.DMLEvent, tableProgress *types.TableProgress) {
+	if event.Len() == 0 {
+		return
+	}
+	tableProgress.Add(event)
+	s.dmlWorker.AddDMLEvent(event)
+}
+
+func (s *CloudStorageSink) PassBlockEvent(event commonEvent.BlockEvent, tableProgress *types.TableProgress) {
+	tableProgress.Pass(event)
+	event.PostFlush()
+}
+
+func (s *CloudStorageSink) WriteBlockEvent(event commonEvent.BlockEvent, tableProgress *types.TableProgress) error {
+	tableProgress.Add(event)
+	switch event := event.(type) {
+	case *commonEvent.DDLEvent:
+		if event.TiDBOnly {
+			// run callback directly and return
+			event.PostFlush()
+			return nil
+		}
+		err := s.ddlWorker.WriteBlockEvent(event)
+		if err != nil {
+			atomic.StoreUint32(&s.isNormal, 0)
+			return errors.Trace(err)
+		}
+		event.PostFlush()
+	case *commonEvent.SyncPointEvent:
+		log.Error("CloudStorageSink doesn't support Sync Point Event",
+			zap.String("namespace", s.changefeedID.Namespace()),
+			zap.String("changefeed", s.changefeedID.Name()),
+			zap.Any("event", event))
+	default:
+		log.Error("CloudStorageSink doesn't support this type of block event",
+			zap.String("namespace", s.changefeedID.Namespace()),
+			zap.String("changefeed", s.changefeedID.Name()),
+			zap.Any("event type", event.GetType()))
+	}
+	return nil
+}
+
+func (s *CloudStorageSink) AddCheckpointTs(ts uint64) {
+	s.ddlWorker.GetCheckpointTsChan() <- ts
+}
+
+func (s *CloudStorageSink) SetTableSchemaStore(tableSchemaStore *util.TableSchemaStore) {
+	s.ddlWorker.SetTableSchemaStore(tableSchemaStore)
+}
+
+func (s *CloudStorageSink) Close(removeDDLTsItem bool) error {
+	err := s.ddlWorker.Close()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = s.dmlWorker.Close()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	s.storage.Close()
+	s.statistics.Close()
+	return nil
+}
+
+func (s *CloudStorageSink) CheckStartTsList(tableIds []int64, startTsList []int64) ([]int64, error) {
+	return startTsList, nil
+}
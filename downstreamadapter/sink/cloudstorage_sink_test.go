@@ -0,0 +1,129 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/ticdc/downstreamadapter/sink/types"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func newCloudStorageSinkForTest(t *testing.T, parentDir string) *CloudStorageSink {
+	uri := fmt.Sprintf("file:///%s?protocol=csv&flush-interval=2s", parentDir)
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	sinkConfig := config.GetDefaultReplicaConfig().Clone().Sink
+	sinkConfig.Protocol = util.AddressOf("csv")
+	sinkConfig.DateSeparator = util.AddressOf(config.DateSeparatorNone.String())
+	sinkConfig.FileIndexWidth = util.AddressOf(6)
+
+	changefeedID := common.ChangefeedID4Test("test", "test")
+	errCh := make(chan error, 16)
+	sink, err := NewCloudStorageSink(context.Background(), changefeedID, sinkURI, sinkConfig, errCh)
+	require.NoError(t, err)
+	return sink
+}
+
+func TestCloudStorageSinkBasicFunctionality(t *testing.T) {
+	parentDir := t.TempDir()
+	sink := newCloudStorageSinkForTest(t, parentDir)
+	defer sink.Close(false)
+	require.Equal(t, CloudStorageSinkType, sink.SinkType())
+	require.True(t, sink.IsNormal())
+
+	var count atomic.Int64
+
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32));")
+	require.NotNil(t, job)
+	tableInfo := helper.GetTableInfo(job)
+
+	ddlEvent := &commonEvent.DDLEvent{
+		Query:      job.Query,
+		Type:       byte(job.Type),
+		SchemaName: job.SchemaName,
+		TableName:  job.TableName,
+		TableInfo:  tableInfo,
+		FinishedTs: 1,
+		BlockedTables: &commonEvent.InfluencedTables{
+			InfluenceType: commonEvent.InfluenceTypeNormal,
+			TableIDs:      []int64{0},
+		},
+		NeedAddedTables: []commonEvent.Table{{TableID: 1, SchemaID: 1}},
+		PostTxnFlushed: []func(){
+			func() { count.Inc() },
+		},
+	}
+
+	dmlEvent := helper.DML2Event("test", "t", "insert into t values (1, 'test')", "insert into t values (2, 'test2');")
+	dmlEvent.PostTxnFlushed = []func(){
+		func() { count.Inc() },
+	}
+	dmlEvent.CommitTs = 2
+
+	tableProgress := types.NewTableProgress()
+	err := sink.WriteBlockEvent(ddlEvent, tableProgress)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count.Load())
+
+	tableDir := path.Join(parentDir, "test/t", fmt.Sprintf("%d", tableInfo.UpdateTS))
+	schemaFiles, err := filepath.Glob(path.Join(parentDir, "test/t/meta/schema_*.json"))
+	require.NoError(t, err)
+	require.Len(t, schemaFiles, 1)
+
+	sink.AddDMLEvent(dmlEvent, tableProgress)
+	// the data file is written after the flush interval.
+	require.Eventually(t, func() bool {
+		return count.Load() == 2
+	}, 10*time.Second, 100*time.Millisecond)
+
+	content, err := os.ReadFile(path.Join(tableDir, "CDC000001.csv"))
+	require.NoError(t, err)
+	require.Equal(t, "\"I\",\"t\",\"test\",1,\"test\"\r\n\"I\",\"t\",\"test\",2,\"test2\"\r\n", string(content))
+	index, err := os.ReadFile(path.Join(tableDir, "meta/CDC.index"))
+	require.NoError(t, err)
+	require.Equal(t, "CDC000001.csv\n", string(index))
+
+	// the checkpoint is written at most once every two seconds.
+	require.Eventually(t, func() bool {
+		sink.AddCheckpointTs(3)
+		metadata, err := os.ReadFile(path.Join(parentDir, "metadata"))
+		if err != nil {
+			return false
+		}
+		return strings.Contains(string(metadata), "\"checkpoint-ts\":3")
+	}, 10*time.Second, 500*time.Millisecond)
+
+	ts, isEmpty := tableProgress.GetCheckpointTs()
+	require.Equal(t, uint64(1), ts)
+	require.True(t, isEmpty)
+	require.True(t, sink.IsNormal())
+}
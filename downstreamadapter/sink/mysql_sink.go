@@ -30,6 +30,8 @@ import (
 	"github.com/pingcap/ticdc/pkg/sink/mysql"
 	"github.com/pingcap/ticdc/pkg/sink/util"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tiflow/pkg/causality"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -38,7 +40,10 @@ import (
 )
 
 const (
-	prime = 31
+	// conflictDetectorSlots is the number of the slots used by the conflict detector.
+	conflictDetectorSlots uint64 = 16 * 1024
+	// txnCacheSize is the max number of the resolved transactions cached for each dml worker.
+	txnCacheSize = 1024
 )
 
 // MysqlSink is responsible for writing data to mysql downstream.
@@ -49,6 +54,10 @@ type MysqlSink struct {
 	ddlWorker   *worker.MysqlDDLWorker
 	dmlWorker   []*worker.MysqlDMLWorker
 	workerCount int
+	// conflictDetector dispatches the dml events to the dml workers,
+	// the events which modify the same primary key or unique key are
+	// dispatched in order, and the others can be executed concurrently.
+	conflictDetector *causality.ConflictDetector[*commonEvent.DMLEvent]
 
 	db         *sql.DB
 	errgroup   *errgroup.Group
@@ -59,56 +68,64 @@ type MysqlSink struct {
 }
 
 func NewMysqlSink(ctx context.Context, changefeedID common.ChangeFeedID, workerCount int, config *config.ChangefeedConfig, sinkURI *url.URL, errCh chan error) (*MysqlSink, error) {
-	errgroup, ctx := errgroup.WithContext(ctx)
-	mysqlSink := MysqlSink{
-		changefeedID: changefeedID,
-		dmlWorker:    make([]*worker.MysqlDMLWorker, workerCount),
-		workerCount:  workerCount,
-		errgroup:     errgroup,
-		statistics:   metrics.NewStatistics(changefeedID, "TxnSink"),
-		errCh:        errCh,
-		isNormal:     1,
-	}
-
 	cfg, db, err := mysql.NewMysqlConfigAndDB(ctx, changefeedID, sinkURI)
 	if err != nil {
 		return nil, err
 	}
 	cfg.SyncPointRetention = utils.GetOrZero(config.SyncPointRetention)
-
-	for i := 0; i < workerCount; i++ {
-		mysqlSink.dmlWorker[i] = worker.NewMysqlDMLWorker(ctx, db, cfg, i, mysqlSink.changefeedID, errgroup, mysqlSink.statistics)
+	// the source id is written to the downstream for every transaction,
+	// so the upstream of the reverse changefeed in BDR mode can filter them out.
+	if config.TiDBSourceID != 0 {
+		cfg.SourceID = config.TiDBSourceID
 	}
-	mysqlSink.ddlWorker = worker.NewMysqlDDLWorker(ctx, db, cfg, mysqlSink.changefeedID, errgroup, mysqlSink.statistics)
-	mysqlSink.db = db
-
-	go mysqlSink.run()
-
-	return &mysqlSink, nil
+	if config.SinkConfig != nil && config.SinkConfig.MySQLConfig != nil {
+		if deadLetter := config.SinkConfig.MySQLConfig.DeadLetter; deadLetter != nil && utils.GetOrZero(deadLetter.Enable) {
+			cfg.DeadLetterEnable = true
+			cfg.DeadLetterTable = utils.GetOrZero(deadLetter.Table)
+			cfg.DeadLetterStorageURI = utils.GetOrZero(deadLetter.StorageURI)
+		}
+	}
+	if config.BDRMode && !cfg.IsWriteSourceExisted {
+		db.Close()
+		return nil, cerror.ErrSinkURIInvalid.GenWithStackByArgs(
+			"the downstream does not support the tidb_cdc_write_source variable required by bdr mode")
+	}
+	return newMysqlSink(ctx, changefeedID, workerCount, cfg, db, errCh), nil
 }
 
 // for test
 func NewMysqlSinkWithDBAndConfig(ctx context.Context, changefeedID common.ChangeFeedID, workerCount int, cfg *mysql.MysqlConfig, db *sql.DB, errCh chan error) (*MysqlSink, error) {
+	return newMysqlSink(ctx, changefeedID, workerCount, cfg, db, errCh), nil
+}
+
+func newMysqlSink(ctx context.Context, changefeedID common.ChangeFeedID, workerCount int, cfg *mysql.MysqlConfig, db *sql.DB, errCh chan error) *MysqlSink {
 	errgroup, ctx := errgroup.WithContext(ctx)
+	conflictDetector := causality.NewConflictDetector[*commonEvent.DMLEvent](conflictDetectorSlots, causality.TxnCacheOption{
+		Count:         workerCount,
+		Size:          txnCacheSize,
+		BlockStrategy: causality.BlockStrategyWaitEmpty,
+	})
 	mysqlSink := MysqlSink{
-		changefeedID: changefeedID,
-		dmlWorker:    make([]*worker.MysqlDMLWorker, workerCount),
-		workerCount:  workerCount,
-		errgroup:     errgroup,
-		statistics:   metrics.NewStatistics(changefeedID, "TxnSink"),
-		errCh:        errCh,
-		isNormal:     1,
+		changefeedID:     changefeedID,
+		dmlWorker:        make([]*worker.MysqlDMLWorker, workerCount),
+		workerCount:      workerCount,
+		conflictDetector: conflictDetector,
+		errgroup:         errgroup,
+		statistics:       metrics.NewStatistics(changefeedID, "TxnSink"),
+		errCh:            errCh,
+		isNormal:         1,
 	}
 
 	for i := 0; i < workerCount; i++ {
-		mysqlSink.dmlWorker[i] = worker.NewMysqlDMLWorker(ctx, db, cfg, i, mysqlSink.changefeedID, errgroup, mysqlSink.statistics)
+		txnCh := mysqlSink.conflictDetector.GetOutChByCacheID(int64(i))
+		mysqlSink.dmlWorker[i] = worker.NewMysqlDMLWorker(ctx, db, cfg, i, mysqlSink.changefeedID, errgroup, mysqlSink.statistics, txnCh)
 	}
 	mysqlSink.ddlWorker = worker.NewMysqlDDLWorker(ctx, db, cfg, mysqlSink.changefeedID, errgroup, mysqlSink.statistics)
 	mysqlSink.db = db
 
 	go mysqlSink.run()
 
-	return &mysqlSink, nil
+	return &mysqlSink
 }
 
 func (s *MysqlSink) run() {
@@ -128,6 +145,10 @@ func (s *MysqlSink) run() {
 	}
 }
 
+func (s *MysqlSink) DeadLetterRows() uint64 {
+	return s.statistics.DeadLetterRows()
+}
+
 func (s *MysqlSink) IsNormal() bool {
 	value := atomic.LoadUint32(&s.isNormal) == 1
 	return value
@@ -147,12 +168,7 @@ func (s *MysqlSink) AddDMLEvent(event *commonEvent.DMLEvent, tableProgress *type
 	}
 
 	tableProgress.Add(event)
-
-	// Considering that the parity of tableID is not necessarily even,
-	// directly dividing by the number of buckets may cause unevenness between buckets.
-	// Therefore, we first take the modulus of the prime number and then take the modulus of the bucket.
-	index := int64(event.PhysicalTableID) % prime % int64(s.workerCount)
-	s.dmlWorker[index].GetEventChan() <- event
+	s.conflictDetector.Add(event)
 }
 
 func (s *MysqlSink) PassBlockEvent(event commonEvent.BlockEvent, tableProgress *types.TableProgress) {
@@ -185,6 +201,7 @@ func (s *MysqlSink) Close(removeDDLTsItem bool) error {
 	if removeDDLTsItem {
 		return s.ddlWorker.RemoveDDLTsItem()
 	}
+	s.conflictDetector.Close()
 	for i := 0; i < s.workerCount; i++ {
 		s.dmlWorker[i].Close()
 	}
@@ -0,0 +1,281 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/helper"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/helper/eventrouter"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/helper/topicmanager"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/types"
+	"github.com/pingcap/ticdc/downstreamadapter/worker"
+	"github.com/pingcap/ticdc/downstreamadapter/worker/producer"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/common/columnselector"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	ticonfig "github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/ticdc/pkg/sink/codec"
+	"github.com/pingcap/ticdc/pkg/sink/pulsar"
+	"github.com/pingcap/ticdc/pkg/sink/util"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	utils "github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// PulsarSink is responsible for writing data to the pulsar downstream.
+// Including DDL, DML and the checkpoint ts.
+type PulsarSink struct {
+	changefeedID common.ChangeFeedID
+
+	dmlWorker *worker.PulsarDMLWorker
+	ddlWorker *worker.PulsarDDLWorker
+
+	// the module used by dmlWorker and ddlWorker
+	// PulsarSink need to close it when Close() is called
+	topicManager topicmanager.TopicManager
+	statistics   *metrics.Statistics
+
+	errgroup *errgroup.Group
+	errCh    chan error
+	isNormal uint32 // if sink is normal, isNormal is 1, otherwise is 0
+}
+
+func (s *PulsarSink) SinkType() SinkType {
+	return PulsarSinkType
+}
+
+func NewPulsarSink(ctx context.Context, changefeedID common.ChangeFeedID, sinkURI *url.URL, sinkConfig *ticonfig.SinkConfig, errCh chan error) (*PulsarSink, error) {
+	return newPulsarSink(ctx, changefeedID, sinkURI, sinkConfig, errCh,
+		pulsar.NewCreatorFactory, producer.NewPulsarDMLProducer, producer.NewPulsarDDLProducer)
+}
+
+// newPulsarSink creates the pulsar sink with the given client and producer creators,
+// so that tests can replace them with the mock ones.
+func newPulsarSink(
+	ctx context.Context,
+	changefeedID common.ChangeFeedID,
+	sinkURI *url.URL,
+	sinkConfig *ticonfig.SinkConfig,
+	errCh chan error,
+	clientCreator pulsar.FactoryCreator,
+	dmlProducerCreator producer.PulsarDMLProducerCreator,
+	ddlProducerCreator producer.PulsarDDLProducerCreator,
+) (*PulsarSink, error) {
+	errGroup, ctx := errgroup.WithContext(ctx)
+	topic, err := helper.GetTopic(sinkURI)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	scheme := sinkURI.Scheme
+	protocol, err := helper.GetProtocol(utils.GetOrZero(sinkConfig.Protocol))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	pulsarConfig, err := pulsar.NewPulsarConfig(sinkURI, sinkConfig.PulsarConfig)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarInvalidConfig, err)
+	}
+	// the dml producer reads the pulsar config from the sink config,
+	// use a copy to avoid modifying the sink config shared with the caller.
+	sinkConfigCopy := *sinkConfig
+	sinkConfigCopy.PulsarConfig = pulsarConfig
+	sinkConfig = &sinkConfigCopy
+
+	eventRouter, err := eventrouter.NewEventRouter(sinkConfig, protocol, topic, scheme)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	columnSelector, err := columnselector.NewColumnSelectors(sinkConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	encoderConfig, err := util.GetEncoderConfig(changefeedID, sinkURI, protocol, sinkConfig, ticonfig.DefaultMaxMessageBytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	encoder, err := codec.NewEventEncoder(ctx, encoderConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// the dml and ddl producers use different clients,
+	// because each of them closes its own client.
+	dmlClient, err := clientCreator(pulsarConfig, changefeedID, sinkConfig)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewClient, err)
+	}
+	// We must release the resources acquired when this func returns an error,
+	// the client is closed by the producer once the producer is created.
+	dmlClientOwned := false
+	defer func() {
+		if err != nil && !dmlClientOwned {
+			dmlClient.Close()
+		}
+	}()
+	topicManager, err := topicmanager.NewPulsarTopicManager(pulsarConfig, dmlClient)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			topicManager.Close()
+		}
+	}()
+	dmlProducer, err := dmlProducerCreator(changefeedID, dmlClient, sinkConfig)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+	dmlClientOwned = true
+	defer func() {
+		if err != nil {
+			dmlProducer.Close()
+		}
+	}()
+
+	ddlClient, err := clientCreator(pulsarConfig, changefeedID, sinkConfig)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewClient, err)
+	}
+	ddlClientOwned := false
+	defer func() {
+		if err != nil && !ddlClientOwned {
+			ddlClient.Close()
+		}
+	}()
+	ddlProducer, err := ddlProducerCreator(changefeedID, pulsarConfig, ddlClient)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+	ddlClientOwned = true
+
+	statistics := metrics.NewStatistics(changefeedID, "PulsarSink")
+	encoderGroup := codec.NewEncoderGroup(ctx, sinkConfig, encoderConfig, changefeedID)
+	dmlWorker := worker.NewPulsarDMLWorker(ctx, changefeedID, protocol, dmlProducer, encoderGroup, columnSelector, eventRouter, topicManager, statistics, errGroup)
+	ddlWorker := worker.NewPulsarDDLWorker(ctx, changefeedID, protocol, ddlProducer, encoder, eventRouter, topicManager, statistics, errGroup)
+
+	sink := &PulsarSink{
+		changefeedID: changefeedID,
+		dmlWorker:    dmlWorker,
+		ddlWorker:    ddlWorker,
+		topicManager: topicManager,
+		statistics:   statistics,
+		errgroup:     errGroup,
+		errCh:        errCh,
+		isNormal:     1,
+	}
+	go sink.run()
+	return sink, nil
+}
+
+func (s *PulsarSink) run() {
+	s.dmlWorker.Run()
+	s.ddlWorker.Run()
+
+	err := s.errgroup.Wait()
+	if errors.Cause(err) != context.Canceled {
+		atomic.StoreUint32(&s.isNormal, 0)
+		select {
+		case s.errCh <- err:
+		default:
+			log.Error("error channel is full, discard error",
+				zap.Any("ChangefeedID", s.changefeedID.String()),
+				zap.Error(err))
+		}
+	}
+}
+
+func (s *PulsarSink) IsNormal() bool {
+	return atomic.LoadUint32(&s.isNormal) == 1
+}
+
+func (s *PulsarSink) AddDMLEvent(event *commonEvent.DMLEvent, tableProgress *types.TableProgress) {
+	if event.Len() == 0 {
+		return
+	}
+	tableProgress.Add(event)
+	s.dmlWorker.GetEventChan() <- event
+}
+
+func (s *PulsarSink) PassBlockEvent(event commonEvent.BlockEvent, tableProgress *types.TableProgress) {
+	tableProgress.Pass(event)
+	event.PostFlush()
+}
+
+func (s *PulsarSink) WriteBlockEvent(event commonEvent.BlockEvent, tableProgress *types.TableProgress) error {
+	tableProgress.Add(event)
+	switch event := event.(type) {
+	case *commonEvent.DDLEvent:
+		if event.TiDBOnly {
+			// run callback directly and return
+			event.PostFlush()
+			return nil
+		}
+		err := s.ddlWorker.WriteBlockEvent(event)
+		if err != nil {
+			atomic.StoreUint32(&s.isNormal, 0)
+			return errors.Trace(err)
+		}
+		event.PostFlush()
+	case *commonEvent.SyncPointEvent:
+		log.Error("PulsarSink doesn't support Sync Point Event",
+			zap.String("namespace", s.changefeedID.Namespace()),
+			zap.String("changefeed", s.changefeedID.Name()),
+			zap.Any("event", event))
+	default:
+		log.Error("PulsarSink doesn't support this type of block event",
+			zap.String("namespace", s.changefeedID.Namespace()),
+			zap.String("changefeed", s.changefeedID.Name()),
+			zap.Any("event type", event.GetType()))
+	}
+	return nil
+}
+
+func (s *PulsarSink) AddCheckpointTs(ts uint64) {
+	s.ddlWorker.GetCheckpointTsChan() <- ts
+}
+
+func (s *PulsarSink) SetTableSchemaStore(tableSchemaStore *util.TableSchemaStore) {
+	s.ddlWorker.SetTableSchemaStore(tableSchemaStore)
+}
+
+func (s *PulsarSink) Close(removeDDLTsItem bool) error {
+	err := s.ddlWorker.Close()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = s.dmlWorker.Close()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	s.topicManager.Close()
+	s.statistics.Close()
+	return nil
+}
+
+func (s *PulsarSink) CheckStartTsList(tableIds []int64, startTsList []int64) ([]int64, error) {
+	return startTsList, nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/types"
+	"github.com/pingcap/ticdc/downstreamadapter/worker/producer"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	pulsarConfig "github.com/pingcap/ticdc/pkg/sink/pulsar"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func newPulsarSinkForTest(t *testing.T) (*PulsarSink, *producer.MockPulsarDMLProducer, *producer.MockPulsarDDLProducer) {
+	sinkURI, err := url.Parse("pulsar://127.0.0.1:6650/test-topic?protocol=canal-json")
+	require.NoError(t, err)
+	sinkConfig := config.GetDefaultReplicaConfig().Clone().Sink
+	sinkConfig.Protocol = util.AddressOf("canal-json")
+
+	var (
+		dmlProducer *producer.MockPulsarDMLProducer
+		ddlProducer *producer.MockPulsarDDLProducer
+	)
+	dmlProducerCreator := func(id common.ChangeFeedID, client pulsar.Client, sinkConfig *config.SinkConfig) (producer.PulsarDMLProducer, error) {
+		p, err := producer.NewMockPulsarDMLProducer(id, client, sinkConfig)
+		dmlProducer = p.(*producer.MockPulsarDMLProducer)
+		return p, err
+	}
+	ddlProducerCreator := func(id common.ChangeFeedID, pConfig *config.PulsarConfig, client pulsar.Client) (ddlproducer.DDLProducer, error) {
+		p, err := producer.NewMockPulsarDDLProducer(id, pConfig, client)
+		ddlProducer = p.(*producer.MockPulsarDDLProducer)
+		return p, err
+	}
+
+	changefeedID := common.ChangefeedID4Test("test", "test")
+	errCh := make(chan error, 16)
+	sink, err := newPulsarSink(context.Background(), changefeedID, sinkURI, sinkConfig, errCh,
+		pulsarConfig.NewMockCreatorFactory, dmlProducerCreator, ddlProducerCreator)
+	require.NoError(t, err)
+	return sink, dmlProducer, ddlProducer
+}
+
+func TestPulsarSinkBasicFunctionality(t *testing.T) {
+	sink, dmlProducer, ddlProducer := newPulsarSinkForTest(t)
+	defer sink.Close(false)
+	require.Equal(t, PulsarSinkType, sink.SinkType())
+	require.True(t, sink.IsNormal())
+
+	var count atomic.Int64
+
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32));")
+	require.NotNil(t, job)
+
+	ddlEvent := &commonEvent.DDLEvent{
+		Query:      job.Query,
+		Type:       byte(job.Type),
+		SchemaName: job.SchemaName,
+		TableName:  job.TableName,
+		FinishedTs: 1,
+		BlockedTables: &commonEvent.InfluencedTables{
+			InfluenceType: commonEvent.InfluenceTypeNormal,
+			TableIDs:      []int64{0},
+		},
+		NeedAddedTables: []commonEvent.Table{{TableID: 1, SchemaID: 1}},
+		PostTxnFlushed: []func(){
+			func() { count.Inc() },
+		},
+	}
+
+	dmlEvent := helper.DML2Event("test", "t", "insert into t values (1, 'test')", "insert into t values (2, 'test2');")
+	dmlEvent.PostTxnFlushed = []func(){
+		func() { count.Inc() },
+	}
+	dmlEvent.CommitTs = 2
+
+	tableProgress := types.NewTableProgress()
+	err := sink.WriteBlockEvent(ddlEvent, tableProgress)
+	require.NoError(t, err)
+	require.Len(t, ddlProducer.GetEvents("test-topic"), 1)
+	require.Equal(t, int64(1), count.Load())
+
+	sink.AddDMLEvent(dmlEvent, tableProgress)
+	require.Eventually(t, func() bool {
+		return len(dmlProducer.GetEvents("test-topic")) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return count.Load() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	ts, isEmpty := tableProgress.GetCheckpointTs()
+	require.Equal(t, uint64(1), ts)
+	require.True(t, isEmpty)
+	require.True(t, sink.IsNormal())
+}
+
+type mockPulsarClient struct {
+	pulsar.Client
+	closed atomic.Bool
+}
+
+func (c *mockPulsarClient) Close() {
+	c.closed.Store(true)
+}
+
+type closeRecordingDMLProducer struct {
+	producer.PulsarDMLProducer
+	closed atomic.Bool
+}
+
+func (p *closeRecordingDMLProducer) Close() {
+	p.closed.Store(true)
+	p.PulsarDMLProducer.Close()
+}
+
+func TestNewPulsarSinkReleaseResourcesOnError(t *testing.T) {
+	sinkURI, err := url.Parse("pulsar://127.0.0.1:6650/test-topic?protocol=canal-json")
+	require.NoError(t, err)
+	sinkConfig := config.GetDefaultReplicaConfig().Clone().Sink
+	sinkConfig.Protocol = util.AddressOf("canal-json")
+
+	var clients []*mockPulsarClient
+	clientCreator := func(*config.PulsarConfig, common.ChangeFeedID, *config.SinkConfig) (pulsar.Client, error) {
+		client := &mockPulsarClient{}
+		clients = append(clients, client)
+		return client, nil
+	}
+	var dmlProducer *closeRecordingDMLProducer
+	dmlProducerCreator := func(id common.ChangeFeedID, client pulsar.Client, sinkConfig *config.SinkConfig) (producer.PulsarDMLProducer, error) {
+		p, err := producer.NewMockPulsarDMLProducer(id, client, sinkConfig)
+		dmlProducer = &closeRecordingDMLProducer{PulsarDMLProducer: p}
+		return dmlProducer, err
+	}
+	ddlProducerCreator := func(common.ChangeFeedID, *config.PulsarConfig, pulsar.Client) (ddlproducer.DDLProducer, error) {
+		return nil, errors.New("ddl producer failed")
+	}
+
+	_, err = newPulsarSink(context.Background(), common.ChangefeedID4Test("test", "test"),
+		sinkURI, sinkConfig, make(chan error, 1), clientCreator, dmlProducerCreator, ddlProducerCreator)
+	require.Error(t, err)
+	// the dml client is closed by the dml producer, and the ddl client is closed directly.
+	require.True(t, dmlProducer.closed.Load())
+	require.Len(t, clients, 2)
+	require.False(t, clients[0].closed.Load())
+	require.True(t, clients[1].closed.Load())
+	// the sink config of the caller is not modified.
+	require.Nil(t, sinkConfig.PulsarConfig)
+
+	// the dml client is closed directly if the dml producer is not created.
+	clients = nil
+	dmlProducerCreator = func(common.ChangeFeedID, pulsar.Client, *config.SinkConfig) (producer.PulsarDMLProducer, error) {
+		return nil, errors.New("dml producer failed")
+	}
+	_, err = newPulsarSink(context.Background(), common.ChangefeedID4Test("test", "test"),
+		sinkURI, sinkConfig, make(chan error, 1), clientCreator, dmlProducerCreator, ddlProducerCreator)
+	require.Error(t, err)
+	require.Len(t, clients, 1)
+	require.True(t, clients[0].closed.Load())
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+// Factory creates a sink for the changefeed with the sink URI.
+type Factory func(
+	ctx context.Context,
+	config *config.ChangefeedConfig,
+	changefeedID common.ChangeFeedID,
+	sinkURI *url.URL,
+	errCh chan error,
+) (Sink, error)
+
+var factoryRegistry = struct {
+	sync.RWMutex
+	factories map[string]Factory
+}{
+	factories: make(map[string]Factory),
+}
+
+func init() {
+	// the sinks encoding events by the protocol in the sink config
+	encodeByProtocol := config.SinkSchemeInfo{EncodeByProtocol: true}
+	Register(func(
+		ctx context.Context, config *config.ChangefeedConfig, changefeedID common.ChangeFeedID, sinkURI *url.URL, errCh chan error,
+	) (Sink, error) {
+		return NewMysqlSink(ctx, changefeedID, 16, config, sinkURI, errCh)
+	}, config.SinkSchemeInfo{}, sink.MySQLScheme, sink.MySQLSSLScheme, sink.TiDBScheme, sink.TiDBSSLScheme)
+	Register(func(
+		ctx context.Context, config *config.ChangefeedConfig, changefeedID common.ChangeFeedID, sinkURI *url.URL, errCh chan error,
+	) (Sink, error) {
+		return NewKafkaSink(ctx, changefeedID, sinkURI, config.SinkConfig, errCh)
+	}, encodeByProtocol, sink.KafkaScheme, sink.KafkaSSLScheme)
+	Register(func(
+		ctx context.Context, config *config.ChangefeedConfig, changefeedID common.ChangeFeedID, sinkURI *url.URL, errCh chan error,
+	) (Sink, error) {
+		return NewPulsarSink(ctx, changefeedID, sinkURI, config.SinkConfig, errCh)
+	}, encodeByProtocol, sink.PulsarScheme, sink.PulsarSSLScheme, sink.PulsarHTTPScheme, sink.PulsarHTTPSScheme)
+	Register(func(
+		ctx context.Context, config *config.ChangefeedConfig, changefeedID common.ChangeFeedID, sinkURI *url.URL, errCh chan error,
+	) (Sink, error) {
+		return NewCloudStorageSink(ctx, changefeedID, sinkURI, config.SinkConfig, errCh)
+	}, encodeByProtocol, sink.S3Scheme, sink.FileScheme, sink.GCSScheme, sink.GSScheme, sink.AzblobScheme, sink.AzureScheme, sink.CloudStorageNoopScheme)
+	Register(func(
+		_ context.Context, _ *config.ChangefeedConfig, changefeedID common.ChangeFeedID, _ *url.URL, _ chan error,
+	) (Sink, error) {
+		return NewBlackHoleSink(changefeedID), nil
+	}, config.SinkSchemeInfo{}, sink.BlackHoleScheme)
+}
+
+// Register registers a sink for the schemes, it's the only way to add a supported scheme.
+// For a sink which is not built in, it's usually called in the `init` function of the
+// package implementing the sink, and the package is linked into the binary by a blank import.
+// The info is used to validate the changefeed config with these schemes.
+func Register(factory Factory, info config.SinkSchemeInfo, schemes ...string) {
+	config.RegisterSinkScheme(info, schemes...)
+	registerFactory(factory, schemes...)
+}
+
+func registerFactory(factory Factory, schemes ...string) {
+	factoryRegistry.Lock()
+	defer factoryRegistry.Unlock()
+	for _, scheme := range schemes {
+		scheme = strings.ToLower(scheme)
+		if _, ok := factoryRegistry.factories[scheme]; ok {
+			log.Panic("sink factory is already registered", zap.String("scheme", scheme))
+		}
+		factoryRegistry.factories[scheme] = factory
+	}
+}
+
+func getFactory(scheme string) (Factory, bool) {
+	factoryRegistry.RLock()
+	defer factoryRegistry.RUnlock()
+	factory, ok := factoryRegistry.factories[strings.ToLower(scheme)]
+	return factory, ok
+}
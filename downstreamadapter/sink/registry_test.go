@@ -0,0 +1,116 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"testing"
+
+	"github.com/pingcap/ticdc/downstreamadapter/sink/types"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterSink(t *testing.T) {
+	sinkURI, err := url.Parse("custom://127.0.0.1:1234/?protocol=canal-json")
+	require.NoError(t, err)
+	// the scheme is not supported before it is registered
+	require.Error(t, config.GetDefaultReplicaConfig().ValidateAndAdjust(sinkURI))
+	_, err = NewSink(context.Background(), &config.ChangefeedConfig{SinkURI: sinkURI.String()},
+		common.NewChangeFeedIDWithName("test"), make(chan error, 1))
+	require.Error(t, err)
+
+	validated := false
+	created := false
+	Register(func(
+		_ context.Context, _ *config.ChangefeedConfig, _ common.ChangeFeedID, _ *url.URL, _ chan error,
+	) (Sink, error) {
+		created = true
+		return nil, nil
+	}, config.SinkSchemeInfo{
+		EncodeByProtocol: true,
+		Validate: func(_ *url.URL, _ *config.SinkConfig) error {
+			validated = true
+			return nil
+		},
+	}, "custom")
+
+	require.Contains(t, config.SupportedSinkSchemes(), "custom")
+	require.NoError(t, config.GetDefaultReplicaConfig().ValidateAndAdjust(sinkURI))
+	require.True(t, validated)
+	_, err = NewSink(context.Background(), &config.ChangefeedConfig{SinkURI: "CUSTOM://127.0.0.1:1234"},
+		common.NewChangeFeedIDWithName("test"), make(chan error, 1))
+	require.NoError(t, err)
+	require.True(t, created)
+
+	// register a scheme twice
+	require.Panics(t, func() {
+		Register(nil, config.SinkSchemeInfo{}, "custom")
+	})
+	require.Panics(t, func() {
+		Register(nil, config.SinkSchemeInfo{}, "mysql")
+	})
+}
+
+func TestSupportedSchemesEqualFactories(t *testing.T) {
+	// every scheme passing the changefeed validation must be able to create a sink, and vice versa
+	factoryRegistry.RLock()
+	factorySchemes := make([]string, 0, len(factoryRegistry.factories))
+	for scheme := range factoryRegistry.factories {
+		factorySchemes = append(factorySchemes, scheme)
+	}
+	factoryRegistry.RUnlock()
+	sort.Strings(factorySchemes)
+	require.Equal(t, factorySchemes, config.SupportedSinkSchemes())
+
+	for _, scheme := range []string{sink.MySQLScheme, sink.TiDBSSLScheme, sink.BlackHoleScheme} {
+		info, ok := config.GetSinkSchemeInfo(scheme)
+		require.True(t, ok, scheme)
+		require.False(t, info.EncodeByProtocol, scheme)
+	}
+	for _, scheme := range []string{sink.KafkaScheme, sink.PulsarHTTPSScheme, sink.S3Scheme, sink.CloudStorageNoopScheme} {
+		info, ok := config.GetSinkSchemeInfo(scheme)
+		require.True(t, ok, scheme)
+		require.True(t, info.EncodeByProtocol, scheme)
+	}
+}
+
+func TestBlackHoleSink(t *testing.T) {
+	s, err := NewSink(context.Background(), &config.ChangefeedConfig{SinkURI: "blackhole://"},
+		common.NewChangeFeedIDWithName("test"), make(chan error, 1))
+	require.NoError(t, err)
+	require.Equal(t, BlackHoleSinkType, s.SinkType())
+	require.True(t, s.IsNormal())
+
+	tableProgress := types.NewTableProgress()
+	flushed := false
+	dml := &commonEvent.DMLEvent{StartTs: 1, CommitTs: 2, Length: 1}
+	dml.AddPostFlushFunc(func() { flushed = true })
+	s.AddDMLEvent(dml, tableProgress)
+	require.True(t, flushed)
+	require.True(t, tableProgress.Empty())
+
+	ddl := &commonEvent.DDLEvent{FinishedTs: 3}
+	require.NoError(t, s.WriteBlockEvent(ddl, tableProgress))
+	require.True(t, tableProgress.Empty())
+	checkpointTs, isEmpty := tableProgress.GetCheckpointTs()
+	require.True(t, isEmpty)
+	require.Equal(t, uint64(2), checkpointTs)
+	require.NoError(t, s.Close(false))
+}
@@ -15,6 +15,7 @@ package sink
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 
 	"github.com/pingcap/ticdc/downstreamadapter/sink/types"
@@ -31,6 +32,9 @@ type SinkType int
 const (
 	MysqlSinkType SinkType = iota
 	KafkaSinkType
+	PulsarSinkType
+	CloudStorageSinkType
+	BlackHoleSinkType
 )
 
 type Sink interface {
@@ -45,17 +49,23 @@ type Sink interface {
 	IsNormal() bool
 }
 
+// DeadLetterSink is implemented by the sinks which write the rows can not be applied
+// to the dead letter destination, instead of failing the changefeed.
+type DeadLetterSink interface {
+	// DeadLetterRows returns the number of rows written to the dead letter destination.
+	DeadLetterRows() uint64
+}
+
 func NewSink(ctx context.Context, config *config.ChangefeedConfig, changefeedID common.ChangeFeedID, errCh chan error) (Sink, error) {
 	sinkURI, err := url.Parse(config.SinkURI)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
 	scheme := sink.GetScheme(sinkURI)
-	switch scheme {
-	case sink.MySQLScheme, sink.MySQLSSLScheme, sink.TiDBScheme, sink.TiDBSSLScheme:
-		return NewMysqlSink(ctx, changefeedID, 16, config, sinkURI, errCh)
-	case sink.KafkaScheme, sink.KafkaSSLScheme:
-		return NewKafkaSink(ctx, changefeedID, sinkURI, config.SinkConfig, errCh)
+	factory, ok := getFactory(scheme)
+	if !ok {
+		return nil, cerror.ErrSinkURIInvalid.GenWithStackByArgs(
+			fmt.Sprintf("the sink scheme (%s) is not supported", scheme))
 	}
-	return nil, nil
+	return factory(ctx, config, changefeedID, sinkURI, errCh)
 }
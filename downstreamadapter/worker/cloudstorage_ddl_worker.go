@@ -0,0 +1,309 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/ticdc/pkg/sink/cloudstorage"
+	"github.com/pingcap/ticdc/pkg/sink/util"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/robfig/cron"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// checkpointFileName is the name of the file which records the checkpoint ts of the changefeed.
+	checkpointFileName = "metadata"
+	// minCheckpointWriteInterval is the min interval to write the checkpoint ts to the external storage.
+	minCheckpointWriteInterval = 2 * time.Second
+)
+
+// CloudStorageDDLWorker writes the table schema files and the checkpoint ts to the external storage.
+type CloudStorageDDLWorker struct {
+	changefeedID     common.ChangeFeedID
+	sinkURI          *url.URL
+	statistics       *metrics.Statistics
+	storage          storage.ExternalStorage
+	config           *cloudstorage.Config
+	cron             *cron.Cron
+	checkpointTsChan chan uint64
+
+	lastCheckpointTs         atomic.Uint64
+	lastSendCheckpointTsTime time.Time
+	tableSchemaStore         *util.TableSchemaStore
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	errGroup *errgroup.Group
+}
+
+// NewCloudStorageDDLWorker creates a ddl worker for cloud storage.
+func NewCloudStorageDDLWorker(
+	ctx context.Context,
+	changefeedID common.ChangeFeedID,
+	sinkURI *url.URL,
+	config *cloudstorage.Config,
+	storage storage.ExternalStorage,
+	statistics *metrics.Statistics,
+	errGroup *errgroup.Group,
+) *CloudStorageDDLWorker {
+	ctx, cancel := context.WithCancel(ctx)
+	return &CloudStorageDDLWorker{
+		changefeedID:             changefeedID,
+		sinkURI:                  sinkURI,
+		config:                   config,
+		storage:                  storage,
+		statistics:               statistics,
+		checkpointTsChan:         make(chan uint64, 16),
+		lastSendCheckpointTsTime: time.Now(),
+		ctx:                      ctx,
+		cancel:                   cancel,
+		errGroup:                 errGroup,
+	}
+}
+
+// Run starts the checkpoint writer and the background cleanup job.
+func (w *CloudStorageDDLWorker) Run() error {
+	if err := w.initCron(nil); err != nil {
+		return errors.Trace(err)
+	}
+	// Note: It is intended to run the cleanup goroutine in the background.
+	// we don't wait for it to finish since the goroutine would be stuck if
+	// the downstream is abnormal, especially when the downstream is a nfs.
+	go w.bgCleanup()
+
+	w.errGroup.Go(func() error {
+		return w.writeCheckpointTsLoop()
+	})
+	return nil
+}
+
+func (w *CloudStorageDDLWorker) GetCheckpointTsChan() chan<- uint64 {
+	return w.checkpointTsChan
+}
+
+func (w *CloudStorageDDLWorker) SetTableSchemaStore(tableSchemaStore *util.TableSchemaStore) {
+	w.tableSchemaStore = tableSchemaStore
+}
+
+// WriteBlockEvent writes the schema file of the table changed by the DDL event.
+func (w *CloudStorageDDLWorker) WriteBlockEvent(event *commonEvent.DDLEvent) error {
+	var def cloudstorage.TableDefinition
+	def.FromDDLEvent(event, w.config.OutputColumnID)
+	if err := w.writeFile(event, def); err != nil {
+		return errors.Trace(err)
+	}
+
+	// For the DDLs which change multiple tables, such as exchange partition,
+	// the schema of the other tables also need to be written.
+	for _, tableInfo := range event.MultipleTableInfos {
+		if tableInfo == nil || tableInfo.TableInfo == nil {
+			continue
+		}
+		var tableDef cloudstorage.TableDefinition
+		tableDef.FromTableInfo(tableInfo, tableInfo.GetVersion(), w.config.OutputColumnID)
+		tableDef.Query = event.Query
+		tableDef.Type = event.GetDDLType()
+		if err := w.writeFile(event, tableDef); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (w *CloudStorageDDLWorker) writeFile(v *commonEvent.DDLEvent, def cloudstorage.TableDefinition) error {
+	encodedDef, err := def.MarshalWithQuery()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	path, err := def.GenerateSchemaFilePath()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	log.Debug("write ddl event to external storage",
+		zap.String("path", path), zap.Any("ddl", v))
+	return w.statistics.RecordDDLExecution(func() error {
+		return w.storage.WriteFile(w.ctx, path, encodedDef)
+	})
+}
+
+func (w *CloudStorageDDLWorker) writeCheckpointTsLoop() error {
+	for {
+		select {
+		case <-w.ctx.Done():
+			return errors.Trace(w.ctx.Err())
+		case ts, ok := <-w.checkpointTsChan:
+			if !ok {
+				log.Warn("cloud storage sink checkpoint ts channel closed",
+					zap.String("namespace", w.changefeedID.Namespace()),
+					zap.String("changefeed", w.changefeedID.Name()))
+				return nil
+			}
+			if err := w.writeCheckpointTs(ts); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// writeCheckpointTs writes the checkpoint ts to the metadata file,
+// it is skipped if the last write happens within minCheckpointWriteInterval.
+func (w *CloudStorageDDLWorker) writeCheckpointTs(ts uint64) error {
+	if time.Since(w.lastSendCheckpointTsTime) < minCheckpointWriteInterval {
+		log.Debug("skip write checkpoint ts to external storage",
+			zap.Any("changefeedID", w.changefeedID),
+			zap.Uint64("ts", ts))
+		return nil
+	}
+
+	defer func() {
+		w.lastSendCheckpointTsTime = time.Now()
+		w.lastCheckpointTs.Store(ts)
+	}()
+	ckpt, err := json.Marshal(map[string]uint64{"checkpoint-ts": ts})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = w.storage.WriteFile(w.ctx, checkpointFileName, ckpt)
+	return errors.Trace(err)
+}
+
+func (w *CloudStorageDDLWorker) initCron(cleanupJobs []func()) (err error) {
+	if cleanupJobs == nil {
+		cleanupJobs = w.genCleanupJob()
+	}
+
+	w.cron = cron.New()
+	for _, job := range cleanupJobs {
+		err = w.cron.AddFunc(w.config.FileCleanupCronSpec, job)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *CloudStorageDDLWorker) bgCleanup() {
+	if w.config.DateSeparator != config.DateSeparatorDay.String() || w.config.FileExpirationDays <= 0 {
+		log.Info("skip cleanup expired files for storage sink",
+			zap.String("namespace", w.changefeedID.Namespace()),
+			zap.String("changefeed", w.changefeedID.Name()),
+			zap.String("dateSeparator", w.config.DateSeparator),
+			zap.Int("expiredFileTTL", w.config.FileExpirationDays))
+		return
+	}
+
+	w.cron.Start()
+	defer w.cron.Stop()
+	log.Info("start schedule cleanup expired files for storage sink",
+		zap.String("namespace", w.changefeedID.Namespace()),
+		zap.String("changefeed", w.changefeedID.Name()),
+		zap.String("dateSeparator", w.config.DateSeparator),
+		zap.Int("expiredFileTTL", w.config.FileExpirationDays))
+
+	// wait for the context done
+	<-w.ctx.Done()
+	log.Info("stop schedule cleanup expired files for storage sink",
+		zap.String("namespace", w.changefeedID.Namespace()),
+		zap.String("changefeed", w.changefeedID.Name()),
+		zap.Error(w.ctx.Err()))
+}
+
+func (w *CloudStorageDDLWorker) genCleanupJob() []func() {
+	ret := []func(){}
+
+	isLocal := w.sinkURI.Scheme == "file" || w.sinkURI.Scheme == "local" || w.sinkURI.Scheme == ""
+	isRemoveEmptyDirsRunning := atomic.Bool{}
+	if isLocal {
+		ret = append(ret, func() {
+			if !isRemoveEmptyDirsRunning.CompareAndSwap(false, true) {
+				log.Warn("remove empty dirs is already running, skip this round",
+					zap.String("namespace", w.changefeedID.Namespace()),
+					zap.String("changefeed", w.changefeedID.Name()))
+				return
+			}
+			defer isRemoveEmptyDirsRunning.Store(false)
+
+			checkpointTs := w.lastCheckpointTs.Load()
+			start := time.Now()
+			cnt, err := cloudstorage.RemoveEmptyDirs(w.ctx, w.changefeedID, w.sinkURI.Path)
+			if err != nil {
+				log.Error("failed to remove empty dirs",
+					zap.String("namespace", w.changefeedID.Namespace()),
+					zap.String("changefeed", w.changefeedID.Name()),
+					zap.Uint64("checkpointTs", checkpointTs),
+					zap.Duration("cost", time.Since(start)),
+					zap.Error(err),
+				)
+				return
+			}
+			log.Info("remove empty dirs",
+				zap.String("namespace", w.changefeedID.Namespace()),
+				zap.String("changefeed", w.changefeedID.Name()),
+				zap.Uint64("checkpointTs", checkpointTs),
+				zap.Uint64("count", cnt),
+				zap.Duration("cost", time.Since(start)))
+		})
+	}
+
+	isCleanupRunning := atomic.Bool{}
+	ret = append(ret, func() {
+		if !isCleanupRunning.CompareAndSwap(false, true) {
+			log.Warn("cleanup expired files is already running, skip this round",
+				zap.String("namespace", w.changefeedID.Namespace()),
+				zap.String("changefeed", w.changefeedID.Name()))
+			return
+		}
+
+		defer isCleanupRunning.Store(false)
+		start := time.Now()
+		checkpointTs := w.lastCheckpointTs.Load()
+		cnt, err := cloudstorage.RemoveExpiredFiles(w.ctx, w.changefeedID, w.storage, w.config, checkpointTs)
+		if err != nil {
+			log.Error("failed to remove expired files",
+				zap.String("namespace", w.changefeedID.Namespace()),
+				zap.String("changefeed", w.changefeedID.Name()),
+				zap.Uint64("checkpointTs", checkpointTs),
+				zap.Duration("cost", time.Since(start)),
+				zap.Error(err),
+			)
+			return
+		}
+		log.Info("remove expired files",
+			zap.String("namespace", w.changefeedID.Namespace()),
+			zap.String("changefeed", w.changefeedID.Name()),
+			zap.Uint64("checkpointTs", checkpointTs),
+			zap.Uint64("count", cnt),
+			zap.Duration("cost", time.Since(start)))
+	})
+	return ret
+}
+
+func (w *CloudStorageDDLWorker) Close() error {
+	w.cancel()
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/ticdc/pkg/sink/cloudstorage"
+	"github.com/pingcap/ticdc/pkg/sink/codec"
+	codecCommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/pkg/chann"
+	ticommon "github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultEncodingConcurrency = 8
+	defaultChannelSize         = 1024
+)
+
+// eventFragment is used to attach a sequence number to DMLEvent.
+type eventFragment struct {
+	event          *commonEvent.DMLEvent
+	versionedTable cloudstorage.VersionedTableName
+
+	// The sequence number is mainly useful for DMLEvent defragmentation.
+	// e.g. DMLEvent 1~5 are dispatched to a group of encoding workers, but the
+	// encoding completion time varies. Let's say the final completion sequence are 1,3,2,5,4,
+	// we can use the sequence numbers to do defragmentation so that the events can arrive
+	// at the writers sequentially.
+	seqNumber uint64
+	// encodedMsgs denote the encoded messages after the event is handled in encodingWorker.
+	encodedMsgs []*ticommon.Message
+}
+
+// CloudStorageDMLWorker encodes the DML events and writes them to the external storage.
+// The data flow is as follows: **data** -> encodingWorkers -> defragmenter -> writers -> external storage
+// The defragmenter will defragment the out-of-order encoded messages and sends encoded
+// messages to individual writers.
+// The writers will write the encoded messages to external storage in parallel between different tables.
+type CloudStorageDMLWorker struct {
+	changefeedID common.ChangeFeedID
+	config       *cloudstorage.Config
+	// last sequence number
+	lastSeqNum uint64
+	// encodingWorkers defines a group of workers for encoding events.
+	encodingWorkers []*encodingWorker
+	// defragmenter is used to defragment the out-of-order encoded messages and
+	// sends encoded messages to individual writers.
+	defragmenter *defragmenter
+	// writers defines a group of workers for writing events to external storage.
+	writers []*writer
+	// msgCh is a channel to hold eventFragment.
+	// The caller of AddDMLEvent will write eventFragment to msgCh and
+	// the encodingWorkers will read eventFragment from msgCh to encode events.
+	msgCh *chann.DrainableChann[eventFragment]
+
+	statistics *metrics.Statistics
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	errGroup *errgroup.Group
+}
+
+// NewCloudStorageDMLWorker creates a dml worker for cloud storage.
+func NewCloudStorageDMLWorker(
+	ctx context.Context,
+	changefeedID common.ChangeFeedID,
+	storage storage.ExternalStorage,
+	config *cloudstorage.Config,
+	encoderConfig *codecCommon.Config,
+	extension string,
+	statistics *metrics.Statistics,
+	errGroup *errgroup.Group,
+) (*CloudStorageDMLWorker, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &CloudStorageDMLWorker{
+		changefeedID:    changefeedID,
+		config:          config,
+		encodingWorkers: make([]*encodingWorker, defaultEncodingConcurrency),
+		writers:         make([]*writer, config.WorkerCount),
+		msgCh:           chann.NewAutoDrainChann[eventFragment](),
+		statistics:      statistics,
+		ctx:             ctx,
+		cancel:          cancel,
+		errGroup:        errGroup,
+	}
+	encodedOutCh := make(chan eventFragment, defaultChannelSize)
+	workerChannels := make([]*chann.DrainableChann[eventFragment], config.WorkerCount)
+
+	// create a group of encoding workers.
+	for i := 0; i < defaultEncodingConcurrency; i++ {
+		encoder, err := codec.NewTxnEventEncoder(encoderConfig)
+		if err != nil {
+			cancel()
+			return nil, errors.Trace(err)
+		}
+		w.encodingWorkers[i] = newEncodingWorker(i, changefeedID, encoder, w.msgCh.Out(), encodedOutCh)
+	}
+
+	// create a group of writers.
+	for i := 0; i < config.WorkerCount; i++ {
+		inputCh := chann.NewAutoDrainChann[eventFragment]()
+		w.writers[i] = newWriter(i, changefeedID, storage, config, extension,
+			inputCh, statistics)
+		workerChannels[i] = inputCh
+	}
+
+	// create defragmenter.
+	// The defragmenter is used to defragment the out-of-order encoded messages from encoding workers and
+	// sends encoded messages to related writers in order. Messages of the same table will be sent to
+	// the same writer.
+	w.defragmenter = newDefragmenter(encodedOutCh, workerChannels)
+	return w, nil
+}
+
+// Run runs the encoding workers, the defragmenter and the writers in the error group.
+func (w *CloudStorageDMLWorker) Run() {
+	// run the encoding workers.
+	for i := 0; i < defaultEncodingConcurrency; i++ {
+		encodingWorker := w.encodingWorkers[i]
+		w.errGroup.Go(func() error {
+			return encodingWorker.run(w.ctx)
+		})
+	}
+
+	// run the defragmenter.
+	w.errGroup.Go(func() error {
+		return w.defragmenter.run(w.ctx)
+	})
+
+	// run the writers.
+	for i := 0; i < len(w.writers); i++ {
+		dataWriter := w.writers[i]
+		w.errGroup.Go(func() error {
+			return dataWriter.run(w.ctx)
+		})
+	}
+
+	log.Info("cloud storage sink dml worker started",
+		zap.String("namespace", w.changefeedID.Namespace()),
+		zap.String("changefeed", w.changefeedID.Name()),
+		zap.Int("workerCount", len(w.writers)),
+		zap.Any("config", w.config))
+}
+
+// AddDMLEvent emits the DML event with a sequence number to the encoding workers.
+func (w *CloudStorageDMLWorker) AddDMLEvent(event *commonEvent.DMLEvent) {
+	tbl := cloudstorage.VersionedTableName{
+		TableNameWithPhysicTableID: common.TableName{
+			Schema:      event.TableInfo.GetSchemaName(),
+			Table:       event.TableInfo.GetTableName(),
+			TableID:     event.PhysicalTableID,
+			IsPartition: event.TableInfo.IsPartitionTable(),
+		},
+		TableInfoVersion: event.TableInfoVersion,
+	}
+	seq := atomic.AddUint64(&w.lastSeqNum, 1)
+
+	w.statistics.ObserveRows([]*commonEvent.DMLEvent{event})
+	// emit a DMLEvent coupled with a sequence number starting from one.
+	w.msgCh.In() <- eventFragment{
+		seqNumber:      seq,
+		versionedTable: tbl,
+		event:          event,
+	}
+}
+
+// Close stops all the workers and releases the resources.
+func (w *CloudStorageDMLWorker) Close() error {
+	w.cancel()
+	for _, encodingWorker := range w.encodingWorkers {
+		encodingWorker.close()
+	}
+
+	for _, dataWriter := range w.writers {
+		dataWriter.close()
+	}
+	w.msgCh.CloseAndDrain()
+	return nil
+}
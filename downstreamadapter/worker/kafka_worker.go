@@ -172,11 +172,13 @@ func (w *KafkaDMLWorker) calculateKeyPartitions() error {
 						TotalPartition: partitionNum,
 					},
 					RowEvent: commonEvent.RowEvent{
-						TableInfo:      event.TableInfo,
-						CommitTs:       event.CommitTs,
-						Event:          row,
-						Callback:       rowCallback,
-						ColumnSelector: selector,
+						PhysicalTableID: event.PhysicalTableID,
+						TableInfo:       event.TableInfo,
+						StartTs:         event.StartTs,
+						CommitTs:        event.CommitTs,
+						Event:           row,
+						Callback:        rowCallback,
+						ColumnSelector:  selector,
 					},
 				}
 			}
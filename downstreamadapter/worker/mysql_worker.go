@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/ticdc/pkg/metrics"
 	"github.com/pingcap/ticdc/pkg/sink/mysql"
 	"github.com/pingcap/ticdc/pkg/sink/util"
+	"github.com/pingcap/tiflow/pkg/causality"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -36,7 +37,9 @@ type MysqlDMLWorker struct {
 	errGroup     *errgroup.Group
 	changefeedID common.ChangeFeedID
 
-	eventChan   chan *commonEvent.DMLEvent
+	// txnCh is the output channel of the conflict detector for this worker,
+	// the transactions in it are conflict free with the ones in other workers.
+	txnCh       <-chan causality.TxnWithNotifier[*commonEvent.DMLEvent]
 	mysqlWriter *mysql.MysqlWriter
 	id          int
 
@@ -50,22 +53,19 @@ func NewMysqlDMLWorker(
 	id int,
 	changefeedID common.ChangeFeedID,
 	errGroup *errgroup.Group,
-	statistics *metrics.Statistics) *MysqlDMLWorker {
+	statistics *metrics.Statistics,
+	txnCh <-chan causality.TxnWithNotifier[*commonEvent.DMLEvent]) *MysqlDMLWorker {
 	return &MysqlDMLWorker{
 		ctx:          ctx,
 		mysqlWriter:  mysql.NewMysqlWriter(ctx, db, config, changefeedID, statistics),
 		id:           id,
 		maxRows:      config.MaxTxnRow,
-		eventChan:    make(chan *commonEvent.DMLEvent, 16),
+		txnCh:        txnCh,
 		changefeedID: changefeedID,
 		errGroup:     errGroup,
 	}
 }
 
-func (w *MysqlDMLWorker) GetEventChan() chan *commonEvent.DMLEvent {
-	return w.eventChan
-}
-
 func (w *MysqlDMLWorker) Run() {
 	w.errGroup.Go(func() error {
 		namespace := w.changefeedID.Namespace()
@@ -84,15 +84,23 @@ func (w *MysqlDMLWorker) Run() {
 		totalStart := time.Now()
 
 		events := make([]*commonEvent.DMLEvent, 0)
+		// postTxnExecuted is used to notify the conflict detector that the transactions are flushed,
+		// so the transactions conflicted with them can be executed.
+		postTxnExecuted := make([]func(), 0)
 		rows := 0
+		addTxn := func(txn causality.TxnWithNotifier[*commonEvent.DMLEvent]) {
+			workerHandledRows.Add(float64(txn.TxnEvent.Len()))
+			events = append(events, txn.TxnEvent)
+			postTxnExecuted = append(postTxnExecuted, txn.PostTxnExecuted)
+			rows += int(txn.TxnEvent.Len())
+		}
 		for {
 			needFlush := false
 			select {
 			case <-w.ctx.Done():
 				return errors.Trace(w.ctx.Err())
-			case txnEvent := <-w.eventChan:
-				events = append(events, txnEvent)
-				rows += int(txnEvent.Len())
+			case txn := <-w.txnCh:
+				addTxn(txn)
 				if rows > w.maxRows {
 					needFlush = true
 				}
@@ -100,10 +108,8 @@ func (w *MysqlDMLWorker) Run() {
 					delay := time.NewTimer(10 * time.Millisecond)
 					for !needFlush {
 						select {
-						case txnEvent := <-w.eventChan:
-							workerHandledRows.Add(float64(txnEvent.Len()))
-							events = append(events, txnEvent)
-							rows += int(txnEvent.Len())
+						case txn := <-w.txnCh:
+							addTxn(txn)
 							if rows > w.maxRows {
 								needFlush = true
 							}
@@ -124,6 +130,9 @@ func (w *MysqlDMLWorker) Run() {
 				if err != nil {
 					return errors.Trace(err)
 				}
+				for _, f := range postTxnExecuted {
+					f()
+				}
 				workerFlushDuration.Observe(time.Since(start).Seconds())
 				// we record total time to calcuate the worker busy ratio.
 				// so we record the total time after flushing, to unified statistics on
@@ -131,6 +140,7 @@ func (w *MysqlDMLWorker) Run() {
 				workerTotalDuration.Observe(time.Since(totalStart).Seconds())
 				totalStart = time.Now()
 				events = events[:0]
+				postTxnExecuted = postTxnExecuted[:0]
 				rows = 0
 			}
 		}
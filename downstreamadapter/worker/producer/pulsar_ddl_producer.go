@@ -0,0 +1,175 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package producer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/helper"
+	commonType "github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/mq"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"go.uber.org/zap"
+)
+
+// Assert DDLProducer implementation
+var _ ddlproducer.DDLProducer = (*pulsarDDLProducer)(nil)
+
+// PulsarDDLProducerCreator defines the type of pulsar DDL producer creator.
+type PulsarDDLProducerCreator func(
+	changefeedID commonType.ChangeFeedID,
+	pConfig *config.PulsarConfig,
+	client pulsar.Client,
+) (ddlproducer.DDLProducer, error)
+
+// pulsarDDLProducer is used to send DDL and checkpoint messages to pulsar synchronously.
+type pulsarDDLProducer struct {
+	id      commonType.ChangeFeedID
+	client  pulsar.Client
+	pConfig *config.PulsarConfig
+	// producers caches one producer for each topic.
+	producers *lru.Cache
+	// closedMu is used to protect `closed`.
+	closedMu sync.RWMutex
+	closed   bool
+}
+
+// NewPulsarDDLProducer creates a pulsar producer for replicating DDL.
+func NewPulsarDDLProducer(
+	changefeedID commonType.ChangeFeedID,
+	pConfig *config.PulsarConfig,
+	client pulsar.Client,
+) (ddlproducer.DDLProducer, error) {
+	log.Info("Starting pulsar DDL producer ...",
+		zap.String("namespace", changefeedID.Namespace()),
+		zap.String("changefeed", changefeedID.Name()))
+
+	topicName, err := helper.GetTopic(pConfig.SinkURI)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	defaultProducer, err := newPulsarProducer(pConfig, client, topicName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	producers, err := newPulsarProducerCache(pConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	producers.Add(topicName, defaultProducer)
+
+	return &pulsarDDLProducer{
+		id:        changefeedID,
+		client:    client,
+		pConfig:   pConfig,
+		producers: producers,
+	}, nil
+}
+
+// SyncBroadcastMessage sends the message to the topic, pulsar consumers
+// consume all partitions of the topic, so totalPartitionsNum is not used.
+func (p *pulsarDDLProducer) SyncBroadcastMessage(ctx context.Context, topic string,
+	totalPartitionsNum int32, message *common.Message,
+) error {
+	return p.SyncSendMessage(ctx, topic, totalPartitionsNum, message)
+}
+
+// SyncSendMessage sends the message to the topic synchronously,
+// partitionNum is not used, the partition is decided by the message key.
+func (p *pulsarDDLProducer) SyncSendMessage(ctx context.Context, topic string,
+	partitionNum int32, message *common.Message,
+) error {
+	p.closedMu.RLock()
+	defer p.closedMu.RUnlock()
+	if p.closed {
+		return cerror.ErrPulsarProducerClosed.GenWithStackByArgs()
+	}
+
+	wrapperSchemaAndTopic(message)
+	mq.IncPublishedDDLCount(topic, p.id.Name(), message)
+
+	producer, err := p.getProducerByTopic(topic)
+	if err != nil {
+		log.Error("ddl SyncSendMessage GetProducerByTopic fail", zap.Error(err))
+		return errors.Trace(err)
+	}
+
+	data := &pulsar.ProducerMessage{
+		Payload: message.Value,
+		Key:     message.GetPartitionKey(),
+	}
+	mID, err := producer.Send(ctx, data)
+	if err != nil {
+		log.Error("ddl producer send fail",
+			zap.String("namespace", p.id.Namespace()),
+			zap.String("changefeed", p.id.Name()),
+			zap.String("topic", topic),
+			zap.Error(err))
+		mq.IncPublishedDDLFail(topic, p.id.Name(), message)
+		return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
+	}
+
+	if message.Type == model.MessageTypeDDL {
+		log.Info("pulsar DDL producer send message success",
+			zap.Any("mID", mID), zap.String("topic", topic),
+			zap.String("ddl", string(message.Value)))
+	}
+	mq.IncPublishedDDLSuccess(topic, p.id.Name(), message)
+	return nil
+}
+
+func (p *pulsarDDLProducer) getProducerByTopic(topicName string) (pulsar.Producer, error) {
+	if target, ok := p.producers.Get(topicName); ok {
+		if producer, ok := target.(pulsar.Producer); ok && producer != nil {
+			return producer, nil
+		}
+	}
+
+	producer, err := newPulsarProducer(p.pConfig, p.client, topicName)
+	if err != nil {
+		return nil, err
+	}
+	p.producers.Add(topicName, producer)
+	return producer, nil
+}
+
+// Close closes all producers and the client.
+func (p *pulsarDDLProducer) Close() {
+	p.closedMu.Lock()
+	defer p.closedMu.Unlock()
+	if p.closed {
+		log.Warn("Pulsar DDL producer already closed",
+			zap.String("namespace", p.id.Namespace()),
+			zap.String("changefeed", p.id.Name()))
+		return
+	}
+	p.closed = true
+
+	for _, topic := range p.producers.Keys() {
+		// the evict callback closes the producer.
+		p.producers.Remove(topic)
+	}
+	p.client.Close()
+}
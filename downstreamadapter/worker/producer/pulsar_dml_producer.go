@@ -0,0 +1,314 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	commonType "github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dmlproducer"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/mq"
+	ticonfig "github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"go.uber.org/zap"
+)
+
+// PulsarDMLProducer is the producer used by the pulsar DML worker.
+// Unlike kafka, the send results of pulsar are delivered by the callbacks
+// of the pulsar client, so the producer reports the async errors by Run.
+type PulsarDMLProducer interface {
+	dmlproducer.DMLProducer
+	// Run blocks until the context is canceled or an async send error occurs.
+	Run(ctx context.Context) error
+}
+
+// PulsarDMLProducerCreator defines the type of pulsar DML producer creator.
+type PulsarDMLProducerCreator func(
+	changefeedID commonType.ChangeFeedID,
+	client pulsar.Client,
+	sinkConfig *config.SinkConfig,
+) (PulsarDMLProducer, error)
+
+// pulsarDMLProducer is used to send messages to pulsar.
+type pulsarDMLProducer struct {
+	// id indicates which processor (changefeed) this sink belongs to.
+	id commonType.ChangeFeedID
+	// We hold the client to make close operation faster.
+	client pulsar.Client
+	// producers is used to send messages to pulsar.
+	// One topic only uses one producer, we use lru to limit the memory
+	// used by the producers when there are many topics.
+	producers *lru.Cache
+
+	// closedMu is used to protect `closed`.
+	// We need to ensure that closed producers are never written to.
+	closedMu sync.RWMutex
+	// closed is used to indicate whether the producer is closed.
+	// We also use it to guard against double closes.
+	closed bool
+	// errCh is used to report the async send errors.
+	errCh chan error
+
+	pConfig *config.PulsarConfig
+}
+
+// NewPulsarDMLProducer creates a new pulsar DML producer.
+func NewPulsarDMLProducer(
+	changefeedID commonType.ChangeFeedID,
+	client pulsar.Client,
+	sinkConfig *config.SinkConfig,
+) (PulsarDMLProducer, error) {
+	log.Info("Creating pulsar DML producer ...",
+		zap.String("namespace", changefeedID.Namespace()),
+		zap.String("changefeed", changefeedID.Name()))
+	start := time.Now()
+
+	if sinkConfig.PulsarConfig == nil {
+		log.Error("new pulsar DML producer fail, sink:pulsar config is empty")
+		return nil, cerror.ErrPulsarInvalidConfig.
+			GenWithStackByArgs("pulsar config is empty")
+	}
+
+	pulsarConfig := sinkConfig.PulsarConfig
+	defaultTopicName := pulsarConfig.GetDefaultTopicName()
+	defaultProducer, err := newPulsarProducer(pulsarConfig, client, defaultTopicName)
+	if err != nil {
+		go client.Close()
+		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+
+	producers, err := newPulsarProducerCache(pulsarConfig)
+	if err != nil {
+		go client.Close()
+		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+	producers.Add(defaultTopicName, defaultProducer)
+
+	p := &pulsarDMLProducer{
+		id:        changefeedID,
+		client:    client,
+		producers: producers,
+		pConfig:   pulsarConfig,
+		closed:    false,
+		errCh:     make(chan error, 1),
+	}
+	log.Info("Pulsar DML producer created",
+		zap.String("namespace", changefeedID.Namespace()),
+		zap.String("changefeed", changefeedID.Name()),
+		zap.Duration("duration", time.Since(start)))
+	return p, nil
+}
+
+// AsyncSendMessage sends one message asynchronously,
+// the callback of the message is called once pulsar acks it.
+func (p *pulsarDMLProducer) AsyncSendMessage(
+	ctx context.Context, topic string,
+	partition int32, message *common.Message,
+) error {
+	wrapperSchemaAndTopic(message)
+
+	// We have to hold the lock to avoid writing to a closed producer.
+	// Close may be blocked for a long time.
+	p.closedMu.RLock()
+	defer p.closedMu.RUnlock()
+
+	// If producers are closed, we should skip the message and return an error.
+	if p.closed {
+		return cerror.ErrPulsarProducerClosed.GenWithStackByArgs()
+	}
+
+	producer, err := p.getProducerByTopic(topic)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	data := &pulsar.ProducerMessage{
+		Payload: message.Value,
+		Key:     message.GetPartitionKey(),
+	}
+	producer.SendAsync(ctx, data,
+		func(id pulsar.MessageID, m *pulsar.ProducerMessage, err error) {
+			if err != nil {
+				e := cerror.WrapError(cerror.ErrPulsarAsyncSendMessage, err)
+				log.Error("Pulsar DML producer async send error",
+					zap.String("namespace", p.id.Namespace()),
+					zap.String("changefeed", p.id.Name()),
+					zap.Int("messageSize", len(m.Payload)),
+					zap.String("topic", topic),
+					zap.String("schema", message.GetSchema()),
+					zap.Error(err))
+				mq.IncPublishedDMLFail(topic, p.id.Name(), message.GetSchema())
+				select {
+				case <-ctx.Done():
+				case p.errCh <- e:
+				default:
+					log.Warn("Error channel is full in pulsar DML producer",
+						zap.String("namespace", p.id.Namespace()),
+						zap.String("changefeed", p.id.Name()),
+						zap.Error(e))
+				}
+				return
+			}
+			if message.Callback != nil {
+				message.Callback()
+			}
+			mq.IncPublishedDMLSuccess(topic, p.id.Name(), message.GetSchema())
+		})
+	mq.IncPublishedDMLCount(topic, p.id.Name(), message.GetSchema())
+	return nil
+}
+
+func (p *pulsarDMLProducer) Run(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case err := <-p.errCh:
+		return errors.Trace(err)
+	}
+}
+
+func (p *pulsarDMLProducer) Close() {
+	// We have to hold the lock to synchronize closing with writing.
+	p.closedMu.Lock()
+	defer p.closedMu.Unlock()
+	// If the producer has already been closed, we should skip this close operation.
+	if p.closed {
+		// We need to guard against double closing the clients,
+		// which could lead to panic.
+		log.Warn("Pulsar DML producer already closed",
+			zap.String("namespace", p.id.Namespace()),
+			zap.String("changefeed", p.id.Name()))
+		return
+	}
+	p.closed = true
+
+	start := time.Now()
+	for _, topic := range p.producers.Keys() {
+		// the evict callback closes the producer.
+		p.producers.Remove(topic)
+		log.Info("Async client closed in pulsar DML producer",
+			zap.String("namespace", p.id.Namespace()),
+			zap.String("changefeed", p.id.Name()),
+			zap.Any("topic", topic),
+			zap.Duration("duration", time.Since(start)))
+	}
+	p.client.Close()
+}
+
+// getProducerByTopic returns the producer of the topic,
+// a new producer is created and cached if it does not exist.
+func (p *pulsarDMLProducer) getProducerByTopic(topicName string) (pulsar.Producer, error) {
+	if target, ok := p.producers.Get(topicName); ok {
+		if producer, ok := target.(pulsar.Producer); ok && producer != nil {
+			return producer, nil
+		}
+	}
+
+	producer, err := newPulsarProducer(p.pConfig, p.client, topicName)
+	if err != nil {
+		return nil, err
+	}
+	p.producers.Add(topicName, producer)
+	return producer, nil
+}
+
+// newPulsarProducerCache creates the lru cache of the pulsar producers,
+// evicted producers are closed.
+func newPulsarProducerCache(pConfig *config.PulsarConfig) (*lru.Cache, error) {
+	producerCacheSize := config.DefaultPulsarProducerCacheSize
+	if pConfig != nil && pConfig.PulsarProducerCacheSize != nil {
+		producerCacheSize = int(*pConfig.PulsarProducerCacheSize)
+	}
+	return lru.NewWithEvict(producerCacheSize, func(_ interface{}, value interface{}) {
+		pulsarProducer, ok := value.(pulsar.Producer)
+		if ok && pulsarProducer != nil {
+			pulsarProducer.Close()
+		}
+	})
+}
+
+// newPulsarProducer creates a pulsar producer,
+// one topic is used by one producer.
+func newPulsarProducer(
+	pConfig *config.PulsarConfig,
+	client pulsar.Client,
+	topicName string,
+) (pulsar.Producer, error) {
+	maxReconnectToBroker := uint(config.DefaultMaxReconnectToPulsarBroker)
+	option := pulsar.ProducerOptions{
+		Topic:                topicName,
+		MaxReconnectToBroker: &maxReconnectToBroker,
+	}
+	if pConfig.BatchingMaxMessages != nil {
+		option.BatchingMaxMessages = *pConfig.BatchingMaxMessages
+	}
+	if pConfig.BatchingMaxPublishDelay != nil {
+		option.BatchingMaxPublishDelay = pConfig.BatchingMaxPublishDelay.Duration()
+	}
+	if pConfig.CompressionType != nil {
+		option.CompressionType = pConfig.CompressionType.Value()
+		option.CompressionLevel = pulsar.Default
+	}
+	if pConfig.SendTimeout != nil {
+		option.SendTimeout = pConfig.SendTimeout.Duration()
+	}
+
+	producer, err := client.CreateProducer(option)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("create pulsar producer success", zap.String("topic", topicName))
+	return producer, nil
+}
+
+// wrapperSchemaAndTopic fills the schema and table of the message
+// for the protocols which don't carry them in the message.
+func wrapperSchemaAndTopic(m *common.Message) {
+	if m.Schema != nil {
+		return
+	}
+	switch m.Protocol {
+	case ticonfig.ProtocolMaxwell:
+		mx := &maxwellMessage{}
+		if err := json.Unmarshal(m.Value, mx); err != nil {
+			log.Error("unmarshal maxwell message failed", zap.Error(err))
+			return
+		}
+		if len(mx.Database) > 0 {
+			m.Schema = &mx.Database
+		}
+		if len(mx.Table) > 0 {
+			m.Table = &mx.Table
+		}
+	case ticonfig.ProtocolCanal:
+		// canal protocol sets multi schemas in one topic
+		schema := "multi_schema"
+		m.Schema = &schema
+	}
+}
+
+// maxwellMessage is the message format of maxwell
+type maxwellMessage struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package producer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/errors"
+	commonType "github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+)
+
+// MockPulsarDMLProducer is a mock pulsar DML producer which keeps the messages in memory,
+// the callback of the message is called as soon as it is sent.
+type MockPulsarDMLProducer struct {
+	mu     sync.Mutex
+	events map[string][]*pulsar.ProducerMessage
+}
+
+// NewMockPulsarDMLProducer creates a mock pulsar DML producer.
+func NewMockPulsarDMLProducer(
+	_ commonType.ChangeFeedID,
+	_ pulsar.Client,
+	_ *config.SinkConfig,
+) (PulsarDMLProducer, error) {
+	return &MockPulsarDMLProducer{
+		events: make(map[string][]*pulsar.ProducerMessage),
+	}, nil
+}
+
+// AsyncSendMessage records the message and calls its callback.
+func (p *MockPulsarDMLProducer) AsyncSendMessage(
+	_ context.Context, topic string,
+	_ int32, message *common.Message,
+) error {
+	p.mu.Lock()
+	p.events[topic] = append(p.events[topic], &pulsar.ProducerMessage{
+		Payload: message.Value,
+		Key:     message.GetPartitionKey(),
+	})
+	p.mu.Unlock()
+
+	if message.Callback != nil {
+		message.Callback()
+	}
+	return nil
+}
+
+// Run blocks until the context is canceled.
+func (p *MockPulsarDMLProducer) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return errors.Trace(ctx.Err())
+}
+
+// Close implements the DMLProducer interface.
+func (p *MockPulsarDMLProducer) Close() {}
+
+// GetEvents returns the messages sent to the topic.
+func (p *MockPulsarDMLProducer) GetEvents(topic string) []*pulsar.ProducerMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.events[topic]
+}
+
+// Assert DDLProducer implementation
+var _ ddlproducer.DDLProducer = (*MockPulsarDDLProducer)(nil)
+
+// MockPulsarDDLProducer is a mock pulsar DDL producer which keeps the messages in memory.
+type MockPulsarDDLProducer struct {
+	mu     sync.Mutex
+	events map[string][]*pulsar.ProducerMessage
+}
+
+// NewMockPulsarDDLProducer creates a mock pulsar DDL producer.
+func NewMockPulsarDDLProducer(
+	_ commonType.ChangeFeedID,
+	_ *config.PulsarConfig,
+	_ pulsar.Client,
+) (ddlproducer.DDLProducer, error) {
+	return &MockPulsarDDLProducer{
+		events: make(map[string][]*pulsar.ProducerMessage),
+	}, nil
+}
+
+// SyncBroadcastMessage records the message.
+func (p *MockPulsarDDLProducer) SyncBroadcastMessage(ctx context.Context, topic string,
+	totalPartitionsNum int32, message *common.Message,
+) error {
+	return p.SyncSendMessage(ctx, topic, totalPartitionsNum, message)
+}
+
+// SyncSendMessage records the message.
+func (p *MockPulsarDDLProducer) SyncSendMessage(_ context.Context, topic string,
+	_ int32, message *common.Message,
+) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events[topic] = append(p.events[topic], &pulsar.ProducerMessage{
+		Payload: message.Value,
+		Key:     message.GetPartitionKey(),
+	})
+	return nil
+}
+
+// Close implements the DDLProducer interface.
+func (p *MockPulsarDDLProducer) Close() {}
+
+// GetEvents returns the messages sent to the topic.
+func (p *MockPulsarDDLProducer) GetEvents(topic string) []*pulsar.ProducerMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.events[topic]
+}
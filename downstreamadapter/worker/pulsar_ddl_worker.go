@@ -0,0 +1,183 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/helper/eventrouter"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/helper/topicmanager"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/ticdc/pkg/sink/codec/encoder"
+	"github.com/pingcap/ticdc/pkg/sink/util"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// PulsarDDLWorker sends the DDL events and the checkpoint ts to pulsar.
+// The partitions of a pulsar topic are decided by the message key,
+// so the DDL messages are always sent to the topic as a whole.
+type PulsarDDLWorker struct {
+	// changeFeedID indicates this sink belongs to which processor(changefeed).
+	changeFeedID common.ChangeFeedID
+	// protocol indicates the protocol used by this sink.
+	protocol         config.Protocol
+	checkpointTsChan chan uint64
+
+	encoder encoder.EventEncoder
+	// eventRouter used to route events to the right topic.
+	eventRouter *eventrouter.EventRouter
+	// topicManager used to get the partition number of the topic.
+	topicManager topicmanager.TopicManager
+
+	// producer is used to send the messages to the pulsar broker.
+	producer ddlproducer.DDLProducer
+
+	tableSchemaStore *util.TableSchemaStore
+
+	statistics *metrics.Statistics
+	ctx        context.Context
+	cancel     context.CancelFunc
+	errGroup   *errgroup.Group
+}
+
+// NewPulsarDDLWorker creates a ddl worker for pulsar.
+func NewPulsarDDLWorker(
+	ctx context.Context,
+	id common.ChangeFeedID,
+	protocol config.Protocol,
+	producer ddlproducer.DDLProducer,
+	encoder encoder.EventEncoder,
+	eventRouter *eventrouter.EventRouter,
+	topicManager topicmanager.TopicManager,
+	statistics *metrics.Statistics,
+	errGroup *errgroup.Group,
+) *PulsarDDLWorker {
+	ctx, cancel := context.WithCancel(ctx)
+	return &PulsarDDLWorker{
+		ctx:              ctx,
+		changeFeedID:     id,
+		protocol:         protocol,
+		checkpointTsChan: make(chan uint64, 16),
+		encoder:          encoder,
+		producer:         producer,
+		eventRouter:      eventRouter,
+		topicManager:     topicManager,
+		statistics:       statistics,
+		cancel:           cancel,
+		errGroup:         errGroup,
+	}
+}
+
+func (w *PulsarDDLWorker) Run() {
+	w.errGroup.Go(func() error {
+		return w.encodeAndSendCheckpointEvents()
+	})
+}
+
+func (w *PulsarDDLWorker) GetCheckpointTsChan() chan<- uint64 {
+	return w.checkpointTsChan
+}
+
+func (w *PulsarDDLWorker) SetTableSchemaStore(tableSchemaStore *util.TableSchemaStore) {
+	w.tableSchemaStore = tableSchemaStore
+}
+
+func (w *PulsarDDLWorker) WriteBlockEvent(event *event.DDLEvent) error {
+	message, err := w.encoder.EncodeDDLEvent(event)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	topic := w.eventRouter.GetTopicForDDL(event)
+	partitionNum, err := w.topicManager.GetPartitionNum(w.ctx, topic)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = w.statistics.RecordDDLExecution(func() error {
+		return w.producer.SyncBroadcastMessage(w.ctx, topic, partitionNum, message)
+	})
+	return errors.Trace(err)
+}
+
+func (w *PulsarDDLWorker) encodeAndSendCheckpointEvents() error {
+	checkpointTsMessageDuration := metrics.CheckpointTsMessageDuration.WithLabelValues(w.changeFeedID.Namespace(), w.changeFeedID.Name())
+	checkpointTsMessageCount := metrics.CheckpointTsMessageCount.WithLabelValues(w.changeFeedID.Namespace(), w.changeFeedID.Name())
+
+	defer func() {
+		metrics.CheckpointTsMessageDuration.DeleteLabelValues(w.changeFeedID.Namespace(), w.changeFeedID.Name())
+		metrics.CheckpointTsMessageCount.DeleteLabelValues(w.changeFeedID.Namespace(), w.changeFeedID.Name())
+	}()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return errors.Trace(w.ctx.Err())
+		case ts, ok := <-w.checkpointTsChan:
+			if !ok {
+				log.Warn("Pulsar sink checkpoint ts channel closed",
+					zap.String("namespace", w.changeFeedID.Namespace()),
+					zap.String("changefeed", w.changeFeedID.Name()))
+				return nil
+			}
+			start := time.Now()
+
+			msg, err := w.encoder.EncodeCheckpointEvent(ts)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if msg == nil {
+				continue
+			}
+
+			// NOTICE: When there are no tables to replicate,
+			// we need to send checkpoint ts to the default topic.
+			// This will be compatible with the old behavior.
+			topics := []string{w.eventRouter.GetDefaultTopic()}
+			tableNames := w.tableSchemaStore.GetAllTableNames(ts)
+			if len(tableNames) != 0 {
+				topics = w.eventRouter.GetActiveTopics(tableNames)
+			}
+			for _, topic := range topics {
+				partitionNum, err := w.topicManager.GetPartitionNum(w.ctx, topic)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				log.Debug("Emit checkpointTs to pulsar topic",
+					zap.String("topic", topic), zap.Uint64("checkpointTs", ts))
+				err = w.producer.SyncBroadcastMessage(w.ctx, topic, partitionNum, msg)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+
+			checkpointTsMessageCount.Inc()
+			checkpointTsMessageDuration.Observe(time.Since(start).Seconds())
+		}
+	}
+}
+
+func (w *PulsarDDLWorker) Close() error {
+	w.cancel()
+	w.producer.Close()
+	return nil
+}
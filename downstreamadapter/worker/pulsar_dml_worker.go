@@ -0,0 +1,347 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/helper/eventrouter"
+	"github.com/pingcap/ticdc/downstreamadapter/sink/helper/topicmanager"
+	"github.com/pingcap/ticdc/downstreamadapter/worker/producer"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/common/columnselector"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/ticdc/pkg/sink/codec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// PulsarDMLWorker encodes the DML events and sends the messages to pulsar.
+type PulsarDMLWorker struct {
+	changeFeedID common.ChangeFeedID
+	protocol     config.Protocol
+
+	eventChan chan *commonEvent.DMLEvent
+	rowChan   chan *commonEvent.MQRowEvent
+	// ticker used to force flush the batched messages when the interval is reached.
+	ticker *time.Ticker
+
+	columnSelector *columnselector.ColumnSelectors
+	// eventRouter used to route events to the right topic and partition.
+	eventRouter *eventrouter.EventRouter
+	// topicManager used to get the partition number of the topic.
+	topicManager topicmanager.TopicManager
+	encoderGroup codec.EncoderGroup
+
+	// producer is used to send the messages to the pulsar broker.
+	producer producer.PulsarDMLProducer
+
+	// statistics is used to record DML metrics.
+	statistics *metrics.Statistics
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	errGroup *errgroup.Group
+}
+
+// NewPulsarDMLWorker creates a dml flush worker for pulsar
+func NewPulsarDMLWorker(
+	ctx context.Context,
+	id common.ChangeFeedID,
+	protocol config.Protocol,
+	producer producer.PulsarDMLProducer,
+	encoderGroup codec.EncoderGroup,
+	columnSelector *columnselector.ColumnSelectors,
+	eventRouter *eventrouter.EventRouter,
+	topicManager topicmanager.TopicManager,
+	statistics *metrics.Statistics,
+	errGroup *errgroup.Group,
+) *PulsarDMLWorker {
+	ctx, cancel := context.WithCancel(ctx)
+	return &PulsarDMLWorker{
+		ctx:            ctx,
+		changeFeedID:   id,
+		protocol:       protocol,
+		eventChan:      make(chan *commonEvent.DMLEvent, 32),
+		rowChan:        make(chan *commonEvent.MQRowEvent, 32),
+		ticker:         time.NewTicker(batchInterval),
+		encoderGroup:   encoderGroup,
+		columnSelector: columnSelector,
+		eventRouter:    eventRouter,
+		topicManager:   topicManager,
+		producer:       producer,
+		statistics:     statistics,
+		cancel:         cancel,
+		errGroup:       errGroup,
+	}
+}
+
+func (w *PulsarDMLWorker) Run() {
+	w.errGroup.Go(func() error {
+		return w.producer.Run(w.ctx)
+	})
+
+	w.errGroup.Go(func() error {
+		return w.calculateKeyPartitions()
+	})
+
+	w.errGroup.Go(func() error {
+		return w.encoderGroup.Run(w.ctx)
+	})
+
+	w.errGroup.Go(func() error {
+		if w.protocol.IsBatchEncode() {
+			return w.batchEncodeRun()
+		}
+		return w.nonBatchEncodeRun()
+	})
+
+	w.errGroup.Go(func() error {
+		return w.sendMessages()
+	})
+}
+
+func (w *PulsarDMLWorker) GetEventChan() chan<- *commonEvent.DMLEvent {
+	return w.eventChan
+}
+
+// calculateKeyPartitions splits the DML events into rows and calculates the partition key of each row.
+// The partition index is always 0, pulsar hashes the partition key to the real partition.
+func (w *PulsarDMLWorker) calculateKeyPartitions() error {
+	for {
+		select {
+		case <-w.ctx.Done():
+			return errors.Trace(w.ctx.Err())
+		case event := <-w.eventChan:
+			topic := w.eventRouter.GetTopicForRowChange(event.TableInfo)
+			partitionNum, err := w.topicManager.GetPartitionNum(w.ctx, topic)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			partitionGenerator := w.eventRouter.GetPartitionGeneratorForRowChange(event.TableInfo)
+			selector := w.columnSelector.GetSelector(event.TableInfo.TableName.Schema, event.TableInfo.TableName.Table)
+
+			rowsCount := uint64(event.Len())
+			postTxnFlushed := event.PostTxnFlushed
+			var calledCount atomic.Uint64
+			// The callback of the last row will trigger the callback of the txn.
+			rowCallback := func() {
+				if calledCount.Inc() == rowsCount {
+					for _, callback := range postTxnFlushed {
+						callback()
+					}
+				}
+			}
+
+			for {
+				row, ok := event.GetNextRow()
+				if !ok {
+					break
+				}
+
+				index, key, err := partitionGenerator.GeneratePartitionIndexAndKey(&row, partitionNum, event.TableInfo, event.CommitTs)
+				if err != nil {
+					return errors.Trace(err)
+				}
+
+				select {
+				case <-w.ctx.Done():
+					return errors.Trace(w.ctx.Err())
+				case w.rowChan <- &commonEvent.MQRowEvent{
+					Key: model.TopicPartitionKey{
+						Topic:          topic,
+						Partition:      index,
+						PartitionKey:   key,
+						TotalPartition: partitionNum,
+					},
+					RowEvent: commonEvent.RowEvent{
+						PhysicalTableID: event.PhysicalTableID,
+						TableInfo:       event.TableInfo,
+						StartTs:         event.StartTs,
+						CommitTs:        event.CommitTs,
+						Event:           row,
+						Callback:        rowCallback,
+						ColumnSelector:  selector,
+					},
+				}:
+				}
+			}
+		}
+	}
+}
+
+// nonBatchEncodeRun adds events to the encoder group immediately.
+func (w *PulsarDMLWorker) nonBatchEncodeRun() error {
+	log.Info("Pulsar sink non batch worker started",
+		zap.String("namespace", w.changeFeedID.Namespace()),
+		zap.String("changefeed", w.changeFeedID.Name()),
+		zap.String("protocol", w.protocol.String()),
+	)
+	for {
+		select {
+		case <-w.ctx.Done():
+			return errors.Trace(w.ctx.Err())
+		case event, ok := <-w.rowChan:
+			if !ok {
+				log.Warn("Pulsar sink flush worker channel closed",
+					zap.String("namespace", w.changeFeedID.Namespace()),
+					zap.String("changefeed", w.changeFeedID.Name()))
+				return nil
+			}
+			if err := w.encoderGroup.AddEvents(w.ctx, event.Key, &event.RowEvent); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// batchEncodeRun collects messages into batch and adds them to the encoder group.
+func (w *PulsarDMLWorker) batchEncodeRun() error {
+	log.Info("Pulsar sink batch worker started",
+		zap.String("namespace", w.changeFeedID.Namespace()),
+		zap.String("changefeed", w.changeFeedID.Name()),
+		zap.String("protocol", w.protocol.String()),
+	)
+
+	namespace, changefeed := w.changeFeedID.Namespace(), w.changeFeedID.Name()
+	metricBatchDuration := metrics.WorkerBatchDuration.WithLabelValues(namespace, changefeed)
+	metricBatchSize := metrics.WorkerBatchSize.WithLabelValues(namespace, changefeed)
+	defer func() {
+		metrics.WorkerBatchDuration.DeleteLabelValues(namespace, changefeed)
+		metrics.WorkerBatchSize.DeleteLabelValues(namespace, changefeed)
+	}()
+
+	msgsBuf := make([]*commonEvent.MQRowEvent, batchSize)
+	for {
+		start := time.Now()
+		msgCount, err := w.batch(msgsBuf, batchInterval)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if msgCount == 0 {
+			continue
+		}
+
+		metricBatchSize.Observe(float64(msgCount))
+		metricBatchDuration.Observe(time.Since(start).Seconds())
+
+		// Group messages by its TopicPartitionKey before adding them to the encoder group.
+		groupedMsgs := make(map[model.TopicPartitionKey][]*commonEvent.RowEvent)
+		for _, msg := range msgsBuf[:msgCount] {
+			groupedMsgs[msg.Key] = append(groupedMsgs[msg.Key], &msg.RowEvent)
+		}
+		for key, msg := range groupedMsgs {
+			if err := w.encoderGroup.AddEvents(w.ctx, key, msg...); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// batch collects a batch of messages from w.rowChan into buffer.
+// It returns the number of messages collected.
+// Note: It will block until at least one message is received.
+func (w *PulsarDMLWorker) batch(buffer []*commonEvent.MQRowEvent, flushInterval time.Duration) (int, error) {
+	msgCount := 0
+	maxBatchSize := len(buffer)
+	// We need to receive at least one message or be interrupted,
+	// otherwise it will lead to idling.
+	select {
+	case <-w.ctx.Done():
+		return msgCount, w.ctx.Err()
+	case msg, ok := <-w.rowChan:
+		if !ok {
+			log.Warn("Pulsar sink flush worker channel closed")
+			return msgCount, nil
+		}
+		buffer[msgCount] = msg
+		msgCount++
+	}
+
+	// Reset the ticker to start a new batching.
+	// We need to stop batching when the interval is reached.
+	w.ticker.Reset(flushInterval)
+	for {
+		select {
+		case <-w.ctx.Done():
+			return msgCount, w.ctx.Err()
+		case msg, ok := <-w.rowChan:
+			if !ok {
+				log.Warn("Pulsar sink flush worker channel closed")
+				return msgCount, nil
+			}
+			buffer[msgCount] = msg
+			msgCount++
+			if msgCount >= maxBatchSize {
+				return msgCount, nil
+			}
+		case <-w.ticker.C:
+			return msgCount, nil
+		}
+	}
+}
+
+func (w *PulsarDMLWorker) sendMessages() error {
+	metricSendMessageDuration := metrics.WorkerSendMessageDuration.WithLabelValues(w.changeFeedID.Namespace(), w.changeFeedID.Name())
+	defer metrics.WorkerSendMessageDuration.DeleteLabelValues(w.changeFeedID.Namespace(), w.changeFeedID.Name())
+
+	outCh := w.encoderGroup.Output()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return errors.Trace(w.ctx.Err())
+		case future, ok := <-outCh:
+			if !ok {
+				log.Warn("Pulsar sink encoder's output channel closed",
+					zap.String("namespace", w.changeFeedID.Namespace()),
+					zap.String("changefeed", w.changeFeedID.Name()))
+				return nil
+			}
+			if err := future.Ready(w.ctx); err != nil {
+				return errors.Trace(err)
+			}
+			for _, message := range future.Messages {
+				start := time.Now()
+				if err := w.statistics.RecordBatchExecution(func() (int, int64, error) {
+					message.SetPartitionKey(future.Key.PartitionKey)
+					if err := w.producer.AsyncSendMessage(
+						w.ctx,
+						future.Key.Topic,
+						future.Key.Partition,
+						message); err != nil {
+						return 0, 0, err
+					}
+					return message.GetRowsCount(), int64(message.Length()), nil
+				}); err != nil {
+					return errors.Trace(err)
+				}
+				metricSendMessageDuration.Observe(time.Since(start).Seconds())
+			}
+		}
+	}
+}
+
+func (w *PulsarDMLWorker) Close() error {
+	w.ticker.Stop()
+	w.cancel()
+	w.producer.Close()
+	return nil
+}
@@ -527,6 +527,7 @@ type RegisterDispatcherRequest struct {
 	EnableSyncPoint   bool                      `protobuf:"varint,8,opt,name=enable_sync_point,json=enableSyncPoint,proto3" json:"enable_sync_point,omitempty"`
 	SyncPointTs       uint64                    `protobuf:"varint,9,opt,name=sync_point_ts,json=syncPointTs,proto3" json:"sync_point_ts,omitempty"`
 	SyncPointInterval uint64                    `protobuf:"varint,10,opt,name=sync_point_interval,json=syncPointInterval,proto3" json:"sync_point_interval,omitempty"`
+	BdrMode           bool                      `protobuf:"varint,11,opt,name=bdr_mode,json=bdrMode,proto3" json:"bdr_mode,omitempty"`
 }
 
 func (m *RegisterDispatcherRequest) Reset()         { *m = RegisterDispatcherRequest{} }
@@ -632,6 +633,13 @@ func (m *RegisterDispatcherRequest) GetSyncPointInterval() uint64 {
 	return 0
 }
 
+func (m *RegisterDispatcherRequest) GetBdrMode() bool {
+	if m != nil {
+		return m.BdrMode
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("eventpb.OpType", OpType_name, OpType_value)
 	proto.RegisterEnum("eventpb.ActionType", ActionType_name, ActionType_value)
@@ -648,65 +656,65 @@ func init() {
 func init() { proto.RegisterFile("eventpb/event.proto", fileDescriptor_d7fb2554dfcf7f7d) }
 
 var fileDescriptor_d7fb2554dfcf7f7d = []byte{
-	// 918 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x41, 0x6f, 0xe3, 0x44,
-	0x14, 0x8e, 0x93, 0x34, 0x89, 0x9f, 0xd3, 0x5d, 0x77, 0xba, 0x5d, 0xdc, 0x2d, 0x84, 0x90, 0x03,
-	0x0a, 0x95, 0x48, 0x21, 0x80, 0x90, 0x56, 0x68, 0xa5, 0xd2, 0x7a, 0x91, 0x0f, 0xdb, 0x46, 0x63,
-	0x77, 0x25, 0xb8, 0x58, 0x8e, 0x3d, 0x49, 0x0d, 0xee, 0xd8, 0xf5, 0x4c, 0xb2, 0xc9, 0xbf, 0x80,
-	0x9f, 0xc0, 0xbf, 0xe1, 0xb8, 0x47, 0x6e, 0xa0, 0xf6, 0xc0, 0x9f, 0xe0, 0x80, 0x3c, 0xe3, 0xd8,
-	0xce, 0x16, 0x21, 0x71, 0xca, 0xcc, 0xfb, 0xbe, 0x37, 0xf3, 0xbe, 0xef, 0xcd, 0x73, 0x60, 0x9f,
-	0x2c, 0x09, 0xe5, 0xc9, 0xf4, 0x44, 0xfc, 0x8e, 0x92, 0x34, 0xe6, 0x31, 0x6a, 0xe7, 0xc1, 0x67,
-	0x47, 0xd7, 0xc4, 0x4b, 0xf9, 0x94, 0x78, 0x19, 0xa3, 0x58, 0x4b, 0xd6, 0xe0, 0x8f, 0x3a, 0x3c,
-	0x36, 0x33, 0xe2, 0xcb, 0x30, 0xe2, 0x24, 0xc5, 0x8b, 0x88, 0x20, 0x03, 0xda, 0x37, 0x1e, 0xf7,
-	0xaf, 0x49, 0x6a, 0x28, 0xfd, 0xc6, 0x50, 0xc5, 0x9b, 0x2d, 0xfa, 0x08, 0xba, 0xe1, 0x9c, 0xc6,
-	0x29, 0x71, 0xc5, 0xe1, 0x46, 0x5d, 0xc0, 0x9a, 0x8c, 0x89, 0x63, 0xd0, 0x07, 0x00, 0x39, 0x85,
-	0xdd, 0x46, 0x46, 0x43, 0x10, 0x54, 0x19, 0xb1, 0x6f, 0x23, 0xf4, 0x35, 0x18, 0x39, 0x1c, 0x52,
-	0x46, 0x52, 0xee, 0x2e, 0xbd, 0x68, 0x41, 0x5c, 0xb2, 0x4a, 0x52, 0xa3, 0xd9, 0x57, 0x86, 0x2a,
-	0x3e, 0x90, 0xb8, 0x25, 0xe0, 0xd7, 0x19, 0x6a, 0xae, 0x92, 0x14, 0xbd, 0x80, 0xf7, 0xf3, 0xc4,
-	0x45, 0x12, 0x78, 0x9c, 0xb8, 0x94, 0xbc, 0xa9, 0x26, 0xef, 0x88, 0xe4, 0xfc, 0xf0, 0x2b, 0x41,
-	0xb9, 0x20, 0x6f, 0xfe, 0x23, 0x3f, 0x8e, 0x82, 0x6a, 0x7e, 0xeb, 0x61, 0xfe, 0x65, 0x14, 0x94,
-	0xf9, 0x65, 0xe1, 0x01, 0x89, 0x08, 0x27, 0xd5, 0xdc, 0x76, 0xb5, 0xf0, 0x73, 0x01, 0x17, 0x89,
-	0x83, 0x5f, 0x14, 0xe8, 0x4a, 0x73, 0xcf, 0x62, 0x3a, 0x0b, 0xe7, 0xe8, 0x09, 0xec, 0xa4, 0x8b,
-	0x88, 0xb0, 0xdc, 0x5c, 0xb9, 0x41, 0x9f, 0xc2, 0x7e, 0x7e, 0x3e, 0x5f, 0x51, 0x97, 0x71, 0x2f,
-	0xe5, 0x2e, 0x67, 0xc2, 0xe1, 0x26, 0xd6, 0x25, 0xe4, 0xac, 0xa8, 0x9d, 0x01, 0x0e, 0x43, 0xdf,
-	0x40, 0xb7, 0xd2, 0x36, 0x26, 0x8c, 0xd6, 0xc6, 0xc6, 0x28, 0x6f, 0xfa, 0xe8, 0x9d, 0x9e, 0xe2,
-	0x2d, 0xf6, 0xa0, 0x0b, 0x80, 0x09, 0x8b, 0xa3, 0x25, 0x09, 0x1c, 0x36, 0x58, 0xc0, 0x8e, 0xec,
-	0x9d, 0x0e, 0x8d, 0x9f, 0xc8, 0xda, 0x50, 0xfa, 0xca, 0xb0, 0x8b, 0xb3, 0x65, 0x56, 0xab, 0xd0,
-	0x69, 0xd4, 0x45, 0x4c, 0x6e, 0xd0, 0x33, 0xe8, 0x6c, 0xbc, 0x31, 0x1a, 0x02, 0x28, 0xf6, 0x68,
-	0x08, 0xed, 0x38, 0x71, 0xf9, 0x3a, 0x21, 0xa2, 0x9f, 0x8f, 0xc6, 0x8f, 0x8b, 0x9a, 0x2e, 0x13,
-	0x67, 0x9d, 0x10, 0xdc, 0x8a, 0xc5, 0xef, 0xe0, 0x47, 0xe8, 0x38, 0x2b, 0x2a, 0x6f, 0xfe, 0x18,
-	0x5a, 0x82, 0x25, 0x4d, 0xd1, 0xc6, 0x8f, 0xb6, 0x85, 0xe0, 0x1c, 0x45, 0x47, 0xa0, 0xfa, 0xf1,
-	0xcd, 0x4d, 0x98, 0x7b, 0xa3, 0x0c, 0x9b, 0xb8, 0x23, 0x03, 0x0e, 0x43, 0x87, 0xd0, 0x29, 0x7c,
-	0x6b, 0x08, 0xac, 0xcd, 0xa4, 0x5d, 0x03, 0x0d, 0x54, 0xc7, 0x9b, 0x46, 0xc4, 0xa2, 0xb3, 0x78,
-	0xf0, 0x97, 0x02, 0xaa, 0xb4, 0x83, 0x90, 0x00, 0x7d, 0x06, 0x90, 0x39, 0xbe, 0x75, 0xfd, 0x5e,
-	0x71, 0xfd, 0xa6, 0x42, 0xac, 0xf2, 0x7c, 0xc5, 0xd0, 0x87, 0xa0, 0xa5, 0xb9, 0x7b, 0x65, 0x19,
-	0x90, 0x16, 0x86, 0xa2, 0x17, 0xb0, 0x1b, 0x84, 0x2c, 0x91, 0x43, 0xe3, 0x86, 0x81, 0xa8, 0x46,
-	0x1b, 0x1f, 0x8e, 0x2a, 0x93, 0x38, 0x3a, 0x2f, 0x18, 0xd6, 0x39, 0xee, 0x96, 0x7c, 0x2b, 0x10,
-	0x2f, 0xc4, 0xe3, 0x61, 0x2c, 0x1c, 0xac, 0x63, 0xb9, 0x41, 0x9f, 0x03, 0xf0, 0x4c, 0x83, 0x1b,
-	0xd2, 0x59, 0x2c, 0xde, 0xbb, 0x36, 0x46, 0x65, 0xa1, 0x1b, 0x79, 0x58, 0xe5, 0x85, 0xd2, 0xbf,
-	0x1b, 0x70, 0x88, 0xc9, 0x3c, 0x64, 0x9c, 0xa4, 0xe5, 0x7d, 0x98, 0xdc, 0x2e, 0x08, 0xe3, 0x59,
-	0x99, 0xfe, 0xb5, 0x47, 0xe7, 0x64, 0x46, 0x48, 0x90, 0x95, 0xa9, 0xfc, 0x4b, 0x99, 0x67, 0x05,
-	0x23, 0x2b, 0xb3, 0xe4, 0x5b, 0xc1, 0x43, 0x99, 0xf5, 0xff, 0x27, 0xf3, 0xab, 0x8d, 0x20, 0x96,
-	0x78, 0x34, 0xf7, 0xe8, 0xe9, 0x56, 0xb2, 0x10, 0x65, 0x27, 0x1e, 0xcd, 0x45, 0x65, 0xcb, 0xad,
-	0x36, 0x37, 0xb7, 0xda, 0x9c, 0x3d, 0x0f, 0x46, 0xd2, 0xa5, 0xac, 0x46, 0x7e, 0x11, 0x3a, 0x32,
-	0x60, 0x05, 0xe8, 0x4b, 0xd0, 0x3c, 0x9f, 0x87, 0x31, 0x95, 0xaf, 0xb3, 0x25, 0x5e, 0xe7, 0x7e,
-	0x61, 0xe0, 0xa9, 0xc0, 0xc4, 0x0b, 0x05, 0xaf, 0x58, 0xa3, 0xe7, 0xb0, 0x3b, 0x13, 0x53, 0xe3,
-	0xfa, 0x62, 0x7c, 0xc5, 0xb0, 0x6b, 0xe3, 0x83, 0x22, 0xaf, 0x3a, 0xdb, 0xb8, 0x3b, 0xab, 0x4e,
-	0xfa, 0x31, 0xec, 0x11, 0x2a, 0x15, 0xae, 0xa9, 0xef, 0x26, 0x71, 0x48, 0xb9, 0xd1, 0xe9, 0x2b,
-	0xc3, 0x0e, 0x7e, 0x2c, 0x01, 0x7b, 0x4d, 0xfd, 0x49, 0x16, 0x46, 0x03, 0xd8, 0x2d, 0x49, 0x99,
-	0x34, 0x55, 0x48, 0xd3, 0xd8, 0x86, 0xe1, 0x30, 0x34, 0x82, 0xfd, 0x0a, 0x27, 0xa4, 0x9c, 0xa4,
-	0x4b, 0x2f, 0x32, 0x40, 0x30, 0xf7, 0x0a, 0xa6, 0x95, 0x03, 0xc7, 0x9f, 0x40, 0x4b, 0xce, 0x1c,
-	0xda, 0x05, 0x55, 0xae, 0x26, 0x0b, 0xae, 0xd7, 0x90, 0x0e, 0x5d, 0xb9, 0x95, 0x1f, 0x2b, 0x5d,
-	0x39, 0xfe, 0x55, 0x01, 0x28, 0x1d, 0x40, 0x47, 0xf0, 0xde, 0xe9, 0x99, 0x63, 0x5d, 0x5e, 0xb8,
-	0xce, 0xf7, 0x13, 0xd3, 0xbd, 0xba, 0xb0, 0x27, 0xe6, 0x99, 0xf5, 0xd2, 0x32, 0xcf, 0xf5, 0x1a,
-	0x32, 0xe0, 0x49, 0x15, 0xc4, 0xe6, 0x77, 0x96, 0xed, 0x98, 0x58, 0x57, 0xd0, 0x53, 0x40, 0xdb,
-	0xc8, 0xab, 0xcb, 0xd7, 0xa6, 0x5e, 0x47, 0x07, 0xb0, 0x57, 0x8d, 0x4f, 0x4e, 0xaf, 0x6c, 0x53,
-	0x6f, 0x3c, 0xa4, 0xdb, 0x57, 0xaf, 0x4c, 0xbd, 0xf9, 0x2e, 0x1d, 0x9b, 0xb6, 0xe9, 0xe8, 0x3b,
-	0xdf, 0x3e, 0xff, 0xed, 0xae, 0xa7, 0xbc, 0xbd, 0xeb, 0x29, 0x7f, 0xde, 0xf5, 0x94, 0x9f, 0xef,
-	0x7b, 0xb5, 0xb7, 0xf7, 0xbd, 0xda, 0xef, 0xf7, 0xbd, 0xda, 0x0f, 0xfd, 0x79, 0xc8, 0xaf, 0x17,
-	0xd3, 0x91, 0x1f, 0xdf, 0x9c, 0x24, 0x21, 0x9d, 0xfb, 0x5e, 0x72, 0xc2, 0x43, 0x3f, 0xf0, 0x4f,
-	0xf2, 0x2e, 0x4d, 0x5b, 0xe2, 0xef, 0xee, 0x8b, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0x72, 0x76,
-	0x72, 0xb5, 0x2b, 0x07, 0x00, 0x00,
+	// 919 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x95, 0x55, 0x4d, 0x6f, 0xdb, 0x46,
+	0x10, 0x0d, 0x25, 0x59, 0x1f, 0x43, 0x39, 0xa6, 0xd7, 0x71, 0x4a, 0x27, 0x69, 0xe2, 0xea, 0x50,
+	0xb8, 0x06, 0x2a, 0x27, 0x6a, 0x83, 0x02, 0x41, 0x51, 0xc0, 0xb1, 0x99, 0x40, 0x07, 0xdb, 0xc2,
+	0x8a, 0x0e, 0xd0, 0x5e, 0x08, 0x8a, 0x5c, 0xc9, 0x6c, 0x69, 0x92, 0x26, 0x57, 0x8e, 0xfd, 0x2f,
+	0xd2, 0x53, 0xcf, 0xfd, 0x37, 0x3d, 0xe6, 0xd8, 0x5b, 0x83, 0xe4, 0x90, 0xbf, 0x91, 0xd9, 0x5d,
+	0x8a, 0xa4, 0xe2, 0xa2, 0x40, 0x0f, 0x84, 0x76, 0xe7, 0xbd, 0xd9, 0x7d, 0xf3, 0x66, 0x77, 0x05,
+	0x1b, 0xec, 0x92, 0x45, 0x3c, 0x99, 0xec, 0xc9, 0xdf, 0x7e, 0x92, 0xc6, 0x3c, 0x26, 0xad, 0x3c,
+	0x78, 0xef, 0xfe, 0x19, 0x73, 0x53, 0x3e, 0x61, 0xae, 0x60, 0x14, 0x63, 0xc5, 0xea, 0xfd, 0x53,
+	0x83, 0x35, 0x4b, 0x10, 0x5f, 0x04, 0x21, 0x67, 0x29, 0x9d, 0x87, 0x8c, 0x98, 0xd0, 0x3a, 0x77,
+	0xb9, 0x77, 0xc6, 0x52, 0x53, 0xdb, 0xae, 0xef, 0x74, 0xe8, 0x62, 0x4a, 0xbe, 0x82, 0x6e, 0x30,
+	0x8b, 0xe2, 0x94, 0x39, 0x72, 0x71, 0xb3, 0x26, 0x61, 0x5d, 0xc5, 0xe4, 0x32, 0xe4, 0x4b, 0x80,
+	0x9c, 0x92, 0x5d, 0x84, 0x66, 0x5d, 0x12, 0x3a, 0x2a, 0x32, 0xbe, 0x08, 0xc9, 0x0f, 0x60, 0xe6,
+	0x70, 0x10, 0x65, 0x2c, 0xe5, 0xce, 0xa5, 0x1b, 0xce, 0x71, 0xb9, 0xab, 0x24, 0x35, 0x1b, 0xdb,
+	0x1a, 0x92, 0x37, 0x15, 0x3e, 0x94, 0xf0, 0x2b, 0x81, 0x5a, 0x08, 0x92, 0x9f, 0xe0, 0x41, 0x9e,
+	0x38, 0x4f, 0x7c, 0x97, 0x33, 0x27, 0x62, 0xaf, 0xab, 0xc9, 0x2b, 0x32, 0x39, 0x5f, 0xfc, 0x54,
+	0x52, 0x8e, 0xd9, 0xeb, 0xff, 0xc8, 0x8f, 0x43, 0xbf, 0x9a, 0xdf, 0xbc, 0x99, 0x7f, 0x12, 0xfa,
+	0x65, 0x7e, 0x29, 0xdc, 0x67, 0x21, 0xc3, 0xfc, 0x4a, 0x6e, 0xab, 0x2a, 0xfc, 0x50, 0xc2, 0x45,
+	0x62, 0xef, 0x77, 0x0d, 0xba, 0xca, 0xdc, 0x83, 0x38, 0x9a, 0x06, 0x33, 0x72, 0x07, 0x56, 0x52,
+	0xb4, 0x39, 0xcb, 0xcd, 0x55, 0x13, 0xf2, 0x2d, 0x6c, 0xe4, 0xeb, 0xf3, 0xab, 0xc8, 0xc9, 0x38,
+	0xb6, 0xc9, 0xe1, 0x99, 0x74, 0xb8, 0x41, 0x0d, 0x05, 0xd9, 0x57, 0xd1, 0x58, 0x00, 0x76, 0x46,
+	0x7e, 0x84, 0x6e, 0xa5, 0x6d, 0x99, 0x34, 0x5a, 0x1f, 0x98, 0xfd, 0xbc, 0xe9, 0xfd, 0xcf, 0x7a,
+	0x4a, 0x97, 0xd8, 0xbd, 0x2e, 0x00, 0x65, 0x59, 0x1c, 0x5e, 0x32, 0xdf, 0xce, 0x7a, 0x73, 0x58,
+	0x51, 0xbd, 0x33, 0xa0, 0xfe, 0x1b, 0xbb, 0x46, 0x5d, 0xda, 0x4e, 0x97, 0x8a, 0xa1, 0xd0, 0x2a,
+	0xeb, 0x44, 0x1d, 0x22, 0xa6, 0x26, 0xe4, 0x1e, 0xb4, 0x17, 0xde, 0xe0, 0xc6, 0x02, 0x28, 0xe6,
+	0x64, 0x07, 0x5a, 0x71, 0xe2, 0xf0, 0xeb, 0x84, 0xc9, 0x7e, 0xde, 0x1e, 0xac, 0x15, 0x9a, 0x4e,
+	0x12, 0x1b, 0xc3, 0xb4, 0x19, 0xcb, 0xdf, 0xde, 0xaf, 0xd0, 0xc6, 0x82, 0xd4, 0xce, 0x5f, 0x43,
+	0x53, 0xb2, 0x94, 0x29, 0xfa, 0xe0, 0xf6, 0x72, 0x21, 0x34, 0x47, 0xc9, 0x7d, 0xe8, 0x78, 0xf1,
+	0xf9, 0x79, 0x90, 0x7b, 0xa3, 0xa1, 0x37, 0x6d, 0x15, 0x40, 0x4f, 0xb6, 0xa0, 0x5d, 0xf8, 0x56,
+	0x97, 0x58, 0x2b, 0x53, 0x76, 0xf5, 0x74, 0xe8, 0xd8, 0xee, 0x24, 0xc4, 0x53, 0x35, 0x8d, 0x7b,
+	0x1f, 0x35, 0xe8, 0x28, 0x3b, 0x18, 0xf3, 0xc9, 0x63, 0x00, 0xe1, 0xf8, 0xd2, 0xf6, 0xeb, 0xc5,
+	0xf6, 0x0b, 0x85, 0xb4, 0xc3, 0xf3, 0x51, 0x46, 0x1e, 0x81, 0x9e, 0xe6, 0xee, 0x95, 0x32, 0x20,
+	0x2d, 0x0c, 0xc5, 0xb3, 0xb6, 0xea, 0x07, 0x59, 0xa2, 0x2e, 0x8d, 0x13, 0xf8, 0x52, 0x8d, 0x3e,
+	0xd8, 0xea, 0x57, 0x6e, 0x62, 0xff, 0xb0, 0x60, 0x0c, 0x0f, 0x69, 0xb7, 0xe4, 0x0f, 0x7d, 0x79,
+	0x42, 0x5c, 0x1e, 0xc4, 0xd2, 0xc1, 0x1a, 0x55, 0x13, 0xf2, 0x04, 0x85, 0x8a, 0x1a, 0xf0, 0xe6,
+	0x4c, 0x63, 0x79, 0xde, 0xf5, 0x01, 0x29, 0x85, 0x2e, 0xca, 0x43, 0xa5, 0x45, 0xa5, 0x7f, 0x34,
+	0x60, 0x8b, 0xb2, 0x59, 0x90, 0x61, 0xd7, 0xcb, 0xfd, 0x28, 0xbb, 0x98, 0xb3, 0x8c, 0x0b, 0x99,
+	0xde, 0x99, 0x1b, 0xcd, 0xd8, 0x14, 0x7d, 0x10, 0x32, 0xb5, 0x7f, 0x91, 0x79, 0x50, 0x30, 0x84,
+	0xcc, 0x92, 0x8f, 0x32, 0x6f, 0x94, 0x59, 0xfb, 0x7f, 0x65, 0x3e, 0x5d, 0x14, 0x84, 0xb1, 0x28,
+	0xf7, 0xe8, 0xee, 0x52, 0xb2, 0x2c, 0x6a, 0x8c, 0x68, 0x5e, 0x94, 0x18, 0x2e, 0xb5, 0xb9, 0xb1,
+	0xd4, 0x66, 0x71, 0x3c, 0xf0, 0xd5, 0xb8, 0x54, 0x6a, 0xd4, 0x8b, 0xd0, 0x56, 0x01, 0xdc, 0xee,
+	0x7b, 0xd0, 0x5d, 0x0f, 0x8d, 0x8c, 0xd4, 0xe9, 0x6c, 0xca, 0xd3, 0xb9, 0x51, 0x18, 0xb8, 0x2f,
+	0x31, 0x79, 0x42, 0xc1, 0x2d, 0xc6, 0xe4, 0x19, 0xac, 0x4e, 0xe5, 0xad, 0x71, 0x3c, 0x79, 0x7d,
+	0xe5, 0x65, 0xd7, 0x07, 0x9b, 0x45, 0x5e, 0xf5, 0x6e, 0xd3, 0xee, 0xb4, 0x7a, 0xd3, 0x77, 0x61,
+	0x9d, 0x45, 0xaa, 0xc2, 0xeb, 0xc8, 0x73, 0x92, 0x38, 0xc0, 0x37, 0xb3, 0x8d, 0xf9, 0x6d, 0xba,
+	0xa6, 0x80, 0x31, 0xc6, 0x47, 0x22, 0x4c, 0x7a, 0xb0, 0x5a, 0x92, 0x44, 0x69, 0x1d, 0x59, 0x9a,
+	0x9e, 0x2d, 0x18, 0x58, 0x5e, 0x1f, 0x36, 0x2a, 0x1c, 0xfc, 0xb0, 0x34, 0x37, 0x34, 0x41, 0x32,
+	0xd7, 0x0b, 0xe6, 0x30, 0x07, 0x84, 0x53, 0x13, 0x3f, 0x75, 0xce, 0x63, 0x9f, 0x99, 0xba, 0xdc,
+	0xb6, 0x85, 0xf3, 0x23, 0x9c, 0xee, 0x7e, 0x03, 0x4d, 0x75, 0x1d, 0xc9, 0x2a, 0x74, 0xd4, 0x68,
+	0x34, 0xe7, 0xc6, 0x2d, 0x7c, 0x03, 0xba, 0x6a, 0xaa, 0xde, 0x31, 0x43, 0xdb, 0xfd, 0x53, 0x03,
+	0x28, 0xcd, 0x41, 0x8f, 0xbf, 0xd8, 0x3f, 0xb0, 0x87, 0x27, 0xc7, 0x8e, 0xfd, 0xf3, 0xc8, 0x72,
+	0x4e, 0x8f, 0xc7, 0x23, 0xeb, 0x60, 0xf8, 0x62, 0x68, 0x1d, 0x62, 0xb6, 0x09, 0x77, 0xaa, 0x20,
+	0xb5, 0x5e, 0x0e, 0xc7, 0xb6, 0x45, 0x0d, 0x8d, 0xdc, 0x05, 0xb2, 0x8c, 0x1c, 0x9d, 0xbc, 0xb2,
+	0x8c, 0x1a, 0xd9, 0x84, 0xf5, 0x6a, 0x7c, 0xb4, 0x7f, 0x3a, 0xb6, 0x8c, 0xfa, 0x4d, 0xfa, 0xf8,
+	0xf4, 0xc8, 0x32, 0x1a, 0x9f, 0xd3, 0x31, 0x6e, 0xd9, 0xc6, 0xca, 0xf3, 0x67, 0x7f, 0xbd, 0x7f,
+	0xa8, 0xbd, 0xc5, 0xef, 0x1d, 0x7e, 0x6f, 0x3e, 0x3c, 0xbc, 0xf5, 0x16, 0xbf, 0xbf, 0xf1, 0xfb,
+	0x65, 0x7b, 0x16, 0xf0, 0xb3, 0xf9, 0xa4, 0x8f, 0xaf, 0xc4, 0x5e, 0x12, 0x44, 0x33, 0xcf, 0x4d,
+	0xf6, 0x78, 0xe0, 0xf9, 0xde, 0x5e, 0xde, 0xc0, 0x49, 0x53, 0xfe, 0x13, 0x7e, 0xf7, 0x09, 0xd9,
+	0xee, 0x42, 0x21, 0x46, 0x07, 0x00, 0x00,
 }
 
 func (m *EventFilterRule) Marshal() (dAtA []byte, err error) {
@@ -1085,6 +1093,16 @@ func (m *RegisterDispatcherRequest) MarshalToSizedBuffer(dAtA []byte) (int, erro
 	_ = i
 	var l int
 	_ = l
+	if m.BdrMode {
+		i--
+		if m.BdrMode {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x58
+	}
 	if m.SyncPointInterval != 0 {
 		i = encodeVarintEvent(dAtA, i, uint64(m.SyncPointInterval))
 		i--
@@ -1388,6 +1406,9 @@ func (m *RegisterDispatcherRequest) Size() (n int) {
 	if m.SyncPointInterval != 0 {
 		n += 1 + sovEvent(uint64(m.SyncPointInterval))
 	}
+	if m.BdrMode {
+		n += 2
+	}
 	return n
 }
 
@@ -2743,6 +2764,26 @@ func (m *RegisterDispatcherRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BdrMode", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.BdrMode = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEvent(dAtA[iNdEx:])
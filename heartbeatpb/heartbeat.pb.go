@@ -226,6 +226,7 @@ type HeartBeatRequest struct {
 	Statuses        []*TableSpanStatus `protobuf:"bytes,3,rep,name=statuses,proto3" json:"statuses,omitempty"`
 	CompeleteStatus bool               `protobuf:"varint,4,opt,name=compeleteStatus,proto3" json:"compeleteStatus,omitempty"`
 	Err             *RunningError      `protobuf:"bytes,5,opt,name=err,proto3" json:"err,omitempty"`
+	DeadLetterRows  uint64             `protobuf:"varint,6,opt,name=deadLetterRows,proto3" json:"deadLetterRows,omitempty"`
 }
 
 func (m *HeartBeatRequest) Reset()         { *m = HeartBeatRequest{} }
@@ -296,6 +297,13 @@ func (m *HeartBeatRequest) GetErr() *RunningError {
 	return nil
 }
 
+func (m *HeartBeatRequest) GetDeadLetterRows() uint64 {
+	if m != nil {
+		return m.DeadLetterRows
+	}
+	return 0
+}
+
 type Watermark struct {
 	CheckpointTs uint64 `protobuf:"varint,1,opt,name=checkpointTs,proto3" json:"checkpointTs,omitempty"`
 	ResolvedTs   uint64 `protobuf:"varint,2,opt,name=resolvedTs,proto3" json:"resolvedTs,omitempty"`
@@ -877,11 +885,12 @@ func (m *MaintainerHeartbeat) GetStatuses() []*MaintainerStatus {
 }
 
 type MaintainerStatus struct {
-	ChangefeedID *ChangefeedID   `protobuf:"bytes,1,opt,name=changefeedID,proto3" json:"changefeedID,omitempty"`
-	FeedState    string          `protobuf:"bytes,2,opt,name=feed_state,json=feedState,proto3" json:"feed_state,omitempty"`
-	State        ComponentState  `protobuf:"varint,3,opt,name=state,proto3,enum=heartbeatpb.ComponentState" json:"state,omitempty"`
-	CheckpointTs uint64          `protobuf:"varint,4,opt,name=checkpoint_ts,json=checkpointTs,proto3" json:"checkpoint_ts,omitempty"`
-	Err          []*RunningError `protobuf:"bytes,5,rep,name=err,proto3" json:"err,omitempty"`
+	ChangefeedID   *ChangefeedID   `protobuf:"bytes,1,opt,name=changefeedID,proto3" json:"changefeedID,omitempty"`
+	FeedState      string          `protobuf:"bytes,2,opt,name=feed_state,json=feedState,proto3" json:"feed_state,omitempty"`
+	State          ComponentState  `protobuf:"varint,3,opt,name=state,proto3,enum=heartbeatpb.ComponentState" json:"state,omitempty"`
+	CheckpointTs   uint64          `protobuf:"varint,4,opt,name=checkpoint_ts,json=checkpointTs,proto3" json:"checkpoint_ts,omitempty"`
+	Err            []*RunningError `protobuf:"bytes,5,rep,name=err,proto3" json:"err,omitempty"`
+	DeadLetterRows uint64          `protobuf:"varint,6,opt,name=dead_letter_rows,json=deadLetterRows,proto3" json:"dead_letter_rows,omitempty"`
 }
 
 func (m *MaintainerStatus) Reset()         { *m = MaintainerStatus{} }
@@ -952,6 +961,13 @@ func (m *MaintainerStatus) GetErr() []*RunningError {
 	return nil
 }
 
+func (m *MaintainerStatus) GetDeadLetterRows() uint64 {
+	if m != nil {
+		return m.DeadLetterRows
+	}
+	return 0
+}
+
 type CoordinatorBootstrapRequest struct {
 	Version int64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
 }
@@ -1675,6 +1691,8 @@ type State struct {
 	UpdatedSchemas    []*SchemaIDChange `protobuf:"bytes,6,rep,name=UpdatedSchemas,proto3" json:"UpdatedSchemas,omitempty"`
 	IsSyncPoint       bool              `protobuf:"varint,7,opt,name=IsSyncPoint,proto3" json:"IsSyncPoint,omitempty"`
 	Stage             BlockStage        `protobuf:"varint,8,opt,name=stage,proto3,enum=heartbeatpb.BlockStage" json:"stage,omitempty"`
+	NeedApproval      bool              `protobuf:"varint,9,opt,name=NeedApproval,proto3" json:"NeedApproval,omitempty"`
+	Query             string            `protobuf:"bytes,10,opt,name=Query,proto3" json:"Query,omitempty"`
 }
 
 func (m *State) Reset()         { *m = State{} }
@@ -1766,6 +1784,20 @@ func (m *State) GetStage() BlockStage {
 	return BlockStage_NONE
 }
 
+func (m *State) GetNeedApproval() bool {
+	if m != nil {
+		return m.NeedApproval
+	}
+	return false
+}
+
+func (m *State) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
 type TableSpanBlockStatus struct {
 	ID    *DispatcherID `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	State *State        `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
@@ -1823,6 +1855,7 @@ type TableSpanStatus struct {
 	ComponentStatus    ComponentState `protobuf:"varint,2,opt,name=component_status,json=componentStatus,proto3,enum=heartbeatpb.ComponentState" json:"component_status,omitempty"`
 	CheckpointTs       uint64         `protobuf:"varint,3,opt,name=checkpoint_ts,json=checkpointTs,proto3" json:"checkpoint_ts,omitempty"`
 	EventSizePerSecond float32        `protobuf:"fixed32,4,opt,name=event_size_per_second,json=eventSizePerSecond,proto3" json:"event_size_per_second,omitempty"`
+	ResolvedTs         uint64         `protobuf:"varint,5,opt,name=resolved_ts,json=resolvedTs,proto3" json:"resolved_ts,omitempty"`
 }
 
 func (m *TableSpanStatus) Reset()         { *m = TableSpanStatus{} }
@@ -1886,6 +1919,13 @@ func (m *TableSpanStatus) GetEventSizePerSecond() float32 {
 	return 0
 }
 
+func (m *TableSpanStatus) GetResolvedTs() uint64 {
+	if m != nil {
+		return m.ResolvedTs
+	}
+	return 0
+}
+
 type BlockStatusRequest struct {
 	ChangefeedID  *ChangefeedID           `protobuf:"bytes,1,opt,name=changefeedID,proto3" json:"changefeedID,omitempty"`
 	BlockStatuses []*TableSpanBlockStatus `protobuf:"bytes,2,rep,name=blockStatuses,proto3" json:"blockStatuses,omitempty"`
@@ -2169,112 +2209,116 @@ func init() {
 func init() { proto.RegisterFile("heartbeatpb/heartbeat.proto", fileDescriptor_6d584080fdadb670) }
 
 var fileDescriptor_6d584080fdadb670 = []byte{
-	// 1674 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x4b, 0x73, 0x1b, 0xc5,
-	0x13, 0xf7, 0xee, 0xca, 0xb2, 0xd4, 0xf2, 0x63, 0x33, 0x4e, 0x1c, 0xc5, 0x8e, 0xfd, 0x77, 0xf6,
-	0xcf, 0xc1, 0x38, 0x60, 0x57, 0x9c, 0xa4, 0x02, 0x14, 0x21, 0xd8, 0xb2, 0x49, 0x54, 0xae, 0x38,
-	0xae, 0x91, 0xa9, 0x10, 0x2e, 0xaa, 0xf5, 0xee, 0x58, 0xde, 0xb2, 0xb4, 0xbb, 0x99, 0x59, 0x39,
-	0x8f, 0x2a, 0xb8, 0x70, 0xe5, 0xc0, 0x91, 0x03, 0x97, 0x1c, 0xf9, 0x24, 0x70, 0xcc, 0x09, 0x38,
-	0x70, 0xa0, 0x92, 0xe2, 0x13, 0x50, 0xdc, 0xa9, 0x99, 0xd9, 0xb7, 0xd6, 0x8f, 0x94, 0x7d, 0xd2,
-	0xf4, 0x4c, 0x77, 0x4f, 0x4f, 0x3f, 0x7e, 0xdd, 0x2b, 0x98, 0xd9, 0x27, 0x26, 0x0d, 0x76, 0x89,
-	0x19, 0xf8, 0xbb, 0xcb, 0xf1, 0x7a, 0xc9, 0xa7, 0x5e, 0xe0, 0xa1, 0x5a, 0xea, 0xd0, 0x78, 0x02,
-	0xd5, 0x1d, 0x73, 0xb7, 0x4b, 0x5a, 0xbe, 0xe9, 0xa2, 0x3a, 0x8c, 0x08, 0xa2, 0xb9, 0x5e, 0x57,
-	0xe6, 0x95, 0x05, 0x0d, 0x47, 0x24, 0x9a, 0x86, 0x4a, 0x2b, 0x30, 0x69, 0xb0, 0x49, 0x5e, 0xd4,
-	0xd5, 0x79, 0x65, 0x61, 0x14, 0xc7, 0x34, 0x9a, 0x82, 0xf2, 0x86, 0x6b, 0xf3, 0x13, 0x4d, 0x9c,
-	0x84, 0x94, 0xf1, 0xa3, 0x0a, 0xfa, 0x03, 0x7e, 0xd5, 0x1a, 0x31, 0x03, 0x4c, 0x9e, 0xf6, 0x09,
-	0x0b, 0xd0, 0x5d, 0x18, 0xb5, 0xf6, 0x4d, 0xb7, 0x43, 0xf6, 0x08, 0xb1, 0xc3, 0x7b, 0x6a, 0x2b,
-	0x57, 0x96, 0x52, 0x36, 0x2d, 0x35, 0x52, 0x0c, 0x38, 0xc3, 0x8e, 0x6e, 0x41, 0xf5, 0x99, 0x19,
-	0x10, 0xda, 0x33, 0xe9, 0x81, 0x30, 0xa4, 0xb6, 0x32, 0x95, 0x91, 0x7d, 0x1c, 0x9d, 0xe2, 0x84,
-	0x11, 0x7d, 0x04, 0x15, 0x16, 0x98, 0x41, 0x9f, 0x11, 0x56, 0xd7, 0xe6, 0xb5, 0x85, 0xda, 0xca,
-	0xd5, 0x8c, 0x50, 0xec, 0x81, 0x96, 0xe0, 0xc2, 0x31, 0x37, 0x5a, 0x80, 0x09, 0xcb, 0xeb, 0xf9,
-	0xa4, 0x4b, 0x02, 0x22, 0x0f, 0xeb, 0xa5, 0x79, 0x65, 0xa1, 0x82, 0xf3, 0xdb, 0xe8, 0x3a, 0x68,
-	0x84, 0xd2, 0xfa, 0x70, 0xc1, 0x7b, 0x70, 0xdf, 0x75, 0x1d, 0xb7, 0xb3, 0x41, 0xa9, 0x47, 0x31,
-	0xe7, 0x32, 0x1e, 0x41, 0x35, 0x36, 0x14, 0x19, 0xdc, 0x25, 0xc4, 0x3a, 0xf0, 0x3d, 0xc7, 0x0d,
-	0x76, 0x98, 0x70, 0x49, 0x09, 0x67, 0xf6, 0xd0, 0x1c, 0x00, 0x25, 0xcc, 0xeb, 0x1e, 0x12, 0x7b,
-	0x87, 0x89, 0x87, 0x97, 0x70, 0x6a, 0xc7, 0xf8, 0x06, 0xf4, 0x75, 0x87, 0xf9, 0x66, 0x60, 0xed,
-	0x13, 0xba, 0x6a, 0x05, 0x8e, 0xe7, 0xa2, 0xeb, 0x50, 0x36, 0xc5, 0x4a, 0x68, 0x1c, 0x5f, 0x99,
-	0xcc, 0x18, 0x25, 0x99, 0x70, 0xc8, 0xc2, 0x03, 0xdc, 0xf0, 0x7a, 0x3d, 0x27, 0x88, 0xd5, 0xc7,
-	0x34, 0x9a, 0x87, 0x5a, 0x93, 0xb5, 0x5e, 0xb8, 0xd6, 0x36, 0xb7, 0x46, 0x44, 0xb9, 0x82, 0xd3,
-	0x5b, 0x46, 0x03, 0xb4, 0xd5, 0xc6, 0x66, 0x46, 0x89, 0x72, 0xbc, 0x12, 0x75, 0x50, 0xc9, 0x77,
-	0x2a, 0x5c, 0x6a, 0xba, 0x7b, 0xdd, 0x3e, 0x71, 0x2d, 0x62, 0x27, 0xcf, 0x61, 0xe8, 0x73, 0x18,
-	0x8b, 0x0f, 0x76, 0x5e, 0xf8, 0x24, 0x7c, 0xd0, 0x74, 0xe6, 0x41, 0x19, 0x0e, 0x9c, 0x15, 0x40,
-	0xf7, 0x60, 0x2c, 0x51, 0xd8, 0x5c, 0xe7, 0x6f, 0xd4, 0x06, 0xe2, 0x94, 0xe6, 0xc0, 0x59, 0x7e,
-	0x51, 0x00, 0xd6, 0x3e, 0xe9, 0x99, 0xcd, 0x75, 0xe1, 0x00, 0x0d, 0xc7, 0x34, 0xda, 0x84, 0x49,
-	0xf2, 0xdc, 0xea, 0xf6, 0x6d, 0x92, 0x92, 0xb1, 0x45, 0xa2, 0x1c, 0x7b, 0x45, 0x91, 0x94, 0xf1,
-	0x8b, 0x92, 0x0e, 0x65, 0x98, 0x5c, 0x5f, 0xc1, 0x25, 0xa7, 0xc8, 0x33, 0x61, 0xf9, 0x18, 0xc5,
-	0x8e, 0x48, 0x73, 0xe2, 0x62, 0x05, 0xe8, 0x76, 0x9c, 0x24, 0xb2, 0x9a, 0x66, 0x8f, 0x30, 0x37,
-	0x97, 0x2e, 0x06, 0x68, 0xa6, 0x75, 0x20, 0x3c, 0x51, 0x5b, 0xd1, 0xb3, 0x89, 0xd5, 0xd8, 0xc4,
-	0xfc, 0xd0, 0x78, 0xa5, 0xc0, 0x85, 0x54, 0xfd, 0x33, 0xdf, 0x73, 0x19, 0x39, 0x2b, 0x00, 0x3c,
-	0x04, 0x64, 0xe7, 0xbc, 0x43, 0xa2, 0x68, 0x1e, 0x65, 0x7b, 0x58, 0xd5, 0x05, 0x82, 0xc6, 0x73,
-	0x98, 0x6c, 0xa4, 0xea, 0xec, 0x21, 0x61, 0xcc, 0xec, 0x9c, 0xd9, 0xc8, 0x7c, 0x45, 0xab, 0x83,
-	0x15, 0x6d, 0xfc, 0x9e, 0x89, 0x73, 0xc3, 0x73, 0xf7, 0x9c, 0x0e, 0x5a, 0x84, 0x12, 0xf3, 0x4d,
-	0x37, 0xbc, 0x6f, 0xaa, 0x18, 0xa4, 0xb0, 0xe0, 0xe1, 0x60, 0xcd, 0x38, 0x04, 0xc7, 0xfa, 0x23,
-	0x92, 0x5b, 0x6f, 0xa7, 0xf2, 0x2c, 0x8c, 0xd2, 0x31, 0x89, 0x98, 0x61, 0xe7, 0xa9, 0xce, 0xa2,
-	0x54, 0x2f, 0xc9, 0x54, 0x8f, 0x68, 0x64, 0xc0, 0x98, 0xd5, 0xa7, 0x94, 0xb8, 0x41, 0xdb, 0xb7,
-	0xdb, 0x01, 0x13, 0x78, 0x57, 0xc2, 0xb5, 0x70, 0x73, 0x9b, 0x63, 0xd1, 0x6f, 0x0a, 0x5c, 0xe1,
-	0xb5, 0x61, 0xf7, 0xbb, 0xa9, 0xd4, 0x3e, 0xa7, 0x06, 0x70, 0x1b, 0xca, 0x96, 0xf0, 0xd5, 0x09,
-	0xf9, 0x2a, 0x1d, 0x8a, 0x43, 0x66, 0xd4, 0x80, 0x71, 0x16, 0x9a, 0x24, 0x33, 0x59, 0x38, 0x65,
-	0x7c, 0x65, 0x26, 0x23, 0xde, 0xca, 0xb0, 0xe0, 0x9c, 0x88, 0xb1, 0x0d, 0x93, 0x0f, 0x4d, 0xc7,
-	0x0d, 0x4c, 0xc7, 0x25, 0xf4, 0x41, 0x24, 0x87, 0x3e, 0x4e, 0x75, 0x17, 0xa5, 0x20, 0x11, 0x13,
-	0x99, 0x7c, 0x7b, 0x31, 0xfe, 0x55, 0x40, 0xcf, 0x1f, 0x9f, 0xd5, 0x43, 0xb3, 0x00, 0x7c, 0xd5,
-	0xe6, 0x97, 0x10, 0xe1, 0xa5, 0x2a, 0xae, 0xf2, 0x1d, 0xae, 0x9e, 0xa0, 0x1b, 0x30, 0x2c, 0x4f,
-	0x8a, 0x1c, 0xd0, 0xf0, 0x7a, 0xbe, 0xe7, 0x12, 0x37, 0x10, 0xbc, 0x58, 0x72, 0xa2, 0xff, 0xc3,
-	0x58, 0x92, 0xba, 0x3c, 0xe8, 0xa5, 0x82, 0x0e, 0x15, 0xf7, 0x3f, 0xed, 0x14, 0xfd, 0xef, 0x0e,
-	0xcc, 0x34, 0x3c, 0x8f, 0xda, 0x8e, 0x6b, 0x06, 0x1e, 0x5d, 0xf3, 0xbc, 0x80, 0x05, 0xd4, 0xf4,
-	0xa3, 0x1c, 0xa9, 0xc3, 0xc8, 0x21, 0xa1, 0x2c, 0x6a, 0x5d, 0x1a, 0x8e, 0x48, 0xe3, 0x09, 0x5c,
-	0x2d, 0x16, 0x0c, 0xd1, 0xe5, 0x0c, 0xb1, 0xf8, 0x16, 0x2e, 0xae, 0xda, 0x76, 0xc2, 0x10, 0x19,
-	0xf3, 0x3e, 0xa8, 0x8e, 0x7d, 0x72, 0x10, 0x54, 0xc7, 0xe6, 0x93, 0x50, 0x2a, 0x39, 0x47, 0xe3,
-	0xec, 0x1b, 0x70, 0xa0, 0x56, 0x00, 0x08, 0xcf, 0xe1, 0x32, 0x26, 0x3d, 0xef, 0x90, 0x9c, 0xc9,
-	0x84, 0x3a, 0x8c, 0x58, 0x26, 0xb3, 0x4c, 0x9b, 0x84, 0x2d, 0x36, 0x22, 0xf9, 0x09, 0x15, 0xfa,
-	0xed, 0xb0, 0x83, 0x47, 0xa4, 0xf1, 0x8f, 0x02, 0xd3, 0xc9, 0xa5, 0x03, 0xd1, 0x38, 0x63, 0x3e,
-	0x1e, 0xe5, 0x94, 0x2b, 0x22, 0x54, 0x34, 0xe5, 0x8f, 0x18, 0xc0, 0x2c, 0xb8, 0x16, 0x70, 0xb4,
-	0x6b, 0x07, 0xd4, 0xe9, 0x74, 0x08, 0x6d, 0x93, 0x43, 0x8e, 0x38, 0x09, 0x4a, 0xb5, 0x9d, 0x53,
-	0xb4, 0xd7, 0x59, 0xa1, 0x63, 0x47, 0xaa, 0xd8, 0xe0, 0x1a, 0x32, 0x8d, 0xf6, 0x6f, 0x05, 0x66,
-	0x0a, 0x5f, 0x7d, 0x3e, 0x8d, 0xea, 0x36, 0x0c, 0x73, 0x98, 0x8e, 0x7a, 0xd3, 0xff, 0x32, 0x72,
-	0xf1, 0x6d, 0x09, 0xa8, 0x4b, 0xee, 0xa8, 0x8c, 0xb4, 0xd3, 0x8c, 0x91, 0xa7, 0x2a, 0x4c, 0xe3,
-	0x67, 0x15, 0xd0, 0xe0, 0x7d, 0x3c, 0xa7, 0x8e, 0x78, 0x54, 0xc6, 0x89, 0x6a, 0x38, 0xfc, 0x47,
-	0x0d, 0x41, 0xcd, 0xcd, 0x3e, 0x51, 0xc7, 0xd2, 0x4e, 0xd1, 0xb1, 0xbe, 0x00, 0xdd, 0x8a, 0x00,
-	0xa6, 0xcd, 0x92, 0x69, 0xfa, 0x04, 0x14, 0x9a, 0xb0, 0xd2, 0x74, 0x9f, 0x0d, 0x3e, 0x7b, 0xb8,
-	0x00, 0x8f, 0x6e, 0x42, 0x6d, 0xb7, 0xeb, 0x59, 0x07, 0x21, 0x0e, 0x96, 0x85, 0x7d, 0x28, 0x0b,
-	0xf7, 0x42, 0x3d, 0x08, 0x36, 0xb1, 0x36, 0x9e, 0xc2, 0x54, 0x92, 0x12, 0x8d, 0xae, 0xc7, 0xc8,
-	0x39, 0x15, 0x41, 0xaa, 0xf8, 0xd4, 0x6c, 0xf1, 0x51, 0xb8, 0x3c, 0x70, 0xe5, 0xf9, 0x64, 0x20,
-	0x1f, 0x10, 0xfa, 0x96, 0x45, 0x18, 0x8b, 0xee, 0x0c, 0x49, 0xe3, 0x7b, 0x05, 0xf4, 0x64, 0x4a,
-	0x14, 0x61, 0x3a, 0x8f, 0x21, 0x7b, 0x1a, 0x2a, 0xe1, 0xf7, 0xa2, 0xcc, 0x7a, 0x0d, 0xc7, 0xf4,
-	0x71, 0xf3, 0xb3, 0x71, 0x17, 0x86, 0x05, 0xdf, 0x09, 0xdf, 0x9f, 0x47, 0xa4, 0xa0, 0xe1, 0xc2,
-	0x78, 0xb4, 0x96, 0xde, 0x38, 0x46, 0xcf, 0x3c, 0xd4, 0x1e, 0x75, 0xed, 0x9c, 0xaa, 0xf4, 0x16,
-	0xe7, 0xd8, 0x22, 0xcf, 0x72, 0xb6, 0xa6, 0xb7, 0x8c, 0x57, 0x1a, 0x0c, 0xcb, 0x5e, 0x7a, 0x15,
-	0xaa, 0x4d, 0xb6, 0xc6, 0xd3, 0x87, 0x48, 0x78, 0xae, 0xe0, 0x64, 0x83, 0x5b, 0x21, 0x96, 0xc9,
-	0x80, 0x16, 0x92, 0xe8, 0x1e, 0xd4, 0xe4, 0x52, 0x78, 0x3e, 0xac, 0x9d, 0xd9, 0x23, 0x86, 0x78,
-	0xc9, 0x84, 0xd3, 0x12, 0x68, 0x13, 0x2e, 0x6c, 0x11, 0x62, 0xaf, 0x53, 0xcf, 0xf7, 0x23, 0x8e,
-	0x10, 0x10, 0x4f, 0x50, 0x33, 0x28, 0x87, 0x3e, 0x85, 0x09, 0xbe, 0xb9, 0x6a, 0xdb, 0xb1, 0x2a,
-	0xd9, 0xc5, 0xd1, 0x60, 0x35, 0xe3, 0x3c, 0x2b, 0x9f, 0xac, 0xbe, 0xf4, 0x6d, 0x33, 0x20, 0xa1,
-	0x0b, 0x59, 0xbd, 0x2c, 0x84, 0x07, 0x27, 0xab, 0x24, 0x40, 0x38, 0x27, 0x92, 0xff, 0x38, 0x1c,
-	0x19, 0xf8, 0x38, 0x44, 0x1f, 0x8a, 0xb1, 0xa5, 0x43, 0xea, 0x15, 0x91, 0x95, 0x97, 0xb3, 0x70,
-	0x1a, 0x56, 0x70, 0x47, 0x8e, 0x2c, 0x1d, 0x62, 0x1c, 0xc0, 0xc5, 0x18, 0x7d, 0xa2, 0x53, 0x0e,
-	0x1d, 0xef, 0x80, 0x7a, 0x0b, 0xd1, 0xa0, 0xa4, 0x1e, 0x09, 0x1d, 0x92, 0xc1, 0xf8, 0x53, 0x81,
-	0x89, 0xdc, 0x5f, 0x08, 0xef, 0x72, 0x51, 0x11, 0x2c, 0xaa, 0xe7, 0x01, 0x8b, 0x05, 0x53, 0x06,
-	0xba, 0x01, 0x97, 0x64, 0x33, 0x65, 0xce, 0x4b, 0xd2, 0xf6, 0x09, 0x6d, 0x33, 0x62, 0x79, 0xae,
-	0x6c, 0xa7, 0x2a, 0x46, 0xe2, 0xb0, 0xe5, 0xbc, 0x24, 0xdb, 0x84, 0xb6, 0xc4, 0x89, 0xf1, 0x93,
-	0x02, 0x28, 0xe5, 0xc3, 0x73, 0x42, 0xc4, 0xfb, 0x30, 0xb6, 0x9b, 0x28, 0x8d, 0xbf, 0xe1, 0xae,
-	0x15, 0x77, 0x90, 0xf4, 0xfd, 0x59, 0x39, 0xc3, 0x86, 0xd1, 0x74, 0x67, 0x44, 0x08, 0x4a, 0x81,
-	0xd3, 0x93, 0xf0, 0x55, 0xc5, 0x62, 0xcd, 0xf7, 0x5c, 0xcf, 0x8e, 0xa6, 0x61, 0xb1, 0xe6, 0x7b,
-	0x16, 0xdf, 0xd3, 0xe4, 0x1e, 0x5f, 0xf3, 0x92, 0xed, 0xc9, 0x4f, 0x40, 0xe1, 0x8f, 0x2a, 0x8e,
-	0x48, 0xe3, 0x16, 0x8c, 0xa6, 0x03, 0xc7, 0xa5, 0xf7, 0x9d, 0xce, 0x7e, 0xf8, 0x37, 0x87, 0x58,
-	0x23, 0x1d, 0xb4, 0xae, 0xf7, 0x2c, 0x2c, 0x76, 0xbe, 0x34, 0xf6, 0x60, 0x34, 0xed, 0x82, 0xd3,
-	0x49, 0x09, 0x6b, 0xcd, 0x5e, 0x6c, 0x19, 0x5f, 0x73, 0xa8, 0xe1, 0xbf, 0xcc, 0x37, 0xad, 0xc8,
-	0xb6, 0x64, 0x63, 0x71, 0x16, 0xca, 0xe1, 0x9f, 0x3e, 0x55, 0x18, 0x7e, 0x4c, 0x9d, 0x80, 0xe8,
-	0x43, 0xa8, 0x02, 0xa5, 0x6d, 0x93, 0x31, 0x5d, 0x59, 0x5c, 0x90, 0x08, 0x99, 0x7c, 0xca, 0x20,
-	0x80, 0x72, 0x83, 0x12, 0x53, 0xf0, 0x01, 0x94, 0xe5, 0xe0, 0xa9, 0x2b, 0x8b, 0x9f, 0x00, 0x24,
-	0xc5, 0xc4, 0x35, 0x6c, 0x3d, 0xda, 0xda, 0xd0, 0x87, 0x50, 0x0d, 0x46, 0x1e, 0xaf, 0x36, 0x77,
-	0x9a, 0x5b, 0xf7, 0x75, 0x45, 0x10, 0x58, 0x12, 0x2a, 0xe7, 0x59, 0xe7, 0x3c, 0xda, 0xe2, 0x07,
-	0xb9, 0x06, 0x82, 0x46, 0x40, 0x5b, 0xed, 0x76, 0xf5, 0x21, 0x54, 0x06, 0x75, 0x7d, 0x4d, 0x57,
-	0xf8, 0x4d, 0x5b, 0x1e, 0xed, 0x99, 0x5d, 0x5d, 0x5d, 0xbc, 0x03, 0xe3, 0xd9, 0x84, 0x16, 0x6a,
-	0x3d, 0x7a, 0xe0, 0xb8, 0x1d, 0x79, 0x61, 0x2b, 0x10, 0x28, 0x25, 0x2f, 0x94, 0x16, 0xda, 0xba,
-	0xba, 0xf6, 0xd9, 0xaf, 0x6f, 0xe6, 0x94, 0xd7, 0x6f, 0xe6, 0x94, 0xbf, 0xde, 0xcc, 0x29, 0x3f,
-	0xbc, 0x9d, 0x1b, 0x7a, 0xfd, 0x76, 0x6e, 0xe8, 0x8f, 0xb7, 0x73, 0x43, 0x5f, 0xbf, 0xd7, 0x71,
-	0x82, 0xfd, 0xfe, 0xee, 0x92, 0xe5, 0xf5, 0x96, 0x7d, 0xc7, 0xed, 0x58, 0xa6, 0xbf, 0x1c, 0x38,
-	0x96, 0x6d, 0x2d, 0xa7, 0x72, 0x6a, 0xb7, 0x2c, 0xfe, 0x05, 0xbd, 0xf9, 0x5f, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x96, 0x00, 0x93, 0xac, 0x24, 0x15, 0x00, 0x00,
+	// 1734 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xad, 0x58, 0x4b, 0x6f, 0x1c, 0x45,
+	0x10, 0xce, 0xce, 0xac, 0xd7, 0xde, 0x5a, 0x3f, 0x36, 0xed, 0xc4, 0xd9, 0xc4, 0x71, 0x1e, 0x03,
+	0x42, 0xc6, 0x80, 0x2d, 0x9c, 0x44, 0x01, 0x44, 0x00, 0x7b, 0x1d, 0x12, 0xcb, 0xc4, 0x31, 0x6d,
+	0xa3, 0x00, 0x97, 0xd5, 0x78, 0xa6, 0xbd, 0x1e, 0x79, 0x77, 0x66, 0x32, 0x33, 0x6b, 0xc7, 0x48,
+	0x70, 0xe1, 0xca, 0x81, 0x1f, 0x80, 0x84, 0x38, 0xf2, 0x2f, 0xb8, 0xc1, 0x31, 0x27, 0x40, 0xe2,
+	0x82, 0x40, 0xfc, 0x02, 0xce, 0x48, 0x54, 0x77, 0xcf, 0x7b, 0x67, 0x6d, 0x47, 0xf6, 0x61, 0xb5,
+	0x5d, 0xdd, 0x55, 0xd5, 0xd5, 0xd5, 0x55, 0x5f, 0x55, 0x0f, 0x4c, 0xef, 0x32, 0xdd, 0x0b, 0xb6,
+	0x99, 0x1e, 0xb8, 0xdb, 0x0b, 0xf1, 0x78, 0xde, 0xf5, 0x9c, 0xc0, 0x21, 0xb5, 0xd4, 0xa2, 0xf6,
+	0x19, 0x54, 0xb7, 0xf4, 0xed, 0x0e, 0xdb, 0x74, 0x75, 0x9b, 0x34, 0x60, 0x58, 0x10, 0xab, 0x2b,
+	0x8d, 0xd2, 0x8d, 0xd2, 0xac, 0x4a, 0x23, 0x92, 0x5c, 0x81, 0x91, 0xcd, 0x00, 0xa5, 0xd6, 0xd8,
+	0x61, 0x43, 0xc1, 0xa5, 0x51, 0x1a, 0xd3, 0x64, 0x0a, 0x2a, 0xf7, 0x6d, 0x93, 0xaf, 0xa8, 0x62,
+	0x25, 0xa4, 0xb4, 0x9f, 0x14, 0xa8, 0x3f, 0xe4, 0x5b, 0x2d, 0xe3, 0x56, 0x94, 0x3d, 0xed, 0x31,
+	0x3f, 0x20, 0xf7, 0x60, 0xd4, 0xd8, 0xd5, 0xed, 0x36, 0xdb, 0x61, 0xcc, 0x0c, 0xf7, 0xa9, 0x2d,
+	0x5e, 0x9e, 0x4f, 0xd9, 0x34, 0xdf, 0x4c, 0x31, 0xd0, 0x0c, 0x3b, 0xb9, 0x0d, 0xd5, 0x03, 0x3d,
+	0x60, 0x5e, 0x57, 0xf7, 0xf6, 0x84, 0x21, 0xb5, 0xc5, 0xa9, 0x8c, 0xec, 0x93, 0x68, 0x95, 0x26,
+	0x8c, 0xe4, 0x2d, 0x18, 0xf1, 0x03, 0x3d, 0xe8, 0xf9, 0xcc, 0x47, 0x1b, 0x55, 0x14, 0xba, 0x9a,
+	0x11, 0x8a, 0x3d, 0xb0, 0x29, 0xb8, 0x68, 0xcc, 0x4d, 0x66, 0x61, 0xc2, 0x70, 0xba, 0x2e, 0xeb,
+	0xb0, 0x80, 0xc9, 0xc5, 0x46, 0x19, 0x77, 0x1d, 0xa1, 0xf9, 0x69, 0xf2, 0x1a, 0xa8, 0xcc, 0xf3,
+	0x1a, 0x43, 0x05, 0xe7, 0xa1, 0x3d, 0xdb, 0xb6, 0xec, 0xf6, 0x7d, 0xcf, 0x73, 0x3c, 0xca, 0xb9,
+	0xc8, 0x2b, 0x30, 0x6e, 0x32, 0xdd, 0xfc, 0x88, 0x05, 0x68, 0x22, 0x75, 0x0e, 0xfc, 0x46, 0x05,
+	0xe5, 0xca, 0x34, 0x37, 0xab, 0x3d, 0x86, 0x6a, 0x7c, 0x20, 0xa2, 0x71, 0xd7, 0x31, 0x63, 0xcf,
+	0x75, 0x2c, 0x3b, 0xd8, 0xf2, 0x85, 0xeb, 0xca, 0x34, 0x33, 0x47, 0xae, 0x01, 0x78, 0xcc, 0x77,
+	0x3a, 0xfb, 0xcc, 0x44, 0x0e, 0x45, 0x70, 0xa4, 0x66, 0xb4, 0x2f, 0xa1, 0xbe, 0x62, 0xf9, 0xae,
+	0x1e, 0xa0, 0x94, 0xb7, 0x64, 0x04, 0x96, 0x63, 0xa3, 0xe5, 0x15, 0x5d, 0x8c, 0x84, 0xc6, 0xf1,
+	0xc5, 0xc9, 0x8c, 0xf1, 0x92, 0x89, 0x86, 0x2c, 0x3c, 0x10, 0x9a, 0x4e, 0xb7, 0x6b, 0x05, 0xb1,
+	0xfa, 0x98, 0x26, 0x37, 0xa0, 0xb6, 0xea, 0x6f, 0x1e, 0xda, 0xc6, 0x06, 0xb7, 0x46, 0x44, 0xc3,
+	0x08, 0x4d, 0x4f, 0x69, 0x4d, 0x50, 0x97, 0x9a, 0x6b, 0x19, 0x25, 0xa5, 0xa3, 0x95, 0x28, 0xfd,
+	0x4a, 0xbe, 0x56, 0xe0, 0xe2, 0xaa, 0xbd, 0xd3, 0xe9, 0x31, 0xdb, 0x60, 0x66, 0x72, 0x1c, 0x9f,
+	0x7c, 0x00, 0x63, 0xf1, 0xc2, 0xd6, 0xa1, 0xcb, 0xc2, 0x03, 0x5d, 0xc9, 0x1c, 0x28, 0xc3, 0x41,
+	0xb3, 0x02, 0xe4, 0x7d, 0x18, 0x4b, 0x14, 0xae, 0xae, 0xf0, 0x33, 0xaa, 0x7d, 0xf7, 0x99, 0xe6,
+	0xa0, 0x59, 0x7e, 0x91, 0x28, 0x38, 0xee, 0xea, 0x18, 0xdb, 0xaa, 0xc8, 0xa1, 0x98, 0x26, 0x6b,
+	0x30, 0xc9, 0x9e, 0x19, 0x9d, 0x9e, 0xc9, 0x52, 0x32, 0xa6, 0x08, 0xa8, 0x23, 0xb7, 0x28, 0x92,
+	0xd2, 0x7e, 0x2e, 0xa5, 0xaf, 0x32, 0x0c, 0xc2, 0x4f, 0xe1, 0xa2, 0x55, 0xe4, 0x99, 0x30, 0xcd,
+	0xb4, 0x62, 0x47, 0xa4, 0x39, 0x69, 0xb1, 0x02, 0x72, 0x27, 0x0e, 0x12, 0x99, 0x75, 0x33, 0x03,
+	0xcc, 0xcd, 0x85, 0x8b, 0x06, 0xaa, 0x6e, 0xec, 0x09, 0x4f, 0xd4, 0x16, 0xeb, 0xd9, 0xc0, 0x6a,
+	0xae, 0x51, 0xbe, 0xa8, 0xfd, 0x50, 0x82, 0xf3, 0x29, 0x9c, 0xf0, 0x5d, 0xc7, 0xf6, 0xd9, 0x69,
+	0x81, 0xe2, 0x11, 0x10, 0x33, 0xe7, 0x1d, 0x16, 0xdd, 0xe6, 0x20, 0xdb, 0xc3, 0xec, 0x2f, 0x10,
+	0xd4, 0x9e, 0xc1, 0x64, 0x33, 0x95, 0x67, 0x8f, 0x98, 0xef, 0xeb, 0xed, 0x53, 0x1b, 0x99, 0xcf,
+	0x68, 0xa5, 0x3f, 0xa3, 0xb5, 0xdf, 0x32, 0xf7, 0xdc, 0x74, 0xec, 0x1d, 0xab, 0x4d, 0xe6, 0xa0,
+	0x8c, 0x33, 0x76, 0xb8, 0xdf, 0x54, 0x31, 0x98, 0x51, 0xc1, 0xc3, 0x41, 0xdd, 0xe7, 0x50, 0x1d,
+	0xeb, 0x8f, 0x48, 0x6e, 0xbd, 0x99, 0x8a, 0xb3, 0xf0, 0x96, 0x8e, 0x08, 0xc4, 0x0c, 0x3b, 0x0f,
+	0x75, 0x3f, 0x0a, 0xf5, 0xb2, 0x0c, 0xf5, 0x88, 0xc6, 0x93, 0x8d, 0x19, 0x3d, 0xcf, 0x63, 0x76,
+	0xd0, 0x72, 0xcd, 0x56, 0xe0, 0x0b, 0x5c, 0x2c, 0xd3, 0x5a, 0x38, 0xb9, 0xc1, 0xb1, 0xe8, 0xd7,
+	0x12, 0x5c, 0xe6, 0xb9, 0x61, 0xf6, 0x3a, 0xa9, 0xd0, 0x3e, 0xa3, 0x42, 0x81, 0xf1, 0x6a, 0x08,
+	0x5f, 0x1d, 0x13, 0xaf, 0xd2, 0xa1, 0x34, 0x64, 0x26, 0x4d, 0x18, 0xf7, 0x43, 0x93, 0x64, 0x24,
+	0x0b, 0xa7, 0x8c, 0x2f, 0x4e, 0x67, 0xc4, 0x37, 0x33, 0x2c, 0x34, 0x27, 0xa2, 0x6d, 0xc0, 0xe4,
+	0x23, 0x1d, 0x6f, 0x0f, 0x7f, 0xcc, 0x7b, 0x18, 0xc9, 0x91, 0xb7, 0x53, 0x55, 0xa8, 0x54, 0x10,
+	0x88, 0x89, 0x4c, 0xbe, 0x0c, 0x69, 0xdf, 0x63, 0x29, 0xcd, 0x2f, 0x9f, 0xd6, 0x43, 0x33, 0x00,
+	0x7c, 0xd4, 0xe2, 0x9b, 0x30, 0xe1, 0xa5, 0x2a, 0xad, 0xf2, 0x19, 0xae, 0x9e, 0x91, 0x37, 0x61,
+	0x48, 0xae, 0x14, 0x39, 0x00, 0xd1, 0x1a, 0xb3, 0x14, 0x2f, 0x52, 0xf0, 0x52, 0xc9, 0x49, 0x5e,
+	0xc2, 0x4b, 0x8f, 0x43, 0x97, 0x5f, 0x7a, 0xb9, 0xa0, 0x42, 0xc5, 0x75, 0x52, 0x3d, 0x41, 0x9d,
+	0x9c, 0x85, 0x3a, 0xaf, 0x88, 0xad, 0x8e, 0x28, 0x89, 0x2d, 0x6f, 0x70, 0xa5, 0xbc, 0x0b, 0xd3,
+	0x4d, 0xc7, 0xf1, 0x4c, 0xcb, 0xd6, 0x03, 0xc7, 0x5b, 0x76, 0x9c, 0xc0, 0x0f, 0x3c, 0xdd, 0x8d,
+	0xa2, 0x09, 0x93, 0x60, 0x1f, 0x61, 0x2c, 0x2a, 0x72, 0xd8, 0xd9, 0x84, 0x24, 0x36, 0x40, 0x57,
+	0x8b, 0x05, 0x43, 0x1c, 0x3a, 0xc5, 0xad, 0x7d, 0x05, 0x17, 0x96, 0x4c, 0x33, 0x61, 0x88, 0x8c,
+	0x79, 0x15, 0x14, 0xcb, 0x3c, 0xfe, 0xba, 0x90, 0x89, 0xf7, 0x56, 0xa9, 0x30, 0x1e, 0x8d, 0xe3,
+	0xb4, 0xcf, 0xd5, 0x6a, 0x01, 0x74, 0x3c, 0x83, 0x4b, 0x94, 0x75, 0x9d, 0x7d, 0x76, 0x2a, 0x13,
+	0xd0, 0x75, 0x86, 0xee, 0x1b, 0xba, 0xc9, 0xc2, 0x62, 0x1c, 0x91, 0x7c, 0xc5, 0x13, 0xfa, 0xcd,
+	0xb0, 0xd6, 0x47, 0xa4, 0xf6, 0x6f, 0x09, 0xae, 0x24, 0x9b, 0xf6, 0xdd, 0xc6, 0x29, 0x23, 0x77,
+	0x90, 0x53, 0x2e, 0x8b, 0xab, 0xf2, 0x52, 0xfe, 0x88, 0xa1, 0xce, 0x80, 0x9b, 0x01, 0xc7, 0xc5,
+	0x56, 0xe0, 0x59, 0xed, 0x36, 0x86, 0x12, 0xdb, 0xe7, 0xd8, 0x94, 0xe0, 0x59, 0xcb, 0x3a, 0x41,
+	0x21, 0x9e, 0x11, 0x3a, 0xb6, 0xa4, 0x8a, 0xfb, 0x5c, 0x43, 0xa6, 0x24, 0xff, 0x53, 0x82, 0xe9,
+	0xc2, 0x53, 0x9f, 0x4d, 0x49, 0xbb, 0x83, 0x19, 0x89, 0x80, 0x1e, 0x55, 0xb1, 0xeb, 0x19, 0xb9,
+	0x78, 0xb7, 0x04, 0xfe, 0x25, 0x77, 0x94, 0x70, 0xea, 0x89, 0x1a, 0xd3, 0x93, 0xa4, 0xb0, 0xf6,
+	0xa3, 0x02, 0xa4, 0x7f, 0x3f, 0x1e, 0x53, 0x03, 0x0e, 0x95, 0x71, 0xa2, 0x12, 0x3e, 0x27, 0xa2,
+	0xd2, 0xa1, 0xe4, 0xba, 0xa4, 0xa8, 0xb6, 0xa9, 0x27, 0xa8, 0x6d, 0x1f, 0x42, 0xdd, 0x88, 0xa0,
+	0xa8, 0xe5, 0x27, 0xfd, 0xf9, 0x31, 0x78, 0x35, 0x61, 0xa4, 0x69, 0x84, 0xd2, 0xbe, 0x63, 0x0f,
+	0x15, 0x20, 0xd7, 0x2d, 0xa8, 0x6d, 0x77, 0x1c, 0x63, 0x2f, 0x44, 0xcc, 0x8a, 0xb0, 0x8f, 0x64,
+	0x0b, 0x83, 0x50, 0x0f, 0x82, 0x4d, 0x8c, 0xb5, 0xa7, 0x30, 0x95, 0x84, 0x44, 0xb3, 0xe3, 0xf8,
+	0xec, 0x8c, 0x92, 0x20, 0x95, 0x7c, 0x4a, 0x36, 0xf9, 0x3c, 0xb8, 0xd4, 0xb7, 0xe5, 0xd9, 0x44,
+	0x20, 0x6f, 0x25, 0x7a, 0x86, 0x81, 0xcd, 0x4f, 0xb4, 0x67, 0x48, 0x6a, 0xdf, 0x60, 0x97, 0x92,
+	0xf4, 0x93, 0xe2, 0x9a, 0xce, 0xa2, 0x1d, 0xc7, 0x38, 0x09, 0x5f, 0xa0, 0x32, 0xea, 0x31, 0x4e,
+	0x22, 0xfa, 0xa8, 0x4e, 0x5b, 0xbb, 0x07, 0x43, 0x82, 0xef, 0x98, 0x17, 0xed, 0x80, 0x10, 0xd4,
+	0x6c, 0x18, 0x8f, 0xc6, 0xd2, 0x1b, 0x47, 0xe8, 0xc1, 0xf7, 0xca, 0xe3, 0x8e, 0x99, 0x53, 0x95,
+	0x9e, 0xe2, 0x1c, 0xeb, 0xec, 0x20, 0x67, 0x6b, 0x7a, 0x4a, 0xfb, 0x43, 0x85, 0x21, 0x59, 0x75,
+	0xaf, 0x42, 0x75, 0xd5, 0x5f, 0xe6, 0xe1, 0xc3, 0x24, 0x3c, 0x8f, 0xd0, 0x64, 0x82, 0x5b, 0x21,
+	0x86, 0x49, 0x2b, 0x17, 0x92, 0xf8, 0x6e, 0xa9, 0xc9, 0xa1, 0xf0, 0x7c, 0x98, 0x3b, 0x33, 0x03,
+	0xda, 0x7d, 0xc9, 0x44, 0xd3, 0x12, 0xf8, 0x36, 0x39, 0xbf, 0x8e, 0x97, 0xbc, 0xe2, 0x39, 0xae,
+	0x1b, 0x71, 0x84, 0x80, 0x78, 0x8c, 0x9a, 0x7e, 0x39, 0xf2, 0x2e, 0x4c, 0xf0, 0x49, 0x2c, 0x7e,
+	0xb1, 0x2a, 0x59, 0xef, 0x49, 0x7f, 0x36, 0xd3, 0x3c, 0x2b, 0xef, 0xc1, 0x3e, 0x71, 0x4d, 0xf4,
+	0x46, 0xe8, 0x42, 0x5e, 0xf2, 0xb9, 0x70, 0x7f, 0x0f, 0x96, 0x5c, 0x10, 0xcd, 0x89, 0xe4, 0x9f,
+	0x91, 0xc3, 0x7d, 0xcf, 0x48, 0xf2, 0x86, 0x68, 0x70, 0xda, 0xac, 0x31, 0x22, 0xa2, 0xf2, 0x52,
+	0x16, 0x4e, 0xc3, 0x0c, 0x6e, 0xcb, 0xe6, 0x06, 0x23, 0x00, 0x7b, 0x75, 0x61, 0xa8, 0xeb, 0x7a,
+	0xce, 0xbe, 0xde, 0x69, 0x54, 0x85, 0xc6, 0xcc, 0x1c, 0xb9, 0x00, 0x43, 0x1f, 0xf7, 0x98, 0x77,
+	0xd8, 0x00, 0xd1, 0x4d, 0x49, 0x42, 0xdb, 0x83, 0x0b, 0x31, 0x6e, 0x45, 0x7a, 0x39, 0xe8, 0xbc,
+	0x00, 0x5e, 0xce, 0x46, 0xcd, 0x98, 0x32, 0x10, 0x74, 0x24, 0x83, 0xf6, 0x5f, 0x09, 0x26, 0x72,
+	0x9f, 0x33, 0x5e, 0x64, 0xa3, 0x22, 0x40, 0x55, 0xce, 0x02, 0x50, 0x0b, 0xfa, 0x13, 0x6c, 0x31,
+	0x2f, 0xca, 0x32, 0xec, 0x5b, 0x5f, 0xb0, 0x96, 0x8b, 0x45, 0xd8, 0x67, 0x58, 0xca, 0x65, 0x21,
+	0x56, 0x28, 0x11, 0x8b, 0x9b, 0xb8, 0xb6, 0x81, 0xcd, 0x95, 0x58, 0x21, 0xd7, 0xa1, 0x16, 0x7d,
+	0xcd, 0x48, 0x60, 0x3a, 0xfd, 0x81, 0xe3, 0xbb, 0x12, 0xd6, 0xa6, 0xc4, 0xc9, 0x67, 0x04, 0xb6,
+	0x0f, 0x60, 0x6c, 0x3b, 0x51, 0x1a, 0x3f, 0x24, 0x6f, 0x16, 0x17, 0xa7, 0xf4, 0xfe, 0x59, 0x39,
+	0xcd, 0x84, 0xd1, 0x74, 0xd1, 0x25, 0x04, 0xca, 0x81, 0xd5, 0x95, 0xc8, 0x58, 0xa5, 0x62, 0xcc,
+	0xe7, 0x6c, 0xc7, 0x8c, 0x5a, 0x72, 0x31, 0xe6, 0x73, 0x06, 0x9f, 0x53, 0xe5, 0x1c, 0x1f, 0x73,
+	0x34, 0xe8, 0xca, 0x77, 0xa8, 0x70, 0x58, 0x95, 0x46, 0xa4, 0x76, 0x1b, 0x46, 0xd3, 0x37, 0xcb,
+	0xa5, 0x77, 0xad, 0xf6, 0x6e, 0xf8, 0xad, 0x45, 0x8c, 0x49, 0x1d, 0xd4, 0x8e, 0x73, 0x10, 0xe2,
+	0x08, 0x1f, 0x6a, 0x3b, 0x30, 0x9a, 0x76, 0xc1, 0xc9, 0xa4, 0x84, 0xb5, 0x7a, 0x37, 0xb6, 0x8c,
+	0x8f, 0x39, 0x8a, 0xf1, 0x7f, 0xb4, 0xc0, 0x88, 0x6c, 0x4b, 0x26, 0xe6, 0x66, 0xa0, 0x12, 0x7e,
+	0x79, 0xaa, 0xc2, 0xd0, 0x13, 0xcf, 0x0a, 0x58, 0xfd, 0x1c, 0x19, 0x81, 0xf2, 0x86, 0xee, 0xfb,
+	0xf5, 0xd2, 0xdc, 0xac, 0x04, 0xdf, 0xe4, 0x3d, 0x45, 0x00, 0x2a, 0x4d, 0x0f, 0x7d, 0xcc, 0xf9,
+	0x70, 0x2c, 0x7b, 0x5a, 0xe4, 0x7c, 0x07, 0x20, 0xc9, 0x53, 0xae, 0x61, 0xfd, 0xf1, 0xfa, 0x7d,
+	0xe4, 0xa9, 0xc1, 0xf0, 0x93, 0xa5, 0xd5, 0xad, 0xd5, 0xf5, 0x07, 0xf5, 0x92, 0x20, 0xa8, 0x24,
+	0x14, 0xce, 0xb3, 0xc2, 0x79, 0xd4, 0xb9, 0xd7, 0x73, 0xb5, 0x89, 0x0c, 0x83, 0xba, 0xd4, 0xe9,
+	0xa0, 0x74, 0x05, 0x94, 0x95, 0x65, 0x14, 0xc4, 0x9d, 0xd6, 0x1d, 0xaf, 0xab, 0x77, 0xea, 0xca,
+	0xdc, 0x5d, 0x18, 0xcf, 0x46, 0xbc, 0x50, 0xeb, 0x78, 0x7b, 0x78, 0x91, 0x72, 0xc3, 0xcd, 0x40,
+	0x00, 0xa0, 0xdc, 0x50, 0x5a, 0x68, 0xd6, 0x95, 0xe5, 0xf7, 0x7e, 0xf9, 0xeb, 0x5a, 0xe9, 0x39,
+	0xfe, 0xfe, 0xc4, 0xdf, 0xb7, 0x7f, 0x5f, 0x3b, 0xf7, 0x1c, 0x7f, 0xbf, 0xe3, 0xef, 0xf3, 0x97,
+	0xdb, 0x56, 0xb0, 0xdb, 0xdb, 0x9e, 0xc7, 0xcc, 0x59, 0x70, 0x51, 0x8d, 0xa1, 0xbb, 0x0b, 0x81,
+	0x65, 0x98, 0xc6, 0x42, 0x2a, 0xa6, 0xb6, 0x2b, 0xe2, 0x93, 0xed, 0xad, 0xff, 0x01, 0x48, 0x38,
+	0xbd, 0xb0, 0xd1, 0x15, 0x00, 0x00,
 }
 
 func (m *TableSpan) Marshal() (dAtA []byte, err error) {
@@ -2339,6 +2383,11 @@ func (m *HeartBeatRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.DeadLetterRows != 0 {
+		i = encodeVarintHeartbeat(dAtA, i, uint64(m.DeadLetterRows))
+		i--
+		dAtA[i] = 0x30
+	}
 	if m.Err != nil {
 		{
 			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
@@ -2894,6 +2943,11 @@ func (m *MaintainerStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.DeadLetterRows != 0 {
+		i = encodeVarintHeartbeat(dAtA, i, uint64(m.DeadLetterRows))
+		i--
+		dAtA[i] = 0x30
+	}
 	if len(m.Err) > 0 {
 		for iNdEx := len(m.Err) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -3539,6 +3593,23 @@ func (m *State) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintHeartbeat(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.NeedApproval {
+		i--
+		if m.NeedApproval {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x48
+	}
 	if m.Stage != 0 {
 		i = encodeVarintHeartbeat(dAtA, i, uint64(m.Stage))
 		i--
@@ -3691,6 +3762,11 @@ func (m *TableSpanStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ResolvedTs != 0 {
+		i = encodeVarintHeartbeat(dAtA, i, uint64(m.ResolvedTs))
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.EventSizePerSecond != 0 {
 		i -= 4
 		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.EventSizePerSecond))))
@@ -3960,6 +4036,9 @@ func (m *HeartBeatRequest) Size() (n int) {
 		l = m.Err.Size()
 		n += 1 + l + sovHeartbeat(uint64(l))
 	}
+	if m.DeadLetterRows != 0 {
+		n += 1 + sovHeartbeat(uint64(m.DeadLetterRows))
+	}
 	return n
 }
 
@@ -4179,6 +4258,9 @@ func (m *MaintainerStatus) Size() (n int) {
 			n += 1 + l + sovHeartbeat(uint64(l))
 		}
 	}
+	if m.DeadLetterRows != 0 {
+		n += 1 + sovHeartbeat(uint64(m.DeadLetterRows))
+	}
 	return n
 }
 
@@ -4453,6 +4535,13 @@ func (m *State) Size() (n int) {
 	if m.Stage != 0 {
 		n += 1 + sovHeartbeat(uint64(m.Stage))
 	}
+	if m.NeedApproval {
+		n += 2
+	}
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovHeartbeat(uint64(l))
+	}
 	return n
 }
 
@@ -4492,6 +4581,9 @@ func (m *TableSpanStatus) Size() (n int) {
 	if m.EventSizePerSecond != 0 {
 		n += 5
 	}
+	if m.ResolvedTs != 0 {
+		n += 1 + sovHeartbeat(uint64(m.ResolvedTs))
+	}
 	return n
 }
 
@@ -4911,6 +5003,25 @@ func (m *HeartBeatRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeadLetterRows", wireType)
+			}
+			m.DeadLetterRows = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHeartbeat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DeadLetterRows |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHeartbeat(dAtA[iNdEx:])
@@ -6331,6 +6442,25 @@ func (m *MaintainerStatus) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeadLetterRows", wireType)
+			}
+			m.DeadLetterRows = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHeartbeat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DeadLetterRows |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHeartbeat(dAtA[iNdEx:])
@@ -8153,6 +8283,58 @@ func (m *State) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NeedApproval", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHeartbeat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.NeedApproval = bool(v != 0)
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHeartbeat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHeartbeat
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHeartbeat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHeartbeat(dAtA[iNdEx:])
@@ -8410,6 +8592,25 @@ func (m *TableSpanStatus) Unmarshal(dAtA []byte) error {
 			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
 			iNdEx += 4
 			m.EventSizePerSecond = float32(math.Float32frombits(v))
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResolvedTs", wireType)
+			}
+			m.ResolvedTs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHeartbeat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ResolvedTs |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHeartbeat(dAtA[iNdEx:])
@@ -26,7 +26,6 @@ import (
 	"github.com/pingcap/ticdc/pkg/messaging"
 	"github.com/pingcap/ticdc/pkg/node"
 	"github.com/pingcap/ticdc/server/watcher"
-	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 
 	"github.com/pingcap/ticdc/pkg/common"
@@ -167,15 +166,12 @@ func (c *logCoordinator) updateEventStoreState(nodeId node.ID, state *logservice
 
 // getCandidateNode return all nodes(exclude the request node) which may contain data for `span` from `startTs`,
 // and the return slice should be sorted by resolvedTs(largest first).
+// The span can be a sub span of a table, a node is a candidate if its subscriptions
+// contain the span, or the overlapping subscriptions cover the span together.
 func (c *logCoordinator) getCandidateNodes(requestNodeID node.ID, span *heartbeatpb.TableSpan, startTs uint64) []node.ID {
 	c.eventStoreStates.RLock()
 	defer c.eventStoreStates.RUnlock()
 
-	// TODO: support incomplete span
-	if !isCompleteSpan(span) {
-		return nil
-	}
-
 	type candidateNode struct {
 		nodeID     node.ID
 		resolvedTs uint64
@@ -189,23 +185,11 @@ func (c *logCoordinator) getCandidateNodes(requestNodeID node.ID, span *heartbea
 		if !ok {
 			continue
 		}
-		// Find the maximum resolvedTs for the current nodeID
-		var maxResolvedTs uint64
-		found := false
-		for _, subscriptionState := range subscriptionStates {
-			if subscriptionState.checkpointTs <= startTs {
-				if !found || subscriptionState.resolvedTs > maxResolvedTs {
-					maxResolvedTs = subscriptionState.resolvedTs
-					found = true
-				}
-			}
-		}
-
-		// If a valid subscription with checkpointTs <= startTs was found, add to candidates
-		if found {
+		// If valid subscriptions with checkpointTs <= startTs cover the span, add to candidates
+		if resolvedTs, ok := getCoveringResolvedTs(span, subscriptionStates, startTs); ok {
 			candidates = append(candidates, candidateNode{
 				nodeID:     nodeID,
-				resolvedTs: maxResolvedTs,
+				resolvedTs: resolvedTs,
 			})
 		}
 	}
@@ -222,10 +206,43 @@ func (c *logCoordinator) getCandidateNodes(requestNodeID node.ID, span *heartbea
 	return candidateNodes
 }
 
-func isCompleteSpan(tableSpan *heartbeatpb.TableSpan) bool {
-	startKey, endKey := spanz.GetTableRange(tableSpan.TableID)
-	if spanz.StartCompare(startKey, tableSpan.StartKey) == 0 && spanz.EndCompare(endKey, tableSpan.EndKey) == 0 {
-		return true
+// getCoveringResolvedTs returns the resolvedTs up to which the data of `span` from `startTs`
+// can be read from the subscriptions, and false if the subscriptions can't cover the span.
+// If some subscription contains the span, the maximum resolvedTs of them is returned,
+// otherwise the overlapping subscriptions are read together by the event store,
+// and the minimum resolvedTs of them is returned.
+func getCoveringResolvedTs(span *heartbeatpb.TableSpan, states subscriptionStates, startTs uint64) (uint64, bool) {
+	var (
+		overlapping   subscriptionStates
+		spans         []*heartbeatpb.TableSpan
+		maxResolvedTs uint64
+		contained     bool
+	)
+	for _, state := range states {
+		if state.checkpointTs > startTs {
+			continue
+		}
+		if common.IsSubSpan(span, state.span) {
+			if !contained || state.resolvedTs > maxResolvedTs {
+				maxResolvedTs = state.resolvedTs
+				contained = true
+			}
+			continue
+		}
+		overlapping = append(overlapping, state)
+		spans = append(spans, state.span)
+	}
+	if contained {
+		return maxResolvedTs, true
+	}
+
+	indexes, _, ok := common.GetCoveringSpans(span, spans)
+	if !ok {
+		return 0, false
+	}
+	minResolvedTs := overlapping[indexes[0]].resolvedTs
+	for _, index := range indexes[1:] {
+		minResolvedTs = min(minResolvedTs, overlapping[index].resolvedTs)
 	}
-	return false
+	return minResolvedTs, true
 }
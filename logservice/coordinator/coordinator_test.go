@@ -183,3 +183,72 @@ func TestGetCandidateNodes(t *testing.T) {
 		assert.Equal(t, []node.ID{nodeID2, nodeID1}, nodes)
 	}
 }
+
+func TestGetCandidateNodesForSubSpan(t *testing.T) {
+	coordinator := &logCoordinator{}
+
+	nodeID1 := node.ID("node-1")
+	nodeID2 := node.ID("node-2")
+	nodeID3 := node.ID("node-3")
+	nodeID4 := node.ID("node-4")
+
+	tableID := int64(100)
+	newSpan := func(start, end string) *heartbeatpb.TableSpan {
+		return &heartbeatpb.TableSpan{TableID: tableID, StartKey: []byte(start), EndKey: []byte(end)}
+	}
+	tableSpan := newSpan("a", "z")
+
+	coordinator.eventStoreStates.m = map[node.ID]*eventStoreState{
+		// node1 subscribes the whole table
+		nodeID1: {
+			subscriptionStates: map[int64]subscriptionStates{
+				tableID: {
+					{subID: 1, span: tableSpan, checkpointTs: 90, resolvedTs: 200},
+				},
+			},
+		},
+		// node2 subscribes two sub spans which cover [c, p) together
+		nodeID2: {
+			subscriptionStates: map[int64]subscriptionStates{
+				tableID: {
+					{subID: 1, span: newSpan("b", "h"), checkpointTs: 90, resolvedTs: 260},
+					{subID: 2, span: newSpan("f", "q"), checkpointTs: 90, resolvedTs: 250},
+				},
+			},
+		},
+		// node3 subscribes two sub spans with a gap [h, k)
+		nodeID3: {
+			subscriptionStates: map[int64]subscriptionStates{
+				tableID: {
+					{subID: 1, span: newSpan("a", "h"), checkpointTs: 90, resolvedTs: 300},
+					{subID: 2, span: newSpan("k", "z"), checkpointTs: 90, resolvedTs: 300},
+				},
+			},
+		},
+		// node4 subscribes a sub span whose data before startTs is deleted
+		nodeID4: {
+			subscriptionStates: map[int64]subscriptionStates{
+				tableID: {
+					{subID: 1, span: newSpan("c", "p"), checkpointTs: 110, resolvedTs: 400},
+				},
+			},
+		},
+	}
+
+	// the sub span is contained in a subscription of node1 and node3
+	nodes := coordinator.getCandidateNodes(nodeID4, newSpan("c", "e"), uint64(100))
+	assert.Equal(t, []node.ID{nodeID3, nodeID2, nodeID1}, nodes)
+
+	// the sub span is covered by the overlapping subscriptions of node2,
+	// the min resolved ts of them is used.
+	nodes = coordinator.getCandidateNodes(nodeID4, newSpan("c", "p"), uint64(100))
+	assert.Equal(t, []node.ID{nodeID2, nodeID1}, nodes)
+
+	// the sub span can't be covered by node2 and node3
+	nodes = coordinator.getCandidateNodes(nodeID4, newSpan("c", "r"), uint64(100))
+	assert.Equal(t, []node.ID{nodeID1}, nodes)
+
+	// node4 contains the sub span when startTs >= its checkpointTs
+	nodes = coordinator.getCandidateNodes(nodeID1, newSpan("d", "e"), uint64(120))
+	assert.Equal(t, []node.ID{nodeID4, nodeID3, nodeID2}, nodes)
+}
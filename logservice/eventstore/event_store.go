@@ -1,10 +1,13 @@
 package eventstore
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -16,10 +19,12 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/heartbeatpb"
 	"github.com/pingcap/ticdc/logservice/logpuller"
+	"github.com/pingcap/ticdc/logservice/pebbleutil"
 	"github.com/pingcap/ticdc/logservice/txnutil"
 	"github.com/pingcap/ticdc/pkg/common"
 	appcontext "github.com/pingcap/ticdc/pkg/common/context"
 	"github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/messaging"
 	"github.com/pingcap/ticdc/pkg/metrics"
 	"github.com/pingcap/ticdc/pkg/node"
@@ -102,10 +107,21 @@ type dispatcherStat struct {
 	// the max ts of events which is not needed by this dispatcher
 	checkpointTs uint64
 
+	// the subscriptions the data of the dispatcher is read from, sorted by the start key.
+	// Usually a single subscription contains the span of the dispatcher, otherwise the
+	// overlapping subscriptions cover it together, and each of them is read in a disjoint part.
+	subs []dispatcherSubscription
+}
+
+type dispatcherSubscription struct {
 	subID logpuller.SubscriptionID
+	// the part of the dispatcher span which is read from the subscription
+	readSpan *heartbeatpb.TableSpan
 }
 
 type subscriptionStat struct {
+	// the span of the subscription, it contains the spans of all dispatchers depend on it
+	tableSpan *heartbeatpb.TableSpan
 	// dispatchers depend on this subscription
 	ids map[common.DispatcherID]bool
 	// events of this subsription will be send to the channel identified by chIndex
@@ -150,24 +166,43 @@ type eventStore struct {
 		m map[common.DispatcherID]*dispatcherStat
 		n map[logpuller.SubscriptionID]*subscriptionStat
 		// table id -> dispatcher ids
-		// use table id as the key is to share data between spans not completely the same in the future.
+		// use table id as the key is to share data between spans not completely the same.
 		l map[int64]map[common.DispatcherID]bool
+		// table id -> subscriptions restored from disk which are not reused by any dispatcher yet
+		p map[int64][]*persistedSubscription
 	}
 
-	encoder *zstd.Encoder
+	// decoder is shared by all the iterators, each write goroutine has its own encoder.
 	decoder *zstd.Decoder
+
+	// diskQuota is the max disk usage of the dbs, 0 means unlimited.
+	diskQuota uint64
+	// diskQuotaExceeded is true if the disk usage exceeds the quota,
+	// the writes are paused until it's false.
+	diskQuotaExceeded atomic.Bool
 }
 
 const dataDir = "event_store"
-const dbCount = 32
+
+const (
+	// persistMetaInterval is the interval to persist the metas of subscriptions.
+	persistMetaInterval = 5 * time.Second
+	// persistedSubscriptionTTL is the time to keep the subscriptions restored from disk
+	// which are not reused by any dispatcher after start.
+	persistedSubscriptionTTL = 10 * time.Minute
+	// diskQuotaCheckInterval is the interval to check whether the paused writes can be resumed.
+	diskQuotaCheckInterval = 100 * time.Millisecond
+)
 
 func New(
 	ctx context.Context,
 	root string,
+	cfg *config.EventStoreConfig,
 	pdCli pd.Client,
 	regionCache *tikv.RegionCache,
 	pdClock pdutil.Clock,
 	kvStorage kv.Storage,
+	credential *security.Credential,
 ) EventStore {
 	clientConfig := &logpuller.SubscriptionClientConfig{
 		RegionRequestWorkerPerStore:   16,
@@ -181,20 +216,20 @@ func New(
 		regionCache,
 		pdClock,
 		txnutil.NewLockerResolver(kvStorage.(tikv.Storage)),
-		&security.Credential{},
+		credential,
 	)
 
 	dbPath := fmt.Sprintf("%s/%s", root, dataDir)
-
-	// FIXME: avoid remove
-	err := os.RemoveAll(dbPath)
-	if err != nil {
-		log.Panic("fail to remove path")
+	// Without WAL, the data and metas of subscriptions in the memtables are lost
+	// on crash, so the data on disk can't be reused.
+	if cfg.DisableWAL {
+		if err := os.RemoveAll(dbPath); err != nil {
+			log.Panic("fail to remove path", zap.String("path", dbPath), zap.Error(err))
+		}
 	}
-	// Create the zstd encoder
-	encoder, err := zstd.NewWriter(nil)
-	if err != nil {
-		log.Panic("Failed to create zstd encoder", zap.Error(err))
+	// The dbs beyond the count are never opened again, remove them to release the disk space.
+	if err := removeExtraDBs(dbPath, cfg.Count); err != nil {
+		log.Panic("fail to remove extra dbs", zap.String("path", dbPath), zap.Error(err))
 	}
 
 	decoder, err := zstd.NewReader(nil)
@@ -204,40 +239,26 @@ func New(
 
 	store := &eventStore{
 		pdClock:  pdClock,
-		dbs:      make([]*pebble.DB, 0, dbCount),
-		eventChs: make([]chan eventWithState, 0, dbCount),
+		dbs:      make([]*pebble.DB, 0, cfg.Count),
+		eventChs: make([]chan eventWithState, 0, cfg.Count),
 
 		gcManager: newGCManager(),
-		encoder:   encoder,
 		decoder:   decoder,
+		diskQuota: cfg.DiskQuota,
 	}
-	// TODO: update pebble options
-	for i := 0; i < dbCount; i++ {
-		opts := &pebble.Options{
-			DisableWAL:   true,
-			MemTableSize: 8 << 20,
-		}
-		// opts.Levels = make([]pebble.LevelOptions, 7)
-		// for i := 0; i < len(opts.Levels); i++ {
-		// 	l := &opts.Levels[i]
-		// 	l.BlockSize = 64 << 10       // 64 KB
-		// 	l.IndexBlockSize = 256 << 10 // 256 KB
-		// 	l.FilterPolicy = bloom.FilterPolicy(10)
-		// 	l.FilterType = pebble.TableFilter
-		// 	l.TargetFileSize = 8 << 20 // 8 MB
-		// 	// 	l.Compression = pebble.ZstdCompression // TODO: choose the right compression
-		// 	l.EnsureDefaults()
-		// }
-		db, err := pebble.Open(fmt.Sprintf("%s/%d", dbPath, i), opts)
+	store.dispatcherStates.m = make(map[common.DispatcherID]*dispatcherStat)
+	store.dispatcherStates.n = make(map[logpuller.SubscriptionID]*subscriptionStat)
+	store.dispatcherStates.l = make(map[int64]map[common.DispatcherID]bool)
+	store.dispatcherStates.p = make(map[int64][]*persistedSubscription)
+	for i := 0; i < cfg.Count; i++ {
+		db, err := pebble.Open(fmt.Sprintf("%s/%d", dbPath, i), pebbleutil.NewOptions(&cfg.PebbleConfig))
 		if err != nil {
 			log.Fatal("open db failed", zap.Error(err))
 		}
 		store.dbs = append(store.dbs, db)
 		store.eventChs = append(store.eventChs, make(chan eventWithState, 8192))
+		store.loadPersistedSubscriptions(i, db)
 	}
-	store.dispatcherStates.m = make(map[common.DispatcherID]*dispatcherStat)
-	store.dispatcherStates.n = make(map[logpuller.SubscriptionID]*subscriptionStat)
-	store.dispatcherStates.l = make(map[int64]map[common.DispatcherID]bool)
 
 	// start background goroutines to handle events from puller
 	for i := range store.dbs {
@@ -272,6 +293,34 @@ func New(
 	return store
 }
 
+// removeExtraDBs removes the dbs under dbPath whose index is not less than count,
+// which are left on disk after the number of dbs is decreased.
+func removeExtraDBs(dbPath string, count int) error {
+	entries, err := os.ReadDir(dbPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		index, err := strconv.Atoi(entry.Name())
+		if err != nil || index < count {
+			continue
+		}
+		path := filepath.Join(dbPath, entry.Name())
+		log.Info("remove the db which exceeds the count",
+			zap.String("path", path), zap.Int("count", count))
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (e *eventStore) handleMessage(_ context.Context, targetMessage *messaging.TargetMessage) error {
 	for _, msg := range targetMessage.Message {
 		switch msg.(type) {
@@ -302,18 +351,19 @@ func (e *eventStore) uploadStatePeriodically(ctx context.Context) error {
 				subIDs := make(map[logpuller.SubscriptionID]bool)
 				for dispatcherID := range dispatcherIDs {
 					dispatcherStat := e.dispatcherStates.m[dispatcherID]
-					subID := dispatcherStat.subID
-					subStat := e.dispatcherStates.n[subID]
-					if _, ok := subIDs[subID]; ok {
-						continue
+					for _, sub := range dispatcherStat.subs {
+						if _, ok := subIDs[sub.subID]; ok {
+							continue
+						}
+						subStat := e.dispatcherStates.n[sub.subID]
+						subStates = append(subStates, &logservicepb.SubscriptionState{
+							SubID:        uint64(sub.subID),
+							Span:         subStat.tableSpan,
+							CheckpointTs: subStat.checkpointTs,
+							ResolvedTs:   atomic.LoadUint64(&subStat.resolvedTs),
+						})
+						subIDs[sub.subID] = true
 					}
-					subStates = append(subStates, &logservicepb.SubscriptionState{
-						SubID:        uint64(subID),
-						Span:         dispatcherStat.tableSpan,
-						CheckpointTs: subStat.checkpointTs,
-						ResolvedTs:   subStat.resolvedTs,
-					})
-					subIDs[subID] = true
 				}
 				sort.Slice(subStates, func(i, j int) bool {
 					return subStates[i].SubID < subStates[j].SubID
@@ -346,7 +396,7 @@ func (e *eventStore) Run(ctx context.Context) error {
 
 	// TODO: manage gcManager exit
 	eg.Go(func() error {
-		return e.gcManager.run(ctx, e.deleteEvents)
+		return e.gcManager.run(ctx, e.deleteEvents, e.deleteSubscription)
 	})
 
 	eg.Go(func() error {
@@ -357,6 +407,14 @@ func (e *eventStore) Run(ctx context.Context) error {
 		return e.uploadStatePeriodically(ctx)
 	})
 
+	eg.Go(func() error {
+		return e.persistSubscriptionMetasPeriodically(ctx)
+	})
+
+	eg.Go(func() error {
+		return e.cleanPersistedSubscriptions(ctx)
+	})
+
 	return eg.Wait()
 }
 
@@ -378,6 +436,9 @@ func (e *eventStore) Close(ctx context.Context) error {
 	// TODO: wait gc manager, because it may also write data to pebble db
 	e.wg.Wait()
 
+	// all the events are written, persist the latest metas before closing db.
+	e.persistSubscriptionMetas()
+
 	for _, db := range e.dbs {
 		if err := db.Close(); err != nil {
 			log.Error("failed to close pebble db", zap.Error(err))
@@ -420,37 +481,27 @@ func (e *eventStore) RegisterDispatcher(
 	}
 
 	e.dispatcherStates.Lock()
-	if candidateIDs, ok := e.dispatcherStates.l[tableSpan.TableID]; ok {
-		for candidateID := range candidateIDs {
-			candidateDispatcher, ok := e.dispatcherStates.m[candidateID]
-			if !ok {
-				log.Panic("should not happen")
-			}
-			if candidateDispatcher.tableSpan.Equal(tableSpan) {
-				subscriptionStat, ok := e.dispatcherStates.n[candidateDispatcher.subID]
-				if !ok {
-					log.Panic("should not happen")
-				}
-				// check whether startTs is in the range [checkpointTs, resolvedTs]
-				// for `[checkpointTs`: because we want data > startTs, so data <= checkpointTs == startTs deleted is ok.
-				// for `resolvedTs]`: startTs == resolvedTs is a special case that no resolved ts has been recieved, so it is ok.
-				if subscriptionStat.checkpointTs <= startTs && startTs <= subscriptionStat.resolvedTs {
-					stat.subID = candidateDispatcher.subID
-					e.dispatcherStates.m[dispatcherID] = stat
-					// add dispatcher to existing subscription and return
-					subscriptionStat.ids[dispatcherID] = true
-					candidateIDs[dispatcherID] = true
-					e.dispatcherStates.Unlock()
-					log.Info("reuse existing subscription",
-						zap.Any("dispatcherID", dispatcherID),
-						zap.Uint64("subID", uint64(stat.subID)),
-						zap.Uint64("checkpointTs", subscriptionStat.checkpointTs),
-						zap.Uint64("startTs", startTs))
-					return nil
-				}
-			}
+	if subs := e.findSharedSubscriptions(tableSpan, startTs); len(subs) > 0 {
+		stat.subs = subs
+		e.dispatcherStates.m[dispatcherID] = stat
+		// add dispatcher to existing subscriptions and return
+		for _, sub := range subs {
+			subscriptionStat := e.dispatcherStates.n[sub.subID]
+			subscriptionStat.ids[dispatcherID] = true
+			log.Info("reuse existing subscription",
+				zap.Any("dispatcherID", dispatcherID),
+				zap.Uint64("subID", uint64(sub.subID)),
+				zap.String("subscriptionSpan", subscriptionStat.tableSpan.String()),
+				zap.String("readSpan", sub.readSpan.String()),
+				zap.Uint64("checkpointTs", subscriptionStat.checkpointTs),
+				zap.Uint64("startTs", startTs))
 		}
+		e.dispatcherStates.l[tableSpan.TableID][dispatcherID] = true
+		e.dispatcherStates.Unlock()
+		return nil
 	}
+	// try to reuse the data persisted before restart
+	persisted := e.takePersistedSubscription(tableSpan, startTs)
 	e.dispatcherStates.Unlock()
 
 	// cannot share data from existing subscription, create a new subscription
@@ -460,27 +511,46 @@ func (e *eventStore) RegisterDispatcher(
 	// But if we need to share data for sub span, we need hash table id instead.
 	chIndex := common.HashTableSpan(tableSpan, len(e.eventChs))
 	uniqueKeyID := genUniqueID()
+	checkpointTs, resolvedTs, maxEventCommitTs := startTs, startTs, startTs
+	if persisted != nil {
+		// the data in (checkpointTs, resolvedTs] is on disk, only pull the data after resolvedTs.
+		chIndex = persisted.chIndex
+		uniqueKeyID = persisted.uniqueKeyID
+		checkpointTs = persisted.meta.CheckpointTs
+		resolvedTs = persisted.meta.ResolvedTs
+		if persisted.meta.MaxEventCommitTs > maxEventCommitTs {
+			maxEventCommitTs = persisted.meta.MaxEventCommitTs
+		}
+		log.Info("reuse persisted subscription",
+			zap.Any("dispatcherID", dispatcherID),
+			zap.Uint64("uniqueKeyID", uniqueKeyID),
+			zap.Uint64("checkpointTs", checkpointTs),
+			zap.Uint64("resolvedTs", resolvedTs),
+			zap.Uint64("startTs", startTs))
+	}
 	// Note: don't hold any lock when call Subscribe
 	// TODO: if puller event come before we initialize dispatcherStat,
 	// maxEventCommitTs may not be updated correctly and cause data loss.(lost resolved ts is harmless)
 	// To fix it, we need to alloc subID and initialize dispatcherStat before puller may send events.
 	// That is allocate subID in a separate method.
-	stat.subID = e.puller.Subscribe(*tableSpan, startTs, subscriptionTag{
+	subID := e.puller.Subscribe(*tableSpan, resolvedTs, subscriptionTag{
 		chIndex:     chIndex,
 		tableID:     tableSpan.TableID,
 		uniqueKeyID: uniqueKeyID,
 	})
+	stat.subs = []dispatcherSubscription{{subID: subID, readSpan: tableSpan}}
 	metrics.EventStoreSubscriptionGauge.Inc()
 
 	e.dispatcherStates.Lock()
 	defer e.dispatcherStates.Unlock()
 	e.dispatcherStates.m[dispatcherID] = stat
-	e.dispatcherStates.n[stat.subID] = &subscriptionStat{
+	e.dispatcherStates.n[subID] = &subscriptionStat{
+		tableSpan:        tableSpan,
 		ids:              map[common.DispatcherID]bool{dispatcherID: true},
 		chIndex:          chIndex,
-		checkpointTs:     startTs,
-		resolvedTs:       startTs,
-		maxEventCommitTs: startTs,
+		checkpointTs:     checkpointTs,
+		resolvedTs:       resolvedTs,
+		maxEventCommitTs: maxEventCommitTs,
 		uniqueKeyID:      uniqueKeyID,
 	}
 	dispatchersForSameTable, ok := e.dispatcherStates.l[tableSpan.TableID]
@@ -493,6 +563,173 @@ func (e *eventStore) RegisterDispatcher(
 	return nil
 }
 
+// findSharedSubscriptions returns the existing subscriptions which the data of the span
+// from startTs can be read from, it must be called with dispatcherStates locked.
+// A subscription containing the span is preferred, otherwise the overlapping subscriptions
+// which cover the span together are returned. It returns nil if the span can't be covered.
+func (e *eventStore) findSharedSubscriptions(tableSpan *heartbeatpb.TableSpan, startTs uint64) []dispatcherSubscription {
+	dispatcherIDs, ok := e.dispatcherStates.l[tableSpan.TableID]
+	if !ok {
+		return nil
+	}
+	var (
+		subIDs []logpuller.SubscriptionID
+		spans  []*heartbeatpb.TableSpan
+	)
+	visited := make(map[logpuller.SubscriptionID]bool)
+	for dispatcherID := range dispatcherIDs {
+		dispatcherStat, ok := e.dispatcherStates.m[dispatcherID]
+		if !ok {
+			log.Panic("should not happen")
+		}
+		for _, sub := range dispatcherStat.subs {
+			if visited[sub.subID] {
+				continue
+			}
+			visited[sub.subID] = true
+			subscriptionStat, ok := e.dispatcherStates.n[sub.subID]
+			if !ok {
+				log.Panic("should not happen")
+			}
+			// check whether startTs is in the range [checkpointTs, resolvedTs]
+			// for `[checkpointTs`: because we want data > startTs, so data <= checkpointTs == startTs deleted is ok.
+			// for `resolvedTs]`: startTs == resolvedTs is a special case that no resolved ts has been recieved, so it is ok.
+			if subscriptionStat.checkpointTs > startTs || startTs > atomic.LoadUint64(&subscriptionStat.resolvedTs) {
+				continue
+			}
+			// the subscription can be shared if its span contains the span of the dispatcher,
+			// the events out of the dispatcher span are filtered out when reading.
+			if common.IsSubSpan(tableSpan, subscriptionStat.tableSpan) {
+				return []dispatcherSubscription{{subID: sub.subID, readSpan: tableSpan}}
+			}
+			subIDs = append(subIDs, sub.subID)
+			spans = append(spans, subscriptionStat.tableSpan)
+		}
+	}
+	indexes, readSpans, ok := common.GetCoveringSpans(tableSpan, spans)
+	if !ok {
+		return nil
+	}
+	subs := make([]dispatcherSubscription, 0, len(indexes))
+	for i, index := range indexes {
+		subs = append(subs, dispatcherSubscription{subID: subIDs[index], readSpan: readSpans[i]})
+	}
+	return subs
+}
+
+// loadPersistedSubscriptions restores the subscriptions persisted in the db before restart,
+// and deletes the data which doesn't belong to any of them.
+func (e *eventStore) loadPersistedSubscriptions(dbIndex int, db *pebble.DB) {
+	metas, err := readSubscriptionMetas(db)
+	if err != nil {
+		log.Panic("read subscription metas failed", zap.Int("dbIndex", dbIndex), zap.Error(err))
+	}
+	if err := deleteOrphanData(db, metas); err != nil {
+		log.Panic("delete orphan data failed", zap.Int("dbIndex", dbIndex), zap.Error(err))
+	}
+	for uniqueKeyID, meta := range metas {
+		// the new subscriptions must not use the unique key id of persisted subscriptions
+		if uniqueKeyID > atomic.LoadUint64(&uniqueIDGen) {
+			atomic.StoreUint64(&uniqueIDGen, uniqueKeyID)
+		}
+		e.dispatcherStates.p[meta.TableID] = append(e.dispatcherStates.p[meta.TableID], &persistedSubscription{
+			chIndex:     dbIndex,
+			uniqueKeyID: uniqueKeyID,
+			meta:        *meta,
+		})
+	}
+	log.Info("load persisted subscriptions", zap.Int("dbIndex", dbIndex), zap.Int("count", len(metas)))
+}
+
+// takePersistedSubscription removes and returns the persisted subscription of the span
+// whose data covers startTs, it must be called with dispatcherStates locked.
+func (e *eventStore) takePersistedSubscription(tableSpan *heartbeatpb.TableSpan, startTs uint64) *persistedSubscription {
+	subs := e.dispatcherStates.p[tableSpan.TableID]
+	for i, sub := range subs {
+		if !sub.meta.span().Equal(tableSpan) {
+			continue
+		}
+		// same as reusing an existing subscription, startTs must be in the range [checkpointTs, resolvedTs]
+		if sub.meta.CheckpointTs <= startTs && startTs <= sub.meta.ResolvedTs {
+			subs = append(subs[:i], subs[i+1:]...)
+			if len(subs) == 0 {
+				delete(e.dispatcherStates.p, tableSpan.TableID)
+			} else {
+				e.dispatcherStates.p[tableSpan.TableID] = subs
+			}
+			return sub
+		}
+	}
+	return nil
+}
+
+// persistSubscriptionMetas writes the metas of all the subscriptions to disk.
+func (e *eventStore) persistSubscriptionMetas() {
+	// Note: hold the lock when writing metas, so the meta of a removed subscription will not be written back.
+	e.dispatcherStates.RLock()
+	defer e.dispatcherStates.RUnlock()
+	metas := make([]map[uint64]*subscriptionMeta, len(e.dbs))
+	for _, subStat := range e.dispatcherStates.n {
+		if metas[subStat.chIndex] == nil {
+			metas[subStat.chIndex] = make(map[uint64]*subscriptionMeta)
+		}
+		metas[subStat.chIndex][subStat.uniqueKeyID] = &subscriptionMeta{
+			TableID:          subStat.tableSpan.TableID,
+			StartKey:         subStat.tableSpan.StartKey,
+			EndKey:           subStat.tableSpan.EndKey,
+			CheckpointTs:     subStat.checkpointTs,
+			ResolvedTs:       atomic.LoadUint64(&subStat.resolvedTs),
+			MaxEventCommitTs: subStat.maxEventCommitTs,
+		}
+	}
+	for i, m := range metas {
+		if err := writeSubscriptionMetas(e.dbs[i], m); err != nil {
+			log.Warn("persist subscription metas failed", zap.Int("dbIndex", i), zap.Error(err))
+		}
+	}
+}
+
+func (e *eventStore) persistSubscriptionMetasPeriodically(ctx context.Context) error {
+	ticker := time.NewTicker(persistMetaInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			e.persistSubscriptionMetas()
+		}
+	}
+}
+
+// cleanPersistedSubscriptions deletes the data of the persisted subscriptions
+// which are not reused by any dispatcher in persistedSubscriptionTTL after start.
+func (e *eventStore) cleanPersistedSubscriptions(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(persistedSubscriptionTTL):
+	}
+	e.dispatcherStates.Lock()
+	persisted := e.dispatcherStates.p
+	e.dispatcherStates.p = make(map[int64][]*persistedSubscription)
+	e.dispatcherStates.Unlock()
+
+	count := 0
+	for _, subs := range persisted {
+		for _, sub := range subs {
+			if err := deleteSubscriptionData(e.dbs[sub.chIndex], sub.uniqueKeyID); err != nil {
+				log.Warn("delete persisted subscription data failed",
+					zap.Uint64("uniqueKeyID", sub.uniqueKeyID), zap.Error(err))
+				continue
+			}
+			count++
+		}
+	}
+	log.Info("clean persisted subscriptions", zap.Int("count", count))
+	return nil
+}
+
 func (e *eventStore) UnregisterDispatcher(dispatcherID common.DispatcherID) error {
 	log.Info("unregister dispatcher", zap.Stringer("dispatcherID", dispatcherID))
 	defer func() {
@@ -504,21 +741,26 @@ func (e *eventStore) UnregisterDispatcher(dispatcherID common.DispatcherID) erro
 	if !ok {
 		return nil
 	}
-	subID := stat.subID
 	tableID := stat.tableSpan.TableID
 	delete(e.dispatcherStates.m, dispatcherID)
 
-	// delete the dispatcher from subscription
-	subscriptionStat, ok := e.dispatcherStates.n[subID]
-	if !ok {
-		log.Panic("should not happen")
-	}
-	delete(subscriptionStat.ids, dispatcherID)
-	if len(subscriptionStat.ids) == 0 {
-		delete(e.dispatcherStates.n, subID)
-		// TODO: do we need unlock before puller.Unsubscribe?
-		e.puller.Unsubscribe(subID)
-		metrics.EventStoreSubscriptionGauge.Dec()
+	// delete the dispatcher from subscriptions
+	for _, sub := range stat.subs {
+		subscriptionStat, ok := e.dispatcherStates.n[sub.subID]
+		if !ok {
+			log.Panic("should not happen")
+		}
+		delete(subscriptionStat.ids, dispatcherID)
+		if len(subscriptionStat.ids) == 0 {
+			delete(e.dispatcherStates.n, sub.subID)
+			// TODO: do we need unlock before puller.Unsubscribe?
+			e.puller.Unsubscribe(sub.subID)
+			metrics.EventStoreSubscriptionGauge.Dec()
+			// the data of the subscription will never be used, delete it in the gc manager
+			// which retries on failure. The events written after the deletion, or the data
+			// not deleted before exit, are cleaned as orphan data at restart.
+			e.gcManager.addGCSubscriptionItem(subscriptionStat.chIndex, subscriptionStat.uniqueKeyID)
+		}
 	}
 
 	// delete the dispatcher from table subscriptions
@@ -542,42 +784,50 @@ func (e *eventStore) UpdateDispatcherSendTs(
 	defer e.dispatcherStates.RUnlock()
 	if stat, ok := e.dispatcherStates.m[dispatcherID]; ok {
 		stat.checkpointTs = sendTs
-		subscriptionStat := e.dispatcherStates.n[stat.subID]
-		// calculate the new checkpoint ts of the subscription
-		newCheckpointTs := uint64(0)
-		for dispatcherID := range subscriptionStat.ids {
-			dispatcherStat := e.dispatcherStates.m[dispatcherID]
-			if newCheckpointTs == 0 || dispatcherStat.checkpointTs < newCheckpointTs {
-				newCheckpointTs = dispatcherStat.checkpointTs
-			}
-		}
-		if newCheckpointTs == 0 {
-			return nil
-		}
-		if newCheckpointTs < subscriptionStat.checkpointTs {
-			log.Panic("should not happen",
-				zap.Uint64("newCheckpointTs", newCheckpointTs),
-				zap.Uint64("oldCheckpointTs", subscriptionStat.checkpointTs))
-		}
-		if subscriptionStat.checkpointTs < newCheckpointTs {
-			e.gcManager.addGCItem(
-				subscriptionStat.chIndex,
-				subscriptionStat.uniqueKeyID,
-				stat.tableSpan.TableID,
-				subscriptionStat.checkpointTs,
-				newCheckpointTs,
-			)
-			log.Debug("update checkpoint ts",
-				zap.Any("dispatcherID", dispatcherID),
-				zap.Uint64("subID", uint64(stat.subID)),
-				zap.Uint64("newCheckpointTs", newCheckpointTs),
-				zap.Uint64("oldCheckpointTs", subscriptionStat.checkpointTs))
-			subscriptionStat.checkpointTs = newCheckpointTs
+		for _, sub := range stat.subs {
+			e.updateSubscriptionCheckpointTs(sub.subID, stat.tableSpan.TableID)
 		}
 	}
 	return nil
 }
 
+// updateSubscriptionCheckpointTs advances the checkpoint ts of the subscription to the minimum
+// checkpoint ts of the dispatchers depend on it, and deletes the data before it.
+// It must be called with dispatcherStates locked.
+func (e *eventStore) updateSubscriptionCheckpointTs(subID logpuller.SubscriptionID, tableID int64) {
+	subscriptionStat := e.dispatcherStates.n[subID]
+	// calculate the new checkpoint ts of the subscription
+	newCheckpointTs := uint64(0)
+	for dispatcherID := range subscriptionStat.ids {
+		dispatcherStat := e.dispatcherStates.m[dispatcherID]
+		if newCheckpointTs == 0 || dispatcherStat.checkpointTs < newCheckpointTs {
+			newCheckpointTs = dispatcherStat.checkpointTs
+		}
+	}
+	if newCheckpointTs == 0 {
+		return
+	}
+	if newCheckpointTs < subscriptionStat.checkpointTs {
+		log.Panic("should not happen",
+			zap.Uint64("newCheckpointTs", newCheckpointTs),
+			zap.Uint64("oldCheckpointTs", subscriptionStat.checkpointTs))
+	}
+	if subscriptionStat.checkpointTs < newCheckpointTs {
+		e.gcManager.addGCItem(
+			subscriptionStat.chIndex,
+			subscriptionStat.uniqueKeyID,
+			tableID,
+			subscriptionStat.checkpointTs,
+			newCheckpointTs,
+		)
+		log.Debug("update checkpoint ts",
+			zap.Uint64("subID", uint64(subID)),
+			zap.Uint64("newCheckpointTs", newCheckpointTs),
+			zap.Uint64("oldCheckpointTs", subscriptionStat.checkpointTs))
+		subscriptionStat.checkpointTs = newCheckpointTs
+	}
+}
+
 func (e *eventStore) GetDispatcherDMLEventState(dispatcherID common.DispatcherID) DMLEventState {
 	e.dispatcherStates.RLock()
 	defer e.dispatcherStates.RUnlock()
@@ -585,11 +835,28 @@ func (e *eventStore) GetDispatcherDMLEventState(dispatcherID common.DispatcherID
 	if !ok {
 		log.Panic("fail to find dispatcher", zap.Any("dispatcherID", dispatcherID))
 	}
-	subscriptionStat := e.dispatcherStates.n[stat.subID]
+	// the events of the dispatcher may be in any of its subscriptions
+	maxEventCommitTs := uint64(0)
+	for _, sub := range stat.subs {
+		maxEventCommitTs = max(maxEventCommitTs, e.dispatcherStates.n[sub.subID].maxEventCommitTs)
+	}
 	return DMLEventState{
 		// ResolvedTs:       subscriptionStat.resolvedTs,
-		MaxEventCommitTs: subscriptionStat.maxEventCommitTs,
+		MaxEventCommitTs: maxEventCommitTs,
+	}
+}
+
+// getDispatcherResolvedTs returns the minimum resolved ts of the subscriptions of the dispatcher,
+// it must be called with dispatcherStates locked.
+func (e *eventStore) getDispatcherResolvedTs(stat *dispatcherStat) uint64 {
+	resolvedTs := uint64(0)
+	for i, sub := range stat.subs {
+		subResolvedTs := atomic.LoadUint64(&e.dispatcherStates.n[sub.subID].resolvedTs)
+		if i == 0 || subResolvedTs < resolvedTs {
+			resolvedTs = subResolvedTs
+		}
 	}
+	return resolvedTs
 }
 
 func (e *eventStore) GetIterator(dispatcherID common.DispatcherID, dataRange common.DataRange) (EventIterator, error) {
@@ -598,42 +865,71 @@ func (e *eventStore) GetIterator(dispatcherID common.DispatcherID, dataRange com
 	if !ok {
 		log.Panic("fail to find dispatcher", zap.Any("dispatcherID", dispatcherID))
 	}
-	subscriptionStat := e.dispatcherStates.n[stat.subID]
-	if dataRange.StartTs < subscriptionStat.checkpointTs {
-		log.Panic("should not happen",
-			zap.Any("dispatcherID", dispatcherID),
-			zap.Uint64("checkpointTs", subscriptionStat.checkpointTs),
-			zap.Uint64("startTs", dataRange.StartTs))
+	type subscriptionRead struct {
+		db          *pebble.DB
+		uniqueKeyID uint64
+		filterSpan  *heartbeatpb.TableSpan
+	}
+	reads := make([]subscriptionRead, 0, len(stat.subs))
+	for _, sub := range stat.subs {
+		subscriptionStat := e.dispatcherStates.n[sub.subID]
+		if dataRange.StartTs < subscriptionStat.checkpointTs {
+			log.Panic("should not happen",
+				zap.Any("dispatcherID", dispatcherID),
+				zap.Uint64("subID", uint64(sub.subID)),
+				zap.Uint64("checkpointTs", subscriptionStat.checkpointTs),
+				zap.Uint64("startTs", dataRange.StartTs))
+		}
+		// only the events in the read span are returned if the subscription is shared by a sub span
+		var filterSpan *heartbeatpb.TableSpan
+		if !sub.readSpan.Equal(subscriptionStat.tableSpan) {
+			filterSpan = sub.readSpan
+		}
+		reads = append(reads, subscriptionRead{
+			db:          e.dbs[subscriptionStat.chIndex],
+			uniqueKeyID: subscriptionStat.uniqueKeyID,
+			filterSpan:  filterSpan,
+		})
 	}
-	db := e.dbs[subscriptionStat.chIndex]
 	e.dispatcherStates.RUnlock()
 
-	// convert range before pass it to pebble: (startTs, endTs] is equal to [startTs + 1, endTs + 1)
-	start := EncodeKeyPrefix(subscriptionStat.uniqueKeyID, stat.tableSpan.TableID, dataRange.StartTs+1)
-	end := EncodeKeyPrefix(subscriptionStat.uniqueKeyID, stat.tableSpan.TableID, dataRange.EndTs+1)
-	// TODO: optimize read performance
-	iter, err := db.NewIter(&pebble.IterOptions{
-		LowerBound: start,
-		UpperBound: end,
-	})
-	if err != nil {
-		return nil, err
+	iters := make([]*eventStoreIter, 0, len(reads))
+	for _, read := range reads {
+		// convert range before pass it to pebble: (startTs, endTs] is equal to [startTs + 1, endTs + 1)
+		start := EncodeKeyPrefix(read.uniqueKeyID, stat.tableSpan.TableID, dataRange.StartTs+1)
+		end := EncodeKeyPrefix(read.uniqueKeyID, stat.tableSpan.TableID, dataRange.EndTs+1)
+		// TODO: optimize read performance
+		iter, err := read.db.NewIter(&pebble.IterOptions{
+			LowerBound: start,
+			UpperBound: end,
+		})
+		if err != nil {
+			for _, iter := range iters {
+				_, _ = iter.Close()
+			}
+			return nil, err
+		}
+		iter.First()
+		iters = append(iters, &eventStoreIter{
+			tableID:      stat.tableSpan.TableID,
+			filterSpan:   read.filterSpan,
+			innerIter:    iter,
+			prevStartTs:  0,
+			prevCommitTs: 0,
+			iterMounter:  event.NewMounter(time.Local), // FIXME
+			startTs:      dataRange.StartTs,
+			endTs:        dataRange.EndTs,
+			rowCount:     0,
+			decoder:      e.decoder,
+		})
 	}
-	iter.First()
 
 	metrics.EventStoreScanRequestsCount.Inc()
 
-	return &eventStoreIter{
-		tableID:      stat.tableSpan.TableID,
-		innerIter:    iter,
-		prevStartTs:  0,
-		prevCommitTs: 0,
-		iterMounter:  event.NewMounter(time.Local), // FIXME
-		startTs:      dataRange.StartTs,
-		endTs:        dataRange.EndTs,
-		rowCount:     0,
-		decoder:      e.decoder,
-	}, nil
+	if len(iters) == 1 {
+		return iters[0], nil
+	}
+	return newMergedEventIter(iters), nil
 }
 
 func (e *eventStore) updateMetrics(ctx context.Context) error {
@@ -663,6 +959,7 @@ func (e *eventStore) updateMetrics(ctx context.Context) error {
 				metrics.EventStoreDispatcherWatermarkLagHist.Observe(float64(watermarkLag))
 			}
 			e.dispatcherStates.RUnlock()
+			e.checkDiskQuota(pebbleutil.UpdateMetrics("event_store", e.dbs...))
 
 			if minResolvedTs == 0 {
 				continue
@@ -674,6 +971,42 @@ func (e *eventStore) updateMetrics(ctx context.Context) error {
 	}
 }
 
+// checkDiskQuota pauses the writes if the disk usage exceeds the quota,
+// and resumes them after the usage falls below it.
+func (e *eventStore) checkDiskQuota(diskUsage uint64) {
+	exceeded := e.diskQuota > 0 && diskUsage > e.diskQuota
+	if e.diskQuotaExceeded.Swap(exceeded) == exceeded {
+		return
+	}
+	if exceeded {
+		log.Warn("the disk usage of the event store exceeds the quota, pause writing",
+			zap.Uint64("usage", diskUsage),
+			zap.Uint64("quota", e.diskQuota))
+	} else {
+		log.Info("the disk usage of the event store falls below the quota, resume writing",
+			zap.Uint64("usage", diskUsage),
+			zap.Uint64("quota", e.diskQuota))
+	}
+}
+
+// waitForDiskQuota blocks until the disk usage falls below the quota or the context is done.
+// The events from the puller are blocked in the channels meanwhile, so the puller stops
+// receiving more events, and the dispatchers can still consume the data in the dbs.
+func (e *eventStore) waitForDiskQuota(ctx context.Context) {
+	if !e.diskQuotaExceeded.Load() {
+		return
+	}
+	ticker := time.NewTicker(diskQuotaCheckInterval)
+	defer ticker.Stop()
+	for e.diskQuotaExceeded.Load() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 type DBBatchEvent struct {
 	batch *pebble.Batch
 
@@ -698,6 +1031,7 @@ func (e *eventStore) batchAndWriteEvents(ctx context.Context, db *pebble.DB, inp
 		for batchEvent := range batchCh {
 			batch := batchEvent.batch
 			if batch != nil && !batch.Empty() {
+				e.waitForDiskQuota(ctx)
 				size := batch.Len()
 				if err := batch.Commit(pebble.NoSync); err != nil {
 					log.Panic("failed to commit pebble batch", zap.Error(err))
@@ -726,17 +1060,24 @@ func (e *eventStore) batchAndWriteEvents(ctx context.Context, db *pebble.DB, inp
 				atomic.StoreUint64(&subscriptionStat.resolvedTs, resolvedTs)
 				for dispatcherID := range subscriptionStat.ids {
 					dispatcherStat := e.dispatcherStates.m[dispatcherID]
-					dispatcherStat.notifier(resolvedTs)
+					dispatcherStat.notifier(e.getDispatcherResolvedTs(dispatcherStat))
 				}
 			}
 			e.dispatcherStates.RUnlock()
 		}
 	}()
 
+	// Note: the encoder is not shared between the write goroutines to avoid the contention.
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		log.Panic("Failed to create zstd encoder", zap.Error(err))
+	}
+	defer encoder.Close()
+
 	addEvent2Batch := func(batch *pebble.Batch, item eventWithState) {
 		key := EncodeKey(item.uniqueID, item.tableID, item.raw)
 		value := item.raw.Encode()
-		compressedValue := e.encoder.EncodeAll(value, nil)
+		compressedValue := encoder.EncodeAll(value, nil)
 		ratio := float64(len(value)) / float64(len(compressedValue))
 		metrics.EventStoreCompressRatio.Set(ratio)
 		if err := batch.Set(key, compressedValue, pebble.NoSync); err != nil {
@@ -830,11 +1171,35 @@ func (e *eventStore) deleteEvents(dbIndex int, uniqueKeyID uint64, tableID int64
 	start := EncodeKeyPrefix(uniqueKeyID, tableID, startTs)
 	end := EncodeKeyPrefix(uniqueKeyID, tableID, endTs)
 
-	return db.DeleteRange(start, end, pebble.NoSync)
+	batch := db.NewBatch()
+	defer batch.Close()
+	if err := batch.DeleteRange(start, end, pebble.NoSync); err != nil {
+		return err
+	}
+	// persist the gc ts together with the deletion, so the deleted range will not be reused after restart.
+	if err := setSubscriptionGCTs(batch, uniqueKeyID, endTs); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.NoSync)
+}
+
+func (e *eventStore) deleteSubscription(dbIndex int, uniqueKeyID uint64) error {
+	return deleteSubscriptionData(e.dbs[dbIndex], uniqueKeyID)
+}
+
+// keyInSpan returns true if the raw kv entry of the event key is in the span,
+// the keys of the span are in memcomparable format while the raw keys are not.
+func keyInSpan(eventKey []byte, span *heartbeatpb.TableSpan) bool {
+	key := common.ToComparableKey(decodeRawKey(eventKey))
+	return common.StartCompare(key, span.StartKey) >= 0 &&
+		common.EndCompare(key, span.EndKey) < 0
 }
 
 type eventStoreIter struct {
-	tableID      common.TableID
+	tableID common.TableID
+	// filterSpan is the span of the dispatcher if it's a sub span of the subscription,
+	// the events out of it are skipped.
+	filterSpan   *heartbeatpb.TableSpan
 	innerIter    *pebble.Iterator
 	prevStartTs  uint64
 	prevCommitTs uint64
@@ -852,6 +1217,9 @@ func (iter *eventStoreIter) Next() (*common.RawKVEntry, bool, error) {
 		log.Panic("iter is nil")
 	}
 
+	for iter.filterSpan != nil && iter.innerIter.Valid() && !keyInSpan(iter.innerIter.Key(), iter.filterSpan) {
+		iter.innerIter.Next()
+	}
 	if !iter.innerIter.Valid() {
 		return nil, false, nil
 	}
@@ -889,3 +1257,97 @@ func (iter *eventStoreIter) Close() (int64, error) {
 	iter.innerIter = nil
 	return iter.rowCount, err
 }
+
+// mergedEventIter merges the events read from the subscriptions which cover the span
+// of a dispatcher together. The events are returned in the same order as in a single
+// subscription, that is ordered by commitTs, startTs and the dml type.
+type mergedEventIter struct {
+	iters []*eventStoreIter
+	// heads are the next events of the iters, nil means the iter is exhausted.
+	heads       []*common.RawKVEntry
+	initialized bool
+
+	prevStartTs  uint64
+	prevCommitTs uint64
+}
+
+func newMergedEventIter(iters []*eventStoreIter) *mergedEventIter {
+	return &mergedEventIter{
+		iters: iters,
+		heads: make([]*common.RawKVEntry, len(iters)),
+	}
+}
+
+func (iter *mergedEventIter) Next() (*common.RawKVEntry, bool, error) {
+	if !iter.initialized {
+		for i := range iter.iters {
+			if err := iter.advance(i); err != nil {
+				return nil, false, err
+			}
+		}
+		iter.initialized = true
+	}
+
+	next := -1
+	for i, head := range iter.heads {
+		if head == nil {
+			continue
+		}
+		if next < 0 || eventLess(head, iter.heads[next]) {
+			next = i
+		}
+	}
+	if next < 0 {
+		return nil, false, nil
+	}
+	rawKV := iter.heads[next]
+	if err := iter.advance(next); err != nil {
+		return nil, false, err
+	}
+	isNewTxn := false
+	if iter.prevCommitTs == 0 || (rawKV.StartTs != iter.prevStartTs || rawKV.CRTs != iter.prevCommitTs) {
+		isNewTxn = true
+	}
+	iter.prevCommitTs = rawKV.CRTs
+	iter.prevStartTs = rawKV.StartTs
+	return rawKV, isNewTxn, nil
+}
+
+// advance reads the next event of the i-th iter into heads.
+func (iter *mergedEventIter) advance(i int) error {
+	rawKV, _, err := iter.iters[i].Next()
+	if err != nil {
+		return err
+	}
+	iter.heads[i] = rawKV
+	return nil
+}
+
+func (iter *mergedEventIter) Close() (int64, error) {
+	var (
+		eventCnt int64
+		firstErr error
+	)
+	for _, inner := range iter.iters {
+		cnt, err := inner.Close()
+		eventCnt += cnt
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return eventCnt, firstErr
+}
+
+// eventLess returns true if lhs is before rhs in the event keys of a subscription.
+func eventLess(lhs, rhs *common.RawKVEntry) bool {
+	if lhs.CRTs != rhs.CRTs {
+		return lhs.CRTs < rhs.CRTs
+	}
+	if lhs.StartTs != rhs.StartTs {
+		return lhs.StartTs < rhs.StartTs
+	}
+	if getDMLOrder(lhs) != getDMLOrder(rhs) {
+		return getDMLOrder(lhs) < getDMLOrder(rhs)
+	}
+	return bytes.Compare(lhs.Key, rhs.Key) < 0
+}
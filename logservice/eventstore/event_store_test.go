@@ -0,0 +1,365 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/logservice/logpuller"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStoreIterFilterSubSpan(t *testing.T) {
+	db, err := pebble.Open(t.TempDir(), &pebble.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	decoder, err := zstd.NewReader(nil)
+	require.NoError(t, err)
+
+	uniqueID, tableID := uint64(1), int64(100)
+	for i, key := range []string{"a", "c", "e", "g"} {
+		raw := &common.RawKVEntry{
+			OpType:  common.OpTypePut,
+			Key:     []byte(key),
+			Value:   []byte("value"),
+			StartTs: uint64(10 + i),
+			CRTs:    uint64(11 + i),
+		}
+		value := encoder.EncodeAll(raw.Encode(), nil)
+		require.NoError(t, db.Set(EncodeKey(uniqueID, tableID, raw), value, pebble.NoSync))
+	}
+
+	read := func(filterSpan *heartbeatpb.TableSpan) []string {
+		innerIter, err := db.NewIter(&pebble.IterOptions{
+			LowerBound: EncodeKeyPrefix(uniqueID, tableID, 0),
+			UpperBound: EncodeKeyPrefix(uniqueID, tableID, 100),
+		})
+		require.NoError(t, err)
+		innerIter.First()
+		iter := &eventStoreIter{
+			tableID:    tableID,
+			filterSpan: filterSpan,
+			innerIter:  innerIter,
+			decoder:    decoder,
+		}
+		var keys []string
+		for {
+			raw, _, err := iter.Next()
+			require.NoError(t, err)
+			if raw == nil {
+				break
+			}
+			keys = append(keys, string(raw.Key))
+		}
+		_, err = iter.Close()
+		require.NoError(t, err)
+		return keys
+	}
+
+	require.Equal(t, []string{"a", "c", "e", "g"}, read(nil))
+	// the span keys are in memcomparable format
+	subSpan := &heartbeatpb.TableSpan{
+		TableID:  tableID,
+		StartKey: common.ToComparableKey([]byte("b")),
+		EndKey:   common.ToComparableKey([]byte("e")),
+	}
+	require.Equal(t, []string{"c"}, read(subSpan))
+	subSpan.EndKey = nil
+	require.Equal(t, []string{"c", "e", "g"}, read(subSpan))
+
+	parent := &heartbeatpb.TableSpan{
+		TableID:  tableID,
+		StartKey: common.ToComparableKey([]byte("a")),
+		EndKey:   common.ToComparableKey([]byte("z")),
+	}
+	require.False(t, common.IsSubSpan(subSpan, parent))
+	subSpan.EndKey = common.ToComparableKey([]byte("z"))
+	require.True(t, common.IsSubSpan(subSpan, parent))
+	// the spans of different tables never contain each other
+	subSpan.TableID = tableID + 1
+	require.False(t, common.IsSubSpan(subSpan, parent))
+}
+
+// newTestEventStore opens an event store with a single db in dir,
+// the puller is never run so no event is pulled from upstream.
+func newTestEventStore(t *testing.T, dir string) *eventStore {
+	decoder, err := zstd.NewReader(nil)
+	require.NoError(t, err)
+	store := &eventStore{
+		gcManager: newGCManager(),
+		decoder:   decoder,
+	}
+	store.dispatcherStates.m = make(map[common.DispatcherID]*dispatcherStat)
+	store.dispatcherStates.n = make(map[logpuller.SubscriptionID]*subscriptionStat)
+	store.dispatcherStates.l = make(map[int64]map[common.DispatcherID]bool)
+	store.dispatcherStates.p = make(map[int64][]*persistedSubscription)
+	db, err := pebble.Open(dir, &pebble.Options{})
+	require.NoError(t, err)
+	store.dbs = append(store.dbs, db)
+	store.eventChs = append(store.eventChs, make(chan eventWithState, 1))
+	store.loadPersistedSubscriptions(0, db)
+
+	client := logpuller.NewSubscriptionClient(
+		logpuller.ClientIDEventStore, &logpuller.SubscriptionClientConfig{}, nil, nil, nil, nil, nil)
+	store.puller = logpuller.NewLogPuller(client, nil, func(
+		context.Context, *common.RawKVEntry, logpuller.SubscriptionID, interface{},
+	) error {
+		return nil
+	})
+	return store
+}
+
+func TestEventStoreReuseDataAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	span := &heartbeatpb.TableSpan{
+		TableID:  100,
+		StartKey: common.ToComparableKey([]byte("a")),
+		EndKey:   common.ToComparableKey([]byte("z")),
+	}
+	notifier := func(uint64) {}
+
+	store := newTestEventStore(t, dir)
+	dispatcherID := common.NewDispatcherID()
+	require.NoError(t, store.RegisterDispatcher(dispatcherID, span, 100, notifier))
+	subStat := store.dispatcherStates.n[store.dispatcherStates.m[dispatcherID].subs[0].subID]
+	uniqueKeyID := subStat.uniqueKeyID
+
+	// write the events as the puller does and advance the resolved ts
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	for _, commitTs := range []uint64{110, 120, 130} {
+		raw := &common.RawKVEntry{
+			OpType:  common.OpTypePut,
+			Key:     []byte("b"),
+			Value:   []byte("value"),
+			StartTs: commitTs - 1,
+			CRTs:    commitTs,
+		}
+		value := encoder.EncodeAll(raw.Encode(), nil)
+		require.NoError(t, store.dbs[0].Set(EncodeKey(uniqueKeyID, span.TableID, raw), value, pebble.NoSync))
+	}
+	subStat.resolvedTs = 150
+	subStat.maxEventCommitTs = 130
+	store.persistSubscriptionMetas()
+	require.NoError(t, store.dbs[0].Close())
+
+	// restart the store, the subscription is restored from disk
+	store = newTestEventStore(t, dir)
+	defer store.dbs[0].Close()
+	require.Len(t, store.dispatcherStates.p[span.TableID], 1)
+
+	// startTs is out of the range of the persisted data, the subscription can't be reused
+	require.Nil(t, store.takePersistedSubscription(span, 160))
+	require.Len(t, store.dispatcherStates.p[span.TableID], 1)
+
+	dispatcherID = common.NewDispatcherID()
+	require.NoError(t, store.RegisterDispatcher(dispatcherID, span, 115, notifier))
+	require.Empty(t, store.dispatcherStates.p)
+	subStat = store.dispatcherStates.n[store.dispatcherStates.m[dispatcherID].subs[0].subID]
+	require.Equal(t, uniqueKeyID, subStat.uniqueKeyID)
+	require.Equal(t, uint64(100), subStat.checkpointTs)
+	require.Equal(t, uint64(150), subStat.resolvedTs)
+	require.Equal(t, uint64(130), subStat.maxEventCommitTs)
+	require.Equal(t, uint64(130), store.GetDispatcherDMLEventState(dispatcherID).MaxEventCommitTs)
+
+	// the data persisted before restart can be read
+	iter, err := store.GetIterator(dispatcherID, common.DataRange{
+		Span:    span,
+		StartTs: 115,
+		EndTs:   150,
+	})
+	require.NoError(t, err)
+	var commitTsList []uint64
+	for {
+		raw, _, err := iter.Next()
+		require.NoError(t, err)
+		if raw == nil {
+			break
+		}
+		commitTsList = append(commitTsList, raw.CRTs)
+	}
+	_, err = iter.Close()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{120, 130}, commitTsList)
+}
+
+func TestEventStoreReadOverlappingSubscriptions(t *testing.T) {
+	store := newTestEventStore(t, t.TempDir())
+	defer store.dbs[0].Close()
+	tableID := int64(100)
+	newSpan := func(start, end string) *heartbeatpb.TableSpan {
+		return &heartbeatpb.TableSpan{
+			TableID:  tableID,
+			StartKey: common.ToComparableKey([]byte(start)),
+			EndKey:   common.ToComparableKey([]byte(end)),
+		}
+	}
+	notifier := func(uint64) {}
+
+	dispatcherID1, dispatcherID2 := common.NewDispatcherID(), common.NewDispatcherID()
+	require.NoError(t, store.RegisterDispatcher(dispatcherID1, newSpan("a", "h"), 100, notifier))
+	require.NoError(t, store.RegisterDispatcher(dispatcherID2, newSpan("f", "z"), 100, notifier))
+	subStat1 := store.dispatcherStates.n[store.dispatcherStates.m[dispatcherID1].subs[0].subID]
+	subStat2 := store.dispatcherStates.n[store.dispatcherStates.m[dispatcherID2].subs[0].subID]
+
+	// the span is covered by the two subscriptions together
+	dispatcherID3 := common.NewDispatcherID()
+	require.NoError(t, store.RegisterDispatcher(dispatcherID3, newSpan("c", "p"), 100, notifier))
+	require.Len(t, store.dispatcherStates.n, 2)
+	subs := store.dispatcherStates.m[dispatcherID3].subs
+	require.Len(t, subs, 2)
+	require.Equal(t, newSpan("c", "h"), subs[0].readSpan)
+	require.Equal(t, newSpan("h", "p"), subs[1].readSpan)
+	require.True(t, subStat1.ids[dispatcherID3])
+	require.True(t, subStat2.ids[dispatcherID3])
+
+	// the events in the overlapping part are written to both subscriptions
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	write := func(subStat *subscriptionStat, key string, commitTs uint64) {
+		raw := &common.RawKVEntry{
+			OpType:  common.OpTypePut,
+			Key:     []byte(key),
+			Value:   []byte("value"),
+			StartTs: commitTs - 1,
+			CRTs:    commitTs,
+		}
+		value := encoder.EncodeAll(raw.Encode(), nil)
+		require.NoError(t, store.dbs[0].Set(EncodeKey(subStat.uniqueKeyID, tableID, raw), value, pebble.NoSync))
+	}
+	write(subStat1, "b", 110)
+	write(subStat1, "d", 120)
+	write(subStat1, "g", 130)
+	write(subStat2, "g", 130)
+	write(subStat2, "k", 110)
+	write(subStat2, "k", 140)
+	write(subStat2, "q", 150)
+	subStat1.resolvedTs, subStat1.maxEventCommitTs = 200, 130
+	subStat2.resolvedTs, subStat2.maxEventCommitTs = 180, 150
+
+	require.Equal(t, uint64(180), store.getDispatcherResolvedTs(store.dispatcherStates.m[dispatcherID3]))
+	require.Equal(t, uint64(150), store.GetDispatcherDMLEventState(dispatcherID3).MaxEventCommitTs)
+
+	iter, err := store.GetIterator(dispatcherID3, common.DataRange{
+		Span:    newSpan("c", "p"),
+		StartTs: 100,
+		EndTs:   180,
+	})
+	require.NoError(t, err)
+	var events []string
+	for {
+		raw, isNewTxn, err := iter.Next()
+		require.NoError(t, err)
+		if raw == nil {
+			break
+		}
+		require.True(t, isNewTxn)
+		events = append(events, fmt.Sprintf("%s@%d", raw.Key, raw.CRTs))
+	}
+	eventCnt, err := iter.Close()
+	require.NoError(t, err)
+	require.Equal(t, []string{"k@110", "d@120", "g@130", "k@140"}, events)
+	require.Equal(t, int64(4), eventCnt)
+
+	// the subscriptions are kept until all the dispatchers depend on them are removed
+	require.NoError(t, store.UnregisterDispatcher(dispatcherID1))
+	require.Len(t, store.dispatcherStates.n, 2)
+	require.NoError(t, store.UnregisterDispatcher(dispatcherID3))
+	require.Len(t, store.dispatcherStates.n, 1)
+	require.NotContains(t, subStat2.ids, dispatcherID3)
+}
+
+func TestGCManagerRetryDeleteSubscription(t *testing.T) {
+	gc := newGCManager()
+	gc.addGCSubscriptionItem(0, 1)
+	gc.addGCSubscriptionItem(1, 2)
+
+	deleted := make(map[uint64]bool)
+	failed := true
+	deleteSubscription := func(dbIndex int, uniqueKeyID uint64) error {
+		if uniqueKeyID == 2 && failed {
+			return errors.New("injected error")
+		}
+		deleted[uniqueKeyID] = true
+		return nil
+	}
+	// the failed item is added back and retried in the next round
+	gc.deleteSubscriptions(deleteSubscription)
+	require.Equal(t, map[uint64]bool{1: true}, deleted)
+	require.Len(t, gc.subscriptions, 1)
+
+	failed = false
+	gc.deleteSubscriptions(deleteSubscription)
+	require.Equal(t, map[uint64]bool{1: true, 2: true}, deleted)
+	require.Empty(t, gc.subscriptions)
+}
+
+func TestRemoveExtraDBs(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), dataDir)
+	// the path doesn't exist on the first startup
+	require.NoError(t, removeExtraDBs(dbPath, 2))
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, os.MkdirAll(filepath.Join(dbPath, fmt.Sprintf("%d", i)), 0o755))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dbPath, "other"), 0o755))
+
+	require.NoError(t, removeExtraDBs(dbPath, 2))
+	entries, err := os.ReadDir(dbPath)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	require.ElementsMatch(t, []string{"0", "1", "other"}, names)
+}
+
+func TestEventStoreDiskQuota(t *testing.T) {
+	// the writes are never paused without the quota.
+	store := &eventStore{}
+	store.checkDiskQuota(math.MaxUint64)
+	require.False(t, store.diskQuotaExceeded.Load())
+
+	store = &eventStore{diskQuota: 100}
+	store.checkDiskQuota(100)
+	require.False(t, store.diskQuotaExceeded.Load())
+	store.checkDiskQuota(101)
+	require.True(t, store.diskQuotaExceeded.Load())
+
+	done := make(chan struct{})
+	go func() {
+		store.waitForDiskQuota(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+		require.Fail(t, "the writes should be paused")
+	case <-time.After(3 * diskQuotaCheckInterval):
+	}
+
+	// the writes are resumed after the usage falls below the quota.
+	store.checkDiskQuota(50)
+	require.False(t, store.diskQuotaExceeded.Load())
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the writes should be resumed")
+	}
+
+	// the writes are not blocked after the context is done.
+	store.checkDiskQuota(200)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	store.waitForDiskQuota(ctx)
+}
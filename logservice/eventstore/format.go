@@ -74,6 +74,11 @@ func EncodeKey(uniqueID uint64, tableID int64, event *common.RawKVEntry) []byte
 	return append(buf, event.Key...)
 }
 
+// decodeRawKey returns the key of the raw kv entry in the key encoded by EncodeKey.
+func decodeRawKey(key []byte) []byte {
+	return key[8+8+8+8+2:]
+}
+
 // getDMLOrder returns the order of the dml types: delete<update<insert
 func getDMLOrder(rowKV *common.RawKVEntry) uint16 {
 	if rowKV.OpType == common.OpTypeDelete {
@@ -83,3 +88,38 @@ func getDMLOrder(rowKV *common.RawKVEntry) uint16 {
 	}
 	return typeInsert
 }
+
+// Metadata keys start with metaKeyPrefix, which is larger than the first byte of all event keys,
+// because event keys start with the big endian encoded unique id of the subscription.
+// Format:
+//
+//	{metaKeyPrefix}{subscriptionMetaKeyType}{uniqueID} -> subscription meta
+//	{metaKeyPrefix}{subscriptionGCTsKeyType}{uniqueID} -> gc ts of the subscription
+const (
+	metaKeyPrefix byte = 0xff
+
+	subscriptionMetaKeyType byte = 'm'
+	subscriptionGCTsKeyType byte = 'g'
+)
+
+func encodeMetaKey(keyType byte, uniqueID uint64) []byte {
+	buf := make([]byte, 2+8)
+	buf[0] = metaKeyPrefix
+	buf[1] = keyType
+	binary.BigEndian.PutUint64(buf[2:], uniqueID)
+	return buf
+}
+
+func decodeMetaKey(key []byte) (keyType byte, uniqueID uint64) {
+	if len(key) != 2+8 || key[0] != metaKeyPrefix {
+		log.Panic("invalid meta key", zap.ByteString("key", key))
+	}
+	return key[1], binary.BigEndian.Uint64(key[2:])
+}
+
+// encodeUniqueIDPrefix encodes the prefix of all the event keys of the subscription with uniqueID.
+func encodeUniqueIDPrefix(uniqueID uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uniqueID)
+	return buf
+}
@@ -21,9 +21,16 @@ type gcRangeItem struct {
 	endTs   uint64
 }
 
+// gcSubscriptionItem is a removed subscription whose events and metas should be deleted.
+type gcSubscriptionItem struct {
+	dbIndex     int
+	uniqueKeyID uint64
+}
+
 type gcManager struct {
-	mu     sync.Mutex
-	ranges []gcRangeItem
+	mu            sync.Mutex
+	ranges        []gcRangeItem
+	subscriptions []gcSubscriptionItem
 }
 
 func newGCManager() *gcManager {
@@ -51,15 +58,36 @@ func (d *gcManager) fetchAllGCItems() []gcRangeItem {
 	return ranges
 }
 
+// add an item to delete all the data of the removed subscription with `uniqueKeyID`.
+func (d *gcManager) addGCSubscriptionItem(dbIndex int, uniqueKeyID uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscriptions = append(d.subscriptions, gcSubscriptionItem{
+		dbIndex:     dbIndex,
+		uniqueKeyID: uniqueKeyID,
+	})
+}
+
+func (d *gcManager) fetchAllGCSubscriptionItems() []gcSubscriptionItem {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	subscriptions := d.subscriptions
+	d.subscriptions = nil
+	return subscriptions
+}
+
 type deleteFunc func(dbIndex int, uniqueKeyID uint64, tableID int64, startCommitTS uint64, endCommitTS uint64) error
 
-func (d *gcManager) run(ctx context.Context, deleteDataRange deleteFunc) error {
+type deleteSubscriptionFunc func(dbIndex int, uniqueKeyID uint64) error
+
+func (d *gcManager) run(ctx context.Context, deleteDataRange deleteFunc, deleteSubscription deleteSubscriptionFunc) error {
 	ticker := time.NewTicker(20 * time.Millisecond)
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
 		case <-ticker.C:
+			d.deleteSubscriptions(deleteSubscription)
 			ranges := d.fetchAllGCItems()
 			if len(ranges) == 0 {
 				continue
@@ -77,3 +105,17 @@ func (d *gcManager) run(ctx context.Context, deleteDataRange deleteFunc) error {
 		}
 	}
 }
+
+// deleteSubscriptions deletes the data of the removed subscriptions,
+// the failed ones are added back and retried in the next round.
+func (d *gcManager) deleteSubscriptions(deleteSubscription deleteSubscriptionFunc) {
+	for _, item := range d.fetchAllGCSubscriptionItems() {
+		if err := deleteSubscription(item.dbIndex, item.uniqueKeyID); err != nil {
+			log.Warn("delete subscription data failed, retry later",
+				zap.Int("dbIndex", item.dbIndex),
+				zap.Uint64("uniqueKeyID", item.uniqueKeyID),
+				zap.Error(err))
+			d.addGCSubscriptionItem(item.dbIndex, item.uniqueKeyID)
+		}
+	}
+}
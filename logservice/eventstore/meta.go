@@ -0,0 +1,171 @@
+package eventstore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"go.uber.org/zap"
+)
+
+// subscriptionMeta is the metadata of a subscription persisted in the event store.
+// The data of the subscription in the range (CheckpointTs, ResolvedTs] is complete on disk,
+// so it can be reused after restart.
+type subscriptionMeta struct {
+	TableID          int64  `json:"table_id"`
+	StartKey         []byte `json:"start_key"`
+	EndKey           []byte `json:"end_key"`
+	CheckpointTs     uint64 `json:"checkpoint_ts"`
+	ResolvedTs       uint64 `json:"resolved_ts"`
+	MaxEventCommitTs uint64 `json:"max_event_commit_ts"`
+}
+
+func (m *subscriptionMeta) span() *heartbeatpb.TableSpan {
+	return &heartbeatpb.TableSpan{
+		TableID:  m.TableID,
+		StartKey: m.StartKey,
+		EndKey:   m.EndKey,
+	}
+}
+
+// persistedSubscription is a subscription restored from disk which is not reused by any dispatcher yet.
+type persistedSubscription struct {
+	chIndex     int
+	uniqueKeyID uint64
+	meta        subscriptionMeta
+}
+
+// writeSubscriptionMetas writes the metas of subscriptions identified by unique key id to db.
+func writeSubscriptionMetas(db *pebble.DB, metas map[uint64]*subscriptionMeta) error {
+	if len(metas) == 0 {
+		return nil
+	}
+	batch := db.NewBatch()
+	defer batch.Close()
+	for uniqueKeyID, meta := range metas {
+		value, err := json.Marshal(meta)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := batch.Set(encodeMetaKey(subscriptionMetaKeyType, uniqueKeyID), value, pebble.NoSync); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return batch.Commit(pebble.NoSync)
+}
+
+// readSubscriptionMetas reads all the subscription metas in db.
+// The checkpoint ts of the meta is advanced to the gc ts of the subscription if needed,
+// because the gc ts is written together with the deletion of the data.
+func readSubscriptionMetas(db *pebble.DB) (map[uint64]*subscriptionMeta, error) {
+	iter, err := db.NewIter(&pebble.IterOptions{
+		LowerBound: []byte{metaKeyPrefix},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer iter.Close()
+
+	metas := make(map[uint64]*subscriptionMeta)
+	gcTsMap := make(map[uint64]uint64)
+	for iter.First(); iter.Valid(); iter.Next() {
+		keyType, uniqueKeyID := decodeMetaKey(iter.Key())
+		switch keyType {
+		case subscriptionMetaKeyType:
+			meta := &subscriptionMeta{}
+			if err := json.Unmarshal(iter.Value(), meta); err != nil {
+				return nil, errors.Trace(err)
+			}
+			metas[uniqueKeyID] = meta
+		case subscriptionGCTsKeyType:
+			gcTsMap[uniqueKeyID] = binary.BigEndian.Uint64(iter.Value())
+		default:
+			log.Panic("unknown meta key type", zap.Uint8("keyType", keyType))
+		}
+	}
+	for uniqueKeyID, gcTs := range gcTsMap {
+		meta, ok := metas[uniqueKeyID]
+		if !ok {
+			continue
+		}
+		if gcTs > meta.CheckpointTs {
+			meta.CheckpointTs = gcTs
+		}
+	}
+	return metas, nil
+}
+
+// setSubscriptionGCTs sets the gc ts of the subscription in the batch,
+// the data of the subscription <= gcTs is deleted and can't be reused.
+func setSubscriptionGCTs(batch *pebble.Batch, uniqueKeyID uint64, gcTs uint64) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, gcTs)
+	return batch.Set(encodeMetaKey(subscriptionGCTsKeyType, uniqueKeyID), value, pebble.NoSync)
+}
+
+// deleteSubscriptionData deletes all the events and metadata of the subscription.
+func deleteSubscriptionData(db *pebble.DB, uniqueKeyID uint64) error {
+	batch := db.NewBatch()
+	defer batch.Close()
+	if err := batch.DeleteRange(
+		encodeUniqueIDPrefix(uniqueKeyID), encodeUniqueIDPrefix(uniqueKeyID+1), pebble.NoSync); err != nil {
+		return errors.Trace(err)
+	}
+	if err := batch.Delete(encodeMetaKey(subscriptionMetaKeyType, uniqueKeyID), pebble.NoSync); err != nil {
+		return errors.Trace(err)
+	}
+	if err := batch.Delete(encodeMetaKey(subscriptionGCTsKeyType, uniqueKeyID), pebble.NoSync); err != nil {
+		return errors.Trace(err)
+	}
+	return batch.Commit(pebble.NoSync)
+}
+
+// deleteOrphanData deletes the events and gc ts which don't belong to any subscription in metas.
+// It happens when the process exits before the meta of a new subscription is persisted,
+// or the data is written by an old version which doesn't persist metas.
+func deleteOrphanData(db *pebble.DB, metas map[uint64]*subscriptionMeta) error {
+	uniqueKeyIDs := make([]uint64, 0, len(metas))
+	for uniqueKeyID := range metas {
+		uniqueKeyIDs = append(uniqueKeyIDs, uniqueKeyID)
+	}
+	sort.Slice(uniqueKeyIDs, func(i, j int) bool {
+		return uniqueKeyIDs[i] < uniqueKeyIDs[j]
+	})
+
+	batch := db.NewBatch()
+	defer batch.Close()
+	// delete the events in the gaps between the unique key ids
+	start := encodeUniqueIDPrefix(0)
+	for _, uniqueKeyID := range uniqueKeyIDs {
+		end := encodeUniqueIDPrefix(uniqueKeyID)
+		if err := batch.DeleteRange(start, end, pebble.NoSync); err != nil {
+			return errors.Trace(err)
+		}
+		start = encodeUniqueIDPrefix(uniqueKeyID + 1)
+	}
+	if err := batch.DeleteRange(start, []byte{metaKeyPrefix}, pebble.NoSync); err != nil {
+		return errors.Trace(err)
+	}
+	// delete the gc ts without meta
+	iter, err := db.NewIter(&pebble.IterOptions{
+		LowerBound: []byte{metaKeyPrefix},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer iter.Close()
+	for iter.First(); iter.Valid(); iter.Next() {
+		keyType, uniqueKeyID := decodeMetaKey(iter.Key())
+		if _, ok := metas[uniqueKeyID]; ok || keyType != subscriptionGCTsKeyType {
+			continue
+		}
+		if err := batch.Delete(iter.Key(), pebble.NoSync); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return batch.Commit(pebble.NoSync)
+}
@@ -0,0 +1,83 @@
+package eventstore
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistSubscriptionMetas(t *testing.T) {
+	db, err := pebble.Open(t.TempDir(), &pebble.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	writeEvent := func(uniqueID uint64, tableID int64, commitTs uint64) []byte {
+		raw := &common.RawKVEntry{
+			OpType:  common.OpTypePut,
+			Key:     []byte("key"),
+			Value:   []byte("value"),
+			StartTs: commitTs - 1,
+			CRTs:    commitTs,
+		}
+		key := EncodeKey(uniqueID, tableID, raw)
+		require.NoError(t, db.Set(key, raw.Encode(), pebble.NoSync))
+		return key
+	}
+	exists := func(key []byte) bool {
+		_, closer, err := db.Get(key)
+		if err == pebble.ErrNotFound {
+			return false
+		}
+		require.NoError(t, err)
+		require.NoError(t, closer.Close())
+		return true
+	}
+
+	key1 := writeEvent(1, 100, 10)
+	key2 := writeEvent(2, 200, 20)
+	key3 := writeEvent(3, 300, 30)
+	metas := map[uint64]*subscriptionMeta{
+		2: {
+			TableID:          200,
+			StartKey:         []byte("a"),
+			EndKey:           []byte("b"),
+			CheckpointTs:     5,
+			ResolvedTs:       25,
+			MaxEventCommitTs: 20,
+		},
+	}
+	require.NoError(t, writeSubscriptionMetas(db, metas))
+
+	// the gc ts is larger than the checkpoint ts in the meta
+	batch := db.NewBatch()
+	require.NoError(t, setSubscriptionGCTs(batch, 2, 8))
+	// the gc ts of a removed subscription
+	require.NoError(t, setSubscriptionGCTs(batch, 3, 8))
+	require.NoError(t, batch.Commit(pebble.NoSync))
+
+	restored, err := readSubscriptionMetas(db)
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+	require.Equal(t, int64(200), restored[2].TableID)
+	require.True(t, restored[2].span().Equal(metas[2].span()))
+	require.Equal(t, uint64(8), restored[2].CheckpointTs)
+	require.Equal(t, uint64(25), restored[2].ResolvedTs)
+	require.Equal(t, uint64(20), restored[2].MaxEventCommitTs)
+
+	// the data without meta is deleted
+	require.NoError(t, deleteOrphanData(db, restored))
+	require.False(t, exists(key1))
+	require.True(t, exists(key2))
+	require.False(t, exists(key3))
+	require.True(t, exists(encodeMetaKey(subscriptionGCTsKeyType, 2)))
+	require.False(t, exists(encodeMetaKey(subscriptionGCTsKeyType, 3)))
+
+	// all the data of the subscription is deleted
+	require.NoError(t, deleteSubscriptionData(db, 2))
+	require.False(t, exists(key2))
+	restored, err = readSubscriptionMetas(db)
+	require.NoError(t, err)
+	require.Len(t, restored, 0)
+}
@@ -280,13 +280,14 @@ func (w *changeEventProcessor) assembleRowEvent(regionID uint64, entry *cdcpb.Ev
 		return &common.RawKVEntry{}, cerror.ErrUnknownKVEventType.GenWithStackByArgs(entry.GetOpType(), entry)
 	}
 	return &common.RawKVEntry{
-		OpType:   opType,
-		Key:      entry.Key,
-		Value:    entry.GetValue(),
-		StartTs:  entry.StartTs,
-		CRTs:     entry.CommitTs,
-		RegionID: regionID,
-		OldValue: entry.GetOldValue(),
+		OpType:    opType,
+		Key:       entry.Key,
+		Value:     entry.GetValue(),
+		StartTs:   entry.StartTs,
+		CRTs:      entry.CommitTs,
+		RegionID:  regionID,
+		OldValue:  entry.GetOldValue(),
+		TxnSource: entry.GetTxnSource(),
 	}, nil
 }
 
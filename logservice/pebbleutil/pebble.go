@@ -0,0 +1,92 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebbleutil
+
+import (
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/metrics"
+)
+
+const (
+	levelCount     = 7
+	indexBlockSize = 256 << 10 // 256 KB
+	targetFileSize = 8 << 20   // 8 MB
+)
+
+// NewOptions returns the pebble options built from the config.
+func NewOptions(cfg *config.PebbleConfig) *pebble.Options {
+	opts := &pebble.Options{
+		DisableWAL:   cfg.DisableWAL,
+		MemTableSize: cfg.MemTableSize,
+	}
+	opts.Levels = make([]pebble.LevelOptions, levelCount)
+	for i := 0; i < len(opts.Levels); i++ {
+		l := &opts.Levels[i]
+		l.BlockSize = cfg.BlockSize
+		l.IndexBlockSize = indexBlockSize
+		if cfg.BloomFilterBitsPerKey > 0 {
+			l.FilterPolicy = bloom.FilterPolicy(cfg.BloomFilterBitsPerKey)
+			l.FilterType = pebble.TableFilter
+		}
+		l.TargetFileSize = targetFileSize
+		l.Compression = toCompression(cfg.GetCompression(i))
+		l.EnsureDefaults()
+	}
+	return opts
+}
+
+func toCompression(compression string) pebble.Compression {
+	switch compression {
+	case config.PebbleCompressionNone:
+		return pebble.NoCompression
+	case config.PebbleCompressionZstd:
+		return pebble.ZstdCompression
+	default:
+		return pebble.SnappyCompression
+	}
+}
+
+// UpdateMetrics updates the metrics of the dbs of the store,
+// and returns the disk usage of them.
+func UpdateMetrics(store string, dbs ...*pebble.DB) uint64 {
+	var (
+		readAmp    int
+		compaction int64
+		inProgress int64
+		debt       uint64
+		diskUsage  uint64
+		total      pebble.LevelMetrics
+	)
+	for _, db := range dbs {
+		m := db.Metrics()
+		if amp := m.ReadAmp(); amp > readAmp {
+			readAmp = amp
+		}
+		compaction += m.Compact.Count
+		inProgress += m.Compact.NumInProgress
+		debt += m.Compact.EstimatedDebt
+		diskUsage += m.DiskSpaceUsage()
+		levels := m.Total()
+		total.Add(&levels)
+	}
+	metrics.PebbleReadAmplificationGauge.WithLabelValues(store).Set(float64(readAmp))
+	metrics.PebbleWriteAmplificationGauge.WithLabelValues(store).Set(total.WriteAmp())
+	metrics.PebbleCompactionCountGauge.WithLabelValues(store).Set(float64(compaction))
+	metrics.PebbleCompactionInProgressGauge.WithLabelValues(store).Set(float64(inProgress))
+	metrics.PebbleCompactionDebtGauge.WithLabelValues(store).Set(float64(debt))
+	metrics.PebbleDiskUsageGauge.WithLabelValues(store).Set(float64(diskUsage))
+	return diskUsage
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebbleutil
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOptions(t *testing.T) {
+	cfg := config.NewDefaultEventStoreConfig()
+	cfg.Compression = []string{config.PebbleCompressionNone, config.PebbleCompressionSnappy, config.PebbleCompressionZstd}
+	require.NoError(t, cfg.ValidateAndAdjust())
+
+	opts := NewOptions(&cfg.PebbleConfig)
+	require.Equal(t, cfg.MemTableSize, opts.MemTableSize)
+	require.False(t, opts.DisableWAL)
+	require.Len(t, opts.Levels, levelCount)
+	require.Equal(t, pebble.NoCompression, opts.Levels[0].Compression)
+	require.Equal(t, pebble.SnappyCompression, opts.Levels[1].Compression)
+	// the last compression is used for the rest levels
+	for i := 2; i < levelCount; i++ {
+		require.Equal(t, pebble.ZstdCompression, opts.Levels[i].Compression)
+		require.Equal(t, cfg.BlockSize, opts.Levels[i].BlockSize)
+		require.NotNil(t, opts.Levels[i].FilterPolicy)
+	}
+
+	// bloom filters are disabled
+	cfg.BloomFilterBitsPerKey = 0
+	opts = NewOptions(&cfg.PebbleConfig)
+	for _, l := range opts.Levels {
+		require.Nil(t, l.FilterPolicy)
+	}
+
+	// invalid configs
+	cfg.Compression = []string{"lz4"}
+	require.Error(t, cfg.ValidateAndAdjust())
+	cfg.Compression = []string{config.PebbleCompressionZstd}
+	cfg.Count = 0
+	require.Error(t, cfg.ValidateAndAdjust())
+}
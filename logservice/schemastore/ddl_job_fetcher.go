@@ -52,6 +52,7 @@ func newDDLJobFetcher(
 	regionCache *tikv.RegionCache,
 	pdClock pdutil.Clock,
 	kvStorage kv.Storage,
+	credential *security.Credential,
 	startTs uint64,
 	writeDDLEvent func(ddlEvent DDLJobWithCommitTs),
 	advanceResolvedTs func(resolvedTS uint64),
@@ -68,7 +69,7 @@ func newDDLJobFetcher(
 		regionCache,
 		pdClock,
 		txnutil.NewLockerResolver(kvStorage.(tikv.Storage)),
-		&security.Credential{},
+		credential,
 	)
 
 	ddlJobFetcher := &ddlJobFetcher{
@@ -23,11 +23,12 @@ import (
 	"time"
 
 	"github.com/cockroachdb/pebble"
-	"github.com/cockroachdb/pebble/bloom"
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/logservice/pebbleutil"
 	"github.com/pingcap/ticdc/pkg/common"
 	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/filter"
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/meta/model"
@@ -48,6 +49,8 @@ type persistentStorage struct {
 	kvStorage kv.Storage
 
 	db *pebble.DB
+	// dbConfig is the config to open the db.
+	dbConfig *config.PebbleConfig
 
 	mu sync.RWMutex
 
@@ -97,23 +100,8 @@ func exists(path string) bool {
 	return true
 }
 
-func openDB(dbPath string) *pebble.DB {
-	opts := &pebble.Options{
-		DisableWAL:   true,
-		MemTableSize: 8 << 20,
-	}
-	opts.Levels = make([]pebble.LevelOptions, 7)
-	for i := 0; i < len(opts.Levels); i++ {
-		l := &opts.Levels[i]
-		l.BlockSize = 64 << 10       // 64 KB
-		l.IndexBlockSize = 256 << 10 // 256 KB
-		l.FilterPolicy = bloom.FilterPolicy(10)
-		l.FilterType = pebble.TableFilter
-		l.TargetFileSize = 8 << 20 // 8 MB
-		l.Compression = pebble.SnappyCompression
-		l.EnsureDefaults()
-	}
-	db, err := pebble.Open(dbPath, opts)
+func openDB(dbPath string, cfg *config.PebbleConfig) *pebble.DB {
+	db, err := pebble.Open(dbPath, pebbleutil.NewOptions(cfg))
 	if err != nil {
 		log.Fatal("open db failed", zap.Error(err))
 	}
@@ -123,6 +111,7 @@ func openDB(dbPath string) *pebble.DB {
 func newPersistentStorage(
 	ctx context.Context,
 	root string,
+	cfg *config.SchemaStoreConfig,
 	pdCli pd.Client,
 	storage kv.Storage,
 ) *persistentStorage {
@@ -140,6 +129,7 @@ func newPersistentStorage(
 	dataStorage := &persistentStorage{
 		pdCli:                  pdCli,
 		kvStorage:              storage,
+		dbConfig:               &cfg.PebbleConfig,
 		tableMap:               make(map[int64]*BasicTableInfo),
 		partitionMap:           make(map[int64]BasicPartitionInfo),
 		databaseMap:            make(map[int64]*BasicDatabaseInfo),
@@ -152,7 +142,7 @@ func newPersistentStorage(
 	isDataReusable := false
 	if exists(dbPath) {
 		isDataReusable = true
-		db := openDB(dbPath)
+		db := openDB(dbPath, dataStorage.dbConfig)
 		// check whether the data on disk is reusable
 		gcTs, err := readGcTs(db)
 		if err != nil {
@@ -198,7 +188,7 @@ func (p *persistentStorage) initializeFromKVStorage(dbPath string, storage kv.St
 	if err := os.RemoveAll(dbPath); err != nil {
 		log.Fatal("fail to remove path in initializeFromKVStorage")
 	}
-	p.db = openDB(dbPath)
+	p.db = openDB(dbPath, p.dbConfig)
 
 	log.Info("schema store initialize from kv storage begin",
 		zap.Uint64("snapTs", gcTs))
@@ -623,6 +613,7 @@ func (p *persistentStorage) persistUpperBoundPeriodically(ctx context.Context) e
 		case <-ctx.Done():
 			return nil
 		case <-ticker.C:
+			pebbleutil.UpdateMetrics("schema_store", p.db)
 			p.mu.Lock()
 			if !p.upperBoundChanged {
 				log.Warn("schema store upper bound not changed")
@@ -1390,6 +1381,7 @@ func buildDDLEvent(rawEvent *PersistedDDLEvent, tableFilter filter.Filter) commo
 		TableInfo:  wrapTableInfo,
 		FinishedTs: rawEvent.FinishedTs,
 		TiDBOnly:   false,
+		BDRRole:    rawEvent.BDRRole,
 	}
 
 	switch model.ActionType(rawEvent.Type) {
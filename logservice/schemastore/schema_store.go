@@ -9,10 +9,12 @@ import (
 	"github.com/pingcap/ticdc/pkg/common"
 	appcontext "github.com/pingcap/ticdc/pkg/common/context"
 	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/filter"
 	"github.com/pingcap/ticdc/pkg/metrics"
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 	pd "github.com/tikv/pd/client"
@@ -79,12 +81,14 @@ type schemaStore struct {
 func New(
 	ctx context.Context,
 	root string,
+	cfg *config.SchemaStoreConfig,
 	pdCli pd.Client,
 	regionCache *tikv.RegionCache,
 	pdClock pdutil.Clock,
 	kvStorage kv.Storage,
+	credential *security.Credential,
 ) SchemaStore {
-	dataStorage := newPersistentStorage(ctx, root, pdCli, kvStorage)
+	dataStorage := newPersistentStorage(ctx, root, cfg, pdCli, kvStorage)
 	upperBound := dataStorage.getUpperBound()
 
 	s := &schemaStore{
@@ -107,6 +111,7 @@ func New(
 		regionCache,
 		pdClock,
 		kvStorage,
+		credential,
 		upperBound.ResolvedTs,
 		s.writeDDLEvent,
 		s.advanceResolvedTs)
@@ -104,12 +104,9 @@ func (m *Manager) add(upstreamID uint64,
 	}
 	securityConf := &security.Credential{}
 	if conf != nil {
-		securityConf = &security.Credential{
-			CAPath:        conf.CAPath,
-			CertPath:      conf.CertPath,
-			KeyPath:       conf.KeyPath,
-			CertAllowedCN: conf.CertAllowedCN,
-		}
+		// copy all the fields, so the mTLS and the client user settings are kept.
+		credential := *conf
+		securityConf = &credential
 	}
 	up := newUpstream(pdEndpoints, securityConf)
 	m.ups.Store(upstreamID, up)
@@ -0,0 +1,160 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/apperror"
+	"github.com/pingcap/tidb/pkg/domain/infosync"
+	"github.com/pingcap/tiflow/pkg/errors"
+	clientV3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// topologyTiDB is /topology/tidb/{ip:port}.
+	// Refer to https://github.com/pingcap/tidb/blob/release-7.5/pkg/domain/infosync/info.go#L78-L79.
+	topologyTiDB    = infosync.TopologyInformationPath
+	topologyTiDBTTL = infosync.TopologySessionTTL
+	// defaultTimeout is the default timeout for etcd and mysql operations.
+	defaultTimeout = time.Second * 2
+)
+
+// VerifyTiDBUser verifies the user and password by connecting to the alive
+// TiDB instances registered in the etcd of the upstream PD.
+func VerifyTiDBUser(ctx context.Context, etcdCli *clientV3.Client, username, password string) error {
+	tidbs, err := fetchTiDBTopology(ctx, etcdCli)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(tidbs) == 0 {
+		return errors.New("tidb instance not found in topology, please check if the tidb is running")
+	}
+
+	for _, addr := range tidbs {
+		err = doVerify(ctx, addr, username, password)
+		if err == nil {
+			return nil
+		}
+		if apperror.IsAccessDeniedError(err) {
+			// For access denied error, we can return immediately.
+			// For other errors, we need to continue to verify the next tidb instance.
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(err)
+}
+
+func doVerify(ctx context.Context, addr, username, password string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	dsn := dmysql.NewConfig()
+	dsn.User = username
+	dsn.Passwd = password
+	dsn.Net = "tcp"
+	dsn.Addr = addr
+	dsn.Timeout = defaultTimeout
+	// Note: we use "preferred" here to make sure the connection is encrypted if possible. It is the same as the default
+	// behavior of mysql client, refer to: https://dev.mysql.com/doc/refman/8.0/en/using-encrypted-connections.html.
+	dsn.TLSConfig = "preferred"
+
+	db, err := sql.Open("mysql", dsn.FormatDSN())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer db.Close()
+
+	if err = db.PingContext(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	log.Info("verify tidb user successfully",
+		zap.String("username", username), zap.String("addr", addr))
+	return nil
+}
+
+// fetchTiDBTopology returns the addresses of the alive TiDB instances.
+func fetchTiDBTopology(ctx context.Context, etcdCli *clientV3.Client) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	resp, err := etcdCli.Get(ctx, topologyTiDB, clientV3.WithPrefix())
+	if err != nil {
+		return nil, errors.ErrPDEtcdAPIError.Wrap(err)
+	}
+
+	nodesAlive := make(map[string]struct{}, len(resp.Kvs))
+	nodesInfo := make(map[string]struct{}, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		key := string(kv.Key)
+		if !strings.HasPrefix(key, topologyTiDB) {
+			continue
+		}
+		// remainingKey looks like `ip:port/info` or `ip:port/ttl`.
+		remainingKey := strings.TrimPrefix(key[len(topologyTiDB):], "/")
+		keyParts := strings.Split(remainingKey, "/")
+		if len(keyParts) != 2 {
+			log.Warn("Ignored invalid topology key", zap.String("key", key))
+			continue
+		}
+
+		switch keyParts[1] {
+		case "info":
+			if _, _, err := net.SplitHostPort(keyParts[0]); err != nil {
+				log.Warn("Ignored invalid tidb topology info entry",
+					zap.String("key", key), zap.Error(err))
+				continue
+			}
+			nodesInfo[keyParts[0]] = struct{}{}
+		case "ttl":
+			alive, err := parseTiDBAliveness(kv.Value)
+			if !alive || err != nil {
+				log.Warn("Ignored invalid tidb topology TTL entry",
+					zap.String("key", key),
+					zap.String("value", string(kv.Value)),
+					zap.Error(err))
+				continue
+			}
+			nodesAlive[keyParts[0]] = struct{}{}
+		}
+	}
+
+	nodes := make([]string, 0, len(nodesInfo))
+	for addr := range nodesInfo {
+		if _, ok := nodesAlive[addr]; ok {
+			nodes = append(nodes, addr)
+		}
+	}
+	return nodes, nil
+}
+
+func parseTiDBAliveness(value []byte) (bool, error) {
+	unixTimestampNano, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return false, errors.ErrUnmarshalFailed.Wrap(err)
+	}
+	t := time.Unix(0, int64(unixTimestampNano))
+	if time.Since(t) > topologyTiDBTTL*time.Second {
+		return false, nil
+	}
+	return true, nil
+}
@@ -130,7 +130,7 @@ func initUpstream(ctx context.Context, up *Upstream) error {
 		return errors.Trace(err)
 	}
 	// init the tikv client tls global config
-	initGlobalConfig(up.SecurityConfig)
+	InitGlobalConfig(up.SecurityConfig)
 	// default upstream always use the pdClient pass from cdc server
 	if !up.isDefaultUpstream {
 		up.PDClient, err = pd.NewClientWithContext(
@@ -211,10 +211,10 @@ func initUpstream(ctx context.Context, up *Upstream) error {
 	return nil
 }
 
-// initGlobalConfig initializes the global config for tikv client tls.
+// InitGlobalConfig initializes the global config for tikv client tls.
 // region cache health check will use the global config.
 // TODO: remove this function after tikv client tls is refactored.
-func initGlobalConfig(secCfg *security.Credential) {
+func InitGlobalConfig(secCfg *security.Credential) {
 	if secCfg.CAPath != "" || secCfg.CertPath != "" || secCfg.KeyPath != "" {
 		conf := tikvconfig.GetGlobalConfig()
 		conf.Security.ClusterSSLCA = secCfg.CAPath
@@ -14,12 +14,16 @@
 package maintainer
 
 import (
+	"sort"
+
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/heartbeatpb"
 	"github.com/pingcap/ticdc/maintainer/range_checker"
 	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/messaging"
 	"github.com/pingcap/ticdc/pkg/node"
+	"github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -33,10 +37,15 @@ import (
 // 5. maintainer send pass action to all other dispatchers. (resend logic is needed)
 // 6. maintainer wait for all dispatchers reporting event(pass) done message
 // 7. maintainer clear the event
+// If the ddl is blocked by the block ddl config of the changefeed, the maintainer holds it in step 3
+// until it's approved or skipped manually, a skipped ddl is passed by the writer instead of written.
+// Only the dispatchers influenced by the ddl are blocked, the other tables keep replicating.
 type Barrier struct {
 	blockedTs         map[eventKey]*BarrierEvent
 	controller        *Controller
 	splitTableEnabled bool
+	// ddlDecisions is the persisted decisions of the pending ddls, block ts -> approved
+	ddlDecisions map[uint64]bool
 }
 
 // eventKey is the key of the block event,
@@ -52,6 +61,7 @@ func NewBarrier(controller *Controller, splitTableEnabled bool) *Barrier {
 		blockedTs:         make(map[eventKey]*BarrierEvent),
 		controller:        controller,
 		splitTableEnabled: splitTableEnabled,
+		ddlDecisions:      make(map[uint64]bool),
 	}
 }
 
@@ -106,6 +116,7 @@ func (b *Barrier) HandleBootstrapResponse(bootstrapRespMap map[node.ID]*heartbea
 			event, ok := b.blockedTs[key]
 			if !ok {
 				event = NewBlockEvent(common.NewChangefeedIDFromPB(resp.ChangefeedID), b.controller, blockState, b.splitTableEnabled)
+				b.applyDDLDecision(event)
 				b.blockedTs[key] = event
 			}
 			switch blockState.Stage {
@@ -218,6 +229,7 @@ func (b *Barrier) getOrInsertNewEvent(changefeedID common.ChangeFeedID, key even
 	event, ok := b.blockedTs[key]
 	if !ok {
 		event = NewBlockEvent(changefeedID, b.controller, blockState, b.splitTableEnabled)
+		b.applyDDLDecision(event)
 		b.blockedTs[key] = event
 	}
 	return event
@@ -237,9 +249,89 @@ func (b *Barrier) checkEvent(be *BarrierEvent,
 		be.scheduleBlockEvent()
 		return nil
 	}
+	if be.waitingForApproval() {
+		if be.reportedDispatchers == nil {
+			log.Info("all dispatchers reported the ddl, wait for it to be approved or skipped",
+				zap.String("changefeed", be.cfID.Name()),
+				zap.Uint64("commitTs", be.commitTs),
+				zap.String("query", be.query))
+		}
+		be.reportedDispatchers = dispatchers
+		return nil
+	}
 	return be.onAllDispatcherReportedBlockEvent(dispatchers)
 }
 
+// GetPendingDDLs returns the ddls which are waiting to be approved or skipped, sorted by the block ts.
+func (b *Barrier) GetPendingDDLs() []*config.PendingDDL {
+	var ddls []*config.PendingDDL
+	for _, event := range b.blockedTs {
+		if !event.waitingForApproval() {
+			continue
+		}
+		ddls = append(ddls, &config.PendingDDL{
+			BlockTs: event.commitTs,
+			Query:   event.query,
+			Ready:   event.reportedDispatchers != nil,
+		})
+	}
+	sort.Slice(ddls, func(i, j int) bool {
+		return ddls[i].BlockTs < ddls[j].BlockTs
+	})
+	return ddls
+}
+
+// DecidePendingDDL approves or skips the pending ddl, a skipped ddl is not executed in the downstream.
+// If all dispatchers have reached the ddl, a writer is selected and the action is sent by the resend logic,
+// otherwise the decision is applied after all dispatchers reach the ddl.
+func (b *Barrier) DecidePendingDDL(blockTs uint64, approve bool) error {
+	event, ok := b.blockedTs[getEventKey(blockTs, false)]
+	if !ok || !event.needApproval {
+		return errors.Errorf("pending ddl with block ts %d is not found", blockTs)
+	}
+	if event.decided {
+		return errors.Errorf("pending ddl with block ts %d is already approved or skipped", blockTs)
+	}
+	event.decide(approve)
+	log.Info("pending ddl is decided",
+		zap.String("changefeed", event.cfID.Name()),
+		zap.Uint64("commitTs", event.commitTs),
+		zap.String("query", event.query),
+		zap.Bool("approve", approve))
+	if event.reportedDispatchers != nil {
+		event.onAllDispatcherReportedBlockEvent(event.reportedDispatchers)
+	}
+	return nil
+}
+
+// LoadPendingDDLDecisions loads the decisions persisted with the changefeed info,
+// they are applied to the pending ddls tracked now and reported later.
+func (b *Barrier) LoadPendingDDLDecisions(decisions []*config.PendingDDLDecision) {
+	for _, decision := range decisions {
+		b.ddlDecisions[decision.BlockTs] = decision.Approved
+	}
+	for _, event := range b.blockedTs {
+		b.applyDDLDecision(event)
+	}
+}
+
+// applyDDLDecision applies the persisted decision to the ddl if it's waiting for approval.
+func (b *Barrier) applyDDLDecision(event *BarrierEvent) {
+	if !event.waitingForApproval() || event.isSyncPoint {
+		return
+	}
+	approved, ok := b.ddlDecisions[event.commitTs]
+	if !ok {
+		return
+	}
+	event.decide(approved)
+	log.Info("apply the persisted decision of the pending ddl",
+		zap.String("changefeed", event.cfID.Name()),
+		zap.Uint64("commitTs", event.commitTs),
+		zap.String("query", event.query),
+		zap.Bool("approve", approved))
+}
+
 // ackEvent creates an ack event
 func ackEvent(commitTs uint64, isSyncPoint bool) *heartbeatpb.ACK {
 	return &heartbeatpb.ACK{
@@ -46,6 +46,17 @@ type BarrierEvent struct {
 	// if the split table is enable for this changefeeed, if not we can use table id to check coverage
 	dynamicSplitEnabled bool
 
+	// needApproval is true if the ddl is blocked by the block ddl config of the changefeed,
+	// the writer is not selected until the ddl is approved or skipped manually.
+	needApproval bool
+	query        string
+	decided      bool
+	// writerAction is the action sent to the writer dispatcher, it's pass if the ddl is skipped
+	writerAction heartbeatpb.Action
+	// reportedDispatchers is the dispatchers reported the block event when all dispatchers are reported,
+	// the writer is selected from them after the ddl is approved or skipped.
+	reportedDispatchers []*heartbeatpb.DispatcherID
+
 	// rangeChecker is used to check if all the dispatchers reported the block events
 	rangeChecker   range_checker.RangeChecker
 	lastResendTime time.Time
@@ -65,6 +76,9 @@ func NewBlockEvent(cfID common.ChangeFeedID, controller *Controller,
 		lastResendTime:      time.Time{},
 		isSyncPoint:         status.IsSyncPoint,
 		dynamicSplitEnabled: dynamicSplitEnabled,
+		needApproval:        status.NeedApproval,
+		query:               status.Query,
+		writerAction:        heartbeatpb.Action_Write,
 	}
 	if status.BlockTables != nil {
 		switch status.BlockTables.InfluenceType {
@@ -150,13 +164,14 @@ func (be *BarrierEvent) onAllDispatcherReportedBlockEvent(dispatchers []*heartbe
 		zap.String("changefeed", be.cfID.Name()),
 		zap.String("dispatcher", be.writerDispatcher.String()),
 		zap.Uint64("commitTs", be.commitTs),
-		zap.String("barrierType", be.blockedDispatchers.InfluenceType.String()))
+		zap.String("barrierType", be.blockedDispatchers.InfluenceType.String()),
+		zap.String("action", be.writerAction.String()))
 	return &heartbeatpb.DispatcherStatus{
 		InfluencedDispatchers: &heartbeatpb.InfluencedDispatchers{
 			InfluenceType: heartbeatpb.InfluenceType_Normal,
 			DispatcherIDs: []*heartbeatpb.DispatcherID{be.writerDispatcher.ToPB()},
 		},
-		Action: be.action(heartbeatpb.Action_Write),
+		Action: be.action(be.writerAction),
 	}
 }
 
@@ -221,6 +236,19 @@ func (be *BarrierEvent) allDispatcherReported() bool {
 	return be.rangeChecker.IsFullyCovered()
 }
 
+// waitingForApproval returns true if the ddl needs approval and it's not approved or skipped yet.
+func (be *BarrierEvent) waitingForApproval() bool {
+	return be.needApproval && !be.decided
+}
+
+// decide marks the ddl as approved or skipped, the writer passes the skipped ddl instead of writing it.
+func (be *BarrierEvent) decide(approve bool) {
+	be.decided = true
+	if !approve {
+		be.writerAction = heartbeatpb.Action_Pass
+	}
+}
+
 func (be *BarrierEvent) sendPassAction() []*messaging.TargetMessage {
 	if be.blockedDispatchers == nil {
 		return []*messaging.TargetMessage{}
@@ -298,7 +326,7 @@ func (be *BarrierEvent) newWriterActionMessage(capture node.ID) *messaging.Targe
 			ChangefeedID: be.cfID.ToPB(),
 			DispatcherStatuses: []*heartbeatpb.DispatcherStatus{
 				{
-					Action: be.action(heartbeatpb.Action_Write),
+					Action: be.action(be.writerAction),
 					InfluencedDispatchers: &heartbeatpb.InfluencedDispatchers{
 						InfluenceType: heartbeatpb.InfluenceType_Normal,
 						DispatcherIDs: []*heartbeatpb.DispatcherID{
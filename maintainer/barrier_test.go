@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/ticdc/maintainer/replica"
 	"github.com/pingcap/ticdc/pkg/common"
 	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/messaging"
 	"github.com/pingcap/ticdc/pkg/node"
 	"github.com/stretchr/testify/require"
@@ -927,6 +928,128 @@ func TestNonBlocked(t *testing.T) {
 	require.Equal(t, 2, barrier.controller.replicationDB.GetAbsentSize(), 2)
 }
 
+func TestBlockDDLApproval(t *testing.T) {
+	setNodeManagerAndMessageCenter()
+	tableTriggerEventDispatcherID := common.NewDispatcherID()
+	cfID := common.NewChangeFeedIDWithName("test")
+	tsoClient := &mockTsoClient{}
+	ddlSpan := replica.NewWorkingReplicaSet(cfID, tableTriggerEventDispatcherID,
+		tsoClient, heartbeatpb.DDLSpanSchemaID,
+		heartbeatpb.DDLSpan, &heartbeatpb.TableSpanStatus{
+			ID:              tableTriggerEventDispatcherID.ToPB(),
+			ComponentStatus: heartbeatpb.ComponentState_Working,
+			CheckpointTs:    1,
+		}, "node1")
+	controller := NewController(cfID, 1, nil, tsoClient, nil, nil, nil, ddlSpan, 1000, 0)
+	var blockedDispatcherIDS []*heartbeatpb.DispatcherID
+	for id := 1; id < 3; id++ {
+		controller.AddNewTable(commonEvent.Table{SchemaID: 1, TableID: int64(id)}, 0)
+		stm := controller.GetTasksByTableIDs(int64(id))[0]
+		blockedDispatcherIDS = append(blockedDispatcherIDS, stm.ID.ToPB())
+		controller.replicationDB.BindSpanToNode("", "node1", stm)
+		controller.replicationDB.MarkSpanReplicating(stm)
+	}
+	barrier := NewBarrier(controller, false)
+
+	blockState := func(blockTs uint64) *heartbeatpb.State {
+		return &heartbeatpb.State{
+			IsBlocked: true,
+			BlockTs:   blockTs,
+			BlockTables: &heartbeatpb.InfluencedTables{
+				InfluenceType: heartbeatpb.InfluenceType_Normal,
+				TableIDs:      []int64{1, 2},
+			},
+			NeedApproval: true,
+			Query:        "DROP TABLE t",
+		}
+	}
+	reportBlocked := func(blockTs uint64, dispatchers ...*heartbeatpb.DispatcherID) *heartbeatpb.HeartBeatResponse {
+		var statuses []*heartbeatpb.TableSpanBlockStatus
+		for _, id := range dispatchers {
+			statuses = append(statuses, &heartbeatpb.TableSpanBlockStatus{ID: id, State: blockState(blockTs)})
+		}
+		msg := barrier.HandleStatus("node1", &heartbeatpb.BlockStatusRequest{
+			ChangefeedID:  cfID.ToPB(),
+			BlockStatuses: statuses,
+		})
+		require.NotNil(t, msg)
+		return msg.Message[0].(*heartbeatpb.HeartBeatResponse)
+	}
+
+	// all dispatchers reported, only ack is sent since the ddl is waiting for approval
+	resp := reportBlocked(10, blockedDispatcherIDS...)
+	require.Len(t, resp.DispatcherStatuses, 1)
+	require.Equal(t, uint64(10), resp.DispatcherStatuses[0].Ack.CommitTs)
+	event := barrier.blockedTs[getEventKey(10, false)]
+	require.False(t, event.selected)
+	ddls := barrier.GetPendingDDLs()
+	require.Len(t, ddls, 1)
+	require.Equal(t, uint64(10), ddls[0].BlockTs)
+	require.Equal(t, "DROP TABLE t", ddls[0].Query)
+	require.True(t, ddls[0].Ready)
+	require.Len(t, barrier.Resend(), 0)
+
+	require.Error(t, barrier.DecidePendingDDL(9, false))
+	// skip the ddl, the writer passes the ddl instead of writing it
+	require.NoError(t, barrier.DecidePendingDDL(10, false))
+	require.Error(t, barrier.DecidePendingDDL(10, false))
+	require.True(t, event.selected)
+	require.Len(t, barrier.GetPendingDDLs(), 0)
+	msgs := barrier.Resend()
+	require.Len(t, msgs, 1)
+	resp = msgs[0].Message[0].(*heartbeatpb.HeartBeatResponse)
+	require.Equal(t, heartbeatpb.Action_Pass, resp.DispatcherStatuses[0].Action.Action)
+	require.Equal(t, event.writerDispatcher.ToPB(), resp.DispatcherStatuses[0].InfluencedDispatchers.DispatcherIDs[0])
+
+	// approve the ddl before all dispatchers reach it
+	resp = reportBlocked(20, blockedDispatcherIDS[0])
+	require.Len(t, resp.DispatcherStatuses, 1)
+	ddls = barrier.GetPendingDDLs()
+	require.Len(t, ddls, 1)
+	require.False(t, ddls[0].Ready)
+	require.NoError(t, barrier.DecidePendingDDL(20, true))
+	event = barrier.blockedTs[getEventKey(20, false)]
+	require.False(t, event.selected)
+	resp = reportBlocked(20, blockedDispatcherIDS[1])
+	require.Len(t, resp.DispatcherStatuses, 2)
+	require.Equal(t, heartbeatpb.Action_Write, resp.DispatcherStatuses[1].Action.Action)
+	require.True(t, event.selected)
+
+	// the maintainer is restarted after the ddl is skipped and the writer is selected,
+	// the persisted decision is applied and the writer still passes the ddl
+	barrier = NewBarrier(controller, false)
+	writingState := blockState(30)
+	writingState.Stage = heartbeatpb.BlockStage_WRITING
+	waitingState := blockState(30)
+	waitingState.Stage = heartbeatpb.BlockStage_WAITING
+	barrier.HandleBootstrapResponse(map[node.ID]*heartbeatpb.MaintainerBootstrapResponse{
+		"node1": {
+			ChangefeedID: cfID.ToPB(),
+			Spans: []*heartbeatpb.BootstrapTableSpan{
+				{ID: blockedDispatcherIDS[0], BlockState: waitingState},
+				{ID: blockedDispatcherIDS[1], BlockState: writingState},
+			},
+		},
+	})
+	barrier.LoadPendingDDLDecisions([]*config.PendingDDLDecision{
+		{BlockTs: 30, Approved: false},
+		{BlockTs: 40, Approved: true},
+	})
+	event = barrier.blockedTs[getEventKey(30, false)]
+	require.True(t, event.selected)
+	require.Len(t, barrier.GetPendingDDLs(), 0)
+	msgs = barrier.Resend()
+	require.Len(t, msgs, 1)
+	resp = msgs[0].Message[0].(*heartbeatpb.HeartBeatResponse)
+	require.Equal(t, heartbeatpb.Action_Pass, resp.DispatcherStatuses[0].Action.Action)
+	require.Equal(t, blockedDispatcherIDS[1], resp.DispatcherStatuses[0].InfluencedDispatchers.DispatcherIDs[0])
+
+	// the decision of the ddl reported after the restart is applied too
+	resp = reportBlocked(40, blockedDispatcherIDS...)
+	require.Len(t, resp.DispatcherStatuses, 2)
+	require.Equal(t, heartbeatpb.Action_Write, resp.DispatcherStatuses[1].Action.Action)
+}
+
 func TestUpdateCheckpointTs(t *testing.T) {
 	setNodeManagerAndMessageCenter()
 	tableTriggerEventDispatcherID := common.NewDispatcherID()
@@ -50,6 +50,7 @@ func NewController(changefeedID common.ChangeFeedID,
 	}
 	c.checkers = []Checker{
 		NewSplitChecker(changefeedID, splitter, oc, db, nodeManager),
+		NewMergeChecker(changefeedID, splitter, oc, db),
 		NewBalanceChecker(changefeedID, oc, db, nodeManager),
 	}
 	return c
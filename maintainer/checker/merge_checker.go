@@ -0,0 +1,178 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/maintainer/operator"
+	"github.com/pingcap/ticdc/maintainer/replica"
+	"github.com/pingcap/ticdc/maintainer/split"
+	"github.com/pingcap/ticdc/pkg/common"
+	"go.uber.org/zap"
+)
+
+// MergeChecker is used to check whether the adjacent spans of a table can be merged.
+// The spans are merged if the write keys and the region count of the merged span are
+// both below the merge thresholds, which are lower than the split thresholds, and the
+// table is not split recently, so a table is not split and merged back and forth.
+type MergeChecker struct {
+	changefeedID common.ChangeFeedID
+	splitter     *split.Splitter
+	opController *operator.Controller
+	db           *replica.ReplicationDB
+
+	maxCheckTime  time.Duration
+	checkInterval time.Duration
+	lastCheckTime time.Time
+	// the spans of a table are not merged in splitCoolDown after it's split
+	splitCoolDown time.Duration
+
+	checkedIndex int
+	cachedGroups [][]*replica.SpanReplication
+}
+
+func NewMergeChecker(
+	changefeedID common.ChangeFeedID,
+	splitter *split.Splitter,
+	opController *operator.Controller,
+	db *replica.ReplicationDB) *MergeChecker {
+	return &MergeChecker{
+		changefeedID: changefeedID,
+		splitter:     splitter,
+		opController: opController,
+		db:           db,
+
+		maxCheckTime: time.Second * 5,
+		// merge is checked less frequently than split, the traffic of
+		// a table must keep low for a while before it's merged.
+		checkInterval: time.Minute * 10,
+		// the spans split just now are not merged until the interval passes.
+		lastCheckTime: time.Now(),
+		splitCoolDown: time.Minute * 30,
+	}
+}
+
+func (m *MergeChecker) Name() string {
+	return "merge-checker"
+}
+
+func (m *MergeChecker) Check() {
+	if m.splitter == nil {
+		return
+	}
+	if time.Since(m.lastCheckTime) < m.checkInterval {
+		return
+	}
+	if m.cachedGroups == nil {
+		m.cachedGroups = findAdjacentSpans(m.db.GetReplicating())
+		m.checkedIndex = 0
+	}
+	start := time.Now()
+	for ; m.checkedIndex < len(m.cachedGroups); m.checkedIndex++ {
+		for _, group := range m.filterGroup(m.cachedGroups[m.checkedIndex]) {
+			m.tryMerge(group)
+		}
+		if time.Since(start) > m.maxCheckTime {
+			break
+		}
+	}
+	if m.checkedIndex >= len(m.cachedGroups) {
+		m.cachedGroups = nil
+		m.checkedIndex = 0
+		m.lastCheckTime = time.Now()
+	}
+}
+
+// filterGroup removes the spans which are removed or handled by other operators
+// since the group is cached, and splits the group into adjacent sub groups.
+func (m *MergeChecker) filterGroup(group []*replica.SpanReplication) [][]*replica.SpanReplication {
+	var (
+		result  [][]*replica.SpanReplication
+		current []*replica.SpanReplication
+	)
+	// the traffic of a table split recently may not be stable yet
+	if time.Since(m.db.GetLastSplitTime(group[0].Span.TableID)) < m.splitCoolDown {
+		return nil
+	}
+	for _, span := range group {
+		if m.db.GetTaskByID(span.ID) == nil || m.opController.GetOperator(span.ID) != nil {
+			if len(current) > 1 {
+				result = append(result, current)
+			}
+			current = nil
+			continue
+		}
+		current = append(current, span)
+	}
+	if len(current) > 1 {
+		result = append(result, current)
+	}
+	return result
+}
+
+func (m *MergeChecker) tryMerge(group []*replica.SpanReplication) {
+	first, last := group[0], group[len(group)-1]
+	merged := &heartbeatpb.TableSpan{
+		TableID:  first.Span.TableID,
+		StartKey: first.Span.StartKey,
+		EndKey:   last.Span.EndKey,
+	}
+	if !m.splitter.Mergeable(context.Background(), merged) {
+		return
+	}
+	log.Info("merge spans",
+		zap.String("changefeed", m.changefeedID.Name()),
+		zap.Int64("table", merged.TableID),
+		zap.String("span", first.ID.String()),
+		zap.Int("span size", len(group)))
+	m.opController.AddOperator(operator.NewMergeDispatcherOperator(m.db, group))
+}
+
+// findAdjacentSpans groups the spans by table, and returns the groups of
+// at least two spans which are sorted and adjacent.
+func findAdjacentSpans(spans []*replica.SpanReplication) [][]*replica.SpanReplication {
+	tableSpans := make(map[int64][]*replica.SpanReplication)
+	for _, span := range spans {
+		tableSpans[span.Span.TableID] = append(tableSpans[span.Span.TableID], span)
+	}
+	var groups [][]*replica.SpanReplication
+	for _, spans := range tableSpans {
+		if len(spans) < 2 {
+			continue
+		}
+		sort.Slice(spans, func(i, j int) bool {
+			return spans[i].Span.Less(spans[j].Span)
+		})
+		current := []*replica.SpanReplication{spans[0]}
+		for _, span := range spans[1:] {
+			if !bytes.Equal(current[len(current)-1].Span.EndKey, span.Span.StartKey) {
+				if len(current) > 1 {
+					groups = append(groups, current)
+				}
+				current = nil
+			}
+			current = append(current, span)
+		}
+		if len(current) > 1 {
+			groups = append(groups, current)
+		}
+	}
+	return groups
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/maintainer/replica"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAdjacentSpans(t *testing.T) {
+	cfID := common.NewChangeFeedIDWithName("test")
+	newSpan := func(tableID int64, start, end string) *replica.SpanReplication {
+		return replica.NewReplicaSet(cfID, common.NewDispatcherID(), nil, 1,
+			&heartbeatpb.TableSpan{TableID: tableID, StartKey: []byte(start), EndKey: []byte(end)}, 10)
+	}
+	t1a, t1b, t1c := newSpan(1, "a", "b"), newSpan(1, "b", "c"), newSpan(1, "c", "d")
+	// there is a gap between t2b and t2c, because the span [c,d) is not replicating
+	t2a, t2b, t2c := newSpan(2, "a", "b"), newSpan(2, "b", "c"), newSpan(2, "d", "e")
+	// only one span of the table
+	t3a := newSpan(3, "a", "b")
+
+	groups := findAdjacentSpans([]*replica.SpanReplication{t1c, t2c, t1a, t3a, t2b, t1b, t2a})
+	require.Len(t, groups, 2)
+	if groups[0][0].Span.TableID != 1 {
+		groups[0], groups[1] = groups[1], groups[0]
+	}
+	require.Equal(t, []*replica.SpanReplication{t1a, t1b, t1c}, groups[0])
+	require.Equal(t, []*replica.SpanReplication{t2a, t2b}, groups[1])
+}
+
+func TestMergeCheckerWaitsForInterval(t *testing.T) {
+	// the spans are not merged until the check interval passes after the checker is created
+	m := NewMergeChecker(common.NewChangeFeedIDWithName("test"), nil, nil, nil)
+	require.WithinDuration(t, time.Now(), m.lastCheckTime, time.Second)
+	require.Less(t, time.Since(m.lastCheckTime), m.checkInterval)
+}
+
+func TestMergeCheckerSkipsTableSplitRecently(t *testing.T) {
+	cfID := common.NewChangeFeedIDWithName("test")
+	ddlSpan := replica.NewWorkingReplicaSet(cfID, common.NewDispatcherID(), nil, heartbeatpb.DDLSpanSchemaID,
+		heartbeatpb.DDLSpan, &heartbeatpb.TableSpanStatus{
+			ComponentStatus: heartbeatpb.ComponentState_Working,
+			CheckpointTs:    1,
+		}, "node1")
+	db := replica.NewReplicaSetDB(cfID, ddlSpan)
+	span := replica.NewReplicaSet(cfID, common.NewDispatcherID(), nil, 1,
+		&heartbeatpb.TableSpan{TableID: 1, StartKey: []byte("a"), EndKey: []byte("c")}, 10)
+	db.AddAbsentReplicaSet(span)
+	require.True(t, db.ReplaceReplicaSet(span, []*heartbeatpb.TableSpan{
+		{TableID: 1, StartKey: []byte("a"), EndKey: []byte("b")},
+		{TableID: 1, StartKey: []byte("b"), EndKey: []byte("c")},
+	}, 10))
+
+	// the spans are not merged in the cool-down after the table is split
+	m := NewMergeChecker(cfID, nil, nil, db)
+	require.Nil(t, m.filterGroup(db.GetTasksByTableIDs(1)))
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/atomic"
@@ -103,6 +104,12 @@ type Maintainer struct {
 	errLock       sync.Mutex
 	runningErrors map[node.ID]*heartbeatpb.RunningError
 
+	// deadLetterRowsByCapture is the number of rows written to the dead letter
+	// destination by the sink on each node, it's a runtime statistic which is
+	// reset when the sink or the maintainer is recreated.
+	deadLetterRowsByCapture map[node.ID]uint64
+	deadLetterRows          *atomic.Uint64
+
 	changefeedCheckpointTsGauge    prometheus.Gauge
 	changefeedCheckpointTsLagGauge prometheus.Gauge
 	changefeedResolvedTsGauge      prometheus.Gauge
@@ -163,6 +170,9 @@ func NewMaintainer(cfID common.ChangeFeedID,
 		checkpointTsByCapture: make(map[node.ID]heartbeatpb.Watermark),
 		runningErrors:         map[node.ID]*heartbeatpb.RunningError{},
 
+		deadLetterRowsByCapture: make(map[node.ID]uint64),
+		deadLetterRows:          atomic.NewUint64(0),
+
 		changefeedCheckpointTsGauge:    metrics.ChangefeedCheckpointTsGauge.WithLabelValues(cfID.Namespace(), cfID.Name()),
 		changefeedCheckpointTsLagGauge: metrics.ChangefeedCheckpointTsLagGauge.WithLabelValues(cfID.Namespace(), cfID.Name()),
 		changefeedResolvedTsGauge:      metrics.ChangefeedResolvedTsGauge.WithLabelValues(cfID.Namespace(), cfID.Name()),
@@ -263,11 +273,12 @@ func (m *Maintainer) GetMaintainerStatus() *heartbeatpb.MaintainerStatus {
 		clear(m.runningErrors)
 	}
 	status := &heartbeatpb.MaintainerStatus{
-		ChangefeedID: m.id.ToPB(),
-		FeedState:    string(m.changefeedSate),
-		State:        m.state,
-		CheckpointTs: m.watermark.CheckpointTs,
-		Err:          runningErrors,
+		ChangefeedID:   m.id.ToPB(),
+		FeedState:      string(m.changefeedSate),
+		State:          m.state,
+		CheckpointTs:   m.watermark.CheckpointTs,
+		Err:            runningErrors,
+		DeadLetterRows: m.deadLetterRows.Load(),
 	}
 	return status
 }
@@ -336,6 +347,12 @@ func (m *Maintainer) onMessage(msg *messaging.TargetMessage) {
 		m.onRemoveMaintainer(req.Cascade, req.Removed)
 	case messaging.TypeCheckpointTsMessage:
 		m.onCheckpointTsPersisted(msg.Message[0].(*heartbeatpb.CheckpointTsMessage))
+	case messaging.TypeTableScheduleRequest:
+		m.onTableScheduleRequest(msg)
+	case messaging.TypeTableStatusRequest:
+		m.onTableStatusRequest(msg)
+	case messaging.TypePendingDDLRequest:
+		m.onPendingDDLRequest(msg)
 	default:
 		log.Panic("unexpected message type",
 			zap.String("changefeed", m.id.Name()),
@@ -362,6 +379,89 @@ func (m *Maintainer) onCheckpointTsPersisted(msg *heartbeatpb.CheckpointTsMessag
 	})
 }
 
+// onTableScheduleRequest handles the manual table schedule request from the coordinator,
+// and replies the coordinator after the operators are added.
+func (m *Maintainer) onTableScheduleRequest(msg *messaging.TargetMessage) {
+	req := msg.Message[0].(*messaging.TableScheduleRequest)
+	var (
+		spans []*config.TableSpanReplicationStatus
+		err   error
+	)
+	if !m.bootstrapped {
+		err = errors.New("maintainer is not bootstrapped, try again later")
+	} else {
+		switch req.Action {
+		case messaging.TableScheduleActionMove:
+			err = m.controller.MoveTable(req.TableID, node.ID(req.TargetNode))
+		case messaging.TableScheduleActionSplit:
+			spans, err = m.controller.SplitTable(req.TableID)
+		default:
+			err = errors.Errorf("unknown table schedule action %d", req.Action)
+		}
+	}
+	log.Info("table schedule request handled",
+		zap.String("changefeed", m.id.Name()),
+		zap.Stringer("action", req.Action),
+		zap.Int64("table", req.TableID),
+		zap.String("targetNode", req.TargetNode),
+		zap.Error(err))
+	resp := &messaging.TableScheduleResponse{RequestID: req.RequestID, Spans: spans}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	m.sendMessages([]*messaging.TargetMessage{
+		messaging.NewSingleTargetMessage(msg.From, messaging.CoordinatorTopic, resp),
+	})
+}
+
+// onTableStatusRequest replies the replication status of all the spans to the coordinator.
+func (m *Maintainer) onTableStatusRequest(msg *messaging.TargetMessage) {
+	req := msg.Message[0].(*messaging.TableStatusRequest)
+	resp := &messaging.TableStatusResponse{RequestID: req.RequestID}
+	if !m.bootstrapped {
+		resp.Error = "maintainer is not bootstrapped, try again later"
+	} else {
+		resp.Spans = m.controller.GetTableSpanStatus()
+	}
+	m.sendMessages([]*messaging.TargetMessage{
+		messaging.NewSingleTargetMessage(msg.From, messaging.CoordinatorTopic, resp),
+	})
+}
+
+// onPendingDDLRequest lists, approves or skips the ddls which are blocked by the block ddl config.
+func (m *Maintainer) onPendingDDLRequest(msg *messaging.TargetMessage) {
+	req := msg.Message[0].(*messaging.PendingDDLRequest)
+	resp := &messaging.PendingDDLResponse{RequestID: req.RequestID}
+	var err error
+	if !m.bootstrapped || m.barrier == nil {
+		err = errors.New("maintainer is not bootstrapped, try again later")
+	} else {
+		switch req.Action {
+		case messaging.PendingDDLActionList:
+			resp.DDLs = m.barrier.GetPendingDDLs()
+		case messaging.PendingDDLActionApprove:
+			err = m.barrier.DecidePendingDDL(req.BlockTs, true)
+		case messaging.PendingDDLActionSkip:
+			err = m.barrier.DecidePendingDDL(req.BlockTs, false)
+		default:
+			err = errors.Errorf("unknown pending ddl action %d", req.Action)
+		}
+	}
+	if req.Action != messaging.PendingDDLActionList {
+		log.Info("pending ddl request handled",
+			zap.String("changefeed", m.id.Name()),
+			zap.Stringer("action", req.Action),
+			zap.Uint64("blockTs", req.BlockTs),
+			zap.Error(err))
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	m.sendMessages([]*messaging.TargetMessage{
+		messaging.NewSingleTargetMessage(msg.From, messaging.CoordinatorTopic, resp),
+	})
+}
+
 func (m *Maintainer) onNodeChanged() {
 	currentNodes := m.bootstrapper.GetAllNodes()
 
@@ -465,6 +565,7 @@ func (m *Maintainer) onHeartBeatRequest(msg *messaging.TargetMessage) {
 		m.checkpointTsByCapture[msg.From] = *req.Watermark
 	}
 	m.controller.HandleStatus(msg.From, req.Statuses)
+	m.updateDeadLetterRows(msg.From, req.DeadLetterRows)
 	if req.Err != nil {
 		log.Warn("dispatcher report an error",
 			zap.String("changefeed", m.id.Name()),
@@ -480,6 +581,18 @@ func (m *Maintainer) onHeartBeatRequest(msg *messaging.TargetMessage) {
 	}
 }
 
+func (m *Maintainer) updateDeadLetterRows(from node.ID, rows uint64) {
+	if m.deadLetterRowsByCapture[from] == rows {
+		return
+	}
+	m.deadLetterRowsByCapture[from] = rows
+	var total uint64
+	for _, rows := range m.deadLetterRowsByCapture {
+		total += rows
+	}
+	m.deadLetterRows.Store(total)
+}
+
 func (m *Maintainer) onBlockStateRequest(msg *messaging.TargetMessage) {
 	// the barrier is not initialized
 	if !m.bootstrapped {
@@ -522,6 +635,7 @@ func (m *Maintainer) onBootstrapDone(cachedResp map[node.ID]*heartbeatpb.Maintai
 		m.handleError(err)
 		return
 	}
+	barrier.LoadPendingDDLDecisions(m.config.PendingDDLDecisions)
 	m.barrier = barrier
 	m.bootstrapped = true
 }
@@ -618,6 +732,10 @@ func (m *Maintainer) getNewBootstrapFn() bootstrap.NewBootstrapMessageFn {
 		SyncPointInterval:  cfg.Config.SyncPointInterval,
 		SyncPointRetention: cfg.Config.SyncPointRetention,
 		MemoryQuota:        cfg.Config.MemoryQuota,
+		Consistent:         cfg.Config.Consistent,
+		Transforms:         cfg.Config.Transforms,
+		BDRMode:            util.GetOrZero(cfg.Config.BDRMode),
+		TiDBSourceID:       cfg.Config.Sink.TiDBSourceID,
 		// other fields are not necessary for maintainer
 	}
 	// cfgBytes only holds necessary fields to initialize a changefeed dispatcher.
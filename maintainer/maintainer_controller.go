@@ -14,7 +14,9 @@
 package maintainer
 
 import (
+	"bytes"
 	"context"
+	"sort"
 	"time"
 
 	"github.com/pingcap/log"
@@ -348,6 +350,131 @@ func (c *Controller) RemoveNode(id node.ID) {
 	c.operatorController.OnNodeRemoved(id)
 }
 
+// MoveTable moves all the spans of the table to the target node manually
+func (c *Controller) MoveTable(tableID int64, targetNode node.ID) error {
+	if _, ok := c.nodeManager.GetAliveNodes()[targetNode]; !ok {
+		return errors.Errorf("node %s is not alive", targetNode)
+	}
+	spans, err := c.getSchedulableSpans(tableID)
+	if err != nil {
+		return err
+	}
+	rejected := 0
+	for _, span := range spans {
+		if span.GetNodeID() == targetNode {
+			continue
+		}
+		if !c.operatorController.AddOperator(operator.NewMoveDispatcherOperator(c.replicationDB, span, span.GetNodeID(), targetNode)) {
+			rejected++
+		}
+	}
+	if rejected > 0 {
+		return errors.Errorf("%d of %d spans of table %d are not moved, they are being scheduled, try again later",
+			rejected, len(spans), tableID)
+	}
+	return nil
+}
+
+// SplitTable splits the spans of the table by the splitter manually,
+// and returns the spans which are being split.
+func (c *Controller) SplitTable(tableID int64) ([]*config.TableSpanReplicationStatus, error) {
+	if c.splitter == nil {
+		return nil, errors.New("split table is not enabled, please set enable-table-across-nodes to true")
+	}
+	spans, err := c.getSchedulableSpans(tableID)
+	if err != nil {
+		return nil, err
+	}
+	nodeCount := len(c.nodeManager.GetAliveNodes())
+	splitSpans := make([][]*heartbeatpb.TableSpan, 0, len(spans))
+	for _, span := range spans {
+		splitSpans = append(splitSpans, c.splitter.SplitSpans(context.Background(), span.Span, nodeCount))
+	}
+	var (
+		result   []*config.TableSpanReplicationStatus
+		rejected int
+	)
+	for i, span := range spans {
+		if len(splitSpans[i]) <= 1 {
+			continue
+		}
+		op := operator.NewSplitDispatcherOperator(c.replicationDB, span, span.GetNodeID(), splitSpans[i])
+		if !c.operatorController.AddOperator(op) {
+			rejected++
+			continue
+		}
+		spanStatus := newTableSpanStatus(span, replica.SpanStateScheduling)
+		spanStatus.Operator = op.Type()
+		result = append(result, spanStatus)
+	}
+	if rejected > 0 {
+		return result, errors.Errorf("%d spans of table %d are not split, they are being scheduled, try again later",
+			rejected, tableID)
+	}
+	if len(result) == 0 {
+		return nil, errors.Errorf("table %d can't be split, the region count and the written keys are below the thresholds", tableID)
+	}
+	return result, nil
+}
+
+// getSchedulableSpans returns all the spans of the table,
+// it returns error if any span is not replicating or is being scheduled by an operator.
+func (c *Controller) getSchedulableSpans(tableID int64) ([]*replica.SpanReplication, error) {
+	if tableID == heartbeatpb.DDLSpan.TableID {
+		return nil, errors.New("the table trigger event dispatcher can't be scheduled")
+	}
+	spans := c.replicationDB.GetTasksByTableIDs(tableID)
+	if len(spans) == 0 {
+		return nil, errors.Errorf("table %d is not found", tableID)
+	}
+	for _, span := range spans {
+		if span.GetNodeID() == "" || c.operatorController.GetOperator(span.ID) != nil {
+			return nil, errors.Errorf("table %d is being scheduled, try again later", tableID)
+		}
+	}
+	return spans, nil
+}
+
+// GetTableSpanStatus returns the replication status of all the spans, the spans with
+// smaller checkpoint ts are in the front, so the lagging spans can be found easily.
+func (c *Controller) GetTableSpanStatus() []*config.TableSpanReplicationStatus {
+	spans, states := c.replicationDB.GetAllTaskStates()
+	result := make([]*config.TableSpanReplicationStatus, 0, len(spans))
+	for idx, span := range spans {
+		spanStatus := newTableSpanStatus(span, states[idx])
+		if op := c.operatorController.GetOperator(span.ID); op != nil {
+			spanStatus.Operator = op.Type()
+		}
+		result = append(result, spanStatus)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CheckpointTs != result[j].CheckpointTs {
+			return result[i].CheckpointTs < result[j].CheckpointTs
+		}
+		if result[i].TableID != result[j].TableID {
+			return result[i].TableID < result[j].TableID
+		}
+		return bytes.Compare(result[i].StartKey, result[j].StartKey) < 0
+	})
+	return result
+}
+
+// newTableSpanStatus returns the replication status of the span without the operator.
+func newTableSpanStatus(span *replica.SpanReplication, state replica.SpanState) *config.TableSpanReplicationStatus {
+	status := span.GetStatus()
+	return &config.TableSpanReplicationStatus{
+		TableID:      span.Span.TableID,
+		SchemaID:     span.GetSchemaID(),
+		StartKey:     span.Span.StartKey,
+		EndKey:       span.Span.EndKey,
+		DispatcherID: span.ID.String(),
+		NodeID:       span.GetNodeID().String(),
+		State:        string(state),
+		CheckpointTs: status.CheckpointTs,
+		ResolvedTs:   status.ResolvedTs,
+	}
+}
+
 // ScheduleFinished return false if not all task are running in working state
 func (c *Controller) ScheduleFinished() bool {
 	return c.replicationDB.GetAbsentSize() == 0 && c.operatorController.OperatorSize() == 0
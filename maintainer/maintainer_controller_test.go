@@ -484,3 +484,142 @@ type mockTsoClient struct {
 func (m *mockTsoClient) GetTS(_ context.Context) (int64, int64, error) {
 	return m.phy, m.logic, m.err
 }
+
+func TestMoveAndSplitTable(t *testing.T) {
+	nodeManager := setNodeManagerAndMessageCenter()
+	nodeManager.GetAliveNodes()["node1"] = &node.Info{ID: "node1"}
+	nodeManager.GetAliveNodes()["node2"] = &node.Info{ID: "node2"}
+	tableTriggerEventDispatcherID := common.NewDispatcherID()
+	cfID := common.NewChangeFeedIDWithName("test")
+	tsoClient := &mockTsoClient{}
+	ddlSpan := replica.NewWorkingReplicaSet(cfID, tableTriggerEventDispatcherID,
+		tsoClient, heartbeatpb.DDLSpanSchemaID,
+		heartbeatpb.DDLSpan, &heartbeatpb.TableSpanStatus{
+			ID:              tableTriggerEventDispatcherID.ToPB(),
+			ComponentStatus: heartbeatpb.ComponentState_Working,
+			CheckpointTs:    1,
+		}, "node1")
+	s := NewController(cfID, 1, nil, tsoClient, nil, nil, nil, ddlSpan, 1000, 0)
+	spans := []*heartbeatpb.TableSpan{
+		{TableID: 1, StartKey: []byte("a"), EndKey: []byte("b")},
+		{TableID: 1, StartKey: []byte("b"), EndKey: []byte("c")},
+		{TableID: 2, StartKey: []byte("a"), EndKey: []byte("b")},
+	}
+	for _, span := range spans {
+		spanReplica := replica.NewReplicaSet(cfID, common.NewDispatcherID(), tsoClient, 1, span, 1)
+		spanReplica.SetNodeID("node1")
+		s.replicationDB.AddReplicatingSpan(spanReplica)
+	}
+
+	require.Error(t, s.MoveTable(1, "node3"))
+	require.Error(t, s.MoveTable(3, "node2"))
+	require.Error(t, s.MoveTable(heartbeatpb.DDLSpan.TableID, "node2"))
+	// all the spans of the table are moved
+	require.NoError(t, s.MoveTable(1, "node2"))
+	require.Equal(t, 2, s.operatorController.OperatorSize())
+	require.Equal(t, 2, s.replicationDB.GetSchedulingSize())
+	// the table is being scheduled
+	require.Error(t, s.MoveTable(1, "node1"))
+	// the span is already on the target node
+	require.NoError(t, s.MoveTable(2, "node1"))
+	require.Equal(t, 2, s.operatorController.OperatorSize())
+
+	// the splitter is not enabled
+	_, err := s.SplitTable(2)
+	require.Error(t, err)
+}
+
+func TestSplitTableReportsSplitSpans(t *testing.T) {
+	pdAPI := &mockPdAPI{
+		regions: make(map[int64][]pdutil.RegionInfo),
+	}
+	nodeManager := setNodeManagerAndMessageCenter()
+	nodeManager.GetAliveNodes()["node1"] = &node.Info{ID: "node1"}
+	nodeManager.GetAliveNodes()["node2"] = &node.Info{ID: "node2"}
+	tableTriggerEventDispatcherID := common.NewDispatcherID()
+	cfID := common.NewChangeFeedIDWithName("test")
+	tsoClient := &mockTsoClient{}
+	ddlSpan := replica.NewWorkingReplicaSet(cfID, tableTriggerEventDispatcherID,
+		tsoClient, heartbeatpb.DDLSpanSchemaID,
+		heartbeatpb.DDLSpan, &heartbeatpb.TableSpanStatus{
+			ID:              tableTriggerEventDispatcherID.ToPB(),
+			ComponentStatus: heartbeatpb.ComponentState_Working,
+			CheckpointTs:    1,
+		}, "node1")
+	defaultConfig := config.GetDefaultReplicaConfig().Clone()
+	defaultConfig.Scheduler = &config.ChangefeedSchedulerConfig{
+		EnableTableAcrossNodes: true,
+		RegionThreshold:        0,
+		WriteKeyThreshold:      1,
+	}
+	s := NewController(cfID, 1, pdAPI, tsoClient, nil, nil, defaultConfig, ddlSpan, 1000, 0)
+	pdAPI.regions[1] = []pdutil.RegionInfo{
+		pdutil.NewTestRegionInfo(2, []byte("a"), []byte("b"), uint64(1)),
+		pdutil.NewTestRegionInfo(3, []byte("b"), []byte("c"), uint64(1)),
+		pdutil.NewTestRegionInfo(4, []byte("c"), []byte("d"), uint64(1)),
+		pdutil.NewTestRegionInfo(5, []byte("e"), []byte("f"), uint64(1)),
+	}
+	span := spanz.TableIDToComparableSpan(1)
+	spanReplica := replica.NewReplicaSet(cfID, common.NewDispatcherID(), tsoClient, 1,
+		&heartbeatpb.TableSpan{TableID: 1, StartKey: span.StartKey, EndKey: span.EndKey}, 1)
+	spanReplica.SetNodeID("node1")
+	s.replicationDB.AddReplicatingSpan(spanReplica)
+
+	// the span which is being split is reported
+	spans, err := s.SplitTable(1)
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+	require.Equal(t, spanReplica.ID.String(), spans[0].DispatcherID)
+	require.Equal(t, "scheduling", spans[0].State)
+	require.Equal(t, "split", spans[0].Operator)
+	require.Equal(t, 1, s.operatorController.OperatorSize())
+
+	// the table is being scheduled
+	_, err = s.SplitTable(1)
+	require.Error(t, err)
+	require.Error(t, s.MoveTable(1, "node2"))
+}
+
+func TestGetTableSpanStatus(t *testing.T) {
+	nodeManager := setNodeManagerAndMessageCenter()
+	nodeManager.GetAliveNodes()["node1"] = &node.Info{ID: "node1"}
+	nodeManager.GetAliveNodes()["node2"] = &node.Info{ID: "node2"}
+	tableTriggerEventDispatcherID := common.NewDispatcherID()
+	cfID := common.NewChangeFeedIDWithName("test")
+	tsoClient := &mockTsoClient{}
+	ddlSpan := replica.NewWorkingReplicaSet(cfID, tableTriggerEventDispatcherID,
+		tsoClient, heartbeatpb.DDLSpanSchemaID,
+		heartbeatpb.DDLSpan, &heartbeatpb.TableSpanStatus{
+			ID:              tableTriggerEventDispatcherID.ToPB(),
+			ComponentStatus: heartbeatpb.ComponentState_Working,
+			CheckpointTs:    20,
+		}, "node1")
+	s := NewController(cfID, 1, nil, tsoClient, nil, nil, nil, ddlSpan, 1000, 0)
+	for tableID, checkpointTs := range map[int64]uint64{1: 30, 2: 10} {
+		span := &heartbeatpb.TableSpan{TableID: tableID, StartKey: []byte("a"), EndKey: []byte("b")}
+		dispatcherID := common.NewDispatcherID()
+		spanReplica := replica.NewWorkingReplicaSet(cfID, dispatcherID, tsoClient, 1, span,
+			&heartbeatpb.TableSpanStatus{
+				ID:              dispatcherID.ToPB(),
+				ComponentStatus: heartbeatpb.ComponentState_Working,
+				CheckpointTs:    checkpointTs,
+				ResolvedTs:      checkpointTs + 5,
+			}, "node1")
+		s.replicationDB.AddReplicatingSpan(spanReplica)
+	}
+	require.NoError(t, s.MoveTable(1, "node2"))
+
+	spans := s.GetTableSpanStatus()
+	require.Len(t, spans, 3)
+	// the spans are sorted by the checkpoint ts
+	require.Equal(t, int64(2), spans[0].TableID)
+	require.Equal(t, uint64(10), spans[0].CheckpointTs)
+	require.Equal(t, uint64(15), spans[0].ResolvedTs)
+	require.Equal(t, "node1", spans[0].NodeID)
+	require.Equal(t, "replicating", spans[0].State)
+	require.Equal(t, "", spans[0].Operator)
+	require.Equal(t, heartbeatpb.DDLSpan.TableID, spans[1].TableID)
+	require.Equal(t, int64(1), spans[2].TableID)
+	require.Equal(t, "scheduling", spans[2].State)
+	require.Equal(t, "move", spans[2].Operator)
+}
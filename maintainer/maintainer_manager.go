@@ -16,6 +16,7 @@ package maintainer
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 
@@ -30,10 +31,16 @@ import (
 	"github.com/pingcap/ticdc/utils/dynstream"
 	"github.com/pingcap/ticdc/utils/threadpool"
 	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/tikv/client-go/v2/tikv"
+	pd "github.com/tikv/pd/client"
 	"go.uber.org/zap"
 )
 
+// getSourceIDTimeout bounds the time to load the source id from PD, since it
+// blocks the message loop of the manager.
+const getSourceIDTimeout = 10 * time.Second
+
 // Manager is the manager of all changefeed maintainer in a ticdc watcher, each ticdc watcher will
 // start a Manager when the watcher is startup. the Manager should:
 // 1. handle bootstrap command from coordinator and return all changefeed maintainer status
@@ -120,6 +127,42 @@ func (m *Manager) recvMessages(ctx context.Context, msg *messaging.TargetMessage
 	case messaging.TypeCheckpointTsMessage:
 		req := msg.Message[0].(*heartbeatpb.CheckpointTsMessage)
 		return m.dispatcherMaintainerMessage(ctx, common.NewChangefeedIDFromPB(req.ChangefeedID), msg)
+	// receive table schedule request from coordinator
+	case messaging.TypeTableScheduleRequest:
+		req := msg.Message[0].(*messaging.TableScheduleRequest)
+		cfID := common.NewChangefeedIDFromPB(req.ChangefeedID)
+		if _, ok := m.maintainers.Load(cfID); !ok {
+			m.sendResponseToCoordinator(msg.From, &messaging.TableScheduleResponse{
+				RequestID: req.RequestID,
+				Error:     fmt.Sprintf("maintainer of changefeed %s is not found", cfID.Name()),
+			})
+			return nil
+		}
+		return m.dispatcherMaintainerMessage(ctx, cfID, msg)
+	// receive table status request from coordinator
+	case messaging.TypeTableStatusRequest:
+		req := msg.Message[0].(*messaging.TableStatusRequest)
+		cfID := common.NewChangefeedIDFromPB(req.ChangefeedID)
+		if _, ok := m.maintainers.Load(cfID); !ok {
+			m.sendResponseToCoordinator(msg.From, &messaging.TableStatusResponse{
+				RequestID: req.RequestID,
+				Error:     fmt.Sprintf("maintainer of changefeed %s is not found", cfID.Name()),
+			})
+			return nil
+		}
+		return m.dispatcherMaintainerMessage(ctx, cfID, msg)
+	// receive pending ddl request from coordinator
+	case messaging.TypePendingDDLRequest:
+		req := msg.Message[0].(*messaging.PendingDDLRequest)
+		cfID := common.NewChangefeedIDFromPB(req.ChangefeedID)
+		if _, ok := m.maintainers.Load(cfID); !ok {
+			m.sendResponseToCoordinator(msg.From, &messaging.PendingDDLResponse{
+				RequestID: req.RequestID,
+				Error:     fmt.Sprintf("maintainer of changefeed %s is not found", cfID.Name()),
+			})
+			return nil
+		}
+		return m.dispatcherMaintainerMessage(ctx, cfID, msg)
 	default:
 		log.Panic("unknown message type", zap.Any("message", msg.Message))
 	}
@@ -227,6 +270,17 @@ func (m *Manager) onAddMaintainerRequest(req *heartbeatpb.AddMaintainerRequest)
 	if err != nil {
 		log.Panic("decode changefeed fail", zap.Error(err))
 	}
+	// The source id of the upstream is not persisted in the changefeed info,
+	// load it from PD every time the maintainer is created.
+	if pdClient, ok := m.tsoClient.(pd.Client); ok && cfConfig.Config.Sink != nil {
+		sourceID, err := getSourceID(pdClient)
+		if err != nil {
+			log.Warn("get source id failed, coordinator will retry later",
+				zap.String("changefeed", cfID.Name()), zap.Error(err))
+			return
+		}
+		cfConfig.Config.Sink.TiDBSourceID = sourceID
+	}
 	cf = NewMaintainer(cfID, m.conf, cfConfig, m.selfNode, m.stream, m.taskScheduler,
 		m.pdAPI, m.tsoClient, m.regionCache,
 		req.CheckpointTs)
@@ -239,6 +293,22 @@ func (m *Manager) onAddMaintainerRequest(req *heartbeatpb.AddMaintainerRequest)
 	m.stream.In() <- &Event{changefeedID: cfID, eventType: EventInit}
 }
 
+// getSourceID loads the source id of the upstream from PD with a few retries,
+// the error is returned if it can't be loaded in getSourceIDTimeout.
+func getSourceID(pdClient pd.Client) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), getSourceIDTimeout)
+	defer cancel()
+	var sourceID uint64
+	err := retry.Do(ctx, func() error {
+		var err error
+		sourceID, err = pdutil.GetSourceID(ctx, pdClient)
+		return err
+	}, retry.WithBackoffBaseDelay(100),
+		retry.WithBackoffMaxDelay(1000),
+		retry.WithMaxTries(3))
+	return sourceID, err
+}
+
 func (m *Manager) onRemoveMaintainerRequest(msg *messaging.TargetMessage) *heartbeatpb.MaintainerStatus {
 	req := msg.Message[0].(*heartbeatpb.RemoveMaintainerRequest)
 	cfID := common.NewChangefeedIDFromPB(req.GetId())
@@ -296,6 +366,14 @@ func (m *Manager) onDispatchMaintainerRequest(
 	return nil
 }
 
+func (m *Manager) sendResponseToCoordinator(to node.ID, resp messaging.IOTypeT) {
+	err := m.mc.SendCommand(messaging.NewSingleTargetMessage(to, messaging.CoordinatorTopic, resp))
+	if err != nil {
+		log.Warn("failed to send response to coordinator",
+			zap.Any("response", resp), zap.Error(err))
+	}
+}
+
 func (m *Manager) sendHeartbeat() {
 	if m.coordinatorVersion > 0 {
 		response := &heartbeatpb.MaintainerHeartbeat{}
@@ -16,6 +16,7 @@ package maintainer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net"
 	"testing"
 	"time"
@@ -36,6 +37,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
+	pd "github.com/tikv/pd/client"
 	"google.golang.org/grpc"
 )
 
@@ -343,6 +345,38 @@ func TestStopNotExistsMaintainer(t *testing.T) {
 	cancel()
 }
 
+func TestGetSourceIDWithRetry(t *testing.T) {
+	// the source id is loaded after the transient errors
+	pdClient := &mockSourceIDPDClient{failures: 2, sourceID: "2"}
+	sourceID, err := getSourceID(pdClient)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), sourceID)
+	require.Equal(t, 3, pdClient.calls)
+
+	// the error is returned after the retries instead of blocking the manager
+	pdClient = &mockSourceIDPDClient{failures: 10, sourceID: "2"}
+	_, err = getSourceID(pdClient)
+	require.Error(t, err)
+	require.Equal(t, 3, pdClient.calls)
+}
+
+type mockSourceIDPDClient struct {
+	pd.Client
+	failures int
+	calls    int
+	sourceID string
+}
+
+func (c *mockSourceIDPDClient) LoadGlobalConfig(
+	_ context.Context, names []string, _ string,
+) ([]pd.GlobalConfigItem, int64, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, 0, errors.New("pd is unavailable")
+	}
+	return []pd.GlobalConfigItem{{Name: names[0], Value: c.sourceID}}, 0, nil
+}
+
 type mockSchemaStore struct {
 	schemastore.SchemaStore
 	tables []commonEvent.Table
@@ -39,6 +39,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
 
@@ -311,3 +312,20 @@ func TestMaintainerSchedule(t *testing.T) {
 	require.Equal(t, tableSize,
 		maintainer.controller.GetTaskSizeByNodeID(n.ID))
 }
+
+func TestMaintainerDeadLetterRows(t *testing.T) {
+	m := &Maintainer{
+		id:                      common.NewChangeFeedIDWithName("test"),
+		watermark:               heartbeatpb.NewMaxWatermark(),
+		runningErrors:           map[node.ID]*heartbeatpb.RunningError{},
+		deadLetterRowsByCapture: make(map[node.ID]uint64),
+		deadLetterRows:          atomic.NewUint64(0),
+	}
+	m.updateDeadLetterRows("node1", 2)
+	m.updateDeadLetterRows("node2", 3)
+	require.Equal(t, uint64(5), m.GetMaintainerStatus().DeadLetterRows)
+
+	// the rows reported by a node are the total rows of its sink
+	m.updateDeadLetterRows("node1", 4)
+	require.Equal(t, uint64(7), m.GetMaintainerStatus().DeadLetterRows)
+}
@@ -124,18 +124,20 @@ func (oc *Controller) AddOperator(op operator.Operator[common.DispatcherID, *hea
 	oc.lock.Lock()
 	defer oc.lock.Unlock()
 
-	if _, ok := oc.operators[op.ID()]; ok {
-		log.Info("add operator failed, operator already exists",
-			zap.String("changefeed", oc.changefeedID.Name()),
-			zap.String("operator", op.String()))
-		return false
-	}
-	span := oc.replicationDB.GetTaskByID(op.ID())
-	if span == nil {
-		log.Warn("add operator failed, span not found",
-			zap.String("changefeed", oc.changefeedID.Name()),
-			zap.String("operator", op.String()))
-		return false
+	for _, id := range affectedSpans(op) {
+		if _, ok := oc.operators[id]; ok {
+			log.Info("add operator failed, operator already exists",
+				zap.String("changefeed", oc.changefeedID.Name()),
+				zap.String("operator", op.String()))
+			return false
+		}
+		span := oc.replicationDB.GetTaskByID(id)
+		if span == nil {
+			log.Warn("add operator failed, span not found",
+				zap.String("changefeed", oc.changefeedID.Name()),
+				zap.String("operator", op.String()))
+			return false
+		}
 	}
 	oc.pushOperator(op)
 	return true
@@ -164,7 +166,13 @@ func (oc *Controller) OnNodeRemoved(n node.ID) {
 			oc.replicationDB.MarkSpanAbsent(span)
 		}
 	}
+	// an operator may be registered for more than one span, notify it only once
+	notified := make(map[*operator.OperatorWithTime[common.DispatcherID, *heartbeatpb.TableSpanStatus]]struct{}, len(oc.operators))
 	for _, op := range oc.operators {
+		if _, ok := notified[op]; ok {
+			continue
+		}
+		notified[op] = struct{}{}
 		op.OP.OnNodeRemove(n)
 	}
 }
@@ -181,11 +189,16 @@ func (oc *Controller) GetOperator(id common.DispatcherID) operator.Operator[comm
 	}
 }
 
-// OperatorSize returns the number of operators in the controller.
+// OperatorSize returns the number of operators in the controller,
+// the operator registered for more than one span is counted once.
 func (oc *Controller) OperatorSize() int {
 	oc.lock.RLock()
 	defer oc.lock.RUnlock()
-	return len(oc.operators)
+	ops := make(map[*operator.OperatorWithTime[common.DispatcherID, *heartbeatpb.TableSpanStatus]]struct{}, len(oc.operators))
+	for _, op := range oc.operators {
+		ops[op] = struct{}{}
+	}
+	return len(ops)
 }
 
 // pollQueueingOperator returns the operator need to be executed,
@@ -207,7 +220,7 @@ func (oc *Controller) pollQueueingOperator() (operator.Operator[common.Dispatche
 	if op.IsFinished() {
 		op.PostFinish()
 		item.Removed = true
-		delete(oc.operators, opID)
+		oc.deleteOperator(op)
 		metrics.FinishedOperatorCount.WithLabelValues(model.DefaultNamespace, oc.changefeedID.Name(), op.Type()).Inc()
 		metrics.OperatorDuration.WithLabelValues(model.DefaultNamespace, oc.changefeedID.Name(), op.Type()).Observe(time.Since(item.EnqueueTime).Seconds())
 		log.Info("operator finished",
@@ -238,7 +251,7 @@ func (oc *Controller) removeReplicaSet(op *RemoveDispatcherOperator) {
 		old.OP.OnTaskRemoved()
 		old.OP.PostFinish()
 		old.Removed = true
-		delete(oc.operators, op.ID())
+		oc.deleteOperator(old.OP)
 	}
 	oc.pushOperator(op)
 }
@@ -249,8 +262,31 @@ func (oc *Controller) pushOperator(op operator.Operator[common.DispatcherID, *he
 		zap.String("changefeed", oc.changefeedID.Name()),
 		zap.String("operator", op.String()))
 	withTime := operator.NewOperatorWithTime(op, time.Now())
-	oc.operators[op.ID()] = withTime
+	for _, id := range affectedSpans(op) {
+		oc.operators[id] = withTime
+	}
 	op.Start()
 	heap.Push(&oc.runningQueue, withTime)
 	metrics.CreatedOperatorCount.WithLabelValues(model.DefaultNamespace, oc.changefeedID.Name(), op.Type()).Inc()
 }
+
+// deleteOperator removes the operator from the operators map for all the spans it affects.
+func (oc *Controller) deleteOperator(op operator.Operator[common.DispatcherID, *heartbeatpb.TableSpanStatus]) {
+	for _, id := range affectedSpans(op) {
+		delete(oc.operators, id)
+	}
+}
+
+// multiSpanOperator is an operator which affects more than one span, like the merge operator,
+// it's registered in the controller for all the spans, so the status of any span is delivered to it.
+type multiSpanOperator interface {
+	AffectedSpans() []common.DispatcherID
+}
+
+// affectedSpans returns the ids of the spans affected by the operator.
+func affectedSpans(op operator.Operator[common.DispatcherID, *heartbeatpb.TableSpanStatus]) []common.DispatcherID {
+	if m, ok := op.(multiSpanOperator); ok {
+		return m.AffectedSpans()
+	}
+	return []common.DispatcherID{op.ID()}
+}
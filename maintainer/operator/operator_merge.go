@@ -0,0 +1,209 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/maintainer/replica"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/messaging"
+	"github.com/pingcap/ticdc/pkg/node"
+	"go.uber.org/zap"
+)
+
+// MergeDispatcherOperator is an operator to remove some adjacent table spans of the same table
+// from dispatchers, and then add a new span which covers all of them to the replication db.
+// It's the reverse of the SplitDispatcherOperator.
+type MergeDispatcherOperator struct {
+	db          *replica.ReplicationDB
+	replicaSets []*replica.SpanReplication
+	// originNodes is the nodes of the replica sets when the operator is created
+	originNodes []node.ID
+	// removed marks whether the dispatcher of the replica set is removed
+	removed []bool
+	// checkpointTs is the checkpoint ts of the removed dispatchers
+	checkpointTs  []uint64
+	scheduleIndex int
+	mergedSpan    *heartbeatpb.TableSpan
+
+	finished    atomic.Bool
+	taskRemoved bool
+
+	lck sync.Mutex
+}
+
+// NewMergeDispatcherOperator creates a new MergeDispatcherOperator,
+// the replica sets must belong to the same table, and they are sorted and adjacent.
+func NewMergeDispatcherOperator(db *replica.ReplicationDB,
+	replicaSets []*replica.SpanReplication) *MergeDispatcherOperator {
+	originNodes := make([]node.ID, 0, len(replicaSets))
+	for _, replicaSet := range replicaSets {
+		originNodes = append(originNodes, replicaSet.GetNodeID())
+	}
+	first, last := replicaSets[0], replicaSets[len(replicaSets)-1]
+	return &MergeDispatcherOperator{
+		db:           db,
+		replicaSets:  replicaSets,
+		originNodes:  originNodes,
+		removed:      make([]bool, len(replicaSets)),
+		checkpointTs: make([]uint64, len(replicaSets)),
+		mergedSpan: &heartbeatpb.TableSpan{
+			TableID:  first.Span.TableID,
+			StartKey: first.Span.StartKey,
+			EndKey:   last.Span.EndKey,
+		},
+	}
+}
+
+func (m *MergeDispatcherOperator) Start() {
+	m.lck.Lock()
+	defer m.lck.Unlock()
+
+	for _, replicaSet := range m.replicaSets {
+		m.db.MarkSpanScheduling(replicaSet)
+	}
+}
+
+func (m *MergeDispatcherOperator) OnNodeRemove(n node.ID) {
+	m.lck.Lock()
+	defer m.lck.Unlock()
+
+	for idx, replicaSet := range m.replicaSets {
+		if m.removed[idx] || m.originNodes[idx] != n {
+			continue
+		}
+		// the dispatcher is gone with the node, use the last reported checkpoint ts
+		log.Info("origin node is removed",
+			zap.String("replicaSet", replicaSet.ID.String()))
+		m.markRemoved(idx, replicaSet.GetStatus().CheckpointTs)
+	}
+}
+
+// ID returns the id of the first replica set, the operator is also
+// registered for the other replica sets by AffectedSpans.
+func (m *MergeDispatcherOperator) ID() common.DispatcherID {
+	return m.replicaSets[0].ID
+}
+
+// AffectedSpans returns the ids of all the replica sets to be merged.
+func (m *MergeDispatcherOperator) AffectedSpans() []common.DispatcherID {
+	ids := make([]common.DispatcherID, 0, len(m.replicaSets))
+	for _, replicaSet := range m.replicaSets {
+		ids = append(ids, replicaSet.ID)
+	}
+	return ids
+}
+
+func (m *MergeDispatcherOperator) IsFinished() bool {
+	return m.finished.Load()
+}
+
+func (m *MergeDispatcherOperator) Check(from node.ID, status *heartbeatpb.TableSpanStatus) {
+	m.lck.Lock()
+	defer m.lck.Unlock()
+
+	id := common.NewDispatcherIDFromPB(status.ID)
+	for idx, replicaSet := range m.replicaSets {
+		if replicaSet.ID != id {
+			continue
+		}
+		if !m.removed[idx] && from == m.originNodes[idx] &&
+			status.ComponentStatus != heartbeatpb.ComponentState_Working {
+			log.Info("replica set removed from origin node",
+				zap.Uint64("checkpointTs", status.CheckpointTs),
+				zap.String("replicaSet", replicaSet.ID.String()))
+			m.markRemoved(idx, status.CheckpointTs)
+		}
+		return
+	}
+}
+
+// Schedule sends the remove message to the dispatchers which are not removed yet in turn.
+func (m *MergeDispatcherOperator) Schedule() *messaging.TargetMessage {
+	m.lck.Lock()
+	defer m.lck.Unlock()
+
+	for i := 0; i < len(m.replicaSets); i++ {
+		idx := (m.scheduleIndex + i) % len(m.replicaSets)
+		if m.removed[idx] {
+			continue
+		}
+		m.scheduleIndex = idx + 1
+		return m.replicaSets[idx].NewRemoveDispatcherMessage(m.originNodes[idx])
+	}
+	return nil
+}
+
+// OnTaskRemoved is called when the task is removed by ddl
+func (m *MergeDispatcherOperator) OnTaskRemoved() {
+	m.lck.Lock()
+	defer m.lck.Unlock()
+
+	log.Info("task removed", zap.String("operator", m.String()))
+	m.taskRemoved = true
+	m.finished.Store(true)
+}
+
+func (m *MergeDispatcherOperator) PostFinish() {
+	m.lck.Lock()
+	defer m.lck.Unlock()
+
+	if m.taskRemoved {
+		return
+	}
+	// the merged span starts from the minimum checkpoint ts of the removed dispatchers,
+	// so no event is lost for any of the old spans.
+	checkpointTs := uint64(math.MaxUint64)
+	for _, ts := range m.checkpointTs {
+		if ts < checkpointTs {
+			checkpointTs = ts
+		}
+	}
+	if merged := m.db.MergeReplicaSets(m.replicaSets, checkpointTs); merged != nil {
+		log.Info("merge dispatcher operator finished",
+			zap.String("id", m.ID().String()),
+			zap.String("merged", merged.ID.String()),
+			zap.Uint64("checkpointTs", checkpointTs))
+	}
+}
+
+func (m *MergeDispatcherOperator) String() string {
+	return fmt.Sprintf("merge dispatcher operator: %s, spans:%d, mergedSpan:[%s,%s]",
+		m.ID(), len(m.replicaSets),
+		hex.EncodeToString(m.mergedSpan.StartKey), hex.EncodeToString(m.mergedSpan.EndKey))
+}
+
+func (m *MergeDispatcherOperator) Type() string {
+	return "merge"
+}
+
+// markRemoved marks the dispatcher of the replica set removed,
+// and the operator is finished when all the dispatchers are removed.
+func (m *MergeDispatcherOperator) markRemoved(idx int, checkpointTs uint64) {
+	m.removed[idx] = true
+	m.checkpointTs[idx] = checkpointTs
+	for _, removed := range m.removed {
+		if !removed {
+			return
+		}
+	}
+	m.finished.Store(true)
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/maintainer/replica"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeDispatcherOperator(t *testing.T) {
+	cfID := common.NewChangeFeedIDWithName("test")
+	ddlDispatcherID := common.NewDispatcherID()
+	ddlSpan := replica.NewWorkingReplicaSet(cfID, ddlDispatcherID, nil, heartbeatpb.DDLSpanSchemaID,
+		heartbeatpb.DDLSpan, &heartbeatpb.TableSpanStatus{
+			ID:              ddlDispatcherID.ToPB(),
+			ComponentStatus: heartbeatpb.ComponentState_Working,
+			CheckpointTs:    1,
+		}, "node1")
+	db := replica.NewReplicaSetDB(cfID, ddlSpan)
+	oc := NewOperatorController(cfID, nil, db, 10)
+
+	keys := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	nodes := []string{"node1", "node2"}
+	var spans []*replica.SpanReplication
+	for i := 0; i < 2; i++ {
+		span := replica.NewReplicaSet(cfID, common.NewDispatcherID(), nil, 1,
+			&heartbeatpb.TableSpan{TableID: 1, StartKey: keys[i], EndKey: keys[i+1]}, 10)
+		db.AddAbsentReplicaSet(span)
+		db.BindSpanToNode("", "node1", span)
+		db.MarkSpanReplicating(span)
+		spans = append(spans, span)
+	}
+	db.BindSpanToNode("node1", "node2", spans[1])
+	db.MarkSpanReplicating(spans[1])
+
+	op := NewMergeDispatcherOperator(db, spans)
+	require.True(t, oc.AddOperator(op))
+	require.Equal(t, 2, db.GetSchedulingSize())
+	require.Equal(t, 1, oc.OperatorSize())
+	// the operator is registered for all the spans
+	require.Equal(t, op, oc.GetOperator(spans[0].ID))
+	require.Equal(t, op, oc.GetOperator(spans[1].ID))
+	require.False(t, oc.AddOperator(NewMergeDispatcherOperator(db, spans[1:])))
+
+	// remove messages are sent to the nodes in turn
+	require.Equal(t, nodes[0], op.Schedule().To.String())
+	require.Equal(t, nodes[1], op.Schedule().To.String())
+
+	oc.UpdateOperatorStatus(spans[0].ID, "node1", &heartbeatpb.TableSpanStatus{
+		ID:              spans[0].ID.ToPB(),
+		ComponentStatus: heartbeatpb.ComponentState_Stopped,
+		CheckpointTs:    20,
+	})
+	require.False(t, op.IsFinished())
+	require.Equal(t, nodes[1], op.Schedule().To.String())
+	// status from other nodes is ignored
+	oc.UpdateOperatorStatus(spans[1].ID, "node1", &heartbeatpb.TableSpanStatus{
+		ID:              spans[1].ID.ToPB(),
+		ComponentStatus: heartbeatpb.ComponentState_Stopped,
+		CheckpointTs:    15,
+	})
+	require.False(t, op.IsFinished())
+	oc.UpdateOperatorStatus(spans[1].ID, "node2", &heartbeatpb.TableSpanStatus{
+		ID:              spans[1].ID.ToPB(),
+		ComponentStatus: heartbeatpb.ComponentState_Stopped,
+		CheckpointTs:    15,
+	})
+	require.True(t, op.IsFinished())
+
+	_, next := oc.pollQueueingOperator()
+	require.True(t, next)
+	require.Equal(t, 0, oc.OperatorSize())
+	tasks := db.GetTasksByTableIDs(1)
+	require.Len(t, tasks, 1)
+	require.Equal(t, 1, db.GetAbsentSize())
+	require.True(t, tasks[0].Span.Equal(&heartbeatpb.TableSpan{TableID: 1, StartKey: keys[0], EndKey: keys[2]}))
+	// the merged span starts from the minimum checkpoint ts
+	require.Equal(t, uint64(15), tasks[0].GetStatus().CheckpointTs)
+}
+
+func TestMergeDispatcherOperatorNodeRemoved(t *testing.T) {
+	cfID := common.NewChangeFeedIDWithName("test")
+	ddlDispatcherID := common.NewDispatcherID()
+	ddlSpan := replica.NewWorkingReplicaSet(cfID, ddlDispatcherID, nil, heartbeatpb.DDLSpanSchemaID,
+		heartbeatpb.DDLSpan, &heartbeatpb.TableSpanStatus{
+			ID:              ddlDispatcherID.ToPB(),
+			ComponentStatus: heartbeatpb.ComponentState_Working,
+			CheckpointTs:    1,
+		}, "node1")
+	db := replica.NewReplicaSetDB(cfID, ddlSpan)
+	oc := NewOperatorController(cfID, nil, db, 10)
+
+	keys := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	var spans []*replica.SpanReplication
+	for i := 0; i < 2; i++ {
+		span := replica.NewReplicaSet(cfID, common.NewDispatcherID(), nil, 1,
+			&heartbeatpb.TableSpan{TableID: 1, StartKey: keys[i], EndKey: keys[i+1]}, uint64(10+i))
+		db.AddAbsentReplicaSet(span)
+		db.BindSpanToNode("", "node1", span)
+		db.MarkSpanReplicating(span)
+		spans = append(spans, span)
+	}
+	op := NewMergeDispatcherOperator(db, spans)
+	require.True(t, oc.AddOperator(op))
+
+	// the last reported checkpoint ts is used if the node is removed
+	oc.OnNodeRemoved("node1")
+	require.True(t, op.IsFinished())
+	require.Equal(t, 2, db.GetSchedulingSize())
+	_, next := oc.pollQueueingOperator()
+	require.True(t, next)
+	tasks := db.GetTasksByTableIDs(1)
+	require.Len(t, tasks, 1)
+	require.Equal(t, uint64(10), tasks[0].GetStatus().CheckpointTs)
+
+	// the table is dropped before the merge operator is finished
+	spans = spans[:0]
+	for i := 0; i < 2; i++ {
+		span := replica.NewReplicaSet(cfID, common.NewDispatcherID(), nil, 2,
+			&heartbeatpb.TableSpan{TableID: 2, StartKey: keys[i], EndKey: keys[i+1]}, 10)
+		db.AddAbsentReplicaSet(span)
+		db.BindSpanToNode("", "node2", span)
+		db.MarkSpanReplicating(span)
+		spans = append(spans, span)
+	}
+	op = NewMergeDispatcherOperator(db, spans)
+	require.True(t, oc.AddOperator(op))
+	oc.RemoveTasksByTableIDs(2)
+	require.True(t, op.IsFinished())
+	require.False(t, db.IsTableExists(2))
+	// only the remove operators are left
+	require.Equal(t, 2, oc.OperatorSize())
+	require.IsType(t, &RemoveDispatcherOperator{}, oc.GetOperator(spans[0].ID))
+	require.IsType(t, &RemoveDispatcherOperator{}, oc.GetOperator(spans[1].ID))
+}
@@ -15,6 +15,7 @@ package replica
 
 import (
 	"sync"
+	"time"
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/heartbeatpb"
@@ -42,10 +43,22 @@ type ReplicationDB struct {
 
 	ddlSpan *SpanReplication
 
+	// splitTime is the last time the spans of a table are split
+	splitTime map[int64]time.Time
+
 	// LOCK protects the above maps
 	lock sync.RWMutex
 }
 
+// SpanState is the scheduling state of a span in the ReplicationDB
+type SpanState string
+
+const (
+	SpanStateAbsent      SpanState = "absent"
+	SpanStateScheduling  SpanState = "scheduling"
+	SpanStateReplicating SpanState = "replicating"
+)
+
 // NewReplicaSetDB creates a new ReplicationDB and initializes the maps
 func NewReplicaSetDB(changefeedID common.ChangeFeedID, ddlSpan *SpanReplication) *ReplicationDB {
 	db := &ReplicationDB{changefeedID: changefeedID, ddlSpan: ddlSpan}
@@ -202,6 +215,27 @@ func (db *ReplicationDB) GetAllTasks() []*SpanReplication {
 	return stms
 }
 
+// GetAllTaskStates returns all the spans in the db and the scheduling state of each span,
+// the ddl span is not scheduled, it's treated as replicating.
+func (db *ReplicationDB) GetAllTaskStates() ([]*SpanReplication, []SpanState) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	var stms = make([]*SpanReplication, 0, len(db.allTasks))
+	var states = make([]SpanState, 0, len(db.allTasks))
+	for id, stm := range db.allTasks {
+		state := SpanStateReplicating
+		if _, ok := db.absent[id]; ok {
+			state = SpanStateAbsent
+		} else if _, ok := db.scheduling[id]; ok {
+			state = SpanStateScheduling
+		}
+		stms = append(stms, stm)
+		states = append(states, state)
+	}
+	return stms, states
+}
+
 // IsTableExists checks if the table exists in the db
 func (db *ReplicationDB) IsTableExists(tableID int64) bool {
 	db.lock.RLock()
@@ -317,9 +351,56 @@ func (db *ReplicationDB) ReplaceReplicaSet(old *SpanReplication, newSpans []*hea
 	// remove and insert the new replica set
 	db.removeSpanUnLock(old)
 	db.addAbsentReplicaSetUnLock(news...)
+	db.splitTime[old.Span.TableID] = time.Now()
 	return true
 }
 
+// GetLastSplitTime returns the last time the spans of the table are split,
+// it's zero if the table is not split since it's added.
+func (db *ReplicationDB) GetLastSplitTime(tableID int64) time.Time {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return db.splitTime[tableID]
+}
+
+// MergeReplicaSets replaces the old replica sets with one new replica set which covers all of them,
+// the old replica sets must belong to the same table, and they are sorted and adjacent.
+// It returns nil if any of the old replica sets is not found.
+func (db *ReplicationDB) MergeReplicaSets(olds []*SpanReplication, checkpointTs uint64) *SpanReplication {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if len(olds) == 0 {
+		return nil
+	}
+	for _, old := range olds {
+		if _, ok := db.allTasks[old.ID]; !ok {
+			log.Warn("old replica set not found, skip merge",
+				zap.String("changefeed", db.changefeedID.Name()),
+				zap.String("span", old.ID.String()))
+			return nil
+		}
+	}
+
+	first, last := olds[0], olds[len(olds)-1]
+	merged := NewReplicaSet(
+		first.ChangefeedID,
+		common.NewDispatcherID(),
+		first.GetTsoClient(),
+		first.GetSchemaID(),
+		&heartbeatpb.TableSpan{
+			TableID:  first.Span.TableID,
+			StartKey: first.Span.StartKey,
+			EndKey:   last.Span.EndKey,
+		}, checkpointTs)
+
+	// remove and insert the new replica set
+	db.removeSpanUnLock(olds...)
+	db.addAbsentReplicaSetUnLock(merged)
+	return merged
+}
+
 // AddReplicatingSpan adds a replicating the replicating map, that means the task is already scheduled to a dispatcher
 func (db *ReplicationDB) AddReplicatingSpan(task *SpanReplication) {
 	db.lock.Lock()
@@ -479,6 +560,7 @@ func (db *ReplicationDB) removeSpanUnLock(spans ...*SpanReplication) {
 		}
 		if len(db.tableTasks[tableID]) == 0 {
 			delete(db.tableTasks, tableID)
+			delete(db.splitTime, tableID)
 		}
 		nodeMap := db.nodeTasks[nodeID]
 		delete(nodeMap, span.ID)
@@ -542,6 +624,7 @@ func (db *ReplicationDB) reset() {
 	db.replicating = make(map[common.DispatcherID]*SpanReplication)
 	db.scheduling = make(map[common.DispatcherID]*SpanReplication)
 	db.absent = make(map[common.DispatcherID]*SpanReplication)
+	db.splitTime = make(map[int64]time.Time)
 }
 
 // putDDLDispatcher
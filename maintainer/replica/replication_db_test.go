@@ -0,0 +1,132 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replica
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newDBForTest(cfID common.ChangeFeedID) *ReplicationDB {
+	ddlDispatcherID := common.NewDispatcherID()
+	ddlSpan := NewWorkingReplicaSet(cfID, ddlDispatcherID, nil, heartbeatpb.DDLSpanSchemaID,
+		heartbeatpb.DDLSpan, &heartbeatpb.TableSpanStatus{
+			ID:              ddlDispatcherID.ToPB(),
+			ComponentStatus: heartbeatpb.ComponentState_Working,
+			CheckpointTs:    1,
+		}, "node1")
+	return NewReplicaSetDB(cfID, ddlSpan)
+}
+
+func TestMergeReplicaSets(t *testing.T) {
+	cfID := common.NewChangeFeedIDWithName("test")
+	db := newDBForTest(cfID)
+
+	keys := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}
+	var olds []*SpanReplication
+	for i := 0; i < 3; i++ {
+		span := NewReplicaSet(cfID, common.NewDispatcherID(), nil, 1,
+			&heartbeatpb.TableSpan{TableID: 1, StartKey: keys[i], EndKey: keys[i+1]}, 10)
+		db.AddAbsentReplicaSet(span)
+		db.BindSpanToNode("", "node1", span)
+		db.MarkSpanReplicating(span)
+		olds = append(olds, span)
+	}
+	require.Equal(t, 3, db.GetReplicatingSize())
+	require.Equal(t, 3, db.GetTaskSizeByNodeID("node1"))
+
+	// the spans are scheduling while the dispatchers are removed
+	for _, span := range olds {
+		db.MarkSpanScheduling(span)
+	}
+	require.Equal(t, 0, db.GetReplicatingSize())
+	require.Equal(t, 3, db.GetSchedulingSize())
+
+	merged := db.MergeReplicaSets(olds, 8)
+	require.NotNil(t, merged)
+	require.Equal(t, 0, db.GetSchedulingSize())
+	require.Equal(t, 1, db.GetAbsentSize())
+	require.Equal(t, 0, db.GetTaskSizeByNodeID("node1"))
+	for _, span := range olds {
+		require.Nil(t, db.GetTaskByID(span.ID))
+	}
+	tasks := db.GetTasksByTableIDs(1)
+	require.Len(t, tasks, 1)
+	require.Equal(t, merged.ID, tasks[0].ID)
+	require.True(t, merged.Span.Equal(&heartbeatpb.TableSpan{TableID: 1, StartKey: keys[0], EndKey: keys[3]}))
+	require.Equal(t, uint64(8), merged.GetStatus().CheckpointTs)
+	require.Equal(t, "", merged.GetNodeID().String())
+	require.Equal(t, 1, db.GetTaskSizeBySchemaID(1))
+
+	// merge the removed spans again
+	require.Nil(t, db.MergeReplicaSets(olds, 8))
+	require.Len(t, db.GetTasksByTableIDs(1), 1)
+}
+
+func TestGetAllTaskStates(t *testing.T) {
+	cfID := common.NewChangeFeedIDWithName("test")
+	db := newDBForTest(cfID)
+
+	absent := NewReplicaSet(cfID, common.NewDispatcherID(), nil, 1,
+		&heartbeatpb.TableSpan{TableID: 1, StartKey: []byte("a"), EndKey: []byte("b")}, 10)
+	db.AddAbsentReplicaSet(absent)
+	scheduling := NewReplicaSet(cfID, common.NewDispatcherID(), nil, 1,
+		&heartbeatpb.TableSpan{TableID: 2, StartKey: []byte("a"), EndKey: []byte("b")}, 10)
+	db.AddAbsentReplicaSet(scheduling)
+	db.BindSpanToNode("", "node1", scheduling)
+	replicating := NewReplicaSet(cfID, common.NewDispatcherID(), nil, 1,
+		&heartbeatpb.TableSpan{TableID: 3, StartKey: []byte("a"), EndKey: []byte("b")}, 10)
+	replicating.SetNodeID("node1")
+	db.AddReplicatingSpan(replicating)
+
+	spans, states := db.GetAllTaskStates()
+	require.Len(t, spans, 4)
+	require.Len(t, states, 4)
+	expected := map[common.DispatcherID]SpanState{
+		db.ddlSpan.ID:  SpanStateReplicating,
+		absent.ID:      SpanStateAbsent,
+		scheduling.ID:  SpanStateScheduling,
+		replicating.ID: SpanStateReplicating,
+	}
+	for idx, span := range spans {
+		require.Equal(t, expected[span.ID], states[idx])
+	}
+}
+
+func TestGetLastSplitTime(t *testing.T) {
+	cfID := common.NewChangeFeedIDWithName("test")
+	db := newDBForTest(cfID)
+
+	span := NewReplicaSet(cfID, common.NewDispatcherID(), nil, 1,
+		&heartbeatpb.TableSpan{TableID: 1, StartKey: []byte("a"), EndKey: []byte("c")}, 10)
+	db.AddAbsentReplicaSet(span)
+	require.True(t, db.GetLastSplitTime(1).IsZero())
+
+	before := time.Now()
+	require.True(t, db.ReplaceReplicaSet(span, []*heartbeatpb.TableSpan{
+		{TableID: 1, StartKey: []byte("a"), EndKey: []byte("b")},
+		{TableID: 1, StartKey: []byte("b"), EndKey: []byte("c")},
+	}, 10))
+	require.Len(t, db.GetTasksByTableIDs(1), 2)
+	require.False(t, db.GetLastSplitTime(1).Before(before))
+	require.True(t, db.GetLastSplitTime(2).IsZero())
+
+	// the split time is cleared after the table is removed
+	db.TryRemoveByTableIDs(1)
+	require.True(t, db.GetLastSplitTime(1).IsZero())
+}
@@ -110,6 +110,10 @@ func (r *SpanReplication) UpdateStatus(newStatus *heartbeatpb.TableSpanStatus) {
 	}
 }
 
+func (r *SpanReplication) GetStatus() *heartbeatpb.TableSpanStatus {
+	return r.status
+}
+
 func (r *SpanReplication) GetSchemaID() int64 {
 	return r.schemaID
 }
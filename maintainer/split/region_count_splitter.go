@@ -29,6 +29,8 @@ type regionCountSplitter struct {
 	changefeedID    common.ChangeFeedID
 	regionCache     RegionCache
 	regionThreshold int
+	// mergeRegionThreshold is the region count under which a span can be merged.
+	mergeRegionThreshold int
 }
 
 func newRegionCountSplitter(
@@ -130,6 +132,19 @@ func (m *regionCountSplitter) split(
 	return spans
 }
 
+func (m *regionCountSplitter) mergeable(ctx context.Context, span *heartbeatpb.TableSpan) bool {
+	bo := tikv.NewBackoffer(ctx, 500)
+	regions, err := m.regionCache.ListRegionIDsInKeyRange(bo, span.StartKey, span.EndKey)
+	if err != nil {
+		log.Warn("list regions failed, skip merge span",
+			zap.String("changefeed", m.changefeedID.Name()),
+			zap.String("span", span.String()),
+			zap.Error(err))
+		return false
+	}
+	return len(regions) < m.mergeRegionThreshold
+}
+
 type evenlySplitStepper struct {
 	spanCount          int
 	regionPerSpan      int
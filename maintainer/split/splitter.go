@@ -53,8 +53,14 @@ type splitter interface {
 	) []*heartbeatpb.TableSpan
 }
 
+// merger checks whether the span is below its merge threshold.
+type merger interface {
+	mergeable(ctx context.Context, span *heartbeatpb.TableSpan) bool
+}
+
 type Splitter struct {
 	splitters    []splitter
+	mergers      []merger
 	changefeedID common.ChangeFeedID
 }
 
@@ -65,12 +71,22 @@ func NewSplitter(
 	regionCache RegionCache,
 	config *config.ChangefeedSchedulerConfig,
 ) *Splitter {
+	writeSplitter := newWriteSplitter(changefeedID, pdapi, config.WriteKeyThreshold)
+	writeSplitter.mergeWriteKeyThreshold = config.GetMergeWriteKeyThreshold()
+	regionCountSplitter := newRegionCountSplitter(changefeedID, regionCache, config.RegionThreshold)
+	regionCountSplitter.mergeRegionThreshold = config.GetMergeRegionThreshold()
 	return &Splitter{
 		changefeedID: changefeedID,
 		splitters: []splitter{
 			// write splitter has the highest priority.
-			newWriteSplitter(changefeedID, pdapi, config.WriteKeyThreshold),
-			newRegionCountSplitter(changefeedID, regionCache, config.RegionThreshold),
+			writeSplitter,
+			regionCountSplitter,
+		},
+		mergers: []merger{
+			// the region count is read from the local region cache,
+			// so it's checked before scanning the written keys from PD.
+			regionCountSplitter,
+			writeSplitter,
 		},
 	}
 }
@@ -88,6 +104,18 @@ func (s *Splitter) SplitSpans(ctx context.Context,
 	return spans
 }
 
+// Mergeable returns true if the span is below all the merge thresholds, so the spans
+// of a table covered by it can be merged into one. The merge thresholds are lower than
+// the split thresholds, a merged span is not split again unless the traffic grows a lot.
+func (s *Splitter) Mergeable(ctx context.Context, span *heartbeatpb.TableSpan) bool {
+	for _, m := range s.mergers {
+		if !m.mergeable(ctx, span) {
+			return false
+		}
+	}
+	return true
+}
+
 // FindHoles returns an array of Span that are not covered in the range
 func FindHoles(currentSpan utils.Map[*heartbeatpb.TableSpan, *replica.SpanReplication], totalSpan *heartbeatpb.TableSpan) []*heartbeatpb.TableSpan {
 	lastSpan := &heartbeatpb.TableSpan{
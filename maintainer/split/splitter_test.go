@@ -14,11 +14,16 @@
 package split
 
 import (
+	"context"
 	"testing"
 
 	"github.com/pingcap/ticdc/heartbeatpb"
 	"github.com/pingcap/ticdc/maintainer/replica"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/utils"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -95,3 +100,60 @@ func TestMapFindHole(t *testing.T) {
 		require.Equalf(t, cs.expectedHole, holes, "case %d, %#v", i, cs)
 	}
 }
+
+// mockPDAPIClient mocks pdutil.PDAPIClient, it records the times of scanning regions.
+type mockPDAPIClient struct {
+	pdutil.PDAPIClient
+	regions   []pdutil.RegionInfo
+	scanCount int
+}
+
+func (m *mockPDAPIClient) ScanRegions(_ context.Context, _ tablepb.Span) ([]pdutil.RegionInfo, error) {
+	m.scanCount++
+	return m.regions, nil
+}
+
+func TestSplitterMergeable(t *testing.T) {
+	t.Parallel()
+
+	cache := NewMockRegionCache(nil)
+	for i, key := range []string{"t1_0", "t1_1", "t1_2", "t1_3"} {
+		cache.regions.ReplaceOrInsert(tablepb.Span{
+			StartKey: []byte(key), EndKey: []byte(key + "0"),
+		}, uint64(i+1))
+	}
+	pd := &mockPDAPIClient{}
+	cfID := common.NewChangeFeedIDWithName("test")
+	span := &heartbeatpb.TableSpan{TableID: 1, StartKey: []byte("t1"), EndKey: []byte("t2")}
+	newSplitter := func(cfg *config.ChangefeedSchedulerConfig) *Splitter {
+		cfg.EnableTableAcrossNodes = true
+		require.NoError(t, cfg.Validate())
+		return NewSplitter(cfID, pd, cache, cfg)
+	}
+
+	// the span is not split by 4 regions, but it's not merged either,
+	// and PD is not asked since the region count is checked first.
+	splitter := newSplitter(&config.ChangefeedSchedulerConfig{RegionThreshold: 6})
+	require.False(t, splitter.Mergeable(context.Background(), span))
+	require.Equal(t, 0, pd.scanCount)
+
+	// the written keys are not checked if the span is never split by them.
+	splitter = newSplitter(&config.ChangefeedSchedulerConfig{RegionThreshold: 10})
+	require.True(t, splitter.Mergeable(context.Background(), span))
+	require.Equal(t, 0, pd.scanCount)
+
+	// the written keys are below the split threshold but above the merge threshold.
+	pd.regions = []pdutil.RegionInfo{
+		pdutil.NewTestRegionInfo(1, []byte("t1_0"), []byte("t1_2"), 30),
+		pdutil.NewTestRegionInfo(2, []byte("t1_2"), []byte("t1_4"), 30),
+	}
+	splitter = newSplitter(&config.ChangefeedSchedulerConfig{RegionThreshold: 10, WriteKeyThreshold: 100})
+	require.False(t, splitter.Mergeable(context.Background(), span))
+	require.Equal(t, 1, pd.scanCount)
+
+	splitter = newSplitter(&config.ChangefeedSchedulerConfig{
+		RegionThreshold: 10, WriteKeyThreshold: 100, MergeWriteKeyThreshold: 70,
+	})
+	require.True(t, splitter.Mergeable(context.Background(), span))
+	require.Equal(t, 2, pd.scanCount)
+}
@@ -31,6 +31,8 @@ type writeSplitter struct {
 	changefeedID      common.ChangeFeedID
 	pdAPIClient       pdutil.PDAPIClient
 	writeKeyThreshold int
+	// mergeWriteKeyThreshold is the written keys under which a span can be merged.
+	mergeWriteKeyThreshold int
 }
 
 type splitRegionsInfo struct {
@@ -101,6 +103,31 @@ func (m *writeSplitter) split(
 	return splitInfo.Spans
 }
 
+func (m *writeSplitter) mergeable(ctx context.Context, span *heartbeatpb.TableSpan) bool {
+	// the span is never split by the written keys, so PD is not asked.
+	if m.writeKeyThreshold == 0 {
+		return true
+	}
+	regions, err := m.pdAPIClient.ScanRegions(ctx, tablepb.Span{
+		TableID:  span.TableID,
+		StartKey: span.StartKey,
+		EndKey:   span.EndKey,
+	})
+	if err != nil {
+		log.Warn("scan regions failed, skip merge span",
+			zap.String("namespace", m.changefeedID.Namespace()),
+			zap.String("changefeed", m.changefeedID.Name()),
+			zap.String("span", span.String()),
+			zap.Error(err))
+		return false
+	}
+	totalWrite := uint64(0)
+	for _, region := range regions {
+		totalWrite += region.WrittenKeys
+	}
+	return totalWrite < uint64(m.mergeWriteKeyThreshold)
+}
+
 // splitRegionsByWrittenKeysV1 tries to split the regions into at least `baseSpansNum` spans,
 // each span has approximately the same write weight.
 // The algorithm is:
@@ -56,10 +56,12 @@ type CDCRESTInterface interface {
 	Delete() *Request
 }
 
-// BasicAuth holds the basic authentication information.
+// BasicAuth holds the authentication information, the bearer token
+// is sent instead of the user and password if it is set.
 type BasicAuth struct {
 	User     string
 	Password string
+	Token    string
 }
 
 // CDCRESTClient defines a TiCDC RESTful client
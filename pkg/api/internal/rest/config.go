@@ -40,14 +40,16 @@ type Config struct {
 }
 
 // parseAuthentication parses the authentication information from the config and
-// removes the user and password from the values.
+// removes the user, password and token from the values.
 func (c *Config) parseAuthentication() {
 	c.authentication = BasicAuth{
 		User:     c.Values.Get("user"),
 		Password: c.Values.Get("password"),
+		Token:    c.Values.Get("token"),
 	}
 	c.Values.Del("user")
 	c.Values.Del("password")
+	c.Values.Del("token")
 }
 
 // defaultServerURLFromConfig is used to build base URL and api path.
@@ -258,7 +258,11 @@ func (r *Request) newHTTPRequest(ctx context.Context) (*http.Request, error) {
 	}
 	req = req.WithContext(ctx)
 	req.Header = r.headers
-	req.SetBasicAuth(r.basicAuth.User, r.basicAuth.Password)
+	if r.basicAuth.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.basicAuth.Token)
+	} else {
+		req.SetBasicAuth(r.basicAuth.User, r.basicAuth.Password)
+	}
 	return req, nil
 }
 
@@ -405,6 +409,12 @@ func (r Result) Error() error {
 	return r.err
 }
 
+// StatusCode returns the http status code of the response,
+// it's 0 if no response is received.
+func (r Result) StatusCode() int {
+	return r.statusCode
+}
+
 // Into stores the http response body into obj.
 func (r Result) Into(obj interface{}) error {
 	if r.err != nil {
@@ -30,6 +30,7 @@ type APIV2Interface interface {
 	UnsafeGetter
 	CapturesGetter
 	StatusGetter
+	SinksGetter
 }
 
 // APIV2Client implements APIV1Interface and it is used to interact with cdc owner http api.
@@ -83,6 +84,14 @@ func (c *APIV2Client) Status() StatusInterface {
 	return newStatus(c)
 }
 
+// Sinks returns a SinkInterface to communicate with cdc api
+func (c *APIV2Client) Sinks() SinkInterface {
+	if c == nil {
+		return nil
+	}
+	return newSinks(c)
+}
+
 // NewAPIClient creates a new APIV1Client.
 func NewAPIClient(serverAddr string, credential *security.Credential, values url.Values) (*APIV2Client, error) {
 	c := &rest.Config{}
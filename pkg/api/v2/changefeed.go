@@ -47,6 +47,18 @@ type ChangefeedInterface interface {
 	Get(ctx context.Context, namespace string, name string) (*v2.ChangeFeedInfo, error)
 	// List lists all changefeeds
 	List(ctx context.Context, namespace string, state string) ([]v2.ChangefeedCommonInfo, error)
+	// ListTables lists the replication status of all the table spans of the changefeed
+	ListTables(ctx context.Context, namespace string, name string) ([]v2.TableSpanInfo, error)
+	// MoveTable moves a table of the changefeed to the target node
+	MoveTable(ctx context.Context, namespace string, name string, tableID int64, targetNodeID string) error
+	// SplitTable splits a table of the changefeed, and returns the spans being split
+	SplitTable(ctx context.Context, namespace string, name string, tableID int64) ([]v2.TableSpanInfo, error)
+	// ListPendingDDLs lists the ddls of the changefeed which are waiting to be approved or skipped
+	ListPendingDDLs(ctx context.Context, namespace string, name string) ([]v2.PendingDDL, error)
+	// ApprovePendingDDL approves a pending ddl of the changefeed
+	ApprovePendingDDL(ctx context.Context, namespace string, name string, blockTs uint64) error
+	// SkipPendingDDL skips a pending ddl of the changefeed
+	SkipPendingDDL(ctx context.Context, namespace string, name string, blockTs uint64) error
 }
 
 // changefeeds implements ChangefeedInterface
@@ -128,6 +140,85 @@ func (c *changefeeds) Pause(ctx context.Context,
 		Do(ctx).Error()
 }
 
+// ListTables lists the replication status of all the table spans of the changefeed
+func (c *changefeeds) ListTables(ctx context.Context,
+	namespace string, name string,
+) ([]v2.TableSpanInfo, error) {
+	result := &v2.ListResponse[v2.TableSpanInfo]{}
+	u := fmt.Sprintf("changefeeds/%s/tables?namespace=%s", name, namespace)
+	err := c.client.Get().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// MoveTable moves a table of the changefeed to the target node
+func (c *changefeeds) MoveTable(ctx context.Context,
+	namespace string, name string, tableID int64, targetNodeID string,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/tables/%d/move?namespace=%s", name, tableID, namespace)
+	return c.client.Post().
+		WithURI(u).
+		WithBody(&v2.MoveTableConfig{TargetNodeID: targetNodeID}).
+		Do(ctx).Error()
+}
+
+// SplitTable splits a table of the changefeed, and returns the spans being split
+func (c *changefeeds) SplitTable(ctx context.Context,
+	namespace string, name string, tableID int64,
+) ([]v2.TableSpanInfo, error) {
+	result := &v2.ListResponse[v2.TableSpanInfo]{}
+	u := fmt.Sprintf("changefeeds/%s/tables/%d/split?namespace=%s", name, tableID, namespace)
+	err := c.client.Post().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// ListPendingDDLs lists the ddls of the changefeed which are waiting to be approved or skipped
+func (c *changefeeds) ListPendingDDLs(ctx context.Context,
+	namespace string, name string,
+) ([]v2.PendingDDL, error) {
+	result := &v2.ListResponse[v2.PendingDDL]{}
+	u := fmt.Sprintf("changefeeds/%s/pending_ddl?namespace=%s", name, namespace)
+	err := c.client.Get().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// ApprovePendingDDL approves a pending ddl of the changefeed
+func (c *changefeeds) ApprovePendingDDL(ctx context.Context,
+	namespace string, name string, blockTs uint64,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/pending_ddl/%d/approve?namespace=%s", name, blockTs, namespace)
+	return c.client.Post().
+		WithURI(u).
+		Do(ctx).Error()
+}
+
+// SkipPendingDDL skips a pending ddl of the changefeed
+func (c *changefeeds) SkipPendingDDL(ctx context.Context,
+	namespace string, name string, blockTs uint64,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/pending_ddl/%d/skip?namespace=%s", name, blockTs, namespace)
+	return c.client.Post().
+		WithURI(u).
+		Do(ctx).Error()
+}
+
 // Get gets a changefeed detaail info
 func (c *changefeeds) Get(ctx context.Context,
 	namespace string, name string,
@@ -0,0 +1,62 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/ticdc/api/v2"
+	"github.com/pingcap/ticdc/pkg/api/internal/rest"
+)
+
+// ErrListSchemesNotSupported is returned by ListSchemes if the server
+// doesn't provide the api, it happens when the server is an old version.
+var ErrListSchemesNotSupported = errors.New("listing the sink schemes is not supported by the server")
+
+// SinksGetter has a method to return a SinkInterface.
+type SinksGetter interface {
+	Sinks() SinkInterface
+}
+
+// SinkInterface has methods to work with sink api
+type SinkInterface interface {
+	ListSchemes(ctx context.Context) ([]string, error)
+}
+
+// sinks implements SinkInterface
+type sinks struct {
+	client rest.CDCRESTInterface
+}
+
+// newSinks returns sinks
+func newSinks(c *APIV2Client) *sinks {
+	return &sinks{
+		client: c.RESTClient(),
+	}
+}
+
+// ListSchemes returns the sink schemes supported by the server
+func (c *sinks) ListSchemes(ctx context.Context) ([]string, error) {
+	result := &v2.ListResponse[string]{}
+	resp := c.client.Get().
+		WithURI("sinks/schemes").
+		Do(ctx)
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, ErrListSchemesNotSupported
+	}
+	err := resp.Into(result)
+	return result.Items, err
+}
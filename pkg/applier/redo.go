@@ -0,0 +1,182 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/ticdc/pkg/redo"
+	"github.com/pingcap/ticdc/pkg/sink/mysql"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const applierChangefeed = "redo-applier"
+
+// newMysqlConfigAndDB is used to create the downstream connection,
+// it can be replaced in tests to use a mock database.
+var newMysqlConfigAndDB = mysql.NewMysqlConfigAndDB
+
+// RedoApplierConfig is the configuration used by a redo log applier.
+type RedoApplierConfig struct {
+	// SinkURI is the uri of the mysql compatible downstream.
+	SinkURI string
+	// Storage is the uri of the redo log storage.
+	Storage string
+	// Dir is the directory to store the sorted redo log files,
+	// the default directory for temporary files is used if it's empty.
+	Dir string
+}
+
+// RedoApplier reads the redo logs from the storage, and replays them to
+// the downstream in commitTs order, from the checkpointTs to the resolvedTs
+// recorded in the redo meta.
+type RedoApplier struct {
+	cfg *RedoApplierConfig
+
+	checkpointTs uint64
+	resolvedTs   uint64
+}
+
+// NewRedoApplier creates a new RedoApplier instance.
+func NewRedoApplier(cfg *RedoApplierConfig) *RedoApplier {
+	return &RedoApplier{cfg: cfg}
+}
+
+// Apply replays the redo logs to the downstream.
+func (ra *RedoApplier) Apply(ctx context.Context) error {
+	sinkURI, err := url.Parse(ra.cfg.SinkURI)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if !sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
+		return cerror.ErrSinkURIInvalid.GenWithStack(
+			"redo applier only supports mysql compatible sink, but got %s", sinkURI.Scheme)
+	}
+
+	reader, err := redo.NewLogReader(ctx, ra.cfg.Storage)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer reader.Close()
+
+	ra.checkpointTs, ra.resolvedTs, err = reader.ReadMeta(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	log.Info("apply redo log starts",
+		zap.String("storage", ra.cfg.Storage),
+		zap.Uint64("checkpointTs", ra.checkpointTs),
+		zap.Uint64("resolvedTs", ra.resolvedTs))
+	if ra.checkpointTs > ra.resolvedTs {
+		return cerror.ErrRedoMetaInitialize.GenWithStack(
+			"checkpointTs %d is larger than resolvedTs %d", ra.checkpointTs, ra.resolvedTs)
+	}
+
+	iter, err := reader.ReadLogs(ctx, ra.cfg.Dir, ra.checkpointTs, ra.resolvedTs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err := iter.Close(); err != nil {
+			log.Warn("remove the sorted redo log files failed", zap.Error(err))
+		}
+	}()
+
+	changefeedID := common.NewChangeFeedIDWithName(applierChangefeed)
+	cfg, db, err := newMysqlConfigAndDB(ctx, changefeedID, sinkURI)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer db.Close()
+	// The rows may have been written to the downstream before the disaster,
+	// so the safe mode is always enabled to make the replay idempotent.
+	cfg.SafeMode = true
+
+	statistics := metrics.NewStatistics(changefeedID, "RedoApplier")
+	defer statistics.Close()
+	writer := mysql.NewMysqlWriter(ctx, db, cfg, changefeedID, statistics)
+	defer writer.Close()
+
+	events, err := ra.applyLogs(writer, iter, cfg.MaxTxnRow)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	log.Info("apply redo log finishes",
+		zap.Int("events", events),
+		zap.Uint64("resolvedTs", ra.resolvedTs))
+	return nil
+}
+
+// applyLogs writes the sorted redo logs to the downstream, and returns the
+// number of the applied events. The consecutive dml events are flushed in
+// batches of at most maxTxnRow rows, and a ddl event is executed only after
+// all the dml events before it are flushed, so at most one batch of the
+// events is kept in memory.
+func (ra *RedoApplier) applyLogs(writer *mysql.MysqlWriter, iter *redo.LogIterator, maxTxnRow int) (int, error) {
+	pending := make([]*commonEvent.DMLEvent, 0)
+	pendingRows := 0
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		if err := writer.Flush(pending, 0); err != nil {
+			return errors.Trace(err)
+		}
+		clear(pending)
+		pending = pending[:0]
+		pendingRows = 0
+		return nil
+	}
+
+	events := 0
+	for {
+		l, err := iter.Next()
+		if err != nil {
+			return events, errors.Trace(err)
+		}
+		if l == nil {
+			break
+		}
+		events++
+		switch l.Type {
+		case redo.LogTypeDML:
+			if pendingRows > 0 && pendingRows+int(l.DML.Len()) > maxTxnRow {
+				if err := flush(); err != nil {
+					return events, err
+				}
+			}
+			pending = append(pending, l.DML)
+			pendingRows += int(l.DML.Len())
+		case redo.LogTypeDDL:
+			if err := flush(); err != nil {
+				return events, err
+			}
+			log.Info("apply redo ddl",
+				zap.String("query", l.DDL.Query),
+				zap.Uint64("commitTs", l.DDL.GetCommitTs()))
+			if err := writer.FlushDDLEvent(l.DDL); err != nil {
+				return events, errors.Trace(err)
+			}
+		}
+	}
+	return events, flush()
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/redo"
+	"github.com/pingcap/ticdc/pkg/sink/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestApplyRedoLogs(t *testing.T) {
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32))")
+	require.NotNil(t, job)
+	dmlEvent := helper.DML2Event("test", "t", "insert into t values (1, 'a')", "insert into t values (2, 'b')")
+	ddlEvent := &commonEvent.DDLEvent{
+		Query:      job.Query,
+		Type:       byte(job.Type),
+		SchemaName: job.SchemaName,
+		TableName:  job.TableName,
+		FinishedTs: dmlEvent.GetCommitTs() - 1,
+		TableInfo:  helper.GetTableInfo(job),
+		BlockedTables: &commonEvent.InfluencedTables{
+			InfluenceType: commonEvent.InfluenceTypeNormal,
+			TableIDs:      []int64{0},
+		},
+	}
+
+	// write the redo logs to a local directory.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	storage := "file://" + t.TempDir()
+	m, err := redo.NewManager(ctx, common.ChangefeedID4Test("test", "test"), "capture-1",
+		&config.ConsistentConfig{
+			Level:                 "eventual",
+			Storage:               storage,
+			MaxLogSize:            1,
+			FlushIntervalInMs:     50,
+			MetaFlushIntervalInMs: 50,
+		})
+	require.NoError(t, err)
+	go func() {
+		_ = m.Run()
+	}()
+
+	checkpointTs := ddlEvent.GetCommitTs() - 1
+	resolvedTs := dmlEvent.GetCommitTs() + 10
+	dispatcherID := common.NewDispatcherID()
+	m.AddDispatcher(dispatcherID, checkpointTs)
+	m.UpdateCheckpointTs(checkpointTs)
+	require.NoError(t, m.WriteDDLEvent(ddlEvent))
+	var flushed atomic.Bool
+	m.AddDMLEvent(dispatcherID, dmlEvent, func() { flushed.Store(true) })
+	m.UpdateResolvedTs(dispatcherID, resolvedTs)
+	require.Eventually(t, flushed.Load, 5*time.Second, 10*time.Millisecond)
+	reader, err := redo.NewLogReader(ctx, storage)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, rts, err := reader.ReadMeta(ctx)
+		return err == nil && rts == resolvedTs
+	}, 5*time.Second, 10*time.Millisecond)
+	reader.Close()
+	m.Close(false)
+
+	// replay the redo logs to a mock mysql.
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	original := newMysqlConfigAndDB
+	newMysqlConfigAndDB = func(
+		_ context.Context, _ common.ChangeFeedID, _ *url.URL,
+	) (*mysql.MysqlConfig, *sql.DB, error) {
+		return &mysql.MysqlConfig{
+			MaxAllowedPacket: int64(variable.DefMaxAllowedPacket),
+			MaxTxnRow:        mysql.DefaultMaxTxnRow,
+		}, db, nil
+	}
+	defer func() { newMysqlConfigAndDB = original }()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(job.Query).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE DATABASE IF NOT EXISTS tidb_cdc").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("USE tidb_cdc").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS ddl_ts_v1
+		(
+			ticdc_cluster_id varchar (255),
+			changefeed varchar(255),
+			ddl_ts varchar(18),
+			table_id bigint(21),
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			INDEX (ticdc_cluster_id, changefeed, table_id),
+			PRIMARY KEY (ticdc_cluster_id, changefeed, table_id)
+		);`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(fmt.Sprintf("INSERT INTO tidb_cdc.ddl_ts_v1 (ticdc_cluster_id, changefeed, ddl_ts, table_id) "+
+		"VALUES ('default', 'default/%s', '%d', 0) "+
+		"ON DUPLICATE KEY UPDATE ddl_ts=VALUES(ddl_ts), created_at=CURRENT_TIMESTAMP;",
+		applierChangefeed, ddlEvent.GetCommitTs())).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// the rows are written in safe mode.
+	mock.ExpectBegin()
+	mock.ExpectExec("REPLACE INTO `test`.`t` (`id`,`name`) VALUES (?,?);REPLACE INTO `test`.`t` (`id`,`name`) VALUES (?,?)").
+		WithArgs(1, "a", 2, "b").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+
+	dir := t.TempDir()
+	applier := NewRedoApplier(&RedoApplierConfig{
+		SinkURI: "mysql://127.0.0.1:3306/",
+		Storage: storage,
+		Dir:     dir,
+	})
+	require.NoError(t, applier.Apply(context.Background()))
+	// the sorted redo log files are removed.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.Equal(t, checkpointTs, applier.checkpointTs)
+	require.Equal(t, resolvedTs, applier.resolvedTs)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplyRedoLogsInvalidSinkURI(t *testing.T) {
+	applier := NewRedoApplier(&RedoApplierConfig{
+		SinkURI: "kafka://127.0.0.1:9092/topic",
+		Storage: "file://" + t.TempDir(),
+	})
+	require.Error(t, applier.Apply(context.Background()))
+}
@@ -0,0 +1,133 @@
+package event
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"strings"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	timodel "github.com/pingcap/tiflow/cdc/model"
+	"go.uber.org/zap"
+)
+
+// OnConflictResolved implements causality.txnEvent interface.
+// It is called when the event leaves the conflict detector.
+func (t *DMLEvent) OnConflictResolved() {}
+
+// ConflictKeys implements causality.txnEvent interface.
+// It returns the deduplicated hash keys of the primary key and unique keys
+// of all the rows in the transaction, the transactions which have the same
+// keys are conflicted and must be executed in order.
+func (t *DMLEvent) ConflictKeys() []uint64 {
+	if t.Len() == 0 {
+		return nil
+	}
+	// Rewind the event after all the rows are read,
+	// so the rows can be read again by the sink.
+	defer t.Rewind()
+
+	hashRes := make(map[uint64]struct{}, t.Len())
+	hasher := fnv.New32a()
+	for {
+		row, ok := t.GetNextRow()
+		if !ok {
+			break
+		}
+		for _, key := range genRowKeys(row, t.TableInfo, t.PhysicalTableID) {
+			if n, err := hasher.Write(key); n != len(key) || err != nil {
+				log.Panic("transaction key hash fail")
+			}
+			hashRes[uint64(hasher.Sum32())] = struct{}{}
+			hasher.Reset()
+		}
+	}
+	keys := make([]uint64, 0, len(hashRes))
+	for key := range hashRes {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func genRowKeys(row RowChange, tableInfo *common.TableInfo, tableID int64) [][]byte {
+	var keys [][]byte
+	if !row.Row.IsEmpty() {
+		for iIdx, idxColOffsets := range tableInfo.IndexColumnsOffset {
+			key := genKeyList(&row.Row, tableInfo, iIdx, idxColOffsets, tableID)
+			if len(key) == 0 {
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	if !row.PreRow.IsEmpty() {
+		for iIdx, idxColOffsets := range tableInfo.IndexColumnsOffset {
+			key := genKeyList(&row.PreRow, tableInfo, iIdx, idxColOffsets, tableID)
+			if len(key) == 0 {
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		// use table ID as key if no key generated (no PK/UK),
+		// no concurrence for rows in the same table.
+		log.Debug("Use table id as the key", zap.Int64("tableID", tableID))
+		tableKey := make([]byte, 8)
+		binary.BigEndian.PutUint64(tableKey, uint64(tableID))
+		keys = [][]byte{tableKey}
+	}
+	return keys
+}
+
+// genKeyList generates the key of the index, colOffsets is the offsets of the
+// index columns in the non-virtual columns of the table.
+func genKeyList(row *chunk.Row, tableInfo *common.TableInfo, iIdx int, colOffsets []int, tableID int64) []byte {
+	var key []byte
+	colInfos := tableInfo.GetColInfosForRowChangedEvent()
+	for _, offset := range colOffsets {
+		colID := colInfos[offset].ID
+		idx := tableInfo.GetColumnsOffset()[colID]
+		col := tableInfo.Columns[idx]
+		// if a column value is null, we can ignore this index
+		// If the index contain generated column, we can't use this key to detect conflict with other DML,
+		// Because such as insert can't specify the generated value.
+		if col.IsGenerated() {
+			return nil
+		}
+		value, err := common.ExtractColVal(row, col, idx)
+		if err != nil {
+			log.Panic("extract column value failed", zap.String("column", col.Name.O), zap.Error(err))
+		}
+		if value == nil {
+			return nil
+		}
+
+		val := timodel.ColumnValueString(value)
+		if columnNeeds2LowerCase(col.GetType(), col.GetCollate()) {
+			val = strings.ToLower(val)
+		}
+
+		key = append(key, []byte(val)...)
+		key = append(key, 0)
+	}
+	if len(key) == 0 {
+		return nil
+	}
+	tableKey := make([]byte, 16)
+	binary.BigEndian.PutUint64(tableKey[:8], uint64(iIdx))
+	binary.BigEndian.PutUint64(tableKey[8:], uint64(tableID))
+	key = append(key, tableKey...)
+	return key
+}
+
+func columnNeeds2LowerCase(mysqlType byte, collation string) bool {
+	switch mysqlType {
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeTinyBlob,
+		mysql.TypeMediumBlob, mysql.TypeBlob, mysql.TypeLongBlob:
+		return strings.HasSuffix(collation, "_ci")
+	}
+	return false
+}
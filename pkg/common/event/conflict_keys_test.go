@@ -0,0 +1,45 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConflictKeys(t *testing.T) {
+	helper := NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.tk.MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32), unique key uk_name(name))")
+	require.NotNil(t, job)
+
+	event1 := helper.DML2Event("test", "t", "insert into t values (1, 'a')")
+	event2 := helper.DML2Event("test", "t", "insert into t values (2, 'b')")
+	keys1 := event1.ConflictKeys()
+	keys2 := event2.ConflictKeys()
+	// one key for the primary key, and one for the unique key.
+	require.Len(t, keys1, 2)
+	require.Len(t, keys2, 2)
+	for _, key := range keys1 {
+		require.NotContains(t, keys2, key)
+	}
+
+	// the rows can still be read after the keys are generated.
+	require.ElementsMatch(t, keys1, event1.ConflictKeys())
+	row, ok := event1.GetNextRow()
+	require.True(t, ok)
+	require.Equal(t, int64(1), row.Row.GetInt64(0))
+
+	// each row in the transaction generates its own keys.
+	event3 := helper.DML2Event("test", "t", "insert into t values (3, 'c')", "insert into t values (4, 'd')")
+	require.Len(t, event3.ConflictKeys(), 4)
+
+	// the table id is used as the key if the table has no primary key or unique key.
+	job = helper.DDL2Job("create table t1 (id int, name varchar(32))")
+	require.NotNil(t, job)
+	event4 := helper.DML2Event("test", "t1", "insert into t1 values (1, 'a')")
+	event5 := helper.DML2Event("test", "t1", "insert into t1 values (2, 'b')")
+	require.Len(t, event4.ConflictKeys(), 1)
+	require.Equal(t, event4.ConflictKeys(), event5.ConflictKeys())
+}
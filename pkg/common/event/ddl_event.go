@@ -52,6 +52,12 @@ type DDLEvent struct {
 	TableNameChange *TableNameChange `json:"table_name_change"`
 
 	TiDBOnly bool `json:"tidb_only"`
+	// BDRRole is the BDR role of the upstream TiDB cluster when the DDL is executed,
+	// only the DDLs executed by the primary cluster are replicated in BDR mode.
+	BDRRole string `json:"bdr_role"`
+	// IsBootstrap means the event is a bootstrap event generated by the sink
+	// to send the table schema to the downstream, instead of a real DDL.
+	IsBootstrap bool `json:"-"`
 	// 用于在event flush 后执行，后续兼容不同下游的时候要看是不是要拆下去
 	PostTxnFlushed []func() `json:"-"`
 	// eventSize is the size of the event in bytes. It is set when it's unmarshaled.
@@ -81,10 +81,19 @@ func NewDMLEvent(
 	}
 }
 
+// RowFilter is used to filter out the row changes which should not be sent to downstream.
+// It returns true if the row change should be ignored.
+// It's called twice for each row change: first with empty rows before the row change is decoded,
+// so the row change ignored by its type is never decoded; then with the decoded rows.
+type RowFilter func(rowType RowType, preRow, row chunk.Row) (bool, error)
+
+// AppendRow decodes the raw kv entry and appends the row change to the event.
+// If the filter is not nil and the row change is ignored by it, the row change is not appended.
 func (t *DMLEvent) AppendRow(raw *common.RawKVEntry,
 	decode func(
 		rawKv *common.RawKVEntry,
 		tableInfo *common.TableInfo, chk *chunk.Chunk) (int, error),
+	filter RowFilter,
 ) error {
 	RowType := RowTypeInsert
 	if raw.OpType == common.OpTypeDelete {
@@ -93,10 +102,38 @@ func (t *DMLEvent) AppendRow(raw *common.RawKVEntry,
 	if len(raw.Value) != 0 && len(raw.OldValue) != 0 {
 		RowType = RowTypeUpdate
 	}
+	if filter != nil {
+		ignore, err := filter(RowType, chunk.Row{}, chunk.Row{})
+		if err != nil || ignore {
+			return err
+		}
+	}
 	count, err := decode(raw, t.TableInfo, t.Rows)
 	if err != nil {
 		return err
 	}
+	if filter != nil && count > 0 {
+		var preRow, row chunk.Row
+		offset := t.Rows.NumRows() - count
+		switch RowType {
+		case RowTypeInsert:
+			row = t.Rows.GetRow(offset)
+		case RowTypeDelete:
+			preRow = t.Rows.GetRow(offset)
+		case RowTypeUpdate:
+			preRow = t.Rows.GetRow(offset)
+			row = t.Rows.GetRow(offset + 1)
+		}
+		ignore, err := filter(RowType, preRow, row)
+		if err != nil {
+			return err
+		}
+		if ignore {
+			// Remove the decoded rows from the chunk.
+			t.Rows.TruncateTo(offset)
+			return nil
+		}
+	}
 	if count == 1 {
 		t.RowTypes = append(t.RowTypes, RowType)
 	} else if count == 2 {
@@ -175,6 +212,11 @@ func (t *DMLEvent) GetNextRow() (RowChange, bool) {
 	return RowChange{}, false
 }
 
+// Rewind resets the offset of the event, so the rows can be read from the beginning by GetNextRow.
+func (t *DMLEvent) Rewind() {
+	t.offset = 0
+}
+
 // Len returns the number of row change events in the transaction.
 // Note: An update event is counted as 1 row.
 func (t *DMLEvent) Len() int32 {
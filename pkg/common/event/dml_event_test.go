@@ -3,6 +3,8 @@ package event
 import (
 	"testing"
 
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/tidb/pkg/util/chunk"
 	"github.com/stretchr/testify/require"
 )
 
@@ -78,3 +80,47 @@ func TestEncodeAndDecodeV0(t *testing.T) {
 	reverseEvent.eventSize = 0
 	require.Equal(t, dmlEvent, reverseEvent)
 }
+
+// TestAppendRowWithFilter test the rows ignored by the filter are not appended.
+func TestAppendRowWithFilter(t *testing.T) {
+	helper := NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.tk.MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32))")
+	require.NotNil(t, job)
+	rawKvs := helper.DML2RawKv("test", "t",
+		"insert into t values (1, 'a')",
+		"insert into t values (2, 'b')",
+		"insert into t values (3, 'c')")
+	require.Len(t, rawKvs, 3)
+
+	tableInfo := helper.GetTableInfo(job)
+	dmlEvent := NewDMLEvent(common.NewDispatcherID(), tableInfo.ID, 1, 2, tableInfo)
+	calls := 0
+	filter := func(rowType RowType, preRow, row chunk.Row) (bool, error) {
+		calls++
+		require.Equal(t, RowTypeInsert, rowType)
+		require.True(t, preRow.IsEmpty())
+		if row.IsEmpty() {
+			return false, nil
+		}
+		return row.GetInt64(0) == 2, nil
+	}
+	for _, rawKv := range rawKvs {
+		require.NoError(t, dmlEvent.AppendRow(rawKv, helper.mounter.DecodeToChunk, filter))
+	}
+	// the filter is called before and after the row is decoded.
+	require.Equal(t, 6, calls)
+	require.Equal(t, int32(2), dmlEvent.Len())
+	require.Equal(t, 2, dmlEvent.Rows.NumRows())
+
+	row, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+	require.Equal(t, int64(1), row.Row.GetInt64(0))
+	row, ok = dmlEvent.GetNextRow()
+	require.True(t, ok)
+	require.Equal(t, int64(3), row.Row.GetInt64(0))
+	_, ok = dmlEvent.GetNextRow()
+	require.False(t, ok)
+}
@@ -0,0 +1,69 @@
+package event
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/ticdc/pkg/common"
+)
+
+// ErrorEvent is sent by the event service when the events of a dispatcher can't be scanned,
+// the dispatcher reports the error to the maintainer, so only its changefeed is failed.
+type ErrorEvent struct {
+	// State is the state of sender when sending this event.
+	State        EventSenderState    `json:"state"`
+	DispatcherID common.DispatcherID `json:"dispatcher_id"`
+	// CommitTs is the commitTs of the transaction which can't be scanned.
+	CommitTs uint64 `json:"commit_ts"`
+	Message  string `json:"message"`
+}
+
+func NewErrorEvent(dispatcherID common.DispatcherID, commitTs uint64, err error) *ErrorEvent {
+	return &ErrorEvent{
+		DispatcherID: dispatcherID,
+		CommitTs:     commitTs,
+		Message:      err.Error(),
+	}
+}
+
+func (e *ErrorEvent) GetType() int {
+	return TypeErrorEvent
+}
+
+func (e *ErrorEvent) GetDispatcherID() common.DispatcherID {
+	return e.DispatcherID
+}
+
+func (e *ErrorEvent) GetCommitTs() common.Ts {
+	return e.CommitTs
+}
+
+func (e *ErrorEvent) GetStartTs() common.Ts {
+	return e.CommitTs
+}
+
+func (e *ErrorEvent) GetSeq() uint64 {
+	// It's a fake seq.
+	return 0
+}
+
+func (e *ErrorEvent) GetSize() int64 {
+	return int64(e.State.GetSize() + e.DispatcherID.GetSize() + 8 + len(e.Message))
+}
+
+func (e *ErrorEvent) IsPaused() bool {
+	return e.State.IsPaused()
+}
+
+// GetError returns the error carried by the event.
+func (e *ErrorEvent) GetError() error {
+	return errors.Errorf("event service failed to scan the events at commitTs %d: %s", e.CommitTs, e.Message)
+}
+
+func (e ErrorEvent) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *ErrorEvent) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, e)
+}
@@ -0,0 +1,21 @@
+package event
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorEvent(t *testing.T) {
+	e := NewErrorEvent(common.NewDispatcherID(), 100, errors.New("decode row failed"))
+	data, err := e.Marshal()
+	require.NoError(t, err)
+
+	e2 := &ErrorEvent{}
+	require.NoError(t, e2.Unmarshal(data))
+	require.Equal(t, e, e2)
+	require.Equal(t, TypeErrorEvent, e2.GetType())
+	require.Contains(t, e2.GetError().Error(), "decode row failed")
+}
@@ -49,6 +49,8 @@ const (
 	TypeSyncPointEvent
 	// HandshakeEvent is the event type of a handshake.
 	TypeHandshakeEvent
+	// ErrorEvent is the event type of an error which fails the changefeed.
+	TypeErrorEvent
 )
 
 // fakeDispatcherID is a fake dispatcherID for batch resolvedTs.
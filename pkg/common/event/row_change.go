@@ -218,11 +218,13 @@ type MQRowEvent struct {
 }
 
 type RowEvent struct {
-	TableInfo      *common.TableInfo
-	CommitTs       uint64
-	Event          RowChange
-	ColumnSelector columnselector.Selector
-	Callback       func()
+	PhysicalTableID int64
+	TableInfo       *common.TableInfo
+	StartTs         uint64
+	CommitTs        uint64
+	Event           RowChange
+	ColumnSelector  columnselector.Selector
+	Callback        func()
 }
 
 func (e *RowEvent) IsDelete() bool {
@@ -261,3 +263,50 @@ func (e *RowEvent) PrimaryKeyColumnNames() []string {
 	}
 	return result
 }
+
+// ToRowChangedEvent converts the RowEvent to a RowChangedEvent, which is
+// used by the encoders that still work on the columns of the row.
+// The columns are aligned with the CDC visible columns of the table, and
+// the column not selected by the ColumnSelector is set to nil.
+func (e *RowEvent) ToRowChangedEvent() *RowChangedEvent {
+	return &RowChangedEvent{
+		PhysicalTableID: e.PhysicalTableID,
+		StartTs:         e.StartTs,
+		CommitTs:        e.CommitTs,
+		TableInfo:       e.TableInfo,
+		Columns:         e.rowToColumns(&e.Event.Row),
+		PreColumns:      e.rowToColumns(&e.Event.PreRow),
+	}
+}
+
+func (e *RowEvent) rowToColumns(row *chunk.Row) []*common.Column {
+	if row.IsEmpty() {
+		return nil
+	}
+	tableInfo := e.TableInfo
+	columns := make([]*common.Column, 0, len(tableInfo.Columns))
+	for idx, colInfo := range tableInfo.Columns {
+		if !common.IsColCDCVisible(colInfo) {
+			continue
+		}
+		if e.ColumnSelector != nil && !e.ColumnSelector.Select(colInfo) {
+			columns = append(columns, nil)
+			continue
+		}
+		value, err := common.ExtractColVal(row, colInfo, idx)
+		if err != nil {
+			log.Panic("extract column value failed",
+				zap.String("column", colInfo.Name.O), zap.Error(err))
+		}
+		columns = append(columns, &common.Column{
+			Name:      colInfo.Name.O,
+			Type:      colInfo.GetType(),
+			Charset:   colInfo.GetCharset(),
+			Collation: colInfo.GetCollate(),
+			Flag:      *tableInfo.ColumnsFlag[colInfo.ID],
+			Value:     value,
+			Default:   common.GetColumnDefaultValue(colInfo),
+		})
+	}
+	return columns
+}
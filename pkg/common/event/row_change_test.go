@@ -0,0 +1,62 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/stretchr/testify/require"
+)
+
+type skipColumnSelector struct {
+	name string
+}
+
+func (s *skipColumnSelector) Select(colInfo *model.ColumnInfo) bool {
+	return colInfo.Name.O != s.name
+}
+
+// TestRowEventToRowChangedEvent test the conversion from RowEvent to RowChangedEvent.
+func TestRowEventToRowChangedEvent(t *testing.T) {
+	helper := NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.tk.MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32), price decimal(10, 2))")
+	require.NotNil(t, job)
+
+	dmlEvent := helper.DML2Event("test", "t", "insert into t values (1, 'a', 1.5)")
+	require.NotNil(t, dmlEvent)
+	row, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+
+	rowEvent := &RowEvent{
+		PhysicalTableID: dmlEvent.PhysicalTableID,
+		TableInfo:       dmlEvent.TableInfo,
+		StartTs:         dmlEvent.StartTs,
+		CommitTs:        dmlEvent.CommitTs,
+		Event:           row,
+	}
+	event := rowEvent.ToRowChangedEvent()
+	require.True(t, event.IsInsert())
+	require.Equal(t, dmlEvent.PhysicalTableID, event.GetTableID())
+	require.Equal(t, dmlEvent.CommitTs, event.CommitTs)
+	require.Nil(t, event.PreColumns)
+	require.Len(t, event.Columns, 3)
+
+	require.Equal(t, "id", event.Columns[0].Name)
+	require.Equal(t, int64(1), event.Columns[0].Value)
+	require.True(t, event.Columns[0].Flag.IsPrimaryKey())
+	// the value of string types is kept as bytes.
+	require.Equal(t, "name", event.Columns[1].Name)
+	require.Equal(t, []byte("a"), event.Columns[1].Value)
+	require.Equal(t, "price", event.Columns[2].Name)
+	require.Equal(t, "1.50", event.Columns[2].Value)
+
+	// the column not selected is set to nil.
+	rowEvent.ColumnSelector = &skipColumnSelector{name: "name"}
+	event = rowEvent.ToRowChangedEvent()
+	require.Len(t, event.Columns, 3)
+	require.NotNil(t, event.Columns[0])
+	require.Nil(t, event.Columns[1])
+	require.NotNil(t, event.Columns[2])
+}
@@ -14,9 +14,34 @@ import (
 
 var EmptyBytes = make([]byte, 0)
 
-// getColumnValue returns the column value in the row
+// FormatColVal returns the column value in the row, the value of string types
+// with a non-binary charset is returned as string, so it can be used as the
+// argument of the sql statement directly.
 func FormatColVal(row *chunk.Row, col *model.ColumnInfo, idx int) (
 	value interface{}, err error,
+) {
+	v, err := ExtractColVal(row, col, idx)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the column value type is []byte and charset is not binary, we get its string
+	// representation. Because if we use the byte array respresentation, the go-sql-driver
+	// will automatically set `_binary` charset for that column, which is not expected.
+	// See https://github.com/go-sql-driver/mysql/blob/ce134bfc/connection.go#L267
+	if col.GetCharset() != "" && col.GetCharset() != charset.CharsetBin {
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+	}
+	return v, nil
+}
+
+// ExtractColVal returns the column value in the row, the value of string types
+// is always returned as []byte, which is the same as the value in the
+// RowChangedEvent produced by the mounter.
+func ExtractColVal(row *chunk.Row, col *model.ColumnInfo, idx int) (
+	value interface{}, err error,
 ) {
 	if row.IsNull(idx) {
 		return nil, nil
@@ -77,15 +102,5 @@ func FormatColVal(row *chunk.Row, col *model.ColumnInfo, idx int) (
 		// Go sql support type ref to: https://github.com/golang/go/blob/go1.17.4/src/database/sql/driver/types.go#L236
 		v = d.GetValue()
 	}
-
-	// If the column value type is []byte and charset is not binary, we get its string
-	// representation. Because if we use the byte array respresentation, the go-sql-driver
-	// will automatically set `_binary` charset for that column, which is not expected.
-	// See https://github.com/go-sql-driver/mysql/blob/ce134bfc/connection.go#L267
-	if col.GetCharset() != "" && col.GetCharset() != charset.CharsetBin {
-		if b, ok := v.([]byte); ok {
-			v = string(b)
-		}
-	}
 	return v, nil
 }
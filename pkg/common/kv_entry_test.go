@@ -72,6 +72,36 @@ func TestRawKVEntryEncodeDecode_ResolvedOperation(t *testing.T) {
 	require.Equal(t, original, decoded)
 }
 
+func TestRawKVEntryEncodeDecode_TxnSource(t *testing.T) {
+	original := RawKVEntry{
+		OpType:    OpTypePut,
+		CRTs:      5555555555,
+		StartTs:   6666666666,
+		RegionID:  7,
+		Key:       []byte("key"),
+		Value:     []byte("value"),
+		OldValue:  make([]byte, 0),
+		TxnSource: 1<<8 | 1,
+	}
+
+	encoded := original.Encode()
+
+	var decoded RawKVEntry
+	err := decoded.Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, original, decoded)
+	require.True(t, decoded.IsWrittenByCDC())
+
+	// the entry encoded without txn source can still be decoded
+	original.TxnSource = 0
+	encoded = original.Encode()
+	decoded = RawKVEntry{}
+	err = decoded.Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, original, decoded)
+	require.False(t, decoded.IsWrittenByCDC())
+}
+
 func TestCompareEncodedSize(t *testing.T) {
 	entry := getRawKVEntry()
 	encoded := entry.Encode()
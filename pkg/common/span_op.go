@@ -119,6 +119,58 @@ func GetIntersectSpan(lhs heartbeatpb.TableSpan, rhs heartbeatpb.TableSpan) hear
 	}
 }
 
+// IsSubSpan returns true if sub is contained in parent, both of them must belong to the same table.
+func IsSubSpan(sub, parent *heartbeatpb.TableSpan) bool {
+	return sub.TableID == parent.TableID &&
+		StartCompare(parent.StartKey, sub.StartKey) <= 0 &&
+		EndCompare(sub.EndKey, parent.EndKey) <= 0
+}
+
+// GetCoveringSpans picks the spans in candidates which cover span together, and returns
+// the indexes of the picked candidates and the parts of span covered by each of them.
+// The parts are disjoint and sorted by the start key. It returns false if span can't be
+// covered by the candidates.
+func GetCoveringSpans(span *heartbeatpb.TableSpan, candidates []*heartbeatpb.TableSpan) ([]int, []*heartbeatpb.TableSpan, bool) {
+	var (
+		indexes []int
+		parts   []*heartbeatpb.TableSpan
+	)
+	// Greedily pick the candidate which starts before the cursor and ends farthest,
+	// until the end of the span is reached.
+	cursor := span.StartKey
+	for {
+		next := -1
+		for i, candidate := range candidates {
+			if candidate.TableID != span.TableID || StartCompare(candidate.StartKey, cursor) > 0 {
+				continue
+			}
+			if len(cursor) != 0 && EndCompare(candidate.EndKey, cursor) <= 0 {
+				continue
+			}
+			if next < 0 || EndCompare(candidate.EndKey, candidates[next].EndKey) > 0 {
+				next = i
+			}
+		}
+		if next < 0 {
+			return nil, nil, false
+		}
+		end := candidates[next].EndKey
+		if EndCompare(end, span.EndKey) >= 0 {
+			end = span.EndKey
+		}
+		indexes = append(indexes, next)
+		parts = append(parts, &heartbeatpb.TableSpan{
+			TableID:  span.TableID,
+			StartKey: cursor,
+			EndKey:   end,
+		})
+		if EndCompare(end, span.EndKey) >= 0 {
+			return indexes, parts, true
+		}
+		cursor = end
+	}
+}
+
 // IsEmptySpan returns true if the span is empty.
 // TODO: check whether need span.StartKey >= span.EndKey
 func IsEmptySpan(span heartbeatpb.TableSpan) bool {
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCoveringSpans(t *testing.T) {
+	newSpan := func(start, end string) *heartbeatpb.TableSpan {
+		span := &heartbeatpb.TableSpan{TableID: 1}
+		if start != "" {
+			span.StartKey = []byte(start)
+		}
+		if end != "" {
+			span.EndKey = []byte(end)
+		}
+		return span
+	}
+	candidates := []*heartbeatpb.TableSpan{
+		newSpan("b", "h"),
+		newSpan("a", "d"),
+		newSpan("f", "q"),
+		newSpan("k", "z"),
+	}
+
+	// the candidate which ends farthest is picked, and the parts are disjoint.
+	indexes, parts, ok := GetCoveringSpans(newSpan("c", "p"), candidates)
+	require.True(t, ok)
+	require.Equal(t, []int{0, 2}, indexes)
+	require.Equal(t, []*heartbeatpb.TableSpan{newSpan("c", "h"), newSpan("h", "p")}, parts)
+
+	indexes, parts, ok = GetCoveringSpans(newSpan("a", "r"), candidates)
+	require.True(t, ok)
+	require.Equal(t, []int{1, 0, 2, 3}, indexes)
+	require.Equal(t, []*heartbeatpb.TableSpan{
+		newSpan("a", "d"), newSpan("d", "h"), newSpan("h", "q"), newSpan("q", "r"),
+	}, parts)
+
+	// a single candidate contains the span.
+	indexes, parts, ok = GetCoveringSpans(newSpan("l", "m"), candidates)
+	require.True(t, ok)
+	require.Equal(t, []int{3}, indexes)
+	require.Equal(t, []*heartbeatpb.TableSpan{newSpan("l", "m")}, parts)
+
+	// the span exceeds the candidates.
+	_, _, ok = GetCoveringSpans(newSpan("", "c"), candidates)
+	require.False(t, ok)
+	_, _, ok = GetCoveringSpans(newSpan("y", ""), candidates)
+	require.False(t, ok)
+	_, _, ok = GetCoveringSpans(newSpan("c", "p"), candidates[:2])
+	require.False(t, ok)
+
+	// the spans of other tables are never picked.
+	other := newSpan("a", "z")
+	other.TableID = 2
+	_, _, ok = GetCoveringSpans(newSpan("a", "r"), []*heartbeatpb.TableSpan{other})
+	require.False(t, ok)
+
+	// the unbounded span is covered by the unbounded candidates.
+	indexes, _, ok = GetCoveringSpans(newSpan("", ""), []*heartbeatpb.TableSpan{newSpan("m", ""), newSpan("", "n")})
+	require.True(t, ok)
+	require.Equal(t, []int{1, 0}, indexes)
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// APIRoleReadOnly can only call the read-only open apis, like list and get.
+	APIRoleReadOnly = "read-only"
+	// APIRoleAdmin can call all the open apis, including the ones mutating changefeeds.
+	APIRoleAdmin = "admin"
+)
+
+// APIAuthConfig represents the authentication and authorization config of the open api.
+// The open api requires authentication if security.client-user-required is true,
+// or any token or client certificate is configured here.
+// The status apis and the metrics api are never authenticated, since they are used
+// by the health checks and the monitoring system.
+type APIAuthConfig struct {
+	// Tokens are the static bearer tokens accepted by the open api.
+	Tokens []*APITokenConfig `toml:"tokens" json:"tokens"`
+	// CertRoles maps the common names of the client certificates to roles,
+	// it takes effect only when the client certificate is verified by mtls.
+	CertRoles map[string]string `toml:"cert-roles" json:"cert-roles"`
+	// UserRoles maps the TiDB users authenticated by basic auth to roles,
+	// the users in security.client-allowed-user which are not listed here are read-only.
+	UserRoles map[string]string `toml:"user-roles" json:"user-roles"`
+}
+
+// APITokenConfig represents a static bearer token of the open api.
+type APITokenConfig struct {
+	// Name identifies the token in the logs, since the token itself is never logged.
+	Name  string `toml:"name" json:"name"`
+	Token string `toml:"token" json:"token"`
+	Role  string `toml:"role" json:"role"`
+}
+
+// NewDefaultAPIAuthConfig returns the default api auth config.
+func NewDefaultAPIAuthConfig() *APIAuthConfig {
+	return &APIAuthConfig{}
+}
+
+// ValidateAndAdjust validates and adjusts the api auth configuration
+func (c *APIAuthConfig) ValidateAndAdjust() error {
+	names := make(map[string]struct{}, len(c.Tokens))
+	tokens := make(map[string]struct{}, len(c.Tokens))
+	for _, t := range c.Tokens {
+		if t == nil || t.Token == "" {
+			return cerror.ErrInvalidServerOption.GenWithStack("api-auth.tokens.token should not be empty")
+		}
+		if _, ok := tokens[t.Token]; ok {
+			return cerror.ErrInvalidServerOption.GenWithStack("api-auth.tokens.token %s is duplicated", t.Name)
+		}
+		tokens[t.Token] = struct{}{}
+		if _, ok := names[t.Name]; ok || t.Name == "" {
+			return cerror.ErrInvalidServerOption.GenWithStack(
+				"api-auth.tokens.name should be unique and not empty, got %q", t.Name)
+		}
+		names[t.Name] = struct{}{}
+		if err := validateAPIRole(t.Role); err != nil {
+			return err
+		}
+	}
+	for _, roles := range []map[string]string{c.CertRoles, c.UserRoles} {
+		for _, role := range roles {
+			if err := validateAPIRole(role); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Enabled returns true if any token or client certificate is configured.
+func (c *APIAuthConfig) Enabled() bool {
+	return len(c.Tokens) != 0 || len(c.CertRoles) != 0
+}
+
+// maskTokens masks the tokens to avoid leaking them in the logs.
+func (c *APIAuthConfig) maskTokens() {
+	for _, t := range c.Tokens {
+		if t != nil && t.Token != "" {
+			t.Token = "******"
+		}
+	}
+}
+
+func validateAPIRole(role string) error {
+	switch role {
+	case APIRoleReadOnly, APIRoleAdmin:
+		return nil
+	default:
+	}
+	return cerror.ErrInvalidServerOption.GenWithStack(
+		"unsupported api role %q, only %s and %s are supported", role, APIRoleReadOnly, APIRoleAdmin)
+}
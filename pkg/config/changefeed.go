@@ -35,6 +35,15 @@ type ChangefeedConfig struct {
 	SyncPointInterval  *time.Duration `json:"sync_point_interval" default:"1m"`
 	SyncPointRetention *time.Duration `json:"sync_point_retention" default:"24h"`
 	SinkConfig         *SinkConfig    `json:"sink_config"`
+	// Consistent is the redo log config, redo log is disabled if it's nil.
+	Consistent *ConsistentConfig `json:"consistent"`
+	// Transforms are the row level transform rules of the changefeed.
+	Transforms []*TransformRule `json:"transforms"`
+	// BDRMode is true if the changefeed is a part of the bidirectional replication.
+	BDRMode bool `json:"bdr_mode"`
+	// TiDBSourceID is the source id of the upstream TiDB cluster,
+	// it's written to the downstream as the `tidb_cdc_write_source`.
+	TiDBSourceID uint64 `json:"tidb_source_id"`
 }
 
 // ChangeFeedInfo describes the detail of a ChangeFeed
@@ -63,6 +72,11 @@ type ChangeFeedInfo struct {
 	CreatorVersion string `json:"creator-version"`
 	// Epoch is the epoch of a changefeed, changes on every restart.
 	Epoch uint64 `json:"epoch"`
+
+	// PendingDDLDecisions are the approve or skip decisions of the ddls blocked by
+	// the block ddl config, they are applied again if the maintainer is restarted
+	// before the ddls are finished.
+	PendingDDLDecisions []*PendingDDLDecision `json:"pending-ddl-decisions,omitempty"`
 }
 
 // NeedBlockGC returns true if the changefeed need to block the GC safepoint.
@@ -453,6 +467,9 @@ type ChangeFeedStatus struct {
 	CheckpointTs uint64 `json:"checkpoint-ts"`
 	// Progress indicates changefeed progress status
 	Progress Progress `json:"progress"`
+	// DeadLetterRows is the number of rows written to the dead letter destination,
+	// it's reported by the maintainer at runtime and is not persisted.
+	DeadLetterRows uint64 `json:"dead-letter-rows,omitempty"`
 }
 
 // Marshal returns json encoded string of ChangeFeedStatus, only contains necessary fields stored in storage
@@ -467,3 +484,37 @@ func (status *ChangeFeedStatus) Unmarshal(data []byte) error {
 	return errors.Annotatef(
 		cerror.WrapError(cerror.ErrUnmarshalFailed, err), "Unmarshal data: %v", data)
 }
+
+// TableSpanReplicationStatus is the replication status of a table span in the maintainer,
+// it is used by the open api to show the lagging spans of a changefeed.
+type TableSpanReplicationStatus struct {
+	TableID      int64  `json:"table_id"`
+	SchemaID     int64  `json:"schema_id"`
+	StartKey     []byte `json:"start_key"`
+	EndKey       []byte `json:"end_key"`
+	DispatcherID string `json:"dispatcher_id"`
+	NodeID       string `json:"node_id"`
+	// State is the scheduling state of the span, absent, scheduling or replicating
+	State        string `json:"state"`
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	ResolvedTs   uint64 `json:"resolved_ts"`
+	// Operator is the type of the operator which is scheduling the span, empty if there is none
+	Operator string `json:"operator"`
+}
+
+// PendingDDL is a ddl which is blocked by the block ddl config of the changefeed,
+// the ddl is not executed until it's approved or skipped manually.
+type PendingDDL struct {
+	BlockTs uint64 `json:"block_ts"`
+	Query   string `json:"query"`
+	// Ready is true if all the influenced dispatchers have reached the ddl,
+	// otherwise the decision is applied after they reach it.
+	Ready bool `json:"ready"`
+}
+
+// PendingDDLDecision is the decision made by the user for a pending ddl.
+type PendingDDLDecision struct {
+	BlockTs uint64 `json:"block-ts"`
+	// Approved is true if the ddl is approved, otherwise it's skipped.
+	Approved bool `json:"approved"`
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// EventServiceConfig represents the config of the event service, which scans
+// the events from the event store and sends them to the dispatchers.
+type EventServiceConfig struct {
+	// ScanMaxBytes is the max bytes of the events a scan task can send,
+	// the rest of the data range is scanned by the next task. 0 means no limit.
+	ScanMaxBytes int64 `toml:"scan-max-bytes" json:"scan-max-bytes"`
+	// ScanMaxRows is the max number of the rows a scan task can send. 0 means no limit.
+	ScanMaxRows int64 `toml:"scan-max-rows" json:"scan-max-rows"`
+	// ScanMemoryQuota is the max bytes of the scanned events which are not sent yet,
+	// it is shared by all the scan tasks. 0 means no limit.
+	ScanMemoryQuota int64 `toml:"scan-memory-quota" json:"scan-memory-quota"`
+}
+
+// NewDefaultEventServiceConfig returns the default event service configuration.
+func NewDefaultEventServiceConfig() *EventServiceConfig {
+	return &EventServiceConfig{
+		ScanMaxBytes:    32 << 20, // 32MB
+		ScanMaxRows:     100000,
+		ScanMemoryQuota: 1 << 30, // 1GB
+	}
+}
+
+// ValidateAndAdjust validates and adjusts the event service configuration.
+func (c *EventServiceConfig) ValidateAndAdjust() error {
+	if c.ScanMaxBytes < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"event-service.scan-max-bytes should not be negative, got %d", c.ScanMaxBytes)
+	}
+	if c.ScanMaxRows < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"event-service.scan-max-rows should not be negative, got %d", c.ScanMaxRows)
+	}
+	if c.ScanMemoryQuota < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"event-service.scan-memory-quota should not be negative, got %d", c.ScanMemoryQuota)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventServiceConfigValidateAndAdjust(t *testing.T) {
+	require.NoError(t, NewDefaultEventServiceConfig().ValidateAndAdjust())
+
+	// zero means no limit
+	cfg := &EventServiceConfig{}
+	require.NoError(t, cfg.ValidateAndAdjust())
+
+	cfg = NewDefaultEventServiceConfig()
+	cfg.ScanMaxBytes = -1
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "event-service.scan-max-bytes")
+
+	cfg = NewDefaultEventServiceConfig()
+	cfg.ScanMaxRows = -1
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "event-service.scan-max-rows")
+
+	cfg = NewDefaultEventServiceConfig()
+	cfg.ScanMemoryQuota = -1
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "event-service.scan-memory-quota")
+}
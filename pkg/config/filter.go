@@ -22,6 +22,11 @@ type FilterConfig struct {
 	Rules            []string           `toml:"rules" json:"rules"`
 	IgnoreTxnStartTs []uint64           `toml:"ignore-txn-start-ts" json:"ignore-txn-start-ts"`
 	EventFilters     []*EventFilterRule `toml:"event-filters" json:"event-filters"`
+	// BlockDDL is the types of the DDLs which are not executed until they are
+	// approved or skipped manually, such as "drop table" and "truncate table".
+	// Only the tables influenced by a pending DDL are blocked, the other tables
+	// of the changefeed keep replicating.
+	BlockDDL []bf.EventType `toml:"block-ddl" json:"block-ddl,omitempty"`
 }
 
 // EventFilterRule is used by sql event filter and expression filter
@@ -1,5 +1,7 @@
 package config
 
+import "github.com/pingcap/tiflow/pkg/security"
+
 const (
 	// size of channel to cache the messages to be sent and received
 	defaultCacheSize = 102400
@@ -8,6 +10,9 @@ const (
 type MessageCenterConfig struct {
 	// The size of the channel for pending messages to be sent and received.
 	CacheChannelSize int
+	// Security is the credential used to connect to the message centers of the other nodes,
+	// the connections are insecure if it's nil.
+	Security *security.Credential
 }
 
 func NewDefaultMessageCenterConfig() *MessageCenterConfig {
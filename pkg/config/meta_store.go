@@ -0,0 +1,79 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// MetaStoreTypeEtcd stores the changefeed metadata in the etcd of PD, it's the default type.
+	MetaStoreTypeEtcd = "etcd"
+	// MetaStoreTypeMySQL stores the changefeed metadata in a MySQL compatible database, like TiDB.
+	MetaStoreTypeMySQL = "mysql"
+)
+
+// MetaStoreConfig represents the config of the storage which the changefeed metadata is stored in.
+type MetaStoreConfig struct {
+	// Type is the type of the meta store, etcd or mysql.
+	Type string `toml:"type" json:"type"`
+	// DSN is the data source name of the database, it's required if the type is mysql,
+	// eg, "root:password@tcp(127.0.0.1:4000)/ticdc".
+	DSN string `toml:"dsn" json:"dsn"`
+}
+
+// NewDefaultMetaStoreConfig returns the default meta store config.
+func NewDefaultMetaStoreConfig() *MetaStoreConfig {
+	return &MetaStoreConfig{
+		Type: MetaStoreTypeEtcd,
+	}
+}
+
+// ValidateAndAdjust validates and adjusts the meta store configuration
+func (c *MetaStoreConfig) ValidateAndAdjust() error {
+	c.Type = strings.ToLower(strings.TrimSpace(c.Type))
+	switch c.Type {
+	case "":
+		c.Type = MetaStoreTypeEtcd
+	case MetaStoreTypeEtcd:
+	case MetaStoreTypeMySQL:
+		if c.DSN == "" {
+			return cerror.ErrInvalidServerOption.GenWithStack("meta-store.dsn is required when meta-store.type is mysql")
+		}
+	default:
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"unsupported meta-store.type %s, only etcd and mysql are supported", c.Type)
+	}
+	return nil
+}
+
+// maskDSN masks the password in the DSN to avoid leaking it in the logs.
+func (c *MetaStoreConfig) maskDSN() {
+	if c.DSN == "" {
+		return
+	}
+	dsnCfg, err := mysql.ParseDSN(c.DSN)
+	if err != nil {
+		// the DSN is invalid, mask it entirely since the password can't be located.
+		c.DSN = "******"
+		return
+	}
+	if dsnCfg.Passwd != "" {
+		dsnCfg.Passwd = "******"
+	}
+	c.DSN = dsnCfg.FormatDSN()
+}
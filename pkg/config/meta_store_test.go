@@ -0,0 +1,57 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetaStoreConfigValidateAndAdjust(t *testing.T) {
+	cfg := &MetaStoreConfig{}
+	require.NoError(t, cfg.ValidateAndAdjust())
+	require.Equal(t, MetaStoreTypeEtcd, cfg.Type)
+
+	cfg = &MetaStoreConfig{Type: " MySQL "}
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "meta-store.dsn")
+	cfg.DSN = "root:password@tcp(127.0.0.1:4000)/ticdc"
+	require.NoError(t, cfg.ValidateAndAdjust())
+	require.Equal(t, MetaStoreTypeMySQL, cfg.Type)
+
+	cfg = &MetaStoreConfig{Type: "redis"}
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "unsupported meta-store.type")
+}
+
+func TestMetaStoreConfigMaskDSN(t *testing.T) {
+	cfg := &MetaStoreConfig{}
+	cfg.maskDSN()
+	require.Empty(t, cfg.DSN)
+
+	cfg.DSN = "root:password@tcp(127.0.0.1:4000)/ticdc"
+	cfg.maskDSN()
+	require.NotContains(t, cfg.DSN, "password")
+	require.True(t, strings.HasPrefix(cfg.DSN, "root:******@tcp(127.0.0.1:4000)/ticdc"), cfg.DSN)
+
+	// no password to mask
+	cfg.DSN = "root@tcp(127.0.0.1:4000)/ticdc"
+	cfg.maskDSN()
+	require.True(t, strings.HasPrefix(cfg.DSN, "root@tcp(127.0.0.1:4000)/ticdc"), cfg.DSN)
+
+	// the whole DSN is masked if it can't be parsed
+	cfg.DSN = "root:password@invalid"
+	cfg.maskDSN()
+	require.Equal(t, "******", cfg.DSN)
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// PebbleCompressionNone disables the compression of a level.
+	PebbleCompressionNone = "none"
+	// PebbleCompressionSnappy compresses a level by snappy.
+	PebbleCompressionSnappy = "snappy"
+	// PebbleCompressionZstd compresses a level by zstd.
+	PebbleCompressionZstd = "zstd"
+
+	// pebbleLevelCount is the number of levels of a pebble db.
+	pebbleLevelCount = 7
+	// maxPebbleMemTableSize is the max memtable size supported by pebble.
+	maxPebbleMemTableSize = 4<<30 - 1
+	// maxPebbleBlockSize is the max block size supported by pebble.
+	maxPebbleBlockSize = 1 << 28
+	// maxEventStoreDBCount is the max number of dbs used by the event store.
+	maxEventStoreDBCount = 256
+)
+
+// PebbleConfig represents the options of a pebble db.
+type PebbleConfig struct {
+	// MemTableSize is the size of a memtable in bytes.
+	MemTableSize uint64 `toml:"memtable-size" json:"memtable-size"`
+	// BlockSize is the target size of a data block in bytes.
+	BlockSize int `toml:"block-size" json:"block-size"`
+	// BloomFilterBitsPerKey is the bits per key of the bloom filters of the tables,
+	// 0 means the bloom filters are disabled.
+	BloomFilterBitsPerKey int `toml:"bloom-filter-bits-per-key" json:"bloom-filter-bits-per-key"`
+	// Compression is the compression algorithm of each level, from L0 to L6.
+	// The last one is used for the rest levels if fewer than 7 are given.
+	// Valid values are "none", "snappy" and "zstd".
+	Compression []string `toml:"compression" json:"compression"`
+	// DisableWAL disables the write ahead log. The data on disk can't be reused
+	// after restart if the write ahead log is disabled.
+	DisableWAL bool `toml:"disable-wal" json:"disable-wal"`
+}
+
+// GetCompression returns the compression algorithm of the level.
+func (c *PebbleConfig) GetCompression(level int) string {
+	if level < len(c.Compression) {
+		return c.Compression[level]
+	}
+	return c.Compression[len(c.Compression)-1]
+}
+
+// ValidateAndAdjust validates and adjusts the pebble configuration.
+func (c *PebbleConfig) ValidateAndAdjust(item string) error {
+	if c.MemTableSize == 0 || c.MemTableSize > maxPebbleMemTableSize {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"%s.memtable-size should be in (0, %d], got %d", item, maxPebbleMemTableSize, c.MemTableSize)
+	}
+	if c.BlockSize <= 0 || c.BlockSize > maxPebbleBlockSize {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"%s.block-size should be in (0, %d], got %d", item, maxPebbleBlockSize, c.BlockSize)
+	}
+	if c.BloomFilterBitsPerKey < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"%s.bloom-filter-bits-per-key should not be negative, got %d", item, c.BloomFilterBitsPerKey)
+	}
+	if len(c.Compression) == 0 || len(c.Compression) > pebbleLevelCount {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"%s.compression should contain 1 to %d algorithms, got %d", item, pebbleLevelCount, len(c.Compression))
+	}
+	for _, compression := range c.Compression {
+		switch compression {
+		case PebbleCompressionNone, PebbleCompressionSnappy, PebbleCompressionZstd:
+		default:
+			return cerror.ErrInvalidServerOption.GenWithStack(
+				"%s.compression must be %q, %q or %q, got %q", item,
+				PebbleCompressionNone, PebbleCompressionSnappy, PebbleCompressionZstd, compression)
+		}
+	}
+	return nil
+}
+
+// EventStoreConfig represents the config of the event store.
+type EventStoreConfig struct {
+	// Count is the number of pebble dbs used by the event store. If the count is
+	// decreased, the extra dbs are removed on startup and their data is pulled
+	// from the upstream again.
+	Count int `toml:"count" json:"count"`
+	// DiskQuota is the max disk usage of the dbs in bytes. The writes are paused
+	// once the usage exceeds it, so the puller is blocked until the usage falls
+	// below it after the data consumed by the dispatchers is removed.
+	// 0 means unlimited.
+	DiskQuota uint64 `toml:"disk-quota" json:"disk-quota"`
+
+	PebbleConfig
+}
+
+// NewDefaultEventStoreConfig returns the default event store configuration.
+func NewDefaultEventStoreConfig() *EventStoreConfig {
+	return &EventStoreConfig{
+		Count: 32,
+		PebbleConfig: PebbleConfig{
+			MemTableSize:          8 << 20,  // 8MB
+			BlockSize:             64 << 10, // 64KB
+			BloomFilterBitsPerKey: 10,
+			// The values are already compressed by zstd before written to the db.
+			Compression: []string{PebbleCompressionNone},
+		},
+	}
+}
+
+// ValidateAndAdjust validates and adjusts the event store configuration.
+func (c *EventStoreConfig) ValidateAndAdjust() error {
+	if c.Count <= 0 || c.Count > maxEventStoreDBCount {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"event-store.count should be in (0, %d], got %d", maxEventStoreDBCount, c.Count)
+	}
+	return c.PebbleConfig.ValidateAndAdjust("event-store")
+}
+
+// SchemaStoreConfig represents the config of the schema store.
+type SchemaStoreConfig struct {
+	PebbleConfig
+}
+
+// NewDefaultSchemaStoreConfig returns the default schema store configuration.
+func NewDefaultSchemaStoreConfig() *SchemaStoreConfig {
+	return &SchemaStoreConfig{
+		PebbleConfig: PebbleConfig{
+			MemTableSize:          8 << 20,  // 8MB
+			BlockSize:             64 << 10, // 64KB
+			BloomFilterBitsPerKey: 10,
+			Compression:           []string{PebbleCompressionSnappy},
+			// The schema store is rebuilt from the upstream if the data is not reusable.
+			DisableWAL: true,
+		},
+	}
+}
+
+// ValidateAndAdjust validates and adjusts the schema store configuration.
+func (c *SchemaStoreConfig) ValidateAndAdjust() error {
+	return c.PebbleConfig.ValidateAndAdjust("schema-store")
+}
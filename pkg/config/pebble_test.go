@@ -0,0 +1,67 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStoreConfigValidateAndAdjust(t *testing.T) {
+	require.NoError(t, NewDefaultEventStoreConfig().ValidateAndAdjust())
+
+	cfg := NewDefaultEventStoreConfig()
+	cfg.Count = 0
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "event-store.count")
+	cfg.Count = maxEventStoreDBCount + 1
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "event-store.count")
+
+	cfg = NewDefaultEventStoreConfig()
+	cfg.MemTableSize = 0
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "event-store.memtable-size")
+	cfg.MemTableSize = maxPebbleMemTableSize + 1
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "event-store.memtable-size")
+
+	cfg = NewDefaultEventStoreConfig()
+	cfg.BlockSize = maxPebbleBlockSize + 1
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "event-store.block-size")
+
+	cfg = NewDefaultEventStoreConfig()
+	cfg.BloomFilterBitsPerKey = -1
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "event-store.bloom-filter-bits-per-key")
+
+	// the disk quota is unlimited by default.
+	require.Zero(t, NewDefaultEventStoreConfig().DiskQuota)
+}
+
+func TestSchemaStoreConfigValidateAndAdjust(t *testing.T) {
+	require.NoError(t, NewDefaultSchemaStoreConfig().ValidateAndAdjust())
+
+	cfg := NewDefaultSchemaStoreConfig()
+	cfg.Compression = nil
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "schema-store.compression")
+	cfg.Compression = make([]string, pebbleLevelCount+1)
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "schema-store.compression")
+	cfg.Compression = []string{PebbleCompressionSnappy, "lz4"}
+	require.ErrorContains(t, cfg.ValidateAndAdjust(), "schema-store.compression")
+}
+
+func TestPebbleConfigGetCompression(t *testing.T) {
+	cfg := &PebbleConfig{Compression: []string{PebbleCompressionNone, PebbleCompressionSnappy}}
+	require.Equal(t, PebbleCompressionNone, cfg.GetCompression(0))
+	require.Equal(t, PebbleCompressionSnappy, cfg.GetCompression(1))
+	// the last one is used for the rest levels
+	require.Equal(t, PebbleCompressionSnappy, cfg.GetCompression(pebbleLevelCount-1))
+}
@@ -161,6 +161,8 @@ type replicaConfig struct {
 	Integrity                    *integrity.Config   `toml:"integrity" json:"integrity"`
 	ChangefeedErrorStuckDuration *time.Duration      `toml:"changefeed-error-stuck-duration" json:"changefeed-error-stuck-duration,omitempty"`
 	SyncedStatus                 *SyncedStatusConfig `toml:"synced-status" json:"synced-status,omitempty"`
+	// Transforms are the row level transform rules applied before the rows are sent to the sink.
+	Transforms []*TransformRule `toml:"transforms" json:"transforms,omitempty"`
 
 	// Deprecated: we don't use this field since v8.0.0.
 	SQLMode string `toml:"sql-mode" json:"sql-mode"`
@@ -277,6 +279,11 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 						minSyncPointRetention.String()))
 		}
 	}
+	// the loop prevention of bdr mode relies on the txn source written by the mysql sink
+	if util.GetOrZero(c.BDRMode) && !sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("bdr mode is not supported by the sink scheme %s", sinkURI.Scheme))
+	}
 	if c.MemoryQuota == uint64(0) {
 		c.FixMemoryQuota()
 	}
@@ -324,6 +331,10 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 					minChangeFeedErrorStuckDuration.Seconds()))
 	}
 
+	if err := ValidateTransformRules(c.Transforms); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -29,6 +29,12 @@ type ChangefeedSchedulerConfig struct {
 	RegionThreshold int `toml:"region-threshold" json:"region-threshold"`
 	// WriteKeyThreshold is the written keys threshold of splitting a table.
 	WriteKeyThreshold int `toml:"write-key-threshold" json:"write-key-threshold"`
+	// MergeRegionThreshold is the region count threshold of merging the spans of a table,
+	// it must be less than RegionThreshold, half of RegionThreshold is used if it's 0.
+	MergeRegionThreshold int `toml:"merge-region-threshold" json:"merge-region-threshold"`
+	// MergeWriteKeyThreshold is the written keys threshold of merging the spans of a table,
+	// it must be less than WriteKeyThreshold, half of WriteKeyThreshold is used if it's 0.
+	MergeWriteKeyThreshold int `toml:"merge-write-key-threshold" json:"merge-write-key-threshold"`
 }
 
 // Validate validates the config.
@@ -42,9 +48,39 @@ func (c *ChangefeedSchedulerConfig) Validate() error {
 	if c.WriteKeyThreshold < 0 {
 		return errors.New("write-key-threshold must be larger than 0")
 	}
+	if c.MergeRegionThreshold < 0 {
+		return errors.New("merge-region-threshold must be larger than 0")
+	}
+	if c.MergeWriteKeyThreshold < 0 {
+		return errors.New("merge-write-key-threshold must be larger than 0")
+	}
+	if c.RegionThreshold > 0 && c.MergeRegionThreshold >= c.RegionThreshold {
+		return errors.New("merge-region-threshold must be less than region-threshold")
+	}
+	if c.WriteKeyThreshold > 0 && c.MergeWriteKeyThreshold >= c.WriteKeyThreshold {
+		return errors.New("merge-write-key-threshold must be less than write-key-threshold")
+	}
 	return nil
 }
 
+// GetMergeRegionThreshold returns the region count threshold of merging the spans of a table.
+// It's lower than the threshold of splitting, so a table is not split and merged back and forth.
+func (c *ChangefeedSchedulerConfig) GetMergeRegionThreshold() int {
+	if c.MergeRegionThreshold > 0 {
+		return c.MergeRegionThreshold
+	}
+	return c.RegionThreshold / 2
+}
+
+// GetMergeWriteKeyThreshold returns the written keys threshold of merging the spans of a table.
+// It's lower than the threshold of splitting, so a table is not split and merged back and forth.
+func (c *ChangefeedSchedulerConfig) GetMergeWriteKeyThreshold() int {
+	if c.MergeWriteKeyThreshold > 0 {
+		return c.MergeWriteKeyThreshold
+	}
+	return c.WriteKeyThreshold / 2
+}
+
 // SchedulerConfig configs TiCDC scheduler.
 type SchedulerConfig struct {
 	// HeartbeatTick is the number of owner tick to initial a heartbeat to captures.
@@ -111,8 +111,13 @@ var defaultServerConfig = &ServerConfig{
 		SortDir:       DefaultSortDir,
 		CacheSizeInMB: 128, // By default, use 128M memory as sorter cache.
 	},
-	Security: &security.Credential{},
-	KVClient: NewDefaultKVClientConfig(),
+	Security:     &security.Credential{},
+	KVClient:     NewDefaultKVClientConfig(),
+	MetaStore:    NewDefaultMetaStoreConfig(),
+	APIAuth:      NewDefaultAPIAuthConfig(),
+	EventStore:   NewDefaultEventStoreConfig(),
+	SchemaStore:  NewDefaultSchemaStoreConfig(),
+	EventService: NewDefaultEventServiceConfig(),
 	Debug: &DebugConfig{
 		DB:       NewDefaultDBConfig(),
 		Messages: defaultMessageConfig.Clone(),
@@ -147,6 +152,11 @@ type ServerConfig struct {
 	Sorter                 *SorterConfig        `toml:"sorter" json:"sorter"`
 	Security               *security.Credential `toml:"security" json:"security"`
 	KVClient               *KVClientConfig      `toml:"kv-client" json:"kv-client"`
+	MetaStore              *MetaStoreConfig     `toml:"meta-store" json:"meta-store"`
+	APIAuth                *APIAuthConfig       `toml:"api-auth" json:"api-auth"`
+	EventStore             *EventStoreConfig    `toml:"event-store" json:"event-store"`
+	SchemaStore            *SchemaStoreConfig   `toml:"schema-store" json:"schema-store"`
+	EventService           *EventServiceConfig  `toml:"event-service" json:"event-service"`
 	Debug                  *DebugConfig         `toml:"debug" json:"debug"`
 	ClusterID              string               `toml:"cluster-id" json:"cluster-id"`
 	GcTunerMemoryThreshold uint64               `toml:"gc-tuner-memory-threshold" json:"gc-tuner-memory-threshold"`
@@ -175,9 +185,16 @@ func (c *ServerConfig) Unmarshal(data []byte) error {
 	return nil
 }
 
-// String implements the Stringer interface
+// String implements the Stringer interface, the api tokens are masked.
 func (c *ServerConfig) String() string {
-	s, _ := c.Marshal()
+	clone := c.Clone()
+	if clone.APIAuth != nil {
+		clone.APIAuth.maskTokens()
+	}
+	if clone.MetaStore != nil {
+		clone.MetaStore.maskDSN()
+	}
+	s, _ := clone.Marshal()
 	return s
 }
 
@@ -241,6 +258,15 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 					"It's highly recommended to enable TLS to secure the communication")
 			}
 		}
+		// the certificates are ignored silently if they are not set together.
+		if !c.Security.IsEmpty() && !c.Security.IsTLSEnabled() {
+			return cerror.ErrInvalidServerOption.GenWithStack(
+				"ca-path, cert-path and key-path should be set together to enable TLS")
+		}
+		if (c.Security.MTLS || len(c.Security.CertAllowedCN) != 0) && !c.Security.IsTLSEnabled() {
+			return cerror.ErrInvalidServerOption.GenWithStack(
+				"mtls and cert-allowed-cn take effect only when TLS is enabled")
+		}
 		if c.Security.IsTLSEnabled() {
 			var err error
 			_, err = c.Security.ToTLSConfig()
@@ -271,6 +297,45 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 		return errors.Trace(err)
 	}
 
+	if c.MetaStore == nil {
+		c.MetaStore = defaultCfg.MetaStore
+	}
+	if err = c.MetaStore.ValidateAndAdjust(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.APIAuth == nil {
+		c.APIAuth = defaultCfg.APIAuth
+	}
+	if err = c.APIAuth.ValidateAndAdjust(); err != nil {
+		return errors.Trace(err)
+	}
+	if len(c.APIAuth.CertRoles) != 0 && (c.Security == nil || !c.Security.MTLS) {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"api-auth.cert-roles takes effect only when security.mtls is enabled")
+	}
+
+	if c.EventStore == nil {
+		c.EventStore = defaultCfg.EventStore
+	}
+	if err = c.EventStore.ValidateAndAdjust(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.SchemaStore == nil {
+		c.SchemaStore = defaultCfg.SchemaStore
+	}
+	if err = c.SchemaStore.ValidateAndAdjust(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.EventService == nil {
+		c.EventService = defaultCfg.EventService
+	}
+	if err = c.EventService.ValidateAndAdjust(); err != nil {
+		return errors.Trace(err)
+	}
+
 	if c.Debug == nil {
 		c.Debug = defaultCfg.Debug
 	}
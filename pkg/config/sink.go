@@ -221,6 +221,10 @@ func (s *SinkConfig) MaskSensitiveData() {
 	if s.PulsarConfig != nil {
 		s.PulsarConfig.MaskSensitiveData()
 	}
+	if s.MySQLConfig != nil && s.MySQLConfig.DeadLetter != nil && s.MySQLConfig.DeadLetter.StorageURI != nil {
+		s.MySQLConfig.DeadLetter.StorageURI = aws.String(
+			util.MaskSensitiveDataInURI(*s.MySQLConfig.DeadLetter.StorageURI))
+	}
 }
 
 // ShouldSendBootstrapMsg returns whether the sink should send bootstrap message.
@@ -682,6 +686,50 @@ type MySQLConfig struct {
 	EnableBatchDML               *bool   `toml:"enable-batch-dml" json:"enable-batch-dml,omitempty"`
 	EnableMultiStatement         *bool   `toml:"enable-multi-statement" json:"enable-multi-statement,omitempty"`
 	EnableCachePreparedStatement *bool   `toml:"enable-cache-prepared-statement" json:"enable-cache-prepared-statement,omitempty"`
+
+	// DeadLetter is the config of the rows which can not be applied to the downstream.
+	DeadLetter *DeadLetterConfig `toml:"dead-letter" json:"dead-letter,omitempty"`
+}
+
+// DeadLetterConfig represents the dead letter configuration of the MySQL sink.
+// If it's enabled, the rows which fail with a non-retryable error are written
+// to the dead letter table or storage, and the changefeed keeps replicating.
+type DeadLetterConfig struct {
+	Enable *bool `toml:"enable" json:"enable,omitempty"`
+	// Table is the downstream table to write the dead letters, in the format of
+	// `schema.table`, the default one is `tidb_cdc.dead_letter_v1`.
+	Table *string `toml:"table" json:"table,omitempty"`
+	// StorageURI is the external storage to write the dead letters,
+	// the dead letters are written to the downstream table if it's empty.
+	StorageURI *string `toml:"storage-uri" json:"storage-uri,omitempty"`
+}
+
+func (c *DeadLetterConfig) validate() error {
+	if !util.GetOrZero(c.Enable) {
+		return nil
+	}
+	table := util.GetOrZero(c.Table)
+	storageURI := util.GetOrZero(c.StorageURI)
+	if table != "" && storageURI != "" {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			"the table and storage-uri of dead letter cannot be set at the same time")
+	}
+	if table != "" && len(strings.Split(table, ".")) > 2 {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("invalid dead letter table %s, it should be in the format of schema.table", table))
+	}
+	if storageURI != "" {
+		uri, err := url.Parse(storageURI)
+		if err != nil {
+			return cerror.ErrInvalidReplicaConfig.Wrap(err).GenWithStackByArgs(
+				"invalid dead letter storage-uri")
+		}
+		if !sink.IsStorageScheme(uri.Scheme) {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				fmt.Sprintf("the scheme %s of dead letter storage-uri is not supported", uri.Scheme))
+		}
+	}
+	return nil
 }
 
 // CloudStorageConfig represents a cloud storage sink configuration
@@ -712,7 +760,17 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 		return err
 	}
 
+	// validateAndAdjustSinkURI makes sure the scheme is registered.
+	if info, _ := GetSinkSchemeInfo(sinkURI.Scheme); info.Validate != nil {
+		if err := info.Validate(sinkURI, s); err != nil {
+			return err
+		}
+	}
+
 	if sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
+		if s.MySQLConfig != nil && s.MySQLConfig.DeadLetter != nil {
+			return s.MySQLConfig.DeadLetter.validate()
+		}
 		return nil
 	}
 
@@ -832,6 +890,12 @@ func (s *SinkConfig) validateAndAdjustSinkURI(sinkURI *url.URL) error {
 		return nil
 	}
 
+	schemeInfo, ok := GetSinkSchemeInfo(sinkURI.Scheme)
+	if !ok {
+		return cerror.ErrSinkURIInvalid.GenWithStackByArgs(fmt.Sprintf("the sink scheme (%s) is not supported, "+
+			"supported schemes: %s", sinkURI.Scheme, strings.Join(SupportedSinkSchemes(), ", ")))
+	}
+
 	if err := s.applyParameterBySinkURI(sinkURI); err != nil {
 		if !cerror.ErrIncompatibleSinkConfig.Equal(err) {
 			return err
@@ -858,7 +922,7 @@ func (s *SinkConfig) validateAndAdjustSinkURI(sinkURI *url.URL) error {
 			"is incompatible with %s scheme", util.GetOrZero(s.Protocol), sinkURI.Scheme))
 	}
 	// For testing purposes, any protocol should be legal for blackhole.
-	if sink.IsMQScheme(sinkURI.Scheme) || sink.IsStorageScheme(sinkURI.Scheme) {
+	if schemeInfo.EncodeByProtocol {
 		return s.ValidateProtocol(sinkURI.Scheme)
 	}
 	return nil
@@ -866,6 +930,10 @@ func (s *SinkConfig) validateAndAdjustSinkURI(sinkURI *url.URL) error {
 
 // ValidateProtocol validates the protocol configuration.
 func (s *SinkConfig) ValidateProtocol(scheme string) error {
+	if info, ok := GetSinkSchemeInfo(scheme); !ok || !info.EncodeByProtocol {
+		return cerror.ErrSinkURIInvalid.GenWithStackByArgs(
+			fmt.Sprintf("protocol is not supported by %s scheme", scheme))
+	}
 	protocol, err := ParseSinkProtocolFromString(util.GetOrZero(s.Protocol))
 	if err != nil {
 		return err
@@ -897,7 +965,10 @@ func (s *SinkConfig) ValidateProtocol(scheme string) error {
 	case sink.PulsarScheme, sink.PulsarSSLScheme, sink.PulsarHTTPScheme, sink.PulsarHTTPSScheme:
 		outputRawChangeEvent = s.PulsarConfig.GetOutputRawChangeEvent()
 	default:
-		outputRawChangeEvent = s.CloudStorageConfig.GetOutputRawChangeEvent()
+		// the registered sinks which are not built in don't have the raw change event config.
+		if sink.IsStorageScheme(scheme) {
+			outputRawChangeEvent = s.CloudStorageConfig.GetOutputRawChangeEvent()
+		}
 	}
 
 	if outputRawChangeEvent && !outputOldValue {
@@ -0,0 +1,79 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+)
+
+// SinkSchemeInfo describes how the sink config of a sink scheme is validated.
+type SinkSchemeInfo struct {
+	// EncodeByProtocol is true if the sink encodes events by the protocol in the sink config,
+	// the protocol is validated by `SinkConfig.ValidateProtocol`.
+	EncodeByProtocol bool
+	// Validate is an optional hook to validate and adjust the sink config for the sink URI.
+	Validate func(sinkURI *url.URL, sinkConfig *SinkConfig) error
+}
+
+// sinkSchemeRegistry records the supported sink schemes. It's filled by the
+// sink factory registry in `downstreamadapter/sink`, so the schemes passing the
+// validation are always the ones which a sink can be created for.
+var sinkSchemeRegistry = struct {
+	sync.RWMutex
+	schemes map[string]SinkSchemeInfo
+}{
+	schemes: make(map[string]SinkSchemeInfo),
+}
+
+// RegisterSinkScheme registers the schemes of a sink, so the changefeeds with
+// these schemes can pass the validation. It's called when the sink factory is
+// registered, use `sink.Register` in `downstreamadapter/sink` instead.
+// It panics if any of the schemes is already registered.
+func RegisterSinkScheme(info SinkSchemeInfo, schemes ...string) {
+	sinkSchemeRegistry.Lock()
+	defer sinkSchemeRegistry.Unlock()
+	for _, scheme := range schemes {
+		scheme = strings.ToLower(scheme)
+		if _, ok := sinkSchemeRegistry.schemes[scheme]; ok {
+			log.Panic("sink scheme is already registered", zap.String("scheme", scheme))
+		}
+		sinkSchemeRegistry.schemes[scheme] = info
+	}
+}
+
+// GetSinkSchemeInfo returns the info of the registered scheme.
+func GetSinkSchemeInfo(scheme string) (SinkSchemeInfo, bool) {
+	sinkSchemeRegistry.RLock()
+	defer sinkSchemeRegistry.RUnlock()
+	info, ok := sinkSchemeRegistry.schemes[strings.ToLower(scheme)]
+	return info, ok
+}
+
+// SupportedSinkSchemes returns all the registered sink schemes in order.
+func SupportedSinkSchemes() []string {
+	sinkSchemeRegistry.RLock()
+	defer sinkSchemeRegistry.RUnlock()
+	schemes := make([]string, 0, len(sinkSchemeRegistry.schemes))
+	for scheme := range sinkSchemeRegistry.schemes {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
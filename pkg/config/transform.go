@@ -0,0 +1,133 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// MaskMethodHash replaces the value with the hex encoded sha256 hash of the value.
+	MaskMethodHash = "hash"
+	// MaskMethodMask replaces the value with '*' except the kept prefix and suffix.
+	MaskMethodMask = "mask"
+	// MaskMethodNull replaces the value with NULL.
+	MaskMethodNull = "null"
+)
+
+const (
+	// ComputedColumnCommitTs is the commit ts of the transaction, the column type is bigint unsigned.
+	ComputedColumnCommitTs = "commit_ts"
+	// ComputedColumnCommitTime is the physical time of the commit ts in UTC,
+	// the column type is datetime(3).
+	ComputedColumnCommitTime = "commit_time"
+	// ComputedColumnSchemaName is the upstream schema name of the row, the column type is varchar.
+	ComputedColumnSchemaName = "schema_name"
+	// ComputedColumnTableName is the upstream table name of the row, the column type is varchar.
+	ComputedColumnTableName = "table_name"
+)
+
+// TransformRule is the row level transformation of the tables matched by the matcher,
+// the rows are transformed before they are sent to the sink, so the rule applies to all kinds of sinks.
+// The downstream table must be consistent with the transformed rows, such as the renamed columns
+// and the added columns, since the DDLs are not transformed.
+type TransformRule struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	// RenameColumns maps the upstream column name to the downstream column name.
+	RenameColumns map[string]string `toml:"rename-columns" json:"rename-columns,omitempty"`
+	// MaskColumns hides the values of the columns, such as the PII columns.
+	MaskColumns []*MaskColumnRule `toml:"mask-columns" json:"mask-columns,omitempty"`
+	// AddColumns appends the static or computed columns to the rows.
+	AddColumns []*AddColumnRule `toml:"add-columns" json:"add-columns,omitempty"`
+}
+
+// MaskColumnRule describes how the value of a column is masked, the hash and mask
+// methods are only available for the string columns.
+type MaskColumnRule struct {
+	Column string `toml:"column" json:"column"`
+	// Method is one of "hash", "mask" and "null".
+	Method string `toml:"method" json:"method"`
+	// KeepPrefix and KeepSuffix are the number of the characters not masked by the mask method.
+	KeepPrefix int `toml:"keep-prefix" json:"keep-prefix,omitempty"`
+	KeepSuffix int `toml:"keep-suffix" json:"keep-suffix,omitempty"`
+}
+
+// AddColumnRule describes a column appended to the rows, either Value or Expr must be set.
+type AddColumnRule struct {
+	Name string `toml:"name" json:"name"`
+	// Value is the static value of the column, such as the tag of the source cluster.
+	Value string `toml:"value" json:"value,omitempty"`
+	// Expr is the computed value of the column, one of "commit_ts", "commit_time",
+	// "schema_name" and "table_name".
+	Expr string `toml:"expr" json:"expr,omitempty"`
+}
+
+// ValidateTransformRules checks whether the transform rules are valid,
+// the matchers are validated when the transformer is created.
+func ValidateTransformRules(rules []*TransformRule) error {
+	for _, rule := range rules {
+		if len(rule.Matcher) == 0 {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs("transform rule must have a matcher")
+		}
+		columns := make(map[string]struct{})
+		for from, to := range rule.RenameColumns {
+			if from == "" || to == "" {
+				return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+					fmt.Sprintf("invalid rename column %q to %q", from, to))
+			}
+			columns[strings.ToLower(to)] = struct{}{}
+		}
+		for _, mask := range rule.MaskColumns {
+			if mask.Column == "" {
+				return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs("mask column must have a column name")
+			}
+			switch mask.Method {
+			case MaskMethodHash, MaskMethodNull:
+			case MaskMethodMask:
+				if mask.KeepPrefix < 0 || mask.KeepSuffix < 0 {
+					return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+						fmt.Sprintf("invalid keep prefix or suffix of mask column %s", mask.Column))
+				}
+			default:
+				return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+					fmt.Sprintf("invalid mask method %q of column %s", mask.Method, mask.Column))
+			}
+		}
+		for _, add := range rule.AddColumns {
+			if add.Name == "" {
+				return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs("add column must have a column name")
+			}
+			if _, ok := columns[strings.ToLower(add.Name)]; ok {
+				return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+					fmt.Sprintf("duplicated column %s", add.Name))
+			}
+			columns[strings.ToLower(add.Name)] = struct{}{}
+			if (add.Value == "") == (add.Expr == "") {
+				return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+					fmt.Sprintf("one of the value and the expr of add column %s must be set", add.Name))
+			}
+			switch add.Expr {
+			case "", ComputedColumnCommitTs, ComputedColumnCommitTime,
+				ComputedColumnSchemaName, ComputedColumnTableName:
+			default:
+				return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+					fmt.Sprintf("invalid expr %q of add column %s", add.Expr, add.Name))
+			}
+		}
+	}
+	return nil
+}
@@ -11,14 +11,17 @@ import (
 	"github.com/pingcap/ticdc/pkg/node"
 	"github.com/pingcap/ticdc/utils/dynstream"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/heartbeatpb"
 	"github.com/pingcap/ticdc/logservice/eventstore"
 	"github.com/pingcap/ticdc/logservice/schemastore"
 	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/filter"
 	"github.com/pingcap/ticdc/pkg/messaging"
 	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/tidb/pkg/util/chunk"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/oracle"
@@ -37,6 +40,7 @@ var metricEventBrokerDropResolvedTsCount = metrics.EventServiceDropResolvedTsCou
 var metricScanTaskQueueDuration = metrics.EventServiceScanTaskQueueDuration
 var metricEventBrokerHandleDuration = metrics.EventServiceHandleDuration
 var metricEventBrokerDropNotificationCount = metrics.EventServiceDropNotificationCount
+var metricEventBrokerInterruptScanCount = metrics.EventServiceInterruptScanCount
 
 // eventBroker get event from the eventStore, and send the event to the dispatchers.
 // Every TiDB cluster has a eventBroker.
@@ -59,12 +63,19 @@ type eventBroker struct {
 	// taskPool is used to store the scan tasks and merge the tasks of same dispatcher.
 	// TODO: Make it support merge the tasks of the same table span, even if the tasks are from different dispatchers.
 	taskPool *scanTaskPool
+	// retryScanTasks is the dispatcherID -> dispatcherStat map of the scan tasks to push again,
+	// which can't be executed or queued before.
+	retryScanTasks sync.Map
 
 	// GID here is the internal changefeedID, use to identify the area of the dispatcher.
 	ds dynstream.DynamicStream[common.GID, common.DispatcherID, scanTask, *eventBroker, *dispatcherEventsHandler]
 
 	// scanWorkerCount is the number of the scan workers to spawn.
 	scanWorkerCount int
+	// scanLimit is the budget of each scan task.
+	scanLimit scanLimit
+	// scanQuota limits the memory used by the scanned events which are not sent yet.
+	scanQuota *memoryQuota
 
 	// messageCh is used to receive message from the scanWorker,
 	// and a goroutine is responsible for sending the message to the dispatchers.
@@ -81,6 +92,7 @@ type eventBroker struct {
 	metricEventServiceDispatcherResolvedTs prometheus.Gauge
 	metricEventServiceResolvedTsLag        prometheus.Gauge
 	metricScanEventDuration                prometheus.Observer
+	metricScanMemoryUsage                  prometheus.Gauge
 }
 
 type pathHasher struct {
@@ -98,6 +110,7 @@ func newEventBroker(
 	schemaStore schemastore.SchemaStore,
 	mc messaging.MessageSender,
 	tz *time.Location,
+	cfg *config.EventServiceConfig,
 ) *eventBroker {
 	ctx, cancel := context.WithCancel(ctx)
 	wg := &sync.WaitGroup{}
@@ -117,6 +130,8 @@ func newEventBroker(
 		msgSender:               mc,
 		taskPool:                newScanTaskPool(),
 		scanWorkerCount:         defaultScanWorkerCount,
+		scanLimit:               scanLimit{maxBytes: cfg.ScanMaxBytes, maxRows: cfg.ScanMaxRows},
+		scanQuota:               newMemoryQuota(cfg.ScanMemoryQuota),
 		ds:                      ds,
 		messageCh:               make(chan wrapEvent, defaultChannelSize),
 		resolvedTsCaches:        make(map[node.ID]*resolvedTsCache),
@@ -128,9 +143,11 @@ func newEventBroker(
 		metricEventServiceResolvedTsLag:        metrics.EventServiceResolvedTsLagGauge.WithLabelValues("puller"),
 		metricEventServiceDispatcherResolvedTs: metrics.EventServiceResolvedTsLagGauge.WithLabelValues("dispatcher"),
 		metricScanEventDuration:                metrics.EventServiceScanDuration,
+		metricScanMemoryUsage:                  metrics.EventServiceScanMemoryUsage,
 	}
 
 	c.runScanWorker(ctx)
+	c.runRetryScanWorker(ctx)
 	c.tickTableTriggerDispatchers(ctx)
 	c.runSendMessageWorker(ctx)
 	c.updateMetrics(ctx)
@@ -257,6 +274,10 @@ func (c *eventBroker) wakeDispatcher(dispatcherID common.DispatcherID) {
 // If the dispatcher needs to scan the event store, it returns true.
 // If the dispatcher does not need to scan the event store, it send the watermark to the dispatcher
 func (c *eventBroker) checkNeedScan(task scanTask) (bool, common.DataRange) {
+	// The error is reported to the dispatcher, don't send any more events or watermarks to it.
+	if task.dispatcherStat.failed.Load() {
+		return false, common.DataRange{}
+	}
 	c.checkAndInitDispatcher(task)
 
 	dataRange, needScan := task.dispatcherStat.getDataRange()
@@ -354,19 +375,53 @@ func (c *eventBroker) doScan(ctx context.Context, task scanTask) {
 	if !needScan {
 		return
 	}
+	// The events scanned by other tasks are not sent yet, don't read more events into memory.
+	// The dispatcher is scanned again later, since its resolved ts may not advance any more.
+	if c.scanQuota.exhausted() {
+		metricEventBrokerInterruptScanCount.WithLabelValues(interruptReasonQuota).Inc()
+		c.retryScanTask(task.dispatcherStat)
+		return
+	}
 
 	// TODO: distinguish only dml or only ddl scenario
 	ddlEvents, err := c.schemaStore.FetchTableDDLEvents(dataRange.Span.TableID, task.dispatcherStat.filter, dataRange.StartTs, dataRange.EndTs)
 	if err != nil {
-		log.Panic("get ddl events failed", zap.Error(err))
+		// No event in the data range is sent yet, so the error is reported at the start of it.
+		c.sendError(remoteID, task.dispatcherStat, dataRange.StartTs, errors.Trace(err))
+		return
 	}
 
+	// The previous scan task of the dispatcher is interrupted after sending the transaction
+	// (lastCommitTs, lastStartTs), the transactions before it with the same commitTs are skipped.
+	lastCommitTs, lastStartTs := task.dispatcherStat.getLastScannedTxn()
+	if lastCommitTs <= dataRange.StartTs {
+		lastCommitTs, lastStartTs = 0, 0
+	}
+	budget := newScanBudget(c.scanLimit)
+	// interruptedAt is the last transaction sent by this task if the scan is interrupted
+	// before reaching the end of the data range.
+	var interruptedAt *pevent.DMLEvent
+	var interruptReason string
+	// scanErr is the error which stops the scan, the events after the last sent
+	// transaction are not sent, and the watermark doesn't advance.
+	var scanErr error
+	var scanErrCommitTs uint64
+
 	// After all the events are sent, we need to
 	// drain the ddlEvents and wake up the dispatcher.
 	defer func() {
+		if scanErr != nil {
+			c.sendError(remoteID, task.dispatcherStat, scanErrCommitTs, scanErr)
+			return
+		}
+		if interruptedAt != nil {
+			c.onScanInterrupted(ctx, task, ddlEvents, interruptedAt, interruptReason)
+			return
+		}
 		for _, e := range ddlEvents {
 			c.sendDDL(ctx, remoteID, e, task.dispatcherStat)
 		}
+		task.dispatcherStat.setLastScannedTxn(0, 0)
 		task.dispatcherStat.watermark.Store(dataRange.EndTs)
 		// After all the events are sent, we send the watermark to the dispatcher.
 		c.sendWatermark(remoteID,
@@ -388,7 +443,8 @@ func (c *eventBroker) doScan(ctx context.Context, task scanTask) {
 	}()
 
 	sendDML := func(dml *pevent.DMLEvent) {
-		if dml == nil {
+		// All the rows of the transaction may be filtered out.
+		if dml == nil || dml.Len() == 0 {
 			return
 		}
 
@@ -398,12 +454,28 @@ func (c *eventBroker) doScan(ctx context.Context, task scanTask) {
 		}
 		dml.Seq = task.dispatcherStat.seq.Add(1)
 		c.emitSyncPointEventIfNeeded(dml.CommitTs, task.dispatcherStat, remoteID)
+		// The quota is released after the event is sent by the send message worker.
+		c.scanQuota.acquire(dml.GetRowsSize())
+		budget.consume(dml.GetRowsSize(), int64(dml.Len()))
 		c.messageCh <- newWrapDMLEvent(remoteID, dml, task.dispatcherStat.getEventSenderState())
 		task.dispatcherStat.metricEventServiceSendKvCount.Add(float64(dml.Len()))
 	}
 
-	// 3. Send the events to the dispatcher.
+	// rowFilter filters out the row changes before they are appended to the DMLEvent,
+	// so the ignored rows are never sent to the dispatcher.
 	var dml *pevent.DMLEvent
+	rowFilter := func(rowType pevent.RowType, preRow, row chunk.Row) (bool, error) {
+		ignore, err := task.dispatcherStat.filter.ShouldIgnoreDML(rowType, preRow, row, dml.TableInfo, dml.StartTs)
+		if err != nil {
+			return false, err
+		}
+		if ignore {
+			task.dispatcherStat.metricEventServiceFilteredRowCount.Inc()
+		}
+		return ignore, nil
+	}
+
+	// 3. Send the events to the dispatcher.
 	for {
 		//Node: The first event of the txn must return isNewTxn as true.
 		e, isNewTxn, err := iter.Next()
@@ -421,17 +493,109 @@ func (c *eventBroker) doScan(ctx context.Context, task scanTask) {
 			// there are some bugs in the eventStore.
 			log.Panic("should never Happen", zap.Uint64("commitTs", e.CRTs), zap.Uint64("watermark", task.dispatcherStat.watermark.Load()))
 		}
+		// The transaction is already sent by the previous interrupted scan task.
+		if e.CRTs == lastCommitTs && e.StartTs <= lastStartTs {
+			continue
+		}
+		// All the rows of a transaction have the same txn source,
+		// so the whole transaction written by TiCDC is skipped.
+		if task.dispatcherStat.filterLoop && e.IsWrittenByCDC() {
+			task.dispatcherStat.metricEventServiceFilteredRowCount.Inc()
+			continue
+		}
 		if isNewTxn {
 			sendDML(dml)
+			// Stop at the boundary of the transaction if the budget is used up,
+			// at least one transaction is scanned by each task to make progress.
+			if dml != nil {
+				if reason, ok := c.checkScanBudget(task.dispatcherStat, budget); ok {
+					interruptedAt, interruptReason = dml, reason
+					c.metricScanEventDuration.Observe(time.Since(start).Seconds())
+					return
+				}
+			}
 			tableID := task.dispatcherStat.info.GetTableSpan().TableID
 			tableInfo, err := c.schemaStore.GetTableInfo(tableID, e.CRTs-1)
 			if err != nil {
-				// FIXME handle the error
-				log.Panic("get table info failed", zap.Error(err))
+				scanErr, scanErrCommitTs = errors.Trace(err), e.CRTs
+				return
 			}
 			dml = pevent.NewDMLEvent(dispatcherID, tableID, e.StartTs, e.CRTs, tableInfo)
 		}
-		dml.AppendRow(e, c.mounter.DecodeToChunk)
+		if err := dml.AppendRow(e, c.mounter.DecodeToChunk, rowFilter); err != nil {
+			scanErr, scanErrCommitTs = errors.Trace(err), e.CRTs
+			return
+		}
+	}
+}
+
+// checkScanBudget returns the reason if the scan task should be interrupted.
+func (c *eventBroker) checkScanBudget(d *dispatcherStat, budget *scanBudget) (string, bool) {
+	// The event collector asks to pause the dispatcher since its memory is used up.
+	if !d.isRunning.Load() {
+		return interruptReasonPaused, true
+	}
+	if c.scanQuota.exhausted() {
+		return interruptReasonQuota, true
+	}
+	return budget.exhausted()
+}
+
+// onScanInterrupted records the progress of an interrupted scan task, and sends the
+// watermark which all the events before it are sent. The rest of the data range is
+// scanned by the next scan task, starting after the last sent transaction.
+func (c *eventBroker) onScanInterrupted(
+	ctx context.Context,
+	task scanTask,
+	ddlEvents []pevent.DDLEvent,
+	lastTxn *pevent.DMLEvent,
+	reason string,
+) {
+	metricEventBrokerInterruptScanCount.WithLabelValues(reason).Inc()
+	d := task.dispatcherStat
+	remoteID := node.ID(d.info.GetServerID())
+	d.setLastScannedTxn(lastTxn.CommitTs, lastTxn.StartTs)
+	// Other transactions with the same commitTs may not be sent yet,
+	// so the watermark can only advance to commitTs - 1.
+	watermark := lastTxn.CommitTs - 1
+	for len(ddlEvents) > 0 && ddlEvents[0].FinishedTs <= watermark {
+		c.sendDDL(ctx, remoteID, ddlEvents[0], d)
+		ddlEvents = ddlEvents[1:]
+	}
+	if watermark > d.watermark.Load() {
+		d.watermark.Store(watermark)
+		c.sendWatermark(remoteID, d, watermark, d.metricEventServiceSendResolvedTsCount)
+	}
+	log.Debug("scan task is interrupted",
+		zap.Stringer("dispatcher", d.info.GetID()),
+		zap.String("reason", reason),
+		zap.Uint64("commitTs", lastTxn.CommitTs),
+		zap.Uint64("startTs", lastTxn.StartTs))
+	// Schedule the next scan task immediately if the task used up its own budget,
+	// retry later if the memory quota is used up, and wait for the dispatcher
+	// to be resumed if it's paused.
+	switch reason {
+	case interruptReasonBytes, interruptReasonRows:
+		c.pushScanTask(d)
+	case interruptReasonQuota:
+		c.retryScanTask(d)
+	}
+}
+
+// sendError stops scanning the dispatcher and sends the error to it. The dispatcher reports
+// the error to the maintainer, so only the changefeed of the dispatcher is failed.
+func (c *eventBroker) sendError(remoteID node.ID, d *dispatcherStat, commitTs uint64, err error) {
+	log.Error("scan events failed, report the error to the dispatcher",
+		zap.Stringer("dispatcher", d.info.GetID()),
+		zap.Uint64("commitTs", commitTs),
+		zap.Error(err))
+	d.failed.Store(true)
+	errorEvent := pevent.NewErrorEvent(d.info.GetID(), commitTs, err)
+	errorEvent.State = d.getEventSenderState()
+	c.messageCh <- wrapEvent{
+		serverID: remoteID,
+		e:        errorEvent,
+		msgType:  pevent.TypeErrorEvent,
 	}
 }
 
@@ -472,6 +636,9 @@ func (c *eventBroker) runSendMessageWorker(ctx context.Context) {
 					m.e)
 				c.flushResolvedTs(ctx, m.serverID)
 				c.sendMsg(ctx, tMsg, m.postSendFunc)
+				if m.msgType == pevent.TypeDMLEvent {
+					c.scanQuota.release(m.e.(*pevent.DMLEvent).GetRowsSize())
+				}
 			case <-flushResolvedTsTicker.C:
 				for serverID := range c.resolvedTsCaches {
 					c.flushResolvedTs(ctx, serverID)
@@ -576,6 +743,7 @@ func (c *eventBroker) updateMetrics(ctx context.Context) {
 				c.metricEventServiceResolvedTsLag.Set(lag)
 				lag = float64(oracle.GetPhysical(time.Now())-oracle.ExtractPhysical(dispatcherMinWaterMark)) / 1e3
 				c.metricEventServiceDispatcherResolvedTs.Set(lag)
+				c.metricScanMemoryUsage.Set(float64(c.scanQuota.usage()))
 			}
 		}
 	}()
@@ -612,15 +780,52 @@ func (c *eventBroker) close() {
 
 func (c *eventBroker) onNotify(d *dispatcherStat, resolvedTs uint64) {
 	if d.onSubscriptionResolvedTs(resolvedTs) {
-		// Note: don't block the caller of this function.
-		select {
-		case c.ds.In() <- newScanTask(d):
-		default:
-			metricEventBrokerDropNotificationCount.Inc()
-		}
+		c.pushScanTask(d)
+	}
+}
+
+func (c *eventBroker) pushScanTask(d *dispatcherStat) {
+	// Note: don't block the caller of this function.
+	select {
+	case c.ds.In() <- newScanTask(d):
+	default:
+		metricEventBrokerDropNotificationCount.Inc()
+		// The dispatcher may stall if no more notification comes, push the task again later.
+		c.retryScanTask(d)
 	}
 }
 
+// retryScanTask pushes the scan task of the dispatcher again after scanRetryInterval,
+// the retries of the same dispatcher are merged.
+func (c *eventBroker) retryScanTask(d *dispatcherStat) {
+	c.retryScanTasks.Store(d.info.GetID(), d)
+}
+
+func (c *eventBroker) runRetryScanWorker(ctx context.Context) {
+	c.wg.Add(1)
+	ticker := time.NewTicker(scanRetryInterval)
+	go func() {
+		defer c.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.retryScanTasks.Range(func(key, value any) bool {
+					c.retryScanTasks.Delete(key)
+					d := value.(*dispatcherStat)
+					// Skip the dispatcher if it's removed or reset after the retry is scheduled.
+					if stat, ok := c.getDispatcher(d.info.GetID()); ok && stat == d {
+						c.pushScanTask(d)
+					}
+					return true
+				})
+			}
+		}
+	}()
+}
+
 func (c *eventBroker) getDispatcher(id common.DispatcherID) (*dispatcherStat, bool) {
 	stat, ok := c.dispatchers.Load(id)
 	if !ok {
@@ -716,6 +921,8 @@ func (c *eventBroker) resumeDispatcher(dispatcherInfo DispatcherInfo) {
 	log.Info("resume dispatcher", zap.Any("dispatcher", stat.info.GetID()), zap.Uint64("checkpointTs", stat.watermark.Load()), zap.Uint64("seq", stat.seq.Load()))
 	// Reset the watermark to the startTs of the dispatcherInfo.
 	stat.isRunning.Store(true)
+	// The scan task may be interrupted when the dispatcher is paused, continue to scan the rest events.
+	c.pushScanTask(stat)
 }
 
 func (c *eventBroker) resetDispatcher(dispatcherInfo DispatcherInfo) {
@@ -735,12 +942,19 @@ type dispatcherStat struct {
 	// startTableInfo is the table info of the dispatcher when it is registered or reset.
 	startTableInfo atomic.Pointer[common.TableInfo]
 	filter         filter.Filter
+	// filterLoop is true if the changefeed is in BDR mode, the events written
+	// by TiCDC are not sent to the dispatcher to avoid replication loops.
+	filterLoop bool
 	// The start ts of the dispatcher
 	startTs atomic.Uint64
 	// The max resolved ts received from event store.
 	resolvedTs atomic.Uint64
 	// The watermark of the events that have been sent to the dispatcher.
 	watermark atomic.Uint64
+	// lastScannedCommitTs and lastScannedStartTs identify the last transaction sent by
+	// an interrupted scan task, the next scan task continues after it.
+	lastScannedCommitTs atomic.Uint64
+	lastScannedStartTs  atomic.Uint64
 	// The seq of the events that have been sent to the dispatcher.
 	// It start from 1, and increase by 1 for each event.
 	// If the dispatcher is reset, the seq will be set to 1.
@@ -754,6 +968,9 @@ type dispatcherStat struct {
 	// It will be set to true, after it sends the handshake event to the dispatcher.
 	// It will be set to false, after it receives the reset event from the dispatcher.
 	isInitialized atomic.Bool
+	// failed is set to true after the events of the dispatcher can't be scanned,
+	// the dispatcher is not scanned any more until it's reset.
+	failed atomic.Bool
 
 	// syncpoint related
 	enableSyncPoint   bool
@@ -764,6 +981,7 @@ type dispatcherStat struct {
 	metricEventServiceSendKvCount         prometheus.Counter
 	metricEventServiceSendDDLCount        prometheus.Counter
 	metricEventServiceSendResolvedTsCount prometheus.Counter
+	metricEventServiceFilteredRowCount    prometheus.Counter
 }
 
 func newDispatcherStat(
@@ -775,10 +993,12 @@ func newDispatcherStat(
 	dispStat := &dispatcherStat{
 		info:                                  info,
 		filter:                                filter,
+		filterLoop:                            info.GetBDRMode(),
 		metricSorterOutputEventCountKV:        metrics.SorterOutputEventCount.WithLabelValues(changefeedID.Namespace(), changefeedID.Name(), "kv"),
 		metricEventServiceSendKvCount:         metrics.EventServiceSendEventCount.WithLabelValues(changefeedID.Namespace(), changefeedID.Name(), "kv"),
 		metricEventServiceSendDDLCount:        metrics.EventServiceSendEventCount.WithLabelValues(changefeedID.Namespace(), changefeedID.Name(), "ddl"),
 		metricEventServiceSendResolvedTsCount: metrics.EventServiceSendEventCount.WithLabelValues(changefeedID.Namespace(), changefeedID.Name(), "resolved_ts"),
+		metricEventServiceFilteredRowCount:    metrics.EventServiceFilteredRowCount.WithLabelValues(changefeedID.Namespace(), changefeedID.Name()),
 	}
 	if info.SyncPointEnabled() {
 		dispStat.enableSyncPoint = true
@@ -799,6 +1019,15 @@ func (a *dispatcherStat) getEventSenderState() pevent.EventSenderState {
 	return pevent.EventSenderStatePaused
 }
 
+func (a *dispatcherStat) getLastScannedTxn() (uint64, uint64) {
+	return a.lastScannedCommitTs.Load(), a.lastScannedStartTs.Load()
+}
+
+func (a *dispatcherStat) setLastScannedTxn(commitTs, startTs uint64) {
+	a.lastScannedCommitTs.Store(commitTs)
+	a.lastScannedStartTs.Store(startTs)
+}
+
 func (a *dispatcherStat) updateTableInfo(tableInfo *common.TableInfo) {
 	a.startTableInfo.Store(tableInfo)
 }
@@ -2,6 +2,7 @@ package eventservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/pingcap/ticdc/heartbeatpb"
 	"github.com/pingcap/ticdc/pkg/common"
 	pevent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/messaging"
 	"github.com/stretchr/testify/require"
 )
@@ -182,7 +184,7 @@ func TestSendEvents(t *testing.T) {
 		}
 	}()
 
-	s := newEventBroker(ctx, 1, eventStore, schemaStore, mc, time.Local)
+	s := newEventBroker(ctx, 1, eventStore, schemaStore, mc, time.Local, config.NewDefaultEventServiceConfig())
 	defer s.close()
 
 	// Register the dispatcher
@@ -201,3 +203,302 @@ func TestSendEvents(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestSendErrorEvent(t *testing.T) {
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	ddlEvent, kvEvents := genEvents(helper, t, `create table test.t(id int primary key, c char(50))`, []string{
+		`insert into test.t(id,c) values (0, "c0")`,
+	}...)
+	require.NotNil(t, kvEvents)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventStore := newMockEventStore(100)
+	schemaStore := newMockSchemaStore()
+	msgCh := make(chan *messaging.TargetMessage, 1024)
+	mc := &mockMessageCenter{messageCh: msgCh}
+
+	s := newEventBroker(ctx, 1, eventStore, schemaStore, mc, time.Local, config.NewDefaultEventServiceConfig())
+	defer s.close()
+
+	tableID := ddlEvent.TableID
+	info := newMockDispatcherInfo(common.NewDispatcherID(), tableID, eventpb.ActionType_ACTION_TYPE_REGISTER)
+	s.addDispatcher(info)
+	schemaStore.tableInfoErr = errors.New("table info not found")
+
+	v, ok := eventStore.spansMap.Load(tableID)
+	require.True(t, ok)
+	span := v.(*mockSpanStats)
+	span.update(kvEvents[0].CRTs+1, kvEvents...)
+
+	// The error is sent to the dispatcher instead of panicking the server,
+	// and the dispatcher is not scanned any more.
+	var errorEvent *pevent.ErrorEvent
+	require.Eventually(t, func() bool {
+		select {
+		case msgs := <-msgCh:
+			for _, msg := range msgs.Message {
+				if e, ok := msg.(*pevent.ErrorEvent); ok {
+					errorEvent = e
+				}
+			}
+		default:
+		}
+		return errorEvent != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, info.GetID(), errorEvent.GetDispatcherID())
+	require.Equal(t, kvEvents[0].CRTs, errorEvent.GetCommitTs())
+	require.Contains(t, errorEvent.GetError().Error(), "table info not found")
+
+	stat, ok := s.getDispatcher(info.GetID())
+	require.True(t, ok)
+	require.True(t, stat.failed.Load())
+	needScan, _ := s.checkNeedScan(newScanTask(stat))
+	require.False(t, needScan)
+}
+
+func TestSendErrorEventWhenFetchDDLEventsFailed(t *testing.T) {
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	ddlEvent, kvEvents := genEvents(helper, t, `create table test.t(id int primary key, c char(50))`, []string{
+		`insert into test.t(id,c) values (0, "c0")`,
+	}...)
+	require.NotNil(t, kvEvents)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventStore := newMockEventStore(100)
+	schemaStore := newMockSchemaStore()
+	msgCh := make(chan *messaging.TargetMessage, 1024)
+	mc := &mockMessageCenter{messageCh: msgCh}
+
+	s := newEventBroker(ctx, 1, eventStore, schemaStore, mc, time.Local, config.NewDefaultEventServiceConfig())
+	defer s.close()
+
+	tableID := ddlEvent.TableID
+	info := newMockDispatcherInfo(common.NewDispatcherID(), tableID, eventpb.ActionType_ACTION_TYPE_REGISTER)
+	s.addDispatcher(info)
+	schemaStore.ddlEventsErr = errors.New("ddl events not found")
+
+	v, ok := eventStore.spansMap.Load(tableID)
+	require.True(t, ok)
+	span := v.(*mockSpanStats)
+	span.update(kvEvents[0].CRTs+1, kvEvents...)
+
+	// The error is sent to the dispatcher instead of panicking the server,
+	// and no dml event is sent before it.
+	var errorEvent *pevent.ErrorEvent
+	require.Eventually(t, func() bool {
+		select {
+		case msgs := <-msgCh:
+			for _, msg := range msgs.Message {
+				if _, ok := msg.(*pevent.DMLEvent); ok {
+					require.Fail(t, "unexpected dml event")
+				}
+				if e, ok := msg.(*pevent.ErrorEvent); ok {
+					errorEvent = e
+				}
+			}
+		default:
+		}
+		return errorEvent != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, info.GetID(), errorEvent.GetDispatcherID())
+	require.Contains(t, errorEvent.GetError().Error(), "ddl events not found")
+
+	stat, ok := s.getDispatcher(info.GetID())
+	require.True(t, ok)
+	require.True(t, stat.failed.Load())
+}
+
+func TestScanInterruptedInTxnsWithSameCommitTs(t *testing.T) {
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	ddlEvent, kvEvents := genEvents(helper, t, `create table test.t(id int primary key, c char(50))`, []string{
+		`insert into test.t(id,c) values (0, "c0")`,
+		`insert into test.t(id,c) values (1, "c1")`,
+		`insert into test.t(id,c) values (2, "c2")`,
+	}...)
+	require.Len(t, kvEvents, 3)
+
+	// txn1 and txn2 have the same commitTs, and a ddl is between txn2 and txn3.
+	base := kvEvents[0].CRTs
+	newKV := func(e *common.RawKVEntry, startTs, commitTs uint64) *common.RawKVEntry {
+		kv := *e
+		kv.StartTs, kv.CRTs = startTs, commitTs
+		return &kv
+	}
+	txns := []*common.RawKVEntry{
+		newKV(kvEvents[0], base, base+10),
+		newKV(kvEvents[1], base+1, base+10),
+		newKV(kvEvents[2], base+2, base+20),
+	}
+	ddlEvent1 := ddlEvent
+	ddlEvent1.FinishedTs = base + 15
+
+	type sentEvent struct {
+		t        int
+		commitTs uint64
+		startTs  uint64
+	}
+	expected := []sentEvent{
+		{t: pevent.TypeDDLEvent, commitTs: ddlEvent.FinishedTs},
+		{t: pevent.TypeDMLEvent, commitTs: base + 10, startTs: base},
+		{t: pevent.TypeDMLEvent, commitTs: base + 10, startTs: base + 1},
+		{t: pevent.TypeDDLEvent, commitTs: base + 15},
+		{t: pevent.TypeDMLEvent, commitTs: base + 20, startTs: base + 2},
+	}
+
+	for _, cfg := range []*config.EventServiceConfig{
+		// Case 1: each scan task sends one transaction
+		{ScanMaxRows: 1},
+		// Case 2: each transaction uses up the bytes budget of a scan task
+		{ScanMaxBytes: 1},
+		// Case 3: the scan tasks are interrupted by the memory quota and retried later
+		{ScanMemoryQuota: 1},
+	} {
+		ctx, cancel := context.WithCancel(context.Background())
+		eventStore := newMockEventStore(100)
+		schemaStore := newMockSchemaStore()
+		msgCh := make(chan *messaging.TargetMessage, 1024)
+		mc := &mockMessageCenter{messageCh: msgCh}
+		s := newEventBroker(ctx, 1, eventStore, schemaStore, mc, time.Local, cfg)
+
+		tableID := ddlEvent.TableID
+		info := newMockDispatcherInfo(common.NewDispatcherID(), tableID, eventpb.ActionType_ACTION_TYPE_REGISTER)
+		s.addDispatcher(info)
+		schemaStore.AppendDDLEvent(tableID, ddlEvent, ddlEvent1)
+
+		v, ok := eventStore.spansMap.Load(tableID)
+		require.True(t, ok)
+		span := v.(*mockSpanStats)
+		span.update(base+30, txns...)
+
+		var received []sentEvent
+		lastSeq := uint64(1) // the seq of the handshake event
+		timeout := time.After(10 * time.Second)
+		for len(received) < len(expected) {
+			select {
+			case <-timeout:
+				require.FailNow(t, "receive events timeout", "received: %+v", received)
+			case msgs := <-msgCh:
+				for _, msg := range msgs.Message {
+					switch e := msg.(type) {
+					case *pevent.DMLEvent:
+						require.Equal(t, lastSeq+1, e.GetSeq())
+						lastSeq = e.GetSeq()
+						received = append(received, sentEvent{t: pevent.TypeDMLEvent, commitTs: e.CommitTs, startTs: e.StartTs})
+					case *pevent.DDLEvent:
+						require.Equal(t, lastSeq+1, e.GetSeq())
+						lastSeq = e.GetSeq()
+						received = append(received, sentEvent{t: pevent.TypeDDLEvent, commitTs: e.FinishedTs})
+					case *pevent.BatchResolvedEvent:
+						// All the events before the watermark must be sent before it.
+						for _, r := range e.Events {
+							if r.DispatcherID != info.GetID() {
+								continue
+							}
+							for _, ev := range expected[len(received):] {
+								require.Greater(t, ev.commitTs, r.ResolvedTs, "received: %+v", received)
+							}
+						}
+					}
+				}
+			}
+		}
+		// No transaction is sent twice or skipped.
+		require.Equal(t, expected, received)
+		s.close()
+		cancel()
+	}
+}
+
+func TestScanFilterTxnsWrittenByCDC(t *testing.T) {
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	ddlEvent, kvEvents := genEvents(helper, t, `create table test.t(id int primary key, c char(50))`, []string{
+		`insert into test.t(id,c) values (0, "c0")`,
+		`insert into test.t(id,c) values (1, "c1")`,
+		`insert into test.t(id,c) values (2, "c2")`,
+	}...)
+	require.Len(t, kvEvents, 3)
+
+	// txn2 is written by a TiCDC changefeed.
+	base := kvEvents[0].CRTs
+	newKV := func(e *common.RawKVEntry, startTs, commitTs, txnSource uint64) *common.RawKVEntry {
+		kv := *e
+		kv.StartTs, kv.CRTs, kv.TxnSource = startTs, commitTs, txnSource
+		return &kv
+	}
+	txns := []*common.RawKVEntry{
+		newKV(kvEvents[0], base, base+10, 0),
+		newKV(kvEvents[1], base+1, base+20, 1),
+		newKV(kvEvents[2], base+2, base+30, 0),
+	}
+
+	for _, tc := range []struct {
+		bdrMode  bool
+		expected []uint64
+	}{
+		// Case 1: the txns written by TiCDC are skipped in BDR mode
+		{bdrMode: true, expected: []uint64{base + 10, base + 30}},
+		// Case 2: all the txns are sent if it's not in BDR mode
+		{bdrMode: false, expected: []uint64{base + 10, base + 20, base + 30}},
+	} {
+		ctx, cancel := context.WithCancel(context.Background())
+		eventStore := newMockEventStore(100)
+		schemaStore := newMockSchemaStore()
+		msgCh := make(chan *messaging.TargetMessage, 1024)
+		mc := &mockMessageCenter{messageCh: msgCh}
+		s := newEventBroker(ctx, 1, eventStore, schemaStore, mc, time.Local, config.NewDefaultEventServiceConfig())
+
+		tableID := ddlEvent.TableID
+		info := newMockDispatcherInfo(common.NewDispatcherID(), tableID, eventpb.ActionType_ACTION_TYPE_REGISTER)
+		info.bdrMode = tc.bdrMode
+		s.addDispatcher(info)
+		schemaStore.AppendDDLEvent(tableID, ddlEvent)
+
+		v, ok := eventStore.spansMap.Load(tableID)
+		require.True(t, ok)
+		span := v.(*mockSpanStats)
+		span.update(base+40, txns...)
+
+		// collect the dmls until all the events before the watermark are sent.
+		var received []uint64
+		resolved := false
+		timeout := time.After(10 * time.Second)
+		for !resolved {
+			select {
+			case <-timeout:
+				require.FailNow(t, "receive events timeout", "received: %+v", received)
+			case msgs := <-msgCh:
+				for _, msg := range msgs.Message {
+					switch e := msg.(type) {
+					case *pevent.DMLEvent:
+						received = append(received, e.CommitTs)
+					case *pevent.BatchResolvedEvent:
+						for _, r := range e.Events {
+							if r.DispatcherID == info.GetID() && r.ResolvedTs >= base+40 {
+								resolved = true
+							}
+						}
+					}
+				}
+			}
+		}
+		require.Equal(t, tc.expected, received)
+		s.close()
+		cancel()
+	}
+}
@@ -34,6 +34,9 @@ type DispatcherInfo interface {
 	GetActionType() eventpb.ActionType
 	GetChangefeedID() common.ChangeFeedID
 	GetFilterConfig() *config.FilterConfig
+	// GetBDRMode returns whether the changefeed is in BDR mode, the events
+	// written by TiCDC are filtered out in BDR mode to avoid replication loops.
+	GetBDRMode() bool
 
 	// sync point related
 	SyncPointEnabled() bool
@@ -47,6 +50,7 @@ type eventService struct {
 	mc          messaging.MessageCenter
 	eventStore  eventstore.EventStore
 	schemaStore schemastore.SchemaStore
+	cfg         *config.EventServiceConfig
 	// clusterID -> eventBroker
 	brokers map[uint64]*eventBroker
 
@@ -55,12 +59,13 @@ type eventService struct {
 	tz             *time.Location
 }
 
-func New(eventStore eventstore.EventStore, schemaStore schemastore.SchemaStore) common.SubModule {
+func New(eventStore eventstore.EventStore, schemaStore schemastore.SchemaStore, cfg *config.EventServiceConfig) common.SubModule {
 	mc := appcontext.GetService[messaging.MessageCenter](appcontext.MessageCenter)
 	es := &eventService{
 		mc:             mc,
 		eventStore:     eventStore,
 		schemaStore:    schemaStore,
+		cfg:            cfg,
 		brokers:        make(map[uint64]*eventBroker),
 		dispatcherInfo: make(chan DispatcherInfo, defaultChannelSize*16),
 		tz:             time.Local, // FIXME use the timezone from the config
@@ -124,7 +129,7 @@ func (s *eventService) registerDispatcher(ctx context.Context, info DispatcherIn
 	clusterID := info.GetClusterID()
 	c, ok := s.brokers[clusterID]
 	if !ok {
-		c = newEventBroker(ctx, clusterID, s.eventStore, s.schemaStore, s.mc, s.tz)
+		c = newEventBroker(ctx, clusterID, s.eventStore, s.schemaStore, s.mc, s.tz, s.cfg)
 		s.brokers[clusterID] = c
 	}
 	c.addDispatcher(info)
@@ -22,9 +22,9 @@ import (
 	appcontext "github.com/pingcap/ticdc/pkg/common/context"
 	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
 	pevent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/filter"
 	"github.com/pingcap/ticdc/pkg/messaging"
-	tconfig "github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -37,7 +37,7 @@ func initEventService(
 	appcontext.SetService(appcontext.MessageCenter, mc)
 	appcontext.SetService(appcontext.EventStore, mockStore)
 	appcontext.SetService(appcontext.SchemaStore, mockSchemaStore)
-	es := New(mockStore, mockSchemaStore)
+	es := New(mockStore, mockSchemaStore, config.NewDefaultEventServiceConfig())
 	esImpl := es.(*eventService)
 	go func() {
 		err := esImpl.Run(ctx)
@@ -137,14 +137,19 @@ func (m *mockEventStore) Close(ctx context.Context) error {
 	return nil
 }
 
-func (m *mockEventStore) UpdateDispatcherSendTs(dispatcherID common.DispatcherID, span *heartbeatpb.TableSpan, gcTS uint64) error {
+func (m *mockEventStore) UpdateDispatcherSendTs(dispatcherID common.DispatcherID, sendTs uint64) error {
 	return nil
 }
 
-func (m *mockEventStore) UnregisterDispatcher(dispatcherID common.DispatcherID, span *heartbeatpb.TableSpan) error {
+func (m *mockEventStore) UnregisterDispatcher(dispatcherID common.DispatcherID) error {
 	return nil
 }
 
+// GetDispatcherDMLEventState always reports new events, so the dispatcher is always scanned.
+func (m *mockEventStore) GetDispatcherDMLEventState(dispatcherID common.DispatcherID) eventstore.DMLEventState {
+	return eventstore.DMLEventState{MaxEventCommitTs: math.MaxUint64}
+}
+
 func (m *mockEventStore) GetIterator(dispatcherID common.DispatcherID, dataRange common.DataRange) (eventstore.EventIterator, error) {
 	iter := &mockEventIterator{
 		events: make([]*common.RawKVEntry, 0),
@@ -165,18 +170,16 @@ func (m *mockEventStore) GetIterator(dispatcherID common.DispatcherID, dataRange
 func (m *mockEventStore) RegisterDispatcher(
 	dispatcherID common.DispatcherID,
 	span *heartbeatpb.TableSpan,
-	startTS common.Ts,
-	observer eventstore.EventObserver,
-	notifier eventstore.WatermarkNotifier,
+	startTS uint64,
+	notifier eventstore.ResolvedTsNotifier,
 ) error {
-	log.Info("subscribe table span", zap.Any("span", span), zap.Uint64("startTs", uint64(startTS)))
+	log.Info("subscribe table span", zap.Any("span", span), zap.Uint64("startTs", startTS))
 	spanStats := &mockSpanStats{
-		startTs:           uint64(startTS),
+		startTs:           startTS,
 		watermarkNotifier: notifier,
-		eventObserver:     observer,
 		pendingEvents:     make([]*common.RawKVEntry, 0),
 	}
-	spanStats.watermark.Store(uint64(startTS))
+	spanStats.watermark.Store(startTS)
 	m.spansMap.Store(span.TableID, spanStats)
 	return nil
 }
@@ -216,6 +219,10 @@ type mockSchemaStore struct {
 	TableInfo map[common.TableID][]*common.TableInfo
 
 	resolvedTs uint64
+	// tableInfoErr is returned by GetTableInfo if it's not nil.
+	tableInfoErr error
+	// ddlEventsErr is returned by FetchTableDDLEvents if it's not nil.
+	ddlEventsErr error
 }
 
 func newMockSchemaStore() *mockSchemaStore {
@@ -246,6 +253,9 @@ func (m *mockSchemaStore) AppendDDLEvent(id common.TableID, ddls ...commonEvent.
 }
 
 func (m *mockSchemaStore) GetTableInfo(tableID common.TableID, ts common.Ts) (*common.TableInfo, error) {
+	if m.tableInfoErr != nil {
+		return nil, m.tableInfoErr
+	}
 	infos := m.TableInfo[tableID]
 	idx := sort.Search(len(infos), func(i int) bool {
 		return infos[i].UpdateTS > uint64(ts)
@@ -281,7 +291,9 @@ func (m *mockSchemaStore) UnregisterTable(tableID int64) error {
 
 // GetNextDDLEvents returns the next ddl event which finishedTs is within the range (start, end]
 func (m *mockSchemaStore) FetchTableDDLEvents(tableID int64, tableFilter filter.Filter, start, end uint64) ([]commonEvent.DDLEvent, error) {
-
+	if m.ddlEventsErr != nil {
+		return nil, m.ddlEventsErr
+	}
 	events := m.DDLEvents[tableID]
 	if len(events) == 0 {
 		return nil, nil
@@ -295,7 +307,6 @@ func (m *mockSchemaStore) FetchTableDDLEvents(tableID int64, tableFilter filter.
 	r := sort.Search(len(events), func(i int) bool {
 		return events[i].FinishedTs > end
 	})
-	m.DDLEvents[tableID] = events[r:]
 	return events[l:r], nil
 }
 
@@ -307,16 +318,12 @@ type mockSpanStats struct {
 	startTs           uint64
 	watermark         atomic.Uint64
 	pendingEvents     []*common.RawKVEntry
-	eventObserver     func(watermark uint64)
 	watermarkNotifier func(watermark uint64)
 }
 
 func (m *mockSpanStats) update(watermark uint64, events ...*common.RawKVEntry) {
 	m.pendingEvents = append(m.pendingEvents, events...)
 	m.watermark.Store(watermark)
-	for _, e := range events {
-		m.eventObserver(e.CRTs)
-	}
 	m.watermarkNotifier(watermark)
 }
 
@@ -329,6 +336,7 @@ type mockDispatcherInfo struct {
 	span       *heartbeatpb.TableSpan
 	startTs    uint64
 	actionType eventpb.ActionType
+	bdrMode    bool
 }
 
 func newMockDispatcherInfo(dispatcherID common.DispatcherID, tableID int64, actionType eventpb.ActionType) *mockDispatcherInfo {
@@ -375,16 +383,20 @@ func (m *mockDispatcherInfo) GetActionType() eventpb.ActionType {
 	return m.actionType
 }
 
-func (m *mockDispatcherInfo) GetChangefeedID() (namespace, id string) {
-	return "default", "test"
+func (m *mockDispatcherInfo) GetChangefeedID() common.ChangeFeedID {
+	return common.NewChangeFeedIDWithName("test")
 }
 
-func (m *mockDispatcherInfo) GetFilterConfig() *tconfig.FilterConfig {
-	return &tconfig.FilterConfig{
+func (m *mockDispatcherInfo) GetFilterConfig() *config.FilterConfig {
+	return &config.FilterConfig{
 		Rules: []string{"*.*"},
 	}
 }
 
+func (m *mockDispatcherInfo) GetBDRMode() bool {
+	return m.bdrMode
+}
+
 func (m *mockDispatcherInfo) SyncPointEnabled() bool {
 	return false
 }
@@ -0,0 +1,81 @@
+package eventservice
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// scanRetryInterval is the interval to push the scan task of a dispatcher again,
+// if the task can't be executed or queued now.
+const scanRetryInterval = 50 * time.Millisecond
+
+const (
+	interruptReasonBytes  = "bytes"
+	interruptReasonRows   = "rows"
+	interruptReasonQuota  = "quota"
+	interruptReasonPaused = "paused"
+)
+
+// scanLimit is the budget of a single scan task.
+// A scan task stops at the boundary of a transaction once the budget is used up,
+// and the rest of the data range is scanned by the next scan task.
+type scanLimit struct {
+	maxBytes int64
+	maxRows  int64
+}
+
+// scanBudget tracks the usage of a scanLimit during a scan task.
+type scanBudget struct {
+	limit scanLimit
+	bytes int64
+	rows  int64
+}
+
+func newScanBudget(limit scanLimit) *scanBudget {
+	return &scanBudget{limit: limit}
+}
+
+func (b *scanBudget) consume(bytes int64, rows int64) {
+	b.bytes += bytes
+	b.rows += rows
+}
+
+// exhausted returns the reason if the budget is used up.
+func (b *scanBudget) exhausted() (string, bool) {
+	if b.limit.maxBytes > 0 && b.bytes >= b.limit.maxBytes {
+		return interruptReasonBytes, true
+	}
+	if b.limit.maxRows > 0 && b.rows >= b.limit.maxRows {
+		return interruptReasonRows, true
+	}
+	return "", false
+}
+
+// memoryQuota limits the bytes of the events scanned by all the scan tasks
+// of a broker, which are still in the message channel waiting to be sent.
+type memoryQuota struct {
+	quota int64
+	used  atomic.Int64
+}
+
+func newMemoryQuota(quota int64) *memoryQuota {
+	return &memoryQuota{quota: quota}
+}
+
+// acquire always succeeds since the event is already in memory,
+// the scan tasks should check exhausted before reading more events.
+func (q *memoryQuota) acquire(bytes int64) {
+	q.used.Add(bytes)
+}
+
+func (q *memoryQuota) release(bytes int64) {
+	q.used.Add(-bytes)
+}
+
+func (q *memoryQuota) exhausted() bool {
+	return q.quota > 0 && q.used.Load() >= q.quota
+}
+
+func (q *memoryQuota) usage() int64 {
+	return q.used.Load()
+}
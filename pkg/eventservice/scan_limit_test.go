@@ -0,0 +1,53 @@
+package eventservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanBudget(t *testing.T) {
+	budget := newScanBudget(scanLimit{maxBytes: 100, maxRows: 10})
+	_, ok := budget.exhausted()
+	require.False(t, ok)
+
+	budget.consume(60, 5)
+	_, ok = budget.exhausted()
+	require.False(t, ok)
+
+	// Case 1: the bytes budget is used up
+	budget.consume(40, 1)
+	reason, ok := budget.exhausted()
+	require.True(t, ok)
+	require.Equal(t, interruptReasonBytes, reason)
+
+	// Case 2: the rows budget is used up
+	budget = newScanBudget(scanLimit{maxBytes: 100, maxRows: 10})
+	budget.consume(10, 10)
+	reason, ok = budget.exhausted()
+	require.True(t, ok)
+	require.Equal(t, interruptReasonRows, reason)
+
+	// Case 3: zero means no limit
+	budget = newScanBudget(scanLimit{})
+	budget.consume(1024, 1024)
+	_, ok = budget.exhausted()
+	require.False(t, ok)
+}
+
+func TestMemoryQuota(t *testing.T) {
+	quota := newMemoryQuota(100)
+	require.False(t, quota.exhausted())
+
+	quota.acquire(80)
+	require.False(t, quota.exhausted())
+	// acquire never blocks, the quota can be exceeded by the last event.
+	quota.acquire(80)
+	require.True(t, quota.exhausted())
+	require.Equal(t, int64(160), quota.usage())
+
+	quota.release(80)
+	require.False(t, quota.exhausted())
+	quota.release(80)
+	require.Equal(t, int64(0), quota.usage())
+}
@@ -16,6 +16,8 @@ package filter
 import (
 	"testing"
 
+	timodel "github.com/pingcap/tidb/pkg/meta/model"
+	bf "github.com/pingcap/tiflow/pkg/binlog-filter"
 	"github.com/stretchr/testify/require"
 )
 
@@ -34,3 +36,16 @@ func TestSingleTableDDL(t *testing.T) {
 	}
 	require.Equal(t, len(singleTableDDLs)+len(multiTableDDLs)+len(globalTableDDLs), len(ddlWhiteListMap))
 }
+
+func TestBlockDDL(t *testing.T) {
+	blockDDL := []bf.EventType{bf.DropTable, bf.TruncateTable, bf.DropColumn}
+	require.NoError(t, verifyBlockDDL(blockDDL))
+	require.Error(t, verifyBlockDDL([]bf.EventType{bf.InsertEvent}))
+	require.Error(t, verifyBlockDDL([]bf.EventType{"drop everything"}))
+
+	require.True(t, IsBlockedDDL(blockDDL, timodel.ActionDropTable))
+	require.True(t, IsBlockedDDL(blockDDL, timodel.ActionTruncateTable))
+	require.True(t, IsBlockedDDL(blockDDL, timodel.ActionDropColumn))
+	require.False(t, IsBlockedDDL(blockDDL, timodel.ActionAddColumn))
+	require.False(t, IsBlockedDDL(nil, timodel.ActionDropTable))
+}
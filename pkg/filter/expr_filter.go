@@ -19,8 +19,11 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
 	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/tidb/pkg/expression"
+	timodel "github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/types"
@@ -38,8 +41,8 @@ import (
 // but have slightly changed to fit the usage of cdc.
 type dmlExprFilterRule struct {
 	mu sync.Mutex
-	// Cache tableInfos to check if the table was changed.
-	tables map[string]*model.TableInfo
+	// Cache the version of tableInfos to check if the table was changed.
+	tableVersions map[string]uint64
 
 	insertExprs    map[string]expression.Expression // tableName -> expr
 	updateOldExprs map[string]expression.Expression // tableName -> expr
@@ -63,7 +66,7 @@ func newExprFilterRule(
 	}
 
 	ret := &dmlExprFilterRule{
-		tables:         make(map[string]*model.TableInfo),
+		tableVersions:  make(map[string]uint64),
 		insertExprs:    make(map[string]expression.Expression),
 		updateOldExprs: make(map[string]expression.Expression),
 		updateNewExprs: make(map[string]expression.Expression),
@@ -113,28 +116,28 @@ func (r *dmlExprFilterRule) verify(tableInfos []*model.TableInfo) error {
 			continue
 		}
 		if r.config.IgnoreInsertValueExpr != "" {
-			e, err := r.getSimpleExprOfTable(r.config.IgnoreInsertValueExpr, ti)
+			e, err := r.getSimpleExprOfTable(r.config.IgnoreInsertValueExpr, tableName, ti.TableInfo)
 			if err != nil {
 				return err
 			}
 			r.insertExprs[tableName] = e
 		}
 		if r.config.IgnoreUpdateOldValueExpr != "" {
-			e, err := r.getSimpleExprOfTable(r.config.IgnoreUpdateOldValueExpr, ti)
+			e, err := r.getSimpleExprOfTable(r.config.IgnoreUpdateOldValueExpr, tableName, ti.TableInfo)
 			if err != nil {
 				return err
 			}
 			r.updateOldExprs[tableName] = e
 		}
 		if r.config.IgnoreUpdateNewValueExpr != "" {
-			e, err := r.getSimpleExprOfTable(r.config.IgnoreUpdateNewValueExpr, ti)
+			e, err := r.getSimpleExprOfTable(r.config.IgnoreUpdateNewValueExpr, tableName, ti.TableInfo)
 			if err != nil {
 				return err
 			}
 			r.updateNewExprs[tableName] = e
 		}
 		if r.config.IgnoreDeleteValueExpr != "" {
-			e, err := r.getSimpleExprOfTable(r.config.IgnoreDeleteValueExpr, ti)
+			e, err := r.getSimpleExprOfTable(r.config.IgnoreDeleteValueExpr, tableName, ti.TableInfo)
 			if err != nil {
 				return err
 			}
@@ -154,15 +157,14 @@ func (r *dmlExprFilterRule) resetExpr(tableName string) {
 
 // getInsertExprs returns the expression filter to filter INSERT events.
 // This function will lazy calculate expressions if not initialized.
-func (r *dmlExprFilterRule) getInsertExpr(ti *model.TableInfo) (
+func (r *dmlExprFilterRule) getInsertExpr(tableName string, ti *timodel.TableInfo) (
 	expression.Expression, error,
 ) {
-	tableName := ti.TableName.String()
 	if r.insertExprs[tableName] != nil {
 		return r.insertExprs[tableName], nil
 	}
 	if r.config.IgnoreInsertValueExpr != "" {
-		expr, err := r.getSimpleExprOfTable(r.config.IgnoreInsertValueExpr, ti)
+		expr, err := r.getSimpleExprOfTable(r.config.IgnoreInsertValueExpr, tableName, ti)
 		if err != nil {
 			return nil, err
 		}
@@ -171,16 +173,15 @@ func (r *dmlExprFilterRule) getInsertExpr(ti *model.TableInfo) (
 	return r.insertExprs[tableName], nil
 }
 
-func (r *dmlExprFilterRule) getUpdateOldExpr(ti *model.TableInfo) (
+func (r *dmlExprFilterRule) getUpdateOldExpr(tableName string, ti *timodel.TableInfo) (
 	expression.Expression, error,
 ) {
-	tableName := ti.TableName.String()
 	if r.updateOldExprs[tableName] != nil {
 		return r.updateOldExprs[tableName], nil
 	}
 
 	if r.config.IgnoreUpdateOldValueExpr != "" {
-		expr, err := r.getSimpleExprOfTable(r.config.IgnoreUpdateOldValueExpr, ti)
+		expr, err := r.getSimpleExprOfTable(r.config.IgnoreUpdateOldValueExpr, tableName, ti)
 		if err != nil {
 			return nil, err
 		}
@@ -189,16 +190,15 @@ func (r *dmlExprFilterRule) getUpdateOldExpr(ti *model.TableInfo) (
 	return r.updateOldExprs[tableName], nil
 }
 
-func (r *dmlExprFilterRule) getUpdateNewExpr(ti *model.TableInfo) (
+func (r *dmlExprFilterRule) getUpdateNewExpr(tableName string, ti *timodel.TableInfo) (
 	expression.Expression, error,
 ) {
-	tableName := ti.TableName.String()
 	if r.updateNewExprs[tableName] != nil {
 		return r.updateNewExprs[tableName], nil
 	}
 
 	if r.config.IgnoreUpdateNewValueExpr != "" {
-		expr, err := r.getSimpleExprOfTable(r.config.IgnoreUpdateNewValueExpr, ti)
+		expr, err := r.getSimpleExprOfTable(r.config.IgnoreUpdateNewValueExpr, tableName, ti)
 		if err != nil {
 			return nil, err
 		}
@@ -207,16 +207,15 @@ func (r *dmlExprFilterRule) getUpdateNewExpr(ti *model.TableInfo) (
 	return r.updateNewExprs[tableName], nil
 }
 
-func (r *dmlExprFilterRule) getDeleteExpr(ti *model.TableInfo) (
+func (r *dmlExprFilterRule) getDeleteExpr(tableName string, ti *timodel.TableInfo) (
 	expression.Expression, error,
 ) {
-	tableName := ti.TableName.String()
 	if r.deleteExprs[tableName] != nil {
 		return r.deleteExprs[tableName], nil
 	}
 
 	if r.config.IgnoreDeleteValueExpr != "" {
-		expr, err := r.getSimpleExprOfTable(r.config.IgnoreDeleteValueExpr, ti)
+		expr, err := r.getSimpleExprOfTable(r.config.IgnoreDeleteValueExpr, tableName, ti)
 		if err != nil {
 			return nil, err
 		}
@@ -227,9 +226,10 @@ func (r *dmlExprFilterRule) getDeleteExpr(ti *model.TableInfo) (
 
 func (r *dmlExprFilterRule) getSimpleExprOfTable(
 	expr string,
-	ti *model.TableInfo,
+	tableName string,
+	ti *timodel.TableInfo,
 ) (expression.Expression, error) {
-	e, err := expression.ParseSimpleExprWithTableInfo(r.sessCtx.GetExprCtx(), expr, ti.TableInfo)
+	e, err := expression.ParseSimpleExprWithTableInfo(r.sessCtx.GetExprCtx(), expr, ti)
 	if err != nil {
 		// If an expression contains an unknown column,
 		// we return an error and stop the changefeed.
@@ -238,7 +238,7 @@ func (r *dmlExprFilterRule) getSimpleExprOfTable(
 				zap.String("expression", expr),
 				zap.Error(err))
 			return nil, cerror.ErrExpressionColumnNotFound.
-				FastGenByArgs(getColumnFromError(err), ti.TableName.String(), expr)
+				FastGenByArgs(getColumnFromError(err), tableName, expr)
 		}
 		log.Error("failed to parse expression", zap.Error(err))
 		return nil, cerror.ErrExpressionParseFailed.FastGenByArgs(err, expr)
@@ -255,21 +255,11 @@ func (r *dmlExprFilterRule) shouldSkipDML(
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
-
-	if oldTi, ok := r.tables[tableName]; ok {
-		// If one table's tableInfo was updated, we need to reset this rule
-		// and update the tableInfo in the cache.
-		if ti.Version != oldTi.Version {
-			r.tables[tableName] = ti.Clone()
-			r.resetExpr(ti.TableName.String())
-		}
-	} else {
-		r.tables[tableName] = ti.Clone()
-	}
+	r.checkTableVersion(tableName, ti.Version)
 
 	switch {
 	case row.IsInsert():
-		exprs, err := r.getInsertExpr(ti)
+		exprs, err := r.getInsertExpr(tableName, ti.TableInfo)
 		if err != nil {
 			return false, err
 		}
@@ -278,11 +268,11 @@ func (r *dmlExprFilterRule) shouldSkipDML(
 			exprs,
 		)
 	case row.IsUpdate():
-		oldExprs, err := r.getUpdateOldExpr(ti)
+		oldExprs, err := r.getUpdateOldExpr(tableName, ti.TableInfo)
 		if err != nil {
 			return false, err
 		}
-		newExprs, err := r.getUpdateNewExpr(ti)
+		newExprs, err := r.getUpdateNewExpr(tableName, ti.TableInfo)
 		if err != nil {
 			return false, err
 		}
@@ -302,7 +292,7 @@ func (r *dmlExprFilterRule) shouldSkipDML(
 		}
 		return ignoreOld || ignoreNew, nil
 	case row.IsDelete():
-		exprs, err := r.getDeleteExpr(ti)
+		exprs, err := r.getDeleteExpr(tableName, ti.TableInfo)
 		if err != nil {
 			return false, err
 		}
@@ -316,6 +306,65 @@ func (r *dmlExprFilterRule) shouldSkipDML(
 	}
 }
 
+// checkTableVersion resets the expressions of the table if its tableInfo was updated.
+// The caller must hold r.mu.Lock() before calling this function.
+func (r *dmlExprFilterRule) checkTableVersion(tableName string, version uint64) {
+	if oldVersion, ok := r.tableVersions[tableName]; ok && oldVersion == version {
+		return
+	}
+	r.tableVersions[tableName] = version
+	r.resetExpr(tableName)
+}
+
+// shouldSkipRow skips the row change decoded in chunk by the expressions.
+func (r *dmlExprFilterRule) shouldSkipRow(
+	rowType commonEvent.RowType,
+	preRow, row chunk.Row,
+	ti *common.TableInfo,
+) (bool, error) {
+	tableName := ti.TableName.String()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkTableVersion(tableName, ti.GetVersion())
+
+	switch rowType {
+	case commonEvent.RowTypeInsert:
+		expr, err := r.getInsertExpr(tableName, ti.TableInfo)
+		if err != nil {
+			return false, err
+		}
+		return r.skipRowByExpression(row, expr)
+	case commonEvent.RowTypeUpdate:
+		oldExpr, err := r.getUpdateOldExpr(tableName, ti.TableInfo)
+		if err != nil {
+			return false, err
+		}
+		newExpr, err := r.getUpdateNewExpr(tableName, ti.TableInfo)
+		if err != nil {
+			return false, err
+		}
+		ignoreOld, err := r.skipRowByExpression(preRow, oldExpr)
+		if err != nil {
+			return false, err
+		}
+		ignoreNew, err := r.skipRowByExpression(row, newExpr)
+		if err != nil {
+			return false, err
+		}
+		return ignoreOld || ignoreNew, nil
+	case commonEvent.RowTypeDelete:
+		expr, err := r.getDeleteExpr(tableName, ti.TableInfo)
+		if err != nil {
+			return false, err
+		}
+		return r.skipRowByExpression(preRow, expr)
+	default:
+		log.Warn("unknown row type", zap.Uint8("rowType", uint8(rowType)))
+		return false, nil
+	}
+}
+
 func (r *dmlExprFilterRule) skipDMLByExpression(
 	rowData []types.Datum,
 	expr expression.Expression,
@@ -323,8 +372,18 @@ func (r *dmlExprFilterRule) skipDMLByExpression(
 	if len(rowData) == 0 || expr == nil {
 		return false, nil
 	}
+	return r.skipRowByExpression(chunk.MutRowFromDatums(rowData).ToRow(), expr)
+}
 
-	row := chunk.MutRowFromDatums(rowData).ToRow()
+// skipRowByExpression evaluates the expression on the row directly,
+// the columns of the row must be in the same order as the tableInfo.Columns.
+func (r *dmlExprFilterRule) skipRowByExpression(
+	row chunk.Row,
+	expr expression.Expression,
+) (bool, error) {
+	if row.IsEmpty() || expr == nil {
+		return false, nil
+	}
 
 	d, err := expr.Eval(r.sessCtx.GetExprCtx().GetEvalCtx(), row)
 	if err != nil {
@@ -431,3 +490,33 @@ func (f *dmlExprFilter) shouldSkipDML(
 	}
 	return false, nil
 }
+
+// shouldSkipRow skips the row change decoded in chunk by sql expression.
+func (f *dmlExprFilter) shouldSkipRow(
+	rowType commonEvent.RowType,
+	preRow, row chunk.Row,
+	ti *common.TableInfo,
+) (bool, error) {
+	if len(f.rules) == 0 {
+		return false, nil
+	}
+	// for defense purpose, normally the rows and ti should not be nil.
+	if ti == nil || (preRow.IsEmpty() && row.IsEmpty()) {
+		return false, nil
+	}
+	rules := f.getRules(ti.GetSchemaName(), ti.GetTableName())
+	for _, rule := range rules {
+		ignore, err := rule.shouldSkipRow(rowType, preRow, row, ti)
+		if err != nil {
+			if cerror.ShouldFailChangefeed(err) {
+				return false, err
+			}
+			return false, cerror.WrapError(cerror.ErrFailedToFilterDML, err,
+				commonEvent.RowTypeToString(rowType)+" event of "+ti.TableName.String())
+		}
+		if ignore {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -18,9 +18,11 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/pkg/apperror"
+	"github.com/pingcap/ticdc/pkg/common"
 	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
 	"github.com/pingcap/ticdc/pkg/config"
 	timodel "github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/util/chunk"
 	tfilter "github.com/pingcap/tidb/pkg/util/table-filter"
 	"github.com/pingcap/tiflow/cdc/model"
 	"go.uber.org/zap"
@@ -31,6 +33,8 @@ const (
 	SyncPointTable = "syncpoint_v1"
 	// DDLTsTable is the table name use to write ddl commitTs for each table when downstream is mysql-class
 	DDLTsTable = "ddl_ts_v1"
+	// DeadLetterTable is the default table name use to write the rows which can not be applied to the downstream.
+	DeadLetterTable = "dead_letter_v1"
 
 	// TiCDCSystemSchema is the schema only use by TiCDC.
 	TiCDCSystemSchema = "tidb_cdc"
@@ -43,6 +47,9 @@ const (
 type Filter interface {
 	// ShouldIgnoreDMLEvent returns true if the DML event should not be sent to downstream.
 	ShouldIgnoreDMLEvent(dml *model.RowChangedEvent, rawRow model.RowChangedDatums, tableInfo *model.TableInfo) (bool, error)
+	// ShouldIgnoreDML returns true if the row change decoded in chunk should not be sent to downstream.
+	// If both preRow and row are empty, only the startTs and the type of the row change are checked.
+	ShouldIgnoreDML(dmlType commonEvent.RowType, preRow, row chunk.Row, tableInfo *common.TableInfo, startTs uint64) (bool, error)
 	// ShouldIgnoreDDLEvent returns true if the DDL event should not be sent to downstream.
 	ShouldIgnoreDDLEvent(ddl *model.DDLEvent) (bool, error)
 	// ShouldDiscardDDL returns true if this DDL should be discarded.
@@ -92,6 +99,9 @@ func NewFilter(cfg *config.FilterConfig, tz string, caseSensitive bool) (Filter,
 	if err != nil {
 		return nil, err
 	}
+	if err := verifyBlockDDL(cfg.BlockDDL); err != nil {
+		return nil, err
+	}
 	return &filter{
 		tableFilter:      f,
 		dmlExprFilter:    dmlExprFilter,
@@ -156,6 +166,32 @@ func (f *filter) ShouldIgnoreDMLEvent(
 	return f.dmlExprFilter.shouldSkipDML(dml, rawRow, ti)
 }
 
+// ShouldIgnoreDML checks if a row change should be ignore by conditions below:
+// 0. By startTs.
+// 1. By type.
+// 2. By columns value.
+// The table name is not checked here, since the dispatchers are only created
+// for the tables which are not ignored.
+func (f *filter) ShouldIgnoreDML(
+	dmlType commonEvent.RowType,
+	preRow, row chunk.Row,
+	ti *common.TableInfo,
+	startTs uint64,
+) (bool, error) {
+	if f.shouldIgnoreStartTs(startTs) {
+		return true, nil
+	}
+
+	ignoreByEventType, err := f.sqlEventFilter.shouldSkipRow(dmlType, ti.GetSchemaName(), ti.GetTableName())
+	if err != nil {
+		return false, err
+	}
+	if ignoreByEventType {
+		return true, nil
+	}
+	return f.dmlExprFilter.shouldSkipRow(dmlType, preRow, row, ti)
+}
+
 // ShouldDiscardDDL checks if a DDL should be discarded by conditions below:
 // 0. By allow list.
 // 1. By schema name.
@@ -0,0 +1,89 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	bf "github.com/pingcap/tiflow/pkg/binlog-filter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldIgnoreDML(t *testing.T) {
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32))")
+	require.NotNil(t, job)
+	dmlEvent := helper.DML2Event("test", "t",
+		"insert into t values (1, 'a')",
+		"insert into t values (2, 'b')")
+	require.NotNil(t, dmlEvent)
+	tableInfo := dmlEvent.TableInfo
+
+	cfg := &config.FilterConfig{
+		Rules:            []string{"test.*"},
+		IgnoreTxnStartTs: []uint64{100},
+		EventFilters: []*config.EventFilterRule{
+			{
+				Matcher:               []string{"test.t"},
+				IgnoreInsertValueExpr: "id > 1",
+			},
+			{
+				Matcher:     []string{"test.t"},
+				IgnoreEvent: []bf.EventType{bf.DeleteEvent},
+			},
+		},
+	}
+	f, err := NewFilter(cfg, "", false)
+	require.NoError(t, err)
+
+	// ignored by startTs.
+	ignore, err := f.ShouldIgnoreDML(commonEvent.RowTypeInsert, chunk.Row{}, chunk.Row{}, tableInfo, 100)
+	require.NoError(t, err)
+	require.True(t, ignore)
+
+	// ignored by type, the rows are not needed.
+	ignore, err = f.ShouldIgnoreDML(commonEvent.RowTypeDelete, chunk.Row{}, chunk.Row{}, tableInfo, dmlEvent.StartTs)
+	require.NoError(t, err)
+	require.True(t, ignore)
+	ignore, err = f.ShouldIgnoreDML(commonEvent.RowTypeInsert, chunk.Row{}, chunk.Row{}, tableInfo, dmlEvent.StartTs)
+	require.NoError(t, err)
+	require.False(t, ignore)
+
+	// ignored by columns value.
+	row, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+	ignore, err = f.ShouldIgnoreDML(row.RowType, row.PreRow, row.Row, tableInfo, dmlEvent.StartTs)
+	require.NoError(t, err)
+	require.False(t, ignore)
+	row, ok = dmlEvent.GetNextRow()
+	require.True(t, ok)
+	ignore, err = f.ShouldIgnoreDML(row.RowType, row.PreRow, row.Row, tableInfo, dmlEvent.StartTs)
+	require.NoError(t, err)
+	require.True(t, ignore)
+
+	// the expression of other tables is not affected.
+	helper.DDL2Job("create table t1 (id int primary key, name varchar(32))")
+	dmlEvent = helper.DML2Event("test", "t1", "insert into t1 values (2, 'b')")
+	row, ok = dmlEvent.GetNextRow()
+	require.True(t, ok)
+	ignore, err = f.ShouldIgnoreDML(row.RowType, row.PreRow, row.Row, dmlEvent.TableInfo, dmlEvent.StartTs)
+	require.NoError(t, err)
+	require.False(t, ignore)
+}
@@ -16,6 +16,8 @@ package filter
 import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
 	"github.com/pingcap/ticdc/pkg/config"
 	tfilter "github.com/pingcap/tidb/pkg/util/table-filter"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -196,11 +198,46 @@ func (f *sqlEventFilter) shouldSkipDML(event *model.RowChangedEvent) (bool, erro
 		log.Warn("unknown row changed event type")
 		return false, nil
 	}
-	rules := f.getRules(event.TableInfo.GetSchemaName(), event.TableInfo.GetTableName())
+	skip, err := f.skipDMLByEventType(event.TableInfo.GetSchemaName(), event.TableInfo.GetTableName(), et)
+	if err != nil {
+		return false, cerror.WrapError(cerror.ErrFailedToFilterDML, err, event)
+	}
+	return skip, nil
+}
+
+// shouldSkipRow skips the row change of the given table by its type.
+func (f *sqlEventFilter) shouldSkipRow(rowType commonEvent.RowType, schema, table string) (bool, error) {
+	if len(f.rules) == 0 {
+		return false, nil
+	}
+
+	var et bf.EventType
+	switch rowType {
+	case commonEvent.RowTypeInsert:
+		et = bf.InsertEvent
+	case commonEvent.RowTypeUpdate:
+		et = bf.UpdateEvent
+	case commonEvent.RowTypeDelete:
+		et = bf.DeleteEvent
+	default:
+		// It should never happen.
+		log.Warn("unknown row type", zap.Uint8("rowType", uint8(rowType)))
+		return false, nil
+	}
+	skip, err := f.skipDMLByEventType(schema, table, et)
+	if err != nil {
+		return false, cerror.WrapError(cerror.ErrFailedToFilterDML, err,
+			commonEvent.RowTypeToString(rowType)+" event of "+common.QuoteSchema(schema, table))
+	}
+	return skip, nil
+}
+
+func (f *sqlEventFilter) skipDMLByEventType(schema, table string, et bf.EventType) (bool, error) {
+	rules := f.getRules(schema, table)
 	for _, rule := range rules {
 		action, err := rule.bf.Filter(binlogFilterSchemaPlaceholder, binlogFilterTablePlaceholder, et, dmlQuery)
 		if err != nil {
-			return false, cerror.WrapError(cerror.ErrFailedToFilterDML, err, event)
+			return false, errors.Trace(err)
 		}
 		if action == bf.Ignore {
 			return true, nil
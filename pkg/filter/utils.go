@@ -141,6 +141,39 @@ func SupportedEventTypes() []bf.EventType {
 	return supportedEventTypes
 }
 
+// verifyBlockDDL checks whether all the types in the block ddl config are ddl types.
+func verifyBlockDDL(types []bf.EventType) error {
+	ddlTypes := make(map[bf.EventType]struct{}, len(ddlWhiteListMap))
+	for _, et := range ddlWhiteListMap {
+		ddlTypes[et] = struct{}{}
+	}
+	for _, et := range types {
+		if _, ok := ddlTypes[et]; !ok {
+			return cerror.ErrFilterRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("invalid block ddl type: '%s'", et))
+		}
+	}
+	return nil
+}
+
+// IsBlockedDDL returns true if the ddl type is in the block ddl config,
+// the ddl must be approved or skipped manually before it's executed.
+func IsBlockedDDL(blockDDL []bf.EventType, ddlType timodel.ActionType) bool {
+	if len(blockDDL) == 0 {
+		return false
+	}
+	et := ddlToEventType(ddlType)
+	if et == bf.NullEvent {
+		return false
+	}
+	for _, t := range blockDDL {
+		if t == et {
+			return true
+		}
+	}
+	return false
+}
+
 func completeExpression(suffix string) string {
 	if suffix == "" {
 		return suffix
@@ -52,6 +52,14 @@ const (
 
 	TypeMessageError
 	TypeMessageHandShake
+
+	TypeTableScheduleRequest
+	TypeTableScheduleResponse
+	TypeTableStatusRequest
+	TypeTableStatusResponse
+	TypePendingDDLRequest
+	TypePendingDDLResponse
+	TypeErrorEvent
 )
 
 func (t IOType) String() string {
@@ -66,6 +74,8 @@ func (t IOType) String() string {
 		return "BatchResolvedTs"
 	case TypeHandshakeEvent:
 		return "HandshakeEvent"
+	case TypeErrorEvent:
+		return "ErrorEvent"
 	case TypeLogCoordinatorBroadcastRequest:
 		return "TypeLogCoordinatorBroadcastRequest"
 	case TypeEventStoreState:
@@ -104,6 +114,18 @@ func (t IOType) String() string {
 		return "MessageHandShake"
 	case TypeCheckpointTsMessage:
 		return "CheckpointTsMessage"
+	case TypeTableScheduleRequest:
+		return "TableScheduleRequest"
+	case TypeTableScheduleResponse:
+		return "TableScheduleResponse"
+	case TypeTableStatusRequest:
+		return "TableStatusRequest"
+	case TypeTableStatusResponse:
+		return "TableStatusResponse"
+	case TypePendingDDLRequest:
+		return "PendingDDLRequest"
+	case TypePendingDDLResponse:
+		return "PendingDDLResponse"
 	default:
 	}
 	return "Unknown"
@@ -176,6 +198,10 @@ func (r RegisterDispatcherRequest) GetFilterConfig() *config.FilterConfig {
 	return filterCfg
 }
 
+func (r RegisterDispatcherRequest) GetBDRMode() bool {
+	return r.BdrMode
+}
+
 func (r RegisterDispatcherRequest) SyncPointEnabled() bool {
 	return r.EnableSyncPoint
 }
@@ -206,6 +232,8 @@ func decodeIOType(ioType IOType, value []byte) (IOTypeT, error) {
 		m = &commonEvent.BatchResolvedEvent{}
 	case TypeHandshakeEvent:
 		m = &commonEvent.HandshakeEvent{}
+	case TypeErrorEvent:
+		m = &commonEvent.ErrorEvent{}
 	case TypeLogCoordinatorBroadcastRequest:
 		m = &common.LogCoordinatorBroadcastRequest{}
 	case TypeEventStoreState:
@@ -242,6 +270,18 @@ func decodeIOType(ioType IOType, value []byte) (IOTypeT, error) {
 		m = &heartbeatpb.CheckpointTsMessage{}
 	case TypeMessageError:
 		m = &MessageError{AppError: &apperror.AppError{}}
+	case TypeTableScheduleRequest:
+		m = &TableScheduleRequest{}
+	case TypeTableScheduleResponse:
+		m = &TableScheduleResponse{}
+	case TypeTableStatusRequest:
+		m = &TableStatusRequest{}
+	case TypeTableStatusResponse:
+		m = &TableStatusResponse{}
+	case TypePendingDDLRequest:
+		m = &PendingDDLRequest{}
+	case TypePendingDDLResponse:
+		m = &PendingDDLResponse{}
 	default:
 		log.Panic("Unimplemented IOType", zap.Stringer("Type", ioType))
 	}
@@ -276,6 +316,8 @@ func NewSingleTargetMessage(To node.ID, Topic string, Message IOTypeT) *TargetMe
 		ioType = TypeBatchResolvedTs
 	case *commonEvent.HandshakeEvent:
 		ioType = TypeHandshakeEvent
+	case *commonEvent.ErrorEvent:
+		ioType = TypeErrorEvent
 	case *common.LogCoordinatorBroadcastRequest:
 		ioType = TypeLogCoordinatorBroadcastRequest
 	case *logservicepb.EventStoreState:
@@ -312,6 +354,18 @@ func NewSingleTargetMessage(To node.ID, Topic string, Message IOTypeT) *TargetMe
 		ioType = TypeMaintainerCloseResponse
 	case *heartbeatpb.CheckpointTsMessage:
 		ioType = TypeCheckpointTsMessage
+	case *TableScheduleRequest:
+		ioType = TypeTableScheduleRequest
+	case *TableScheduleResponse:
+		ioType = TypeTableScheduleResponse
+	case *TableStatusRequest:
+		ioType = TypeTableStatusRequest
+	case *TableStatusResponse:
+		ioType = TypeTableStatusResponse
+	case *PendingDDLRequest:
+		ioType = TypePendingDDLRequest
+	case *PendingDDLResponse:
+		ioType = TypePendingDDLResponse
 	default:
 		panic("unknown io type")
 	}
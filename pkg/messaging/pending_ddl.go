@@ -0,0 +1,81 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messaging
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/pkg/config"
+)
+
+// PendingDDLAction is the action of a pending ddl request.
+type PendingDDLAction int
+
+const (
+	// PendingDDLActionList lists all the pending ddls of the changefeed.
+	PendingDDLActionList PendingDDLAction = iota + 1
+	// PendingDDLActionApprove executes the pending ddl in the downstream.
+	PendingDDLActionApprove
+	// PendingDDLActionSkip skips the pending ddl, it's not executed in the downstream.
+	PendingDDLActionSkip
+)
+
+func (a PendingDDLAction) String() string {
+	switch a {
+	case PendingDDLActionList:
+		return "list"
+	case PendingDDLActionApprove:
+		return "approve"
+	case PendingDDLActionSkip:
+		return "skip"
+	default:
+	}
+	return "unknown"
+}
+
+// PendingDDLRequest is sent by the coordinator to the maintainer of a changefeed
+// to list, approve or skip the ddls which are blocked by the block ddl config,
+// the maintainer replies a PendingDDLResponse with the same RequestID.
+type PendingDDLRequest struct {
+	RequestID    uint64                    `json:"request_id"`
+	ChangefeedID *heartbeatpb.ChangefeedID `json:"changefeed_id"`
+	Action       PendingDDLAction          `json:"action"`
+	// BlockTs is the commit ts of the ddl to approve or skip.
+	BlockTs uint64 `json:"block_ts"`
+}
+
+func (r *PendingDDLRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *PendingDDLRequest) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+// PendingDDLResponse is the response of the PendingDDLRequest,
+// DDLs is only set for the list action.
+type PendingDDLResponse struct {
+	RequestID uint64               `json:"request_id"`
+	Error     string               `json:"error"`
+	DDLs      []*config.PendingDDL `json:"ddls"`
+}
+
+func (r *PendingDDLResponse) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *PendingDDLResponse) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, r)
+}
@@ -0,0 +1,66 @@
+package messaging
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/pkg/config"
+)
+
+// TableScheduleAction is the action of a manual table schedule request.
+type TableScheduleAction int
+
+const (
+	// TableScheduleActionMove moves all the spans of a table to the target node.
+	TableScheduleActionMove TableScheduleAction = iota + 1
+	// TableScheduleActionSplit splits the spans of a table by the splitter of the changefeed.
+	TableScheduleActionSplit
+)
+
+func (a TableScheduleAction) String() string {
+	switch a {
+	case TableScheduleActionMove:
+		return "move"
+	case TableScheduleActionSplit:
+		return "split"
+	default:
+	}
+	return "unknown"
+}
+
+// TableScheduleRequest is sent by the coordinator to the maintainer of a changefeed
+// to schedule a table manually, the maintainer replies a TableScheduleResponse
+// with the same RequestID after the operators are added.
+type TableScheduleRequest struct {
+	RequestID    uint64                    `json:"request_id"`
+	ChangefeedID *heartbeatpb.ChangefeedID `json:"changefeed_id"`
+	Action       TableScheduleAction       `json:"action"`
+	TableID      int64                     `json:"table_id"`
+	// TargetNode is the destination node of the move action.
+	TargetNode string `json:"target_node"`
+}
+
+func (r *TableScheduleRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *TableScheduleRequest) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+// TableScheduleResponse is the response of the TableScheduleRequest,
+// Error is empty if the request is accepted by the maintainer.
+type TableScheduleResponse struct {
+	RequestID uint64 `json:"request_id"`
+	Error     string `json:"error"`
+	// Spans are the spans which are being split by the split action.
+	Spans []*config.TableSpanReplicationStatus `json:"spans,omitempty"`
+}
+
+func (r *TableScheduleResponse) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *TableScheduleResponse) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, r)
+}
@@ -0,0 +1,39 @@
+package messaging
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/ticdc/heartbeatpb"
+	"github.com/pingcap/ticdc/pkg/config"
+)
+
+// TableStatusRequest is sent by the coordinator to the maintainer of a changefeed
+// to query the replication status of all the table spans, the maintainer replies
+// a TableStatusResponse with the same RequestID.
+type TableStatusRequest struct {
+	RequestID    uint64                    `json:"request_id"`
+	ChangefeedID *heartbeatpb.ChangefeedID `json:"changefeed_id"`
+}
+
+func (r *TableStatusRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *TableStatusRequest) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+// TableStatusResponse is the response of the TableStatusRequest.
+type TableStatusResponse struct {
+	RequestID uint64                               `json:"request_id"`
+	Error     string                               `json:"error"`
+	Spans     []*config.TableSpanReplicationStatus `json:"spans"`
+}
+
+func (r *TableStatusResponse) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *TableStatusResponse) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, r)
+}
@@ -45,6 +45,8 @@ type remoteMessageTarget struct {
 	targetEpoch atomic.Value
 	targetId    node.ID
 	targetAddr  string
+	// security is the credential used to connect to the target.
+	security *security.Credential
 
 	// For sending events and commands
 	eventSender   *sendStreamWrapper
@@ -147,6 +149,7 @@ func newRemoteMessageTarget(
 		messageCenterEpoch: localEpoch,
 		targetAddr:         addr,
 		targetId:           targetId,
+		security:           cfg.Security,
 		eventSender:        &sendStreamWrapper{ready: atomic.Bool{}},
 		commandSender:      &sendStreamWrapper{ready: atomic.Bool{}},
 		ctx:                ctx,
@@ -229,7 +232,11 @@ func (s *remoteMessageTarget) connect() {
 	if s.conn != nil {
 		return
 	}
-	conn, err := conn.Connect(string(s.targetAddr), &security.Credential{})
+	credential := s.security
+	if credential == nil {
+		credential = &security.Credential{}
+	}
+	conn, err := conn.Connect(string(s.targetAddr), credential)
 	if err != nil {
 		log.Info("Cannot create grpc client",
 			zap.Any("messageCenterID", s.messageCenterID), zap.Any("remote", s.targetId), zap.Error(err))
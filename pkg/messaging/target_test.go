@@ -7,6 +7,7 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -37,3 +38,21 @@ func TestRemoteTargetNewMessage(t *testing.T) {
 	require.Equal(t, TypeMessageHandShake, IOType(msg2.Type))
 	require.Equal(t, rt.messageCenterEpoch, uint64(msg2.Epoch))
 }
+
+func TestRemoteTargetConnectWithCredential(t *testing.T) {
+	cfg := config.NewDefaultMessageCenterConfig()
+	cfg.Security = &security.Credential{
+		CAPath:   "not-exist-ca.pem",
+		CertPath: "not-exist-cert.pem",
+		KeyPath:  "not-exist-key.pem",
+	}
+	receivedMsgCh := make(chan *TargetMessage, 1)
+	rt := newRemoteMessageTarget(node.NewID(), node.NewID(), 1, 1, "127.0.0.1:8300",
+		receivedMsgCh, receivedMsgCh, cfg)
+	defer rt.close()
+	require.Equal(t, cfg.Security, rt.security)
+
+	// the connection can not be created since the certificates are not found.
+	rt.connect()
+	require.Nil(t, rt.conn)
+}
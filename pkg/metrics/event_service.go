@@ -34,6 +34,14 @@ var (
 		Help:      "The number of events sent by the event service",
 	}, []string{"namespace", "changefeed", "type"})
 
+	// EventServiceFilteredRowCount is the metric that counts the row changes filtered out by the event service.
+	EventServiceFilteredRowCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "event_service",
+		Name:      "filtered_row_count",
+		Help:      "The number of row changes filtered out by the event service",
+	}, []string{"namespace", "changefeed"})
+
 	// EventServiceSendEventDuration is the metric that records the duration of sending events by the event service.
 	EventServiceSendEventDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "ticdc",
@@ -108,12 +116,27 @@ var (
 			Name:      "drop_notification_count",
 			Help:      "The number of notifications dropped",
 		})
+	EventServiceInterruptScanCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "event_service",
+			Name:      "interrupt_scan_count",
+			Help:      "The number of scan tasks interrupted before reaching the end of the data range",
+		}, []string{"reason"})
+	EventServiceScanMemoryUsage = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "event_service",
+			Name:      "scan_memory_usage",
+			Help:      "The bytes of the scanned events which are not sent yet",
+		})
 )
 
 // InitMetrics registers all metrics in this file.
 func InitEventServiceMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(SorterOutputEventCount)
 	registry.MustRegister(EventServiceSendEventCount)
+	registry.MustRegister(EventServiceFilteredRowCount)
 	registry.MustRegister(EventServiceSendEventDuration)
 	registry.MustRegister(EventServiceResolvedTsGauge)
 	registry.MustRegister(EventServiceResolvedTsLagGauge)
@@ -124,4 +147,6 @@ func InitEventServiceMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(EventServiceScanTaskQueueDuration)
 	registry.MustRegister(EventServiceHandleDuration)
 	registry.MustRegister(EventServiceDropNotificationCount)
+	registry.MustRegister(EventServiceInterruptScanCount)
+	registry.MustRegister(EventServiceScanMemoryUsage)
 }
@@ -27,7 +27,9 @@ func InitMetrics(registry *prometheus.Registry) {
 	InitPullerMetrics(registry)
 	InitEventStoreMetrics(registry)
 	InitSchemaStoreMetrics(registry)
+	InitPebbleMetrics(registry)
 	InitEventServiceMetrics(registry)
 	InitMaintainerMetrics(registry)
 	InitCoordinatorMetrics(registry)
+	InitRedoMetrics(registry)
 }
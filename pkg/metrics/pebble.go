@@ -0,0 +1,80 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// PebbleReadAmplificationGauge is the max read amplification of the pebble dbs of a store.
+	PebbleReadAmplificationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "pebble",
+			Name:      "read_amplification",
+			Help:      "The max read amplification of the pebble dbs, the number of sublevels of L0 plus the non-empty levels",
+		}, []string{"store"})
+
+	// PebbleWriteAmplificationGauge is the write amplification of the pebble dbs of a store.
+	PebbleWriteAmplificationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "pebble",
+			Name:      "write_amplification",
+			Help:      "The write amplification of the pebble dbs, the bytes written to disk divided by the bytes flushed",
+		}, []string{"store"})
+
+	PebbleCompactionCountGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "pebble",
+			Name:      "compaction_count",
+			Help:      "The number of compactions of the pebble dbs since they are opened",
+		}, []string{"store"})
+
+	PebbleCompactionInProgressGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "pebble",
+			Name:      "compaction_in_progress",
+			Help:      "The number of the in-progress compactions of the pebble dbs",
+		}, []string{"store"})
+
+	PebbleCompactionDebtGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "pebble",
+			Name:      "compaction_debt_bytes",
+			Help:      "The estimated bytes need to be compacted for the pebble dbs to reach a stable state",
+		}, []string{"store"})
+
+	PebbleDiskUsageGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "pebble",
+			Name:      "disk_usage_bytes",
+			Help:      "The disk space used by the pebble dbs",
+		}, []string{"store"})
+)
+
+// InitPebbleMetrics registers all metrics in this file.
+func InitPebbleMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(PebbleReadAmplificationGauge)
+	registry.MustRegister(PebbleWriteAmplificationGauge)
+	registry.MustRegister(PebbleCompactionCountGauge)
+	registry.MustRegister(PebbleCompactionInProgressGauge)
+	registry.MustRegister(PebbleCompactionDebtGauge)
+	registry.MustRegister(PebbleDiskUsageGauge)
+}
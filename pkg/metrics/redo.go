@@ -0,0 +1,78 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// RedoWriteBytesGauge records the total number of bytes written to redo log.
+	RedoWriteBytesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "redo",
+		Name:      "write_bytes_total",
+		Help:      "Total number of bytes redo log written",
+	}, []string{"namespace", "changefeed", "type"})
+
+	// RedoWriteLogDurationHistogram records the latency distributions of writing a redo log file.
+	RedoWriteLogDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "ticdc",
+		Subsystem: "redo",
+		Name:      "write_log_duration_seconds",
+		Help:      "The latency distributions of writing a redo log file to the storage",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2.0, 16),
+	}, []string{"namespace", "changefeed", "type"})
+
+	// RedoFlushLogDurationHistogram records the latency distributions of flushing the buffered redo logs,
+	// including encoding, writing and the callbacks.
+	RedoFlushLogDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "ticdc",
+		Subsystem: "redo",
+		Name:      "flush_log_duration_seconds",
+		Help:      "The latency distributions of flushing buffered redo logs",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2.0, 16),
+	}, []string{"namespace", "changefeed"})
+
+	// RedoWriteEventCounter records the number of events written to redo log.
+	RedoWriteEventCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "redo",
+		Name:      "write_event_count",
+		Help:      "The number of events written to redo log",
+	}, []string{"namespace", "changefeed", "type"})
+
+	// RedoResolvedTsGauge records the resolved ts written in the redo meta.
+	RedoResolvedTsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "redo",
+		Name:      "resolved_ts",
+		Help:      "The resolved ts written in the redo meta",
+	}, []string{"namespace", "changefeed"})
+
+	// RedoCheckpointTsGauge records the checkpoint ts written in the redo meta.
+	RedoCheckpointTsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "redo",
+		Name:      "checkpoint_ts",
+		Help:      "The checkpoint ts written in the redo meta",
+	}, []string{"namespace", "changefeed"})
+)
+
+func InitRedoMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(RedoWriteBytesGauge)
+	registry.MustRegister(RedoWriteLogDurationHistogram)
+	registry.MustRegister(RedoFlushLogDurationHistogram)
+	registry.MustRegister(RedoWriteEventCounter)
+	registry.MustRegister(RedoResolvedTsGauge)
+	registry.MustRegister(RedoCheckpointTsGauge)
+}
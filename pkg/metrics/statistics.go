@@ -14,6 +14,7 @@
 package metrics
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/ticdc/pkg/common"
@@ -43,6 +44,7 @@ func NewStatistics(
 	statistics.metricEventSizeHis = EventSizeHistogram.WithLabelValues(namespcae, changefeedID)
 	statistics.metricExecErrCnt = ExecutionErrorCounter.WithLabelValues(namespcae, changefeedID, s)
 	statistics.metricExecDMLCnt = ExecDMLEventCounter.WithLabelValues(namespcae, changefeedID)
+	statistics.metricDeadLetterRowsCnt = DeadLetterRowsCounter.WithLabelValues(namespcae, changefeedID)
 	return statistics
 }
 
@@ -65,6 +67,11 @@ type Statistics struct {
 	metricExecErrCnt prometheus.Counter
 
 	metricExecDMLCnt prometheus.Counter
+
+	// deadLetterRows is the number of rows written to the dead letter destination
+	// since the sink is created, it's reported to the maintainer by the heartbeat.
+	deadLetterRows          atomic.Uint64
+	metricDeadLetterRowsCnt prometheus.Counter
 }
 
 // ObserveRows stats all received `RowChangedEvent`s.
@@ -98,6 +105,17 @@ func (b *Statistics) RecordDDLExecution(executor func() error) error {
 	return nil
 }
 
+// RecordDeadLetterRow records a row written to the dead letter destination.
+func (b *Statistics) RecordDeadLetterRow() {
+	b.deadLetterRows.Add(1)
+	b.metricDeadLetterRowsCnt.Inc()
+}
+
+// DeadLetterRows returns the number of rows written to the dead letter destination.
+func (b *Statistics) DeadLetterRows() uint64 {
+	return b.deadLetterRows.Load()
+}
+
 // Close release some internal resources.
 func (b *Statistics) Close() {
 	namespace := b.changefeedID.Namespace()
@@ -108,4 +126,5 @@ func (b *Statistics) Close() {
 	ExecutionErrorCounter.DeleteLabelValues(namespace, changefeedID)
 	TotalWriteBytesCounter.DeleteLabelValues(namespace, changefeedID)
 	ExecDMLEventCounter.DeleteLabelValues(namespace, changefeedID)
+	DeadLetterRowsCounter.DeleteLabelValues(namespace, changefeedID)
 }
@@ -44,4 +44,16 @@ type Coordinator interface {
 	ResumeChangefeed(ctx context.Context, id common.ChangeFeedID, newCheckpointTs uint64) error
 	// UpdateChangefeed updates a changefeed
 	UpdateChangefeed(ctx context.Context, change *config.ChangeFeedInfo) error
+	// MoveTable moves all the spans of a table in the changefeed to the target node
+	MoveTable(ctx context.Context, id common.ChangeFeedID, tableID int64, targetNode ID) error
+	// SplitTable splits the spans of a table in the changefeed, and returns the spans being split
+	SplitTable(ctx context.Context, id common.ChangeFeedID, tableID int64) ([]*config.TableSpanReplicationStatus, error)
+	// GetTableSpanStatus returns the replication status of all the table spans in the changefeed
+	GetTableSpanStatus(ctx context.Context, id common.ChangeFeedID) ([]*config.TableSpanReplicationStatus, error)
+	// ListPendingDDLs returns the ddls which are blocked by the block ddl config of the changefeed
+	ListPendingDDLs(ctx context.Context, id common.ChangeFeedID) ([]*config.PendingDDL, error)
+	// ApprovePendingDDL executes the pending ddl in the downstream
+	ApprovePendingDDL(ctx context.Context, id common.ChangeFeedID, blockTs uint64) error
+	// SkipPendingDDL skips the pending ddl, it's not executed in the downstream
+	SkipPendingDDL(ctx context.Context, id common.ChangeFeedID, blockTs uint64) error
 }
@@ -0,0 +1,230 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tiflow/pkg/compression"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// LogType is the type of the event carried by a redo log.
+type LogType byte
+
+const (
+	// LogTypeDML means the redo log carries a DMLEvent.
+	LogTypeDML LogType = iota + 1
+	// LogTypeDDL means the redo log carries a DDLEvent.
+	LogTypeDDL
+)
+
+// RedoLog is a record in the redo log files.
+// Only one of DML and DDL is set, which is decided by the Type.
+type RedoLog struct {
+	Type LogType
+	DML  *commonEvent.DMLEvent
+	DDL  *commonEvent.DDLEvent
+}
+
+// NewDMLRedoLog creates a redo log for the dml event.
+func NewDMLRedoLog(event *commonEvent.DMLEvent) *RedoLog {
+	return &RedoLog{Type: LogTypeDML, DML: event}
+}
+
+// NewDDLRedoLog creates a redo log for the ddl event.
+func NewDDLRedoLog(event *commonEvent.DDLEvent) *RedoLog {
+	return &RedoLog{Type: LogTypeDDL, DDL: event}
+}
+
+// GetCommitTs returns the commitTs of the event carried by the redo log.
+func (l *RedoLog) GetCommitTs() uint64 {
+	if l.Type == LogTypeDML {
+		return l.DML.GetCommitTs()
+	}
+	return l.DDL.GetCommitTs()
+}
+
+// dmlTableInfo is the table info stored along with a dml event.
+// DMLEvent.Marshal doesn't contain the table info, but it's necessary to
+// decode the rows of the event when reading the redo log.
+type dmlTableInfo struct {
+	SchemaID   int64            `json:"schema_id"`
+	SchemaName string           `json:"schema_name"`
+	TableInfo  *model.TableInfo `json:"table_info"`
+}
+
+// Marshal encodes the redo log to bytes.
+// The layout of a dml redo log is:
+//
+//	| type(1B) | tableInfoLen(4B) | tableInfo | DMLEvent |
+//
+// The layout of a ddl redo log is:
+//
+//	| type(1B) | DDLEvent |
+func (l *RedoLog) Marshal() ([]byte, error) {
+	switch l.Type {
+	case LogTypeDML:
+		tableInfo := l.DML.TableInfo
+		if tableInfo == nil {
+			return nil, cerror.ErrMarshalFailed.GenWithStack("table info of the dml event is nil")
+		}
+		info, err := json.Marshal(&dmlTableInfo{
+			SchemaID:   tableInfo.SchemaID,
+			SchemaName: tableInfo.GetSchemaName(),
+			TableInfo:  tableInfo.TableInfo,
+		})
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+		}
+		event, err := l.DML.Marshal()
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+		}
+		buf := make([]byte, 1+4, 1+4+len(info)+len(event))
+		buf[0] = byte(l.Type)
+		binary.LittleEndian.PutUint32(buf[1:], uint32(len(info)))
+		buf = append(buf, info...)
+		return append(buf, event...), nil
+	case LogTypeDDL:
+		event, err := l.DDL.Marshal()
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+		}
+		return append([]byte{byte(l.Type)}, event...), nil
+	default:
+		return nil, cerror.ErrMarshalFailed.GenWithStack("unknown redo log type")
+	}
+}
+
+// Unmarshal decodes the redo log from bytes.
+// For a dml redo log, the rows of the event are assembled with the table info
+// stored in the log, so the event can be used directly.
+func (l *RedoLog) Unmarshal(data []byte) error {
+	if len(data) < 1 {
+		return cerror.ErrUnmarshalFailed.GenWithStack("redo log is empty")
+	}
+	l.Type = LogType(data[0])
+	data = data[1:]
+	switch l.Type {
+	case LogTypeDML:
+		if len(data) < 4 {
+			return cerror.ErrUnmarshalFailed.GenWithStack("dml redo log is truncated")
+		}
+		infoLen := int(binary.LittleEndian.Uint32(data))
+		data = data[4:]
+		if len(data) < infoLen {
+			return cerror.ErrUnmarshalFailed.GenWithStack("dml redo log is truncated")
+		}
+		info := &dmlTableInfo{}
+		if err := json.Unmarshal(data[:infoLen], info); err != nil {
+			return cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+		}
+		tableInfo := common.WrapTableInfo(info.SchemaID, info.SchemaName, info.TableInfo)
+		tableInfo.InitPreSQLs()
+
+		l.DML = &commonEvent.DMLEvent{}
+		if err := l.DML.Unmarshal(data[infoLen:]); err != nil {
+			return cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+		}
+		return errors.Trace(l.DML.AssembleRows(tableInfo))
+	case LogTypeDDL:
+		l.DDL = &commonEvent.DDLEvent{}
+		if err := l.DDL.Unmarshal(data); err != nil {
+			return cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+		}
+		return nil
+	default:
+		return cerror.ErrUnmarshalFailed.GenWithStack("unknown redo log type")
+	}
+}
+
+const (
+	// logFileVersion is the version of the redo log file layout.
+	logFileVersion byte = 1
+
+	compressionNone byte = 0
+	compressionLZ4  byte = 1
+)
+
+// encodeLogFile encodes the redo logs into the content of a redo log file.
+// The layout of a redo log file is:
+//
+//	| version(1B) | compression(1B) | body |
+//
+// and the body, which may be compressed, is a sequence of
+//
+//	| length(4B) | redo log |
+func encodeLogFile(logs [][]byte, codec string) ([]byte, error) {
+	size := 0
+	for _, log := range logs {
+		size += 4 + len(log)
+	}
+	body := make([]byte, 0, size)
+	for _, log := range logs {
+		body = binary.LittleEndian.AppendUint32(body, uint32(len(log)))
+		body = append(body, log...)
+	}
+
+	flag := compressionNone
+	if codec == compression.LZ4 {
+		flag = compressionLZ4
+		var err error
+		body, err = compression.Encode(compression.LZ4, body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return append([]byte{logFileVersion, flag}, body...), nil
+}
+
+// decodeLogFileRecords decodes the content of a redo log file into
+// the encoded redo logs, which can be decoded by RedoLog.Unmarshal.
+func decodeLogFileRecords(data []byte) ([][]byte, error) {
+	if len(data) < 2 || data[0] != logFileVersion {
+		return nil, cerror.ErrUnmarshalFailed.GenWithStack("invalid redo log file header")
+	}
+	body := data[2:]
+	switch data[1] {
+	case compressionNone:
+	case compressionLZ4:
+		var err error
+		body, err = compression.Decode(compression.LZ4, body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	default:
+		return nil, cerror.ErrUnmarshalFailed.GenWithStack("unknown redo log file compression")
+	}
+
+	records := make([][]byte, 0)
+	for len(body) > 0 {
+		if len(body) < 4 {
+			return nil, cerror.ErrUnmarshalFailed.GenWithStack("redo log file is truncated")
+		}
+		length := int(binary.LittleEndian.Uint32(body))
+		body = body[4:]
+		if len(body) < length {
+			return nil, cerror.ErrUnmarshalFailed.GenWithStack("redo log file is truncated")
+		}
+		records = append(records, body[:length])
+		body = body[length:]
+	}
+	return records, nil
+}
@@ -0,0 +1,519 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/metrics"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	redoConfig "github.com/pingcap/tiflow/pkg/redo"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultInputChanSize = 1024
+)
+
+// IsConsistentEnabled returns whether the redo log is enabled by the consistent config.
+func IsConsistentEnabled(cfg *config.ConsistentConfig) bool {
+	return cfg != nil && redoConfig.IsConsistentEnabled(cfg.Level)
+}
+
+// redoTask is a dml event or a resolvedTs of a dispatcher.
+// Tasks are handled in order, so the resolvedTs of a dispatcher is
+// advanced only after all the dml events before it are flushed.
+type redoTask struct {
+	dispatcherID common.DispatcherID
+	// event is nil if the task is a resolvedTs.
+	event      *commonEvent.DMLEvent
+	callback   func()
+	resolvedTs uint64
+}
+
+// Manager writes the events of the dispatchers in an event dispatcher manager
+// to the redo log storage, and maintains the redo meta of them.
+//
+// DML events are buffered in memory, and flushed to a redo log file every
+// flush interval or when the buffered size exceeds the max log size. The callback
+// of a dml event is called after the event is flushed, which sends the event to the sink.
+// DDL events are written synchronously, because they are rare and the dispatcher
+// waits for them to be flushed to downstream before handling the following events.
+//
+// The redo meta records the checkpointTs and resolvedTs, all the events with
+// commitTs in (checkpointTs, resolvedTs] can be found in the redo log files,
+// so they can be applied to the downstream to recover a consistent snapshot.
+type Manager struct {
+	ctx          context.Context
+	cancel       context.CancelFunc
+	changefeedID common.ChangeFeedID
+	captureID    string
+	cfg          *config.ConsistentConfig
+	// storage is nil if the blackhole storage is used, and all the logs are discarded.
+	storage storage.ExternalStorage
+
+	inputCh chan *redoTask
+	// if manager is running, isNormal is 1, otherwise is 0
+	isNormal uint32
+
+	// the buffered logs and tasks waiting for flushing, only accessed in flushLogLoop.
+	logs        [][]byte
+	tasks       []*redoTask
+	bufferSize  int64
+	maxCommitTs uint64
+	maxLogSize  int64
+
+	mu sync.Mutex
+	// resolvedTsMap stores the flushed resolvedTs of each dispatcher.
+	resolvedTsMap map[common.DispatcherID]uint64
+	checkpointTs  uint64
+	// resolvedTs is the last resolvedTs written in the meta.
+	resolvedTs uint64
+
+	metricWriteDMLBytes    prometheus.Gauge
+	metricWriteDDLBytes    prometheus.Gauge
+	metricWriteDMLDuration prometheus.Observer
+	metricWriteDDLDuration prometheus.Observer
+	metricFlushDuration    prometheus.Observer
+	metricDMLCount         prometheus.Counter
+	metricDDLCount         prometheus.Counter
+	metricResolvedTs       prometheus.Gauge
+	metricCheckpointTs     prometheus.Gauge
+}
+
+// NewManager creates a redo log manager with the consistent config.
+// The config should be validated and adjusted before.
+func NewManager(
+	ctx context.Context,
+	changefeedID common.ChangeFeedID,
+	captureID string,
+	cfg *config.ConsistentConfig,
+) (*Manager, error) {
+	uri, err := storage.ParseRawURL(cfg.Storage)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrStorageInitialize, err)
+	}
+	if !redoConfig.IsValidConsistentStorage(uri.Scheme) {
+		return nil, cerror.ErrConsistentStorage.GenWithStackByArgs(uri.Scheme)
+	}
+
+	var extStorage storage.ExternalStorage
+	if !redoConfig.IsBlackholeStorage(uri.Scheme) {
+		redoConfig.FixLocalScheme(uri)
+		extStorage, err = redoConfig.InitExternalStorage(ctx, *uri)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	maxLogSize := cfg.MaxLogSize
+	if maxLogSize <= 0 {
+		maxLogSize = redoConfig.DefaultMaxLogSize
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	namespace, name := changefeedID.Namespace(), changefeedID.Name()
+	m := &Manager{
+		ctx:                    ctx,
+		cancel:                 cancel,
+		changefeedID:           changefeedID,
+		captureID:              captureID,
+		cfg:                    cfg,
+		storage:                extStorage,
+		inputCh:                make(chan *redoTask, defaultInputChanSize),
+		isNormal:               1,
+		maxLogSize:             maxLogSize * redoConfig.Megabyte,
+		resolvedTsMap:          make(map[common.DispatcherID]uint64),
+		metricWriteDMLBytes:    metrics.RedoWriteBytesGauge.WithLabelValues(namespace, name, redoConfig.RedoRowLogFileType),
+		metricWriteDDLBytes:    metrics.RedoWriteBytesGauge.WithLabelValues(namespace, name, redoConfig.RedoDDLLogFileType),
+		metricWriteDMLDuration: metrics.RedoWriteLogDurationHistogram.WithLabelValues(namespace, name, redoConfig.RedoRowLogFileType),
+		metricWriteDDLDuration: metrics.RedoWriteLogDurationHistogram.WithLabelValues(namespace, name, redoConfig.RedoDDLLogFileType),
+		metricFlushDuration:    metrics.RedoFlushLogDurationHistogram.WithLabelValues(namespace, name),
+		metricDMLCount:         metrics.RedoWriteEventCounter.WithLabelValues(namespace, name, redoConfig.RedoRowLogFileType),
+		metricDDLCount:         metrics.RedoWriteEventCounter.WithLabelValues(namespace, name, redoConfig.RedoDDLLogFileType),
+		metricResolvedTs:       metrics.RedoResolvedTsGauge.WithLabelValues(namespace, name),
+		metricCheckpointTs:     metrics.RedoCheckpointTsGauge.WithLabelValues(namespace, name),
+	}
+	log.Info("redo log manager created",
+		zap.String("namespace", namespace),
+		zap.String("changefeed", name),
+		zap.String("storage", uri.String()),
+		zap.Int64("maxLogSize", maxLogSize),
+		zap.Int64("flushIntervalInMs", cfg.FlushIntervalInMs),
+		zap.Int64("metaFlushIntervalInMs", cfg.MetaFlushIntervalInMs))
+	return m, nil
+}
+
+// Run starts to flush the redo logs and the redo meta, it returns when
+// the manager is closed or an error occurs.
+func (m *Manager) Run() error {
+	g, ctx := errgroup.WithContext(m.ctx)
+	g.Go(func() error {
+		return m.flushLogLoop(ctx)
+	})
+	g.Go(func() error {
+		return m.flushMetaLoop(ctx)
+	})
+	err := g.Wait()
+	atomic.StoreUint32(&m.isNormal, 0)
+	return err
+}
+
+// IsNormal returns whether the manager is running, the pending events
+// can't be flushed anymore if it's not normal.
+func (m *Manager) IsNormal() bool {
+	return atomic.LoadUint32(&m.isNormal) == 1
+}
+
+// AddDispatcher registers a dispatcher to the manager,
+// the resolvedTs of the dispatcher starts from startTs.
+func (m *Manager) AddDispatcher(id common.DispatcherID, startTs uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resolvedTsMap[id] = startTs
+}
+
+// RemoveDispatcher removes the dispatcher from the manager,
+// it doesn't block the resolvedTs of the redo meta anymore.
+func (m *Manager) RemoveDispatcher(id common.DispatcherID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.resolvedTsMap, id)
+}
+
+// AddDMLEvent adds a dml event of the dispatcher to the redo log,
+// callback is called after the event is flushed to the redo log storage.
+func (m *Manager) AddDMLEvent(id common.DispatcherID, event *commonEvent.DMLEvent, callback func()) {
+	select {
+	case <-m.ctx.Done():
+	case m.inputCh <- &redoTask{dispatcherID: id, event: event, callback: callback}:
+	}
+}
+
+// UpdateResolvedTs updates the resolvedTs of the dispatcher, it takes effect
+// after all the dml events added before are flushed.
+func (m *Manager) UpdateResolvedTs(id common.DispatcherID, resolvedTs uint64) {
+	select {
+	case <-m.ctx.Done():
+	case m.inputCh <- &redoTask{dispatcherID: id, resolvedTs: resolvedTs}:
+	}
+}
+
+// UpdateCheckpointTs updates the checkpointTs of the changefeed.
+func (m *Manager) UpdateCheckpointTs(checkpointTs uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if checkpointTs > m.checkpointTs {
+		m.checkpointTs = checkpointTs
+	}
+}
+
+// GetResolvedTs returns the resolvedTs written in the redo meta.
+func (m *Manager) GetResolvedTs() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.resolvedTs
+}
+
+// GetDispatcherResolvedTs returns the resolvedTs of the dispatcher flushed
+// to the redo log, false is returned if the dispatcher is not registered.
+func (m *Manager) GetDispatcherResolvedTs(id common.DispatcherID) (uint64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ts, ok := m.resolvedTsMap[id]
+	return ts, ok
+}
+
+// WriteDDLEvent writes the ddl event to the redo log storage synchronously.
+func (m *Manager) WriteDDLEvent(event *commonEvent.DDLEvent) error {
+	data, err := NewDDLRedoLog(event).Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = m.writeLogFile(m.ctx, redoConfig.RedoDDLLogFileType, [][]byte{data}, event.GetCommitTs())
+	if err != nil {
+		return err
+	}
+	m.metricDDLCount.Inc()
+	return nil
+}
+
+func (m *Manager) flushLogLoop(ctx context.Context) error {
+	ticker := time.NewTicker(time.Duration(m.cfg.FlushIntervalInMs) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+			if err := m.flushLogs(ctx); err != nil {
+				return err
+			}
+		case task := <-m.inputCh:
+			if err := m.handleTask(task); err != nil {
+				return err
+			}
+			if m.bufferSize >= m.maxLogSize {
+				if err := m.flushLogs(ctx); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (m *Manager) handleTask(task *redoTask) error {
+	if task.event == nil {
+		// there are no dml events waiting for flushing,
+		// so the resolvedTs can be advanced directly.
+		if len(m.tasks) == 0 {
+			m.advanceResolvedTs(task.dispatcherID, task.resolvedTs)
+			return nil
+		}
+		m.tasks = append(m.tasks, task)
+		return nil
+	}
+
+	data, err := NewDMLRedoLog(task.event).Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m.logs = append(m.logs, data)
+	m.tasks = append(m.tasks, task)
+	m.bufferSize += int64(len(data))
+	m.maxCommitTs = max(m.maxCommitTs, task.event.GetCommitTs())
+	return nil
+}
+
+// flushLogs writes the buffered dml logs to a redo log file,
+// and then calls the callbacks and advances the resolvedTs in order.
+func (m *Manager) flushLogs(ctx context.Context) error {
+	if len(m.tasks) == 0 {
+		return nil
+	}
+	start := time.Now()
+	if len(m.logs) > 0 {
+		if err := m.writeLogFile(ctx, redoConfig.RedoRowLogFileType, m.logs, m.maxCommitTs); err != nil {
+			return err
+		}
+		m.metricDMLCount.Add(float64(len(m.logs)))
+	}
+	for _, task := range m.tasks {
+		if task.event != nil {
+			task.callback()
+		} else {
+			m.advanceResolvedTs(task.dispatcherID, task.resolvedTs)
+		}
+	}
+	m.metricFlushDuration.Observe(time.Since(start).Seconds())
+
+	m.logs = m.logs[:0]
+	m.tasks = m.tasks[:0]
+	m.bufferSize = 0
+	m.maxCommitTs = 0
+	return nil
+}
+
+func (m *Manager) writeLogFile(ctx context.Context, fileType string, logs [][]byte, maxCommitTs uint64) error {
+	if m.storage == nil {
+		return nil
+	}
+	data, err := encodeLogFile(logs, m.cfg.Compression)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	name := getLogFileName(m.captureID, m.changefeedID, fileType, maxCommitTs)
+	if err := m.storage.WriteFile(ctx, name, data); err != nil {
+		return cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+	}
+	if fileType == redoConfig.RedoRowLogFileType {
+		m.metricWriteDMLDuration.Observe(time.Since(start).Seconds())
+		m.metricWriteDMLBytes.Add(float64(len(data)))
+	} else {
+		m.metricWriteDDLDuration.Observe(time.Since(start).Seconds())
+		m.metricWriteDDLBytes.Add(float64(len(data)))
+	}
+	return nil
+}
+
+func (m *Manager) advanceResolvedTs(id common.DispatcherID, resolvedTs uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// the dispatcher may be removed already.
+	if ts, ok := m.resolvedTsMap[id]; ok && resolvedTs > ts {
+		m.resolvedTsMap[id] = resolvedTs
+	}
+}
+
+func (m *Manager) flushMetaLoop(ctx context.Context) error {
+	ticker := time.NewTicker(time.Duration(m.cfg.MetaFlushIntervalInMs) * time.Millisecond)
+	defer ticker.Stop()
+	gcTicker := time.NewTicker(time.Duration(redoConfig.DefaultGCIntervalInMs) * time.Millisecond)
+	defer gcTicker.Stop()
+
+	var lastMeta LogMeta
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+			meta := m.calculateMeta()
+			if meta == lastMeta {
+				continue
+			}
+			if err := m.writeMeta(ctx, meta); err != nil {
+				return err
+			}
+			lastMeta = meta
+		case <-gcTicker.C:
+			if err := m.removeExpiredLogs(ctx, lastMeta.CheckpointTs); err != nil {
+				log.Warn("remove expired redo logs failed",
+					zap.String("namespace", m.changefeedID.Namespace()),
+					zap.String("changefeed", m.changefeedID.Name()),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
+// calculateMeta returns the meta to write, the resolvedTs is the minimum
+// flushed resolvedTs of all the dispatchers. If there is no dispatcher,
+// the resolvedTs keeps unchanged and the meta is marked as idle, so the
+// reader doesn't take it into account.
+func (m *Manager) calculateMeta() LogMeta {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.resolvedTsMap) > 0 {
+		resolvedTs := uint64(0)
+		for _, ts := range m.resolvedTsMap {
+			if resolvedTs == 0 || ts < resolvedTs {
+				resolvedTs = ts
+			}
+		}
+		if resolvedTs > m.resolvedTs {
+			m.resolvedTs = resolvedTs
+		}
+	}
+	return LogMeta{
+		CheckpointTs: m.checkpointTs,
+		ResolvedTs:   m.resolvedTs,
+		Idle:         len(m.resolvedTsMap) == 0,
+	}
+}
+
+func (m *Manager) writeMeta(ctx context.Context, meta LogMeta) error {
+	if m.storage != nil {
+		data, err := json.Marshal(meta)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrMarshalFailed, err)
+		}
+		name := getMetaFileName(m.captureID, m.changefeedID)
+		if err := m.storage.WriteFile(ctx, name, data); err != nil {
+			return cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+		}
+	}
+	m.metricCheckpointTs.Set(float64(meta.CheckpointTs))
+	m.metricResolvedTs.Set(float64(meta.ResolvedTs))
+	return nil
+}
+
+// removeExpiredLogs removes the redo log files of the changefeed, which only contain
+// the events with commitTs less than checkpointTs. These events are already
+// flushed to downstream, so there is no need to keep them.
+func (m *Manager) removeExpiredLogs(ctx context.Context, checkpointTs uint64) error {
+	if m.storage == nil || checkpointTs == 0 {
+		return nil
+	}
+	toRemove := make([]string, 0)
+	err := m.storage.WalkDir(ctx, &storage.WalkOption{}, func(path string, _ int64) error {
+		if !isChangefeedLogFile(path, m.changefeedID) {
+			return nil
+		}
+		maxCommitTs, fileType, err := redoConfig.ParseLogFileName(path)
+		if err != nil {
+			return nil
+		}
+		if fileType != redoConfig.RedoMetaFileType && maxCommitTs < checkpointTs {
+			toRemove = append(toRemove, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+	}
+	for _, path := range toRemove {
+		if err := m.storage.DeleteFile(ctx, path); err != nil {
+			return cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+		}
+	}
+	return nil
+}
+
+// Close stops the manager, the buffered logs which are not flushed are discarded.
+// If remove is true, the redo meta written by the manager is removed too,
+// because the changefeed is removed and the logs are useless. Otherwise the
+// meta is marked as idle, since the dispatchers are moved to other captures.
+func (m *Manager) Close(remove bool) {
+	m.cancel()
+	if m.storage != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), redoConfig.CloseTimeout)
+		defer cancel()
+		if remove {
+			name := getMetaFileName(m.captureID, m.changefeedID)
+			if err := m.storage.DeleteFile(ctx, name); err != nil {
+				log.Warn("remove redo meta failed",
+					zap.String("namespace", m.changefeedID.Namespace()),
+					zap.String("changefeed", m.changefeedID.Name()),
+					zap.Error(err))
+			}
+		} else {
+			meta := m.calculateMeta()
+			meta.Idle = true
+			if err := m.writeMeta(ctx, meta); err != nil {
+				log.Warn("mark redo meta as idle failed",
+					zap.String("namespace", m.changefeedID.Namespace()),
+					zap.String("changefeed", m.changefeedID.Name()),
+					zap.Error(err))
+			}
+		}
+	}
+	if m.storage != nil {
+		m.storage.Close()
+	}
+
+	namespace, name := m.changefeedID.Namespace(), m.changefeedID.Name()
+	metrics.RedoWriteBytesGauge.DeleteLabelValues(namespace, name, redoConfig.RedoRowLogFileType)
+	metrics.RedoWriteBytesGauge.DeleteLabelValues(namespace, name, redoConfig.RedoDDLLogFileType)
+	metrics.RedoWriteLogDurationHistogram.DeleteLabelValues(namespace, name, redoConfig.RedoRowLogFileType)
+	metrics.RedoWriteLogDurationHistogram.DeleteLabelValues(namespace, name, redoConfig.RedoDDLLogFileType)
+	metrics.RedoFlushLogDurationHistogram.DeleteLabelValues(namespace, name)
+	metrics.RedoWriteEventCounter.DeleteLabelValues(namespace, name, redoConfig.RedoRowLogFileType)
+	metrics.RedoWriteEventCounter.DeleteLabelValues(namespace, name, redoConfig.RedoDDLLogFileType)
+	metrics.RedoResolvedTsGauge.DeleteLabelValues(namespace, name)
+	metrics.RedoCheckpointTsGauge.DeleteLabelValues(namespace, name)
+}
@@ -0,0 +1,324 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/pkg/compression"
+	redoConfig "github.com/pingcap/tiflow/pkg/redo"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestRedoLogMarshal(t *testing.T) {
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32))")
+	require.NotNil(t, job)
+	dmlEvent := helper.DML2Event("test", "t", "insert into t values (1, 'a')", "insert into t values (2, 'b')")
+
+	data, err := NewDMLRedoLog(dmlEvent).Marshal()
+	require.NoError(t, err)
+	redoLog := &RedoLog{}
+	require.NoError(t, redoLog.Unmarshal(data))
+	require.Equal(t, LogTypeDML, redoLog.Type)
+	require.Equal(t, dmlEvent.GetCommitTs(), redoLog.GetCommitTs())
+	require.Equal(t, dmlEvent.GetStartTs(), redoLog.DML.GetStartTs())
+	require.Equal(t, dmlEvent.Len(), redoLog.DML.Len())
+	require.Equal(t, "test", redoLog.DML.TableInfo.GetSchemaName())
+	require.Equal(t, "t", redoLog.DML.TableInfo.GetTableName())
+
+	for _, expected := range []struct {
+		id   int64
+		name string
+	}{{1, "a"}, {2, "b"}} {
+		row, ok := redoLog.DML.GetNextRow()
+		require.True(t, ok)
+		require.Equal(t, expected.id, row.Row.GetInt64(0))
+		require.Equal(t, expected.name, row.Row.GetString(1))
+	}
+	_, ok := redoLog.DML.GetNextRow()
+	require.False(t, ok)
+
+	ddlEvent := &commonEvent.DDLEvent{
+		Query:      job.Query,
+		Type:       byte(job.Type),
+		SchemaName: job.SchemaName,
+		TableName:  job.TableName,
+		FinishedTs: 100,
+		TableInfo:  helper.GetTableInfo(job),
+	}
+	data, err = NewDDLRedoLog(ddlEvent).Marshal()
+	require.NoError(t, err)
+	redoLog = &RedoLog{}
+	require.NoError(t, redoLog.Unmarshal(data))
+	require.Equal(t, LogTypeDDL, redoLog.Type)
+	require.Equal(t, uint64(100), redoLog.GetCommitTs())
+	require.Equal(t, job.Query, redoLog.DDL.Query)
+	require.Equal(t, "t", redoLog.DDL.TableInfo.GetTableName())
+}
+
+func TestManagerWriteAndRead(t *testing.T) {
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32))")
+	require.NotNil(t, job)
+	dmlEvent := helper.DML2Event("test", "t", "insert into t values (1, 'a')", "insert into t values (2, 'b')")
+	ddlEvent := &commonEvent.DDLEvent{
+		Query:      job.Query,
+		Type:       byte(job.Type),
+		SchemaName: job.SchemaName,
+		TableName:  job.TableName,
+		FinishedTs: dmlEvent.GetCommitTs() - 1,
+		TableInfo:  helper.GetTableInfo(job),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	cfg := &config.ConsistentConfig{
+		Level:                 "eventual",
+		Storage:               "file://" + dir,
+		MaxLogSize:            1,
+		FlushIntervalInMs:     50,
+		MetaFlushIntervalInMs: 50,
+		Compression:           compression.LZ4,
+	}
+	require.True(t, IsConsistentEnabled(cfg))
+	m, err := NewManager(ctx, common.ChangefeedID4Test("test", "test"), "capture-1", cfg)
+	require.NoError(t, err)
+	go func() {
+		_ = m.Run()
+	}()
+	defer m.Close(false)
+
+	checkpointTs := ddlEvent.GetCommitTs() - 1
+	resolvedTs := dmlEvent.GetCommitTs() + 10
+	dispatcherID := common.NewDispatcherID()
+	m.AddDispatcher(dispatcherID, checkpointTs)
+	m.UpdateCheckpointTs(checkpointTs)
+
+	require.NoError(t, m.WriteDDLEvent(ddlEvent))
+	var flushed atomic.Bool
+	m.AddDMLEvent(dispatcherID, dmlEvent, func() { flushed.Store(true) })
+	m.UpdateResolvedTs(dispatcherID, resolvedTs)
+	require.Eventually(t, flushed.Load, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return m.GetResolvedTs() == resolvedTs
+	}, 5*time.Second, 10*time.Millisecond)
+
+	reader, err := NewLogReader(ctx, "file://"+dir)
+	require.NoError(t, err)
+	defer reader.Close()
+	require.Eventually(t, func() bool {
+		cp, rts, err := reader.ReadMeta(ctx)
+		return err == nil && cp == checkpointTs && rts == resolvedTs
+	}, 5*time.Second, 10*time.Millisecond)
+
+	logs := readAllLogs(t, reader, checkpointTs, resolvedTs)
+	require.Len(t, logs, 2)
+	require.Equal(t, LogTypeDDL, logs[0].Type)
+	require.Equal(t, job.Query, logs[0].DDL.Query)
+	require.Equal(t, LogTypeDML, logs[1].Type)
+	require.Equal(t, dmlEvent.GetCommitTs(), logs[1].GetCommitTs())
+	require.Equal(t, dmlEvent.Len(), logs[1].DML.Len())
+
+	// the events not greater than startTs are skipped.
+	logs = readAllLogs(t, reader, ddlEvent.GetCommitTs(), resolvedTs)
+	require.Len(t, logs, 1)
+	require.Equal(t, LogTypeDML, logs[0].Type)
+}
+
+func readAllLogs(t *testing.T, reader *LogReader, startTs, endTs uint64) []*RedoLog {
+	dir := t.TempDir()
+	iter, err := reader.ReadLogs(context.Background(), dir, startTs, endTs)
+	require.NoError(t, err)
+	logs := make([]*RedoLog, 0)
+	for {
+		l, err := iter.Next()
+		require.NoError(t, err)
+		if l == nil {
+			break
+		}
+		logs = append(logs, l)
+	}
+	require.NoError(t, iter.Close())
+	// the sorted files are removed after the iterator is closed.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	return logs
+}
+
+func TestReadLogsMergeSortedFiles(t *testing.T) {
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32))")
+	require.NotNil(t, job)
+	newDDL := func(query string, commitTs uint64) []byte {
+		data, err := NewDDLRedoLog(&commonEvent.DDLEvent{
+			Query:      query,
+			Type:       byte(job.Type),
+			SchemaName: job.SchemaName,
+			TableName:  job.TableName,
+			FinishedTs: commitTs,
+			TableInfo:  helper.GetTableInfo(job),
+		}).Marshal()
+		require.NoError(t, err)
+		return data
+	}
+	newDML := func(commitTs uint64, sql string) []byte {
+		event := helper.DML2Event("test", "t", sql)
+		event.CommitTs = commitTs
+		data, err := NewDMLRedoLog(event).Marshal()
+		require.NoError(t, err)
+		return data
+	}
+
+	dir := t.TempDir()
+	changefeedID := common.ChangefeedID4Test("test", "test")
+	writeFile := func(fileType string, maxCommitTs uint64, logs ...[]byte) {
+		data, err := encodeLogFile(logs, compression.LZ4)
+		require.NoError(t, err)
+		name := getLogFileName("capture-1", changefeedID, fileType, maxCommitTs)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o644))
+	}
+	// the logs in a file are not sorted.
+	writeFile(redoConfig.RedoRowLogFileType, 300,
+		newDML(300, "insert into t values (3, 'c')"),
+		newDML(100, "insert into t values (1, 'a')"),
+		newDML(200, "insert into t values (2, 'b')"))
+	writeFile(redoConfig.RedoDDLLogFileType, 200,
+		newDDL("alter table t add column c1 int", 200),
+		newDDL("alter table t comment 'a'", 100))
+	// the ddl event is written again after the table trigger event dispatcher is moved.
+	writeFile(redoConfig.RedoDDLLogFileType, 200,
+		newDDL("alter table t add column c1 int", 200),
+		newDDL("alter table t comment 'b'", 50))
+	// all the logs are flushed to downstream.
+	writeFile(redoConfig.RedoRowLogFileType, 60,
+		newDML(60, "insert into t values (0, 'z')"))
+
+	reader, err := NewLogReader(context.Background(), "file://"+dir)
+	require.NoError(t, err)
+	defer reader.Close()
+	logs := readAllLogs(t, reader, 60, 250)
+	require.Len(t, logs, 4)
+	require.Equal(t, LogTypeDDL, logs[0].Type)
+	require.Equal(t, "alter table t comment 'a'", logs[0].DDL.Query)
+	require.Equal(t, LogTypeDML, logs[1].Type)
+	require.Equal(t, uint64(100), logs[1].GetCommitTs())
+	require.Equal(t, LogTypeDDL, logs[2].Type)
+	require.Equal(t, "alter table t add column c1 int", logs[2].DDL.Query)
+	require.Equal(t, uint64(200), logs[2].GetCommitTs())
+	require.Equal(t, LogTypeDML, logs[3].Type)
+	require.Equal(t, uint64(200), logs[3].GetCommitTs())
+}
+
+func TestReadMetaSkipIdleCapture(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	changefeedID := common.ChangefeedID4Test("test", "test")
+	writeMeta := func(captureID string, meta LogMeta) {
+		data, err := json.Marshal(meta)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, getMetaFileName(captureID, changefeedID)), data, 0o644))
+	}
+
+	// the meta of capture-2 is not flushed since the checkpointTs advanced,
+	// it still bounds the resolvedTs.
+	writeMeta("capture-1", LogMeta{CheckpointTs: 200, ResolvedTs: 300})
+	writeMeta("capture-2", LogMeta{CheckpointTs: 0, ResolvedTs: 100})
+
+	reader, err := NewLogReader(ctx, "file://"+dir)
+	require.NoError(t, err)
+	defer reader.Close()
+	checkpointTs, resolvedTs, err := reader.ReadMeta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(200), checkpointTs)
+	require.Equal(t, uint64(100), resolvedTs)
+
+	// capture-2 is alive but all its dispatchers are moved away.
+	writeMeta("capture-2", LogMeta{CheckpointTs: 0, ResolvedTs: 100, Idle: true})
+	checkpointTs, resolvedTs, err = reader.ReadMeta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(200), checkpointTs)
+	require.Equal(t, uint64(300), resolvedTs)
+
+	// capture-2 holds dispatchers again, it bounds the resolvedTs.
+	writeMeta("capture-2", LogMeta{CheckpointTs: 0, ResolvedTs: 250})
+	checkpointTs, resolvedTs, err = reader.ReadMeta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(200), checkpointTs)
+	require.Equal(t, uint64(250), resolvedTs)
+
+	// all the captures are idle.
+	writeMeta("capture-1", LogMeta{CheckpointTs: 200, ResolvedTs: 300, Idle: true})
+	writeMeta("capture-2", LogMeta{CheckpointTs: 0, ResolvedTs: 250, Idle: true})
+	checkpointTs, resolvedTs, err = reader.ReadMeta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(200), checkpointTs)
+	require.Equal(t, uint64(200), resolvedTs)
+}
+
+func TestManagerCloseMarksMetaIdle(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cfg := &config.ConsistentConfig{
+		Level:                 "eventual",
+		Storage:               "file://" + dir,
+		FlushIntervalInMs:     50,
+		MetaFlushIntervalInMs: 50,
+	}
+	m, err := NewManager(ctx, common.ChangefeedID4Test("test", "test"), "capture-1", cfg)
+	require.NoError(t, err)
+	dispatcherID := common.NewDispatcherID()
+	m.AddDispatcher(dispatcherID, 100)
+	m.UpdateCheckpointTs(100)
+	resolvedTs, ok := m.GetDispatcherResolvedTs(dispatcherID)
+	require.True(t, ok)
+	require.Equal(t, uint64(100), resolvedTs)
+	_, ok = m.GetDispatcherResolvedTs(common.NewDispatcherID())
+	require.False(t, ok)
+
+	// the dispatchers are moved to other captures, the meta doesn't bound the resolvedTs.
+	m.Close(false)
+	reader, err := NewLogReader(ctx, "file://"+dir)
+	require.NoError(t, err)
+	defer reader.Close()
+	checkpointTs, resolvedTs, err := reader.ReadMeta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), checkpointTs)
+	require.Equal(t, uint64(100), resolvedTs)
+	data, err := os.ReadFile(filepath.Join(dir, getMetaFileName("capture-1", common.ChangefeedID4Test("test", "test"))))
+	require.NoError(t, err)
+	meta := LogMeta{}
+	require.NoError(t, json.Unmarshal(data, &meta))
+	require.True(t, meta.Idle)
+}
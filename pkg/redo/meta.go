@@ -0,0 +1,56 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/ticdc/pkg/common"
+	redoConfig "github.com/pingcap/tiflow/pkg/redo"
+)
+
+// LogMeta is the meta of the redo logs written by a capture.
+type LogMeta struct {
+	CheckpointTs uint64 `json:"checkpoint-ts"`
+	ResolvedTs   uint64 `json:"resolved-ts"`
+	// Idle is true if the capture holds no dispatcher of the changefeed,
+	// the resolvedTs of an idle capture doesn't advance any more.
+	Idle bool `json:"idle,omitempty"`
+}
+
+// getLogFileName returns the name of a new redo log file,
+// layout: captureID_namespace_changefeedID_fileType_maxCommitTs_uuid.log
+func getLogFileName(
+	captureID string, changefeedID common.ChangeFeedID, fileType string, maxCommitTs uint64,
+) string {
+	return fmt.Sprintf(redoConfig.RedoLogFileFormatV2, captureID,
+		changefeedID.Namespace(), changefeedID.Name(),
+		fileType, maxCommitTs, uuid.NewString(), redoConfig.LogEXT)
+}
+
+// getMetaFileName returns the name of the redo meta file written by the capture,
+// layout: captureID_namespace_changefeedID_meta.meta
+func getMetaFileName(captureID string, changefeedID common.ChangeFeedID) string {
+	return fmt.Sprintf("%s_%s_%s_%s%s", captureID,
+		changefeedID.Namespace(), changefeedID.Name(),
+		redoConfig.RedoMetaFileType, redoConfig.MetaEXT)
+}
+
+// isChangefeedLogFile checks whether the file is a redo log or meta file of the changefeed.
+func isChangefeedLogFile(path string, changefeedID common.ChangeFeedID) bool {
+	return strings.Contains(path,
+		fmt.Sprintf("_%s_%s_", changefeedID.Namespace(), changefeedID.Name()))
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	redoConfig "github.com/pingcap/tiflow/pkg/redo"
+	"go.uber.org/zap"
+)
+
+// LogReader reads the redo meta and the redo logs from the redo log storage.
+type LogReader struct {
+	uri     string
+	storage storage.ExternalStorage
+}
+
+// NewLogReader creates a LogReader for the redo log storage.
+func NewLogReader(ctx context.Context, storageURI string) (*LogReader, error) {
+	uri, err := storage.ParseRawURL(storageURI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrStorageInitialize, err)
+	}
+	if !redoConfig.IsValidConsistentStorage(uri.Scheme) || redoConfig.IsBlackholeStorage(uri.Scheme) {
+		return nil, cerror.ErrConsistentStorage.GenWithStackByArgs(uri.Scheme)
+	}
+	redoConfig.FixLocalScheme(uri)
+	extStorage, err := redoConfig.InitExternalStorage(ctx, *uri)
+	if err != nil {
+		return nil, err
+	}
+	return &LogReader{uri: storageURI, storage: extStorage}, nil
+}
+
+// ReadMeta reads the redo meta files written by all the captures.
+// The checkpointTs is the maximum of them, because only one capture receives
+// the checkpointTs of the changefeed. The resolvedTs is the minimum of them,
+// because each capture only records the resolvedTs of its own dispatchers.
+//
+// The metas of the captures which hold no dispatcher are marked as idle and skipped,
+// the metas of the removed changefeed are deleted. All the other metas bound the
+// resolvedTs, even if they fall behind the checkpointTs, since the capture may
+// be alive and its meta is not flushed yet. In that case the resolvedTs is
+// less than the checkpointTs, and the logs can't be applied.
+func (r *LogReader) ReadMeta(ctx context.Context) (checkpointTs, resolvedTs uint64, err error) {
+	metas := make([]LogMeta, 0)
+	err = r.storage.WalkDir(ctx, &storage.WalkOption{}, func(path string, _ int64) error {
+		if filepath.Ext(path) != redoConfig.MetaEXT {
+			return nil
+		}
+		data, err := r.storage.ReadFile(ctx, path)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+		}
+		var meta LogMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+		}
+		metas = append(metas, meta)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	if len(metas) == 0 {
+		return 0, 0, cerror.ErrRedoMetaFileNotFound.GenWithStackByArgs(r.uri)
+	}
+
+	for _, meta := range metas {
+		checkpointTs = max(checkpointTs, meta.CheckpointTs)
+	}
+	found := false
+	for _, meta := range metas {
+		if meta.Idle {
+			log.Info("skip the idle redo meta",
+				zap.String("uri", r.uri),
+				zap.Uint64("checkpointTs", meta.CheckpointTs),
+				zap.Uint64("resolvedTs", meta.ResolvedTs))
+			continue
+		}
+		if !found || meta.ResolvedTs < resolvedTs {
+			resolvedTs = meta.ResolvedTs
+			found = true
+		}
+	}
+	// all the captures are idle, there is nothing to apply after the checkpointTs.
+	if !found {
+		resolvedTs = checkpointTs
+	}
+	return checkpointTs, resolvedTs, nil
+}
+
+// ReadLogs returns an iterator of the redo logs with commitTs in (startTs, endTs],
+// which returns the logs sorted by commitTs. Each redo log file is downloaded,
+// sorted and spilled to a temporary directory in dir, so the logs are merged
+// without being loaded into memory together. The ddl events with the same
+// commitTs and query are deduplicated, since they may be written more than
+// once when the table trigger event dispatcher is moved.
+func (r *LogReader) ReadLogs(ctx context.Context, dir string, startTs, endTs uint64) (*LogIterator, error) {
+	files := make([]string, 0)
+	err := r.storage.WalkDir(ctx, &storage.WalkOption{}, func(path string, _ int64) error {
+		maxCommitTs, fileType, err := redoConfig.ParseLogFileName(filepath.Base(path))
+		if err != nil {
+			log.Warn("skip the file which is not a redo log", zap.String("path", path), zap.Error(err))
+			return nil
+		}
+		if fileType != redoConfig.RedoRowLogFileType && fileType != redoConfig.RedoDDLLogFileType {
+			return nil
+		}
+		// all the events in the file are already flushed to downstream.
+		if maxCommitTs <= startTs {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	sortDir, err := os.MkdirTemp(dir, "redo-sort-")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	iter := newLogIterator(sortDir)
+	for _, file := range files {
+		data, err := r.storage.ReadFile(ctx, file)
+		if err != nil {
+			_ = iter.Close()
+			return nil, cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+		}
+		reader, err := newSortedLogReader(sortDir, data, startTs, endTs)
+		if err != nil {
+			_ = iter.Close()
+			return nil, errors.Annotatef(err, "sort redo log file %s failed", file)
+		}
+		if reader != nil {
+			iter.addReader(reader)
+		}
+	}
+	log.Info("redo log files are sorted",
+		zap.String("uri", r.uri),
+		zap.Int("files", len(files)),
+		zap.String("dir", sortDir))
+	return iter, nil
+}
+
+// Close closes the reader.
+func (r *LogReader) Close() {
+	r.storage.Close()
+}
@@ -0,0 +1,218 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/ticdc/utils/heap"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// logLess decides the replay order of the redo logs. The logs are sorted
+// by commitTs, and the ddl event is placed before the dml events with the
+// same commitTs.
+func logLess(a, b *RedoLog) bool {
+	if a.GetCommitTs() != b.GetCommitTs() {
+		return a.GetCommitTs() < b.GetCommitTs()
+	}
+	return a.Type == LogTypeDDL && b.Type != LogTypeDDL
+}
+
+// sortedLogReader reads the redo logs of a redo log file one by one in
+// replay order. The logs are sorted and spilled to a local file when the
+// reader is created, so only the current log is kept in memory.
+type sortedLogReader struct {
+	file   *os.File
+	reader *bufio.Reader
+	// log is the current log of the reader.
+	log       *RedoLog
+	heapIndex int
+}
+
+// newSortedLogReader sorts the redo logs with commitTs in (startTs, endTs]
+// in the content of a redo log file, and writes them to a local file in dir.
+// It returns nil if no log falls into the range.
+func newSortedLogReader(dir string, data []byte, startTs, endTs uint64) (*sortedLogReader, error) {
+	records, err := decodeLogFileRecords(data)
+	if err != nil {
+		return nil, err
+	}
+	type entry struct {
+		record []byte
+		log    *RedoLog
+	}
+	entries := make([]entry, 0, len(records))
+	for _, record := range records {
+		l := &RedoLog{}
+		if err := l.Unmarshal(record); err != nil {
+			return nil, err
+		}
+		commitTs := l.GetCommitTs()
+		if commitTs <= startTs || commitTs > endTs {
+			continue
+		}
+		entries = append(entries, entry{record: record, log: l})
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return logLess(entries[i].log, entries[j].log)
+	})
+
+	file, err := os.CreateTemp(dir, "*.sort")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	r := &sortedLogReader{file: file}
+	writer := bufio.NewWriter(file)
+	for _, e := range entries {
+		if err := binary.Write(writer, binary.LittleEndian, uint32(len(e.record))); err != nil {
+			r.close()
+			return nil, errors.Trace(err)
+		}
+		if _, err := writer.Write(e.record); err != nil {
+			r.close()
+			return nil, errors.Trace(err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		r.close()
+		return nil, errors.Trace(err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		r.close()
+		return nil, errors.Trace(err)
+	}
+	r.reader = bufio.NewReader(file)
+	if _, err := r.next(); err != nil {
+		r.close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// next reads the next log into r.log, it returns false if all the logs are read.
+func (r *sortedLogReader) next() (bool, error) {
+	var length uint32
+	if err := binary.Read(r.reader, binary.LittleEndian, &length); err != nil {
+		if err == io.EOF {
+			r.log = nil
+			return false, nil
+		}
+		return false, errors.Trace(err)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.reader, data); err != nil {
+		return false, cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	l := &RedoLog{}
+	if err := l.Unmarshal(data); err != nil {
+		return false, err
+	}
+	r.log = l
+	return true, nil
+}
+
+func (r *sortedLogReader) close() {
+	_ = r.file.Close()
+}
+
+func (r *sortedLogReader) SetHeapIndex(index int) { r.heapIndex = index }
+
+func (r *sortedLogReader) GetHeapIndex() int { return r.heapIndex }
+
+func (r *sortedLogReader) LessThan(other *sortedLogReader) bool {
+	return logLess(r.log, other.log)
+}
+
+// LogIterator iterates the redo logs of all the redo log files in replay order,
+// by merging the sorted logs of each file.
+type LogIterator struct {
+	dir     string
+	readers []*sortedLogReader
+	heap    *heap.Heap[*sortedLogReader]
+
+	// ddlCommitTs and ddlQueries record the ddl events returned at the
+	// current commitTs, to deduplicate the ddl events.
+	ddlCommitTs uint64
+	ddlQueries  map[string]struct{}
+}
+
+func newLogIterator(dir string) *LogIterator {
+	return &LogIterator{
+		dir:        dir,
+		heap:       heap.NewHeap[*sortedLogReader](),
+		ddlQueries: make(map[string]struct{}),
+	}
+}
+
+func (it *LogIterator) addReader(r *sortedLogReader) {
+	it.readers = append(it.readers, r)
+	it.heap.AddOrUpdate(r)
+}
+
+// Next returns the next redo log, it returns nil if all the logs are read.
+func (it *LogIterator) Next() (*RedoLog, error) {
+	for {
+		r, ok := it.heap.PeekTop()
+		if !ok {
+			return nil, nil
+		}
+		l := r.log
+		hasNext, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if hasNext {
+			it.heap.AddOrUpdate(r)
+		} else {
+			it.heap.PopTop()
+		}
+		if l.Type == LogTypeDDL && it.isDuplicateDDL(l.DDL.GetCommitTs(), l.DDL.Query) {
+			continue
+		}
+		return l, nil
+	}
+}
+
+// isDuplicateDDL checks whether the ddl event is already returned.
+// The ddl events are returned in commitTs order, so only the queries
+// of the current commitTs are recorded.
+func (it *LogIterator) isDuplicateDDL(commitTs uint64, query string) bool {
+	if commitTs != it.ddlCommitTs {
+		it.ddlCommitTs = commitTs
+		clear(it.ddlQueries)
+	}
+	if _, ok := it.ddlQueries[query]; ok {
+		return true
+	}
+	it.ddlQueries[query] = struct{}{}
+	return false
+}
+
+// Close closes the iterator and removes the sorted files.
+func (it *LogIterator) Close() error {
+	for _, r := range it.readers {
+		r.close()
+	}
+	it.readers = nil
+	return errors.Trace(os.RemoveAll(it.dir))
+}
@@ -28,13 +28,14 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/pkg/common"
 	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
-	"github.com/pingcap/ticdc/pkg/config"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
 	"github.com/pingcap/ticdc/pkg/sink/codec/encoder"
 	timodel "github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/rowcodec"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	ticommon "github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/tikv/client-go/v2/oracle"
@@ -47,7 +48,7 @@ type BatchEncoder struct {
 	schemaM   SchemaManager
 	result    []*ticommon.Message
 
-	config *ticommon.Config
+	config *newcommon.Config
 }
 
 type avroEncodeInput struct {
@@ -88,7 +89,7 @@ func (a *BatchEncoder) encodeKey(ctx context.Context, topic string, e *commonEve
 		columns:  cols,
 		colInfos: colInfos,
 	}
-	avroCodec, header, err := a.getKeySchemaCodec(ctx, topic, &e.TableInfo.TableName, e.TableInfo.Version, keyColumns)
+	avroCodec, header, err := a.getKeySchemaCodec(ctx, topic, &e.TableInfo.TableName, e.TableInfo.GetVersion(), keyColumns)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -173,7 +174,7 @@ func (a *BatchEncoder) encodeValue(ctx context.Context, topic string, e *commonE
 		return nil, nil
 	}
 
-	avroCodec, header, err := a.getValueSchemaCodec(ctx, topic, &e.TableInfo.TableName, e.TableInfo.Version, input)
+	avroCodec, header, err := a.getValueSchemaCodec(ctx, topic, &e.TableInfo.TableName, e.TableInfo.GetVersion(), input)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -209,10 +210,10 @@ func (a *BatchEncoder) encodeValue(ctx context.Context, topic string, e *commonE
 func (a *BatchEncoder) AppendRowChangedEvent(
 	ctx context.Context,
 	topic string,
-	e *commonEvent.RowChangedEvent,
-	callback func(),
+	event *commonEvent.RowEvent,
 ) error {
 	topic = sanitizeTopic(topic)
+	e := event.ToRowChangedEvent()
 
 	key, err := a.encodeKey(ctx, topic, e)
 	if err != nil {
@@ -235,7 +236,7 @@ func (a *BatchEncoder) AppendRowChangedEvent(
 		e.TableInfo.GetSchemaNamePtr(),
 		e.TableInfo.GetTableNamePtr(),
 	)
-	message.Callback = callback
+	message.Callback = event.Callback
 	message.IncRowsCount()
 
 	if message.Length() > a.config.MaxMessageBytes {
@@ -280,26 +281,33 @@ type ddlEvent struct {
 // EncodeDDLEvent only encode DDL event if the watermark event is enabled
 // it's only used for the testing purpose.
 func (a *BatchEncoder) EncodeDDLEvent(e *commonEvent.DDLEvent) (*ticommon.Message, error) {
-	// if a.config.EnableTiDBExtension && a.config.AvroEnableWatermark {
-	// 	buf := new(bytes.Buffer)
-	// 	_ = binary.Write(buf, binary.BigEndian, ddlByte)
-
-	// 	event := &ddlEvent{
-	// 		Query:    e.Query,
-	// 		Type:     e.Type,
-	// 		Schema:   e.TableInfo.TableName.Schema,
-	// 		Table:    e.TableInfo.TableName.Table,
-	// 		CommitTs: e.CommitTs,
-	// 	}
-	// 	data, err := json.Marshal(event)
-	// 	if err != nil {
-	// 		return nil, cerror.WrapError(cerror.ErrAvroToEnvelopeError, err)
-	// 	}
-	// 	buf.Write(data)
-
-	// 	value := buf.Bytes()
-	// 	return ticommon.NewDDLMsg(config.ProtocolAvro, nil, value, e), nil
-	// }
+	if a.config.EnableTiDBExtension && a.config.AvroEnableWatermark {
+		buf := new(bytes.Buffer)
+		_ = binary.Write(buf, binary.BigEndian, ddlByte)
+
+		event := &ddlEvent{
+			Query:    e.Query,
+			Type:     timodel.ActionType(e.Type),
+			Schema:   e.SchemaName,
+			Table:    e.TableName,
+			CommitTs: e.GetCommitTs(),
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrAvroToEnvelopeError, err)
+		}
+		buf.Write(data)
+
+		value := buf.Bytes()
+		return &ticommon.Message{
+			Value:    value,
+			Ts:       e.GetCommitTs(),
+			Schema:   &e.SchemaName,
+			Table:    &e.TableName,
+			Type:     model.MessageTypeDDL,
+			Protocol: config.ProtocolAvro,
+		}, nil
+	}
 
 	return nil, nil
 }
@@ -406,7 +414,7 @@ func flagFromTiDBType(tp string) model.ColumnFlagType {
 	return flag
 }
 
-func mysqlTypeFromTiDBType(tidbType string) byte {
+func mysqlTypeFromTiDBType(tidbType string) (byte, error) {
 	var result byte
 	switch tidbType {
 	case "INT", "INT UNSIGNED":
@@ -442,9 +450,9 @@ func mysqlTypeFromTiDBType(tidbType string) byte {
 	case "YEAR":
 		result = mysql.TypeYear
 	default:
-		log.Panic("this should not happen, unknown TiDB type", zap.String("type", tidbType))
+		return 0, cerror.ErrAvroInvalidMessage.GenWithStack("unknown TiDB type %s", tidbType)
 	}
-	return result
+	return result, nil
 }
 
 const (
@@ -714,7 +722,7 @@ func (a *BatchEncoder) columnToAvroSchema(
 	case mysql.TypeLonglong: // BIGINT
 		t := "long"
 		if col.Flag.IsUnsigned() &&
-			a.config.AvroBigintUnsignedHandlingMode == ticommon.BigintUnsignedHandlingModeString {
+			a.config.AvroBigintUnsignedHandlingMode == newcommon.BigintUnsignedHandlingModeString {
 			t = "string"
 		}
 		return avroSchema{
@@ -744,7 +752,7 @@ func (a *BatchEncoder) columnToAvroSchema(
 			},
 		}, nil
 	case mysql.TypeNewDecimal:
-		if a.config.AvroDecimalHandlingMode == ticommon.DecimalHandlingModePrecise {
+		if a.config.AvroDecimalHandlingMode == newcommon.DecimalHandlingModePrecise {
 			defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(ft.GetType())
 			displayFlen, displayDecimal := ft.GetFlen(), ft.GetDecimal()
 			// length not specified, set it to system type default
@@ -859,7 +867,7 @@ func (a *BatchEncoder) columnToAvroData(
 	case mysql.TypeLonglong:
 		if v, ok := col.Value.(string); ok {
 			if col.Flag.IsUnsigned() {
-				if a.config.AvroBigintUnsignedHandlingMode == ticommon.BigintUnsignedHandlingModeString {
+				if a.config.AvroBigintUnsignedHandlingMode == newcommon.BigintUnsignedHandlingModeString {
 					return v, "string", nil
 				}
 				n, err := strconv.ParseUint(v, 10, 64)
@@ -875,7 +883,7 @@ func (a *BatchEncoder) columnToAvroData(
 			return n, "long", nil
 		}
 		if col.Flag.IsUnsigned() {
-			if a.config.AvroBigintUnsignedHandlingMode == ticommon.BigintUnsignedHandlingModeLong {
+			if a.config.AvroBigintUnsignedHandlingMode == newcommon.BigintUnsignedHandlingModeLong {
 				return int64(col.Value.(uint64)), "long", nil
 			}
 			// bigintUnsignedHandlingMode == "string"
@@ -906,7 +914,7 @@ func (a *BatchEncoder) columnToAvroData(
 		}
 		return []byte(types.NewBinaryLiteralFromUint(col.Value.(uint64), -1)), "bytes", nil
 	case mysql.TypeNewDecimal:
-		if a.config.AvroDecimalHandlingMode == ticommon.DecimalHandlingModePrecise {
+		if a.config.AvroDecimalHandlingMode == newcommon.DecimalHandlingModePrecise {
 			v, succ := new(big.Rat).SetString(col.Value.(string))
 			if !succ {
 				return nil, "", cerror.ErrAvroEncodeFailed.GenWithStack(
@@ -1002,7 +1010,7 @@ func (r *avroEncodeResult) toEnvelope() ([]byte, error) {
 
 type batchEncoderBuilder struct {
 	namespace string
-	config    *ticommon.Config
+	config    *newcommon.Config
 	schemaM   SchemaManager
 }
 
@@ -1011,28 +1019,29 @@ const (
 	valueSchemaSuffix = "-value"
 )
 
-// NewAvroEncoder return a avro encoder.
-func NewAvroEncoder(ctx context.Context, config *ticommon.Config) (encoder.EventEncoder, error) {
-	var schemaM SchemaManager
-	var err error
-
+// NewSchemaManager creates the schema manager of the schema registry set by the config.
+func NewSchemaManager(ctx context.Context, config *newcommon.Config) (SchemaManager, error) {
 	schemaRegistryType := config.SchemaRegistryType()
 	switch schemaRegistryType {
-	case ticommon.SchemaRegistryTypeConfluent:
-		schemaM, err = NewConfluentSchemaManager(ctx, config.AvroConfluentSchemaRegistry, nil)
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
-	case ticommon.SchemaRegistryTypeGlue:
-		schemaM, err = NewGlueSchemaManager(ctx, config.AvroGlueSchemaRegistry)
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
+	case newcommon.SchemaRegistryTypeConfluent:
+		schemaM, err := NewConfluentSchemaManager(ctx, config.AvroConfluentSchemaRegistry, nil)
+		return schemaM, errors.Trace(err)
+	case newcommon.SchemaRegistryTypeGlue:
+		schemaM, err := NewGlueSchemaManager(ctx, config.AvroGlueSchemaRegistry)
+		return schemaM, errors.Trace(err)
 	default:
 		return nil, cerror.ErrAvroSchemaAPIError.GenWithStackByArgs(schemaRegistryType)
 	}
+}
+
+// NewAvroEncoder return a avro encoder.
+func NewAvroEncoder(ctx context.Context, config *newcommon.Config) (encoder.EventEncoder, error) {
+	schemaM, err := NewSchemaManager(ctx, config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return &BatchEncoder{
-		namespace: config.ChangefeedID.Namespace,
+		namespace: config.ChangefeedID.Namespace(),
 		schemaM:   schemaM,
 		result:    make([]*ticommon.Message, 0, 1),
 		config:    config,
@@ -0,0 +1,164 @@
+package avro
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/ticdc/pkg/common"
+	pevent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	codecdecoder "github.com/pingcap/ticdc/pkg/sink/codec/decoder"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	ticommon "github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockGlueSchemaManager() *glueSchemaManager {
+	return &glueSchemaManager{
+		registryName: "test",
+		client:       newMockGlueClientImpl(),
+		cache:        make(map[string]*schemaCacheEntry),
+		registryType: newcommon.SchemaRegistryTypeGlue,
+	}
+}
+
+func TestDecodeRowChangedEvents(t *testing.T) {
+	ctx := context.Background()
+	codecConfig := newcommon.NewConfig(config.ProtocolAvro)
+	codecConfig.EnableTiDBExtension = true
+	// the registry is shared by the encoder and the decoder as the real one.
+	schemaM := newMockGlueSchemaManager()
+	encoder := &BatchEncoder{
+		schemaM: schemaM,
+		result:  make([]*ticommon.Message, 0, 1),
+		config:  codecConfig,
+	}
+
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+
+	job := helper.DDL2Job(`create table test.t(a int primary key, b varchar(32), c double,
+		d datetime, f enum('x', 'y'), g blob)`)
+	tableInfo := helper.GetTableInfo(job)
+	dmlEvent := helper.DML2Event("test", "t",
+		`insert into test.t values (1, 'hello', 3.14, '2024-01-01 10:00:00', 'y', x'0102')`)
+	require.NotNil(t, dmlEvent)
+	insertRow, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+	deleteRow := pevent.RowChange{PreRow: insertRow.Row, RowType: pevent.RowTypeDelete}
+
+	for _, row := range []pevent.RowChange{insertRow, deleteRow} {
+		err := encoder.AppendRowChangedEvent(ctx, "topic", &pevent.RowEvent{
+			TableInfo:      tableInfo,
+			CommitTs:       dmlEvent.CommitTs,
+			Event:          row,
+			ColumnSelector: common.NewDefaultColumnSelector(),
+			Callback:       func() {},
+		})
+		require.NoError(t, err)
+	}
+	messages := encoder.Build()
+	require.Len(t, messages, 2)
+
+	decoder := NewDecoder(codecConfig, schemaM, "topic", nil)
+	var events []*pevent.DMLEvent
+	for _, message := range messages {
+		require.NoError(t, decoder.AddKeyValue(message.Key, message.Value))
+		tp, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, tp)
+		event, err := decoder.NextDMLEvent()
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+
+	// the insert event carries all the columns
+	event := events[0]
+	require.Equal(t, dmlEvent.CommitTs, event.CommitTs)
+	require.Equal(t, "test", event.TableInfo.GetSchemaName())
+	require.Equal(t, "t", event.TableInfo.GetTableName())
+	row, ok := event.GetNextRow()
+	require.True(t, ok)
+	require.Equal(t, pevent.RowTypeInsert, row.RowType)
+	codecdecoder.RequireRowEqual(t, tableInfo, insertRow.Row, event.TableInfo, row.Row)
+
+	// the delete event only carries the handle key columns in the key part
+	event = events[1]
+	require.Equal(t, "t", event.TableInfo.GetTableName())
+	row, ok = event.GetNextRow()
+	require.True(t, ok)
+	require.Equal(t, pevent.RowTypeDelete, row.RowType)
+	require.Len(t, event.TableInfo.Columns, 1)
+	require.Equal(t, int64(1), extractColValByName(t, event.TableInfo, &row, "a"))
+}
+
+func extractColValByName(t *testing.T, tableInfo *common.TableInfo, row *pevent.RowChange, name string) interface{} {
+	colID, ok := tableInfo.NameToColID[name]
+	require.True(t, ok, name)
+	offset := tableInfo.ColumnsOffset[colID]
+	actual := row.Row
+	if row.RowType == pevent.RowTypeDelete {
+		actual = row.PreRow
+	}
+	value, err := common.ExtractColVal(&actual, tableInfo.Columns[offset], offset)
+	require.NoError(t, err)
+	return value
+}
+
+func TestAssembleEventWithInvalidTypeInfo(t *testing.T) {
+	keyMap := map[string]interface{}{"a": int32(1)}
+	schema := map[string]interface{}{
+		"namespace": ".test",
+		"name":      "t",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "a",
+				"type": "int",
+			},
+		},
+	}
+	// the type info without the connect parameters returns an error instead of panic
+	_, err := assembleEvent(keyMap, keyMap, schema, true)
+	require.Error(t, err)
+
+	schema["fields"] = []interface{}{
+		map[string]interface{}{
+			"name": "a",
+			"type": []interface{}{"null", "int"},
+		},
+	}
+	_, err = assembleEvent(keyMap, keyMap, schema, true)
+	require.Error(t, err)
+
+	schema["fields"] = []interface{}{
+		map[string]interface{}{
+			"name": "a",
+			"type": map[string]interface{}{
+				"type":               "int",
+				"connect.parameters": map[string]interface{}{"tidb_type": "UNKNOWN"},
+			},
+		},
+	}
+	_, err = assembleEvent(keyMap, keyMap, schema, true)
+	require.Error(t, err)
+
+	schema["fields"] = []interface{}{
+		map[string]interface{}{
+			"name": "a",
+			"type": []interface{}{
+				"null",
+				map[string]interface{}{
+					"type":               "int",
+					"connect.parameters": map[string]interface{}{"tidb_type": "INT"},
+				},
+			},
+		},
+	}
+	event, err := assembleEvent(keyMap, keyMap, schema, true)
+	require.NoError(t, err)
+	require.Len(t, event.PreColumns, 1)
+	require.Equal(t, int32(1), event.PreColumns[0].Value)
+}
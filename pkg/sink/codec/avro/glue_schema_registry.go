@@ -30,8 +30,8 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/sink/codec/common"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
-	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"go.uber.org/zap"
 )
 
@@ -131,10 +131,10 @@ func (m *glueSchemaManager) Lookup(
 ) (*goavro.Codec, error) {
 	m.cacheRWLock.RLock()
 	entry, exists := m.cache[schemaName]
-	if exists && entry.schemaID.confluentSchemaID == schemaID.confluentSchemaID {
+	if exists && entry.schemaID.glueSchemaID == schemaID.glueSchemaID {
 		log.Debug("Avro schema lookup cache hit",
 			zap.String("key", schemaName),
-			zap.Int("schemaID", entry.schemaID.confluentSchemaID))
+			zap.String("schemaID", entry.schemaID.glueSchemaID))
 		m.cacheRWLock.RUnlock()
 		return entry.codec, nil
 	}
@@ -142,7 +142,7 @@ func (m *glueSchemaManager) Lookup(
 
 	log.Info("Avro schema lookup cache miss",
 		zap.String("key", schemaName),
-		zap.Int("schemaID", schemaID.confluentSchemaID))
+		zap.String("schemaID", schemaID.glueSchemaID))
 
 	ok, schema, err := m.getSchemaByID(ctx, schemaID.glueSchemaID)
 	if err != nil {
@@ -109,9 +109,9 @@ func (b *bootstrapWorker) run(ctx context.Context) error {
 func (b *bootstrapWorker) addEvent(
 	ctx context.Context,
 	key model.TopicPartitionKey,
-	row *commonEvent.RowChangedEvent,
+	row *commonEvent.RowEvent,
 ) error {
-	table, ok := b.activeTables.Load(row.GetTableID())
+	table, ok := b.activeTables.Load(row.PhysicalTableID)
 	if !ok {
 		tb := newTableStatistic(key, row)
 		b.activeTables.Store(tb.id, tb)
@@ -155,10 +155,11 @@ func (b *bootstrapWorker) sendBootstrapMsg(ctx context.Context, table *tableStat
 
 func NewBootstrapDDLEvent(tableInfo *common.TableInfo) *commonEvent.DDLEvent {
 	return &commonEvent.DDLEvent{
-		// StartTs:  0,
-		FinishedTs: 0,
-		// TableInfo:   tableInfo,
-		// IsBootstrap: true,
+		FinishedTs:  0,
+		SchemaName:  tableInfo.GetSchemaName(),
+		TableName:   tableInfo.GetTableName(),
+		TableInfo:   tableInfo,
+		IsBootstrap: true,
 	}
 }
 
@@ -231,9 +232,9 @@ type tableStatistic struct {
 	tableInfo atomic.Value
 }
 
-func newTableStatistic(key model.TopicPartitionKey, row *commonEvent.RowChangedEvent) *tableStatistic {
+func newTableStatistic(key model.TopicPartitionKey, row *commonEvent.RowEvent) *tableStatistic {
 	res := &tableStatistic{
-		id:    row.GetTableID(),
+		id:    row.PhysicalTableID,
 		topic: key.Topic,
 	}
 	res.totalPartition.Store(key.TotalPartition)
@@ -254,14 +255,14 @@ func (t *tableStatistic) shouldSendBootstrapMsg(
 		t.counter.Load() >= sendBootstrapMsgCountInterval
 }
 
-func (t *tableStatistic) update(row *commonEvent.RowChangedEvent, totalPartition int32) {
+func (t *tableStatistic) update(row *commonEvent.RowEvent, totalPartition int32) {
 	t.counter.Add(1)
 	t.lastMsgReceivedTime.Store(time.Now())
 
 	// Note(dongmen): Rename Table DDL is a special case,
 	// the TableInfo.Name is changed but the TableInfo.UpdateTs is not changed.
 	if t.version.Load() != row.TableInfo.UpdateTS ||
-		t.tableInfo.Load().(*model.TableInfo).Name != row.TableInfo.Name {
+		t.tableInfo.Load().(*common.TableInfo).Name != row.TableInfo.Name {
 		t.version.Store(row.TableInfo.UpdateTS)
 		t.tableInfo.Store(row.TableInfo)
 	}
@@ -13,27 +13,40 @@
 
 package canal
 
-// import (
-// 	"github.com/pingcap/tiflow/cdc/model"
-// 	canal "github.com/pingcap/tiflow/proto/canal"
-// )
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	timodel "github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/utils"
+	canal "github.com/pingcap/tiflow/proto/canal"
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding/charmap"
+)
 
 const tidbWaterMarkType = "TIDB_WATERMARK"
 
-// // The TiCDC Canal-JSON implementation extend the official format with a TiDB extension field.
-// // canalJSONMessageInterface is used to support this without affect the original format.
+// The TiCDC Canal-JSON implementation extend the official format with a TiDB extension field.
+// canalJSONMessageInterface is used to support this without affect the original format.
 type canalJSONMessageInterface interface {
-	// getSchema() *string
-	// getTable() *string
-	// getCommitTs() uint64
-	// getQuery() string
-	// getOld() map[string]interface{}
-	// getData() map[string]interface{}
-	// getMySQLType() map[string]string
-	// getJavaSQLType() map[string]int32
-	// messageType() model.MessageType
-	// eventType() canal.EventType
-	// pkNameSet() map[string]struct{}
+	getSchema() *string
+	getTable() *string
+	getCommitTs() uint64
+	getQuery() string
+	getOld() map[string]interface{}
+	getData() map[string]interface{}
+	getMySQLType() map[string]string
+	getJavaSQLType() map[string]int32
+	messageType() model.MessageType
+	eventType() canal.EventType
+	pkNameSet() map[string]struct{}
 }
 
 // JSONMessage adapted from https://github.com/alibaba/canal/blob/b54bea5e3337c9597c427a53071d214ff04628d1/protocol/src/main/java/com/alibaba/otter/canal/protocol/FlatMessage.java#L1
@@ -60,68 +73,68 @@ type JSONMessage struct {
 	Old  []map[string]interface{} `json:"old"`
 }
 
-// func (c *JSONMessage) getSchema() *string {
-// 	return &c.Schema
-// }
-
-// func (c *JSONMessage) getTable() *string {
-// 	return &c.Table
-// }
-
-// // for JSONMessage, we lost the commitTs.
-// func (c *JSONMessage) getCommitTs() uint64 {
-// 	return 0
-// }
-
-// func (c *JSONMessage) getQuery() string {
-// 	return c.Query
-// }
-
-// func (c *JSONMessage) getOld() map[string]interface{} {
-// 	if c.Old == nil {
-// 		return nil
-// 	}
-// 	return c.Old[0]
-// }
-
-// func (c *JSONMessage) getData() map[string]interface{} {
-// 	if c.Data == nil {
-// 		return nil
-// 	}
-// 	return c.Data[0]
-// }
-
-// func (c *JSONMessage) getMySQLType() map[string]string {
-// 	return c.MySQLType
-// }
-
-// func (c *JSONMessage) getJavaSQLType() map[string]int32 {
-// 	return c.SQLType
-// }
-
-// func (c *JSONMessage) messageType() model.MessageType {
-// 	if c.IsDDL {
-// 		return model.MessageTypeDDL
-// 	}
-
-// 	if c.EventType == tidbWaterMarkType {
-// 		return model.MessageTypeResolved
-// 	}
-
-// 	return model.MessageTypeRow
-// }
-
-// func (c *JSONMessage) eventType() canal.EventType {
-// 	return canal.EventType(canal.EventType_value[c.EventType])
-// }
-
-// func (c *JSONMessage) pkNameSet() map[string]struct{} {
-// 	result := make(map[string]struct{}, len(c.PKNames))
-// 	for _, item := range c.PKNames {
-// 		result[item] = struct{}{}
-// 	}
-// 	return result
-// }
+func (c *JSONMessage) getSchema() *string {
+	return &c.Schema
+}
+
+func (c *JSONMessage) getTable() *string {
+	return &c.Table
+}
+
+// for JSONMessage, we lost the commitTs.
+func (c *JSONMessage) getCommitTs() uint64 {
+	return 0
+}
+
+func (c *JSONMessage) getQuery() string {
+	return c.Query
+}
+
+func (c *JSONMessage) getOld() map[string]interface{} {
+	if c.Old == nil {
+		return nil
+	}
+	return c.Old[0]
+}
+
+func (c *JSONMessage) getData() map[string]interface{} {
+	if c.Data == nil {
+		return nil
+	}
+	return c.Data[0]
+}
+
+func (c *JSONMessage) getMySQLType() map[string]string {
+	return c.MySQLType
+}
+
+func (c *JSONMessage) getJavaSQLType() map[string]int32 {
+	return c.SQLType
+}
+
+func (c *JSONMessage) messageType() model.MessageType {
+	if c.IsDDL {
+		return model.MessageTypeDDL
+	}
+
+	if c.EventType == tidbWaterMarkType {
+		return model.MessageTypeResolved
+	}
+
+	return model.MessageTypeRow
+}
+
+func (c *JSONMessage) eventType() canal.EventType {
+	return canal.EventType(canal.EventType_value[c.EventType])
+}
+
+func (c *JSONMessage) pkNameSet() map[string]struct{} {
+	result := make(map[string]struct{}, len(c.PKNames))
+	for _, item := range c.PKNames {
+		result[item] = struct{}{}
+	}
+	return result
+}
 
 type tidbExtension struct {
 	CommitTs           uint64 `json:"commitTs,omitempty"`
@@ -139,6 +152,146 @@ type canalJSONMessageWithTiDBExtension struct {
 	Extensions *tidbExtension `json:"_tidb"`
 }
 
-// func (c *canalJSONMessageWithTiDBExtension) getCommitTs() uint64 {
-// 	return c.Extensions.CommitTs
-// }
+func (c *canalJSONMessageWithTiDBExtension) getCommitTs() uint64 {
+	return c.Extensions.CommitTs
+}
+
+func canalJSONMessage2RowChange(msg canalJSONMessageInterface) (*commonEvent.RowChangedEvent, error) {
+	result := new(commonEvent.RowChangedEvent)
+	result.CommitTs = msg.getCommitTs()
+	mysqlType := msg.getMySQLType()
+
+	cols, err := canalJSONColumnMap2RowChangeColumns(msg.getData(), mysqlType)
+	if err != nil {
+		return nil, err
+	}
+	result.TableInfo = common.BuildTableInfoWithPKNames4Test(*msg.getSchema(), *msg.getTable(), cols, msg.pkNameSet())
+
+	switch msg.eventType() {
+	case canal.EventType_DELETE:
+		// for `DELETE` event, `data` contain the old data, set it as the `PreColumns`
+		result.PreColumns = cols
+	case canal.EventType_UPDATE:
+		// for `UPDATE`, `old` contain old data, set it as the `PreColumns`,
+		// the columns not in the `old` are filled by the new value when building the DML event.
+		result.Columns = cols
+		result.PreColumns, err = canalJSONColumnMap2RowChangeColumns(msg.getOld(), mysqlType)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.PreColumns) == 0 {
+			result.PreColumns = cols
+		}
+	default:
+		// for `INSERT`, `data` contain fresh data, set it as the `Columns`
+		result.Columns = cols
+	}
+	return result, nil
+}
+
+func canalJSONColumnMap2RowChangeColumns(cols map[string]interface{}, mysqlType map[string]string) ([]*common.Column, error) {
+	result := make([]*common.Column, 0, len(cols))
+	for name, value := range cols {
+		mysqlTypeStr, ok := mysqlType[name]
+		if !ok {
+			// this should not happen, else we have to check encoding for mysqlType.
+			return nil, cerror.ErrCanalDecodeFailed.GenWithStack(
+				"mysql type does not found, column: %+v, mysqlType: %+v", name, mysqlType)
+		}
+		col, err := canalJSONFormatColumn(value, name, mysqlTypeStr)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, col)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.Compare(result[i].Name, result[j].Name) < 0
+	})
+	return result, nil
+}
+
+// canalJSONFormatColumn reverses the value formatted by `formatColumnValue`,
+// the numeric values are kept as string, which are parsed by the field type later.
+func canalJSONFormatColumn(value interface{}, name string, mysqlTypeStr string) (*common.Column, error) {
+	mysqlType := utils.ExtractBasicMySQLType(mysqlTypeStr)
+	result := &common.Column{
+		Type:  mysqlType,
+		Name:  name,
+		Value: value,
+	}
+	if strings.Contains(mysqlTypeStr, "unsigned") {
+		result.Flag.SetIsUnsigned()
+	}
+	if utils.IsBinaryMySQLType(mysqlTypeStr) {
+		result.Flag.SetIsBinary()
+	}
+	if result.Value == nil {
+		return result, nil
+	}
+
+	data, ok := value.(string)
+	if !ok {
+		log.Panic("canal-json encoded message should have type in `string`")
+	}
+
+	switch mysqlType {
+	case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if !result.Flag.IsBinary() {
+			break
+		}
+		// when encoding the `JavaSQLTypeBLOB`, use `ISO8859_1` decoder, now reverse it back.
+		encoder := charmap.ISO8859_1.NewEncoder()
+		bytesValue, err := encoder.Bytes([]byte(data))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCanalDecodeFailed, err)
+		}
+		result.Value = bytesValue
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString:
+		if result.Flag.IsBinary() {
+			result.Value = []byte(data)
+		}
+	case mysql.TypeBit:
+		bitValue, err := strconv.ParseUint(data, 10, 64)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCanalDecodeFailed, err)
+		}
+		result.Value = bitValue
+	case mysql.TypeEnum, mysql.TypeSet:
+		// the value is encoded as the index or the bitmap of the elements,
+		// but it's the name if the row is queried from the upstream TiDB.
+		if v, err := strconv.ParseUint(data, 10, 64); err == nil {
+			result.Value = v
+		}
+	}
+	return result, nil
+}
+
+func canalJSONMessage2DDLEvent(msg canalJSONMessageInterface) *commonEvent.DDLEvent {
+	// we lost DDL type from canal json format, only got the DDL SQL.
+	query := msg.getQuery()
+	return &commonEvent.DDLEvent{
+		// we lost the startTs from kafka message
+		FinishedTs: msg.getCommitTs(),
+		SchemaName: *msg.getSchema(),
+		TableName:  *msg.getTable(),
+		Query:      query,
+		// hack the DDL Type to be compatible with MySQL sink's logic
+		Type: byte(getDDLActionType(query)),
+	}
+}
+
+// getDDLActionType return DDL ActionType by the prefix
+// see https://github.com/pingcap/tidb/blob/6dbf2de2f/parser/model/ddl.go#L101-L102
+func getDDLActionType(query string) timodel.ActionType {
+	query = strings.ToLower(query)
+	if strings.HasPrefix(query, "create schema") || strings.HasPrefix(query, "create database") {
+		return timodel.ActionCreateSchema
+	}
+	if strings.HasPrefix(query, "drop schema") || strings.HasPrefix(query, "drop database") {
+		return timodel.ActionDropSchema
+	}
+	return timodel.ActionNone
+}
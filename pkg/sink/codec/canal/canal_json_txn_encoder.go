@@ -0,0 +1,108 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canal
+
+import (
+	"bytes"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/ticdc/pkg/common/columnselector"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/pingcap/ticdc/pkg/sink/codec/encoder"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	ticommon "github.com/pingcap/tiflow/pkg/sink/codec/common"
+)
+
+// JSONTxnEventEncoder encodes txn event in JSON format
+type JSONTxnEventEncoder struct {
+	config *newcommon.Config
+
+	// the symbol separating two lines
+	terminator []byte
+	valueBuf   *bytes.Buffer
+	batchSize  int
+	callback   func()
+
+	// Store some fields of the txn event.
+	txnCommitTs uint64
+	txnSchema   *string
+	txnTable    *string
+}
+
+// NewJSONTxnEventEncoder creates a new JSONTxnEventEncoder
+func NewJSONTxnEventEncoder(config *newcommon.Config) encoder.TxnEventEncoder {
+	return &JSONTxnEventEncoder{
+		valueBuf:   &bytes.Buffer{},
+		terminator: []byte(config.Terminator),
+		config:     config,
+	}
+}
+
+// AppendTxnEvent appends a txn event to the encoder.
+func (j *JSONTxnEventEncoder) AppendTxnEvent(event *commonEvent.DMLEvent) error {
+	for {
+		row, ok := event.GetNextRow()
+		if !ok {
+			break
+		}
+		value, err := newJSONMessageForDML(&commonEvent.RowEvent{
+			TableInfo:      event.TableInfo,
+			CommitTs:       event.CommitTs,
+			Event:          row,
+			ColumnSelector: columnselector.NewDefaultColumnSelector(),
+		}, j.config, false, "")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		length := len(value) + len(j.terminator)
+		if length > j.config.MaxMessageBytes {
+			return cerror.ErrMessageTooLarge.GenWithStackByArgs()
+		}
+		j.valueBuf.Write(value)
+		j.valueBuf.Write(j.terminator)
+		j.batchSize++
+	}
+	j.callback = event.PostFlush
+	j.txnCommitTs = event.CommitTs
+	j.txnSchema = event.TableInfo.GetSchemaNamePtr()
+	j.txnTable = event.TableInfo.GetTableNamePtr()
+	return nil
+}
+
+// Build builds a message from the encoder and resets the encoder.
+func (j *JSONTxnEventEncoder) Build() []*ticommon.Message {
+	if j.batchSize == 0 {
+		return nil
+	}
+
+	ret := ticommon.NewMsg(config.ProtocolCanalJSON, nil,
+		j.valueBuf.Bytes(), j.txnCommitTs, model.MessageTypeRow, j.txnSchema, j.txnTable)
+	ret.SetRowsCount(j.batchSize)
+	ret.Callback = j.callback
+	if j.valueBuf.Cap() > encoder.MemBufShrinkThreshold {
+		j.valueBuf = &bytes.Buffer{}
+	} else {
+		j.valueBuf.Reset()
+	}
+	j.callback = nil
+	j.batchSize = 0
+	j.txnCommitTs = 0
+	j.txnSchema = nil
+	j.txnTable = nil
+
+	return []*ticommon.Message{ret}
+}
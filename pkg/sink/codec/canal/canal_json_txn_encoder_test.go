@@ -0,0 +1,84 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canal
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	pevent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONTxnEventEncoder(t *testing.T) {
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32))")
+	require.NotNil(t, job)
+
+	protocolConfig := newcommon.NewConfig(config.ProtocolCanalJSON)
+	protocolConfig.Terminator = "\n"
+	encoder := NewJSONTxnEventEncoder(protocolConfig)
+	require.Nil(t, encoder.Build())
+
+	dmlEvent := helper.DML2Event("test", "t",
+		"insert into t values (1, 'a')",
+		"insert into t values (2, null)")
+	var called bool
+	dmlEvent.PostTxnFlushed = []func(){func() { called = true }}
+	require.NoError(t, encoder.AppendTxnEvent(dmlEvent))
+
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+	require.Equal(t, 2, messages[0].GetRowsCount())
+	require.Equal(t, dmlEvent.CommitTs, messages[0].Ts)
+	require.Equal(t, "test", *messages[0].Schema)
+	require.Equal(t, "t", *messages[0].Table)
+
+	// each row is encoded as a canal-json message ended with the terminator
+	lines := bytes.Split(bytes.TrimSuffix(messages[0].Value, []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 2)
+	expected := []map[string]interface{}{
+		{"id": "1", "name": "a"},
+		{"id": "2", "name": nil},
+	}
+	for i, line := range lines {
+		msg := &JSONMessage{}
+		require.NoError(t, json.Unmarshal(line, msg))
+		require.Equal(t, "test", msg.Schema)
+		require.Equal(t, "t", msg.Table)
+		require.Equal(t, "INSERT", msg.EventType)
+		require.False(t, msg.IsDDL)
+		require.Equal(t, []string{"id"}, msg.PKNames)
+		require.Equal(t, expected[i:i+1], msg.Data)
+	}
+
+	messages[0].Callback()
+	require.True(t, called)
+	// the encoder is reset after build
+	require.Nil(t, encoder.Build())
+
+	// the message is too large
+	protocolConfig.MaxMessageBytes = 10
+	encoder = NewJSONTxnEventEncoder(protocolConfig)
+	dmlEvent = helper.DML2Event("test", "t", "insert into t values (3, 'c')")
+	err := encoder.AppendTxnEvent(dmlEvent)
+	require.True(t, cerror.ErrMessageTooLarge.Equal(err))
+}
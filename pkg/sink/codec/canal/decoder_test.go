@@ -0,0 +1,101 @@
+package canal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/ticdc/pkg/common"
+	pevent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	codecdecoder "github.com/pingcap/ticdc/pkg/sink/codec/decoder"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeEvents(t *testing.T) {
+	ctx := context.Background()
+	codecConfig := newcommon.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.EnableTiDBExtension = true
+	enc, err := NewJSONRowEventEncoder(ctx, codecConfig)
+	require.NoError(t, err)
+
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+
+	job := helper.DDL2Job(`create table test.t(a int primary key, b varchar(32), c double,
+		d datetime, e decimal(10, 2), f enum('x', 'y'), g blob, h int unsigned)`)
+	tableInfo := helper.GetTableInfo(job)
+	ddlEvent := &pevent.DDLEvent{
+		Type:       byte(job.Type),
+		Query:      job.Query,
+		SchemaName: job.SchemaName,
+		TableName:  job.TableName,
+		TableInfo:  tableInfo,
+		FinishedTs: job.BinlogInfo.FinishedTS,
+	}
+	ddlMessage, err := enc.EncodeDDLEvent(ddlEvent)
+	require.NoError(t, err)
+	checkpointMessage, err := enc.EncodeCheckpointEvent(ddlEvent.FinishedTs + 1)
+	require.NoError(t, err)
+
+	dmlEvent := helper.DML2Event("test", "t",
+		`insert into test.t values (1, 'hello', 3.14, '2024-01-01 10:00:00', 12.34, 'y', x'00ff', 4294967295)`)
+	require.NotNil(t, dmlEvent)
+	insertRow, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+	err = enc.AppendRowChangedEvent(ctx, "", &pevent.RowEvent{
+		TableInfo:      tableInfo,
+		CommitTs:       dmlEvent.CommitTs,
+		Event:          insertRow,
+		ColumnSelector: common.NewDefaultColumnSelector(),
+		Callback:       func() {},
+	})
+	require.NoError(t, err)
+	messages := enc.Build()
+	require.Len(t, messages, 1)
+
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, decoder.AddKeyValue(ddlMessage.Key, ddlMessage.Value))
+	tp, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeDDL, tp)
+	ddl, err := decoder.NextDDLEvent()
+	require.NoError(t, err)
+	require.Equal(t, job.Query, ddl.Query)
+	require.Equal(t, "test", ddl.SchemaName)
+	require.Equal(t, "t", ddl.TableName)
+	require.Equal(t, ddlEvent.FinishedTs, ddl.FinishedTs)
+
+	require.NoError(t, decoder.AddKeyValue(checkpointMessage.Key, checkpointMessage.Value))
+	tp, hasNext, err = decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeResolved, tp)
+	ts, err := decoder.NextResolvedEvent()
+	require.NoError(t, err)
+	require.Equal(t, ddlEvent.FinishedTs+1, ts)
+
+	require.NoError(t, decoder.AddKeyValue(messages[0].Key, messages[0].Value))
+	tp, hasNext, err = decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	event, err := decoder.NextDMLEvent()
+	require.NoError(t, err)
+	require.Equal(t, dmlEvent.CommitTs, event.CommitTs)
+	require.Equal(t, []string{"a"}, event.TableInfo.GetPrimaryKeyColumnNames())
+
+	row, ok := event.GetNextRow()
+	require.True(t, ok)
+	require.Equal(t, pevent.RowTypeInsert, row.RowType)
+	codecdecoder.RequireRowEqual(t, tableInfo, insertRow.Row, event.TableInfo, row.Row)
+
+	_, hasNext, err = decoder.HasNext()
+	require.NoError(t, err)
+	require.False(t, hasNext)
+}
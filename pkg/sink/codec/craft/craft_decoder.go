@@ -16,6 +16,7 @@ package craft
 import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
 	"github.com/pingcap/ticdc/pkg/sink/codec/decoder"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -52,8 +53,8 @@ func (b *batchDecoder) NextResolvedEvent() (uint64, error) {
 	return ts, nil
 }
 
-// NextRowChangedEvent implements the RowEventDecoder interface
-func (b *batchDecoder) NextRowChangedEvent() (*common.RowChangedEvent, error) {
+// NextDMLEvent implements the RowEventDecoder interface
+func (b *batchDecoder) NextDMLEvent() (*commonEvent.DMLEvent, error) {
 	ty, hasNext, err := b.HasNext()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -65,7 +66,7 @@ func (b *batchDecoder) NextRowChangedEvent() (*common.RowChangedEvent, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	ev := &common.RowChangedEvent{}
+	ev := &commonEvent.RowChangedEvent{}
 	var cols, preCols []*common.Column
 	if oldValue != nil {
 		if preCols, err = oldValue.ToModel(); err != nil {
@@ -79,10 +80,10 @@ func (b *batchDecoder) NextRowChangedEvent() (*common.RowChangedEvent, error) {
 	}
 	ev.CommitTs = b.headers.GetTs(b.index)
 	if len(preCols) > 0 {
-		indexColumns := common.GetHandleAndUniqueIndexOffsets4Test(preCols)
+		indexColumns := commonEvent.GetHandleAndUniqueIndexOffsets4Test(preCols)
 		ev.TableInfo = common.BuildTableInfo(b.headers.GetSchema(b.index), b.headers.GetTable(b.index), preCols, indexColumns)
 	} else {
-		indexColumns := common.GetHandleAndUniqueIndexOffsets4Test(cols)
+		indexColumns := commonEvent.GetHandleAndUniqueIndexOffsets4Test(cols)
 		ev.TableInfo = common.BuildTableInfo(b.headers.GetSchema(b.index), b.headers.GetTable(b.index), cols, indexColumns)
 	}
 	if len(preCols) > 0 {
@@ -97,11 +98,11 @@ func (b *batchDecoder) NextRowChangedEvent() (*common.RowChangedEvent, error) {
 		ev.TableInfo.TableName.IsPartition = true
 	}
 	b.index++
-	return ev, nil
+	return decoder.NewDMLEvent(ev)
 }
 
 // NextDDLEvent implements the RowEventDecoder interface
-func (b *batchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
+func (b *batchDecoder) NextDDLEvent() (*commonEvent.DDLEvent, error) {
 	ty, hasNext, err := b.HasNext()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -113,16 +114,12 @@ func (b *batchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	event := &model.DDLEvent{
-		CommitTs: b.headers.GetTs(b.index),
-		Query:    query,
-		Type:     ddlType,
-		TableInfo: &model.TableInfo{
-			TableName: model.TableName{
-				Schema: b.headers.GetSchema(b.index),
-				Table:  b.headers.GetTable(b.index),
-			},
-		},
+	event := &commonEvent.DDLEvent{
+		FinishedTs: b.headers.GetTs(b.index),
+		Query:      query,
+		Type:       byte(ddlType),
+		SchemaName: b.headers.GetSchema(b.index),
+		TableName:  b.headers.GetTable(b.index),
 	}
 	b.index++
 	return event, nil
@@ -0,0 +1,122 @@
+package craft
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/ticdc/pkg/common"
+	pevent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	codecdecoder "github.com/pingcap/ticdc/pkg/sink/codec/decoder"
+	timodel "github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeRowChangedEvents(t *testing.T) {
+	ctx := context.Background()
+	codecConfig := newcommon.NewConfig(config.ProtocolCraft)
+	encoder := NewBatchEncoder(codecConfig)
+
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+
+	job := helper.DDL2Job(`create table test.t(a int primary key, b varchar(32), c double,
+		d datetime, e decimal(10, 2), f enum('x', 'y'), g blob)`)
+	tableInfo := helper.GetTableInfo(job)
+	dmlEvent := helper.DML2Event("test", "t",
+		`insert into test.t values (1, 'hello', 3.14, '2024-01-01 10:00:00', 12.34, 'y', x'0102')`)
+	require.NotNil(t, dmlEvent)
+	insertRow, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+	deleteRow := pevent.RowChange{PreRow: insertRow.Row, RowType: pevent.RowTypeDelete}
+
+	for _, row := range []pevent.RowChange{insertRow, deleteRow} {
+		err := encoder.AppendRowChangedEvent(ctx, "", &pevent.RowEvent{
+			TableInfo:      tableInfo,
+			CommitTs:       dmlEvent.CommitTs,
+			Event:          row,
+			ColumnSelector: common.NewDefaultColumnSelector(),
+			Callback:       func() {},
+		})
+		require.NoError(t, err)
+	}
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+
+	decoder := NewBatchDecoderWithAllocator(NewSliceAllocator(64))
+	require.NoError(t, decoder.AddKeyValue(messages[0].Key, messages[0].Value))
+	var events []*pevent.DMLEvent
+	for {
+		tp, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		if !hasNext {
+			break
+		}
+		require.Equal(t, model.MessageTypeRow, tp)
+		event, err := decoder.NextDMLEvent()
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+	require.Len(t, events, 2)
+
+	expectedTypes := []pevent.RowType{pevent.RowTypeInsert, pevent.RowTypeDelete}
+	for i, event := range events {
+		require.Equal(t, dmlEvent.CommitTs, event.CommitTs)
+		require.Equal(t, "test", event.TableInfo.GetSchemaName())
+		require.Equal(t, "t", event.TableInfo.GetTableName())
+		row, ok := event.GetNextRow()
+		require.True(t, ok)
+		require.Equal(t, expectedTypes[i], row.RowType)
+		actual := row.Row
+		if row.RowType == pevent.RowTypeDelete {
+			actual = row.PreRow
+		}
+		codecdecoder.RequireRowEqual(t, tableInfo, insertRow.Row, event.TableInfo, actual)
+	}
+}
+
+func TestDecodeDDLAndResolvedEvents(t *testing.T) {
+	codecConfig := newcommon.NewConfig(config.ProtocolCraft)
+	encoder := NewBatchEncoder(codecConfig)
+
+	ddl := &pevent.DDLEvent{
+		Type:       byte(timodel.ActionCreateTable),
+		SchemaName: "test",
+		TableName:  "t",
+		Query:      "create table test.t(a int primary key)",
+		FinishedTs: 100,
+	}
+	message, err := encoder.EncodeDDLEvent(ddl)
+	require.NoError(t, err)
+
+	decoder := NewBatchDecoderWithAllocator(NewSliceAllocator(64))
+	require.NoError(t, decoder.AddKeyValue(message.Key, message.Value))
+	tp, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeDDL, tp)
+	decoded, err := decoder.NextDDLEvent()
+	require.NoError(t, err)
+	require.Equal(t, ddl.Type, decoded.Type)
+	require.Equal(t, ddl.SchemaName, decoded.SchemaName)
+	require.Equal(t, ddl.TableName, decoded.TableName)
+	require.Equal(t, ddl.Query, decoded.Query)
+	require.Equal(t, ddl.FinishedTs, decoded.FinishedTs)
+
+	message, err = encoder.EncodeCheckpointEvent(200)
+	require.NoError(t, err)
+	require.NoError(t, decoder.AddKeyValue(message.Key, message.Value))
+	tp, hasNext, err = decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeResolved, tp)
+	ts, err := decoder.NextResolvedEvent()
+	require.NoError(t, err)
+	require.Equal(t, uint64(200), ts)
+	_, hasNext, err = decoder.HasNext()
+	require.NoError(t, err)
+	require.False(t, hasNext)
+}
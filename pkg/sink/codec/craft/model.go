@@ -16,6 +16,7 @@ package craft
 import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
@@ -408,7 +409,7 @@ func newColumnGroup(allocator *SliceAllocator, ty byte, columns []*common.Column
 // Row changed message is basically an array of column groups
 type rowChangedEvent = []*columnGroup
 
-func newRowChangedMessage(allocator *SliceAllocator, ev *common.RowChangedEvent, onlyHandleKeyColumns bool) (int, rowChangedEvent) {
+func newRowChangedMessage(allocator *SliceAllocator, ev *commonEvent.RowChangedEvent, onlyHandleKeyColumns bool) (int, rowChangedEvent) {
 	numGroups := 0
 	if ev.PreColumns != nil {
 		numGroups++
@@ -467,7 +468,7 @@ func (b *RowChangedEventBuffer) Encode() []byte {
 }
 
 // AppendRowChangedEvent append a new event to buffer
-func (b *RowChangedEventBuffer) AppendRowChangedEvent(ev *common.RowChangedEvent, onlyHandleKeyColumns bool) (rows, size int) {
+func (b *RowChangedEventBuffer) AppendRowChangedEvent(ev *commonEvent.RowChangedEvent, onlyHandleKeyColumns bool) (rows, size int) {
 	var partition int64 = -1
 	if ev.TableInfo.IsPartitionTable() {
 		partition = ev.GetTableID()
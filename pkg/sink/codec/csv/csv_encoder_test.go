@@ -0,0 +1,83 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatWithQuotesAndEscapes(t *testing.T) {
+	csvConfig := &common.Config{
+		Delimiter:  ",",
+		Quote:      "\"",
+		Terminator: "\n",
+	}
+	msg := &csvMessage{config: csvConfig}
+	builder := &strings.Builder{}
+	msg.formatWithQuotes("a\"b", builder)
+	require.Equal(t, "\"a\"\"b\"", builder.String())
+
+	csvConfig.Delimiter = "|@|"
+	csvConfig.Quote = ""
+	builder.Reset()
+	msg.formatWithEscapes("a|@|b\nc\\d", builder)
+	require.Equal(t, "a\\|\\@\\|b\\nc\\\\d", builder.String())
+}
+
+func TestCSVBatchEncoder(t *testing.T) {
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32), data varbinary(16))")
+	require.NotNil(t, job)
+
+	csvConfig := &common.Config{
+		Protocol:             config.ProtocolCsv,
+		Delimiter:            ",",
+		Quote:                "\"",
+		Terminator:           "\n",
+		NullString:           "\\N",
+		IncludeCommitTs:      true,
+		BinaryEncodingMethod: config.BinaryEncodingBase64,
+	}
+	encoder := NewTxnEventEncoder(csvConfig)
+	require.Nil(t, encoder.Build())
+
+	dmlEvent := helper.DML2Event("test", "t",
+		"insert into t values (1, 'a', 'abc')",
+		"insert into t values (2, null, null)")
+	var called bool
+	dmlEvent.PostTxnFlushed = []func(){func() { called = true }}
+	require.NoError(t, encoder.AppendTxnEvent(dmlEvent))
+
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+	require.Equal(t, 2, messages[0].GetRowsCount())
+	expected := fmt.Sprintf("\"I\",\"t\",\"test\",%d,1,\"a\",\"YWJj\"\n"+
+		"\"I\",\"t\",\"test\",%d,2,\\N,\\N\n", dmlEvent.CommitTs, dmlEvent.CommitTs)
+	require.Equal(t, expected, string(messages[0].Value))
+
+	messages[0].Callback()
+	require.True(t, called)
+	// the encoder is reset after build
+	require.Nil(t, encoder.Build())
+}
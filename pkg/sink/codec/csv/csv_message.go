@@ -0,0 +1,331 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	commonType "github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/sink/codec/common"
+	timodel "github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// operation specifies the operation type
+type operation int
+
+// enum types of operation
+const (
+	operationInsert operation = iota
+	operationDelete
+	operationUpdate
+)
+
+func (o operation) String() string {
+	switch o {
+	case operationInsert:
+		return "I"
+	case operationDelete:
+		return "D"
+	case operationUpdate:
+		return "U"
+	default:
+		return "unknown"
+	}
+}
+
+type csvMessage struct {
+	// config hold the codec configuration items.
+	config *common.Config
+	// opType denotes the specific operation type.
+	opType     operation
+	tableName  string
+	schemaName string
+	commitTs   uint64
+	columns    []any
+	preColumns []any
+	// newRecord indicates whether we encounter a new record.
+	newRecord bool
+	// handleKey is the string representation of the handle key columns.
+	handleKey string
+}
+
+// encode returns a byte slice composed of the columns as follows:
+// Col1: The operation-type indicator: I, D, U.
+// Col2: Table name, the name of the source table.
+// Col3: Schema name, the name of the source schema.
+// Col4: Commit TS, the commit-ts of the source txn (optional).
+// Col5-n: one or more columns that represent the data to be changed.
+func (c *csvMessage) encode() []byte {
+	strBuilder := new(strings.Builder)
+	if c.opType == operationUpdate && c.config.OutputOldValue && len(c.preColumns) != 0 {
+		// Encode the old value first as a dedicated row.
+		c.encodeMeta("D", strBuilder)
+		c.encodeColumns(c.preColumns, strBuilder)
+
+		// Encode the after value as a dedicated row.
+		c.newRecord = true // reset newRecord to true, so that the first column will not start with delimiter.
+		c.encodeMeta("I", strBuilder)
+		c.encodeColumns(c.columns, strBuilder)
+	} else {
+		c.encodeMeta(c.opType.String(), strBuilder)
+		c.encodeColumns(c.columns, strBuilder)
+	}
+	return []byte(strBuilder.String())
+}
+
+func (c *csvMessage) encodeMeta(opType string, b *strings.Builder) {
+	c.formatValue(opType, b)
+	c.formatValue(c.tableName, b)
+	c.formatValue(c.schemaName, b)
+	if c.config.IncludeCommitTs {
+		c.formatValue(c.commitTs, b)
+	}
+	if c.config.OutputOldValue {
+		// When c.config.OutputOldValue, we need an extra column "is-updated"
+		// to indicate whether the row is updated or just original insert/delete
+		if c.opType == operationUpdate {
+			c.formatValue(true, b)
+		} else {
+			c.formatValue(false, b)
+		}
+	}
+	if c.config.OutputHandleKey {
+		c.formatValue(c.handleKey, b)
+	}
+}
+
+func (c *csvMessage) encodeColumns(columns []any, b *strings.Builder) {
+	for _, col := range columns {
+		c.formatValue(col, b)
+	}
+	b.WriteString(c.config.Terminator)
+}
+
+// as stated in https://datatracker.ietf.org/doc/html/rfc4180,
+// if double-quotes are used to enclose fields, then a double-quote
+// appearing inside a field must be escaped by preceding it with
+// another double quote.
+func (c *csvMessage) formatWithQuotes(value string, strBuilder *strings.Builder) {
+	quote := c.config.Quote
+
+	strBuilder.WriteString(quote)
+	// replace any quote in csv column with two quotes.
+	strBuilder.WriteString(strings.ReplaceAll(value, quote, quote+quote))
+	strBuilder.WriteString(quote)
+}
+
+// formatWithEscapes escapes the csv column if necessary.
+func (c *csvMessage) formatWithEscapes(value string, strBuilder *strings.Builder) {
+	lastPos := 0
+	delimiter := c.config.Delimiter
+
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		isDelimiterStart := strings.HasPrefix(value[i:], delimiter)
+		// if '\r', '\n', '\' or the delimiter (may have multiple characters) are contained in
+		// csv column, we should escape these characters.
+		if ch == config.CR || ch == config.LF || ch == config.Backslash || isDelimiterStart {
+			// write out characters up until this position.
+			strBuilder.WriteString(value[lastPos:i])
+			switch ch {
+			case config.LF:
+				ch = 'n'
+			case config.CR:
+				ch = 'r'
+			}
+			strBuilder.WriteRune(config.Backslash)
+			strBuilder.WriteRune(rune(ch))
+
+			// escape each characters in delimiter.
+			if isDelimiterStart {
+				for k := 1; k < len(c.config.Delimiter); k++ {
+					strBuilder.WriteRune(config.Backslash)
+					strBuilder.WriteRune(rune(delimiter[k]))
+				}
+				lastPos = i + len(delimiter)
+			} else {
+				lastPos = i + 1
+			}
+		}
+	}
+	strBuilder.WriteString(value[lastPos:])
+}
+
+// formatValue formats the csv column and appends it to a string builder.
+func (c *csvMessage) formatValue(value any, strBuilder *strings.Builder) {
+	defer func() {
+		// reset newRecord to false after handing the first csv column
+		c.newRecord = false
+	}()
+
+	if !c.newRecord {
+		strBuilder.WriteString(c.config.Delimiter)
+	}
+
+	if value == nil {
+		strBuilder.WriteString(c.config.NullString)
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		// if quote is configured, format the csv column with quotes,
+		// otherwise escape this csv column.
+		if len(c.config.Quote) != 0 {
+			c.formatWithQuotes(v, strBuilder)
+		} else {
+			c.formatWithEscapes(v, strBuilder)
+		}
+	default:
+		strBuilder.WriteString(fmt.Sprintf("%v", v))
+	}
+}
+
+// fromColValToCsvVal converts the column value in the row from TiDB type to csv type.
+func fromColValToCsvVal(
+	csvConfig *common.Config,
+	row *chunk.Row,
+	idx int,
+	colInfo *timodel.ColumnInfo,
+	flag *commonType.ColumnFlagType,
+) (any, error) {
+	if row.IsNull(idx) {
+		return nil, nil
+	}
+
+	switch colInfo.GetType() {
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeTinyBlob,
+		mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		v := row.GetBytes(idx)
+		if flag.IsBinary() {
+			switch csvConfig.BinaryEncodingMethod {
+			case config.BinaryEncodingBase64:
+				return base64.StdEncoding.EncodeToString(v), nil
+			case config.BinaryEncodingHex:
+				return hex.EncodeToString(v), nil
+			default:
+				return nil, cerror.WrapError(cerror.ErrCSVEncodeFailed,
+					errors.Errorf("unsupported binary encoding method %s",
+						csvConfig.BinaryEncodingMethod))
+			}
+		}
+		return string(v), nil
+	case mysql.TypeEnum:
+		enumVar, err := types.ParseEnumValue(colInfo.GetElems(), row.GetEnum(idx).Value)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCSVEncodeFailed, err)
+		}
+		return enumVar.Name, nil
+	case mysql.TypeSet:
+		setVar, err := types.ParseSetValue(colInfo.GetElems(), row.GetEnum(idx).Value)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCSVEncodeFailed, err)
+		}
+		return setVar.Name, nil
+	default:
+		return commonType.FormatColVal(row, colInfo, idx)
+	}
+}
+
+// rowChangedEvent2CSVMsg converts a RowEvent to a csv record.
+func rowChangedEvent2CSVMsg(csvConfig *common.Config, e *commonEvent.RowEvent) (*csvMessage, error) {
+	var err error
+
+	csvMsg := &csvMessage{
+		config:     csvConfig,
+		tableName:  e.TableInfo.GetTableName(),
+		schemaName: e.TableInfo.GetSchemaName(),
+		commitTs:   e.CommitTs,
+		newRecord:  true,
+	}
+
+	if e.IsDelete() {
+		csvMsg.opType = operationDelete
+		csvMsg.columns, err = rowChangeColumns2CSVColumns(csvConfig, e.GetPreRows(), e.TableInfo)
+		if err != nil {
+			return nil, err
+		}
+		if csvConfig.OutputHandleKey {
+			csvMsg.handleKey, err = formatHandleKey(csvConfig, e.GetPreRows(), e.TableInfo)
+		}
+	} else {
+		if e.IsInsert() {
+			// This is a insert operation.
+			csvMsg.opType = operationInsert
+		} else {
+			// This is a update operation.
+			csvMsg.opType = operationUpdate
+			if csvConfig.OutputOldValue {
+				csvMsg.preColumns, err = rowChangeColumns2CSVColumns(csvConfig, e.GetPreRows(), e.TableInfo)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		csvMsg.columns, err = rowChangeColumns2CSVColumns(csvConfig, e.GetRows(), e.TableInfo)
+		if err != nil {
+			return nil, err
+		}
+		if csvConfig.OutputHandleKey {
+			csvMsg.handleKey, err = formatHandleKey(csvConfig, e.GetRows(), e.TableInfo)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return csvMsg, nil
+}
+
+func rowChangeColumns2CSVColumns(csvConfig *common.Config, row *chunk.Row, tableInfo *commonType.TableInfo) ([]any, error) {
+	csvColumns := make([]any, 0, len(tableInfo.Columns))
+	for idx, col := range tableInfo.Columns {
+		if !commonType.IsColCDCVisible(col) {
+			continue
+		}
+		converted, err := fromColValToCsvVal(csvConfig, row, idx, col, tableInfo.ColumnsFlag[col.ID])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		csvColumns = append(csvColumns, converted)
+	}
+	return csvColumns, nil
+}
+
+// formatHandleKey formats the values of the handle key columns as `{v1, v2, ...}`.
+func formatHandleKey(csvConfig *common.Config, row *chunk.Row, tableInfo *commonType.TableInfo) (string, error) {
+	values := make([]string, 0, len(tableInfo.HandleColID))
+	for _, colID := range tableInfo.HandleColID {
+		idx, ok := tableInfo.ColumnsOffset[colID]
+		if !ok {
+			continue
+		}
+		colInfo := tableInfo.Columns[idx]
+		converted, err := fromColValToCsvVal(csvConfig, row, idx, colInfo, tableInfo.ColumnsFlag[colID])
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		values = append(values, fmt.Sprintf("%v", converted))
+	}
+	return "{" + strings.Join(values, ", ") + "}", nil
+}
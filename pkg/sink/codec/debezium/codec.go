@@ -24,18 +24,19 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/hack"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
-	ticommon "github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
 type dbzCodec struct {
-	config    *ticommon.Config
+	config    *newcommon.Config
 	clusterID string
 	nowFunc   func() time.Time
 }
@@ -50,6 +51,9 @@ func (c *dbzCodec) writeDebeziumFieldValues(
 	colInfos := tableInfo.GetColInfosForRowChangedEvent()
 	writer.WriteObjectField(fieldName, func() {
 		for i, col := range cols {
+			if col == nil {
+				continue
+			}
 			err = c.writeDebeziumFieldValue(writer, col, colInfos[i].Ft)
 			if err != nil {
 				break
@@ -89,7 +93,12 @@ func (c *dbzCodec) writeDebeziumFieldSchema(
 	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeTinyBlob,
 		mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
 		writer.WriteObjectElement(func() {
-			writer.WriteStringField("type", "string")
+			// the value of binary column is encoded as base64 string, see writeBinaryField.
+			if col.Flag.IsBinary() {
+				writer.WriteStringField("type", "bytes")
+			} else {
+				writer.WriteStringField("type", "string")
+			}
 			writer.WriteBoolField("optional", !mysql.HasNotNullFlag(ft.GetFlag()))
 			writer.WriteStringField("field", col.Name)
 		})
@@ -517,7 +526,7 @@ func (c *dbzCodec) writeBinaryField(writer *util.JSONWriter, fieldName string, v
 }
 
 func (c *dbzCodec) EncodeRowChangedEvent(
-	e *common.RowChangedEvent,
+	e *commonEvent.RowChangedEvent,
 	dest io.Writer,
 ) error {
 	jWriter := util.BorrowJSONWriter(dest)
@@ -619,6 +628,9 @@ func (c *dbzCodec) EncodeRowChangedEvent(
 						}
 						colInfos := e.TableInfo.GetColInfosForRowChangedEvent()
 						for i, col := range validCols {
+							if col == nil {
+								continue
+							}
 							c.writeDebeziumFieldSchema(fieldsWriter, col, colInfos[i].Ft)
 						}
 						util.ReturnJSONWriter(fieldsWriter)
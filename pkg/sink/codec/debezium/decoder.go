@@ -0,0 +1,353 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debezium
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/pingcap/ticdc/pkg/sink/codec/decoder"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// message is the decoded value of a debezium message.
+type message struct {
+	Payload struct {
+		Source struct {
+			DB       string `json:"db"`
+			Table    string `json:"table"`
+			CommitTs uint64 `json:"commit_ts"`
+		} `json:"source"`
+		Op     string                 `json:"op"`
+		Before map[string]interface{} `json:"before"`
+		After  map[string]interface{} `json:"after"`
+	} `json:"payload"`
+	Schema *struct {
+		Fields []struct {
+			Field  string        `json:"field"`
+			Fields []fieldSchema `json:"fields"`
+		} `json:"fields"`
+	} `json:"schema"`
+}
+
+// fieldSchema is the schema of a column written by writeDebeziumFieldSchema.
+type fieldSchema struct {
+	Type       string            `json:"type"`
+	Optional   bool              `json:"optional"`
+	Name       string            `json:"name"`
+	Field      string            `json:"field"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// Decoder decodes the debezium messages into the row changed events.
+// Debezium messages carry neither DDL nor watermark, and the primary key
+// of the table is unknown, so it's mainly used to verify the encoded data.
+type Decoder struct {
+	config *newcommon.Config
+
+	value []byte
+	msg   *message
+}
+
+// NewDecoder creates a new debezium Decoder.
+func NewDecoder(config *newcommon.Config) decoder.RowEventDecoder {
+	return &Decoder{config: config}
+}
+
+// AddKeyValue implements the RowEventDecoder interface
+func (d *Decoder) AddKeyValue(_, value []byte) error {
+	if d.value != nil {
+		return cerror.ErrCodecDecode.GenWithStack(
+			"decoder value already exists, not consumed yet")
+	}
+	value, err := newcommon.Decompress(d.config.LargeMessageHandle.LargeMessageHandleCompression, value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	d.value = value
+	return nil
+}
+
+// HasNext implements the RowEventDecoder interface
+func (d *Decoder) HasNext() (model.MessageType, bool, error) {
+	if d.value == nil {
+		return model.MessageTypeUnknown, false, nil
+	}
+	m := new(message)
+	jsonDecoder := json.NewDecoder(bytes.NewReader(d.value))
+	jsonDecoder.UseNumber()
+	d.value = nil
+	if err := jsonDecoder.Decode(m); err != nil {
+		return model.MessageTypeUnknown, false, cerror.WrapError(cerror.ErrDecodeFailed, err)
+	}
+	d.msg = m
+	return model.MessageTypeRow, true, nil
+}
+
+// NextResolvedEvent implements the RowEventDecoder interface
+func (d *Decoder) NextResolvedEvent() (uint64, error) {
+	return 0, cerror.ErrCodecDecode.GenWithStack("debezium protocol does not support resolved event")
+}
+
+// NextDDLEvent implements the RowEventDecoder interface
+func (d *Decoder) NextDDLEvent() (*commonEvent.DDLEvent, error) {
+	return nil, cerror.ErrCodecDecode.GenWithStack("debezium protocol does not support DDL event")
+}
+
+// NextDMLEvent implements the RowEventDecoder interface
+func (d *Decoder) NextDMLEvent() (*commonEvent.DMLEvent, error) {
+	if d.msg == nil {
+		return nil, cerror.ErrCodecDecode.GenWithStack("not found row changed event message")
+	}
+	m := d.msg
+	d.msg = nil
+
+	fields := make(map[string]fieldSchema)
+	if m.Schema != nil {
+		for _, f := range m.Schema.Fields {
+			if f.Field != "after" {
+				continue
+			}
+			for _, col := range f.Fields {
+				fields[col.Field] = col
+			}
+		}
+	}
+
+	event := &commonEvent.RowChangedEvent{
+		CommitTs: m.Payload.Source.CommitTs,
+	}
+	var err error
+	switch m.Payload.Op {
+	case "c":
+		event.Columns, err = d.decodeColumns(m.Payload.After, fields)
+	case "d":
+		event.PreColumns, err = d.decodeColumns(m.Payload.Before, fields)
+	case "u":
+		event.Columns, err = d.decodeColumns(m.Payload.After, fields)
+		if err == nil {
+			event.PreColumns, err = d.decodeColumns(m.Payload.Before, fields)
+		}
+		// the old value is not output by default, take the new value as the old one.
+		if len(event.PreColumns) == 0 {
+			event.PreColumns = event.Columns
+		}
+	default:
+		return nil, cerror.ErrCodecDecode.GenWithStack("unknown debezium operation %s", m.Payload.Op)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := event.Columns
+	if len(columns) == 0 {
+		columns = event.PreColumns
+	}
+	event.TableInfo = common.BuildTableInfo(m.Payload.Source.DB, m.Payload.Source.Table, columns, nil)
+	return decoder.NewDMLEvent(event)
+}
+
+func (d *Decoder) decodeColumns(
+	data map[string]interface{}, fields map[string]fieldSchema,
+) ([]*common.Column, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	result := make([]*common.Column, 0, len(data))
+	for name, value := range data {
+		field, ok := fields[name]
+		if !ok {
+			field = inferFieldSchema(value)
+		}
+		col, err := d.decodeColumn(name, value, field)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCodecDecode, err)
+		}
+		result = append(result, col)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// inferFieldSchema guesses the schema by the value if the schema is disabled.
+func inferFieldSchema(value interface{}) fieldSchema {
+	switch v := value.(type) {
+	case bool:
+		return fieldSchema{Type: "boolean"}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return fieldSchema{Type: "int64"}
+		}
+		return fieldSchema{Type: "double"}
+	default:
+		return fieldSchema{Type: "string"}
+	}
+}
+
+// decodeColumn reverses the value written by writeDebeziumFieldValue.
+func (d *Decoder) decodeColumn(name string, value interface{}, field fieldSchema) (*common.Column, error) {
+	col := &common.Column{Name: name}
+	if field.Optional {
+		col.Flag.SetIsNullable()
+	}
+
+	switch field.Name {
+	case "io.debezium.data.Bits":
+		col.Type = mysql.TypeBit
+	case "io.debezium.data.Enum":
+		col.Type = mysql.TypeEnum
+	case "io.debezium.data.EnumSet":
+		col.Type = mysql.TypeSet
+	case "io.debezium.time.Date":
+		col.Type = mysql.TypeDate
+	case "io.debezium.time.Timestamp", "io.debezium.time.MicroTimestamp":
+		col.Type = mysql.TypeDatetime
+	case "io.debezium.time.ZonedTimestamp":
+		col.Type = mysql.TypeTimestamp
+	case "io.debezium.time.MicroTime":
+		col.Type = mysql.TypeDuration
+	case "io.debezium.data.Json":
+		col.Type = mysql.TypeJSON
+	case "io.debezium.time.Year":
+		col.Type = mysql.TypeYear
+	default:
+		switch field.Type {
+		case "boolean":
+			col.Type = mysql.TypeBit
+		case "bytes":
+			col.Type = mysql.TypeBlob
+			col.Flag.SetIsBinary()
+		case "int16", "int32", "int64":
+			col.Type = mysql.TypeLonglong
+		case "float":
+			col.Type = mysql.TypeFloat
+		case "double":
+			col.Type = mysql.TypeDouble
+		default:
+			col.Type = mysql.TypeVarchar
+		}
+	}
+	if value == nil {
+		return col, nil
+	}
+
+	var err error
+	switch col.Type {
+	case mysql.TypeBit:
+		col.Value, err = decodeBit(value)
+	case mysql.TypeBlob:
+		col.Value, err = base64.StdEncoding.DecodeString(decoder.ToString(value))
+	case mysql.TypeEnum:
+		col.Value = decodeEnum(decoder.ToString(value), field.Parameters["allowed"])
+	case mysql.TypeSet:
+		col.Value = decodeSet(decoder.ToString(value), field.Parameters["allowed"])
+	case mysql.TypeDate:
+		var days int64
+		days, err = toInt64(value)
+		col.Value = time.Unix(days*24*60*60, 0).UTC().Format("2006-01-02")
+	case mysql.TypeDatetime:
+		var v int64
+		v, err = toInt64(value)
+		t := time.UnixMilli(v)
+		if field.Name == "io.debezium.time.MicroTimestamp" {
+			t = time.UnixMicro(v)
+		}
+		col.Value = t.UTC().Format("2006-01-02 15:04:05.999999")
+	case mysql.TypeTimestamp:
+		var t time.Time
+		t, err = time.Parse(time.RFC3339Nano, decoder.ToString(value))
+		col.Value = t.In(d.config.TimeZone).Format("2006-01-02 15:04:05.999999")
+	case mysql.TypeDuration:
+		var v int64
+		v, err = toInt64(value)
+		col.Value = types.Duration{Duration: time.Duration(v) * time.Microsecond, Fsp: types.MaxFsp}.String()
+	default:
+		col.Value = value
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return col, nil
+}
+
+// decodeBit decodes the BIT(1) encoded as boolean, and the BIT(>1) encoded
+// as the little-endian bytes.
+func decodeBit(value interface{}) (uint64, error) {
+	if v, ok := value.(bool); ok {
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(decoder.ToString(value))
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	var buf [8]byte
+	copy(buf[:], data)
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+// decodeEnum returns the index of the element, the index starts from 1,
+// and 0 is returned for the invalid value.
+func decodeEnum(name string, allowed string) uint64 {
+	for i, elem := range strings.Split(allowed, ",") {
+		if elem == name {
+			return uint64(i + 1)
+		}
+	}
+	return 0
+}
+
+// decodeSet returns the bitmap of the elements.
+func decodeSet(names string, allowed string) uint64 {
+	if names == "" {
+		return 0
+	}
+	var result uint64
+	elems := strings.Split(allowed, ",")
+	for _, name := range strings.Split(names, ",") {
+		for i, elem := range elems {
+			if elem == name {
+				result |= 1 << uint(i)
+			}
+		}
+	}
+	return result
+}
+
+func toInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.Int64()
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, errors.Errorf("unexpected value %v of type %T", value, value)
+}
@@ -0,0 +1,64 @@
+package debezium
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/ticdc/pkg/common/columnselector"
+	pevent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	codecdecoder "github.com/pingcap/ticdc/pkg/sink/codec/decoder"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeInsertEvent(t *testing.T) {
+	codecConfig := newcommon.NewConfig(config.ProtocolDebezium)
+	batchEncoder := NewBatchEncoder(codecConfig, "test-cluster")
+
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+
+	job := helper.DDL2Job(`create table test.t(a int primary key, b varchar(32), c double,
+		d datetime, f enum('x', 'y'), g blob, h bit(10))`)
+	tableInfo := helper.GetTableInfo(job)
+	dmlEvent := helper.DML2Event("test", "t",
+		`insert into test.t values (1, 'hello', 3.14, '2024-01-01 10:00:00', 'y', x'00ff', b'1000000001')`)
+	require.NotNil(t, dmlEvent)
+	insertRow, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+
+	err := batchEncoder.AppendRowChangedEvent(context.Background(), "", &pevent.RowEvent{
+		TableInfo:      tableInfo,
+		CommitTs:       dmlEvent.CommitTs,
+		Event:          insertRow,
+		ColumnSelector: columnselector.NewDefaultColumnSelector(),
+		Callback:       func() {},
+	})
+	require.NoError(t, err)
+	messages := batchEncoder.Build()
+	require.Len(t, messages, 1)
+
+	decoder := NewDecoder(codecConfig)
+	require.NoError(t, decoder.AddKeyValue(messages[0].Key, messages[0].Value))
+	tp, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	event, err := decoder.NextDMLEvent()
+	require.NoError(t, err)
+	require.Equal(t, dmlEvent.CommitTs, event.CommitTs)
+	require.Equal(t, "test", event.TableInfo.GetSchemaName())
+	require.Equal(t, "t", event.TableInfo.GetTableName())
+
+	row, ok := event.GetNextRow()
+	require.True(t, ok)
+	require.Equal(t, pevent.RowTypeInsert, row.RowType)
+	codecdecoder.RequireRowEqual(t, tableInfo, insertRow.Row, event.TableInfo, row.Row)
+
+	_, hasNext, err = decoder.HasNext()
+	require.NoError(t, err)
+	require.False(t, hasNext)
+}
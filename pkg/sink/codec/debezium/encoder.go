@@ -19,6 +19,7 @@ import (
 	"time"
 
 	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
 	"github.com/pingcap/ticdc/pkg/sink/codec/encoder"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -30,7 +31,7 @@ import (
 type BatchEncoder struct {
 	messages []*ticommon.Message
 
-	config *ticommon.Config
+	config *newcommon.Config
 	codec  *dbzCodec
 }
 
@@ -44,16 +45,16 @@ func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*ticommon.Message, erro
 func (d *BatchEncoder) AppendRowChangedEvent(
 	_ context.Context,
 	_ string,
-	e *commonEvent.RowChangedEvent,
-	callback func(),
+	event *commonEvent.RowEvent,
 ) error {
+	e := event.ToRowChangedEvent()
 	valueBuf := bytes.Buffer{}
 	err := d.codec.EncodeRowChangedEvent(e, &valueBuf)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	// TODO: Use a streaming compression is better.
-	value, err := ticommon.Compress(
+	value, err := newcommon.Compress(
 		d.config.ChangefeedID,
 		d.config.LargeMessageHandle.LargeMessageHandleCompression,
 		valueBuf.Bytes(),
@@ -69,7 +70,7 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 		Table:    e.TableInfo.GetTableNamePtr(),
 		Type:     model.MessageTypeRow,
 		Protocol: config.ProtocolDebezium,
-		Callback: callback,
+		Callback: event.Callback,
 	}
 	m.IncRowsCount()
 
@@ -98,7 +99,7 @@ func (d *BatchEncoder) Build() []*ticommon.Message {
 func (d *BatchEncoder) Clean() {}
 
 // newBatchEncoder creates a new Debezium BatchEncoder.
-func NewBatchEncoder(c *ticommon.Config, clusterID string) encoder.EventEncoder {
+func NewBatchEncoder(c *newcommon.Config, clusterID string) encoder.EventEncoder {
 	batch := &BatchEncoder{
 		messages: nil,
 		config:   c,
@@ -0,0 +1,68 @@
+package debezium
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pingcap/ticdc/pkg/common/columnselector"
+	pevent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeInsertEvent(t *testing.T) {
+	codecConfig := newcommon.NewConfig(config.ProtocolDebezium)
+	codecConfig.DebeziumDisableSchema = true
+	batchEncoder := NewBatchEncoder(codecConfig, "test-cluster")
+
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+
+	job := helper.DDL2Job(`create table test.t(a int primary key, b varchar(32))`)
+	tableInfo := helper.GetTableInfo(job)
+	dmlEvent := helper.DML2Event("test", "t", `insert into test.t values (1, 'hello')`)
+	require.NotNil(t, dmlEvent)
+	insertRow, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+
+	count := 0
+	err := batchEncoder.AppendRowChangedEvent(context.Background(), "", &pevent.RowEvent{
+		TableInfo:      tableInfo,
+		CommitTs:       dmlEvent.CommitTs,
+		Event:          insertRow,
+		ColumnSelector: columnselector.NewDefaultColumnSelector(),
+		Callback:       func() { count++ },
+	})
+	require.NoError(t, err)
+
+	messages := batchEncoder.Build()
+	require.Len(t, messages, 1)
+	require.Equal(t, 1, messages[0].GetRowsCount())
+	require.Equal(t, "test", *messages[0].Schema)
+	require.Equal(t, "t", *messages[0].Table)
+
+	var value map[string]interface{}
+	require.NoError(t, json.Unmarshal(messages[0].Value, &value))
+	payload := value["payload"].(map[string]interface{})
+	require.Equal(t, "c", payload["op"])
+	require.Nil(t, payload["before"])
+	after := payload["after"].(map[string]interface{})
+	require.Equal(t, float64(1), after["a"])
+	require.Equal(t, "hello", after["b"])
+	source := payload["source"].(map[string]interface{})
+	require.Equal(t, "test-cluster", source["name"])
+	require.Equal(t, "test", source["db"])
+	require.Equal(t, "t", source["table"])
+	require.NotContains(t, value, "schema")
+
+	messages[0].Callback()
+	require.Equal(t, 1, count)
+
+	// DDL events are not supported by debezium yet.
+	message, err := batchEncoder.EncodeDDLEvent(&pevent.DDLEvent{})
+	require.NoError(t, err)
+	require.Nil(t, message)
+}
@@ -14,11 +14,21 @@
 package decoder
 
 import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
-// RowEventDecoder is an abstraction for events decoder
-// this interface is only for testing now
+// RowEventDecoder is an abstraction for events decoder,
+// it decodes the messages encoded by the event encoder of the same protocol.
 type RowEventDecoder interface {
 	// AddKeyValue add the received key and values to the decoder,
 	// should be called before `HasNext`
@@ -32,8 +42,282 @@ type RowEventDecoder interface {
 	HasNext() (model.MessageType, bool, error)
 	// NextResolvedEvent returns the next resolved event if exists
 	NextResolvedEvent() (uint64, error)
-	// NextRowChangedEvent returns the next row changed event if exists
-	// NextRowChangedEvent() (*common.RowChangedEvent, error)
+	// NextDMLEvent returns the next row changed event as a DML event if exists,
+	// the returned event may be nil if the row is cached by the decoder, such as
+	// the simple protocol decoder waiting for the table schema.
+	NextDMLEvent() (*commonEvent.DMLEvent, error)
 	// NextDDLEvent returns the next DDL event if exists
-	NextDDLEvent() (*model.DDLEvent, error)
+	NextDDLEvent() (*commonEvent.DDLEvent, error)
+}
+
+// NewDMLEvent builds a DML event which contains only the given row change.
+// The columns are matched with the table info by name, the columns which are
+// not in the row are filled by null, and the missing pre columns of an update
+// are filled by the new value, since some protocols only carry the updated columns.
+func NewDMLEvent(row *commonEvent.RowChangedEvent) (*commonEvent.DMLEvent, error) {
+	tableID := row.PhysicalTableID
+	if tableID == 0 {
+		tableID = row.TableInfo.TableName.TableID
+	}
+	event := commonEvent.NewDMLEvent(common.DispatcherID{}, tableID, row.StartTs, row.CommitTs, row.TableInfo)
+	switch {
+	case row.IsUpdate():
+		if err := appendRow(event, row.PreColumns, row.Columns); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := appendRow(event, row.Columns, nil); err != nil {
+			return nil, errors.Trace(err)
+		}
+		event.RowTypes = append(event.RowTypes, commonEvent.RowTypeUpdate, commonEvent.RowTypeUpdate)
+	case row.IsDelete():
+		if err := appendRow(event, row.PreColumns, nil); err != nil {
+			return nil, errors.Trace(err)
+		}
+		event.RowTypes = append(event.RowTypes, commonEvent.RowTypeDelete)
+	default:
+		if err := appendRow(event, row.Columns, nil); err != nil {
+			return nil, errors.Trace(err)
+		}
+		event.RowTypes = append(event.RowTypes, commonEvent.RowTypeInsert)
+	}
+	event.Length = 1
+	event.ApproximateSize = int64(row.ApproximateBytes())
+	return event, nil
+}
+
+// appendRow appends the values of the columns to the chunk of the event,
+// the value of a column which is not in columns is taken from fallback.
+func appendRow(event *commonEvent.DMLEvent, columns, fallback []*common.Column) error {
+	tableInfo := event.TableInfo
+	values := make([]interface{}, len(tableInfo.Columns))
+	for _, cols := range [][]*common.Column{fallback, columns} {
+		for _, col := range cols {
+			if col == nil {
+				continue
+			}
+			colID, ok := tableInfo.NameToColID[col.Name]
+			if !ok {
+				return cerror.ErrCodecDecode.GenWithStack("column %s not found in table %s",
+					col.Name, tableInfo.TableName.String())
+			}
+			values[tableInfo.ColumnsOffset[colID]] = col.Value
+		}
+	}
+	for idx, colInfo := range tableInfo.Columns {
+		d, err := newDatum(values[idx], &colInfo.FieldType)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrCodecDecode, err)
+		}
+		event.Rows.AppendDatum(idx, &d)
+	}
+	return nil
+}
+
+// newDatum converts the decoded column value to the datum of the field type,
+// the kind of the datum must match the field type to be appended to the chunk.
+func newDatum(value interface{}, ft *types.FieldType) (types.Datum, error) {
+	if value == nil {
+		return types.Datum{}, nil
+	}
+	switch ft.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+		if mysql.HasUnsignedFlag(ft.GetFlag()) {
+			v, err := toUint64(value)
+			if err != nil {
+				return types.Datum{}, errors.Trace(err)
+			}
+			return types.NewUintDatum(v), nil
+		}
+		v, err := toInt64(value)
+		if err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		return types.NewIntDatum(v), nil
+	case mysql.TypeFloat:
+		v, err := toFloat64(value)
+		if err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		return types.NewFloat32Datum(float32(v)), nil
+	case mysql.TypeDouble:
+		v, err := toFloat64(value)
+		if err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		return types.NewFloat64Datum(v), nil
+	case mysql.TypeNewDecimal:
+		if v, ok := value.(*big.Rat); ok {
+			scale := ft.GetDecimal()
+			if scale < 0 {
+				scale = types.MaxFsp
+			}
+			value = v.FloatString(scale)
+		}
+		dec := new(types.MyDecimal)
+		if err := dec.FromString([]byte(ToString(value))); err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		return types.NewDecimalDatum(dec), nil
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		s := ToString(value)
+		t, err := types.ParseTime(types.DefaultStmtNoWarningContext, s, ft.GetType(), types.GetFsp(s))
+		if err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		return types.NewTimeDatum(t), nil
+	case mysql.TypeDuration:
+		s := ToString(value)
+		d, _, err := types.ParseDuration(types.DefaultStmtNoWarningContext, s, types.GetFsp(s))
+		if err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		return types.NewDurationDatum(d), nil
+	case mysql.TypeJSON:
+		j, err := types.ParseBinaryJSONFromString(ToString(value))
+		if err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		return types.NewJSONDatum(j), nil
+	case mysql.TypeEnum:
+		if s, ok := value.(string); ok {
+			e, err := types.ParseEnum(ft.GetElems(), s, ft.GetCollate())
+			if err != nil {
+				return types.Datum{}, errors.Trace(err)
+			}
+			return types.NewMysqlEnumDatum(e), nil
+		}
+		v, err := toUint64(value)
+		if err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		e, err := types.ParseEnumValue(ft.GetElems(), v)
+		if err != nil {
+			// the elements may be unknown by the decoder, keep the value only.
+			e = types.Enum{Value: v}
+		}
+		return types.NewMysqlEnumDatum(e), nil
+	case mysql.TypeSet:
+		if s, ok := value.(string); ok {
+			set, err := types.ParseSet(ft.GetElems(), s, ft.GetCollate())
+			if err != nil {
+				return types.Datum{}, errors.Trace(err)
+			}
+			return types.NewMysqlSetDatum(set, ft.GetCollate()), nil
+		}
+		v, err := toUint64(value)
+		if err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		set, err := types.ParseSetValue(ft.GetElems(), v)
+		if err != nil {
+			set = types.Set{Value: v}
+		}
+		return types.NewMysqlSetDatum(set, ft.GetCollate()), nil
+	case mysql.TypeBit:
+		if v, ok := value.([]byte); ok {
+			n, err := types.BinaryLiteral(v).ToInt(types.DefaultStmtNoWarningContext)
+			if err != nil {
+				return types.Datum{}, errors.Trace(err)
+			}
+			value = n
+		}
+		v, err := toUint64(value)
+		if err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		return types.NewMysqlBitDatum(types.NewBinaryLiteralFromUint(v, -1)), nil
+	default:
+		switch v := value.(type) {
+		case []byte:
+			return types.NewBytesDatum(v), nil
+		default:
+			return types.NewBytesDatum([]byte(ToString(v))), nil
+		}
+	}
+}
+
+// ToString converts the value decoded from a text message to a string,
+// it returns an empty string if the type of the value is not supported.
+func ToString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case json.Number:
+		return v.String()
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return ""
+}
+
+func toInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int32:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case uint64:
+		return int64(v), nil
+	case float64:
+		return int64(v), nil
+	case float32:
+		return int64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number, string, []byte:
+		return strconv.ParseInt(ToString(v), 10, 64)
+	}
+	return 0, errors.Errorf("unexpected value %v of type %T for int column", value, value)
+}
+
+func toUint64(value interface{}) (uint64, error) {
+	switch v := value.(type) {
+	case uint64:
+		return v, nil
+	case int64:
+		return uint64(v), nil
+	case int32:
+		return uint64(v), nil
+	case int:
+		return uint64(v), nil
+	case float64:
+		return uint64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number, string, []byte:
+		return strconv.ParseUint(ToString(v), 10, 64)
+	}
+	return 0, errors.Errorf("unexpected value %v of type %T for unsigned column", value, value)
+}
+
+func toFloat64(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case json.Number, string, []byte:
+		return strconv.ParseFloat(ToString(v), 64)
+	}
+	return 0, errors.Errorf("unexpected value %v of type %T for float column", value, value)
 }
@@ -0,0 +1,44 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+// RequireRowEqual checks the decoded row has the same column values with the encoded row,
+// the columns of the decoded row are matched by name since the order may be different.
+// It's used by the tests of the protocol packages.
+func RequireRowEqual(
+	t *testing.T,
+	expectedTableInfo *common.TableInfo, expected chunk.Row,
+	actualTableInfo *common.TableInfo, actual chunk.Row,
+) {
+	require.Len(t, actualTableInfo.Columns, len(expectedTableInfo.Columns))
+	for idx, col := range expectedTableInfo.Columns {
+		expectedValue, err := common.ExtractColVal(&expected, col, idx)
+		require.NoError(t, err)
+
+		colID, ok := actualTableInfo.NameToColID[col.Name.O]
+		require.True(t, ok, col.Name.O)
+		offset := actualTableInfo.ColumnsOffset[colID]
+		actualValue, err := common.ExtractColVal(&actual, actualTableInfo.Columns[offset], offset)
+		require.NoError(t, err)
+		require.Equal(t, expectedValue, actualValue, col.Name.O)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/sink/codec/avro"
+	"github.com/pingcap/ticdc/pkg/sink/codec/canal"
+	"github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/pingcap/ticdc/pkg/sink/codec/craft"
+	"github.com/pingcap/ticdc/pkg/sink/codec/debezium"
+	"github.com/pingcap/ticdc/pkg/sink/codec/decoder"
+	"github.com/pingcap/ticdc/pkg/sink/codec/open"
+	"github.com/pingcap/ticdc/pkg/sink/codec/simple"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// NewEventDecoder returns a RowEventDecoder which decodes the messages encoded by
+// the encoder of the same protocol. The topic is used by avro to look up the schema,
+// and the upstream TiDB is used to fetch the whole row of the handle-key-only message.
+func NewEventDecoder(
+	ctx context.Context, cfg *common.Config, topic string, upstreamTiDB *sql.DB,
+) (decoder.RowEventDecoder, error) {
+	switch cfg.Protocol {
+	case config.ProtocolDefault, config.ProtocolOpen:
+		return open.NewBatchDecoder(ctx, cfg, upstreamTiDB)
+	case config.ProtocolAvro:
+		schemaM, err := avro.NewSchemaManager(ctx, cfg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return avro.NewDecoder(cfg, schemaM, topic, upstreamTiDB), nil
+	case config.ProtocolCanalJSON:
+		return canal.NewBatchDecoder(ctx, cfg, upstreamTiDB)
+	case config.ProtocolCraft:
+		return craft.NewBatchDecoderWithAllocator(craft.NewSliceAllocator(64)), nil
+	case config.ProtocolDebezium:
+		return debezium.NewDecoder(cfg), nil
+	case config.ProtocolSimple:
+		return simple.NewDecoder(ctx, cfg, upstreamTiDB)
+	default:
+		return nil, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(cfg.Protocol)
+	}
+}
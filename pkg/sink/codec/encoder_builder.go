@@ -4,10 +4,15 @@ import (
 	"context"
 
 	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/ticdc/pkg/sink/codec/avro"
 	"github.com/pingcap/ticdc/pkg/sink/codec/canal"
 	"github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/pingcap/ticdc/pkg/sink/codec/craft"
+	"github.com/pingcap/ticdc/pkg/sink/codec/csv"
+	"github.com/pingcap/ticdc/pkg/sink/codec/debezium"
 	"github.com/pingcap/ticdc/pkg/sink/codec/encoder"
 	"github.com/pingcap/ticdc/pkg/sink/codec/open"
+	"github.com/pingcap/ticdc/pkg/sink/codec/simple"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
@@ -15,17 +20,29 @@ func NewEventEncoder(ctx context.Context, cfg *common.Config) (encoder.EventEnco
 	switch cfg.Protocol {
 	case config.ProtocolDefault, config.ProtocolOpen:
 		return open.NewBatchEncoder(ctx, cfg)
-	// case config.ProtocolAvro:
-	// 	return avro.NewAvroEncoder(ctx, cfg)
+	case config.ProtocolAvro:
+		return avro.NewAvroEncoder(ctx, cfg)
 	case config.ProtocolCanalJSON:
 		return canal.NewJSONRowEventEncoder(ctx, cfg)
-	// case config.ProtocolCraft:
-	// 	return craft.NewBatchEncoder(cfg), nil
-	// case config.ProtocolDebezium:
-	// 	return debezium.NewBatchEncoder(cfg, config.GetGlobalServerConfig().ClusterID), nil
-	// case config.ProtocolSimple:
-	// 	return simple.NewEncoder(ctx, cfg)
+	case config.ProtocolCraft:
+		return craft.NewBatchEncoder(cfg), nil
+	case config.ProtocolDebezium:
+		return debezium.NewBatchEncoder(cfg, config.GetGlobalServerConfig().ClusterID), nil
+	case config.ProtocolSimple:
+		return simple.NewEncoder(ctx, cfg)
 	default:
 		return nil, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(cfg.Protocol)
 	}
 }
+
+// NewTxnEventEncoder returns an TxnEventEncoder.
+func NewTxnEventEncoder(c *common.Config) (encoder.TxnEventEncoder, error) {
+	switch c.Protocol {
+	case config.ProtocolCsv:
+		return csv.NewTxnEventEncoder(c), nil
+	case config.ProtocolCanalJSON:
+		return canal.NewJSONTxnEventEncoder(c), nil
+	default:
+		return nil, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(c.Protocol)
+	}
+}
@@ -90,7 +90,9 @@ func (c *Column) ToRowChangeColumn(name string) *common.Column {
 	case mysql.TypeFloat:
 		col.Value = float32(col.Value.(float64))
 	case mysql.TypeYear:
-		col.Value = int64(col.Value.(uint64))
+		if v, ok := col.Value.(uint64); ok {
+			col.Value = int64(v)
+		}
 	case mysql.TypeEnum, mysql.TypeSet:
 		val, err := col.Value.(json.Number).Int64()
 		if err != nil {
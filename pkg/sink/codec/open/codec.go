@@ -36,6 +36,8 @@ func encodeRowChangedEvent(e *commonEvent.RowEvent, config *newcommon.Config, la
 		if claimCheckLocationName != "" {
 			keyWriter.WriteBoolField("ohk", false)
 			keyWriter.WriteStringField("ccl", claimCheckLocationName)
+		} else if largeMessageOnlyHandleKeyColumns {
+			keyWriter.WriteBoolField("ohk", true)
 		}
 	})
 	var err error
@@ -140,7 +142,7 @@ func encodeDDLEvent(e *commonEvent.DDLEvent, config *newcommon.Config) ([]byte,
 	keyOutput.Write(key)
 
 	valueOutput := new(bytes.Buffer)
-	valueOutput.Write(versionByte[:])
+	valueOutput.Write(valueLenByte[:])
 	valueOutput.Write(value)
 
 	return keyOutput.Bytes(), valueOutput.Bytes(), nil
@@ -0,0 +1,433 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/pingcap/ticdc/pkg/sink/codec/decoder"
+	"github.com/pingcap/ticdc/pkg/sink/codec/encoder"
+	"github.com/pingcap/ticdc/pkg/sink/codec/internal"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	timodel "github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const claimCheckStorageTimeout = 5 * time.Minute
+
+// messageKey is the key part of an open protocol message.
+type messageKey struct {
+	Ts        uint64            `json:"ts"`
+	Schema    string            `json:"scm,omitempty"`
+	Table     string            `json:"tbl,omitempty"`
+	Partition *int64            `json:"ptn,omitempty"`
+	Type      model.MessageType `json:"t"`
+	// OnlyHandleKey is true if only the handle key columns are encoded in the value.
+	OnlyHandleKey bool `json:"ohk,omitempty"`
+	// ClaimCheckLocation is the location of the whole message in the external storage.
+	ClaimCheckLocation string `json:"ccl,omitempty"`
+}
+
+// messageRow is the value part of an open protocol row changed message.
+type messageRow struct {
+	Update     map[string]internal.Column `json:"u,omitempty"`
+	PreColumns map[string]internal.Column `json:"p,omitempty"`
+	Delete     map[string]internal.Column `json:"d,omitempty"`
+}
+
+func (m *messageRow) decode(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(m); err != nil {
+		return cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	for _, columns := range []map[string]internal.Column{m.Update, m.PreColumns, m.Delete} {
+		for name, col := range columns {
+			columns[name] = internal.FormatColumn(col)
+		}
+	}
+	return nil
+}
+
+// messageDDL is the value part of an open protocol DDL message.
+type messageDDL struct {
+	Query string             `json:"q"`
+	Type  timodel.ActionType `json:"t"`
+}
+
+// BatchDecoder decodes the byte of a batch into the original messages.
+type BatchDecoder struct {
+	keyBytes   []byte
+	valueBytes []byte
+
+	nextKey   *messageKey
+	nextEvent *commonEvent.RowChangedEvent
+
+	storage storage.ExternalStorage
+
+	config *newcommon.Config
+
+	upstreamTiDB *sql.DB
+}
+
+// NewBatchDecoder creates a new BatchDecoder.
+func NewBatchDecoder(ctx context.Context, config *newcommon.Config, db *sql.DB) (decoder.RowEventDecoder, error) {
+	var (
+		externalStorage storage.ExternalStorage
+		err             error
+	)
+	if config.LargeMessageHandle.EnableClaimCheck() {
+		storageURI := config.LargeMessageHandle.ClaimCheckStorageURI
+		externalStorage, err = util.GetExternalStorageWithTimeout(ctx, storageURI, claimCheckStorageTimeout)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+	}
+
+	if config.LargeMessageHandle.HandleKeyOnly() && db == nil {
+		return nil, cerror.ErrCodecDecode.
+			GenWithStack("handle-key-only is enabled, but upstream TiDB is not provided")
+	}
+
+	return &BatchDecoder{
+		config:       config,
+		storage:      externalStorage,
+		upstreamTiDB: db,
+	}, nil
+}
+
+// AddKeyValue implements the RowEventDecoder interface
+func (b *BatchDecoder) AddKeyValue(key, value []byte) error {
+	if len(b.keyBytes) != 0 || len(b.valueBytes) != 0 {
+		return cerror.ErrOpenProtocolCodecInvalidData.
+			GenWithStack("decoder key and value not nil")
+	}
+	if len(key) < 8 {
+		return cerror.ErrOpenProtocolCodecInvalidData.
+			GenWithStack("key is too short, length: %d", len(key))
+	}
+	version := binary.BigEndian.Uint64(key[:8])
+	if version != encoder.BatchVersion1 {
+		return cerror.ErrOpenProtocolCodecInvalidData.
+			GenWithStack("unexpected key format version")
+	}
+
+	b.keyBytes = key[8:]
+	b.valueBytes = value
+	return nil
+}
+
+func (b *BatchDecoder) hasNext() bool {
+	keyLen := len(b.keyBytes)
+	valueLen := len(b.valueBytes)
+
+	if keyLen > 0 && valueLen > 0 {
+		return true
+	}
+
+	if keyLen == 0 && valueLen != 0 || keyLen != 0 && valueLen == 0 {
+		log.Panic("open-protocol meet invalid data",
+			zap.Int("keyLen", keyLen), zap.Int("valueLen", valueLen))
+	}
+
+	return false
+}
+
+func (b *BatchDecoder) decodeNextKey() error {
+	keyLen := binary.BigEndian.Uint64(b.keyBytes[:8])
+	key := b.keyBytes[8 : keyLen+8]
+	msgKey := new(messageKey)
+	if err := json.Unmarshal(key, msgKey); err != nil {
+		return cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	b.nextKey = msgKey
+
+	b.keyBytes = b.keyBytes[keyLen+8:]
+	return nil
+}
+
+// nextValue returns the next value in the batch, the value is decompressed if necessary.
+func (b *BatchDecoder) nextValue() ([]byte, error) {
+	valueLen := binary.BigEndian.Uint64(b.valueBytes[:8])
+	value := b.valueBytes[8 : valueLen+8]
+	b.valueBytes = b.valueBytes[valueLen+8:]
+
+	value, err := newcommon.Decompress(b.config.LargeMessageHandle.LargeMessageHandleCompression, value)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrOpenProtocolCodecInvalidData, err)
+	}
+	return value, nil
+}
+
+// HasNext implements the RowEventDecoder interface
+func (b *BatchDecoder) HasNext() (model.MessageType, bool, error) {
+	if !b.hasNext() {
+		return model.MessageTypeUnknown, false, nil
+	}
+	if err := b.decodeNextKey(); err != nil {
+		return model.MessageTypeUnknown, false, err
+	}
+
+	if b.nextKey.Type == model.MessageTypeRow {
+		value, err := b.nextValue()
+		if err != nil {
+			return model.MessageTypeUnknown, false, err
+		}
+		rowMsg := new(messageRow)
+		if err := rowMsg.decode(value); err != nil {
+			return b.nextKey.Type, false, errors.Trace(err)
+		}
+		b.nextEvent = msgToRowChange(b.nextKey, rowMsg)
+	}
+
+	return b.nextKey.Type, true, nil
+}
+
+// NextResolvedEvent implements the RowEventDecoder interface
+func (b *BatchDecoder) NextResolvedEvent() (uint64, error) {
+	if b.nextKey.Type != model.MessageTypeResolved {
+		return 0, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack("not found resolved event message")
+	}
+	resolvedTs := b.nextKey.Ts
+	b.nextKey = nil
+	// resolved ts event's value part is empty, can be ignored.
+	b.valueBytes = nil
+	return resolvedTs, nil
+}
+
+// NextDDLEvent implements the RowEventDecoder interface
+func (b *BatchDecoder) NextDDLEvent() (*commonEvent.DDLEvent, error) {
+	if b.nextKey.Type != model.MessageTypeDDL {
+		return nil, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack("not found ddl event message")
+	}
+
+	value, err := b.nextValue()
+	if err != nil {
+		return nil, err
+	}
+	ddlMsg := new(messageDDL)
+	if err := json.Unmarshal(value, ddlMsg); err != nil {
+		return nil, cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	result := &commonEvent.DDLEvent{
+		Type:       byte(ddlMsg.Type),
+		SchemaName: b.nextKey.Schema,
+		TableName:  b.nextKey.Table,
+		Query:      ddlMsg.Query,
+		FinishedTs: b.nextKey.Ts,
+	}
+
+	b.nextKey = nil
+	b.valueBytes = nil
+	return result, nil
+}
+
+// NextDMLEvent implements the RowEventDecoder interface
+func (b *BatchDecoder) NextDMLEvent() (*commonEvent.DMLEvent, error) {
+	if b.nextKey.Type != model.MessageTypeRow {
+		return nil, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack("not found row event message")
+	}
+
+	ctx := context.Background()
+	event := b.nextEvent
+	var err error
+	// claim-check message found
+	if b.nextKey.ClaimCheckLocation != "" {
+		event, err = b.assembleEventFromClaimCheckStorage(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else if b.nextKey.OnlyHandleKey {
+		event = b.assembleHandleKeyOnlyEvent(ctx, event)
+	}
+
+	b.nextKey = nil
+	b.nextEvent = nil
+	return decoder.NewDMLEvent(event)
+}
+
+func (b *BatchDecoder) buildColumns(
+	holder *newcommon.ColumnsHolder, handleKeyColumns map[string]interface{},
+) []*common.Column {
+	columnsCount := holder.Length()
+	columns := make([]*common.Column, 0, columnsCount)
+	for i := 0; i < columnsCount; i++ {
+		columnType := holder.Types[i]
+		name := columnType.Name()
+		mysqlType := types.StrToType(strings.ToLower(columnType.DatabaseTypeName()))
+
+		var value interface{}
+		if raw, ok := holder.Values[i].([]uint8); ok {
+			value = raw
+			switch mysqlType {
+			case mysql.TypeJSON, mysql.TypeEnum, mysql.TypeSet:
+				value = string(raw)
+			case mysql.TypeBit:
+				value = newcommon.MustBinaryLiteralToInt(raw)
+			}
+		}
+
+		column := &common.Column{
+			Name:  name,
+			Type:  mysqlType,
+			Value: value,
+		}
+		if _, ok := handleKeyColumns[name]; ok {
+			column.Flag.SetIsHandleKey()
+			column.Flag.SetIsPrimaryKey()
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// handleKeyConditions returns the conditions to query the whole row by the handle key columns.
+func handleKeyConditions(columns []*common.Column) map[string]interface{} {
+	conditions := make(map[string]interface{}, len(columns))
+	for _, col := range columns {
+		value := col.Value
+		switch v := value.(type) {
+		case []byte:
+			value = strconv.Quote(string(v))
+		case string:
+			value = strconv.Quote(v)
+		}
+		conditions[common.QuoteName(col.Name)] = value
+	}
+	return conditions
+}
+
+// assembleHandleKeyOnlyEvent queries the whole row from the upstream TiDB by the
+// handle key columns, at the commit ts for the new row, and the commit ts - 1 for the old row.
+// Note that the elements of enum and set columns are not known by the decoder,
+// their values can not be converted from the names returned by the query.
+func (b *BatchDecoder) assembleHandleKeyOnlyEvent(
+	ctx context.Context, handleKeyOnlyEvent *commonEvent.RowChangedEvent,
+) *commonEvent.RowChangedEvent {
+	var (
+		schema   = handleKeyOnlyEvent.TableInfo.GetSchemaName()
+		table    = handleKeyOnlyEvent.TableInfo.GetTableName()
+		commitTs = handleKeyOnlyEvent.CommitTs
+	)
+
+	query := func(ts uint64, handleKeyColumns []*common.Column) []*common.Column {
+		conditions := handleKeyConditions(handleKeyColumns)
+		holder := newcommon.MustSnapshotQuery(ctx, b.upstreamTiDB, ts, schema, table, conditions)
+		names := make(map[string]interface{}, len(handleKeyColumns))
+		for _, col := range handleKeyColumns {
+			names[col.Name] = struct{}{}
+		}
+		return b.buildColumns(holder, names)
+	}
+
+	var columns, preColumns []*common.Column
+	if len(handleKeyOnlyEvent.Columns) != 0 {
+		columns = query(commitTs, handleKeyOnlyEvent.Columns)
+	}
+	if len(handleKeyOnlyEvent.PreColumns) != 0 {
+		preColumns = query(commitTs-1, handleKeyOnlyEvent.PreColumns)
+	}
+	tableColumns := columns
+	if len(tableColumns) == 0 {
+		tableColumns = preColumns
+	}
+	indexColumns := commonEvent.GetHandleAndUniqueIndexOffsets4Test(tableColumns)
+	handleKeyOnlyEvent.TableInfo = common.BuildTableInfo(schema, table, tableColumns, indexColumns)
+	handleKeyOnlyEvent.Columns = columns
+	handleKeyOnlyEvent.PreColumns = preColumns
+	return handleKeyOnlyEvent
+}
+
+func (b *BatchDecoder) assembleEventFromClaimCheckStorage(ctx context.Context) (*commonEvent.RowChangedEvent, error) {
+	_, claimCheckFileName := filepath.Split(b.nextKey.ClaimCheckLocation)
+	data, err := b.storage.ReadFile(ctx, claimCheckFileName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	claimCheckM, err := newcommon.UnmarshalClaimCheckMessage(data)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+
+	// the claim-check message is a batch contains only one message.
+	claimCheckDecoder := &BatchDecoder{config: b.config}
+	if err = claimCheckDecoder.AddKeyValue(claimCheckM.Key, claimCheckM.Value); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tp, hasNext, err := claimCheckDecoder.HasNext()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !hasNext || tp != model.MessageTypeRow {
+		return nil, cerror.ErrOpenProtocolCodecInvalidData.
+			GenWithStack("not found row event in the claim-check message")
+	}
+	return claimCheckDecoder.nextEvent, nil
+}
+
+func msgToRowChange(key *messageKey, value *messageRow) *commonEvent.RowChangedEvent {
+	e := new(commonEvent.RowChangedEvent)
+	e.CommitTs = key.Ts
+	if len(value.Delete) != 0 {
+		e.PreColumns = codecColumns2RowChangeColumns(value.Delete)
+	} else {
+		e.Columns = codecColumns2RowChangeColumns(value.Update)
+		e.PreColumns = codecColumns2RowChangeColumns(value.PreColumns)
+	}
+
+	columns := e.Columns
+	if len(columns) == 0 {
+		columns = e.PreColumns
+	}
+	indexColumns := commonEvent.GetHandleAndUniqueIndexOffsets4Test(columns)
+	e.TableInfo = common.BuildTableInfo(key.Schema, key.Table, columns, indexColumns)
+	if key.Partition != nil {
+		e.PhysicalTableID = *key.Partition
+		e.TableInfo.TableName.IsPartition = true
+	}
+	return e
+}
+
+func codecColumns2RowChangeColumns(cols map[string]internal.Column) []*common.Column {
+	if len(cols) == 0 {
+		return nil
+	}
+	columns := make([]*common.Column, 0, len(cols))
+	for name, col := range cols {
+		columns = append(columns, col.ToRowChangeColumn(name))
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Name < columns[j].Name
+	})
+	return columns
+}
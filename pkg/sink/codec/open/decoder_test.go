@@ -0,0 +1,203 @@
+package open
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/ticdc/pkg/common"
+	pevent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	codecdecoder "github.com/pingcap/ticdc/pkg/sink/codec/decoder"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeRowChangedEvents(t *testing.T) {
+	ctx := context.Background()
+	codecConfig := newcommon.NewConfig(config.ProtocolOpen)
+	batchEncoder, err := NewBatchEncoder(ctx, codecConfig)
+	require.NoError(t, err)
+
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+
+	job := helper.DDL2Job(`create table test.t(a int primary key, b varchar(32), c double,
+		d datetime, e decimal(10, 2), f enum('x', 'y'), g blob)`)
+	tableInfo := helper.GetTableInfo(job)
+	dmlEvent := helper.DML2Event("test", "t",
+		`insert into test.t values (1, 'hello', 3.14, '2024-01-01 10:00:00', 12.34, 'y', x'0102')`)
+	require.NotNil(t, dmlEvent)
+	insertRow, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+	deleteRow := pevent.RowChange{PreRow: insertRow.Row, RowType: pevent.RowTypeDelete}
+
+	for _, row := range []pevent.RowChange{insertRow, deleteRow} {
+		err = batchEncoder.AppendRowChangedEvent(ctx, "", &pevent.RowEvent{
+			TableInfo:      tableInfo,
+			CommitTs:       dmlEvent.CommitTs,
+			Event:          row,
+			ColumnSelector: common.NewDefaultColumnSelector(),
+			Callback:       func() {},
+		})
+		require.NoError(t, err)
+	}
+	messages := batchEncoder.Build()
+	require.NotEmpty(t, messages)
+
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	var events []*pevent.DMLEvent
+	for _, message := range messages {
+		require.NoError(t, decoder.AddKeyValue(message.Key, message.Value))
+		for {
+			tp, hasNext, err := decoder.HasNext()
+			require.NoError(t, err)
+			if !hasNext {
+				break
+			}
+			require.Equal(t, model.MessageTypeRow, tp)
+			event, err := decoder.NextDMLEvent()
+			require.NoError(t, err)
+			events = append(events, event)
+		}
+	}
+	require.Len(t, events, 2)
+
+	expectedTypes := []pevent.RowType{pevent.RowTypeInsert, pevent.RowTypeDelete}
+	for i, event := range events {
+		require.Equal(t, dmlEvent.CommitTs, event.CommitTs)
+		require.Equal(t, "test", event.TableInfo.GetSchemaName())
+		require.Equal(t, "t", event.TableInfo.GetTableName())
+		row, ok := event.GetNextRow()
+		require.True(t, ok)
+		require.Equal(t, expectedTypes[i], row.RowType)
+		actual := row.Row
+		if row.RowType == pevent.RowTypeDelete {
+			actual = row.PreRow
+		}
+		codecdecoder.RequireRowEqual(t, tableInfo, insertRow.Row, event.TableInfo, actual)
+	}
+}
+
+func TestDecodeClaimCheckMessage(t *testing.T) {
+	ctx := context.Background()
+	codecConfig := newcommon.NewConfig(config.ProtocolOpen)
+	codecConfig = codecConfig.WithMaxMessageBytes(1000)
+	codecConfig.LargeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionClaimCheck
+	codecConfig.LargeMessageHandle.ClaimCheckStorageURI = "file://" + t.TempDir()
+	batchEncoder, err := NewBatchEncoder(ctx, codecConfig)
+	require.NoError(t, err)
+
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+
+	job := helper.DDL2Job(`create table test.t(a int primary key, b text)`)
+	tableInfo := helper.GetTableInfo(job)
+	dmlEvent := helper.DML2Event("test", "t",
+		fmt.Sprintf(`insert into test.t values (1, '%s')`, strings.Repeat("x", 2000)))
+	require.NotNil(t, dmlEvent)
+	insertRow, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+
+	err = batchEncoder.AppendRowChangedEvent(ctx, "", &pevent.RowEvent{
+		TableInfo:      tableInfo,
+		CommitTs:       dmlEvent.CommitTs,
+		Event:          insertRow,
+		ColumnSelector: common.NewDefaultColumnSelector(),
+		Callback:       func() {},
+	})
+	require.NoError(t, err)
+	messages := batchEncoder.Build()
+	require.Len(t, messages, 1)
+	// only the location of the claim-check message is sent
+	require.Contains(t, string(messages[0].Key), `"ccl"`)
+
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	require.NoError(t, decoder.AddKeyValue(messages[0].Key, messages[0].Value))
+	tp, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	event, err := decoder.NextDMLEvent()
+	require.NoError(t, err)
+
+	// the whole row is read from the external storage
+	require.Equal(t, dmlEvent.CommitTs, event.CommitTs)
+	row, ok := event.GetNextRow()
+	require.True(t, ok)
+	require.Equal(t, pevent.RowTypeInsert, row.RowType)
+	codecdecoder.RequireRowEqual(t, tableInfo, insertRow.Row, event.TableInfo, row.Row)
+}
+
+func TestDecodeHandleKeyOnlyMessage(t *testing.T) {
+	ctx := context.Background()
+	codecConfig := newcommon.NewConfig(config.ProtocolOpen)
+	codecConfig = codecConfig.WithMaxMessageBytes(1000)
+	codecConfig.LargeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionHandleKeyOnly
+	batchEncoder, err := NewBatchEncoder(ctx, codecConfig)
+	require.NoError(t, err)
+
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+
+	job := helper.DDL2Job(`create table test.t(a int primary key, b text)`)
+	tableInfo := helper.GetTableInfo(job)
+	value := strings.Repeat("x", 2000)
+	dmlEvent := helper.DML2Event("test", "t",
+		fmt.Sprintf(`insert into test.t values (1, '%s')`, value))
+	require.NotNil(t, dmlEvent)
+	insertRow, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+
+	err = batchEncoder.AppendRowChangedEvent(ctx, "", &pevent.RowEvent{
+		TableInfo:      tableInfo,
+		CommitTs:       dmlEvent.CommitTs,
+		Event:          insertRow,
+		ColumnSelector: common.NewDefaultColumnSelector(),
+		Callback:       func() {},
+	})
+	require.NoError(t, err)
+	messages := batchEncoder.Build()
+	require.Len(t, messages, 1)
+	require.Contains(t, string(messages[0].Key), `"ohk":true`)
+
+	// the decoder requires the upstream TiDB to query the whole row
+	_, err = NewBatchDecoder(ctx, codecConfig, nil)
+	require.Error(t, err)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec(fmt.Sprintf("set @@tidb_snapshot=%d", dmlEvent.CommitTs)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("select \\* from test.t where `a` = 1").
+		WillReturnRows(sqlmock.NewRowsWithColumnDefinition(
+			sqlmock.NewColumn("a").OfType("INT", int64(0)),
+			sqlmock.NewColumn("b").OfType("TEXT", ""),
+		).AddRow([]byte("1"), []byte(value)))
+
+	decoder, err := NewBatchDecoder(ctx, codecConfig, db)
+	require.NoError(t, err)
+	require.NoError(t, decoder.AddKeyValue(messages[0].Key, messages[0].Value))
+	tp, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	event, err := decoder.NextDMLEvent()
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Equal(t, dmlEvent.CommitTs, event.CommitTs)
+	row, ok := event.GetNextRow()
+	require.True(t, ok)
+	require.Equal(t, pevent.RowTypeInsert, row.RowType)
+	codecdecoder.RequireRowEqual(t, tableInfo, insertRow.Row, event.TableInfo, row.Row)
+}
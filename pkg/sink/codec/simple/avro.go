@@ -19,12 +19,13 @@ import (
 	"time"
 
 	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	timodel "github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/parser/types"
-	"github.com/pingcap/tiflow/cdc/model"
 )
 
-func newTableSchemaMap(tableInfo *model.TableInfo) interface{} {
+func newTableSchemaMap(tableInfo *common.TableInfo) interface{} {
 	pkInIndexes := false
 	indexesSchema := make([]interface{}, 0, len(tableInfo.Indices))
 	for _, idx := range tableInfo.Indices {
@@ -65,12 +66,15 @@ func newTableSchemaMap(tableInfo *model.TableInfo) interface{} {
 		}
 	}
 
-	sort.SliceStable(tableInfo.Columns, func(i, j int) bool {
-		return tableInfo.Columns[i].ID < tableInfo.Columns[j].ID
+	// the table info is shared with other components, sort a copy of the columns.
+	sortedColumns := make([]*timodel.ColumnInfo, len(tableInfo.Columns))
+	copy(sortedColumns, tableInfo.Columns)
+	sort.SliceStable(sortedColumns, func(i, j int) bool {
+		return sortedColumns[i].ID < sortedColumns[j].ID
 	})
 
-	columnsSchema := make([]interface{}, 0, len(tableInfo.Columns))
-	for _, col := range tableInfo.Columns {
+	columnsSchema := make([]interface{}, 0, len(sortedColumns))
+	for _, col := range sortedColumns {
 		mysqlType := map[string]interface{}{
 			"mysqlType": types.TypeToStr(col.GetType(), col.GetCharset()),
 			"charset":   col.GetCharset(),
@@ -110,7 +114,7 @@ func newTableSchemaMap(tableInfo *model.TableInfo) interface{} {
 			"nullable": !mysql.HasNotNullFlag(col.GetFlag()),
 			"default":  nil,
 		}
-		defaultValue := model.GetColumnDefaultValue(col)
+		defaultValue := common.GetColumnDefaultValue(col)
 		if defaultValue != nil {
 			// according to TiDB source code, the default value is converted to string if not nil.
 			column["default"] = map[string]interface{}{
@@ -154,7 +158,7 @@ func newResolvedMessageMap(ts uint64) map[string]interface{} {
 	}
 }
 
-func newBootstrapMessageMap(tableInfo *model.TableInfo) map[string]interface{} {
+func newBootstrapMessageMap(tableInfo *common.TableInfo) map[string]interface{} {
 	m := map[string]interface{}{
 		"version":     defaultVersion,
 		"type":        string(MessageTypeBootstrap),
@@ -176,12 +180,12 @@ func newBootstrapMessageMap(tableInfo *model.TableInfo) map[string]interface{} {
 	}
 }
 
-func newDDLMessageMap(ddl *model.DDLEvent) map[string]interface{} {
+func newDDLMessageMap(ddl *commonEvent.DDLEvent) map[string]interface{} {
 	result := map[string]interface{}{
 		"version":  defaultVersion,
-		"type":     string(getDDLType(ddl.Type)),
+		"type":     string(getDDLType(timodel.ActionType(ddl.Type))),
 		"sql":      ddl.Query,
-		"commitTs": int64(ddl.CommitTs),
+		"commitTs": int64(ddl.GetCommitTs()),
 		"buildTs":  time.Now().UnixMilli(),
 	}
 
@@ -191,12 +195,6 @@ func newDDLMessageMap(ddl *model.DDLEvent) map[string]interface{} {
 			"com.pingcap.simple.avro.TableSchema": tableSchema,
 		}
 	}
-	if ddl.PreTableInfo != nil && ddl.PreTableInfo.TableInfo != nil {
-		tableSchema := newTableSchemaMap(ddl.PreTableInfo)
-		result["preTableSchema"] = map[string]interface{}{
-			"com.pingcap.simple.avro.TableSchema": tableSchema,
-		}
-	}
 
 	result = map[string]interface{}{
 		"com.pingcap.simple.avro.DDL": result,
@@ -245,7 +243,7 @@ var (
 )
 
 func (a *avroMarshaller) newDMLMessageMap(
-	event *common.RowChangedEvent,
+	event *commonEvent.RowChangedEvent,
 	onlyHandleKey bool,
 	claimCheckFileName string,
 ) map[string]interface{} {
@@ -23,6 +23,9 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/pingcap/ticdc/pkg/sink/codec/decoder"
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -34,7 +37,7 @@ import (
 
 // Decoder implement the RowEventDecoder interface
 type Decoder struct {
-	config *ticommon.Config
+	config *newcommon.Config
 
 	marshaller marshaller
 
@@ -48,11 +51,11 @@ type Decoder struct {
 	// cachedMessages is used to store the messages which does not have received corresponding table info yet.
 	cachedMessages *list.List
 	// CachedRowChangedEvents are events just decoded from the cachedMessages
-	CachedRowChangedEvents []*common.RowChangedEvent
+	CachedRowChangedEvents []*commonEvent.DMLEvent
 }
 
 // NewDecoder returns a new Decoder
-func NewDecoder(ctx context.Context, config *ticommon.Config, db *sql.DB) (*Decoder, error) {
+func NewDecoder(ctx context.Context, config *newcommon.Config, db *sql.DB) (*Decoder, error) {
 	var (
 		externalStorage storage.ExternalStorage
 		err             error
@@ -131,8 +134,18 @@ func (d *Decoder) NextResolvedEvent() (uint64, error) {
 	return ts, nil
 }
 
-// NextRowChangedEvent returns the next row changed event if exists
-func (d *Decoder) NextRowChangedEvent() (*common.RowChangedEvent, error) {
+// NextDMLEvent returns the next row changed event as a DML event if exists,
+// nil is returned if the table info of the event is not received yet,
+// the event is cached and returned by `GetCachedEvents` after the table info is received.
+func (d *Decoder) NextDMLEvent() (*commonEvent.DMLEvent, error) {
+	event, err := d.nextRowChangedEvent()
+	if err != nil || event == nil {
+		return nil, err
+	}
+	return decoder.NewDMLEvent(event)
+}
+
+func (d *Decoder) nextRowChangedEvent() (*commonEvent.RowChangedEvent, error) {
 	if d.msg == nil || (d.msg.Data == nil && d.msg.Old == nil) {
 		return nil, cerror.ErrCodecDecode.GenWithStack(
 			"invalid row changed event message")
@@ -165,7 +178,7 @@ func (d *Decoder) NextRowChangedEvent() (*common.RowChangedEvent, error) {
 	return event, err
 }
 
-func (d *Decoder) assembleClaimCheckRowChangedEvent(claimCheckLocation string) (*common.RowChangedEvent, error) {
+func (d *Decoder) assembleClaimCheckRowChangedEvent(claimCheckLocation string) (*commonEvent.RowChangedEvent, error) {
 	_, claimCheckFileName := filepath.Split(claimCheckLocation)
 	data, err := d.storage.ReadFile(context.Background(), claimCheckFileName)
 	if err != nil {
@@ -191,10 +204,10 @@ func (d *Decoder) assembleClaimCheckRowChangedEvent(claimCheckLocation string) (
 		return nil, err
 	}
 	d.msg = m
-	return d.NextRowChangedEvent()
+	return d.nextRowChangedEvent()
 }
 
-func (d *Decoder) assembleHandleKeyOnlyRowChangedEvent(m *message) (*common.RowChangedEvent, error) {
+func (d *Decoder) assembleHandleKeyOnlyRowChangedEvent(m *message) (*commonEvent.RowChangedEvent, error) {
 	tableInfo := d.memo.Read(m.Schema, m.Table, m.SchemaVersion)
 	if tableInfo == nil {
 		log.Debug("table info not found for the event, "+
@@ -240,7 +253,7 @@ func (d *Decoder) assembleHandleKeyOnlyRowChangedEvent(m *message) (*common.RowC
 	}
 
 	d.msg = result
-	return d.NextRowChangedEvent()
+	return d.nextRowChangedEvent()
 }
 
 func (d *Decoder) buildData(
@@ -259,35 +272,35 @@ func (d *Decoder) buildData(
 	return result
 }
 
-// NextDDLEvent returns the next DDL event if exists
-func (d *Decoder) NextDDLEvent() (*model.DDLEvent, error) {
+// NextDDLEvent returns the next DDL event if exists,
+// the bootstrap message is returned as a DDL event without query.
+func (d *Decoder) NextDDLEvent() (*commonEvent.DDLEvent, error) {
 	if d.msg == nil {
 		return nil, cerror.ErrCodecDecode.GenWithStack(
 			"no message found when decode DDL event")
 	}
 	ddl := newDDLEvent(d.msg)
 	d.msg = nil
-	// TODO:
-	// d.memo.Write(ddl.TableInfo)
-	// d.memo.Write(ddl.PreTableInfo)
+	d.memo.Write(ddl.TableInfo)
 
-	for ele := d.cachedMessages.Front(); ele != nil; {
+	// the message is cached again if its table info is still not received.
+	cachedMessages := d.cachedMessages
+	d.cachedMessages = list.New()
+	for ele := cachedMessages.Front(); ele != nil; ele = ele.Next() {
 		d.msg = ele.Value.(*message)
-		event, err := d.NextRowChangedEvent()
+		event, err := d.NextDMLEvent()
 		if err != nil {
 			return nil, err
 		}
-		d.CachedRowChangedEvents = append(d.CachedRowChangedEvents, event)
-
-		next := ele.Next()
-		d.cachedMessages.Remove(ele)
-		ele = next
+		if event != nil {
+			d.CachedRowChangedEvents = append(d.CachedRowChangedEvents, event)
+		}
 	}
 	return ddl, nil
 }
 
 // GetCachedEvents returns the cached events
-func (d *Decoder) GetCachedEvents() []*common.RowChangedEvent {
+func (d *Decoder) GetCachedEvents() []*commonEvent.DMLEvent {
 	result := d.CachedRowChangedEvents
 	d.CachedRowChangedEvents = nil
 	return result
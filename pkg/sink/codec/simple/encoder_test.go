@@ -0,0 +1,88 @@
+package simple
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pingcap/ticdc/pkg/common/columnselector"
+	pevent "github.com/pingcap/ticdc/pkg/common/event"
+	newcommon "github.com/pingcap/ticdc/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDDLAndDMLEvent(t *testing.T) {
+	ctx := context.Background()
+	codecConfig := newcommon.NewConfig(config.ProtocolSimple)
+	enc, err := NewEncoder(ctx, codecConfig)
+	require.NoError(t, err)
+
+	helper := pevent.NewEventTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+
+	job := helper.DDL2Job(`create table test.t(a int primary key, b varchar(32))`)
+	tableInfo := helper.GetTableInfo(job)
+	ddlEvent := &pevent.DDLEvent{
+		Type:       byte(job.Type),
+		Query:      job.Query,
+		SchemaName: job.SchemaName,
+		TableName:  job.TableName,
+		TableInfo:  tableInfo,
+		FinishedTs: job.BinlogInfo.FinishedTS,
+	}
+	msg, err := enc.EncodeDDLEvent(ddlEvent)
+	require.NoError(t, err)
+	require.Equal(t, model.MessageTypeDDL, msg.Type)
+	require.Equal(t, ddlEvent.GetCommitTs(), msg.Ts)
+
+	decoded := new(message)
+	require.NoError(t, json.Unmarshal(msg.Value, decoded))
+	require.Equal(t, DDLTypeCreate, decoded.Type)
+	require.Equal(t, job.Query, decoded.SQL)
+	require.Equal(t, ddlEvent.GetCommitTs(), decoded.CommitTs)
+	require.Equal(t, "test", decoded.TableSchema.Schema)
+	require.Equal(t, "t", decoded.TableSchema.Table)
+	require.Len(t, decoded.TableSchema.Columns, 2)
+	require.Nil(t, decoded.PreTableSchema)
+
+	// the bootstrap message carries the table schema only.
+	ddlEvent.IsBootstrap = true
+	msg, err = enc.EncodeDDLEvent(ddlEvent)
+	require.NoError(t, err)
+	decoded = new(message)
+	require.NoError(t, json.Unmarshal(msg.Value, decoded))
+	require.Equal(t, MessageTypeBootstrap, decoded.Type)
+	require.Equal(t, "t", decoded.TableSchema.Table)
+
+	dmlEvent := helper.DML2Event("test", "t", `insert into test.t values (1, 'hello')`)
+	require.NotNil(t, dmlEvent)
+	insertRow, ok := dmlEvent.GetNextRow()
+	require.True(t, ok)
+
+	count := 0
+	err = enc.AppendRowChangedEvent(ctx, "", &pevent.RowEvent{
+		PhysicalTableID: dmlEvent.PhysicalTableID,
+		TableInfo:       tableInfo,
+		CommitTs:        dmlEvent.CommitTs,
+		Event:           insertRow,
+		ColumnSelector:  columnselector.NewDefaultColumnSelector(),
+		Callback:        func() { count++ },
+	})
+	require.NoError(t, err)
+
+	messages := enc.Build()
+	require.Len(t, messages, 1)
+	require.Equal(t, model.MessageTypeRow, messages[0].Type)
+	decoded = new(message)
+	require.NoError(t, json.Unmarshal(messages[0].Value, decoded))
+	require.Equal(t, DMLTypeInsert, decoded.Type)
+	require.Equal(t, dmlEvent.CommitTs, decoded.CommitTs)
+	require.Equal(t, map[string]interface{}{"a": "1", "b": "hello"}, decoded.Data)
+	require.Nil(t, decoded.Old)
+
+	messages[0].Callback()
+	require.Equal(t, 1, count)
+}
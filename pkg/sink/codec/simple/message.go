@@ -23,12 +23,12 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
 	commonNew "github.com/pingcap/ticdc/pkg/sink/codec/common"
 	timodel "github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/parser/types"
 	tiTypes "github.com/pingcap/tidb/pkg/types"
-	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/integrity"
 	ticommon "github.com/pingcap/tiflow/pkg/sink/codec/common"
@@ -146,7 +146,7 @@ func newColumnSchema(col *timodel.ColumnInfo) *columnSchema {
 		tp.Decimal = col.GetDecimal()
 	}
 
-	defaultValue := model.GetColumnDefaultValue(col)
+	defaultValue := common.GetColumnDefaultValue(col)
 	if defaultValue != nil && col.GetType() == mysql.TypeBit {
 		defaultValue = ticommon.MustBinaryLiteralToInt([]byte(defaultValue.(string)))
 	}
@@ -282,7 +282,7 @@ type TableSchema struct {
 	Indexes []*IndexSchema  `json:"indexes"`
 }
 
-func newTableSchema(tableInfo *model.TableInfo) *TableSchema {
+func newTableSchema(tableInfo *common.TableInfo) *TableSchema {
 	pkInIndexes := false
 	indexes := make([]*IndexSchema, 0, len(tableInfo.Indices))
 	for _, idx := range tableInfo.Indices {
@@ -308,12 +308,15 @@ func newTableSchema(tableInfo *model.TableInfo) *TableSchema {
 		}
 	}
 
-	sort.SliceStable(tableInfo.Columns, func(i, j int) bool {
-		return tableInfo.Columns[i].ID < tableInfo.Columns[j].ID
+	// the table info is shared with other components, sort a copy of the columns.
+	sortedColumns := make([]*timodel.ColumnInfo, len(tableInfo.Columns))
+	copy(sortedColumns, tableInfo.Columns)
+	sort.SliceStable(sortedColumns, func(i, j int) bool {
+		return sortedColumns[i].ID < sortedColumns[j].ID
 	})
 
-	columns := make([]*columnSchema, 0, len(tableInfo.Columns))
-	for _, col := range tableInfo.Columns {
+	columns := make([]*columnSchema, 0, len(sortedColumns))
+	for _, col := range sortedColumns {
 		colSchema := newColumnSchema(col)
 		columns = append(columns, colSchema)
 	}
@@ -329,16 +332,12 @@ func newTableSchema(tableInfo *model.TableInfo) *TableSchema {
 }
 
 // newTableInfo converts from TableSchema to TableInfo.
-func newTableInfo(m *TableSchema) *model.TableInfo {
-	var (
-		database      string
-		schemaVersion uint64
-	)
+func newTableInfo(m *TableSchema) *common.TableInfo {
+	var database string
 
 	tidbTableInfo := &timodel.TableInfo{}
 	if m != nil {
 		database = m.Schema
-		schemaVersion = m.Version
 
 		tidbTableInfo.ID = m.TableID
 		tidbTableInfo.Name = timodel.NewCIStr(m.Table)
@@ -358,34 +357,52 @@ func newTableInfo(m *TableSchema) *model.TableInfo {
 			mockIndexID += 1
 		}
 	}
-	return model.WrapTableInfo(100, database, schemaVersion, tidbTableInfo)
+	return common.WrapTableInfo(100, database, tidbTableInfo)
+}
+
+// getDDLActionType returns the action type of the DDL message type,
+// the message type is coarser than the action type, so it's only an approximation.
+func getDDLActionType(t MessageType) timodel.ActionType {
+	switch t {
+	case DDLTypeCreate:
+		return timodel.ActionCreateTable
+	case DDLTypeRename:
+		return timodel.ActionRenameTable
+	case DDLTypeCIndex:
+		return timodel.ActionAddIndex
+	case DDLTypeDIndex:
+		return timodel.ActionDropIndex
+	case DDLTypeErase:
+		return timodel.ActionDropTable
+	case DDLTypeTruncate:
+		return timodel.ActionTruncateTable
+	default:
+		return timodel.ActionNone
+	}
 }
 
 // newDDLEvent converts from message to DDLEvent.
-func newDDLEvent(msg *message) *model.DDLEvent {
-	var (
-		tableInfo    *model.TableInfo
-		preTableInfo *model.TableInfo
-	)
-
-	tableInfo = newTableInfo(msg.TableSchema)
-	if msg.PreTableSchema != nil {
-		preTableInfo = newTableInfo(msg.PreTableSchema)
-	}
-	return &model.DDLEvent{
-		StartTs:      msg.CommitTs,
-		CommitTs:     msg.CommitTs,
-		TableInfo:    tableInfo,
-		PreTableInfo: preTableInfo,
-		Query:        msg.SQL,
+func newDDLEvent(msg *message) *commonEvent.DDLEvent {
+	result := &commonEvent.DDLEvent{
+		Type:        byte(getDDLActionType(msg.Type)),
+		FinishedTs:  msg.CommitTs,
+		Query:       msg.SQL,
+		IsBootstrap: msg.Type == MessageTypeBootstrap,
+	}
+	if msg.TableSchema != nil {
+		result.TableInfo = newTableInfo(msg.TableSchema)
+		result.SchemaName = msg.TableSchema.Schema
+		result.TableName = msg.TableSchema.Table
+		result.TableID = msg.TableSchema.TableID
 	}
+	return result
 }
 
 // buildRowChangedEvent converts from message to RowChangedEvent.
 func buildRowChangedEvent(
 	msg *message, tableInfo *common.TableInfo, enableRowChecksum bool, db *sql.DB,
-) (*common.RowChangedEvent, error) {
-	result := &common.RowChangedEvent{
+) (*commonEvent.RowChangedEvent, error) {
+	result := &commonEvent.RowChangedEvent{
 		CommitTs:        msg.CommitTs,
 		PhysicalTableID: msg.TableID,
 		TableInfo:       tableInfo,
@@ -459,6 +476,7 @@ func decodeColumns(
 			log.Panic("cannot decode column",
 				zap.String("name", info.Name.O), zap.Any("data", value))
 		}
+		col.Name = info.Name.O
 
 		result = append(result, col)
 	}
@@ -513,7 +531,7 @@ func newResolvedMessage(ts uint64) *message {
 	}
 }
 
-func newBootstrapMessage(tableInfo *model.TableInfo) *message {
+func newBootstrapMessage(tableInfo *common.TableInfo) *message {
 	schema := newTableSchema(tableInfo)
 	msg := &message{
 		Version:     defaultVersion,
@@ -524,33 +542,27 @@ func newBootstrapMessage(tableInfo *model.TableInfo) *message {
 	return msg
 }
 
-func newDDLMessage(ddl *model.DDLEvent) *message {
-	var (
-		schema    *TableSchema
-		preSchema *TableSchema
-	)
+func newDDLMessage(ddl *commonEvent.DDLEvent) *message {
+	var schema *TableSchema
 	// the tableInfo maybe nil if the DDL is `drop database`
+	// the DDLEvent does not carry the table info before the DDL executed,
+	// so the PreTableSchema is not set.
 	if ddl.TableInfo != nil && ddl.TableInfo.TableInfo != nil {
 		schema = newTableSchema(ddl.TableInfo)
 	}
-	// `PreTableInfo` may not exist for some DDL, such as `create table`
-	if ddl.PreTableInfo != nil && ddl.PreTableInfo.TableInfo != nil {
-		preSchema = newTableSchema(ddl.PreTableInfo)
-	}
 	msg := &message{
-		Version:        defaultVersion,
-		Type:           getDDLType(ddl.Type),
-		CommitTs:       ddl.CommitTs,
-		BuildTs:        time.Now().UnixMilli(),
-		SQL:            ddl.Query,
-		TableSchema:    schema,
-		PreTableSchema: preSchema,
+		Version:     defaultVersion,
+		Type:        getDDLType(timodel.ActionType(ddl.Type)),
+		CommitTs:    ddl.GetCommitTs(),
+		BuildTs:     time.Now().UnixMilli(),
+		SQL:         ddl.Query,
+		TableSchema: schema,
 	}
 	return msg
 }
 
 func (a *JSONMarshaller) newDMLMessage(
-	event *common.RowChangedEvent,
+	event *commonEvent.RowChangedEvent,
 	onlyHandleKey bool, claimCheckFileName string,
 ) *message {
 	m := &message{
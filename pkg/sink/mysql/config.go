@@ -95,6 +95,15 @@ type MysqlConfig struct {
 	// retry number for dml
 	DMLMaxRetry uint64
 
+	// DeadLetterEnable is true if the rows which fail with a non-retryable error
+	// are written to the dead letter destination instead of failing the changefeed.
+	DeadLetterEnable bool
+	// DeadLetterTable is the downstream table to write the dead letters.
+	DeadLetterTable string
+	// DeadLetterStorageURI is the external storage to write the dead letters,
+	// it takes precedence over DeadLetterTable.
+	DeadLetterStorageURI string
+
 	IsTiDB bool // IsTiDB is true if the downstream is TiDB
 	// IsBDRModeSupported is true if the downstream is TiDB and write source is existed.
 	// write source exists when the downstream is TiDB and version is greater than or equal to v6.5.0.
@@ -0,0 +1,265 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/filter"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	putil "github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const deadLetterStorageTimeout = 5 * time.Minute
+
+// DeadLetter is a row which can not be applied to the downstream.
+type DeadLetter struct {
+	Schema   string        `json:"schema"`
+	Table    string        `json:"table"`
+	CommitTs uint64        `json:"commit_ts"`
+	SQL      string        `json:"sql"`
+	Args     []interface{} `json:"args"`
+	Error    string        `json:"error"`
+}
+
+// rowDML is the statement of a single row, it's used to locate
+// the bad rows when a batch of rows can not be applied.
+type rowDML struct {
+	event *commonEvent.DMLEvent
+	sql   string
+	args  []interface{}
+}
+
+// isDeadLetterError returns true if the error is caused by the data of the rows,
+// which means that the rows will never be applied by retrying.
+// Connection, timeout and driver errors are not caused by the rows,
+// so they are always returned to the caller.
+func isDeadLetterError(err error) bool {
+	mysqlErr, ok := errors.Cause(err).(*dmysql.MySQLError)
+	if !ok {
+		return false
+	}
+	switch mysqlErr.Number {
+	case errno.ErrDataTooLong, errno.WarnDataTruncated,
+		errno.ErrTruncatedWrongValue, errno.ErrTruncatedWrongValueForField,
+		errno.ErrWarnDataOutOfRange, errno.ErrDataOutOfRange,
+		errno.ErrBadNull, errno.ErrWrongValue, errno.ErrInvalidCharacterString,
+		errno.ErrIncorrectDatetimeValue, errno.ErrCheckConstraintViolated,
+		errno.ErrDupEntry,
+		errno.ErrNoReferencedRow, errno.ErrNoReferencedRow2,
+		errno.ErrRowIsReferenced, errno.ErrRowIsReferenced2:
+		return true
+	default:
+		return false
+	}
+}
+
+// prepareRowDMLs generates one statement for each row of the events.
+func (w *MysqlWriter) prepareRowDMLs(events []*commonEvent.DMLEvent) ([]*rowDML, error) {
+	var rows []*rowDML
+	for _, event := range events {
+		if event.Len() == 0 {
+			continue
+		}
+		// the rows may have been read when preparing the batch statements.
+		event.Rewind()
+		sqls, values, err := w.generateNormalSQLs(event, w.translateToInsert(event))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for i := range sqls {
+			rows = append(rows, &rowDML{event: event, sql: sqls[i], args: values[i]})
+		}
+	}
+	return rows, nil
+}
+
+// execDMLsWithDeadLetter applies the rows in a transaction, if the transaction fails
+// with a non-retryable error, the rows are split into halves and applied separately,
+// until the single bad row is located and written to the dead letter destination.
+// So the good rows still land, but the rows of a transaction may be applied partially.
+func (w *MysqlWriter) execDMLsWithDeadLetter(rows []*rowDML) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	dmls := &preparedDMLs{
+		sqls:     make([]string, 0, len(rows)),
+		values:   make([][]interface{}, 0, len(rows)),
+		rowCount: len(rows),
+	}
+	for _, row := range rows {
+		dmls.sqls = append(dmls.sqls, row.sql)
+		dmls.values = append(dmls.values, row.args)
+		dmls.approximateSize += row.event.GetRowsSize() / int64(row.event.Len())
+	}
+	// the rows are executed one by one to avoid exceeding the max allowed packet.
+	sequenceWay := true
+	err := w.statistics.RecordBatchExecution(func() (int, int64, error) {
+		if err := w.execDMLs(dmls, &sequenceWay); err != nil {
+			return 0, 0, err
+		}
+		return dmls.rowCount, dmls.approximateSize, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if !isDeadLetterError(err) {
+		return errors.Trace(err)
+	}
+	if len(rows) == 1 {
+		return w.writeDeadLetter(rows[0], err)
+	}
+	mid := len(rows) / 2
+	if err := w.execDMLsWithDeadLetter(rows[:mid]); err != nil {
+		return err
+	}
+	return w.execDMLsWithDeadLetter(rows[mid:])
+}
+
+func (w *MysqlWriter) writeDeadLetter(row *rowDML, execErr error) error {
+	letter := &DeadLetter{
+		Schema:   row.event.TableInfo.GetSchemaName(),
+		Table:    row.event.TableInfo.GetTableName(),
+		CommitTs: row.event.CommitTs,
+		SQL:      row.sql,
+		Args:     row.args,
+		Error:    errors.Cause(execErr).Error(),
+	}
+	log.Warn("the row can not be applied to the downstream, write it to the dead letter",
+		zap.String("namespace", w.ChangefeedID.Namespace()),
+		zap.String("changefeed", w.ChangefeedID.Name()),
+		zap.String("schema", letter.Schema),
+		zap.String("table", letter.Table),
+		zap.Uint64("commitTs", letter.CommitTs),
+		zap.String("sql", letter.SQL),
+		zap.Error(execErr))
+
+	var err error
+	if w.cfg.DeadLetterStorageURI != "" {
+		err = w.writeDeadLetterToStorage(letter)
+	} else {
+		err = w.writeDeadLetterToTable(letter)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.statistics.RecordDeadLetterRow()
+	return nil
+}
+
+// getDeadLetterTable returns the schema and the table name of the dead letter table.
+func (w *MysqlWriter) getDeadLetterTable() (string, string) {
+	if w.cfg.DeadLetterTable == "" {
+		return filter.TiCDCSystemSchema, filter.DeadLetterTable
+	}
+	if schema, table, ok := strings.Cut(w.cfg.DeadLetterTable, "."); ok {
+		return schema, table
+	}
+	return filter.TiCDCSystemSchema, w.cfg.DeadLetterTable
+}
+
+func (w *MysqlWriter) createDeadLetterTable(schema, table string) error {
+	query := `CREATE TABLE IF NOT EXISTS %s
+	(
+		id bigint NOT NULL AUTO_INCREMENT,
+		ticdc_cluster_id varchar (255),
+		changefeed varchar(255),
+		table_schema varchar(255),
+		table_name varchar(255),
+		commit_ts bigint unsigned,
+		dml_sql longtext,
+		dml_args longtext,
+		error_message longtext,
+		created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		INDEX (ticdc_cluster_id, changefeed, commit_ts),
+		PRIMARY KEY (id)
+	);`
+	query = fmt.Sprintf(query, quotes.QuoteName(table))
+	return w.CreateTable(quotes.QuoteName(schema), table, query)
+}
+
+func (w *MysqlWriter) writeDeadLetterToTable(letter *DeadLetter) error {
+	schema, table := w.getDeadLetterTable()
+	if !w.deadLetterTableInit {
+		if err := w.createDeadLetterTable(schema, table); err != nil {
+			return err
+		}
+		w.deadLetterTableInit = true
+	}
+	args, err := json.Marshal(letter.Args)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+
+	tx, err := w.db.BeginTx(w.ctx, nil)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, errors.WithMessage(err, "write dead letter: begin Tx fail;"))
+	}
+	// the dead letters should not be replicated back in bdr mode.
+	if err = SetWriteSource(w.cfg, tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error("Failed to rollback", zap.Error(rbErr))
+		}
+		return cerror.WrapError(cerror.ErrMySQLTxnError, errors.WithMessage(err, "write dead letter: set write source fail;"))
+	}
+	query := fmt.Sprintf("INSERT INTO %s (ticdc_cluster_id, changefeed, table_schema, table_name, commit_ts, dml_sql, dml_args, error_message) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?)", quotes.QuoteSchema(schema, table))
+	_, err = tx.Exec(query, config.GetGlobalServerConfig().ClusterID, w.ChangefeedID.String(),
+		letter.Schema, letter.Table, letter.CommitTs, letter.SQL, string(args), letter.Error)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error("Failed to rollback", zap.Error(rbErr))
+		}
+		return cerror.WrapError(cerror.ErrMySQLTxnError, errors.WithMessage(err, "failed to write dead letter table;"))
+	}
+	err = tx.Commit()
+	return cerror.WrapError(cerror.ErrMySQLTxnError, errors.WithMessage(err, "failed to write dead letter table; Commit Fail;"))
+}
+
+// writeDeadLetterToStorage writes each dead letter to a json file named by the
+// changefeed and the commitTs of the row in the external storage.
+func (w *MysqlWriter) writeDeadLetterToStorage(letter *DeadLetter) error {
+	if w.deadLetterStorage == nil {
+		externalStorage, err := putil.GetExternalStorageWithTimeout(w.ctx, w.cfg.DeadLetterStorageURI, deadLetterStorageTimeout)
+		if err != nil {
+			log.Error("create dead letter external storage failed",
+				zap.String("namespace", w.ChangefeedID.Namespace()),
+				zap.String("changefeed", w.ChangefeedID.Name()),
+				zap.String("storageURI", putil.MaskSensitiveDataInURI(w.cfg.DeadLetterStorageURI)),
+				zap.Error(err))
+			return errors.Trace(err)
+		}
+		w.deadLetterStorage = externalStorage
+	}
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	fileName := fmt.Sprintf("%s/%s/%d-%s.json",
+		w.ChangefeedID.Namespace(), w.ChangefeedID.Name(), letter.CommitTs, uuid.New().String())
+	return errors.Trace(w.deadLetterStorage.WriteFile(w.ctx, fileName, data))
+}
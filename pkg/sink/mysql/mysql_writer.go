@@ -30,7 +30,9 @@ import (
 	"github.com/pingcap/ticdc/pkg/filter"
 	"github.com/pingcap/ticdc/pkg/metrics"
 	"github.com/pingcap/ticdc/pkg/sink/util"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	timodel "github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/retry"
@@ -65,6 +67,10 @@ type MysqlWriter struct {
 	maxAllowedPacket int64
 
 	statistics *metrics.Statistics
+
+	// deadLetterTableInit is true if the dead letter table is created.
+	deadLetterTableInit bool
+	deadLetterStorage   storage.ExternalStorage
 }
 
 func NewMysqlWriter(ctx context.Context, db *sql.DB, cfg *MysqlConfig, changefeedID common.ChangeFeedID, statistics *metrics.Statistics) *MysqlWriter {
@@ -156,6 +162,13 @@ func (w *MysqlWriter) SendSyncPointEvent(event *commonEvent.SyncPointEvent) erro
 	if err != nil {
 		return cerror.WrapError(cerror.ErrMySQLTxnError, errors.WithMessage(err, "sync table: begin Tx fail;"))
 	}
+
+	if err = SetWriteSource(w.cfg, tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error("Failed to rollback", zap.Error(rbErr))
+		}
+		return cerror.WrapError(cerror.ErrMySQLTxnError, errors.WithMessage(err, "sync table: set write source fail;"))
+	}
 	row := tx.QueryRow("select @@tidb_current_ts")
 	var secondaryTs string
 	err = row.Scan(&secondaryTs)
@@ -247,6 +260,13 @@ func (w *MysqlWriter) SendDDLTs(event *commonEvent.DDLEvent) error {
 		return cerror.WrapError(cerror.ErrMySQLTxnError, errors.WithMessage(err, "ddl ts table: begin Tx fail;"))
 	}
 
+	if err = SetWriteSource(w.cfg, tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error("Failed to rollback", zap.Error(rbErr))
+		}
+		return cerror.WrapError(cerror.ErrMySQLTxnError, errors.WithMessage(err, "ddl ts table: set write source fail;"))
+	}
+
 	changefeedID := w.ChangefeedID.String()
 	ticdcClusterID := config.GetGlobalServerConfig().ClusterID
 	ddlTs := strconv.FormatUint(event.GetCommitTs(), 10)
@@ -478,6 +498,13 @@ func (w *MysqlWriter) RemoveDDLTsItem() error {
 		return cerror.WrapError(cerror.ErrMySQLTxnError, errors.WithMessage(err, "select ddl ts table: begin Tx fail;"))
 	}
 
+	if err = SetWriteSource(w.cfg, tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error("Failed to rollback", zap.Error(rbErr))
+		}
+		return cerror.WrapError(cerror.ErrMySQLTxnError, errors.WithMessage(err, "select ddl ts table: set write source fail;"))
+	}
+
 	changefeedID := w.ChangefeedID.String()
 	ticdcClusterID := config.GetGlobalServerConfig().ClusterID
 
@@ -718,7 +745,21 @@ func (w *MysqlWriter) Flush(events []*commonEvent.DMLEvent, workerNum int) error
 
 	if !w.cfg.DryRun {
 		if err := w.execDMLWithMaxRetries(dmls); err != nil {
-			return errors.Trace(err)
+			if !w.cfg.DeadLetterEnable || !isDeadLetterError(err) {
+				return errors.Trace(err)
+			}
+			log.Warn("failed to apply the rows, locate the bad rows to write them to the dead letter",
+				zap.String("namespace", w.ChangefeedID.Namespace()),
+				zap.String("changefeed", w.ChangefeedID.Name()),
+				zap.Int("rowCount", dmls.rowCount),
+				zap.Error(err))
+			rows, err := w.prepareRowDMLs(events)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if err := w.execDMLsWithDeadLetter(rows); err != nil {
+				return errors.Trace(err)
+			}
 		}
 	} else {
 		// dry run mode, just record the metrics
@@ -754,62 +795,22 @@ func (w *MysqlWriter) prepareDMLs(events []*commonEvent.DMLEvent) (*preparedDMLs
 			startTs = append(startTs, event.StartTs)
 		}
 
-		// translateToInsert control the update and insert behavior.
-		translateToInsert := !w.cfg.SafeMode
-		translateToInsert = translateToInsert && event.CommitTs > event.ReplicatingTs
-		log.Debug("translate to insert",
-			zap.Bool("translateToInsert", translateToInsert),
-			zap.Uint64("firstRowCommitTs", event.CommitTs),
-			zap.Uint64("firstRowReplicatingTs", event.ReplicatingTs),
-			zap.Bool("safeMode", w.cfg.SafeMode))
-		for {
-			row, ok := event.GetNextRow()
-			if !ok {
-				break
-			}
-			var query string
-			var args []interface{}
-			var err error
-			// Update Event
-			if row.RowType == commonEvent.RowTypeUpdate {
-				query, args, err = buildUpdate(event.TableInfo, row)
-				if err != nil {
-					return nil, errors.Trace(err)
-				}
-				if query != "" {
-					sqls = append(sqls, query)
-					values = append(values, args)
-				}
-				continue
-			}
-
-			// Delete Event
-			if row.RowType == commonEvent.RowTypeDelete {
-				query, args, err = buildDelete(event.TableInfo, row)
-				if err != nil {
-					return nil, errors.Trace(err)
-				}
-				if query != "" {
-					sqls = append(sqls, query)
-					values = append(values, args)
-				}
-			}
-
-			// Insert Event
-			// It will be translated directly into a
-			// INSERT(not in safe mode)
-			// or REPLACE(in safe mode) SQL.
-			if row.RowType == commonEvent.RowTypeInsert {
-				query, args, err = buildInsert(event.TableInfo, row, translateToInsert)
-				if err != nil {
-					return nil, errors.Trace(err)
-				}
-				if query != "" {
-					sqls = append(sqls, query)
-					values = append(values, args)
-				}
-			}
+		translateToInsert := w.translateToInsert(event)
+		var (
+			eventSQLs   []string
+			eventValues [][]interface{}
+			err         error
+		)
+		if w.shouldGenBatchSQL(event) {
+			eventSQLs, eventValues, err = w.generateBatchSQLs(event, translateToInsert)
+		} else {
+			eventSQLs, eventValues, err = w.generateNormalSQLs(event, translateToInsert)
 		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sqls = append(sqls, eventSQLs...)
+		values = append(values, eventValues...)
 	}
 
 	return &preparedDMLs{
@@ -821,54 +822,199 @@ func (w *MysqlWriter) prepareDMLs(events []*commonEvent.DMLEvent) (*preparedDMLs
 	}, nil
 }
 
-func (w *MysqlWriter) execDMLWithMaxRetries(dmls *preparedDMLs) error {
-	if len(dmls.sqls) != len(dmls.values) {
-		return cerror.ErrUnexpected.FastGenByArgs(fmt.Sprintf("unexpected number of sqls and values, sqls is %s, values is %s", dmls.sqls, dmls.values))
+// translateToInsert control the update and insert behavior.
+func (w *MysqlWriter) translateToInsert(event *commonEvent.DMLEvent) bool {
+	translateToInsert := !w.cfg.SafeMode
+	translateToInsert = translateToInsert && event.CommitTs > event.ReplicatingTs
+	log.Debug("translate to insert",
+		zap.Bool("translateToInsert", translateToInsert),
+		zap.Uint64("firstRowCommitTs", event.CommitTs),
+		zap.Uint64("firstRowReplicatingTs", event.ReplicatingTs),
+		zap.Bool("safeMode", w.cfg.SafeMode))
+	return translateToInsert
+}
+
+// generateNormalSQLs generates one statement for each row of the event.
+func (w *MysqlWriter) generateNormalSQLs(event *commonEvent.DMLEvent, translateToInsert bool) ([]string, [][]interface{}, error) {
+	var (
+		sqls   []string
+		values [][]interface{}
+	)
+	for {
+		row, ok := event.GetNextRow()
+		if !ok {
+			break
+		}
+		var query string
+		var args []interface{}
+		var err error
+		// Update Event
+		if row.RowType == commonEvent.RowTypeUpdate {
+			query, args, err = buildUpdate(event.TableInfo, row)
+			if err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			if query != "" {
+				sqls = append(sqls, query)
+				values = append(values, args)
+			}
+			continue
+		}
+
+		// Delete Event
+		if row.RowType == commonEvent.RowTypeDelete {
+			query, args, err = buildDelete(event.TableInfo, row)
+			if err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			if query != "" {
+				sqls = append(sqls, query)
+				values = append(values, args)
+			}
+		}
+
+		// Insert Event
+		// It will be translated directly into a
+		// INSERT(not in safe mode)
+		// or REPLACE(in safe mode) SQL.
+		if row.RowType == commonEvent.RowTypeInsert {
+			query, args, err = buildInsert(event.TableInfo, row, translateToInsert)
+			if err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			if query != "" {
+				sqls = append(sqls, query)
+				values = append(values, args)
+			}
+		}
 	}
+	return sqls, values, nil
+}
 
-	// approximateSize is multiplied by 2 because in extreme circustumas, every
-	// byte in dmls can be escaped and adds one byte.
-	fallbackToSeqWay := dmls.approximateSize*2 > w.maxAllowedPacket
+// shouldGenBatchSQL determines whether the rows of the event can be merged into batch statements.
+// The batch statements locate rows by the handle key, so they are only used when
+//  1. the batch dml is enabled and the sink is not in safe mode,
+//  2. the event has more than one row,
+//  3. the table has a handle key and all the handle key columns are comparable exactly.
+func (w *MysqlWriter) shouldGenBatchSQL(event *commonEvent.DMLEvent) bool {
+	if !w.cfg.BatchDMLEnable || w.cfg.SafeMode || event.Len() <= 1 {
+		return false
+	}
+	hasHandleKey := false
+	for _, col := range event.TableInfo.Columns {
+		if col == nil || !event.TableInfo.ColumnsFlag[col.ID].IsHandleKey() {
+			continue
+		}
+		switch col.GetType() {
+		// the approximate values can't be used to locate rows in the IN clause.
+		case mysql.TypeFloat, mysql.TypeDouble, mysql.TypeJSON:
+			return false
+		}
+		hasHandleKey = true
+	}
+	return hasHandleKey
+}
 
-	writeTimeout, _ := time.ParseDuration(w.cfg.WriteTimeout)
-	writeTimeout += networkDriftDuration
+// generateBatchSQLs merges the rows of the event into batch statements.
+// The rows are grouped by type and executed in the order of delete, update and insert,
+// so the unique keys released by the deleted and updated rows can be used by the inserted rows.
+func (w *MysqlWriter) generateBatchSQLs(event *commonEvent.DMLEvent, translateToInsert bool) ([]string, [][]interface{}, error) {
+	var deleteRows, updateRows, insertRows []commonEvent.RowChange
+	for {
+		row, ok := event.GetNextRow()
+		if !ok {
+			break
+		}
+		switch row.RowType {
+		case commonEvent.RowTypeDelete:
+			deleteRows = append(deleteRows, row)
+		case commonEvent.RowTypeUpdate:
+			updateRows = append(updateRows, row)
+		case commonEvent.RowTypeInsert:
+			insertRows = append(insertRows, row)
+		}
+	}
 
-	tryExec := func() (int, int64, error) {
-		tx, err := w.db.BeginTx(w.ctx, nil)
-		if err != nil {
-			return 0, 0, errors.Trace(err)
+	var (
+		sqls   []string
+		values [][]interface{}
+	)
+	appendSQL := func(query string, args []interface{}) {
+		if query != "" {
+			sqls = append(sqls, query)
+			values = append(values, args)
 		}
+	}
 
-		// Set session variables first and then execute the transaction.
-		// we try to set write source for each txn,
-		// so we can use it to trace the data source
-		if err = SetWriteSource(w.cfg, tx); err != nil {
-			log.Error("Failed to set write source", zap.Error(err))
-			if rbErr := tx.Rollback(); rbErr != nil {
-				if errors.Cause(rbErr) != context.Canceled {
-					log.Warn("failed to rollback txn", zap.Error(rbErr))
-				}
-			}
-			return 0, 0, err
+	for _, rows := range splitRows(deleteRows, w.cfg.MaxTxnRow) {
+		query, args, err := buildBatchDelete(event.TableInfo, rows)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
 		}
+		appendSQL(query, args)
+	}
 
-		if !fallbackToSeqWay {
-			err = w.multiStmtExecute(dmls, tx, writeTimeout)
+	// The multiple rows update statement is only efficient in TiDB,
+	// and it is disabled when the rows are too large.
+	if len(updateRows) > 1 && w.cfg.IsTiDB &&
+		event.GetRowsSize()/int64(event.Len()) < int64(w.cfg.MaxMultiUpdateRowSize) {
+		for _, rows := range splitRows(updateRows, w.cfg.MaxMultiUpdateRowCount) {
+			query, args, err := buildBatchUpdate(event.TableInfo, rows)
 			if err != nil {
-				fallbackToSeqWay = true
-				return 0, 0, err
+				return nil, nil, errors.Trace(err)
 			}
-		} else {
-			err = w.sequenceExecute(dmls, tx, writeTimeout)
+			appendSQL(query, args)
+		}
+	} else {
+		for _, row := range updateRows {
+			query, args, err := buildUpdate(event.TableInfo, row)
 			if err != nil {
-				return 0, 0, err
+				return nil, nil, errors.Trace(err)
 			}
+			appendSQL(query, args)
+		}
+	}
+
+	for _, rows := range splitRows(insertRows, w.cfg.MaxTxnRow) {
+		query, args, err := buildBatchInsert(event.TableInfo, rows, translateToInsert)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
 		}
+		appendSQL(query, args)
+	}
+	return sqls, values, nil
+}
+
+// splitRows splits the rows into groups with at most limit rows,
+// a non-positive limit means no limit.
+func splitRows(rows []commonEvent.RowChange, limit int) [][]commonEvent.RowChange {
+	if len(rows) == 0 {
+		return nil
+	}
+	if limit <= 0 {
+		return [][]commonEvent.RowChange{rows}
+	}
+	groups := make([][]commonEvent.RowChange, 0, (len(rows)+limit-1)/limit)
+	for len(rows) > limit {
+		groups = append(groups, rows[:limit])
+		rows = rows[limit:]
+	}
+	return append(groups, rows)
+}
 
-		if err = tx.Commit(); err != nil {
+func (w *MysqlWriter) execDMLWithMaxRetries(dmls *preparedDMLs) error {
+	if len(dmls.sqls) != len(dmls.values) {
+		return cerror.ErrUnexpected.FastGenByArgs(fmt.Sprintf("unexpected number of sqls and values, sqls is %s, values is %s", dmls.sqls, dmls.values))
+	}
+
+	// approximateSize is multiplied by 2 because in extreme circustumas, every
+	// byte in dmls can be escaped and adds one byte.
+	fallbackToSeqWay := dmls.approximateSize*2 > w.maxAllowedPacket
+
+	tryExec := func() (int, int64, error) {
+		if err := w.execDMLs(dmls, &fallbackToSeqWay); err != nil {
 			return 0, 0, err
 		}
-		log.Debug("Exec Rows succeeded")
 		return dmls.rowCount, dmls.approximateSize, nil
 	}
 	return retry.Do(w.ctx, func() error {
@@ -882,6 +1028,52 @@ func (w *MysqlWriter) execDMLWithMaxRetries(dmls *preparedDMLs) error {
 		retry.WithMaxTries(w.cfg.DMLMaxRetry))
 }
 
+// execDMLs executes the dmls in a transaction.
+// If the multi statements execution fails, sequenceWay is set to true,
+// so the next try executes the statements one by one.
+func (w *MysqlWriter) execDMLs(dmls *preparedDMLs, sequenceWay *bool) error {
+	writeTimeout, _ := time.ParseDuration(w.cfg.WriteTimeout)
+	writeTimeout += networkDriftDuration
+
+	tx, err := w.db.BeginTx(w.ctx, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Set session variables first and then execute the transaction.
+	// we try to set write source for each txn,
+	// so we can use it to trace the data source
+	if err = SetWriteSource(w.cfg, tx); err != nil {
+		log.Error("Failed to set write source", zap.Error(err))
+		if rbErr := tx.Rollback(); rbErr != nil {
+			if errors.Cause(rbErr) != context.Canceled {
+				log.Warn("failed to rollback txn", zap.Error(rbErr))
+			}
+		}
+		return err
+	}
+
+	if !*sequenceWay {
+		err = w.multiStmtExecute(dmls, tx, writeTimeout)
+		if err != nil {
+			// the multi statements may be not supported, fallback to the sequence way.
+			*sequenceWay = true
+			return err
+		}
+	} else {
+		err = w.sequenceExecute(dmls, tx, writeTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	log.Debug("Exec Rows succeeded")
+	return nil
+}
+
 func (w *MysqlWriter) sequenceExecute(
 	dmls *preparedDMLs, tx *sql.Tx, writeTimeout time.Duration,
 ) error {
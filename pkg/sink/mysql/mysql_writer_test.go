@@ -3,10 +3,15 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
 	"github.com/pingcap/ticdc/pkg/common"
 	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
 	"github.com/pingcap/ticdc/pkg/metrics"
@@ -68,6 +73,112 @@ func TestMysqlWriter_FlushDML(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMysqlWriter_FlushBatchDML(t *testing.T) {
+	writer, db, mock := newTestMysqlWriter(t)
+	defer db.Close()
+	writer.cfg.BatchDMLEnable = true
+	writer.cfg.IsTiDB = true
+	writer.cfg.MaxTxnRow = 2
+	writer.cfg.MaxMultiUpdateRowCount = 2
+	writer.cfg.MaxMultiUpdateRowSize = 1024
+
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32));")
+	require.NotNil(t, job)
+
+	// case 1: the inserted rows are merged into multiple values and split by the max txn row.
+	insertEvent := helper.DML2Event("test", "t",
+		"insert into t values (1, 'a')", "insert into t values (2, 'b')", "insert into t values (3, 'c')")
+	insertEvent.CommitTs = 2
+	insertEvent.ReplicatingTs = 1
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `test`.`t` (`id`,`name`) VALUES (?,?),(?,?);INSERT INTO `test`.`t` (`id`,`name`) VALUES (?,?)").
+		WithArgs(1, "a", 2, "b", 3, "c").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, writer.Flush([]*commonEvent.DMLEvent{insertEvent}, 0))
+
+	// case 2: the rows are merged into a multiple values REPLACE statement
+	// when the commitTs is not larger than the replicatingTs.
+	insertEvent.Rewind()
+	insertEvent.ReplicatingTs = 3
+	mock.ExpectBegin()
+	mock.ExpectExec("REPLACE INTO `test`.`t` (`id`,`name`) VALUES (?,?),(?,?);REPLACE INTO `test`.`t` (`id`,`name`) VALUES (?,?)").
+		WithArgs(1, "a", 2, "b", 3, "c").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, writer.Flush([]*commonEvent.DMLEvent{insertEvent}, 0))
+
+	// case 3: the deleted rows are located by the primary key in a single statement.
+	deleteEvent := helper.DML2Event("test", "t", "insert into t values (4, 'd')", "insert into t values (5, 'e')")
+	deleteEvent.RowTypes = []commonEvent.RowType{commonEvent.RowTypeDelete, commonEvent.RowTypeDelete}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `test`.`t` WHERE (`id`) IN ((?),(?))").
+		WithArgs(4, 5).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, writer.Flush([]*commonEvent.DMLEvent{deleteEvent}, 0))
+
+	// case 4: the updated rows are merged into a CASE WHEN statement.
+	updateEvent := helper.DML2Event("test", "t",
+		"insert into t values (6, 'f')", "update t set name = 'ff' where id = 6",
+		"insert into t values (7, 'g')", "update t set name = 'gg' where id = 7")
+	updateEvent.RowTypes = []commonEvent.RowType{
+		commonEvent.RowTypeUpdate, commonEvent.RowTypeUpdate,
+		commonEvent.RowTypeUpdate, commonEvent.RowTypeUpdate,
+	}
+	updateEvent.Length = 2
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `test`.`t` SET `id`=CASE WHEN `id` = ? THEN ? WHEN `id` = ? THEN ? END,"+
+		"`name`=CASE WHEN `id` = ? THEN ? WHEN `id` = ? THEN ? END WHERE (`id`) IN ((?),(?))").
+		WithArgs(6, 6, 7, 7, 6, "ff", 7, "gg", 6, 7).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, writer.Flush([]*commonEvent.DMLEvent{updateEvent}, 0))
+
+	// case 5: the multiple rows update is not used when the downstream is not TiDB.
+	updateEvent.Rewind()
+	writer.cfg.IsTiDB = false
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `test`.`t` SET `id` = ?,`name` = ? WHERE `id` = ? LIMIT 1;"+
+		"UPDATE `test`.`t` SET `id` = ?,`name` = ? WHERE `id` = ? LIMIT 1").
+		WithArgs(6, "ff", 6, 7, "gg", 7).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, writer.Flush([]*commonEvent.DMLEvent{updateEvent}, 0))
+
+	// case 6: fall back to one statement per row in safe mode.
+	insertEvent.Rewind()
+	writer.cfg.SafeMode = true
+	mock.ExpectBegin()
+	mock.ExpectExec("REPLACE INTO `test`.`t` (`id`,`name`) VALUES (?,?);REPLACE INTO `test`.`t` (`id`,`name`) VALUES (?,?);"+
+		"REPLACE INTO `test`.`t` (`id`,`name`) VALUES (?,?)").
+		WithArgs(1, "a", 2, "b", 3, "c").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, writer.Flush([]*commonEvent.DMLEvent{insertEvent}, 0))
+
+	// case 7: fall back to one statement per row when the primary key is a float column.
+	writer.cfg.SafeMode = false
+	job = helper.DDL2Job("create table t2 (id double primary key, name varchar(32));")
+	require.NotNil(t, job)
+	floatEvent := helper.DML2Event("test", "t2", "insert into t2 values (1.5, 'a')", "insert into t2 values (2.5, 'b')")
+	floatEvent.CommitTs = 2
+	floatEvent.ReplicatingTs = 1
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `test`.`t2` (`id`,`name`) VALUES (?,?);INSERT INTO `test`.`t2` (`id`,`name`) VALUES (?,?)").
+		WithArgs(1.5, "a", 2.5, "b").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, writer.Flush([]*commonEvent.DMLEvent{floatEvent}, 0))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 // Test flush ddl event
 // Ensure the ddl query will be write to the databases
 // and the ddl_ts_v1 table will be updated with the ddl_ts and table_id
@@ -156,6 +267,202 @@ func TestMysqlWriter_FlushDDLEvent(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// Test flush dml events with the dead letter enabled
+// Ensure the bad row is located and written to the dead letter storage,
+// and the other rows of the transaction are still applied
+func TestMysqlWriter_FlushDMLWithDeadLetter(t *testing.T) {
+	writer, db, mock := newTestMysqlWriter(t)
+	defer db.Close()
+	dir := t.TempDir()
+	writer.cfg.DMLMaxRetry = 1
+	writer.cfg.DeadLetterEnable = true
+	writer.cfg.DeadLetterStorageURI = "file://" + dir
+
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32));")
+	require.NotNil(t, job)
+
+	dmlEvent := helper.DML2Event("test", "t",
+		"insert into t values (1, 'a')", "insert into t values (2, 'b')", "insert into t values (3, 'c')")
+	dmlEvent.CommitTs = 2
+	dmlEvent.ReplicatingTs = 1
+	var flushed bool
+	dmlEvent.AddPostFlushFunc(func() { flushed = true })
+
+	execErr := &dmysql.MySQLError{Number: 1406, Message: "Data too long for column 'name' at row 1"}
+	insertSQL := "INSERT INTO `test`.`t` (`id`,`name`) VALUES (?,?)"
+	// the batch fails with a non-retryable error.
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL+";"+insertSQL+";"+insertSQL).
+		WithArgs(1, "a", 2, "b", 3, "c").
+		WillReturnError(execErr)
+	mock.ExpectRollback()
+	// all the rows are applied in a transaction again to locate the bad row.
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL).WithArgs(1, "a").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertSQL).WithArgs(2, "b").WillReturnError(execErr)
+	mock.ExpectRollback()
+	// the first half is applied.
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL).WithArgs(1, "a").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	// the second half is split again.
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL).WithArgs(2, "b").WillReturnError(execErr)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL).WithArgs(2, "b").WillReturnError(execErr)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL).WithArgs(3, "c").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, writer.Flush([]*commonEvent.DMLEvent{dmlEvent}, 0))
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.True(t, flushed)
+
+	// the bad row is written to the dead letter storage.
+	files, err := filepath.Glob(filepath.Join(dir, "test", "test", "2-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	letter := &DeadLetter{}
+	require.NoError(t, json.Unmarshal(data, letter))
+	require.Equal(t, "test", letter.Schema)
+	require.Equal(t, "t", letter.Table)
+	require.Equal(t, uint64(2), letter.CommitTs)
+	require.Equal(t, insertSQL, letter.SQL)
+	require.Equal(t, []interface{}{float64(2), "b"}, letter.Args)
+	require.Contains(t, letter.Error, "Data too long")
+	require.Equal(t, uint64(1), writer.statistics.DeadLetterRows())
+
+	// the error is returned if the dead letter is disabled.
+	dmlEvent.Rewind()
+	writer.cfg.DeadLetterEnable = false
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL+";"+insertSQL+";"+insertSQL).
+		WithArgs(1, "a", 2, "b", 3, "c").
+		WillReturnError(execErr)
+	mock.ExpectRollback()
+	require.Error(t, writer.Flush([]*commonEvent.DMLEvent{dmlEvent}, 0))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Test flush dml events with the dead letter enabled
+// Ensure the writer fails instead of skipping the rows
+// if locating the bad rows meets an error not caused by the rows
+func TestMysqlWriter_FlushDMLWithDeadLetterConnectionError(t *testing.T) {
+	writer, db, mock := newTestMysqlWriter(t)
+	defer db.Close()
+	dir := t.TempDir()
+	writer.cfg.DMLMaxRetry = 1
+	writer.cfg.DeadLetterEnable = true
+	writer.cfg.DeadLetterStorageURI = "file://" + dir
+
+	helper := commonEvent.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+	job := helper.DDL2Job("create table t (id int primary key, name varchar(32));")
+	require.NotNil(t, job)
+
+	dmlEvent := helper.DML2Event("test", "t",
+		"insert into t values (1, 'a')", "insert into t values (2, 'b')")
+	dmlEvent.CommitTs = 2
+	dmlEvent.ReplicatingTs = 1
+	var flushed bool
+	dmlEvent.AddPostFlushFunc(func() { flushed = true })
+
+	execErr := &dmysql.MySQLError{Number: 1406, Message: "Data too long for column 'name' at row 1"}
+	insertSQL := "INSERT INTO `test`.`t` (`id`,`name`) VALUES (?,?)"
+	// the batch fails with a data error.
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL+";"+insertSQL).
+		WithArgs(1, "a", 2, "b").
+		WillReturnError(execErr)
+	mock.ExpectRollback()
+	// the connection is lost when locating the bad row.
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL).WithArgs(1, "a").WillReturnError(dmysql.ErrInvalidConn)
+	mock.ExpectRollback()
+
+	err := writer.Flush([]*commonEvent.DMLEvent{dmlEvent}, 0)
+	require.ErrorIs(t, err, dmysql.ErrInvalidConn)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.False(t, flushed)
+
+	// no row is written to the dead letter storage.
+	files, err := filepath.Glob(filepath.Join(dir, "test", "test", "*.json"))
+	require.NoError(t, err)
+	require.Empty(t, files)
+	require.Equal(t, uint64(0), writer.statistics.DeadLetterRows())
+}
+
+func TestIsDeadLetterError(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		expected bool
+	}{
+		{&dmysql.MySQLError{Number: 1406, Message: "Data too long"}, true},
+		{&dmysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, true},
+		{&dmysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}, true},
+		{&dmysql.MySQLError{Number: 1366, Message: "Incorrect integer value"}, true},
+		{&dmysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, false},
+		{&dmysql.MySQLError{Number: 1045, Message: "Access denied"}, false},
+		{dmysql.ErrInvalidConn, false},
+		{context.DeadlineExceeded, false},
+		{errors.New("connection reset by peer"), false},
+	} {
+		require.Equal(t, tc.expected, isDeadLetterError(tc.err), tc.err.Error())
+	}
+}
+
+// Test exec dmls with the multi statements
+// Ensure only the failure of the multi statements falls back to the sequence way
+func TestMysqlWriter_ExecDMLsFallbackToSequenceWay(t *testing.T) {
+	writer, db, mock := newTestMysqlWriter(t)
+	defer db.Close()
+
+	insertSQL := "INSERT INTO `test`.`t` (`id`,`name`) VALUES (?,?)"
+	dmls := &preparedDMLs{
+		sqls:     []string{insertSQL, insertSQL},
+		values:   [][]interface{}{{1, "a"}, {2, "b"}},
+		rowCount: 2,
+	}
+
+	// the failure of the transaction does not change the way to execute.
+	sequenceWay := false
+	mock.ExpectBegin().WillReturnError(errors.New("begin failed"))
+	require.Error(t, writer.execDMLs(dmls, &sequenceWay))
+	require.False(t, sequenceWay)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL+";"+insertSQL).WithArgs(1, "a", 2, "b").
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
+	require.Error(t, writer.execDMLs(dmls, &sequenceWay))
+	require.False(t, sequenceWay)
+
+	// the failure of the multi statements falls back to the sequence way.
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL+";"+insertSQL).WithArgs(1, "a", 2, "b").
+		WillReturnError(errors.New("multi statements not supported"))
+	mock.ExpectRollback()
+	require.Error(t, writer.execDMLs(dmls, &sequenceWay))
+	require.True(t, sequenceWay)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insertSQL).WithArgs(1, "a").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertSQL).WithArgs(2, "b").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, writer.execDMLs(dmls, &sequenceWay))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestMysqlWriter_Flush_EmptyEvents(t *testing.T) {
 	writer, db, mock := newTestMysqlWriter(t)
 	defer db.Close()
@@ -184,3 +184,175 @@ func whereSlice(row *chunk.Row, tableInfo *common.TableInfo) ([]string, []interf
 	}
 	return colNames, args, nil
 }
+
+// buildBatchInsert builds a parametric INSERT or REPLACE statement for multiple rows as following
+// sql: `INSERT INTO `test`.`t` (`a`,`b`) VALUES (?,?),(?,?)`
+func buildBatchInsert(
+	tableInfo *common.TableInfo,
+	rows []commonEvent.RowChange,
+	translateToInsert bool,
+) (string, []interface{}, error) {
+	var builder strings.Builder
+	var preSQL string
+	if translateToInsert {
+		preSQL = tableInfo.GetPreInsertSQL()
+	} else {
+		preSQL = tableInfo.GetPreReplaceSQL()
+	}
+	if preSQL == "" {
+		log.Panic("PreInsertSQL should not be empty")
+	}
+	builder.WriteString(preSQL)
+
+	var placeHolder string
+	args := make([]interface{}, 0, len(rows)*len(tableInfo.Columns))
+	for i, row := range rows {
+		rowArgs, err := getArgs(&row.Row, tableInfo)
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		if len(rowArgs) == 0 {
+			return "", nil, nil
+		}
+		// The pre insert sql already contains the place holder of the first row.
+		if i == 0 {
+			placeHolder = "(" + strings.TrimSuffix(strings.Repeat("?,", len(rowArgs)), ",") + ")"
+		} else {
+			builder.WriteString(",")
+			builder.WriteString(placeHolder)
+		}
+		args = append(args, rowArgs...)
+	}
+	return builder.String(), args, nil
+}
+
+// buildBatchDelete builds a parametric DELETE statement for multiple rows as following
+// sql: `DELETE FROM `test`.`t` WHERE (`a`,`b`) IN ((?,?),(?,?))`
+// The where columns of all the rows must be not null.
+func buildBatchDelete(tableInfo *common.TableInfo, rows []commonEvent.RowChange) (string, []interface{}, error) {
+	var builder strings.Builder
+	builder.WriteString("DELETE FROM ")
+	builder.WriteString(tableInfo.TableName.QuoteString())
+	builder.WriteString(" WHERE ")
+
+	colNames, args, err := batchWhereSlice(tableInfo, rows)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	if len(args) == 0 {
+		return "", nil, nil
+	}
+	writeWhereIn(&builder, colNames, len(rows))
+	return builder.String(), args, nil
+}
+
+// buildBatchUpdate builds a parametric UPDATE statement for multiple rows as following
+// sql: `UPDATE `test`.`t` SET `a`=CASE WHEN `a` = ? THEN ? WHEN `a` = ? THEN ? END,
+// `b`=CASE WHEN `a` = ? THEN ? WHEN `a` = ? THEN ? END WHERE (`a`) IN ((?),(?))`
+// The where columns of all the rows must be not null.
+func buildBatchUpdate(tableInfo *common.TableInfo, rows []commonEvent.RowChange) (string, []interface{}, error) {
+	whereColNames, whereArgs, err := batchWhereSlice(tableInfo, rows)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	if len(whereArgs) == 0 {
+		return "", nil, nil
+	}
+	rowArgs := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
+		args, err := getArgs(&row.Row, tableInfo)
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		if len(args) == 0 {
+			return "", nil, nil
+		}
+		rowArgs = append(rowArgs, args)
+	}
+
+	// the condition to locate a single row, such as "`a` = ? AND `b` = ?"
+	var condBuilder strings.Builder
+	for i, colName := range whereColNames {
+		if i > 0 {
+			condBuilder.WriteString(" AND ")
+		}
+		condBuilder.WriteString(quotes.QuoteName(colName))
+		condBuilder.WriteString(" = ?")
+	}
+	cond := condBuilder.String()
+
+	var builder strings.Builder
+	builder.WriteString("UPDATE ")
+	builder.WriteString(tableInfo.TableName.QuoteString())
+	builder.WriteString(" SET ")
+
+	whereColCount := len(whereColNames)
+	args := make([]interface{}, 0, len(rows)*(len(rowArgs[0])*(whereColCount+1)+whereColCount))
+	colIdx := 0
+	for _, col := range tableInfo.Columns {
+		if col == nil || tableInfo.ColumnsFlag[col.ID].IsGeneratedColumn() {
+			continue
+		}
+		if colIdx > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(quotes.QuoteName(col.Name.O))
+		builder.WriteString("=CASE")
+		for i := range rows {
+			builder.WriteString(" WHEN ")
+			builder.WriteString(cond)
+			builder.WriteString(" THEN ?")
+			args = append(args, whereArgs[i*whereColCount:(i+1)*whereColCount]...)
+			args = append(args, rowArgs[i][colIdx])
+		}
+		builder.WriteString(" END")
+		colIdx++
+	}
+	builder.WriteString(" WHERE ")
+	writeWhereIn(&builder, whereColNames, len(rows))
+	args = append(args, whereArgs...)
+	return builder.String(), args, nil
+}
+
+// batchWhereSlice returns the where column names and the flattened where values
+// of the pre rows, the values of each row are placed next to each other.
+func batchWhereSlice(tableInfo *common.TableInfo, rows []commonEvent.RowChange) ([]string, []interface{}, error) {
+	var colNames []string
+	args := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		names, whereArgs, err := whereSlice(&row.PreRow, tableInfo)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		for i, arg := range whereArgs {
+			if arg == nil {
+				return nil, nil, errors.ErrUnexpected.GenWithStackByArgs(
+					"the where column " + names[i] + " of the batch dml should not be null")
+			}
+		}
+		colNames = names
+		args = append(args, whereArgs...)
+	}
+	return colNames, args, nil
+}
+
+// writeWhereIn writes the condition as following
+// `(`a`,`b`) IN ((?,?),(?,?))`
+func writeWhereIn(builder *strings.Builder, colNames []string, rowCount int) {
+	builder.WriteString("(")
+	for i, colName := range colNames {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(quotes.QuoteName(colName))
+	}
+	builder.WriteString(") IN (")
+	placeHolder := "(" + strings.TrimSuffix(strings.Repeat("?,", len(colNames)), ",") + ")"
+	for i := 0; i < rowCount; i++ {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(placeHolder)
+	}
+	builder.WriteString(")")
+}
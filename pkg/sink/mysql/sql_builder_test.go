@@ -370,3 +370,62 @@ func TestBuildUpdate(t *testing.T) {
 	require.Equal(t, expectedArgs, args)
 
 }
+
+func TestBuildBatchDML(t *testing.T) {
+	helper := event.NewEventTestHelper(t)
+	defer helper.Close()
+
+	helper.Tk().MustExec("use test")
+
+	// the table has a composite primary key
+	createTableSQL := "create table t (id int, name varchar(32), age int, primary key (id, name));"
+	job := helper.DDL2Job(createTableSQL)
+	require.NotNil(t, job)
+
+	event := helper.DML2Event("test", "t", "insert into t values (1, 'a', 10)", "insert into t values (2, 'b', 20)")
+	require.NotNil(t, event)
+	var rows []pevent.RowChange
+	for {
+		row, ok := event.GetNextRow()
+		if !ok {
+			break
+		}
+		rows = append(rows, row)
+	}
+	require.Len(t, rows, 2)
+
+	// case 1: batch insert
+	sql, args, err := buildBatchInsert(event.TableInfo, rows, true)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `test`.`t` (`id`,`name`,`age`) VALUES (?,?,?),(?,?,?)", sql)
+	require.Equal(t, []interface{}{int64(1), "a", int64(10), int64(2), "b", int64(20)}, args)
+
+	// case 2: batch delete
+	deleteRows := make([]pevent.RowChange, 0, len(rows))
+	for _, row := range rows {
+		deleteRows = append(deleteRows, pevent.RowChange{PreRow: row.Row, RowType: pevent.RowTypeDelete})
+	}
+	sql, args, err = buildBatchDelete(event.TableInfo, deleteRows)
+	require.NoError(t, err)
+	require.Equal(t, "DELETE FROM `test`.`t` WHERE (`id`,`name`) IN ((?,?),(?,?))", sql)
+	require.Equal(t, []interface{}{int64(1), "a", int64(2), "b"}, args)
+
+	// case 3: batch update, the pre row and the row are the same here
+	updateRows := make([]pevent.RowChange, 0, len(rows))
+	for _, row := range rows {
+		updateRows = append(updateRows, pevent.RowChange{PreRow: row.Row, Row: row.Row, RowType: pevent.RowTypeUpdate})
+	}
+	sql, args, err = buildBatchUpdate(event.TableInfo, updateRows)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `test`.`t` SET "+
+		"`id`=CASE WHEN `id` = ? AND `name` = ? THEN ? WHEN `id` = ? AND `name` = ? THEN ? END,"+
+		"`name`=CASE WHEN `id` = ? AND `name` = ? THEN ? WHEN `id` = ? AND `name` = ? THEN ? END,"+
+		"`age`=CASE WHEN `id` = ? AND `name` = ? THEN ? WHEN `id` = ? AND `name` = ? THEN ? END "+
+		"WHERE (`id`,`name`) IN ((?,?),(?,?))", sql)
+	require.Equal(t, []interface{}{
+		int64(1), "a", int64(1), int64(2), "b", int64(2),
+		int64(1), "a", "a", int64(2), "b", "b",
+		int64(1), "a", int64(10), int64(2), "b", int64(20),
+		int64(1), "a", int64(2), "b",
+	}, args)
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultConnectionTimeout is the default timeout for establishing a TCP connection, in seconds.
+	defaultConnectionTimeout = 5
+	// defaultOperationTimeout is the default timeout of producer-create, subscribe and unsubscribe operations, in seconds.
+	defaultOperationTimeout = 30
+	// defaultBatchingMaxSize is the default maximum number of messages in a batch.
+	defaultBatchingMaxSize = uint(1000)
+	// defaultBatchingMaxPublishDelay is the default delay of sending a batch, in milliseconds.
+	defaultBatchingMaxPublishDelay = 10
+	// defaultSendTimeout is the default timeout of sending a message, in seconds.
+	defaultSendTimeout = 30
+)
+
+func checkSinkURI(sinkURI *url.URL) error {
+	if sinkURI.Scheme == "" {
+		return fmt.Errorf("scheme is empty")
+	}
+	if sinkURI.Host == "" {
+		return fmt.Errorf("host is empty")
+	}
+	if sinkURI.Path == "" {
+		return fmt.Errorf("path is empty")
+	}
+	return nil
+}
+
+// NewPulsarConfig returns the pulsar config parsed from the sink URI,
+// the fields not set in pulsarConfig are filled with the default values.
+func NewPulsarConfig(sinkURI *url.URL, pulsarConfig *config.PulsarConfig) (*config.PulsarConfig, error) {
+	c := &config.PulsarConfig{
+		ConnectionTimeout:       config.NewTimeSec(defaultConnectionTimeout),
+		OperationTimeout:        config.NewTimeSec(defaultOperationTimeout),
+		BatchingMaxMessages:     toUint(defaultBatchingMaxSize),
+		BatchingMaxPublishDelay: config.NewTimeMill(defaultBatchingMaxPublishDelay),
+		SendTimeout:             config.NewTimeSec(defaultSendTimeout),
+	}
+	if err := checkSinkURI(sinkURI); err != nil {
+		return nil, err
+	}
+	if !sink.IsPulsarScheme(sinkURI.Scheme) {
+		return nil, fmt.Errorf("invalid pulsar scheme %s", sinkURI.Scheme)
+	}
+
+	brokerScheme := sinkURI.Scheme
+	switch brokerScheme {
+	case sink.PulsarHTTPScheme:
+		brokerScheme = "http"
+	case sink.PulsarHTTPSScheme:
+		brokerScheme = "https"
+	}
+	c.SinkURI = sinkURI
+	c.BrokerURL = brokerScheme + "://" + sinkURI.Host
+
+	if pulsarConfig == nil {
+		log.Debug("new pulsar config", zap.Any("config", c))
+		return c, nil
+	}
+
+	pulsarConfig.SinkURI = c.SinkURI
+	pulsarConfig.BrokerURL = c.BrokerURL
+
+	// merge the default config
+	if pulsarConfig.ConnectionTimeout == nil {
+		pulsarConfig.ConnectionTimeout = c.ConnectionTimeout
+	}
+	if pulsarConfig.OperationTimeout == nil {
+		pulsarConfig.OperationTimeout = c.OperationTimeout
+	}
+	if pulsarConfig.BatchingMaxMessages == nil {
+		pulsarConfig.BatchingMaxMessages = c.BatchingMaxMessages
+	}
+	if pulsarConfig.BatchingMaxPublishDelay == nil {
+		pulsarConfig.BatchingMaxPublishDelay = c.BatchingMaxPublishDelay
+	}
+	if pulsarConfig.SendTimeout == nil {
+		pulsarConfig.SendTimeout = c.SendTimeout
+	}
+
+	log.Debug("new pulsar config success", zap.Any("config", pulsarConfig))
+	return pulsarConfig, nil
+}
+
+func toUint(x uint) *uint {
+	return &x
+}
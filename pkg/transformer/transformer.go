@@ -0,0 +1,333 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transformer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	filter "github.com/pingcap/tidb/pkg/util/table-filter"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+const (
+	// addedVarcharLength is the length of the added varchar columns.
+	addedVarcharLength = 255
+	// hashLength is the length of the hex encoded sha256 hash written by the hash mask method.
+	hashLength = sha256.Size * 2
+)
+
+// Transformer transforms the rows of the DMLEvents by the transform rules of the changefeed,
+// such as renaming the columns, masking the values of the columns and adding the columns.
+// It's shared by all the dispatchers of a changefeed in the same node.
+type Transformer struct {
+	rules []*rule
+
+	mu sync.Mutex
+	// tables caches the transformed table info of the tables, keyed by the physical table id.
+	tables map[int64]*tableTransform
+}
+
+type rule struct {
+	tableF filter.Filter
+	config *config.TransformRule
+}
+
+// tableTransform is the transformation of a table with the specified table info version.
+type tableTransform struct {
+	version   uint64
+	tableInfo *common.TableInfo
+	// masks is the mask rule of every column of the origin table, nil if the column is not masked.
+	masks []*config.MaskColumnRule
+	// added are the added columns appended to the end of the rows.
+	added []*config.AddColumnRule
+}
+
+// NewTransformer creates a Transformer, it returns nil if there is no transform rule.
+// The first rule whose matcher matches the table is used to transform the rows of the table.
+func NewTransformer(rules []*config.TransformRule, caseSensitive bool) (*Transformer, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	if err := config.ValidateTransformRules(rules); err != nil {
+		return nil, err
+	}
+	t := &Transformer{
+		rules:  make([]*rule, 0, len(rules)),
+		tables: make(map[int64]*tableTransform),
+	}
+	for _, r := range rules {
+		tableF, err := filter.Parse(r.Matcher)
+		if err != nil {
+			return nil, errors.WrapError(errors.ErrFilterRuleInvalid, err, r.Matcher)
+		}
+		if !caseSensitive {
+			tableF = filter.CaseInsensitive(tableF)
+		}
+		t.rules = append(t.rules, &rule{tableF: tableF, config: r})
+	}
+	return t, nil
+}
+
+// Transform replaces the rows and the table info of the event with the transformed ones,
+// the rows of the event must be assembled before.
+func (t *Transformer) Transform(event *commonEvent.DMLEvent) error {
+	if event.Rows == nil || event.TableInfo == nil {
+		return nil
+	}
+	tt, err := t.getTableTransform(event.PhysicalTableID, event.TableInfo)
+	if err != nil {
+		return err
+	}
+	if tt == nil {
+		return nil
+	}
+
+	originFields := event.TableInfo.GetFieldSlice()
+	rows := chunk.NewChunkWithCapacity(tt.tableInfo.GetFieldSlice(), event.Rows.NumRows())
+	added := make([]types.Datum, 0, len(tt.added))
+	for _, col := range tt.added {
+		added = append(added, computeColumn(col, event))
+	}
+	for i := 0; i < event.Rows.NumRows(); i++ {
+		row := event.Rows.GetRow(i)
+		for idx, ft := range originFields {
+			d := row.GetDatum(idx, ft)
+			if mask := tt.masks[idx]; mask != nil {
+				d = maskDatum(mask, d)
+			}
+			rows.AppendDatum(idx, &d)
+		}
+		for idx := range added {
+			rows.AppendDatum(len(originFields)+idx, &added[idx])
+		}
+	}
+	event.Rows = rows
+	event.TableInfo = tt.tableInfo
+	return nil
+}
+
+// RemoveTable removes the cached transformation of the table,
+// it's called when a dispatcher of the table is removed, e.g. the table is dropped.
+func (t *Transformer) RemoveTable(tableID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.tables, tableID)
+}
+
+// getTableTransform returns the transformation of the table, nil if no rule matches the table.
+func (t *Transformer) getTableTransform(tableID int64, tableInfo *common.TableInfo) (*tableTransform, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tt, ok := t.tables[tableID]
+	if ok && (tt == nil || tt.version == tableInfo.GetVersion()) {
+		return tt, nil
+	}
+	var matched *config.TransformRule
+	for _, r := range t.rules {
+		if r.tableF.MatchTable(tableInfo.GetSchemaName(), tableInfo.GetTableName()) {
+			matched = r.config
+			break
+		}
+	}
+	if matched == nil {
+		t.tables[tableID] = nil
+		return nil, nil
+	}
+	tt, err := newTableTransform(matched, tableInfo)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("build table transformation",
+		zap.String("schema", tableInfo.GetSchemaName()),
+		zap.String("table", tableInfo.GetTableName()),
+		zap.Int64("tableID", tableID),
+		zap.Uint64("version", tt.version))
+	t.tables[tableID] = tt
+	return tt, nil
+}
+
+func newTableTransform(r *config.TransformRule, origin *common.TableInfo) (*tableTransform, error) {
+	info := origin.TableInfo.Clone()
+	tt := &tableTransform{
+		version: origin.GetVersion(),
+		masks:   make([]*config.MaskColumnRule, len(info.Columns)),
+		added:   r.AddColumns,
+	}
+
+	renamed := make(map[string]pmodel.CIStr, len(r.RenameColumns))
+	for from, to := range r.RenameColumns {
+		renamed[strings.ToLower(from)] = pmodel.NewCIStr(to)
+	}
+	maxColumnID := int64(0)
+	for idx, col := range info.Columns {
+		if col.ID > maxColumnID {
+			maxColumnID = col.ID
+		}
+		for _, mask := range r.MaskColumns {
+			if !strings.EqualFold(mask.Column, col.Name.O) {
+				continue
+			}
+			if err := checkMaskColumn(mask, origin, col); err != nil {
+				return nil, err
+			}
+			tt.masks[idx] = mask
+			switch mask.Method {
+			case config.MaskMethodNull:
+				col.DelFlag(mysql.NotNullFlag)
+			case config.MaskMethodHash:
+				// widen the column, or the hash doesn't fit in it.
+				if flen := col.GetFlen(); flen != types.UnspecifiedLength && flen < hashLength {
+					col.SetFlen(hashLength)
+				}
+			default:
+			}
+		}
+		if name, ok := renamed[col.Name.L]; ok {
+			col.Name = name
+		}
+	}
+	for _, index := range info.Indices {
+		for _, col := range index.Columns {
+			if name, ok := renamed[col.Name.L]; ok {
+				col.Name = name
+			}
+		}
+	}
+	for _, add := range r.AddColumns {
+		if info.FindPublicColumnByName(strings.ToLower(add.Name)) != nil {
+			return nil, errors.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				fmt.Sprintf("added column %s already exists in table %s",
+					add.Name, origin.TableName.QuoteString()))
+		}
+		maxColumnID++
+		info.Columns = append(info.Columns, newAddedColumn(add, maxColumnID, len(info.Columns)))
+	}
+	if info.MaxColumnID < maxColumnID {
+		info.MaxColumnID = maxColumnID
+	}
+
+	tt.tableInfo = common.WrapTableInfo(origin.SchemaID, origin.GetSchemaName(), info)
+	tt.tableInfo.InitPreSQLs()
+	return tt, nil
+}
+
+// checkMaskColumn checks whether the mask rule can be applied to the column,
+// the columns of the handle key can only be hashed, or the rows can't be identified by the sink.
+func checkMaskColumn(mask *config.MaskColumnRule, origin *common.TableInfo, col *model.ColumnInfo) error {
+	if mask.Method != config.MaskMethodNull && !types.IsString(col.GetType()) {
+		return errors.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("mask method %s is not supported by column %s of table %s, the column is not a string",
+				mask.Method, col.Name.O, origin.TableName.QuoteString()))
+	}
+	if flag, ok := origin.ColumnsFlag[col.ID]; ok && flag.IsHandleKey() && mask.Method != config.MaskMethodHash {
+		return errors.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("mask method %s is not supported by handle key column %s of table %s",
+				mask.Method, col.Name.O, origin.TableName.QuoteString()))
+	}
+	return nil
+}
+
+func newAddedColumn(add *config.AddColumnRule, id int64, offset int) *model.ColumnInfo {
+	col := &model.ColumnInfo{
+		ID:     id,
+		Name:   pmodel.NewCIStr(add.Name),
+		Offset: offset,
+		State:  model.StatePublic,
+	}
+	switch add.Expr {
+	case config.ComputedColumnCommitTs:
+		col.SetType(mysql.TypeLonglong)
+		col.SetFlag(mysql.UnsignedFlag | mysql.NotNullFlag)
+		col.SetFlen(mysql.MaxIntWidth)
+		col.SetCharset("binary")
+		col.SetCollate("binary")
+	case config.ComputedColumnCommitTime:
+		col.SetType(mysql.TypeDatetime)
+		col.SetFlag(mysql.NotNullFlag | mysql.BinaryFlag)
+		col.SetDecimal(3)
+		col.SetCharset("binary")
+		col.SetCollate("binary")
+	default:
+		col.SetType(mysql.TypeVarchar)
+		col.SetFlen(addedVarcharLength)
+		col.SetCharset(mysql.UTF8MB4Charset)
+		col.SetCollate(mysql.UTF8MB4DefaultCollation)
+	}
+	return col
+}
+
+// computeColumn returns the value of the added column for the event.
+func computeColumn(add *config.AddColumnRule, event *commonEvent.DMLEvent) types.Datum {
+	switch add.Expr {
+	case config.ComputedColumnCommitTs:
+		return types.NewUintDatum(event.CommitTs)
+	case config.ComputedColumnCommitTime:
+		commitTime := types.FromGoTime(oracle.GetTimeFromTS(event.CommitTs).UTC().Truncate(time.Millisecond))
+		return types.NewTimeDatum(types.NewTime(commitTime, mysql.TypeDatetime, 3))
+	case config.ComputedColumnSchemaName:
+		return types.NewStringDatum(event.TableInfo.GetSchemaName())
+	case config.ComputedColumnTableName:
+		return types.NewStringDatum(event.TableInfo.GetTableName())
+	default:
+	}
+	return types.NewStringDatum(add.Value)
+}
+
+// maskDatum returns the masked value of the datum, the null value is not masked.
+func maskDatum(mask *config.MaskColumnRule, d types.Datum) types.Datum {
+	if d.IsNull() {
+		return d
+	}
+	switch mask.Method {
+	case config.MaskMethodNull:
+		return types.Datum{}
+	case config.MaskMethodHash:
+		sum := sha256.Sum256(d.GetBytes())
+		return types.NewStringDatum(hex.EncodeToString(sum[:]))
+	case config.MaskMethodMask:
+		return types.NewStringDatum(maskString(d.GetString(), mask.KeepPrefix, mask.KeepSuffix))
+	default:
+	}
+	return d
+}
+
+// maskString replaces the characters of the string with '*' except the kept prefix and suffix,
+// all the characters are replaced if the string is not longer than the kept characters.
+func maskString(s string, keepPrefix, keepSuffix int) string {
+	runes := []rune(s)
+	if len(runes) <= keepPrefix+keepSuffix {
+		return strings.Repeat("*", len(runes))
+	}
+	for i := keepPrefix; i < len(runes)-keepSuffix; i++ {
+		runes[i] = '*'
+	}
+	return string(runes)
+}
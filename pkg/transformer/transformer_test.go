@@ -0,0 +1,172 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transformer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/pingcap/ticdc/pkg/common"
+	commonEvent "github.com/pingcap/ticdc/pkg/common/event"
+	"github.com/pingcap/ticdc/pkg/config"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func newTestTableInfo(schema, table string) *common.TableInfo {
+	columns := []*common.Column{
+		{Name: "id", Type: mysql.TypeLonglong, Flag: common.PrimaryKeyFlag | common.HandleKeyFlag},
+		{Name: "email", Type: mysql.TypeVarchar, Flag: common.NullableFlag},
+		{Name: "phone", Type: mysql.TypeVarchar, Flag: common.NullableFlag},
+		{Name: "age", Type: mysql.TypeLong, Flag: common.NullableFlag},
+	}
+	return common.BuildTableInfo(schema, table, columns, [][]int{{0}})
+}
+
+func newTestEvent(tableInfo *common.TableInfo, commitTs uint64) *commonEvent.DMLEvent {
+	rows := chunk.NewChunkWithCapacity(tableInfo.GetFieldSlice(), 2)
+	// the pre row and the row of an update
+	rows.AppendInt64(0, 1)
+	rows.AppendString(1, "alice@example.com")
+	rows.AppendString(2, "13800001234")
+	rows.AppendInt64(3, 20)
+	rows.AppendInt64(0, 1)
+	rows.AppendNull(1)
+	rows.AppendString(2, "123")
+	rows.AppendInt64(3, 21)
+	return &commonEvent.DMLEvent{
+		PhysicalTableID: 100,
+		CommitTs:        commitTs,
+		Length:          1,
+		RowTypes:        []commonEvent.RowType{commonEvent.RowTypeUpdate, commonEvent.RowTypeUpdate},
+		Rows:            rows,
+		TableInfo:       tableInfo,
+	}
+}
+
+func TestTransform(t *testing.T) {
+	rules := []*config.TransformRule{
+		{
+			Matcher:       []string{"test.user"},
+			RenameColumns: map[string]string{"age": "user_age"},
+			MaskColumns: []*config.MaskColumnRule{
+				{Column: "email", Method: config.MaskMethodHash},
+				{Column: "phone", Method: config.MaskMethodMask, KeepPrefix: 3, KeepSuffix: 2},
+			},
+			AddColumns: []*config.AddColumnRule{
+				{Name: "source", Value: "cluster-1"},
+				{Name: "_commit_ts", Expr: config.ComputedColumnCommitTs},
+				{Name: "_commit_time", Expr: config.ComputedColumnCommitTime},
+			},
+		},
+	}
+	transformer, err := NewTransformer(rules, false)
+	require.NoError(t, err)
+
+	tableInfo := newTestTableInfo("test", "user")
+	tableInfo.Columns[1].SetFlen(32)
+	tableInfo.Columns[2].SetFlen(32)
+	commitTime := time.Date(2024, 10, 1, 8, 0, 0, 123000000, time.UTC)
+	commitTs := oracle.GoTimeToTS(commitTime)
+	event := newTestEvent(tableInfo, commitTs)
+	require.NoError(t, transformer.Transform(event))
+
+	fields := event.TableInfo.GetFieldSlice()
+	require.Len(t, fields, 7)
+	require.Equal(t, "user_age", event.TableInfo.Columns[3].Name.O)
+	require.Equal(t, "source", event.TableInfo.Columns[4].Name.O)
+	require.Equal(t, []string{"id"}, event.TableInfo.GetPrimaryKeyColumnNames())
+	// the hashed column is widened to hold the hash, the origin table info is not changed
+	require.Equal(t, hashLength, event.TableInfo.Columns[1].GetFlen())
+	require.Equal(t, 32, event.TableInfo.Columns[2].GetFlen())
+	require.Equal(t, 32, tableInfo.Columns[1].GetFlen())
+
+	sum := sha256.Sum256([]byte("alice@example.com"))
+	row := event.Rows.GetRow(0)
+	require.Equal(t, int64(1), row.GetInt64(0))
+	require.Equal(t, hex.EncodeToString(sum[:]), row.GetString(1))
+	require.Equal(t, "138******34", row.GetString(2))
+	require.Equal(t, int64(20), row.GetInt64(3))
+	require.Equal(t, "cluster-1", row.GetString(4))
+	require.Equal(t, commitTs, row.GetUint64(5))
+	goTime, err := row.GetTime(6).GoTime(time.UTC)
+	require.NoError(t, err)
+	require.True(t, commitTime.Equal(goTime))
+
+	row = event.Rows.GetRow(1)
+	require.True(t, row.IsNull(1))
+	require.Equal(t, "***", row.GetString(2))
+	require.Equal(t, int64(21), row.GetInt64(3))
+
+	// the transformation is cached by the table info version
+	cached := event.TableInfo
+	event = newTestEvent(tableInfo, commitTs)
+	require.NoError(t, transformer.Transform(event))
+	require.Same(t, cached, event.TableInfo)
+
+	// the transformation is rebuilt after the table is removed
+	transformer.RemoveTable(event.PhysicalTableID)
+	event = newTestEvent(tableInfo, commitTs)
+	require.NoError(t, transformer.Transform(event))
+	require.NotSame(t, cached, event.TableInfo)
+	require.Equal(t, cached.Columns[4].Name, event.TableInfo.Columns[4].Name)
+
+	// the table is not matched by any rule
+	otherInfo := newTestTableInfo("test", "other")
+	event = newTestEvent(otherInfo, commitTs)
+	event.PhysicalTableID = 101
+	require.NoError(t, transformer.Transform(event))
+	require.Same(t, otherInfo, event.TableInfo)
+}
+
+func TestTransformInvalidRule(t *testing.T) {
+	transformer, err := NewTransformer(nil, false)
+	require.NoError(t, err)
+	require.Nil(t, transformer)
+
+	_, err = NewTransformer([]*config.TransformRule{{
+		Matcher:     []string{"test.*"},
+		MaskColumns: []*config.MaskColumnRule{{Column: "email", Method: "unknown"}},
+	}}, false)
+	require.Error(t, err)
+
+	// the mask method is not supported by the non string column
+	transformer, err = NewTransformer([]*config.TransformRule{{
+		Matcher:     []string{"test.*"},
+		MaskColumns: []*config.MaskColumnRule{{Column: "age", Method: config.MaskMethodHash}},
+	}}, false)
+	require.NoError(t, err)
+	tableInfo := newTestTableInfo("test", "user")
+	require.Error(t, transformer.Transform(newTestEvent(tableInfo, 1)))
+
+	// the handle key column can't be set to null
+	transformer, err = NewTransformer([]*config.TransformRule{{
+		Matcher:     []string{"test.*"},
+		MaskColumns: []*config.MaskColumnRule{{Column: "id", Method: config.MaskMethodNull}},
+	}}, false)
+	require.NoError(t, err)
+	require.Error(t, transformer.Transform(newTestEvent(tableInfo, 1)))
+
+	// the added column exists in the table
+	transformer, err = NewTransformer([]*config.TransformRule{{
+		Matcher:    []string{"test.*"},
+		AddColumns: []*config.AddColumnRule{{Name: "email", Value: "v"}},
+	}}, false)
+	require.NoError(t, err)
+	require.Error(t, transformer.Transform(newTestEvent(tableInfo, 1)))
+}
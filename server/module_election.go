@@ -15,6 +15,7 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/pingcap/log"
@@ -22,6 +23,7 @@ import (
 	"github.com/pingcap/ticdc/coordinator/changefeed"
 	logcoordinator "github.com/pingcap/ticdc/logservice/coordinator"
 	"github.com/pingcap/ticdc/pkg/common"
+	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/etcd"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -29,6 +31,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
 
+	_ "github.com/go-sql-driver/mysql"
 	"github.com/pingcap/errors"
 	"go.etcd.io/etcd/client/v3/concurrency"
 	"go.etcd.io/etcd/server/v3/mvcc"
@@ -124,14 +127,19 @@ func (e *elector) campaignCoordinator(ctx context.Context) error {
 			zap.String("captureID", string(e.svr.info.ID)),
 			zap.Int64("coordinatorVersion", coordinatorVersion))
 
+		backend, closeBackend, err := e.newChangefeedBackend(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
 		co := coordinator.New(e.svr.info,
-			e.svr.pdClient, e.svr.PDClock, changefeed.NewEtcdBackend(e.svr.EtcdClient),
+			e.svr.pdClient, e.svr.PDClock, backend,
 			e.svr.EtcdClient.GetClusterID(),
 			coordinatorVersion, 10000, time.Minute)
 		e.svr.setCoordinator(co)
 		err = co.Run(ctx)
 		e.svr.coordinator.AsyncStop()
 		e.svr.setCoordinator(nil)
+		closeBackend()
 
 		if !cerror.ErrNotOwner.Equal(err) {
 			// if coordinator exits, resign the coordinator key,
@@ -169,6 +177,26 @@ func (e *elector) campaignCoordinator(ctx context.Context) error {
 	}
 }
 
+// newChangefeedBackend creates the changefeed meta store by the meta-store config of the server,
+// the returned function releases the resources of the backend after the coordinator exits.
+func (e *elector) newChangefeedBackend(ctx context.Context) (changefeed.Backend, func(), error) {
+	cfg := config.GetGlobalServerConfig().MetaStore
+	if cfg == nil || cfg.Type != config.MetaStoreTypeMySQL {
+		return changefeed.NewEtcdBackend(e.svr.EtcdClient), func() {}, nil
+	}
+	db, err := sql.Open("mysql", cfg.DSN)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	backend, err := changefeed.NewSQLBackend(ctx, db, e.svr.EtcdClient.GetClusterID())
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, errors.Trace(err)
+	}
+	log.Info("use mysql as the changefeed meta store")
+	return backend, func() { _ = db.Close() }, nil
+}
+
 func (e *elector) campaignLogCoordinator(ctx context.Context) error {
 	// Limit the frequency of elections to avoid putting too much pressure on the etcd server
 	rl := rate.NewLimiter(rate.Every(time.Second), 1 /* burst */)
@@ -28,6 +28,10 @@ type GrpcModule struct {
 	lis        net.Listener
 }
 
+// NewGrpcServer creates the gRPC server of the message center on the listener.
+// The listener is provided by the tcp server, which terminates TLS with the
+// server credential, and verifies the client certificates if mTLS or the
+// cert-allowed-cn is configured, so the gRPC server itself must not enable TLS again.
 func NewGrpcServer(lis net.Listener) common.SubModule {
 	option := []grpc.ServerOption{
 		grpc.MaxRecvMsgSize(256 * 1024 * 1024), // 256MB
@@ -15,8 +15,10 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -25,6 +27,7 @@ import (
 	"github.com/pingcap/ticdc/pkg/common"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	clogutil "github.com/pingcap/tiflow/pkg/logutil"
+	"github.com/soheilhy/cmux"
 	"go.uber.org/zap"
 	"golang.org/x/net/netutil"
 )
@@ -49,7 +52,8 @@ func NewHttpServer(c *server, lis net.Listener) common.SubModule {
 	// limit will wait in a queue and no new goroutines will be created until
 	// a connection is processed.
 	// We use it here to limit the max concurrent connections of statusServer.
-	lis = netutil.LimitListener(lis, maxHTTPConnection)
+	tlsLis := &tlsStateListener{Listener: lis}
+	lis = netutil.LimitListener(tlsLis, maxHTTPConnection)
 
 	logWritter := clogutil.InitGinLogWritter()
 	router := gin.New()
@@ -65,7 +69,7 @@ func NewHttpServer(c *server, lis net.Listener) common.SubModule {
 	return &HttpServer{
 		listener: lis,
 		server: &http.Server{
-			Handler:      router,
+			Handler:      tlsLis.withTLSState(router),
 			ReadTimeout:  httpConnectionTimeout,
 			WriteTimeout: httpConnectionTimeout,
 		},
@@ -88,3 +92,54 @@ func (s *HttpServer) Close(ctx context.Context) error {
 func (s *HttpServer) Name() string {
 	return "http-server"
 }
+
+// tlsStateListener records the tls connection state of the accepted connections.
+// The tls connections are wrapped by cmux and the limit listener, so net/http
+// can't set the Request.TLS, which is used to authenticate the client certificate.
+type tlsStateListener struct {
+	net.Listener
+	// states maps the remote address of the connection to its tls state.
+	states sync.Map
+}
+
+func (l *tlsStateListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	inner := conn
+	if muxConn, ok := inner.(*cmux.MuxConn); ok {
+		inner = muxConn.Conn
+	}
+	tlsConn, ok := inner.(*tls.Conn)
+	if !ok {
+		return conn, nil
+	}
+	state := tlsConn.ConnectionState()
+	addr := conn.RemoteAddr().String()
+	l.states.Store(addr, &state)
+	return &tlsStateConn{Conn: conn, release: func() { l.states.Delete(addr) }}, nil
+}
+
+// withTLSState sets the tls state of the connection to the request.
+func (l *tlsStateListener) withTLSState(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil {
+			if state, ok := l.states.Load(r.RemoteAddr); ok {
+				r.TLS = state.(*tls.ConnectionState)
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+type tlsStateConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (c *tlsStateConn) Close() error {
+	c.releaseOnce.Do(c.release)
+	return c.Conn.Close()
+}
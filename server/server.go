@@ -113,7 +113,10 @@ func (c *server) initialize(ctx context.Context) error {
 		return errors.Trace(err)
 	}
 
-	messageCenter := messaging.NewMessageCenter(ctx, c.info.ID, c.info.Epoch, config.NewDefaultMessageCenterConfig())
+	conf := config.GetGlobalServerConfig()
+	messageCenterConfig := config.NewDefaultMessageCenterConfig()
+	messageCenterConfig.Security = conf.Security
+	messageCenter := messaging.NewMessageCenter(ctx, c.info.ID, c.info.Epoch, messageCenterConfig)
 	appcontext.SetID(c.info.ID.String())
 	appcontext.SetService(appcontext.MessageCenter, messageCenter)
 
@@ -126,10 +129,9 @@ func (c *server) initialize(ctx context.Context) error {
 		appcontext.MessageCenter,
 		appcontext.GetService[messaging.MessageCenter](appcontext.MessageCenter).OnNodeChanges)
 
-	conf := config.GetGlobalServerConfig()
-	schemaStore := schemastore.New(ctx, conf.DataDir, c.pdClient, c.RegionCache, c.PDClock, c.KVStorage)
-	eventStore := eventstore.New(ctx, conf.DataDir, c.pdClient, c.RegionCache, c.PDClock, c.KVStorage)
-	eventService := eventservice.New(eventStore, schemaStore)
+	schemaStore := schemastore.New(ctx, conf.DataDir, conf.SchemaStore, c.pdClient, c.RegionCache, c.PDClock, c.KVStorage, conf.Security)
+	eventStore := eventstore.New(ctx, conf.DataDir, conf.EventStore, c.pdClient, c.RegionCache, c.PDClock, c.KVStorage, conf.Security)
+	eventService := eventservice.New(eventStore, schemaStore, conf.EventService)
 	c.subModules = []common.SubModule{
 		nodeManager,
 		schemaStore,
@@ -24,6 +24,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/ticdc/logservice/upstream"
 	"github.com/pingcap/ticdc/pkg/config"
 	"github.com/pingcap/ticdc/pkg/etcd"
 	"github.com/pingcap/ticdc/pkg/node"
@@ -117,6 +118,8 @@ func (c *server) prepare(ctx context.Context) error {
 		return errors.Trace(err)
 	}
 
+	// region cache health check uses the tls config in the tikv global config.
+	upstream.InitGlobalConfig(conf.Security)
 	c.RegionCache = tikv.NewRegionCache(c.pdClient)
 	c.PDClock, err = pdutil.NewClock(ctx, c.pdClient)
 	if err != nil {
@@ -175,6 +178,14 @@ func (c *server) initDir() error {
 	log.Info(fmt.Sprintf("%s is set as data-dir (%dGB available), sort-dir=%s. "+
 		"It is recommended that the disk for data-dir at least have %dGB available space",
 		conf.DataDir, diskInfo.Avail, conf.Sorter.SortDir, dataDirThreshold))
+	// The data of the event store already on disk is not counted in the available space,
+	// so only warn if the quota exceeds it.
+	if conf.EventStore.DiskQuota > diskInfo.Avail*1024*1024*1024 {
+		log.Warn("the disk quota of the event store exceeds the available space of data-dir",
+			zap.String("dataDir", conf.DataDir),
+			zap.Uint64("availableGB", diskInfo.Avail),
+			zap.Uint64("eventStoreDiskQuota", conf.EventStore.DiskQuota))
+	}
 
 	// Ensure sorter dir exists and read-writable.
 	_, err = checkDir(conf.Sorter.SortDir)
@@ -285,6 +285,10 @@ type ChangefeedSchedulerConfig struct {
 	RegionThreshold int `toml:"region_threshold" json:"region_threshold"`
 	// WriteKeyThreshold is the written keys threshold of splitting a table.
 	WriteKeyThreshold int `toml:"write_key_threshold" json:"write_key_threshold"`
+	// MergeRegionThreshold is the region count threshold of merging the spans of a table.
+	MergeRegionThreshold int `toml:"merge_region_threshold" json:"merge_region_threshold"`
+	// MergeWriteKeyThreshold is the written keys threshold of merging the spans of a table.
+	MergeWriteKeyThreshold int `toml:"merge_write_key_threshold" json:"merge_write_key_threshold"`
 }
 
 // IntegrityConfig is the config for integrity check
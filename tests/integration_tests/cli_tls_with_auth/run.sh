@@ -63,6 +63,8 @@ function run() {
    cert-allowed-cn = [\"fake_cn\"]
    client-user-required = true
    client-allowed-user = [\"ticdc\"]
+  [api-auth.user-roles]
+   ticdc = \"admin\"
   " >$WORK_DIR/server.toml
 	run_cdc_server \
 		--workdir $WORK_DIR \
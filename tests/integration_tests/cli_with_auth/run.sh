@@ -41,6 +41,8 @@ function run() {
   [security]
    client-user-required = true
    client-allowed-user = [\"ticdc\"]
+  [api-auth.user-roles]
+   ticdc = \"admin\"
   " >$WORK_DIR/server.toml
 
 	run_cdc_server --workdir $WORK_DIR --binary $CDC_BINARY --config "$WORK_DIR/server.toml"